@@ -23,8 +23,14 @@ func main() {
 	}
 
 	// Initialize logger
-	logger.InitLogger(cfg.Logger.Level, cfg.Logger.Format)
+	if err := logger.InitLogger(cfg.Logger); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Close()
 	logger.Info("application starting", "version", "1.0.0", "mode", cfg.Server.Mode)
+	if cfg.Server.Mode == "debug" {
+		logger.Info("effective configuration", "config", cfg.Redacted())
+	}
 
 	// Create and start server
 	server := app.NewServer(cfg)
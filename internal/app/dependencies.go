@@ -0,0 +1,267 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/config"
+	"github.com/emerarteaga/products-api/internal/domain/category"
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/handler"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/infra/metrics"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/emerarteaga/products-api/internal/repository"
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Repositories are the storage drivers the services are built on.
+// Any implementation of the domain interfaces can be plugged in, e.g. fakes in tests.
+type Repositories struct {
+	Products product.Repository
+	Orders   order.Repository
+	Catalog  order.Catalog // Product lookup used to duplicate orders
+
+	PriceHistory product.PriceHistoryRepository // nil disables price history
+
+	Categories        category.Repository
+	ProductCategories category.ProductCategories // Category listing fallback for sale points without categories
+}
+
+// Dependencies is the composition root: every component of the application, wired from config.
+// Components are built in dependency order by the build* functions below.
+type Dependencies struct {
+	Config       *config.Config
+	Repositories Repositories
+
+	Metrics         *metrics.Metrics  // nil when disabled
+	StatusFeed      *order.StatusFeed // nil when tracking streams are disabled
+	ProductService  product.ServiceAPI
+	CategoryService category.ServiceAPI
+	OrderService    order.ServiceAPI
+
+	ProductHandler   *handler.ProductHandler
+	CategoryHandler  *handler.CategoryHandler
+	OrderHandler     *handler.OrderHandler
+	DashboardHandler *handler.DashboardHandler // nil when disabled
+}
+
+// NewMongoRepositories builds the MongoDB repositories and creates their indexes
+func NewMongoRepositories(ctx context.Context, db *mongo.Database) Repositories {
+	productsCollection := db.Collection("products")
+	repos := Repositories{
+		Products: repository.NewProductMongoRepository(productsCollection),
+		Orders:   repository.NewOrderMongoRepository(db.Collection("orders"), db.Collection("orders_archive")),
+		Catalog:  repository.NewProductCatalog(productsCollection),
+
+		PriceHistory: repository.NewPriceHistoryMongoRepository(db.Collection("product_price_history")),
+
+		Categories:        repository.NewCategoryMongoRepository(db.Collection(repository.CategoriesCollection)),
+		ProductCategories: repository.NewProductCategoryNames(productsCollection),
+	}
+
+	createIndexes(ctx, "product", repos.Products)
+	createIndexes(ctx, "order", repos.Orders)
+	createIndexes(ctx, "price history", repos.PriceHistory)
+	createIndexes(ctx, "category", repos.Categories)
+	return repos
+}
+
+// createIndexes creates the indexes of repositories that manage their own; failures are only logged
+func createIndexes(ctx context.Context, kind string, repo any) {
+	indexed, ok := repo.(interface{ CreateIndexes(context.Context) error })
+	if !ok {
+		return
+	}
+	if err := indexed.CreateIndexes(ctx); err != nil {
+		logger.Warn(fmt.Sprintf("failed to create %s indexes", kind), "error", err)
+		return
+	}
+	logger.Info(fmt.Sprintf("%s indexes created successfully", kind))
+}
+
+// NewDependencies wires metrics, services and handlers on top of the given repositories
+func NewDependencies(cfg *config.Config, repos Repositories) (*Dependencies, error) {
+	d := &Dependencies{Config: cfg, Repositories: repos}
+
+	if cfg.Metrics.Enabled {
+		// Open order gauges are computed on scrape from the repository
+		d.Metrics = metrics.New(repos.Orders)
+	}
+
+	d.ProductService = buildProductService(cfg, repos)
+	d.CategoryService = buildCategoryService(cfg, repos)
+
+	if cfg.Orders.TrackingStreamMax > 0 {
+		d.StatusFeed = order.NewStatusFeed(cfg.Orders.TrackingStreamMax)
+	}
+
+	orderService, err := buildOrderService(cfg, repos, d.Metrics, d.StatusFeed)
+	if err != nil {
+		return nil, err
+	}
+	d.OrderService = orderService
+
+	d.ProductHandler = handler.NewProductHandler(d.ProductService)
+	d.CategoryHandler = handler.NewCategoryHandler(d.CategoryService)
+	d.OrderHandler = handler.NewOrderHandler(d.OrderService)
+
+	d.DashboardHandler, err = buildDashboardHandler(cfg, d.OrderService, d.ProductService)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Router builds the HTTP router over the handlers
+func (d *Dependencies) Router() (*gin.Engine, error) {
+	gin.SetMode(d.Config.Server.Mode)
+	if err := handler.RegisterValidators(); err != nil {
+		return nil, fmt.Errorf("failed to register validators: %w", err)
+	}
+
+	var metricsHandler http.Handler
+	if d.Metrics != nil {
+		metricsHandler = d.Metrics.Handler()
+	}
+	return SetupRouter(d.ProductHandler, d.CategoryHandler, d.OrderHandler, d.DashboardHandler, metricsHandler, d.Config), nil
+}
+
+// buildProductService configures the product service
+func buildProductService(cfg *config.Config, repos Repositories) product.ServiceAPI {
+	pagination := cfg.Pagination
+	opts := []product.Option{
+		product.WithCompanyPageLimits(util.PageLimits(pagination.CompanyProducts)),
+		product.WithSalePointPageLimits(util.PageLimits(pagination.SalePointProducts)),
+		product.WithPhotoHostAllowlist(util.NewHostAllowlist(cfg.Media.AllowedHosts)),
+		product.WithDocumentSizeLimit(documentSizeLimit(cfg, "product")),
+		product.WithOrderReferences(repos.Orders, time.Duration(cfg.Products.DeleteReferenceDays)*24*time.Hour),
+		product.WithOpenOrderCounter(repos.Orders),
+		product.WithPriceHistory(repos.PriceHistory),
+		product.WithImportLimits(product.ImportLimits{
+			MaxBytes: cfg.Products.ImportMaxBytes,
+			MaxRows:  cfg.Products.ImportMaxRows,
+		}),
+	}
+	if cfg.Products.ValidateCategories && repos.Categories != nil {
+		opts = append(opts, product.WithCategoryCatalog(repos.Categories))
+	}
+	return product.NewService(repos.Products, opts...)
+}
+
+// buildCategoryService configures the category service
+func buildCategoryService(cfg *config.Config, repos Repositories) category.ServiceAPI {
+	opts := []category.Option{
+		category.WithImageHostAllowlist(util.NewHostAllowlist(cfg.Media.AllowedHosts)),
+	}
+	if repos.ProductCategories != nil {
+		opts = append(opts, category.WithProductCategories(repos.ProductCategories))
+	}
+	return category.NewService(repos.Categories, opts...)
+}
+
+// buildOrderService configures the order service; events go to the recorder and status changes
+// to the tracking feed when they are not nil
+func buildOrderService(cfg *config.Config, repos Repositories, recorder *metrics.Metrics, feed *order.StatusFeed) (order.ServiceAPI, error) {
+	opts := []order.Option{
+		order.WithPageLimits(util.PageLimits(cfg.Pagination.Orders)),
+		order.WithReceiptHostAllowlist(util.NewHostAllowlist(cfg.Media.AllowedHosts)),
+		order.WithDefaultPhoneCountryCode(cfg.Orders.DefaultPhoneCountryCode),
+		order.WithOrderLimits(order.OrderLimits{
+			MaxLineQuantity: cfg.Orders.MaxLineQuantity,
+			MaxLines:        cfg.Orders.MaxLines,
+			MaxTotal:        cfg.Orders.MaxTotal,
+		}),
+		order.WithDocumentSizeLimit(documentSizeLimit(cfg, "order")),
+		order.WithTrackingURLTemplate(cfg.Orders.TrackingURLTemplate),
+		order.WithCustomerEditWindow(time.Duration(cfg.Orders.CustomerEditMinutes) * time.Minute),
+		order.WithCodeGenerator(order.CodeGenerator{
+			Format:      order.CodeFormat(cfg.Orders.CodeFormat),
+			ShortLength: cfg.Orders.ShortCodeLength,
+		}),
+		order.WithCurrency(money.Currency{Code: cfg.Currency.Code, MinorUnits: cfg.Currency.MinorUnits}),
+		order.WithPaymentRequiredForDispatch(cfg.Orders.RequirePaidDispatch),
+		order.WithMaxNotes(cfg.Orders.MaxNotes),
+		order.WithStatusKeptOnModify(cfg.Orders.KeepStatusOnModify),
+	}
+	filterLocation, err := time.LoadLocation(cfg.Orders.FilterTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order filter timezone: %w", err)
+	}
+	opts = append(opts, order.WithFilterLocation(filterLocation))
+	if recorder != nil {
+		opts = append(opts, order.WithEventRecorder(recorder))
+	}
+	if feed != nil {
+		opts = append(opts, order.WithStatusFeed(feed))
+	}
+	if repos.Catalog != nil {
+		opts = append(opts, order.WithCatalog(repos.Catalog))
+	}
+
+	stateMachine, err := order.ParseStateMachine(map[order.SaleType][]string{
+		order.SaleTypeDelivery: cfg.Transitions.Delivery,
+		order.SaleTypeOnSite:   cfg.Transitions.OnSite,
+	}, map[order.SaleType][]string{
+		order.SaleTypeDelivery: cfg.Transitions.DeliveryExtra,
+		order.SaleTypeOnSite:   cfg.Transitions.OnSiteExtra,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid order transitions: %w", err)
+	}
+	opts = append(opts, order.WithStateMachine(stateMachine))
+
+	autoAdvanceRules, err := order.ParseAutoAdvanceRules(map[order.SaleType][]string{
+		order.SaleTypeDelivery: cfg.AutoAdvance.Delivery,
+		order.SaleTypeOnSite:   cfg.AutoAdvance.OnSite,
+	}, stateMachine)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auto-advance rules: %w", err)
+	}
+	opts = append(opts, order.WithAutoAdvanceRules(autoAdvanceRules))
+
+	archive := cfg.Archive
+	opts = append(opts, order.WithArchivePolicy(order.ArchivePolicy{
+		MinAge:     time.Duration(archive.MinAgeDays) * 24 * time.Hour,
+		BatchSize:  archive.BatchSize,
+		MaxBatches: archive.MaxBatches,
+		Pause:      time.Duration(archive.BatchPauseMs) * time.Millisecond,
+	}))
+
+	return order.NewService(repos.Orders, opts...), nil
+}
+
+// buildDashboardHandler configures the admin dashboard, or returns nil when it is disabled
+func buildDashboardHandler(cfg *config.Config, orders order.ServiceAPI, products product.ServiceAPI) (*handler.DashboardHandler, error) {
+	dashboard := cfg.Dashboard
+	if !dashboard.Enabled {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(dashboard.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dashboard timezone: %w", err)
+	}
+	return handler.NewDashboardHandler(orders, products, handler.DashboardSettings{
+		Location:          loc,
+		LowStockThreshold: dashboard.LowStockThreshold,
+		LowStockLimit:     dashboard.LowStockLimit,
+		RefreshInterval:   time.Duration(dashboard.RefreshSeconds) * time.Second,
+	}), nil
+}
+
+// documentSizeLimit measures documents as BSON and warns when one passes half the soft limit
+func documentSizeLimit(cfg *config.Config, kind string) util.DocumentSizeLimit {
+	return util.DocumentSizeLimit{
+		MaxBytes: cfg.Database.MaxDocumentBytes,
+		Size:     repository.DocumentSize,
+		OnNearLimit: func(id string, size, maxBytes int) {
+			logger.Warn("document approaching size limit", "kind", kind, "id", id, "bytes", size, "limit", maxBytes)
+		},
+	}
+}
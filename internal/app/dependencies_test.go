@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/config"
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/gin-gonic/gin"
+)
+
+// memoryOrders stores orders in memory with the optimistic locking of the MongoDB repository.
+// It only implements what an order round trip needs; other methods panic through the nil interface.
+type memoryOrders struct {
+	order.Repository
+
+	mu     sync.Mutex
+	orders map[string]order.Order // By code
+}
+
+func (r *memoryOrders) Create(ctx context.Context, o *order.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[o.Code] = *o
+	return nil
+}
+
+func (r *memoryOrders) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.orders[code]
+	return ok, nil
+}
+
+func (r *memoryOrders) FindByCode(ctx context.Context, code string) (*order.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if o, ok := r.orders[code]; ok {
+		return &o, nil
+	}
+	return nil, order.ErrOrderNotFound
+}
+
+func (r *memoryOrders) FindArchivedByCode(ctx context.Context, code string) (*order.Order, error) {
+	return nil, order.ErrOrderNotFound
+}
+
+func (r *memoryOrders) Update(ctx context.Context, o *order.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[o.Code]
+	if !ok {
+		return order.ErrOrderNotFound
+	}
+	if stored.Version != o.Version {
+		return order.ErrOrderConflict
+	}
+	o.Version++
+	r.orders[o.Code] = *o
+	return nil
+}
+
+// newMemoryApp boots the application from config over in-memory repositories, with no MongoDB
+func newMemoryApp(t *testing.T) (*gin.Engine, *memoryOrders) {
+	t.Helper()
+	t.Setenv("ADMIN_TOKEN", testAdminToken)
+	t.Setenv("SERVER_MODE", gin.TestMode)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orders := &memoryOrders{orders: make(map[string]order.Order)}
+	deps, err := NewDependencies(cfg, Repositories{
+		Products: struct{ product.Repository }{},
+		Orders:   orders,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := deps.Router()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return router, orders
+}
+
+// serveApp sends an admin request with an optional JSON body and decodes the response envelope's data
+func serveApp(t *testing.T, router *gin.Engine, method, target, body string, data any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if data != nil && w.Code < 300 {
+		envelope := struct {
+			Data any `json:"data"`
+		}{Data: data}
+		if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("%s %s: %v: %s", method, target, err, w.Body.String())
+		}
+	}
+	return w.Code
+}
+
+func TestOrderRoundTripWithoutMongo(t *testing.T) {
+	router, orders := newMemoryApp(t)
+
+	type orderBody struct {
+		Code    string            `json:"code"`
+		Status  order.OrderStatus `json:"status"`
+		Total   int64             `json:"total"`
+		Version int64             `json:"version"`
+	}
+
+	var created orderBody
+	status := serveApp(t, router, http.MethodPost, "/api/v1/orders", `{
+		"company_id": "11111111-1111-4111-8111-111111111111",
+		"sale_point_id": "22222222-2222-4222-8222-222222222222",
+		"sale_type": "ON_SITE", "table_number": 4,
+		"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 2}]
+	}`, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("create status = %d", status)
+	}
+	if created.Code == "" || created.Status != order.StatusCreated || created.Total != 24000 {
+		t.Fatalf("created = %+v", created)
+	}
+	if _, ok := orders.orders[created.Code]; !ok {
+		t.Fatalf("order %s not stored in the memory repository", created.Code)
+	}
+
+	var fetched orderBody
+	if status := serveApp(t, router, http.MethodGet, "/api/v1/orders/"+created.Code, "", &fetched); status != http.StatusOK {
+		t.Fatalf("get status = %d", status)
+	}
+	if fetched.Code != created.Code || fetched.Status != created.Status || fetched.Total != created.Total || fetched.Version != 1 {
+		t.Errorf("fetched = %+v, want %+v at version 1", fetched, created)
+	}
+
+	var updated orderBody
+	status = serveApp(t, router, http.MethodPatch, "/api/v1/orders", `{"code": "`+created.Code+`", "status": "VERIFIED"}`, &updated)
+	if status != http.StatusOK {
+		t.Fatalf("update status = %d", status)
+	}
+	if updated.Status != order.StatusVerified || updated.Version != fetched.Version+1 {
+		t.Errorf("updated = %+v, want VERIFIED at version %d", updated, fetched.Version+1)
+	}
+
+	// The fetched version is stale now
+	stale := `{"code": "` + created.Code + `", "status": "IN_PROGRESS", "version": 1}`
+	if status := serveApp(t, router, http.MethodPatch, "/api/v1/orders", stale, nil); status != http.StatusConflict {
+		t.Errorf("stale update status = %d, want 409", status)
+	}
+
+	if status := serveApp(t, router, http.MethodGet, "/api/v1/orders/ORD-1700000000-a1b2c3d4", "", nil); status != http.StatusNotFound {
+		t.Errorf("unknown order status = %d, want 404", status)
+	}
+}
+
+func TestTrackingAcceptsCodesInAnyCase(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		typed  func(code string) string
+	}{
+		{"short code in lower case", "short", strings.ToLower},
+		{"legacy code in upper case", "legacy", strings.ToUpper},
+		{"legacy code as stored", "legacy", func(code string) string { return code }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ORDER_CODE_FORMAT", tt.format)
+			router, _ := newMemoryApp(t)
+
+			var created struct {
+				Code string `json:"code"`
+			}
+			status := serveApp(t, router, http.MethodPost, "/api/v1/orders", `{
+				"company_id": "11111111-1111-4111-8111-111111111111",
+				"sale_point_id": "22222222-2222-4222-8222-222222222222",
+				"sale_type": "ON_SITE", "table_number": 4,
+				"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 1}]
+			}`, &created)
+			if status != http.StatusCreated {
+				t.Fatalf("create status = %d", status)
+			}
+
+			var tracked struct {
+				Code string `json:"code"`
+			}
+			typed := tt.typed(created.Code)
+			if status := serveApp(t, router, http.MethodGet, "/api/v1/orders/track/"+typed, "", &tracked); status != http.StatusOK {
+				t.Fatalf("tracking %q status = %d", typed, status)
+			}
+			if tracked.Code != created.Code {
+				t.Errorf("tracked %s, want %s", tracked.Code, created.Code)
+			}
+		})
+	}
+}
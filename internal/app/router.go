@@ -1,67 +1,184 @@
 package app
 
 import (
+	"net/http"
+	"regexp"
+	"time"
+
 	"github.com/emerarteaga/products-api/internal/config"
 	"github.com/emerarteaga/products-api/internal/handler"
 	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(productHandler *handler.ProductHandler, orderHandler *handler.OrderHandler, cfg *config.Config) *gin.Engine {
+func SetupRouter(productHandler *handler.ProductHandler, categoryHandler *handler.CategoryHandler, orderHandler *handler.OrderHandler, dashboardHandler *handler.DashboardHandler, metricsHandler http.Handler, cfg *config.Config) *gin.Engine {
 	router := gin.New()
 	router.Use(customhttp.Recovery())
 	router.Use(customhttp.Logger())
+	router.Use(customhttp.Abandoned())
 	router.Use(customhttp.CORS(cfg.CORS))
 
+	// Requests carrying the admin token are marked; admin routes require it and staff-only flags
+	// (override, hard, include_deleted, include_unavailable) are ignored without it
+	if cfg.Admin.Token == "" {
+		logger.Warn("ADMIN_TOKEN is not set: admin endpoints will reject every request")
+	}
+	router.Use(customhttp.Authenticate(cfg.Admin.Token))
+
+	// Read-only mode rejects writes during maintenance; the toggle itself and reads sent as POST stay open
+	readOnly := customhttp.NewReadOnlyMode(cfg.Maintenance.ReadOnly)
+	maintenanceHandler := handler.NewMaintenanceHandler(readOnly)
+	router.Use(customhttp.ReadOnly(readOnly, cfg.Maintenance.RetryAfterSeconds, "/api/v1/admin/read-only", "/api/v1/products/batch"))
+
+	// Let clients read their own writes when reads go to secondaries
+	router.Use(customhttp.ReadAfterWrite(time.Duration(cfg.Database.ReadAfterWriteSeconds) * time.Second))
+
 	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok", "message": "Products API is running"})
+		c.JSON(200, gin.H{"status": "ok", "message": "Products API is running", "read_only": readOnly.Enabled()})
 	})
 
+	// Prometheus metrics (nil when disabled)
+	if metricsHandler != nil {
+		router.GET(cfg.Metrics.Path, gin.WrapH(metricsHandler))
+	}
+
+	// Malformed path IDs are rejected before reaching the database
+	productID := customhttp.ValidateParam("id", regexp.MustCompile(cfg.IDFormats.ProductID))
+	categoryID := customhttp.ValidateParam("id", regexp.MustCompile(cfg.IDFormats.CategoryID))
+	companyID := customhttp.ValidateParam("company_id", regexp.MustCompile(cfg.IDFormats.TenantID))
+	salePointID := customhttp.ValidateParam("sale_point_id", regexp.MustCompile(cfg.IDFormats.TenantID))
+	orderID := customhttp.ValidateParam("id", regexp.MustCompile(cfg.IDFormats.OrderID))
+	orderCode := customhttp.ValidateParam("code", regexp.MustCompile(cfg.IDFormats.OrderCode))
+
 	v1 := router.Group("/api/v1")
 	{
+		// API settings (currency of amounts)
+		v1.GET("/info", orderHandler.GetInfo)
+
 		// Product CRUD operations
 		products := v1.Group("/products")
 		{
 			products.POST("", productHandler.Create)
-			products.GET("/:id", productHandler.GetByID)
-			products.PUT("/:id", productHandler.Update)
-			products.DELETE("/:id", productHandler.Delete)
+			products.POST("/batch", productHandler.GetByIDs)
+			products.POST("/bulk-delete", productHandler.BulkDelete)
+			products.POST("/bulk/availability", productHandler.BulkSetAvailability)
+			products.POST("/reorder", productHandler.ReorderProducts)
+			products.POST("/copy", productHandler.CopyProducts)
+			products.POST("/import", productHandler.ImportProducts)
+			products.GET("/export", productHandler.ExportProducts)
+			products.GET("/:id", productID, productHandler.GetByID)
+			products.GET("/sku/:sku", productHandler.GetBySKU)
+			products.GET("/barcode/:barcode", productHandler.GetByBarcode)
+			products.PUT("/:id", productID, productHandler.Update)
+			products.PATCH("/:id/stock", productID, productHandler.AdjustStock)
+			products.DELETE("/:id", productID, productHandler.Delete)
+			products.POST("/:id/restore", productID, productHandler.Restore)
+			products.GET("/:id/price-history", productID, productHandler.GetPriceHistory)
 
 			// List products by company or sale point
-			products.GET("/company/:company_id", productHandler.GetByCompanyID)
-			products.GET("/sale-point/:sale_point_id", productHandler.GetBySalePointID)
+			products.GET("/company/:company_id", companyID, productHandler.GetByCompanyID)
+			products.GET("/company/:company_id/summary", companyID, productHandler.GetCompanySummary)
+			products.GET("/sale-point/:sale_point_id", salePointID, productHandler.GetBySalePointID)
+			products.GET("/sale-point/:sale_point_id/menu", salePointID, productHandler.GetMenu)
 		}
 
 		// Categories endpoints
 		categories := v1.Group("/categories")
 		{
-			categories.GET("/company/:company_id", productHandler.GetCategoriesByCompanyID)
-			categories.GET("/sale-point/:sale_point_id", productHandler.GetCategoriesBySalePointID)
+			// Category documents with display order; the listing falls back to product categories
+			categories.POST("", categoryHandler.Create)
+			categories.GET("", categoryHandler.List)
+			categories.GET("/:id", categoryID, categoryHandler.GetByID)
+			categories.PUT("/:id", categoryID, categoryHandler.Update)
+			categories.DELETE("/:id", categoryID, categoryHandler.Delete)
+
+			// Distinct categories of the products
+			categories.GET("/company/:company_id", companyID, productHandler.GetCategoriesByCompanyID)
+			categories.GET("/sale-point/:sale_point_id", salePointID, productHandler.GetCategoriesBySalePointID)
+			categories.POST("/rename", productHandler.RenameCategory)
 		}
 
+		// Tags endpoints
+		v1.GET("/tags/company/:company_id", companyID, productHandler.GetTagsByCompanyID)
+
 		// Order endpoints
 		orders := v1.Group("/orders")
 		{
 			// STAGE 1: Create order
 			orders.POST("", orderHandler.Create)
+			orders.POST("/validate", orderHandler.Validate)
 
 			// STAGE 2: Public tracking (no auth required)
-			orders.GET("/track/:code", orderHandler.Track)
+			orders.GET("/track/:code", orderCode, orderHandler.Track)
+			orders.GET("/track/:code/stream", orderCode, orderHandler.TrackStream)
+			orders.PATCH("/track/:code", orderCode, orderHandler.CustomerEdit)
 
 			// STAGE 3: Partial update (PATCH - no products)
 			orders.PATCH("", orderHandler.PartialUpdate)
+			orders.POST("/batch/status", orderHandler.BatchUpdateStatus)
 
 			// STAGE 4: Modify order (PUT - products allowed)
 			orders.PUT("", orderHandler.Modify)
 
 			// STAGE 5: List orders with filters
 			orders.GET("", orderHandler.GetAll)
+			orders.GET("/export", orderHandler.ExportOrders)
 
 			// STAGE 5: Get metrics and analytics
 			orders.GET("/metrics", orderHandler.GetMetrics)
+			orders.GET("/metrics/export", orderHandler.ExportMetrics)
+			orders.GET("/metrics/products", orderHandler.GetProductSales)
+
+			// Order size guards, for client-side validation
+			orders.GET("/limits", orderHandler.GetLimits)
+
+			// Effective status state machine per sale type, so clients render the right actions
+			orders.GET("/statuses", orderHandler.GetStatuses)
+
+			// Admin free text search (rate limited, it's expensive)
+			orders.GET("/search", customhttp.RequireAdmin(), customhttp.RateLimit(cfg.RateLimit.SearchPerMinute, time.Minute), orderHandler.Search)
+
+			// Past orders of a returning customer (admin)
+			orders.GET("/customer/:identification", customhttp.RequireAdmin(), customhttp.ValidateParam("identification", nil), orderHandler.GetCustomerHistory)
+
+			// Get order by ID, for internal tools that store the UUID
+			orders.GET("/id/:id", orderID, orderHandler.GetByID)
 
 			// Get order by code (admin/internal)
-			orders.GET("/:code", orderHandler.GetByCode)
+			orders.GET("/:code", orderCode, orderHandler.GetByCode)
+			orders.GET("/:code/qr", orderCode, orderHandler.GetQR)
+			orders.GET("/:code/receipt", orderCode, orderHandler.GetReceipt)
+
+			// Repeat an order at current catalog prices ("order again")
+			orders.POST("/:code/duplicate", orderCode, orderHandler.Duplicate)
+
+			// Mark the payment as reviewed, e.g. after checking a transfer receipt
+			orders.POST("/:code/payment/confirm", customhttp.RequireAdmin(), orderCode, orderHandler.ConfirmPayment)
+
+			// Append a staff note ("customer called", "no onions confirmed")
+			orders.POST("/:code/notes", orderCode, orderHandler.AddNote)
+		}
+
+		// Report endpoints
+		reports := v1.Group("/reports")
+		{
+			// End-of-day (Z) report for one local calendar day
+			reports.GET("/z", orderHandler.GetZReport)
+		}
+
+		// Admin endpoints
+		admin := v1.Group("/admin", customhttp.RequireAdmin())
+		{
+			admin.POST("/orders/recalculate-totals", orderHandler.RecalculateTotals)
+			admin.POST("/orders/archive", orderHandler.Archive)
+			admin.POST("/read-only", maintenanceHandler.SetReadOnly)
+
+			// Operational dashboard (nil when disabled)
+			if dashboardHandler != nil {
+				admin.GET("/dashboard", dashboardHandler.Page)
+				admin.GET("/dashboard/data", dashboardHandler.Data)
+			}
 		}
 	}
 
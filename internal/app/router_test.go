@@ -0,0 +1,239 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/config"
+	"github.com/emerarteaga/products-api/internal/domain/category"
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/handler"
+	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/gin-gonic/gin"
+)
+
+const testAdminToken = "router-test-admin-token"
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	// Access logs are info records; only errors would reach stderr
+	if err := logger.InitLogger(config.LoggerConfig{Level: "error", Format: "text", Outputs: []string{logger.OutputStderr}}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestRouter wires the production router over mocks that record the ID they were asked for
+func newTestRouter(t *testing.T, env map[string]string) (*gin.Engine, *string) {
+	t.Helper()
+	t.Setenv("ADMIN_TOKEN", testAdminToken)
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reached string
+	products := &mocks.ProductService{
+		GetByIDFunc: func(_ context.Context, id string) (*product.Product, error) {
+			reached = id
+			return nil, product.ErrProductNotFound
+		},
+		GetByCompanyIDFunc: func(_ context.Context, companyID string, _ product.ProductFilters) ([]*product.Product, int64, error) {
+			reached = companyID
+			return nil, 0, nil
+		},
+		GetBySalePointIDFunc: func(_ context.Context, salePointID string, _ product.ProductFilters) ([]*product.Product, int64, error) {
+			reached = salePointID
+			return nil, 0, nil
+		},
+	}
+	orders := &mocks.OrderService{
+		GetByIDFunc: func(_ context.Context, id string) (*order.Order, error) {
+			reached = id
+			return nil, order.ErrOrderNotFound
+		},
+		GetByCodeFunc: func(_ context.Context, code string) (*order.Order, error) {
+			reached = code
+			return nil, order.ErrOrderNotFound
+		},
+	}
+
+	categories := &mocks.CategoryService{
+		GetByIDFunc: func(_ context.Context, id string) (*category.Category, error) {
+			reached = id
+			return nil, category.ErrCategoryNotFound
+		},
+	}
+
+	router := SetupRouter(handler.NewProductHandler(products), handler.NewCategoryHandler(categories), handler.NewOrderHandler(orders), nil, nil, cfg)
+	return router, &reached
+}
+
+func TestPathIDFormats(t *testing.T) {
+	const (
+		uuid      = "3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b"
+		legacy    = "prod_00042"
+		orderCode = "ORD-1700000000-a1b2c3d4"
+		anyID     = `^[A-Za-z0-9_-]+$`
+	)
+	legacyFormats := map[string]string{
+		"ID_FORMAT_PRODUCT": anyID, "ID_FORMAT_CATEGORY": anyID, "ID_FORMAT_TENANT": anyID,
+		"ID_FORMAT_ORDER_ID": anyID, "ID_FORMAT_ORDER_CODE": anyID,
+	}
+
+	routes := []struct {
+		name  string
+		path  string // %s is replaced with the ID under test
+		valid string
+	}{
+		{"product", "/api/v1/products/%s", uuid},
+		{"category", "/api/v1/categories/%s", uuid},
+		{"company products", "/api/v1/products/company/%s", uuid},
+		{"sale point products", "/api/v1/products/sale-point/%s", uuid},
+		{"order by id", "/api/v1/orders/id/%s", uuid},
+		{"order by code", "/api/v1/orders/%s", orderCode},
+		{"order tracking", "/api/v1/orders/track/%s", orderCode},
+	}
+	cases := []struct {
+		name     string
+		id       func(valid string) string
+		env      map[string]string
+		rejected bool
+	}{
+		{"valid", func(valid string) string { return valid }, nil, false},
+		{"malformed", func(string) string { return "not-an-id!" }, nil, true},
+		{"first character missing", func(valid string) string { return valid[1:] }, nil, true},
+		{"oversized", func(string) string { return strings.Repeat("a", 2048) }, nil, true},
+		{"legacy rejected by default", func(string) string { return legacy }, nil, true},
+		{"legacy allowed by config", func(string) string { return legacy }, legacyFormats, false},
+		{"oversized despite legacy config", func(string) string { return strings.Repeat("a", 2048) }, legacyFormats, true},
+	}
+
+	for _, route := range routes {
+		for _, tc := range cases {
+			t.Run(route.name+"/"+tc.name, func(t *testing.T) {
+				router, reached := newTestRouter(t, tc.env)
+				id := tc.id(route.valid)
+
+				req := httptest.NewRequest(http.MethodGet, strings.Replace(route.path, "%s", id, 1), nil)
+				req.Header.Set("Authorization", "Bearer "+testAdminToken)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				var body struct {
+					Code string `json:"code"`
+				}
+				_ = json.Unmarshal(w.Body.Bytes(), &body)
+
+				if tc.rejected {
+					if w.Code != http.StatusBadRequest || body.Code != customhttp.InvalidIDFormatCode {
+						t.Fatalf("status = %d, code = %q, want 400 %s", w.Code, body.Code, customhttp.InvalidIDFormatCode)
+					}
+					if *reached != "" {
+						t.Errorf("service was called with %q", *reached)
+					}
+					return
+				}
+				if body.Code == customhttp.InvalidIDFormatCode {
+					t.Fatalf("status = %d, valid ID rejected: %s", w.Code, w.Body.String())
+				}
+				if *reached != id {
+					t.Errorf("service reached with %q, want %q", *reached, id)
+				}
+			})
+		}
+	}
+}
+
+func TestOrderByIDDoesNotShadowCodes(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", testAdminToken)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var called string
+	orders := &mocks.OrderService{
+		GetByIDFunc: func(_ context.Context, id string) (*order.Order, error) {
+			called = "GetByID " + id
+			return nil, order.ErrOrderNotFound
+		},
+		GetByCodeFunc: func(_ context.Context, code string) (*order.Order, error) {
+			called = "GetByCode " + code
+			return nil, order.ErrOrderNotFound
+		},
+	}
+	router := SetupRouter(handler.NewProductHandler(&mocks.ProductService{}), handler.NewCategoryHandler(&mocks.CategoryService{}), handler.NewOrderHandler(orders), nil, nil, cfg)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantCalled string
+		wantStatus int
+	}{
+		{"by id", "/api/v1/orders/id/3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b", "GetByID 3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b", http.StatusNotFound},
+		{"by code", "/api/v1/orders/ORD-7KQ2M9", "GetByCode ORD-7KQ2M9", http.StatusNotFound},
+		{"by legacy code", "/api/v1/orders/ORD-1700000000-a1b2c3d4", "GetByCode ORD-1700000000-a1b2c3d4", http.StatusNotFound},
+		{"id prefix alone is a malformed code", "/api/v1/orders/id", "", http.StatusBadRequest},
+		{"code as an id", "/api/v1/orders/id/ORD-7KQ2M9", "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = ""
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+testAdminToken)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if called != tt.wantCalled {
+				t.Errorf("called %q, want %q", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestAdminOnlyRoutesRejectAnonymousCallers(t *testing.T) {
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/orders/search?q=ana"},
+		{http.MethodPost, "/api/v1/orders/ORD-7KQ2M9/payment/confirm"},
+		{http.MethodGet, "/api/v1/orders/customer/1020304050?id_type=CC"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			router, reached := newTestRouter(t, nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(route.method, route.path, nil))
+
+			var body struct {
+				Code string `json:"code"`
+			}
+			_ = json.Unmarshal(w.Body.Bytes(), &body)
+			if w.Code != http.StatusUnauthorized || body.Code != customhttp.UnauthorizedErrorCode {
+				t.Fatalf("status = %d, code = %q, want 401 %s", w.Code, body.Code, customhttp.UnauthorizedErrorCode)
+			}
+			if *reached != "" {
+				t.Errorf("service was called with %q", *reached)
+			}
+		})
+	}
+}
@@ -10,13 +10,9 @@ import (
 	"time"
 
 	"github.com/emerarteaga/products-api/internal/config"
-	"github.com/emerarteaga/products-api/internal/domain/order"
-	"github.com/emerarteaga/products-api/internal/domain/product"
-	"github.com/emerarteaga/products-api/internal/handler"
 	"github.com/emerarteaga/products-api/internal/infra/logger"
 	"github.com/emerarteaga/products-api/internal/infra/mongo"
-	"github.com/emerarteaga/products-api/internal/repository"
-	"github.com/gin-gonic/gin"
+	ginjson "github.com/gin-gonic/gin/codec/json"
 )
 
 type Server struct {
@@ -39,47 +35,26 @@ func (s *Server) Start() error {
 	s.mongoClient = mongoClient
 	logger.Info("MongoDB connected successfully")
 
-	productsCollection := mongoClient.Database.Collection("products")
-	productRepo := repository.NewProductMongoRepository(productsCollection)
-
-	// Create indexes for products
-	if mongoRepo, ok := productRepo.(interface{ CreateIndexes(context.Context) error }); ok {
-		if err := mongoRepo.CreateIndexes(ctx); err != nil {
-			logger.Warn("failed to create product indexes", "error", err)
-		} else {
-			logger.Info("product indexes created successfully")
-		}
+	deps, err := NewDependencies(s.config, NewMongoRepositories(ctx, mongoClient.Database))
+	if err != nil {
+		return err
 	}
-
-	productService := product.NewService(productRepo)
-	productHandler := handler.NewProductHandler(productService)
-
-	// Initialize order module
-	ordersCollection := mongoClient.Database.Collection("orders")
-	orderRepo := repository.NewOrderMongoRepository(ordersCollection)
-
-	// Create indexes for orders
-	if mongoRepo, ok := orderRepo.(interface{ CreateIndexes(context.Context) error }); ok {
-		if err := mongoRepo.CreateIndexes(ctx); err != nil {
-			logger.Warn("failed to create order indexes", "error", err)
-		} else {
-			logger.Info("order indexes created successfully")
-		}
+	router, err := deps.Router()
+	if err != nil {
+		return err
 	}
 
-	orderService := order.NewService(orderRepo)
-	orderHandler := handler.NewOrderHandler(orderService)
-
-	gin.SetMode(s.config.Server.Mode)
-	router := SetupRouter(productHandler, orderHandler, s.config)
-
 	s.httpServer = &http.Server{
 		Addr:    fmt.Sprintf(":%d", s.config.Server.Port),
 		Handler: router,
 	}
+	if deps.StatusFeed != nil {
+		// Tracking streams never finish on their own; end them so Shutdown does not wait for the timeout
+		s.httpServer.RegisterOnShutdown(deps.StatusFeed.Close)
+	}
 
 	go func() {
-		logger.Info("starting HTTP server", "port", s.config.Server.Port, "mode", s.config.Server.Mode)
+		logger.Info("starting HTTP server", "port", s.config.Server.Port, "mode", s.config.Server.Mode, "json", ginjson.Package)
 		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Error("HTTP server error", "error", err)
 			os.Exit(1)
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAdminToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"unset disables admin routes", "", false},
+		{"long enough", "0123456789abcdef", false},
+		{"too short", "short", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADMIN_TOKEN", tt.token)
+			_, err := LoadConfig()
+
+			var verr *ValidationError
+			hasTokenError := false
+			if errors.As(err, &verr) {
+				for _, fe := range verr.Errors {
+					hasTokenError = hasTokenError || fe.Env == "ADMIN_TOKEN"
+				}
+			}
+			if hasTokenError != tt.wantErr {
+				t.Fatalf("ADMIN_TOKEN error = %v, want %v (err: %v)", hasTokenError, tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRedactedMasksAdminToken(t *testing.T) {
+	cfg := &Config{Admin: AdminConfig{Token: "0123456789abcdef"}}
+	if got := cfg.Redacted().Admin.Token; got != redactedMask {
+		t.Errorf("redacted token = %q, want %q", got, redactedMask)
+	}
+	if cfg.Admin.Token != "0123456789abcdef" {
+		t.Error("Redacted modified the original configuration")
+	}
+	if got := (&Config{}).Redacted().Admin.Token; got != "" {
+		t.Errorf("redacted empty token = %q, want empty", got)
+	}
+}
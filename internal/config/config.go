@@ -4,14 +4,36 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+
+	"github.com/emerarteaga/products-api/internal/money"
 )
 
+// uuidPattern matches the canonical UUID form used for generated IDs
+const uuidPattern = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logger   LoggerConfig
-	CORS     CORSConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Logger      LoggerConfig
+	CORS        CORSConfig
+	Pagination  PaginationConfig
+	Metrics     MetricsConfig
+	Products    ProductsConfig
+	AutoAdvance AutoAdvanceConfig
+	Transitions TransitionsConfig
+	RateLimit   RateLimitConfig
+	Archive     ArchiveConfig
+	Media       MediaConfig
+	Orders      OrdersConfig
+	Maintenance MaintenanceConfig
+	Admin       AdminConfig
+	Dashboard   DashboardConfig
+	IDFormats   IDFormatsConfig
+	Currency    CurrencyConfig
+
+	parseErrors []FieldError // Variables that were set but could not be parsed
 }
 
 // ServerConfig holds server-specific configuration
@@ -27,45 +49,282 @@ type CORSConfig struct {
 	AllowedHeaders []string // List of allowed headers
 }
 
+// PaginationConfig holds page size limits for list endpoints
+type PaginationConfig struct {
+	DefaultLimit      int
+	MaxLimit          int
+	Orders            PageLimitsConfig // GET /orders
+	CompanyProducts   PageLimitsConfig // GET /products/company/:company_id
+	SalePointProducts PageLimitsConfig // GET /products/sale-point/:sale_point_id (public menu)
+}
+
+// PageLimitsConfig holds the page size limits for a single endpoint
+type PageLimitsConfig struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// MetricsConfig holds Prometheus metrics configuration
+type MetricsConfig struct {
+	Enabled bool
+	Path    string // HTTP path where metrics are exposed
+}
+
+// AutoAdvanceConfig holds the automatic status advance rules per sale type.
+// Each rule has the form "FROM>TO@trigger", e.g. "CREATED>VERIFIED@payment_receipt".
+type AutoAdvanceConfig struct {
+	Delivery []string
+	OnSite   []string
+}
+
+// TransitionsConfig holds the per-sale-type status transition overrides.
+// Each edge has the form "FROM>TO", e.g. "CREATED>IN_PROGRESS"; an empty list keeps the default state machine.
+// Extra edges are added to the state machine in use instead of replacing it.
+type TransitionsConfig struct {
+	Delivery      []string
+	OnSite        []string
+	DeliveryExtra []string
+	OnSiteExtra   []string
+}
+
+// CurrencyConfig holds the currency of all stored amounts
+type CurrencyConfig struct {
+	Code       string // ISO 4217 code
+	MinorUnits int    // Digits of the minor unit; defaults to the code's customary digits
+}
+
+// IDFormatsConfig holds the regular expressions path IDs must match.
+// Deployments with legacy non-UUID IDs can relax them, e.g. to "^[A-Za-z0-9_-]+$".
+type IDFormatsConfig struct {
+	ProductID  string // /products/:id
+	CategoryID string // /categories/:id
+	TenantID   string // /.../company/:company_id and /.../sale-point/:sale_point_id
+	OrderID    string // /orders/id/:id
+	OrderCode  string // /orders/:code and /orders/track/:code
+}
+
+// MaintenanceConfig holds the read-only mode used during database migrations
+type MaintenanceConfig struct {
+	ReadOnly          bool // Initial state; can be toggled at runtime via POST /api/v1/admin/read-only
+	RetryAfterSeconds int  // Retry-After sent with rejected writes
+}
+
+// AdminConfig holds the credentials of the /api/v1/admin routes and staff-only options
+type AdminConfig struct {
+	Token string // Sent as "Authorization: Bearer <token>" or the Basic auth password; empty disables the admin routes
+}
+
+// DashboardConfig holds the embedded admin dashboard settings
+type DashboardConfig struct {
+	Enabled           bool   // Serve GET /api/v1/admin/dashboard
+	Timezone          string // IANA zone that defines "today"
+	LowStockThreshold int    // Products with this stock or less are listed
+	LowStockLimit     int    // Maximum low stock products listed
+	RefreshSeconds    int    // How often the page polls for new numbers; 0 disables polling
+}
+
+// RateLimitConfig holds per-client request limits for expensive endpoints
+type RateLimitConfig struct {
+	SearchPerMinute int // GET /orders/search; 0 disables the limit
+}
+
+// ArchiveConfig holds the bounds for archiving old terminal orders
+type ArchiveConfig struct {
+	MinAgeDays   int // Safety floor: the cutoff must be at least this many days in the past
+	BatchSize    int // Orders moved per batch
+	MaxBatches   int // Batches processed per request
+	BatchPauseMs int // Pause between batches, in milliseconds
+}
+
+// OrdersConfig holds order-specific settings
+type OrdersConfig struct {
+	DefaultPhoneCountryCode string // Applied to customer phones entered without a country code
+	MaxLineQuantity         int    // Maximum quantity per product line; 0 disables
+	MaxLines                int    // Maximum number of product lines; 0 disables
+	MaxTotal                int64  // Maximum order total in cents; 0 disables
+	TrackingURLTemplate     string // Customer tracking page encoded in order QR codes, e.g. "https://track.example.com/{code}"; empty disables QR codes
+	CustomerEditMinutes     int    // How long after creation customers may edit address and note via the tracking code; 0 disables
+	CodeFormat              string // legacy (ORD-<nanos>-<hex>) or short (ORD-7F3K2Q)
+	ShortCodeLength         int    // Random characters in short codes
+	TrackingStreamMax       int    // Concurrent tracking streams (SSE) per order code; 0 disables streams
+	FilterTimezone          string // IANA zone of date-only date_from/date_to filter values
+	RequirePaidDispatch     bool   // DELIVERY orders need a CONFIRMED payment before going OUT_FOR_DELIVERY
+	MaxNotes                int    // Maximum number of notes per order
+	KeepStatusOnModify      bool   // PUT product changes only verify CREATED orders instead of moving every order back to VERIFIED
+}
+
+// ProductsConfig holds product-specific settings
+type ProductsConfig struct {
+	DeleteReferenceDays int  // Bulk deletes refuse products in orders from the last N days; 0 disables the check
+	ValidateCategories  bool // Product categories must name a category of the sale point, when it defines any
+	ImportMaxBytes      int  // Largest CSV file accepted by product imports
+	ImportMaxRows       int  // Most data rows per product import
+}
+
+// MediaConfig holds restrictions on user-supplied media URLs
+type MediaConfig struct {
+	AllowedHosts []string // Photo and receipt URL hosts, e.g. "*.cloudfront.net"; empty allows all
+}
+
 // DatabaseConfig holds database-specific configuration
 type DatabaseConfig struct {
-	URI         string
-	Name        string
-	MaxPoolSize uint64
-	Timeout     int // in seconds
+	URI              string
+	Name             string
+	MaxPoolSize      uint64
+	Timeout          int // in seconds
+	MaxDocumentBytes int // Soft limit on stored order/product documents, well below MongoDB's 16MB hard limit
+
+	// ReadPreference is the MongoDB read preference mode, e.g. "secondaryPreferred"
+	ReadPreference string
+	// ReadAfterWriteSeconds is how long after a write the client's reads go to the primary; 0 disables the cookie
+	ReadAfterWriteSeconds int
 }
 
 // LoggerConfig holds logger-specific configuration
 type LoggerConfig struct {
-	Level  string // debug, info, warn, error
-	Format string // json, text
+	Level              string   // debug, info, warn, error
+	Format             string   // json, text
+	Outputs            []string // stdout, stderr (warn and above), file
+	File               LogFileConfig
+	HTTPAccessSeparate bool   // Write HTTP access logs to HTTPAccessFile instead of the outputs
+	HTTPAccessFile     string // Rotated with the File limits
+}
+
+// LogFileConfig holds the rotating log file settings
+type LogFileConfig struct {
+	Path       string
+	MaxSizeMB  int // Rotate once the file would grow past this size
+	MaxBackups int // Rotated files kept; 0 keeps none
+	MaxAgeDays int // Delete rotated files older than this; 0 disables
 }
 
 // LoadConfig reads configuration from environment variables
 func LoadConfig() (*Config, error) {
+	envErrors = nil
 	config := &Config{
 		Server: ServerConfig{
 			Port: getEnvAsInt("SERVER_PORT", 8080),
 			Mode: getEnv("SERVER_MODE", "debug"),
 		},
 		Database: DatabaseConfig{
-			URI:         getEnv("DATABASE_URI", "mongodb://localhost:27017"),
-			Name:        getEnv("DATABASE_NAME", "products_db"),
-			MaxPoolSize: getEnvAsUint64("DATABASE_MAX_POOL_SIZE", 100),
-			Timeout:     getEnvAsInt("DATABASE_TIMEOUT", 10),
+			URI:                   getEnv("DATABASE_URI", "mongodb://localhost:27017"),
+			Name:                  getEnv("DATABASE_NAME", "products_db"),
+			MaxPoolSize:           getEnvAsUint64("DATABASE_MAX_POOL_SIZE", 100),
+			Timeout:               getEnvAsInt("DATABASE_TIMEOUT", 10),
+			MaxDocumentBytes:      getEnvAsInt("DATABASE_MAX_DOCUMENT_BYTES", 1<<20),
+			ReadPreference:        getEnv("DATABASE_READ_PREFERENCE", "primary"),
+			ReadAfterWriteSeconds: getEnvAsInt("DATABASE_READ_AFTER_WRITE_SECONDS", 5),
 		},
 		Logger: LoggerConfig{
-			Level:  getEnv("LOGGER_LEVEL", "info"),
-			Format: getEnv("LOGGER_FORMAT", "json"),
+			Level:   getEnv("LOGGER_LEVEL", "info"),
+			Format:  getEnv("LOGGER_FORMAT", "json"),
+			Outputs: getEnvAsSlice("LOGGER_OUTPUTS", []string{"stdout"}),
+			File: LogFileConfig{
+				Path:       getEnv("LOGGER_FILE_PATH", "logs/app.log"),
+				MaxSizeMB:  getEnvAsInt("LOGGER_FILE_MAX_SIZE_MB", 100),
+				MaxBackups: getEnvAsInt("LOGGER_FILE_MAX_BACKUPS", 5),
+				MaxAgeDays: getEnvAsInt("LOGGER_FILE_MAX_AGE_DAYS", 30),
+			},
+			HTTPAccessSeparate: getEnvAsBool("LOGGER_HTTP_ACCESS_SEPARATE", false),
+			HTTPAccessFile:     getEnv("LOGGER_HTTP_ACCESS_FILE", "logs/access.log"),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"*"}),
 			AllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-Requested-With"}),
+			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "X-Requested-With", "X-Read-After-Write", "If-Match"}),
+		},
+		Metrics: MetricsConfig{
+			Enabled: getEnvAsBool("METRICS_ENABLED", true),
+			Path:    getEnv("METRICS_PATH", "/metrics"),
+		},
+		Orders: OrdersConfig{
+			DefaultPhoneCountryCode: strings.TrimPrefix(getEnv("PHONE_DEFAULT_COUNTRY_CODE", "57"), "+"),
+			MaxLineQuantity:         getEnvAsInt("ORDER_MAX_LINE_QUANTITY", 0),
+			MaxLines:                getEnvAsInt("ORDER_MAX_LINES", 0),
+			MaxTotal:                int64(getEnvAsInt("ORDER_MAX_TOTAL", 0)),
+			TrackingURLTemplate:     getEnv("TRACKING_URL_TEMPLATE", ""),
+			CustomerEditMinutes:     getEnvAsInt("ORDER_CUSTOMER_EDIT_MINUTES", 10),
+			CodeFormat:              strings.ToLower(getEnv("ORDER_CODE_FORMAT", "legacy")),
+			ShortCodeLength:         getEnvAsInt("ORDER_CODE_SHORT_LENGTH", 6),
+			TrackingStreamMax:       getEnvAsInt("TRACKING_STREAM_MAX_PER_ORDER", 5),
+			FilterTimezone:          getEnv("ORDER_FILTER_TIMEZONE", "UTC"),
+			RequirePaidDispatch:     getEnvAsBool("ORDER_REQUIRE_PAYMENT_BEFORE_DISPATCH", true),
+			MaxNotes:                getEnvAsInt("ORDER_MAX_NOTES", 50),
+			KeepStatusOnModify:      getEnvAsBool("ORDER_KEEP_STATUS_ON_MODIFY", false),
+		},
+		Products: ProductsConfig{
+			DeleteReferenceDays: getEnvAsInt("PRODUCT_DELETE_REFERENCE_DAYS", 30),
+			ValidateCategories:  getEnvAsBool("PRODUCT_VALIDATE_CATEGORIES", false),
+			ImportMaxBytes:      getEnvAsInt("PRODUCT_IMPORT_MAX_BYTES", 2<<20),
+			ImportMaxRows:       getEnvAsInt("PRODUCT_IMPORT_MAX_ROWS", 1000),
+		},
+		Media: MediaConfig{
+			AllowedHosts: getEnvAsSlice("PHOTO_URL_ALLOWED_HOSTS", nil),
+		},
+		Archive: ArchiveConfig{
+			MinAgeDays:   getEnvAsInt("ARCHIVE_MIN_AGE_DAYS", 90),
+			BatchSize:    getEnvAsInt("ARCHIVE_BATCH_SIZE", 500),
+			MaxBatches:   getEnvAsInt("ARCHIVE_MAX_BATCHES", 10),
+			BatchPauseMs: getEnvAsInt("ARCHIVE_BATCH_PAUSE_MS", 100),
 		},
+		Currency: CurrencyConfig{
+			Code:       strings.ToUpper(getEnv("CURRENCY_CODE", "COP")),
+			MinorUnits: getEnvAsInt("CURRENCY_MINOR_UNITS", -1),
+		},
+		IDFormats: IDFormatsConfig{
+			ProductID:  getEnv("ID_FORMAT_PRODUCT", uuidPattern),
+			CategoryID: getEnv("ID_FORMAT_CATEGORY", uuidPattern),
+			TenantID:   getEnv("ID_FORMAT_TENANT", uuidPattern),
+			OrderID:    getEnv("ID_FORMAT_ORDER_ID", uuidPattern),
+			OrderCode:  getEnv("ID_FORMAT_ORDER_CODE", `^(?i)ORD-([0-9]+-[0-9a-f]{8}|[0-9A-Z]{4,12})$`),
+		},
+		Maintenance: MaintenanceConfig{
+			ReadOnly:          getEnvAsBool("READ_ONLY_MODE", false),
+			RetryAfterSeconds: getEnvAsInt("READ_ONLY_RETRY_AFTER_SECONDS", 300),
+		},
+		Admin: AdminConfig{
+			Token: getEnv("ADMIN_TOKEN", ""),
+		},
+		Dashboard: DashboardConfig{
+			Enabled:           getEnvAsBool("ADMIN_DASHBOARD_ENABLED", false),
+			Timezone:          getEnv("ADMIN_DASHBOARD_TIMEZONE", "UTC"),
+			LowStockThreshold: getEnvAsInt("ADMIN_DASHBOARD_LOW_STOCK_THRESHOLD", 5),
+			LowStockLimit:     getEnvAsInt("ADMIN_DASHBOARD_LOW_STOCK_LIMIT", 20),
+			RefreshSeconds:    getEnvAsInt("ADMIN_DASHBOARD_REFRESH_SECONDS", 30),
+		},
+		RateLimit: RateLimitConfig{
+			SearchPerMinute: getEnvAsInt("RATE_LIMIT_SEARCH_PER_MINUTE", 30),
+		},
+		AutoAdvance: AutoAdvanceConfig{
+			Delivery: getEnvAsSlice("AUTO_ADVANCE_DELIVERY", nil),
+			OnSite:   getEnvAsSlice("AUTO_ADVANCE_ON_SITE", nil),
+		},
+		Transitions: TransitionsConfig{
+			Delivery:      getEnvAsSlice("ORDER_TRANSITIONS_DELIVERY", nil),
+			OnSite:        getEnvAsSlice("ORDER_TRANSITIONS_ON_SITE", nil),
+			DeliveryExtra: getEnvAsSlice("ORDER_TRANSITIONS_DELIVERY_EXTRA", nil),
+			OnSiteExtra:   getEnvAsSlice("ORDER_TRANSITIONS_ON_SITE_EXTRA", nil),
+		},
+	}
+
+	// Pagination: per-endpoint overrides fall back to the global limits
+	defaultLimit := getEnvAsInt("PAGINATION_DEFAULT_LIMIT", 50)
+	maxLimit := getEnvAsInt("PAGINATION_MAX_LIMIT", 100)
+	config.Pagination = PaginationConfig{
+		DefaultLimit:      defaultLimit,
+		MaxLimit:          maxLimit,
+		Orders:            getPageLimits("PAGINATION_ORDERS", defaultLimit, maxLimit),
+		CompanyProducts:   getPageLimits("PAGINATION_COMPANY_PRODUCTS", defaultLimit, maxLimit),
+		SalePointProducts: getPageLimits("PAGINATION_SALE_POINT_PRODUCTS", defaultLimit, maxLimit),
 	}
 
-	// Validate configuration
+	// Currencies default to their customary minor unit digits
+	if currency, ok := money.Lookup(config.Currency.Code); ok && config.Currency.MinorUnits < 0 {
+		config.Currency.MinorUnits = currency.MinorUnits
+	}
+
+	// Validate configuration, reporting every problem at once
+	config.parseErrors = envErrors
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -73,6 +332,14 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// envErrors collects the variables getEnvAs* could not parse during LoadConfig
+var envErrors []FieldError
+
+// invalidEnv records a variable that could not be parsed; its default value is used meanwhile
+func invalidEnv(key, value, kind string) {
+	envErrors = append(envErrors, FieldError{Env: key, Message: fmt.Sprintf("%q is not a valid %s", value, kind)})
+}
+
 // getEnv reads an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -89,6 +356,29 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	value, err := strconv.Atoi(valueStr)
 	if err != nil {
+		invalidEnv(key, valueStr, "integer")
+		return defaultValue
+	}
+	return value
+}
+
+// getPageLimits reads <prefix>_DEFAULT_LIMIT and <prefix>_MAX_LIMIT or returns the given defaults
+func getPageLimits(prefix string, defaultLimit, maxLimit int) PageLimitsConfig {
+	return PageLimitsConfig{
+		DefaultLimit: getEnvAsInt(prefix+"_DEFAULT_LIMIT", defaultLimit),
+		MaxLimit:     getEnvAsInt(prefix+"_MAX_LIMIT", maxLimit),
+	}
+}
+
+// getEnvAsBool reads an environment variable as bool or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		invalidEnv(key, valueStr, "boolean")
 		return defaultValue
 	}
 	return value
@@ -102,6 +392,7 @@ func getEnvAsUint64(key string, defaultValue uint64) uint64 {
 	}
 	value, err := strconv.ParseUint(valueStr, 10, 64)
 	if err != nil {
+		invalidEnv(key, valueStr, "non-negative integer")
 		return defaultValue
 	}
 	return value
@@ -170,30 +461,3 @@ func trim(s string) string {
 	}
 	return s[start:end]
 }
-
-// Validate checks if the configuration is valid
-func (c *Config) Validate() error {
-	if c.Server.Port <= 0 || c.Server.Port > 65535 {
-		return fmt.Errorf("invalid server port: %d", c.Server.Port)
-	}
-
-	if c.Database.URI == "" {
-		return fmt.Errorf("database URI is required")
-	}
-
-	if c.Database.Name == "" {
-		return fmt.Errorf("database name is required")
-	}
-
-	validModes := map[string]bool{"debug": true, "release": true, "test": true}
-	if !validModes[c.Server.Mode] {
-		return fmt.Errorf("invalid server mode: %s", c.Server.Mode)
-	}
-
-	validLogLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
-	if !validLogLevels[c.Logger.Level] {
-		return fmt.Errorf("invalid logger level: %s", c.Logger.Level)
-	}
-
-	return nil
-}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCurrencyConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		want      CurrencyConfig
+		wantError string // Env of the expected validation error
+	}{
+		{"defaults to pesos without decimals", nil, CurrencyConfig{Code: "COP", MinorUnits: 0}, ""},
+		{"customary minor units", map[string]string{"CURRENCY_CODE": "usd"}, CurrencyConfig{Code: "USD", MinorUnits: 2}, ""},
+		{"explicit minor units", map[string]string{"CURRENCY_CODE": "COP", "CURRENCY_MINOR_UNITS": "2"}, CurrencyConfig{Code: "COP", MinorUnits: 2}, ""},
+		{"unknown code", map[string]string{"CURRENCY_CODE": "XYZ"}, CurrencyConfig{}, "CURRENCY_CODE"},
+		{"too many minor units", map[string]string{"CURRENCY_MINOR_UNITS": "5"}, CurrencyConfig{}, "CURRENCY_MINOR_UNITS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := LoadConfig()
+
+			if tt.wantError != "" {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || !hasEnvError(verr, tt.wantError) {
+					t.Fatalf("err = %v, want an error for %s", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Currency != tt.want {
+				t.Errorf("currency = %+v, want %+v", cfg.Currency, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestIDFormats(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		matches   map[string]string // Pattern name to an ID it must match
+		wantError string            // Env of the expected validation error
+	}{
+		{
+			name: "defaults",
+			matches: map[string]string{
+				"product":    "3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b",
+				"order_code": "ORD-1700000000-a1b2c3d4",
+			},
+		},
+		{
+			name:    "legacy product IDs",
+			env:     map[string]string{"ID_FORMAT_PRODUCT": `^prod_[0-9]+$`},
+			matches: map[string]string{"product": "prod_00042"},
+		},
+		{
+			name:      "invalid regular expression",
+			env:       map[string]string{"ID_FORMAT_TENANT": `^[a-z+$`},
+			wantError: "ID_FORMAT_TENANT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := LoadConfig()
+
+			if tt.wantError != "" {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || !hasEnvError(verr, tt.wantError) {
+					t.Fatalf("err = %v, want an error for %s", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			patterns := map[string]string{"product": cfg.IDFormats.ProductID, "order_code": cfg.IDFormats.OrderCode}
+			for name, id := range tt.matches {
+				if !regexp.MustCompile(patterns[name]).MatchString(id) {
+					t.Errorf("%s pattern %q does not match %q", name, patterns[name], id)
+				}
+			}
+		})
+	}
+}
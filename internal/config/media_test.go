@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMediaAllowedHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		want    []string
+		wantErr bool
+	}{
+		{"unset allows all", "", nil, false},
+		{"host names and wildcards", "cdn.example.com,*.cloudfront.net", []string{"cdn.example.com", "*.cloudfront.net"}, false},
+		{"pinned port", "media.example.org:8443", []string{"media.example.org:8443"}, false},
+		{"ipv6 literal with port", "[::1]:8080", []string{"[::1]:8080"}, false},
+		{"scheme is not a host", "https://cdn.example.com", nil, true},
+		{"path is not a host", "cdn.example.com/photos", nil, true},
+		{"userinfo is not a host", "user@cdn.example.com", nil, true},
+		{"bare wildcard", "*.", nil, true},
+		{"port out of range", "cdn.example.com:70000", nil, true},
+		{"port not a number", "cdn.example.com:https", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("PHOTO_URL_ALLOWED_HOSTS", tt.env)
+			}
+			cfg, err := LoadConfig()
+
+			if tt.wantErr {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || !hasEnvError(verr, "PHOTO_URL_ALLOWED_HOSTS") {
+					t.Fatalf("err = %v, want an error for PHOTO_URL_ALLOWED_HOSTS", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(cfg.Media.AllowedHosts, tt.want) {
+				t.Errorf("allowed hosts = %q, want %q", cfg.Media.AllowedHosts, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOrderCodeConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantFormat string
+		wantLength int
+		wantError  string // Env of the expected validation error
+	}{
+		{"legacy by default", nil, "legacy", 6, ""},
+		{"short", map[string]string{"ORDER_CODE_FORMAT": "short"}, "short", 6, ""},
+		{"format in any case", map[string]string{"ORDER_CODE_FORMAT": "SHORT"}, "short", 6, ""},
+		{"shortest length", map[string]string{"ORDER_CODE_FORMAT": "short", "ORDER_CODE_SHORT_LENGTH": "4"}, "short", 4, ""},
+		{"longest length", map[string]string{"ORDER_CODE_FORMAT": "short", "ORDER_CODE_SHORT_LENGTH": "12"}, "short", 12, ""},
+		{"unknown format", map[string]string{"ORDER_CODE_FORMAT": "base32"}, "", 0, "ORDER_CODE_FORMAT"},
+		{"too short", map[string]string{"ORDER_CODE_SHORT_LENGTH": "3"}, "", 0, "ORDER_CODE_SHORT_LENGTH"},
+		{"too long", map[string]string{"ORDER_CODE_SHORT_LENGTH": "13"}, "", 0, "ORDER_CODE_SHORT_LENGTH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := LoadConfig()
+
+			if tt.wantError != "" {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || !hasEnvError(verr, tt.wantError) {
+					t.Fatalf("err = %v, want an error for %s", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Orders.CodeFormat != tt.wantFormat || cfg.Orders.ShortCodeLength != tt.wantLength {
+				t.Errorf("code format = %s/%d, want %s/%d", cfg.Orders.CodeFormat, cfg.Orders.ShortCodeLength, tt.wantFormat, tt.wantLength)
+			}
+		})
+	}
+}
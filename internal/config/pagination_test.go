@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPaginationConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		want      PaginationConfig
+		wantError string // Env of the expected validation error
+	}{
+		{
+			name: "defaults",
+			want: PaginationConfig{
+				DefaultLimit: 50, MaxLimit: 100,
+				Orders:            PageLimitsConfig{50, 100},
+				CompanyProducts:   PageLimitsConfig{50, 100},
+				SalePointProducts: PageLimitsConfig{50, 100},
+			},
+		},
+		{
+			name: "global limits apply to every endpoint",
+			env:  map[string]string{"PAGINATION_DEFAULT_LIMIT": "20", "PAGINATION_MAX_LIMIT": "200"},
+			want: PaginationConfig{
+				DefaultLimit: 20, MaxLimit: 200,
+				Orders:            PageLimitsConfig{20, 200},
+				CompanyProducts:   PageLimitsConfig{20, 200},
+				SalePointProducts: PageLimitsConfig{20, 200},
+			},
+		},
+		{
+			name: "per endpoint overrides",
+			env: map[string]string{
+				"PAGINATION_ORDERS_MAX_LIMIT":                  "500",
+				"PAGINATION_SALE_POINT_PRODUCTS_DEFAULT_LIMIT": "10",
+				"PAGINATION_SALE_POINT_PRODUCTS_MAX_LIMIT":     "30",
+			},
+			want: PaginationConfig{
+				DefaultLimit: 50, MaxLimit: 100,
+				Orders:            PageLimitsConfig{50, 500},
+				CompanyProducts:   PageLimitsConfig{50, 100},
+				SalePointProducts: PageLimitsConfig{10, 30},
+			},
+		},
+		{
+			name:      "maximum below the default",
+			env:       map[string]string{"PAGINATION_ORDERS_DEFAULT_LIMIT": "200"},
+			wantError: "PAGINATION_ORDERS_MAX_LIMIT",
+		},
+		{
+			name:      "non-positive default",
+			env:       map[string]string{"PAGINATION_DEFAULT_LIMIT": "0"},
+			wantError: "PAGINATION_DEFAULT_LIMIT",
+		},
+		{
+			name:      "not a number",
+			env:       map[string]string{"PAGINATION_MAX_LIMIT": "lots"},
+			wantError: "PAGINATION_MAX_LIMIT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := LoadConfig()
+
+			if tt.wantError != "" {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || !hasEnvError(verr, tt.wantError) {
+					t.Fatalf("err = %v, want an error for %s", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Pagination != tt.want {
+				t.Errorf("pagination = %+v, want %+v", cfg.Pagination, tt.want)
+			}
+		})
+	}
+}
+
+// hasEnvError reports whether the validation error names the environment variable
+func hasEnvError(verr *ValidationError, env string) bool {
+	for _, fe := range verr.Errors {
+		if fe.Env == env {
+			return true
+		}
+	}
+	return false
+}
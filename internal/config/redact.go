@@ -0,0 +1,29 @@
+package config
+
+import "net/url"
+
+// redactedMask replaces secrets in logged configuration
+const redactedMask = "xxxxx"
+
+// Redacted returns a copy of the configuration that is safe to log: credentials are masked
+func (c *Config) Redacted() Config {
+	redacted := *c
+	redacted.parseErrors = nil
+	redacted.Database.URI = redactURI(c.Database.URI)
+	if c.Admin.Token != "" {
+		redacted.Admin.Token = redactedMask
+	}
+	return redacted
+}
+
+// redactURI masks the password of a connection URI, or the whole URI if it cannot be parsed
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return redactedMask
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), redactedMask)
+	}
+	return u.String()
+}
@@ -0,0 +1,327 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/money"
+)
+
+// FieldError is a configuration problem tied to the setting that caused it
+type FieldError struct {
+	Field   string // Path in Config, e.g. "server.port"; empty when the variable could not be parsed
+	Env     string // Environment variable that sets it, e.g. "SERVER_PORT"
+	Message string
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", e.Env, e.Message)
+	}
+	return fmt.Sprintf("%s (%s): %s", e.Field, e.Env, e.Message)
+}
+
+// ValidationError collects every configuration problem found in a single pass
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error lists every problem, one per line
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d configuration problem(s):", len(e.Errors))
+	for _, fe := range e.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(fe.Error())
+	}
+	return b.String()
+}
+
+// Unwrap exposes the individual problems to errors.Is and errors.As
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fe := range e.Errors {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// problems accumulates field errors while validating
+type problems []FieldError
+
+// check records a problem unless ok holds
+func (p *problems) check(ok bool, field, env, format string, args ...any) {
+	if !ok {
+		*p = append(*p, FieldError{Field: field, Env: env, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// Validate checks every configuration section and reports all problems at once as a *ValidationError
+func (c *Config) Validate() error {
+	p := append(problems{}, c.parseErrors...)
+
+	c.validateServer(&p)
+	c.validateDatabase(&p)
+	c.validateLogger(&p)
+	c.validateCORS(&p)
+	c.validatePagination(&p)
+	c.validateMetrics(&p)
+	c.validateOrders(&p)
+	c.validateProducts(&p)
+	c.validateRules(&p)
+	c.validateRateLimit(&p)
+	c.validateArchive(&p)
+	c.validateMedia(&p)
+	c.validateCurrency(&p)
+	c.validateIDFormats(&p)
+	c.validateMaintenance(&p)
+	c.validateAdmin(&p)
+	c.validateDashboard(&p)
+
+	if len(p) > 0 {
+		return &ValidationError{Errors: p}
+	}
+	return nil
+}
+
+func (c *Config) validateServer(p *problems) {
+	p.check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port", "SERVER_PORT", "must be between 1 and 65535, got %d", c.Server.Port)
+	validModes := map[string]bool{"debug": true, "release": true, "test": true}
+	p.check(validModes[c.Server.Mode], "server.mode", "SERVER_MODE", "must be debug, release or test, got %q", c.Server.Mode)
+}
+
+func (c *Config) validateDatabase(p *problems) {
+	if c.Database.URI == "" {
+		p.check(false, "database.uri", "DATABASE_URI", "is required")
+	} else {
+		u, err := url.Parse(c.Database.URI)
+		p.check(err == nil && (u.Scheme == "mongodb" || u.Scheme == "mongodb+srv") && u.Host != "",
+			"database.uri", "DATABASE_URI", "must be a mongodb:// or mongodb+srv:// URI")
+	}
+	p.check(c.Database.Name != "", "database.name", "DATABASE_NAME", "is required")
+	p.check(c.Database.MaxPoolSize > 0, "database.max_pool_size", "DATABASE_MAX_POOL_SIZE", "must be positive")
+	p.check(c.Database.Timeout > 0, "database.timeout", "DATABASE_TIMEOUT", "must be a positive number of seconds, got %d", c.Database.Timeout)
+	p.check(c.Database.MaxDocumentBytes > 0 && c.Database.MaxDocumentBytes <= 16<<20,
+		"database.max_document_bytes", "DATABASE_MAX_DOCUMENT_BYTES", "must be between 1 and 16MB, got %d bytes", c.Database.MaxDocumentBytes)
+	validReadPreferences := map[string]bool{"primary": true, "primaryPreferred": true, "secondary": true, "secondaryPreferred": true, "nearest": true}
+	p.check(validReadPreferences[c.Database.ReadPreference], "database.read_preference", "DATABASE_READ_PREFERENCE",
+		"must be primary, primaryPreferred, secondary, secondaryPreferred or nearest, got %q", c.Database.ReadPreference)
+	p.check(c.Database.ReadAfterWriteSeconds >= 0, "database.read_after_write_seconds", "DATABASE_READ_AFTER_WRITE_SECONDS",
+		"must be 0 (disabled) or positive, got %d", c.Database.ReadAfterWriteSeconds)
+}
+
+func (c *Config) validateLogger(p *problems) {
+	validLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+	p.check(validLevels[c.Logger.Level], "logger.level", "LOGGER_LEVEL", "must be debug, info, warn or error, got %q", c.Logger.Level)
+	p.check(c.Logger.Format == "json" || c.Logger.Format == "text", "logger.format", "LOGGER_FORMAT", "must be json or text, got %q", c.Logger.Format)
+
+	p.check(len(c.Logger.Outputs) > 0, "logger.outputs", "LOGGER_OUTPUTS", "must list at least one output")
+	for _, output := range c.Logger.Outputs {
+		p.check(output == "stdout" || output == "stderr" || output == "file", "logger.outputs", "LOGGER_OUTPUTS", "must be stdout, stderr or file, got %q", output)
+	}
+	usesFile := slices.Contains(c.Logger.Outputs, "file")
+	if usesFile {
+		p.check(c.Logger.File.Path != "", "logger.file.path", "LOGGER_FILE_PATH", "is required with the file output")
+	}
+	if c.Logger.HTTPAccessSeparate {
+		p.check(c.Logger.HTTPAccessFile != "", "logger.http_access_file", "LOGGER_HTTP_ACCESS_FILE", "is required when LOGGER_HTTP_ACCESS_SEPARATE is set")
+	}
+	if usesFile || c.Logger.HTTPAccessSeparate {
+		p.check(c.Logger.File.MaxSizeMB > 0, "logger.file.max_size_mb", "LOGGER_FILE_MAX_SIZE_MB", "must be positive, got %d", c.Logger.File.MaxSizeMB)
+		p.check(c.Logger.File.MaxBackups >= 0, "logger.file.max_backups", "LOGGER_FILE_MAX_BACKUPS", "must be 0 or positive, got %d", c.Logger.File.MaxBackups)
+		p.check(c.Logger.File.MaxAgeDays >= 0, "logger.file.max_age_days", "LOGGER_FILE_MAX_AGE_DAYS", "must be 0 (disabled) or positive, got %d", c.Logger.File.MaxAgeDays)
+	}
+}
+
+func (c *Config) validateCORS(p *problems) {
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		p.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && (u.Path == "" || u.Path == "/"),
+			"cors.allowed_origins", "CORS_ALLOWED_ORIGINS", "origin %q must be \"*\" or scheme://host[:port]", origin)
+	}
+	validMethods := map[string]bool{"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "OPTIONS": true}
+	for _, method := range c.CORS.AllowedMethods {
+		p.check(validMethods[method], "cors.allowed_methods", "CORS_ALLOWED_METHODS", "unknown HTTP method %q", method)
+	}
+}
+
+func (c *Config) validatePagination(p *problems) {
+	pageLimits := []struct {
+		field, env string
+		limits     PageLimitsConfig
+	}{
+		{"pagination", "PAGINATION", PageLimitsConfig{DefaultLimit: c.Pagination.DefaultLimit, MaxLimit: c.Pagination.MaxLimit}},
+		{"pagination.orders", "PAGINATION_ORDERS", c.Pagination.Orders},
+		{"pagination.company_products", "PAGINATION_COMPANY_PRODUCTS", c.Pagination.CompanyProducts},
+		{"pagination.sale_point_products", "PAGINATION_SALE_POINT_PRODUCTS", c.Pagination.SalePointProducts},
+	}
+	for _, pl := range pageLimits {
+		p.check(pl.limits.DefaultLimit > 0, pl.field+".default_limit", pl.env+"_DEFAULT_LIMIT", "must be positive, got %d", pl.limits.DefaultLimit)
+		p.check(pl.limits.MaxLimit >= pl.limits.DefaultLimit, pl.field+".max_limit", pl.env+"_MAX_LIMIT",
+			"must be at least the default limit %d, got %d", pl.limits.DefaultLimit, pl.limits.MaxLimit)
+	}
+}
+
+func (c *Config) validateMetrics(p *problems) {
+	if c.Metrics.Enabled {
+		p.check(strings.HasPrefix(c.Metrics.Path, "/"), "metrics.path", "METRICS_PATH", "must start with /, got %q", c.Metrics.Path)
+	}
+}
+
+func (c *Config) validateOrders(p *problems) {
+	code := c.Orders.DefaultPhoneCountryCode
+	p.check(code != "" && len(code) <= 3 && strings.Trim(code, "0123456789") == "" && code[0] != '0',
+		"orders.default_phone_country_code", "PHONE_DEFAULT_COUNTRY_CODE", "must be 1 to 3 digits without a leading zero, got %q", code)
+	p.check(c.Orders.MaxLineQuantity >= 0, "orders.max_line_quantity", "ORDER_MAX_LINE_QUANTITY", "must be 0 (disabled) or positive")
+	p.check(c.Orders.MaxLines >= 0, "orders.max_lines", "ORDER_MAX_LINES", "must be 0 (disabled) or positive")
+	p.check(c.Orders.MaxTotal >= 0, "orders.max_total", "ORDER_MAX_TOTAL", "must be 0 (disabled) or positive")
+	p.check(c.Orders.CustomerEditMinutes >= 0, "orders.customer_edit_minutes", "ORDER_CUSTOMER_EDIT_MINUTES",
+		"must be 0 (disabled) or positive, got %d", c.Orders.CustomerEditMinutes)
+	p.check(c.Orders.CodeFormat == "legacy" || c.Orders.CodeFormat == "short", "orders.code_format", "ORDER_CODE_FORMAT",
+		"must be legacy or short, got %q", c.Orders.CodeFormat)
+	p.check(c.Orders.ShortCodeLength >= 4 && c.Orders.ShortCodeLength <= 12, "orders.short_code_length", "ORDER_CODE_SHORT_LENGTH",
+		"must be between 4 and 12, got %d", c.Orders.ShortCodeLength)
+	p.check(c.Orders.TrackingStreamMax >= 0, "orders.tracking_stream_max", "TRACKING_STREAM_MAX_PER_ORDER",
+		"must be 0 (disabled) or positive, got %d", c.Orders.TrackingStreamMax)
+	p.check(c.Orders.MaxNotes > 0, "orders.max_notes", "ORDER_MAX_NOTES", "must be positive, got %d", c.Orders.MaxNotes)
+	_, err := time.LoadLocation(c.Orders.FilterTimezone)
+	p.check(err == nil, "orders.filter_timezone", "ORDER_FILTER_TIMEZONE", "must be an IANA time zone, got %q", c.Orders.FilterTimezone)
+	if tmpl := c.Orders.TrackingURLTemplate; tmpl != "" {
+		u, err := url.Parse(strings.ReplaceAll(tmpl, "{code}", "code"))
+		p.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && strings.Contains(tmpl, "{code}"),
+			"orders.tracking_url_template", "TRACKING_URL_TEMPLATE", "must be an http(s) URL containing {code}, got %q", tmpl)
+	}
+}
+
+func (c *Config) validateProducts(p *problems) {
+	p.check(c.Products.DeleteReferenceDays >= 0, "products.delete_reference_days", "PRODUCT_DELETE_REFERENCE_DAYS",
+		"must be 0 (disabled) or positive, got %d", c.Products.DeleteReferenceDays)
+	p.check(c.Products.ImportMaxBytes > 0, "products.import_max_bytes", "PRODUCT_IMPORT_MAX_BYTES",
+		"must be positive, got %d", c.Products.ImportMaxBytes)
+	p.check(c.Products.ImportMaxRows > 0, "products.import_max_rows", "PRODUCT_IMPORT_MAX_ROWS",
+		"must be positive, got %d", c.Products.ImportMaxRows)
+}
+
+// autoAdvancePattern and transitionPattern only check the shape of the rules;
+// status names and legality are checked against the state machine at startup
+var (
+	autoAdvancePattern = regexp.MustCompile(`^[A-Za-z_]+\s*>\s*[A-Za-z_]+\s*@\s*[a-z_]+$`)
+	transitionPattern  = regexp.MustCompile(`^[A-Za-z_]+\s*>\s*[A-Za-z_]+$`)
+)
+
+func (c *Config) validateRules(p *problems) {
+	rules := []struct {
+		field, env string
+		specs      []string
+		pattern    *regexp.Regexp
+		shape      string
+	}{
+		{"auto_advance.delivery", "AUTO_ADVANCE_DELIVERY", c.AutoAdvance.Delivery, autoAdvancePattern, "FROM>TO@trigger"},
+		{"auto_advance.on_site", "AUTO_ADVANCE_ON_SITE", c.AutoAdvance.OnSite, autoAdvancePattern, "FROM>TO@trigger"},
+		{"transitions.delivery", "ORDER_TRANSITIONS_DELIVERY", c.Transitions.Delivery, transitionPattern, "FROM>TO"},
+		{"transitions.on_site", "ORDER_TRANSITIONS_ON_SITE", c.Transitions.OnSite, transitionPattern, "FROM>TO"},
+		{"transitions.delivery_extra", "ORDER_TRANSITIONS_DELIVERY_EXTRA", c.Transitions.DeliveryExtra, transitionPattern, "FROM>TO"},
+		{"transitions.on_site_extra", "ORDER_TRANSITIONS_ON_SITE_EXTRA", c.Transitions.OnSiteExtra, transitionPattern, "FROM>TO"},
+	}
+	for _, r := range rules {
+		for _, spec := range r.specs {
+			p.check(r.pattern.MatchString(spec), r.field, r.env, "rule %q must have the form %s", spec, r.shape)
+		}
+	}
+}
+
+func (c *Config) validateRateLimit(p *problems) {
+	p.check(c.RateLimit.SearchPerMinute >= 0, "rate_limit.search_per_minute", "RATE_LIMIT_SEARCH_PER_MINUTE",
+		"must be 0 (disabled) or positive, got %d", c.RateLimit.SearchPerMinute)
+}
+
+func (c *Config) validateArchive(p *problems) {
+	p.check(c.Archive.MinAgeDays >= 1, "archive.min_age_days", "ARCHIVE_MIN_AGE_DAYS", "must be at least 1, got %d", c.Archive.MinAgeDays)
+	p.check(c.Archive.BatchSize >= 1 && c.Archive.BatchSize <= 1000, "archive.batch_size", "ARCHIVE_BATCH_SIZE",
+		"must be between 1 and 1000, got %d", c.Archive.BatchSize)
+	p.check(c.Archive.MaxBatches >= 1, "archive.max_batches", "ARCHIVE_MAX_BATCHES", "must be at least 1, got %d", c.Archive.MaxBatches)
+	p.check(c.Archive.BatchPauseMs >= 0, "archive.batch_pause_ms", "ARCHIVE_BATCH_PAUSE_MS", "cannot be negative, got %d", c.Archive.BatchPauseMs)
+}
+
+func (c *Config) validateMedia(p *problems) {
+	for _, host := range c.Media.AllowedHosts {
+		p.check(validAllowedHost(host), "media.allowed_hosts", "PHOTO_URL_ALLOWED_HOSTS",
+			"host %q must be a host name, optionally prefixed with *. and followed by :port", host)
+	}
+}
+
+// validAllowedHost accepts "host", "*.host", "[ipv6]" and any of them with a ":port" suffix
+func validAllowedHost(entry string) bool {
+	host := strings.TrimPrefix(strings.TrimSpace(entry), "*.")
+	if i := strings.LastIndex(host, ":"); i > strings.LastIndex(host, "]") {
+		port := host[i+1:]
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 || strings.HasPrefix(port, "+") {
+			return false
+		}
+		host = host[:i]
+	}
+	if inner, ok := strings.CutPrefix(host, "["); ok {
+		inner, ok = strings.CutSuffix(inner, "]")
+		return ok && inner != "" && strings.Trim(inner, "0123456789abcdefABCDEF:.") == ""
+	}
+	return host != "" && !strings.ContainsAny(host, "/:*@[] ")
+}
+
+func (c *Config) validateCurrency(p *problems) {
+	_, ok := money.Lookup(c.Currency.Code)
+	p.check(ok, "currency.code", "CURRENCY_CODE", "unknown currency code %q", c.Currency.Code)
+	p.check(c.Currency.MinorUnits >= 0 && c.Currency.MinorUnits <= 4, "currency.minor_units", "CURRENCY_MINOR_UNITS",
+		"must be between 0 and 4, got %d", c.Currency.MinorUnits)
+}
+
+func (c *Config) validateIDFormats(p *problems) {
+	formats := []struct{ field, env, pattern string }{
+		{"id_formats.product_id", "ID_FORMAT_PRODUCT", c.IDFormats.ProductID},
+		{"id_formats.category_id", "ID_FORMAT_CATEGORY", c.IDFormats.CategoryID},
+		{"id_formats.tenant_id", "ID_FORMAT_TENANT", c.IDFormats.TenantID},
+		{"id_formats.order_id", "ID_FORMAT_ORDER_ID", c.IDFormats.OrderID},
+		{"id_formats.order_code", "ID_FORMAT_ORDER_CODE", c.IDFormats.OrderCode},
+	}
+	for _, f := range formats {
+		_, err := regexp.Compile(f.pattern)
+		p.check(err == nil, f.field, f.env, "invalid regular expression: %v", err)
+	}
+}
+
+func (c *Config) validateMaintenance(p *problems) {
+	p.check(c.Maintenance.RetryAfterSeconds >= 1, "maintenance.retry_after_seconds", "READ_ONLY_RETRY_AFTER_SECONDS",
+		"must be at least 1, got %d", c.Maintenance.RetryAfterSeconds)
+}
+
+// minAdminTokenLength keeps the admin token out of reach of guessing
+const minAdminTokenLength = 16
+
+func (c *Config) validateAdmin(p *problems) {
+	if c.Admin.Token != "" {
+		p.check(len(c.Admin.Token) >= minAdminTokenLength, "admin.token", "ADMIN_TOKEN",
+			"must be at least %d characters, got %d", minAdminTokenLength, len(c.Admin.Token))
+	}
+}
+
+func (c *Config) validateDashboard(p *problems) {
+	if !c.Dashboard.Enabled {
+		return
+	}
+	_, err := time.LoadLocation(c.Dashboard.Timezone)
+	p.check(err == nil, "dashboard.timezone", "ADMIN_DASHBOARD_TIMEZONE", "must be an IANA time zone, got %q", c.Dashboard.Timezone)
+	p.check(c.Dashboard.LowStockThreshold >= 0, "dashboard.low_stock_threshold", "ADMIN_DASHBOARD_LOW_STOCK_THRESHOLD",
+		"must be 0 or positive, got %d", c.Dashboard.LowStockThreshold)
+	p.check(c.Dashboard.LowStockLimit >= 1 && c.Dashboard.LowStockLimit <= 100, "dashboard.low_stock_limit", "ADMIN_DASHBOARD_LOW_STOCK_LIMIT",
+		"must be between 1 and 100, got %d", c.Dashboard.LowStockLimit)
+	p.check(c.Dashboard.RefreshSeconds >= 0, "dashboard.refresh_seconds", "ADMIN_DASHBOARD_REFRESH_SECONDS",
+		"must be 0 (no polling) or positive, got %d", c.Dashboard.RefreshSeconds)
+}
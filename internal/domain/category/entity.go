@@ -0,0 +1,62 @@
+package category
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/google/uuid"
+)
+
+// MaxNameLength is the maximum number of characters for a category name, as for the product category
+const MaxNameLength = 100
+
+// Category is a product category of a sale point, with its display order.
+// Products still reference categories by name.
+type Category struct {
+	ID          string    `json:"id" bson:"_id"`
+	CompanyID   string    `json:"company_id" bson:"company_id"`
+	SalePointID string    `json:"sale_point_id" bson:"sale_point_id"`
+	Name        string    `json:"name" bson:"name"`         // Unique per sale point, ignoring case
+	Position    int       `json:"position" bson:"position"` // Display order, lowest first; ties sort by name
+	ImageURL    string    `json:"image_url" bson:"image_url"`
+	IsActive    bool      `json:"is_active" bson:"is_active"` // Inactive categories are hidden from menus and cannot be assigned
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// NewCategory creates a new active Category with generated UUID and timestamps
+func NewCategory(companyID, salePointID, name string) *Category {
+	now := time.Now()
+	return &Category{
+		ID:          uuid.New().String(),
+		CompanyID:   companyID,
+		SalePointID: salePointID,
+		Name:        strings.TrimSpace(name),
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Validate performs business logic validation on the Category.
+// Errors are returned as *apperrors.DomainError with the offending field.
+func (c *Category) Validate() error {
+	if c.CompanyID == "" {
+		return apperrors.NewDomainError(ErrInvalidCompanyID, "company_id", nil)
+	}
+	if c.SalePointID == "" {
+		return apperrors.NewDomainError(ErrInvalidSalePointID, "sale_point_id", nil)
+	}
+	if c.Name == "" {
+		return apperrors.NewDomainError(ErrInvalidName, "name", nil)
+	}
+	if utf8.RuneCountInString(c.Name) > MaxNameLength {
+		return apperrors.NewDomainError(ErrNameTooLong, "name", c.Name)
+	}
+	if c.Position < 0 {
+		return apperrors.NewDomainError(ErrNegativePosition, "position", c.Position)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package category
+
+import "errors"
+
+// Domain errors for Category entity
+var (
+	// Validation errors
+	ErrInvalidCompanyID    = errors.New("company_id is required")
+	ErrInvalidSalePointID  = errors.New("sale_point_id is required")
+	ErrInvalidName         = errors.New("category name is required")
+	ErrNameTooLong         = errors.New("category name must be at most 100 characters long")
+	ErrNegativePosition    = errors.New("position cannot be negative")
+	ErrImageHostNotAllowed = errors.New("image URL host is not allowed")
+
+	// Conflict errors
+	ErrDuplicateCategory = errors.New("a category with the same name already exists in the sale point")
+
+	// Not found error
+	ErrCategoryNotFound = errors.New("category not found")
+)
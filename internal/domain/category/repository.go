@@ -0,0 +1,31 @@
+package category
+
+import "context"
+
+// Repository defines the contract for category data operations
+type Repository interface {
+	// Create creates a new category. Create and Update fail with ErrDuplicateCategory when another
+	// category of the sale point has the same name, ignoring case.
+	Create(ctx context.Context, category *Category) error
+
+	// FindByID retrieves a category by its ID
+	FindByID(ctx context.Context, id string) (*Category, error)
+
+	// FindBySalePointID retrieves the categories of a sale point sorted by position, then name
+	FindBySalePointID(ctx context.Context, salePointID string) ([]*Category, error)
+
+	// FindActiveNames retrieves the names of the active categories of a sale point
+	FindActiveNames(ctx context.Context, salePointID string) ([]string, error)
+
+	// Update updates an existing category
+	Update(ctx context.Context, category *Category) error
+
+	// Delete removes a category by ID
+	Delete(ctx context.Context, id string) error
+}
+
+// ProductCategories reads the category names products use, for sale points that define no categories yet
+type ProductCategories interface {
+	// FindProductCategoryNames retrieves the distinct categories of a sale point's live products, sorted by name
+	FindProductCategoryNames(ctx context.Context, salePointID string) ([]string, error)
+}
@@ -0,0 +1,223 @@
+package category
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// ServiceAPI is the set of category use cases consumed by the HTTP handlers
+type ServiceAPI interface {
+	Create(ctx context.Context, input CreateInput) (*Category, error)
+	GetByID(ctx context.Context, id string) (*Category, error)
+	List(ctx context.Context, salePointID string, filters ListFilters) (*Listing, error)
+	Update(ctx context.Context, id string, input UpdateInput) (*Category, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// Compile-time check that Service implements ServiceAPI
+var _ ServiceAPI = (*Service)(nil)
+
+// Service handles business logic for categories
+type Service struct {
+	repo       Repository
+	products   ProductCategories
+	imageHosts util.HostAllowlist
+}
+
+// Option configures optional service behavior
+type Option func(*Service)
+
+// WithProductCategories lists the categories products use when a sale point defines none
+func WithProductCategories(products ProductCategories) Option {
+	return func(s *Service) {
+		s.products = products
+	}
+}
+
+// WithImageHostAllowlist restricts the hosts image URLs may point at
+func WithImageHostAllowlist(allowlist util.HostAllowlist) Option {
+	return func(s *Service) {
+		s.imageHosts = allowlist
+	}
+}
+
+// NewService creates a new category service
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateInput represents input for creating a category
+type CreateInput struct {
+	CompanyID   string
+	SalePointID string
+	Name        string
+	Position    int
+	ImageURL    string
+	IsActive    *bool // Defaults to true
+}
+
+// UpdateInput represents input for updating a category
+type UpdateInput struct {
+	Name     *string
+	Position *int
+	ImageURL *string // An empty URL removes the image
+	IsActive *bool
+}
+
+// ListFilters represents filters for listing the categories of a sale point
+type ListFilters struct {
+	OnlyActive bool
+}
+
+// ListingSource tells where the categories of a listing come from
+type ListingSource string
+
+const (
+	// SourceCategories lists the category documents of the sale point
+	SourceCategories ListingSource = "categories"
+	// SourceProducts lists the distinct categories of the sale point's products, which have no ID,
+	// because the sale point defines no categories
+	SourceProducts ListingSource = "products"
+)
+
+// Listing is the categories of a sale point in display order
+type Listing struct {
+	Categories []*Category
+	Source     ListingSource
+}
+
+// Create creates a new category
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Category, error) {
+	c := NewCategory(input.CompanyID, input.SalePointID, input.Name)
+	c.Position = input.Position
+	c.ImageURL = strings.TrimSpace(input.ImageURL)
+	if input.IsActive != nil {
+		c.IsActive = *input.IsActive
+	}
+
+	if err := s.validate(c); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return c, nil
+}
+
+// GetByID retrieves a category by ID
+func (s *Service) GetByID(ctx context.Context, id string) (*Category, error) {
+	if id == "" {
+		return nil, fmt.Errorf("category ID is required")
+	}
+
+	return s.repo.FindByID(ctx, id)
+}
+
+// List retrieves the categories of a sale point in display order. Sale points that define no
+// categories get the distinct categories of their products instead, sorted by name.
+func (s *Service) List(ctx context.Context, salePointID string, filters ListFilters) (*Listing, error) {
+	if salePointID == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidSalePointID, "sale_point_id", nil)
+	}
+
+	// Inactive categories still count as defined, so hiding them all doesn't bring the fallback back
+	categories, err := s.repo.FindBySalePointID(ctx, salePointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	if len(categories) > 0 || s.products == nil {
+		if filters.OnlyActive {
+			categories = onlyActive(categories)
+		}
+		return &Listing{Categories: categories, Source: SourceCategories}, nil
+	}
+
+	names, err := s.products.FindProductCategoryNames(ctx, salePointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product categories: %w", err)
+	}
+	categories = make([]*Category, len(names))
+	for i, name := range names {
+		categories[i] = &Category{SalePointID: salePointID, Name: name, Position: i, IsActive: true}
+	}
+	return &Listing{Categories: categories, Source: SourceProducts}, nil
+}
+
+// Update updates a category. Renaming it does not rename its products' category;
+// use the category rename of the products for that.
+func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*Category, error) {
+	if id == "" {
+		return nil, fmt.Errorf("category ID is required")
+	}
+
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		c.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Position != nil {
+		c.Position = *input.Position
+	}
+	if input.ImageURL != nil {
+		c.ImageURL = strings.TrimSpace(*input.ImageURL)
+	}
+	if input.IsActive != nil {
+		c.IsActive = *input.IsActive
+	}
+
+	if err := s.validate(c); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	return c, nil
+}
+
+// Delete removes a category. Its products keep their category name.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("category ID is required")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// validate checks the business rules and the image URL host
+func (s *Service) validate(c *Category) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.ImageURL != "" {
+		if host, ok := s.imageHosts.Check(c.ImageURL); !ok {
+			return apperrors.NewDomainError(fmt.Errorf("%w: %s", ErrImageHostNotAllowed, host), "image_url", c.ImageURL)
+		}
+	}
+	return nil
+}
+
+// onlyActive drops the inactive categories
+func onlyActive(categories []*Category) []*Category {
+	active := make([]*Category, 0, len(categories))
+	for _, c := range categories {
+		if c.IsActive {
+			active = append(active, c)
+		}
+	}
+	return active
+}
@@ -0,0 +1,253 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// memoryRepository is an in-memory Repository for service tests
+type memoryRepository struct {
+	mu         sync.Mutex
+	categories map[string]Category // By ID, stored as copies
+}
+
+func newMemoryRepository(categories ...*Category) *memoryRepository {
+	r := &memoryRepository{categories: make(map[string]Category)}
+	for _, c := range categories {
+		r.categories[c.ID] = *c
+	}
+	return r
+}
+
+// duplicate mimics the case-insensitive unique name index
+func (r *memoryRepository) duplicate(c *Category) bool {
+	for id, stored := range r.categories {
+		if id != c.ID && stored.SalePointID == c.SalePointID && strings.EqualFold(stored.Name, c.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryRepository) Create(ctx context.Context, c *Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.duplicate(c) {
+		return ErrDuplicateCategory
+	}
+	r.categories[c.ID] = *c
+	return nil
+}
+
+func (r *memoryRepository) FindByID(ctx context.Context, id string) (*Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.categories[id]
+	if !ok {
+		return nil, ErrCategoryNotFound
+	}
+	return &c, nil
+}
+
+func (r *memoryRepository) FindBySalePointID(ctx context.Context, salePointID string) ([]*Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	categories := []*Category{}
+	for _, c := range r.categories {
+		if c.SalePointID == salePointID {
+			categories = append(categories, &c)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Position != categories[j].Position {
+			return categories[i].Position < categories[j].Position
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+func (r *memoryRepository) FindActiveNames(ctx context.Context, salePointID string) ([]string, error) {
+	categories, _ := r.FindBySalePointID(ctx, salePointID)
+	var names []string
+	for _, c := range onlyActive(categories) {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+func (r *memoryRepository) Update(ctx context.Context, c *Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.categories[c.ID]; !ok {
+		return ErrCategoryNotFound
+	}
+	if r.duplicate(c) {
+		return ErrDuplicateCategory
+	}
+	r.categories[c.ID] = *c
+	return nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.categories[id]; !ok {
+		return ErrCategoryNotFound
+	}
+	delete(r.categories, id)
+	return nil
+}
+
+// productCategories maps sale point IDs to the categories of their products
+type productCategories map[string][]string
+
+func (p productCategories) FindProductCategoryNames(ctx context.Context, salePointID string) ([]string, error) {
+	return p[salePointID], nil
+}
+
+func TestCreateCategory(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemoryRepository(), WithImageHostAllowlist(util.NewHostAllowlist([]string{"cdn.example.com"})))
+	inactive := false
+
+	tests := []struct {
+		name    string
+		input   CreateInput
+		wantErr error
+	}{
+		{"valid", CreateInput{CompanyID: "company-1", SalePointID: "sale-point-1", Name: " Pizzas ", Position: 2, ImageURL: "https://cdn.example.com/pizzas.png"}, nil},
+		{"same name in another sale point", CreateInput{CompanyID: "company-1", SalePointID: "sale-point-2", Name: "Pizzas", IsActive: &inactive}, nil},
+		{"duplicate name ignoring case", CreateInput{CompanyID: "company-1", SalePointID: "sale-point-1", Name: "PIZZAS"}, ErrDuplicateCategory},
+		{"missing sale point", CreateInput{CompanyID: "company-1", Name: "Drinks"}, ErrInvalidSalePointID},
+		{"blank name", CreateInput{CompanyID: "company-1", SalePointID: "sale-point-1", Name: "  "}, ErrInvalidName},
+		{"name too long", CreateInput{CompanyID: "company-1", SalePointID: "sale-point-1", Name: strings.Repeat("a", 101)}, ErrNameTooLong},
+		{"negative position", CreateInput{CompanyID: "company-1", SalePointID: "sale-point-1", Name: "Drinks", Position: -1}, ErrNegativePosition},
+		{"image host not allowed", CreateInput{CompanyID: "company-1", SalePointID: "sale-point-1", Name: "Drinks", ImageURL: "https://evil.example.org/a.png"}, ErrImageHostNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := svc.Create(ctx, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if c.ID == "" || c.Name != strings.TrimSpace(tt.input.Name) {
+				t.Errorf("created %+v, want an ID and the trimmed name", c)
+			}
+			if wantActive := tt.input.IsActive == nil || *tt.input.IsActive; c.IsActive != wantActive {
+				t.Errorf("is_active = %v, want %v", c.IsActive, wantActive)
+			}
+		})
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	ctx := context.Background()
+	pizzas := NewCategory("company-1", "sale-point-1", "Pizzas")
+	drinks := NewCategory("company-1", "sale-point-1", "Drinks")
+	drinks.ImageURL = "https://cdn.example.com/drinks.png"
+	svc := NewService(newMemoryRepository(pizzas, drinks))
+
+	position, inactive, noImage := 5, false, ""
+	updated, err := svc.Update(ctx, drinks.ID, UpdateInput{Position: &position, IsActive: &inactive, ImageURL: &noImage})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Position != 5 || updated.IsActive || updated.ImageURL != "" || updated.Name != "Drinks" {
+		t.Errorf("updated = %+v, want position 5, inactive, no image and the name kept", updated)
+	}
+
+	taken := "pizzas"
+	if _, err := svc.Update(ctx, drinks.ID, UpdateInput{Name: &taken}); !errors.Is(err, ErrDuplicateCategory) {
+		t.Errorf("rename to a taken name error = %v, want %v", err, ErrDuplicateCategory)
+	}
+	if _, err := svc.Update(ctx, "missing", UpdateInput{Position: &position}); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("update of a missing category error = %v, want %v", err, ErrCategoryNotFound)
+	}
+}
+
+func TestListCategories(t *testing.T) {
+	ctx := context.Background()
+	desserts := NewCategory("company-1", "sale-point-1", "Desserts")
+	desserts.Position = 2
+	drinks := NewCategory("company-1", "sale-point-1", "Drinks")
+	drinks.Position = 1
+	pizzas := NewCategory("company-1", "sale-point-1", "Pizzas")
+	pizzas.Position = 1
+	hidden := NewCategory("company-1", "sale-point-3", "Hidden")
+	hidden.IsActive = false
+	repo := newMemoryRepository(desserts, drinks, pizzas, hidden)
+	products := productCategories{
+		"sale-point-1": {"Burgers"},
+		"sale-point-2": {"Burgers", "Drinks"},
+		"sale-point-3": {"Burgers"},
+	}
+
+	names := func(l *Listing) []string {
+		var names []string
+		for _, c := range l.Categories {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		salePointID string
+		filters     ListFilters
+		wantNames   []string
+		wantSource  ListingSource
+	}{
+		{"display order", []Option{WithProductCategories(products)}, "sale-point-1", ListFilters{}, []string{"Drinks", "Pizzas", "Desserts"}, SourceCategories},
+		{"fallback to product categories", []Option{WithProductCategories(products)}, "sale-point-2", ListFilters{}, []string{"Burgers", "Drinks"}, SourceProducts},
+		{"inactive categories keep the fallback off", []Option{WithProductCategories(products)}, "sale-point-3", ListFilters{OnlyActive: true}, nil, SourceCategories},
+		{"inactive categories are listed", []Option{WithProductCategories(products)}, "sale-point-3", ListFilters{}, []string{"Hidden"}, SourceCategories},
+		{"no fallback configured", nil, "sale-point-2", ListFilters{}, nil, SourceCategories},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listing, err := NewService(repo, tt.opts...).List(ctx, tt.salePointID, tt.filters)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := names(listing); strings.Join(got, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("names = %v, want %v", got, tt.wantNames)
+			}
+			if listing.Source != tt.wantSource {
+				t.Errorf("source = %q, want %q", listing.Source, tt.wantSource)
+			}
+		})
+	}
+
+	if _, err := NewService(repo).List(ctx, "", ListFilters{}); !errors.Is(err, ErrInvalidSalePointID) {
+		t.Errorf("List() without sale point error = %v, want %v", err, ErrInvalidSalePointID)
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	ctx := context.Background()
+	pizzas := NewCategory("company-1", "sale-point-1", "Pizzas")
+	svc := NewService(newMemoryRepository(pizzas))
+
+	if err := svc.Delete(ctx, pizzas.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := svc.GetByID(ctx, pizzas.ID); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("GetByID() after delete error = %v, want %v", err, ErrCategoryNotFound)
+	}
+	if err := svc.Delete(ctx, pizzas.ID); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("second Delete() error = %v, want %v", err, ErrCategoryNotFound)
+	}
+}
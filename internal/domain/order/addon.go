@@ -0,0 +1,79 @@
+package order
+
+import (
+	"fmt"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// OrderProductAddon is an addon selected for one unit of an order line, e.g. extra cheese on a burger
+type OrderProductAddon struct {
+	ID       string `json:"id" bson:"id"`
+	Name     string `json:"name" bson:"name"`
+	Price    int64  `json:"price" bson:"price"`       // In cents
+	Quantity int    `json:"quantity" bson:"quantity"` // Per unit of the line
+}
+
+// AddonsPrice returns the price of the line's addons for one unit, in cents
+func (p OrderProduct) AddonsPrice() int64 {
+	var total int64
+	for _, a := range p.Addons {
+		total += a.Price * int64(a.Quantity)
+	}
+	return total
+}
+
+// UnitPrice returns the price of one unit of the line, addons included, in cents
+func (p OrderProduct) UnitPrice() int64 {
+	return p.Price + p.AddonsPrice()
+}
+
+// LineTotal returns the price of the whole line, addons included, in cents
+func (p OrderProduct) LineTotal() int64 {
+	return p.UnitPrice() * int64(p.Quantity)
+}
+
+// validateAddons checks the addons of the line at index i
+func (p OrderProduct) validateAddons(i int) error {
+	for j, a := range p.Addons {
+		field := fmt.Sprintf("products[%d].addons[%d]", i, j)
+		if a.ID == "" {
+			return apperrors.NewIndexedDomainError(ErrInvalidAddonID, field+".id", i, a.ID)
+		}
+		if a.Name == "" {
+			return apperrors.NewIndexedDomainError(ErrInvalidAddonName, field+".name", i, a.Name)
+		}
+		if a.Price < 0 {
+			return apperrors.NewIndexedDomainError(ErrInvalidAddonPrice, field+".price", i, a.Price)
+		}
+		if a.Quantity <= 0 {
+			return apperrors.NewIndexedDomainError(ErrInvalidAddonQuantity, field+".quantity", i, a.Quantity)
+		}
+		for k := j + 1; k < len(p.Addons); k++ {
+			if p.Addons[k].ID == a.ID {
+				return apperrors.NewIndexedDomainError(ErrDuplicateAddon, fmt.Sprintf("products[%d].addons[%d].id", i, k), i, a.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// sameAddons reports whether both lines carry the same addons, in any order
+func sameAddons(a, b OrderProduct) bool {
+	if len(a.Addons) != len(b.Addons) {
+		return false
+	}
+	for _, x := range a.Addons {
+		found := false
+		for _, y := range b.Addons {
+			if x == y {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
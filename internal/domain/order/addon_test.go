@@ -0,0 +1,132 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+func TestLineTotalWithAddons(t *testing.T) {
+	cheese := OrderProductAddon{ID: "a1", Name: "Cheese", Price: 300, Quantity: 1}
+	bacon := OrderProductAddon{ID: "a2", Name: "Bacon", Price: 450, Quantity: 2}
+
+	tests := []struct {
+		name       string
+		line       OrderProduct
+		wantAddons int64
+		wantUnit   int64
+		wantTotal  int64
+	}{
+		{"no addons", OrderProduct{Price: 1000, Quantity: 3}, 0, 1000, 3000},
+		{"one addon", OrderProduct{Price: 1000, Quantity: 1, Addons: []OrderProductAddon{cheese}}, 300, 1300, 1300},
+		{"addons repeat per unit", OrderProduct{Price: 1000, Quantity: 3, Addons: []OrderProductAddon{cheese}}, 300, 1300, 3900},
+		{"addon quantity", OrderProduct{Price: 1000, Quantity: 2, Addons: []OrderProductAddon{cheese, bacon}}, 1200, 2200, 4400},
+		{"free product with addons", OrderProduct{Price: 0, Quantity: 2, Addons: []OrderProductAddon{bacon}}, 900, 900, 1800},
+		{"free addon", OrderProduct{Price: 1000, Quantity: 1, Addons: []OrderProductAddon{{ID: "a3", Name: "Napkins", Price: 0, Quantity: 5}}}, 0, 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.line.AddonsPrice(); got != tt.wantAddons {
+				t.Errorf("AddonsPrice = %d, want %d", got, tt.wantAddons)
+			}
+			if got := tt.line.UnitPrice(); got != tt.wantUnit {
+				t.Errorf("UnitPrice = %d, want %d", got, tt.wantUnit)
+			}
+			if got := tt.line.LineTotal(); got != tt.wantTotal {
+				t.Errorf("LineTotal = %d, want %d", got, tt.wantTotal)
+			}
+		})
+	}
+
+	o := &Order{Products: []OrderProduct{tests[2].line, tests[3].line}}
+	o.CalculateTotal()
+	if o.Subtotal != 8300 || o.Total != 8300 {
+		t.Errorf("order subtotal/total = %d/%d, want 8300 with the addons", o.Subtotal, o.Total)
+	}
+}
+
+func TestValidateAddons(t *testing.T) {
+	addon := func(id, name string, price int64, quantity int) OrderProductAddon {
+		return OrderProductAddon{ID: id, Name: name, Price: price, Quantity: quantity}
+	}
+
+	tests := []struct {
+		name      string
+		addons    []OrderProductAddon
+		wantErr   error
+		wantField string
+	}{
+		{"valid", []OrderProductAddon{addon("a1", "Cheese", 300, 1), addon("a2", "Bacon", 0, 2)}, nil, ""},
+		{"missing id", []OrderProductAddon{addon("", "Cheese", 300, 1)}, ErrInvalidAddonID, "products[1].addons[0].id"},
+		{"missing name", []OrderProductAddon{addon("a1", "Cheese", 300, 1), addon("a2", "", 300, 1)}, ErrInvalidAddonName, "products[1].addons[1].name"},
+		{"negative price", []OrderProductAddon{addon("a1", "Cheese", -1, 1)}, ErrInvalidAddonPrice, "products[1].addons[0].price"},
+		{"zero quantity", []OrderProductAddon{addon("a1", "Cheese", 300, 0)}, ErrInvalidAddonQuantity, "products[1].addons[0].quantity"},
+		{"duplicate id", []OrderProductAddon{addon("a1", "Cheese", 300, 1), addon("a2", "Bacon", 450, 1), addon("a1", "Cheese", 300, 2)}, ErrDuplicateAddon, "products[1].addons[2].id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := onSiteInput([]OrderProduct{
+				{ID: "p1", Name: "Soda", Price: 3000, Quantity: 1},
+				{ID: "p2", Name: "Burger", Price: 10000, Quantity: 2, Addons: tt.addons},
+			})
+			repo := newMemoryRepository()
+
+			o, err := NewService(repo).Create(context.Background(), input)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if o.Subtotal != 3000+2*(10000+300+0) {
+					t.Errorf("subtotal = %d, want the addons included", o.Subtotal)
+				}
+				return
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.Is(err, tt.wantErr) || !errors.As(err, &domainErr) || domainErr.Field != tt.wantField {
+				t.Fatalf("err = %v, want %v on %s", err, tt.wantErr, tt.wantField)
+			}
+			if domainErr.Index == nil || *domainErr.Index != 1 {
+				t.Errorf("error index = %v, want the line 1", domainErr.Index)
+			}
+			if len(repo.orders) != 0 {
+				t.Error("order stored with invalid addons")
+			}
+		})
+	}
+}
+
+func TestSameProductWithOtherAddonsIsAnotherLine(t *testing.T) {
+	cheese := OrderProductAddon{ID: "a1", Name: "Cheese", Price: 300, Quantity: 1}
+	bacon := OrderProductAddon{ID: "a2", Name: "Bacon", Price: 450, Quantity: 1}
+
+	tests := []struct {
+		name    string
+		first   []OrderProductAddon
+		second  []OrderProductAddon
+		wantErr bool
+	}{
+		{"plain and with cheese", nil, []OrderProductAddon{cheese}, false},
+		{"cheese and bacon", []OrderProductAddon{cheese}, []OrderProductAddon{bacon}, false},
+		{"same addons", []OrderProductAddon{cheese, bacon}, []OrderProductAddon{cheese, bacon}, true},
+		{"same addons in another order", []OrderProductAddon{cheese, bacon}, []OrderProductAddon{bacon, cheese}, true},
+		{"both plain", nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Order{SaleType: SaleTypeOnSite, TableNumber: new(int), Products: []OrderProduct{
+				{ID: "p1", Name: "Burger", Price: 10000, Quantity: 1, Addons: tt.first},
+				{ID: "p1", Name: "Burger", Price: 10000, Quantity: 1, Addons: tt.second},
+			}}
+			*o.TableNumber = 1
+			err := o.Validate()
+			if gotErr := errors.Is(err, ErrDuplicateProduct); gotErr != tt.wantErr {
+				t.Errorf("err = %v, want duplicate %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ArchivePolicy bounds archive runs
+type ArchivePolicy struct {
+	MinAge     time.Duration // Safety floor: only orders older than this can be archived
+	BatchSize  int           // Orders moved per batch
+	MaxBatches int           // Batches processed per request
+	Pause      time.Duration // Pause between batches to limit database load
+}
+
+// DefaultArchivePolicy is used when no policy is configured
+var DefaultArchivePolicy = ArchivePolicy{
+	MinAge:     90 * 24 * time.Hour,
+	BatchSize:  500,
+	MaxBatches: 10,
+	Pause:      100 * time.Millisecond,
+}
+
+// ArchiveInput represents input for archiving old terminal orders
+type ArchiveInput struct {
+	OlderThan time.Time
+	DryRun    bool
+	After     *BatchCursor // Resume after this order; nil starts from the oldest match
+}
+
+// ArchiveResult reports the progress of an archive run
+type ArchiveResult struct {
+	DryRun     bool
+	OlderThan  time.Time
+	Batches    int
+	Scanned    int          // Terminal orders older than the cutoff that were visited
+	Archived   int64        // Orders moved to the archive (always 0 on dry runs)
+	NextCursor *BatchCursor // nil when there are no more orders to archive
+}
+
+// Archive moves DELIVERED and CANCELLED orders created before the cutoff to the archive,
+// processing at most MaxBatches batches. Call again with NextCursor to continue.
+func (s *Service) Archive(ctx context.Context, input ArchiveInput) (*ArchiveResult, error) {
+	if input.OlderThan.After(time.Now().Add(-s.archive.MinAge)) {
+		return nil, ErrArchiveCutoffTooRecent
+	}
+
+	result := &ArchiveResult{
+		DryRun:    input.DryRun,
+		OlderThan: input.OlderThan,
+	}
+
+	after := input.After
+	for result.Batches < s.archive.MaxBatches {
+		if result.Batches > 0 && s.archive.Pause > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(s.archive.Pause):
+			}
+		}
+
+		orders, err := s.repo.FindArchivable(ctx, input.OlderThan, after, s.archive.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get orders to archive: %w", err)
+		}
+		result.Batches++
+		result.Scanned += len(orders)
+
+		if !input.DryRun && len(orders) > 0 {
+			archived, err := s.repo.ArchiveOrders(ctx, orders)
+			if err != nil {
+				return nil, fmt.Errorf("failed to archive orders: %w", err)
+			}
+			result.Archived += archived
+		}
+
+		if len(orders) < s.archive.BatchSize {
+			after = nil
+			break
+		}
+		last := orders[len(orders)-1]
+		after = &BatchCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	result.NextCursor = after
+	return result, nil
+}
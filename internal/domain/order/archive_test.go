@@ -0,0 +1,120 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// agedOrder is a stored order created days ago with the given status
+func agedOrder(i int, status OrderStatus, days int) *Order {
+	o := storedOrder(i, 23333, 0, 23333, nil)
+	o.Status = status
+	o.CreatedAt = time.Now().AddDate(0, 0, -days)
+	return o
+}
+
+func TestArchive(t *testing.T) {
+	policy := ArchivePolicy{MinAge: 90 * 24 * time.Hour, BatchSize: 2, MaxBatches: 2}
+	cutoff := time.Now().AddDate(0, 0, -100)
+
+	tests := []struct {
+		name         string
+		olderThan    time.Time
+		dryRun       bool
+		wantErr      error
+		wantScanned  int
+		wantArchived int64
+		wantBatches  int
+		wantMore     bool
+	}{
+		{"cutoff inside the safety floor", time.Now().AddDate(0, 0, -30), false, ErrArchiveCutoffTooRecent, 0, 0, 0, false},
+		{"dry run moves nothing", cutoff, true, nil, 4, 0, 2, true},
+		{"stops after the batch limit", cutoff, false, nil, 4, 4, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(
+				agedOrder(1, StatusDelivered, 400),
+				agedOrder(2, StatusCancelled, 300),
+				agedOrder(3, StatusDelivered, 200),
+				agedOrder(4, StatusDelivered, 150),
+				agedOrder(5, StatusCancelled, 120),
+				agedOrder(6, StatusInProgress, 500), // Not terminal
+				agedOrder(7, StatusDelivered, 50),   // After the cutoff
+			)
+			svc := NewService(repo, WithArchivePolicy(policy))
+
+			result, err := svc.Archive(context.Background(), ArchiveInput{OlderThan: tt.olderThan, DryRun: tt.dryRun})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if result.Scanned != tt.wantScanned || result.Archived != tt.wantArchived || result.Batches != tt.wantBatches {
+				t.Errorf("scanned/archived/batches = %d/%d/%d, want %d/%d/%d",
+					result.Scanned, result.Archived, result.Batches, tt.wantScanned, tt.wantArchived, tt.wantBatches)
+			}
+			if (result.NextCursor != nil) != tt.wantMore {
+				t.Errorf("next cursor = %v, want more %v", result.NextCursor, tt.wantMore)
+			}
+			if got := int64(len(repo.archived)); got != tt.wantArchived {
+				t.Errorf("archived orders = %d, want %d", got, tt.wantArchived)
+			}
+		})
+	}
+}
+
+func TestArchiveResumesFromTheCursor(t *testing.T) {
+	repo := newMemoryRepository(
+		agedOrder(1, StatusDelivered, 400),
+		agedOrder(2, StatusCancelled, 300),
+		agedOrder(3, StatusDelivered, 200),
+		agedOrder(4, StatusInProgress, 200),
+	)
+	svc := NewService(repo, WithArchivePolicy(ArchivePolicy{MinAge: 90 * 24 * time.Hour, BatchSize: 2, MaxBatches: 1}))
+	input := ArchiveInput{OlderThan: time.Now().AddDate(0, 0, -100)}
+
+	first, err := svc.Archive(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Archived != 2 || first.NextCursor == nil {
+		t.Fatalf("first run archived %d, next %v, want 2 and a cursor", first.Archived, first.NextCursor)
+	}
+
+	input.After = first.NextCursor
+	second, err := svc.Archive(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Archived != 1 || second.NextCursor != nil {
+		t.Errorf("second run archived %d, next %v, want 1 and no cursor", second.Archived, second.NextCursor)
+	}
+	if repo.stored(agedOrder(4, StatusInProgress, 0).ID) == nil {
+		t.Error("an open order was archived")
+	}
+}
+
+func TestGetByCodeFallsBackToTheArchive(t *testing.T) {
+	old := agedOrder(1, StatusDelivered, 400)
+	repo := newMemoryRepository(old)
+	svc := NewService(repo, WithArchivePolicy(ArchivePolicy{MinAge: 90 * 24 * time.Hour, BatchSize: 10, MaxBatches: 1}))
+
+	if _, err := svc.Archive(context.Background(), ArchiveInput{OlderThan: time.Now().AddDate(0, 0, -100)}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.GetByCode(context.Background(), old.Code)
+	if err != nil {
+		t.Fatalf("archived order not found by code: %v", err)
+	}
+	if got.ID != old.ID {
+		t.Errorf("found order %s, want %s", got.ID, old.ID)
+	}
+	if _, err := svc.GetByCode(context.Background(), "ORD-MISSING"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("unknown code err = %v, want %v", err, ErrOrderNotFound)
+	}
+}
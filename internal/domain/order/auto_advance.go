@@ -0,0 +1,116 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AutoAdvanceTrigger identifies the mutation that can fire an auto-advance rule
+type AutoAdvanceTrigger string
+
+const (
+	// TriggerPaymentReceipt fires when a payment receipt is attached to the order
+	TriggerPaymentReceipt AutoAdvanceTrigger = "payment_receipt"
+)
+
+// IsValidAutoAdvanceTrigger checks if the trigger is supported
+func IsValidAutoAdvanceTrigger(trigger AutoAdvanceTrigger) bool {
+	return trigger == TriggerPaymentReceipt
+}
+
+// AutoAdvanceRule moves an order of a sale type from one status to another when its trigger fires
+type AutoAdvanceRule struct {
+	SaleType SaleType
+	From     OrderStatus
+	To       OrderStatus
+	Trigger  AutoAdvanceTrigger
+}
+
+// ParseAutoAdvanceRule parses a rule in the form "FROM>TO@trigger" for the given sale type,
+// e.g. "CREATED>VERIFIED@payment_receipt". The transition must be allowed by the sale type's state machine.
+func ParseAutoAdvanceRule(saleType SaleType, spec string, transitions Transitions) (AutoAdvanceRule, error) {
+	transition, trigger, ok := strings.Cut(strings.TrimSpace(spec), "@")
+	if !ok {
+		return AutoAdvanceRule{}, fmt.Errorf("auto-advance rule %q: missing @trigger", spec)
+	}
+	from, to, ok := strings.Cut(transition, ">")
+	if !ok {
+		return AutoAdvanceRule{}, fmt.Errorf("auto-advance rule %q: expected FROM>TO", spec)
+	}
+
+	rule := AutoAdvanceRule{
+		SaleType: saleType,
+		From:     OrderStatus(strings.ToUpper(strings.TrimSpace(from))),
+		To:       OrderStatus(strings.ToUpper(strings.TrimSpace(to))),
+		Trigger:  AutoAdvanceTrigger(strings.ToLower(strings.TrimSpace(trigger))),
+	}
+
+	if saleType != SaleTypeDelivery && saleType != SaleTypeOnSite {
+		return AutoAdvanceRule{}, fmt.Errorf("auto-advance rule %q: %w", spec, ErrInvalidSaleType)
+	}
+	if !IsValidAutoAdvanceTrigger(rule.Trigger) {
+		return AutoAdvanceRule{}, fmt.Errorf("auto-advance rule %q: unknown trigger %q", spec, rule.Trigger)
+	}
+
+	// Reuse the transition table so a rule can never encode an illegal transition
+	probe := Order{Status: rule.From}
+	if !probe.IsValidStatus(rule.From) || !probe.IsValidStatus(rule.To) {
+		return AutoAdvanceRule{}, fmt.Errorf("auto-advance rule %q: %w", spec, ErrInvalidStatus)
+	}
+	if !transitions.Allows(rule.From, rule.To) {
+		return AutoAdvanceRule{}, fmt.Errorf("auto-advance rule %q: %w", spec, ErrInvalidStatusTransition)
+	}
+
+	return rule, nil
+}
+
+// ParseAutoAdvanceRules parses the rule specs configured for each sale type
+func ParseAutoAdvanceRules(specs map[SaleType][]string, machine StateMachine) ([]AutoAdvanceRule, error) {
+	var rules []AutoAdvanceRule
+	for _, saleType := range []SaleType{SaleTypeDelivery, SaleTypeOnSite} {
+		for _, spec := range specs[saleType] {
+			rule, err := ParseAutoAdvanceRule(saleType, spec, machine.For(saleType))
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// StatusGuard vetoes a status change the state machine allows, e.g. for business rules
+type StatusGuard func(o *Order, to OrderStatus) error
+
+// applyAutoAdvance applies matching rules after a mutation fired the trigger.
+// Rules are re-evaluated after each transition so chains such as CREATED→VERIFIED→IN_PROGRESS
+// are followed; each step is checked against the state machine and the guard (when not nil)
+// and recorded with the system actor.
+func applyAutoAdvance(o *Order, rules []AutoAdvanceRule, transitions Transitions, guard StatusGuard, trigger AutoAdvanceTrigger) bool {
+	advanced := false
+	// Every step moves forward, so a chain can never be longer than the number of statuses
+	for range AllStatuses {
+		rule, ok := findAutoAdvanceRule(o, rules, transitions, guard, trigger)
+		if !ok {
+			break
+		}
+		o.changeStatus(rule.To, ActorSystem, time.Now())
+		advanced = true
+	}
+	return advanced
+}
+
+// findAutoAdvanceRule returns the first rule applicable to the order in its current status
+func findAutoAdvanceRule(o *Order, rules []AutoAdvanceRule, transitions Transitions, guard StatusGuard, trigger AutoAdvanceTrigger) (AutoAdvanceRule, bool) {
+	for _, rule := range rules {
+		if rule.SaleType != o.SaleType || rule.Trigger != trigger || rule.From != o.Status || !transitions.Allows(o.Status, rule.To) {
+			continue
+		}
+		if guard != nil && guard(o, rule.To) != nil {
+			continue
+		}
+		return rule, true
+	}
+	return AutoAdvanceRule{}, false
+}
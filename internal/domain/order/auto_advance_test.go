@@ -0,0 +1,146 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseAutoAdvanceRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		saleType SaleType
+		spec     string
+		want     AutoAdvanceRule
+		wantErr  error // nil with wantFail for errors without a sentinel
+		wantFail bool
+	}{
+		{"valid", SaleTypeOnSite, "CREATED>VERIFIED@payment_receipt", AutoAdvanceRule{SaleTypeOnSite, StatusCreated, StatusVerified, TriggerPaymentReceipt}, nil, false},
+		{"case and spaces", SaleTypeDelivery, " created > verified @ PAYMENT_RECEIPT ", AutoAdvanceRule{SaleTypeDelivery, StatusCreated, StatusVerified, TriggerPaymentReceipt}, nil, false},
+		{"missing trigger", SaleTypeOnSite, "CREATED>VERIFIED", AutoAdvanceRule{}, nil, true},
+		{"missing arrow", SaleTypeOnSite, "CREATED@payment_receipt", AutoAdvanceRule{}, nil, true},
+		{"unknown trigger", SaleTypeOnSite, "CREATED>VERIFIED@timer", AutoAdvanceRule{}, nil, true},
+		{"unknown status", SaleTypeOnSite, "CREATED>PAID@payment_receipt", AutoAdvanceRule{}, ErrInvalidStatus, true},
+		{"illegal transition", SaleTypeOnSite, "DELIVERED>CREATED@payment_receipt", AutoAdvanceRule{}, ErrInvalidStatusTransition, true},
+		{"not in the sale type's machine", SaleTypeOnSite, "IN_PROGRESS>OUT_FOR_DELIVERY@payment_receipt", AutoAdvanceRule{}, ErrInvalidStatusTransition, true},
+		{"unknown sale type", "PICKUP", "CREATED>VERIFIED@payment_receipt", AutoAdvanceRule{}, ErrInvalidSaleType, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAutoAdvanceRule(tt.saleType, tt.spec, DefaultTransitionsFor(tt.saleType))
+			if (err != nil) != tt.wantFail || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v (fail %v)", err, tt.wantErr, tt.wantFail)
+			}
+			if got != tt.want {
+				t.Errorf("rule = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAutoAdvanceRulesStopsAtTheFirstError(t *testing.T) {
+	rules, err := ParseAutoAdvanceRules(map[SaleType][]string{
+		SaleTypeOnSite:   {"CREATED>VERIFIED@payment_receipt", "VERIFIED>IN_PROGRESS@payment_receipt"},
+		SaleTypeDelivery: {"CREATED>VERIFIED@payment_receipt"},
+	}, StateMachine{})
+	if err != nil || len(rules) != 3 {
+		t.Fatalf("rules = %v, err = %v, want 3 rules", rules, err)
+	}
+
+	if _, err := ParseAutoAdvanceRules(map[SaleType][]string{SaleTypeOnSite: {"CREATED>VERIFIED@payment_receipt", "bogus"}}, StateMachine{}); err == nil {
+		t.Error("invalid spec was accepted")
+	}
+}
+
+func TestApplyAutoAdvance(t *testing.T) {
+	onSiteVerify := AutoAdvanceRule{SaleTypeOnSite, StatusCreated, StatusVerified, TriggerPaymentReceipt}
+	onSiteStart := AutoAdvanceRule{SaleTypeOnSite, StatusVerified, StatusInProgress, TriggerPaymentReceipt}
+	veto := func(o *Order, to OrderStatus) error {
+		if to == StatusInProgress {
+			return ErrPaymentNotConfirmed
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		saleType    SaleType
+		status      OrderStatus
+		rules       []AutoAdvanceRule
+		guard       StatusGuard
+		trigger     AutoAdvanceTrigger
+		want        OrderStatus
+		wantHistory int
+	}{
+		{"single step", SaleTypeOnSite, StatusCreated, []AutoAdvanceRule{onSiteVerify}, nil, TriggerPaymentReceipt, StatusVerified, 1},
+		{"chain", SaleTypeOnSite, StatusCreated, []AutoAdvanceRule{onSiteStart, onSiteVerify}, nil, TriggerPaymentReceipt, StatusInProgress, 2},
+		{"guard stops the chain", SaleTypeOnSite, StatusCreated, []AutoAdvanceRule{onSiteVerify, onSiteStart}, veto, TriggerPaymentReceipt, StatusVerified, 1},
+		{"other sale type", SaleTypeDelivery, StatusCreated, []AutoAdvanceRule{onSiteVerify}, nil, TriggerPaymentReceipt, StatusCreated, 0},
+		{"other status", SaleTypeOnSite, StatusInProgress, []AutoAdvanceRule{onSiteVerify}, nil, TriggerPaymentReceipt, StatusInProgress, 0},
+		{"other trigger", SaleTypeOnSite, StatusCreated, []AutoAdvanceRule{onSiteVerify}, nil, "manual", StatusCreated, 0},
+		{"no rules", SaleTypeOnSite, StatusCreated, nil, nil, TriggerPaymentReceipt, StatusCreated, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := validOrder(1)
+			o.SaleType = tt.saleType
+			o.Status = tt.status
+
+			advanced := applyAutoAdvance(o, tt.rules, DefaultTransitionsFor(tt.saleType), tt.guard, tt.trigger)
+			if o.Status != tt.want {
+				t.Errorf("status = %s, want %s", o.Status, tt.want)
+			}
+			if advanced != (tt.wantHistory > 0) {
+				t.Errorf("advanced = %v", advanced)
+			}
+			if len(o.StatusHistory) != tt.wantHistory {
+				t.Fatalf("history = %+v, want %d entries", o.StatusHistory, tt.wantHistory)
+			}
+			for _, change := range o.StatusHistory {
+				if change.Actor != ActorSystem {
+					t.Errorf("actor = %q, want %q", change.Actor, ActorSystem)
+				}
+			}
+		})
+	}
+}
+
+func TestPartialUpdateReceiptAutoAdvances(t *testing.T) {
+	rules := []AutoAdvanceRule{{SaleTypeOnSite, StatusCreated, StatusVerified, TriggerPaymentReceipt}}
+
+	tests := []struct {
+		name    string
+		receipt string
+		want    OrderStatus
+	}{
+		{"receipt attached", "https://cdn.example.com/receipt.jpg", StatusVerified},
+		{"receipt removed", "", StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithAutoAdvanceRules(rules))
+			o, err := svc.Create(context.Background(), onSiteInput(lines(1, 1, 100)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := svc.PartialUpdate(context.Background(), o.Code, PartialUpdateInput{PaymentReceiptURL: &tt.receipt}); err != nil {
+				t.Fatal(err)
+			}
+			stored := repo.stored(o.ID)
+			if stored.Status != tt.want {
+				t.Errorf("status = %s, want %s", stored.Status, tt.want)
+			}
+			if tt.want != StatusCreated {
+				last := stored.StatusHistory[len(stored.StatusHistory)-1]
+				if last.To != tt.want || last.Actor != ActorSystem {
+					t.Errorf("last change = %+v, want %s by %s", last, tt.want, ActorSystem)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package order
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxBatchStatusCodes bounds the orders of one batch status update
+const MaxBatchStatusCodes = 100
+
+// BatchStatusInput moves several orders to the same status
+type BatchStatusInput struct {
+	Codes  []string
+	Status OrderStatus
+}
+
+// BatchStatusResult is the outcome of the status update of one order in a batch
+type BatchStatusResult struct {
+	Code  string
+	Order *Order // Nil when the update failed
+	Err   error
+}
+
+// BatchUpdateStatus applies the status to every order independently: an unknown code or an invalid
+// transition is reported in its result and does not stop the rest. Results follow the order of
+// the codes; repeated codes are updated once. Only an invalid batch returns an error.
+func (s *Service) BatchUpdateStatus(ctx context.Context, input BatchStatusInput) ([]BatchStatusResult, error) {
+	if len(input.Codes) == 0 {
+		return nil, ErrBatchEmpty
+	}
+	if len(input.Codes) > MaxBatchStatusCodes {
+		return nil, fmt.Errorf("%w: %d codes, max %d", ErrBatchTooLarge, len(input.Codes), MaxBatchStatusCodes)
+	}
+	probe := Order{}
+	if !probe.IsValidStatus(input.Status) {
+		return nil, ErrInvalidStatus
+	}
+
+	results := make([]BatchStatusResult, 0, len(input.Codes))
+	seen := make(map[string]bool, len(input.Codes))
+	for _, code := range input.Codes {
+		code = NormalizeCode(code)
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		status := input.Status
+		o, err := s.PartialUpdate(ctx, code, PartialUpdateInput{Status: &status})
+		results = append(results, BatchStatusResult{Code: code, Order: o, Err: err})
+	}
+	return results, nil
+}
@@ -0,0 +1,113 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBatchUpdateStatus(t *testing.T) {
+	// ON_SITE orders 1-5 start in these statuses
+	statuses := []OrderStatus{StatusInProgress, StatusCreated, StatusInProgress, StatusCancelled, StatusVerified}
+	var orders []*Order
+	for i, status := range statuses {
+		o := storedOrder(i+1, 23333, 0, 23333, nil)
+		o.Status = status
+		orders = append(orders, o)
+	}
+	repo := newMemoryRepository(orders...)
+
+	results, err := NewService(repo).BatchUpdateStatus(context.Background(), BatchStatusInput{
+		Codes:  []string{"ORD-000001", "ORD-000002", "ORD-999999", "ord-000003", "ORD-000004", "ORD-000001", " ORD-000005 "},
+		Status: StatusDelivered,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		code    string
+		wantErr error
+	}{
+		{"ORD-000001", nil},
+		{"ORD-000002", ErrInvalidStatusTransition},
+		{"ORD-999999", ErrOrderNotFound},
+		{"ORD-000003", nil},
+		{"ORD-000004", ErrInvalidStatusTransition},
+		{"ORD-000005", ErrInvalidStatusTransition},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d with repeated codes once: %+v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Code != w.code {
+			t.Errorf("result %d is for %s, want %s", i, r.Code, w.code)
+		}
+		if w.wantErr == nil {
+			if r.Err != nil || r.Order == nil || r.Order.Status != StatusDelivered {
+				t.Errorf("%s: result = %+v, want delivered", w.code, r)
+			}
+		} else if !errors.Is(r.Err, w.wantErr) || r.Order != nil {
+			t.Errorf("%s: err = %v, want %v and no order", w.code, r.Err, w.wantErr)
+		}
+	}
+
+	// A failed update leaves its order alone and does not undo the others
+	wantStored := []OrderStatus{StatusDelivered, StatusCreated, StatusDelivered, StatusCancelled, StatusVerified}
+	for i, o := range orders {
+		if got := repo.stored(o.ID); got.Status != wantStored[i] {
+			t.Errorf("%s stored as %s, want %s", o.Code, got.Status, wantStored[i])
+		}
+	}
+	if got := repo.stored(orders[0].ID).Version; got != 2 {
+		t.Errorf("repeated code saved %d times, want once", got-1)
+	}
+}
+
+func TestBatchUpdateStatusRejectsInvalidBatches(t *testing.T) {
+	codes := func(n int) []string {
+		c := make([]string, n)
+		for i := range c {
+			c[i] = "ORD-000001"
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		input   BatchStatusInput
+		wantErr error
+	}{
+		{"no codes", BatchStatusInput{Status: StatusDelivered}, ErrBatchEmpty},
+		{"too many codes", BatchStatusInput{Codes: codes(MaxBatchStatusCodes + 1), Status: StatusDelivered}, ErrBatchTooLarge},
+		{"unknown status", BatchStatusInput{Codes: codes(1), Status: "SERVED"}, ErrInvalidStatus},
+		{"largest batch", BatchStatusInput{Codes: codes(MaxBatchStatusCodes), Status: StatusDelivered}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			stored.Status = StatusInProgress
+			repo := newMemoryRepository(stored)
+
+			results, err := NewService(repo).BatchUpdateStatus(context.Background(), tt.input)
+			if tt.wantErr == nil {
+				if err != nil || len(results) != 1 || results[0].Err != nil {
+					t.Errorf("results = %+v, err = %v, want one update", results, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || results != nil {
+				t.Fatalf("err = %v, want %v and no results", err, tt.wantErr)
+			}
+			if tt.wantErr == ErrBatchTooLarge && !strings.Contains(err.Error(), "max 100") {
+				t.Errorf("err = %v, want the limit", err)
+			}
+			if repo.stored(stored.ID).Version != 1 {
+				t.Error("order updated by an invalid batch")
+			}
+		})
+	}
+}
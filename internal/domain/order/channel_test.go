@@ -0,0 +1,36 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCreateChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel Channel
+		want    Channel
+		wantErr error
+	}{
+		{"defaults to OTHER", "", ChannelOther, nil},
+		{"kept", ChannelWhatsApp, ChannelWhatsApp, nil},
+		{"unknown", "FAX", "", ErrInvalidChannel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			input := onSiteInput(lines(1, 1, 100))
+			input.Channel = tt.channel
+
+			o, err := NewService(repo).Create(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && repo.stored(o.ID).Channel != tt.want {
+				t.Errorf("channel = %q, want %q", repo.stored(o.ID).Channel, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package order
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// CodeFormat selects how new order codes are generated
+type CodeFormat string
+
+const (
+	// CodeFormatLegacy generates codes like ORD-1716412345123456789-a1b2c3d4
+	CodeFormatLegacy CodeFormat = "legacy"
+	// CodeFormatShort generates codes like ORD-7F3K2Q that can be read over the phone
+	CodeFormatShort CodeFormat = "short"
+)
+
+const (
+	codePrefix = "ORD-"
+
+	// shortCodeAlphabet is base32 without the characters that are easy to confuse (0/O, 1/I)
+	shortCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+	// DefaultShortCodeLength is the number of random characters in a short code (32^6 ≈ 1e9 codes)
+	DefaultShortCodeLength = 6
+
+	// maxCodeAttempts bounds the collision retries when creating an order
+	maxCodeAttempts = 10
+)
+
+// CodeGenerator creates new order codes in the configured format
+type CodeGenerator struct {
+	Format      CodeFormat
+	ShortLength int // Random characters of short codes; DefaultShortCodeLength when <= 0
+}
+
+// Generate returns a new order code
+func (g CodeGenerator) Generate() string {
+	if g.Format != CodeFormatShort {
+		return generateOrderCode()
+	}
+
+	length := g.ShortLength
+	if length <= 0 {
+		length = DefaultShortCodeLength
+	}
+	b := make([]byte, length)
+	// The alphabet has 32 characters, so the low 5 bits of each random byte pick one without bias
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = shortCodeAlphabet[b[i]&31]
+	}
+	return codePrefix + string(b)
+}
+
+// CodePrefixQuery reports whether a quick search query looks like the start of an order code
+// ("ORD-7F") and returns it in stored form, so it can be matched as an exact prefix
+func CodePrefixQuery(query string) (string, bool) {
+	query = strings.TrimSpace(query)
+	if len(query) < len(codePrefix) || !strings.EqualFold(query[:len(codePrefix)], codePrefix) {
+		return "", false
+	}
+	if len(query) == len(codePrefix) {
+		return codePrefix, true
+	}
+	return NormalizeCode(query), true
+}
+
+// NormalizeCode returns the stored form of an order code typed in any case.
+// Short codes are upper case; legacy codes (ORD-<digits>-<hex>) keep their lower case hex part.
+func NormalizeCode(code string) string {
+	code = strings.TrimSpace(code)
+	if len(code) <= len(codePrefix) || !strings.EqualFold(code[:len(codePrefix)], codePrefix) {
+		return code
+	}
+	rest := code[len(codePrefix):]
+	if strings.Contains(rest, "-") {
+		return codePrefix + strings.ToLower(rest)
+	}
+	return codePrefix + strings.ToUpper(rest)
+}
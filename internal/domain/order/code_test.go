@@ -0,0 +1,228 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestShortCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		length     int
+		wantLength int
+	}{
+		{"default length", 0, DefaultShortCodeLength},
+		{"configured length", 10, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := CodeGenerator{Format: CodeFormatShort, ShortLength: tt.length}
+			seen := make(map[byte]bool)
+			for range 2000 {
+				code := g.Generate()
+				random, ok := strings.CutPrefix(code, codePrefix)
+				if !ok || len(random) != tt.wantLength {
+					t.Fatalf("code %q: want %s followed by %d characters", code, codePrefix, tt.wantLength)
+				}
+				if strings.ContainsAny(random, "01OI") {
+					t.Fatalf("code %q contains an ambiguous character", code)
+				}
+				for i := range len(random) {
+					if !strings.ContainsRune(shortCodeAlphabet, rune(random[i])) {
+						t.Fatalf("code %q: %q is not in the alphabet", code, random[i])
+					}
+					seen[random[i]] = true
+				}
+				if NormalizeCode(strings.ToLower(code)) != code {
+					t.Fatalf("code %q does not survive being typed in lower case", code)
+				}
+			}
+			// Every alphabet character is reachable: the random bytes are not biased away from any of them
+			if len(seen) != len(shortCodeAlphabet) {
+				t.Errorf("only %d of %d alphabet characters were generated", len(seen), len(shortCodeAlphabet))
+			}
+		})
+	}
+}
+
+func TestShortCodeAlphabetExcludesAmbiguousCharacters(t *testing.T) {
+	if len(shortCodeAlphabet) != 32 {
+		t.Fatalf("alphabet has %d characters, want 32 so each is picked by 5 random bits", len(shortCodeAlphabet))
+	}
+	if strings.ContainsAny(shortCodeAlphabet, "01OI") {
+		t.Errorf("alphabet %q contains 0, 1, O or I", shortCodeAlphabet)
+	}
+}
+
+func TestLegacyCodes(t *testing.T) {
+	legacy := regexp.MustCompile(`^ORD-[0-9]+-[0-9a-f]{8}$`)
+	for _, g := range []CodeGenerator{{}, {Format: CodeFormatLegacy}} {
+		if code := g.Generate(); !legacy.MatchString(code) {
+			t.Errorf("format %q generated %q, want a legacy code", g.Format, code)
+		}
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"ORD-7F3K2Q", "ORD-7F3K2Q"},
+		{"ord-7f3k2q", "ORD-7F3K2Q"},
+		{"  Ord-7f3K2q ", "ORD-7F3K2Q"},
+		{"ORD-1716412345123456789-a1b2c3d4", "ORD-1716412345123456789-a1b2c3d4"},
+		{"ORD-1716412345123456789-A1B2C3D4", "ORD-1716412345123456789-a1b2c3d4"},
+		{"ord-1716412345123456789-A1b2C3d4", "ORD-1716412345123456789-a1b2c3d4"},
+		{"ORD-", "ORD-"},
+		{"", ""},
+		{"something-else", "something-else"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeCode(tt.in); got != tt.want {
+			t.Errorf("NormalizeCode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCodePrefixQuery(t *testing.T) {
+	tests := []struct {
+		query  string
+		want   string
+		isCode bool
+	}{
+		{"ORD-7F", "ORD-7F", true},
+		{"ord-7f", "ORD-7F", true},
+		{"  Ord-7f ", "ORD-7F", true},
+		{"ORD-", "ORD-", true},
+		{"ord-", "ORD-", true},
+		{"ORD-1716412345", "ORD-1716412345", true},
+		{"ORD-1716412345123456789-A1B2", "ORD-1716412345123456789-a1b2", true},
+		{"ORD", "", false},
+		{"ORDER", "", false},
+		{"Ana", "", false},
+		{"7F3K2Q", "", false},
+		{"3001234567", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, isCode := CodePrefixQuery(tt.query)
+		if got != tt.want || isCode != tt.isCode {
+			t.Errorf("CodePrefixQuery(%q) = %q, %v, want %q, %v", tt.query, got, isCode, tt.want, tt.isCode)
+		}
+	}
+}
+
+// collidingRepository reports the first collisions codes as already used and records every code checked
+type collidingRepository struct {
+	*memoryRepository
+	collisions int
+	err        error // Returned by every check when set
+	checked    []string
+}
+
+func (r *collidingRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	r.checked = append(r.checked, code)
+	if r.err != nil {
+		return false, r.err
+	}
+	if len(r.checked) <= r.collisions {
+		return true, nil
+	}
+	return r.memoryRepository.ExistsByCode(ctx, code)
+}
+
+func TestCreateRetriesCodeCollisions(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	tests := []struct {
+		name        string
+		collisions  int
+		checkErr    error
+		wantChecks  int
+		wantErr     error
+		wantErrText string
+	}{
+		{"free at once", 0, nil, 1, nil, ""},
+		{"one collision", 1, nil, 2, nil, ""},
+		{"several collisions", 5, nil, 6, nil, ""},
+		{"last attempt free", maxCodeAttempts - 1, nil, maxCodeAttempts, nil, ""},
+		{"every attempt collides", maxCodeAttempts, nil, maxCodeAttempts, nil, "unique order code"},
+		{"lookup failed", 0, errDown, 1, errDown, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &collidingRepository{memoryRepository: newMemoryRepository(), collisions: tt.collisions, err: tt.checkErr}
+			svc := NewService(repo, WithCodeGenerator(CodeGenerator{Format: CodeFormatShort}))
+
+			o, err := svc.Create(context.Background(), onSiteInput(lines(1, 1, 1000)))
+			if len(repo.checked) != tt.wantChecks {
+				t.Errorf("checked %d codes, want %d", len(repo.checked), tt.wantChecks)
+			}
+			// A collision draws a new code instead of checking the same one again
+			seen := make(map[string]bool)
+			for _, code := range repo.checked {
+				if seen[code] {
+					t.Errorf("code %s checked twice: %v", code, repo.checked)
+				}
+				seen[code] = true
+			}
+
+			if tt.wantErr != nil || tt.wantErrText != "" {
+				if err == nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("err = %v, want %v %q", err, tt.wantErr, tt.wantErrText)
+				}
+				if len(repo.orders) != 0 {
+					t.Error("order stored without a unique code")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if last := repo.checked[len(repo.checked)-1]; o.Code != last || repo.stored(o.ID).Code != last {
+				t.Errorf("order code = %s, stored %s, want the last code checked %s", o.Code, repo.stored(o.ID).Code, last)
+			}
+		})
+	}
+}
+
+func TestCodesResolveInAnyCase(t *testing.T) {
+	short := storedOrder(1, 23333, 0, 23333, nil)
+	short.Code = "ORD-7F3K2Q"
+	legacy := storedOrder(2, 23333, 0, 23333, nil)
+	legacy.Code = "ORD-1716412345123456789-a1b2c3d4"
+	note := "typed from a phone call"
+
+	for _, tt := range []struct {
+		stored *Order
+		typed  string
+	}{
+		{short, "ord-7f3k2q"},
+		{short, " ORD-7F3K2Q "},
+		{legacy, "ORD-1716412345123456789-A1B2C3D4"},
+		{legacy, "ORD-1716412345123456789-a1b2c3d4"},
+	} {
+		svc := NewService(newMemoryRepository(short, legacy))
+		ctx := context.Background()
+
+		if o, err := svc.GetByCode(ctx, tt.typed); err != nil || o.ID != tt.stored.ID {
+			t.Errorf("GetByCode(%q) = %v, %v", tt.typed, o, err)
+		}
+		if o, err := svc.PartialUpdate(ctx, tt.typed, PartialUpdateInput{Note: &note}); err != nil || o.ID != tt.stored.ID {
+			t.Errorf("PartialUpdate(%q) = %v, %v", tt.typed, o, err)
+		}
+		if r, err := svc.Modify(ctx, tt.typed, ModifyInput{Note: &note}); err != nil || r.Order.ID != tt.stored.ID {
+			t.Errorf("Modify(%q) = %v, %v", tt.typed, r, err)
+		}
+	}
+
+	svc := NewService(newMemoryRepository(short))
+	if _, err := svc.PartialUpdate(context.Background(), "ORD-UNKNOWN", PartialUpdateInput{Note: &note}); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("unknown code: err = %v, want ErrOrderNotFound", err)
+	}
+}
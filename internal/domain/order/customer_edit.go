@@ -0,0 +1,99 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// FieldShippingAddress is the shipping address field, editable by customers through the tracking code
+const FieldShippingAddress = "shipping_address"
+
+// CustomerEditableFields lists the fields a customer may change through the tracking code
+var CustomerEditableFields = []string{FieldShippingAddress, FieldNote}
+
+// FieldEdit records a change of order fields outside the status lifecycle
+type FieldEdit struct {
+	Fields   []string   `json:"fields" bson:"fields"`
+	Actor    string     `json:"actor" bson:"actor"`
+	EditedAt time.Time  `json:"edited_at" bson:"edited_at"`
+	Changes  *OrderDiff `json:"changes,omitempty" bson:"changes,omitempty"` // Set by full modifications (PUT)
+}
+
+// CustomerEditInput represents the changes a customer can make to their own order
+type CustomerEditInput struct {
+	ShippingAddress *string
+	Note            *string
+}
+
+// changedFields lists the fields a customer edit sets
+func (input CustomerEditInput) changedFields() []string {
+	var fields []string
+	if input.ShippingAddress != nil {
+		fields = append(fields, FieldShippingAddress)
+	}
+	if input.Note != nil {
+		fields = append(fields, FieldNote)
+	}
+	return fields
+}
+
+// CustomerEdit applies a customer's own changes to a just-placed order, identified by its tracking code.
+// Only CREATED orders within the edit window can be changed, and the edit is recorded with the customer actor.
+func (s *Service) CustomerEdit(ctx context.Context, code string, input CustomerEditInput) (*Order, error) {
+	code = NormalizeCode(code)
+	if code == "" {
+		return nil, ErrInvalidOrderCode
+	}
+	if s.customerEditWindow <= 0 {
+		return nil, ErrCustomerEditDisabled
+	}
+
+	fields := input.changedFields()
+	if len(fields) == 0 {
+		return nil, ErrNoCustomerEditFields
+	}
+
+	order, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if order.Status != StatusCreated {
+		return nil, fmt.Errorf("%w: order is %s", ErrCustomerEditNotAllowed, order.Status)
+	}
+	if now.After(order.CreatedAt.Add(s.customerEditWindow)) {
+		return nil, ErrCustomerEditWindowExpired
+	}
+
+	if input.ShippingAddress != nil {
+		order.ShippingAddress = input.ShippingAddress
+	}
+	if input.Note != nil {
+		// Notes written by the customer are always visible to them
+		if err := order.addNote(*input.Note, ActorCustomer, NotePublic, NotePublic, s.maxNotes, now); err != nil {
+			return nil, fmt.Errorf("validation error: %w", err)
+		}
+	}
+
+	// Sanitize free text before validation
+	if err := order.SanitizeText(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if err := order.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if err := s.checkDocumentSize(order); err != nil {
+		return nil, err
+	}
+
+	order.EditHistory = append(order.EditHistory, FieldEdit{Fields: fields, Actor: ActorCustomer, EditedAt: now})
+	order.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	return order, nil
+}
@@ -0,0 +1,94 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCustomerEdit(t *testing.T) {
+	const window = 15 * time.Minute
+	current := "Calle 1 # 2-3"
+	address := "Calle 10 # 20-30"
+	note := "No onions please"
+
+	tests := []struct {
+		name       string
+		window     time.Duration
+		age        time.Duration // Time since the order was placed
+		status     OrderStatus
+		code       string // Defaults to the stored code
+		input      CustomerEditInput
+		wantErr    error
+		wantFields []string
+	}{
+		{"address inside the window", window, window - time.Minute, StatusCreated, "", CustomerEditInput{ShippingAddress: &address}, nil, []string{FieldShippingAddress}},
+		{"note inside the window", window, time.Minute, StatusCreated, "", CustomerEditInput{Note: &note}, nil, []string{FieldNote}},
+		{"both fields", window, time.Minute, StatusCreated, "", CustomerEditInput{ShippingAddress: &address, Note: &note}, nil, []string{FieldShippingAddress, FieldNote}},
+		{"lowercase code", window, time.Minute, StatusCreated, "ord-000001", CustomerEditInput{Note: &note}, nil, []string{FieldNote}},
+		{"window expired", window, window + time.Minute, StatusCreated, "", CustomerEditInput{Note: &note}, ErrCustomerEditWindowExpired, nil},
+		{"edits disabled", 0, time.Minute, StatusCreated, "", CustomerEditInput{Note: &note}, ErrCustomerEditDisabled, nil},
+		{"already verified", window, time.Minute, StatusVerified, "", CustomerEditInput{Note: &note}, ErrCustomerEditNotAllowed, nil},
+		{"already cancelled", window, time.Minute, StatusCancelled, "", CustomerEditInput{Note: &note}, ErrCustomerEditNotAllowed, nil},
+		{"nothing to edit", window, time.Minute, StatusCreated, "", CustomerEditInput{}, ErrNoCustomerEditFields, nil},
+		{"address on an on-site order", window, time.Minute, StatusCreated, "", CustomerEditInput{ShippingAddress: &address}, ErrShippingAddressNotAllowedForOnSite, nil},
+		{"unknown code", window, time.Minute, StatusCreated, "ORD-999999", CustomerEditInput{Note: &note}, ErrOrderNotFound, nil},
+		{"blank code", window, time.Minute, StatusCreated, " ", CustomerEditInput{Note: &note}, ErrInvalidOrderCode, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := storedOrder(1, 23333, 0, 23333, nil)
+			existing.Status = tt.status
+			existing.CreatedAt = time.Now().Add(-tt.age)
+			if !errors.Is(tt.wantErr, ErrShippingAddressNotAllowedForOnSite) { // The remaining cases edit a delivery order
+				existing.SaleType = SaleTypeDelivery
+				existing.TableNumber = nil
+				existing.ShippingAddress = &current
+				existing.Customer = &Customer{Identification: "1020304050", IDType: "CC", Name: "Ana", Phone: "300 123 4567"}
+			}
+			repo := newMemoryRepository(existing)
+			svc := NewService(repo, WithCustomerEditWindow(tt.window))
+
+			code := tt.code
+			if code == "" {
+				code = existing.Code
+			}
+			_, err := svc.CustomerEdit(context.Background(), code, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			saved := repo.stored(existing.ID)
+			if tt.wantErr != nil {
+				if saved.Version != existing.Version || len(saved.EditHistory) != 0 {
+					t.Errorf("rejected edit was stored: version %d, history %v", saved.Version, saved.EditHistory)
+				}
+				return
+			}
+
+			if len(saved.EditHistory) != 1 {
+				t.Fatalf("edit history = %v, want one entry", saved.EditHistory)
+			}
+			edit := saved.EditHistory[0]
+			if edit.Actor != ActorCustomer || !slices.Equal(edit.Fields, tt.wantFields) {
+				t.Errorf("edit = %s %v, want %s %v", edit.Actor, edit.Fields, ActorCustomer, tt.wantFields)
+			}
+			if tt.input.ShippingAddress != nil && (saved.ShippingAddress == nil || *saved.ShippingAddress != address) {
+				t.Errorf("shipping address = %v, want %q", saved.ShippingAddress, address)
+			}
+			if tt.input.Note != nil {
+				notes := saved.NoteList()
+				last := notes[len(notes)-1]
+				if last.Text != note || last.Author != ActorCustomer || last.Visibility != NotePublic {
+					t.Errorf("note = %+v, want a public customer note %q", last, note)
+				}
+			}
+			if saved.Status != tt.status {
+				t.Errorf("status = %s, want it unchanged", saved.Status)
+			}
+		})
+	}
+}
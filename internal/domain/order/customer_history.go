@@ -0,0 +1,58 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// CustomerHistoryQuery selects one page of the orders of a customer
+type CustomerHistoryQuery struct {
+	Identification string
+	IDType         *IDType // Identifications are only unique per type; nil matches every type
+	CompanyID      *string
+	SalePointID    *string
+	Limit          int
+	Offset         int
+}
+
+// CustomerStats summarizes every order of the customer, not only the returned page
+type CustomerStats struct {
+	OrderCount    int64
+	LifetimeSpend int64      // Total of the orders that were not cancelled, in cents
+	LastOrderAt   *time.Time // Nil when the customer has no orders
+}
+
+// CustomerHistory is one page of the orders of a customer, newest first, with their stats
+type CustomerHistory struct {
+	Orders []*Order
+	Stats  CustomerStats
+}
+
+// GetCustomerHistory lists the orders placed with a customer identification
+func (s *Service) GetCustomerHistory(ctx context.Context, q CustomerHistoryQuery) (*CustomerHistory, error) {
+	q.Identification = strings.TrimSpace(q.Identification)
+	if q.Identification == "" {
+		return nil, apperrors.NewDomainError(ErrCustomerIdentificationRequired, "identification", q.Identification)
+	}
+	if q.IDType != nil && !slices.Contains(AllIDTypes, *q.IDType) {
+		return nil, apperrors.NewDomainError(ErrInvalidIDType, "id_type", *q.IDType)
+	}
+
+	// Apply configured pagination limits
+	q.Limit = s.pageLimits.Resolve(q.Limit)
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	orders, stats, err := s.repo.FindByCustomerIdentification(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer orders: %w", err)
+	}
+
+	return &CustomerHistory{Orders: orders, Stats: stats}, nil
+}
@@ -0,0 +1,130 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestGetCustomerHistory(t *testing.T) {
+	customerOrder := func(i int, identification string, idType IDType, status OrderStatus, total int64) *Order {
+		o := storedOrder(i, total, 0, total, nil)
+		o.Customer = &Customer{Identification: identification, IDType: idType, Name: "Ana"}
+		o.Status = status
+		return o
+	}
+	repo := newMemoryRepository(
+		customerOrder(1, "1020304050", IDTypeCC, StatusDelivered, 10000),
+		customerOrder(2, "1020304050", IDTypeCC, StatusCancelled, 5000),
+		customerOrder(3, "1020304050", IDTypeCC, StatusCreated, 20000),
+		customerOrder(4, "1020304050", IDTypeNIT, StatusDelivered, 70000),
+		customerOrder(5, "9999999999", IDTypeCC, StatusDelivered, 30000),
+		storedOrder(6, 23333, 0, 23333, nil),
+	)
+	cc, nit := IDTypeCC, IDTypeNIT
+
+	tests := []struct {
+		name      string
+		q         CustomerHistoryQuery
+		wantCodes []string
+		wantCount int64
+		wantSpend int64
+		wantLast  int // Index of the newest order; 0 for none
+	}{
+		{"every id type", CustomerHistoryQuery{Identification: "1020304050"}, []string{"ORD-000004", "ORD-000003", "ORD-000002", "ORD-000001"}, 4, 100000, 4},
+		{"one id type", CustomerHistoryQuery{Identification: "1020304050", IDType: &cc}, []string{"ORD-000003", "ORD-000002", "ORD-000001"}, 3, 30000, 3},
+		{"other id type", CustomerHistoryQuery{Identification: "1020304050", IDType: &nit}, []string{"ORD-000004"}, 1, 70000, 4},
+		{"stats cover every page", CustomerHistoryQuery{Identification: "1020304050", IDType: &cc, Limit: 1, Offset: 1}, []string{"ORD-000002"}, 3, 30000, 3},
+		{"identification trimmed", CustomerHistoryQuery{Identification: " 9999999999 "}, []string{"ORD-000005"}, 1, 30000, 5},
+		{"unknown customer", CustomerHistoryQuery{Identification: "1111111111"}, nil, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history, err := NewService(repo).GetCustomerHistory(context.Background(), tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var codes []string
+			for _, o := range history.Orders {
+				codes = append(codes, o.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) {
+				t.Errorf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+			stats := history.Stats
+			if stats.OrderCount != tt.wantCount || stats.LifetimeSpend != tt.wantSpend {
+				t.Errorf("stats = %d orders, %d spent; want %d, %d", stats.OrderCount, stats.LifetimeSpend, tt.wantCount, tt.wantSpend)
+			}
+			if tt.wantLast == 0 {
+				if stats.LastOrderAt != nil {
+					t.Errorf("last order at %v, want none", stats.LastOrderAt)
+				}
+				return
+			}
+			last := repo.stored(fmt.Sprintf("order-%d", tt.wantLast))
+			if stats.LastOrderAt == nil || !stats.LastOrderAt.Equal(last.CreatedAt) {
+				t.Errorf("last order at %v, want %v", stats.LastOrderAt, last.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestGetCustomerHistoryPageLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		offset     int
+		wantLimit  int
+		wantOffset int
+	}{
+		{"default limit", 0, 0, 25, 0},
+		{"clamped to the maximum", 1000, 10, 100, 10},
+		{"negative offset", 10, -5, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithPageLimits(util.PageLimits{DefaultLimit: 25, MaxLimit: 100}))
+
+			if _, err := svc.GetCustomerHistory(context.Background(), CustomerHistoryQuery{Identification: "1020304050", Limit: tt.limit, Offset: tt.offset}); err != nil {
+				t.Fatal(err)
+			}
+			got := repo.histories[0]
+			if got.Limit != tt.wantLimit || got.Offset != tt.wantOffset {
+				t.Errorf("limit, offset = %d, %d, want %d, %d", got.Limit, got.Offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestGetCustomerHistoryRejectsInvalidQueries(t *testing.T) {
+	unknown := IDType("DNI")
+
+	tests := []struct {
+		name    string
+		q       CustomerHistoryQuery
+		wantErr error
+	}{
+		{"no identification", CustomerHistoryQuery{}, ErrCustomerIdentificationRequired},
+		{"blank identification", CustomerHistoryQuery{Identification: "   "}, ErrCustomerIdentificationRequired},
+		{"unknown id type", CustomerHistoryQuery{Identification: "1020304050", IDType: &unknown}, ErrInvalidIDType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			if _, err := NewService(repo).GetCustomerHistory(context.Background(), tt.q); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.histories) != 0 {
+				t.Error("an invalid query reached the repository")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package order
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDateBound(t *testing.T) {
+	bogota, err := time.LoadLocation("America/Bogota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		endOfDay bool
+		loc      *time.Location
+		want     time.Time
+	}{
+		{"RFC3339 in UTC", "2024-05-01T10:30:00Z", false, time.UTC, utc("2024-05-01T10:30:00Z")},
+		{"RFC3339 offset converted to UTC", "2024-05-01T10:30:00-05:00", false, time.UTC, utc("2024-05-01T15:30:00Z")},
+		{"RFC3339 ignores the location", "2024-05-01T10:30:00Z", false, bogota, utc("2024-05-01T10:30:00Z")},
+		{"RFC3339 kept as is at end of day", "2024-05-01T10:30:00Z", true, time.UTC, utc("2024-05-01T10:30:00Z")},
+		{"date start of day in UTC", "2024-05-01", false, time.UTC, utc("2024-05-01T00:00:00Z")},
+		{"date end of day in UTC", "2024-05-01", true, time.UTC, utc("2024-05-01T23:59:59.999Z")},
+		{"date start of day in Bogota", "2024-05-01", false, bogota, utc("2024-05-01T05:00:00Z")},
+		{"date end of day in Bogota", "2024-05-01", true, bogota, utc("2024-05-02T04:59:59.999Z")},
+		{"end of a leap day", "2024-02-29", true, time.UTC, utc("2024-02-29T23:59:59.999Z")},
+		{"end of a short DST day", "2024-03-10", true, newYork, utc("2024-03-11T03:59:59.999Z")},
+		{"end of the year", "2024-12-31", true, time.UTC, utc("2024-12-31T23:59:59.999Z")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateBound(tt.value, tt.endOfDay, tt.loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("ParseDateBound(%q) = %s, want %s in UTC", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateBoundRejectsInvalidValues(t *testing.T) {
+	for _, value := range []string{"", "yesterday", "2024-13-01", "2023-02-29", "05/01/2024", "2024-5-1", "2024-05-01T10:30", "2024-05-01 10:30:00"} {
+		t.Run(value, func(t *testing.T) {
+			if _, err := ParseDateBound(value, false, time.UTC); !errors.Is(err, ErrInvalidDateFilter) {
+				t.Errorf("ParseDateBound(%q) error = %v, want ErrInvalidDateFilter", value, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package order
+
+import "slices"
+
+// Fields compared by a modification diff
+const (
+	FieldProducts = "products"
+	FieldCustomer = "customer"
+)
+
+// LineChange records a product line whose quantity or unit price changed
+type LineChange struct {
+	ID               string `json:"id" bson:"id"`
+	Name             string `json:"name" bson:"name"`
+	PreviousQuantity int    `json:"previous_quantity" bson:"previous_quantity"`
+	Quantity         int    `json:"quantity" bson:"quantity"`
+	PreviousPrice    int64  `json:"previous_price" bson:"previous_price"` // In cents
+	Price            int64  `json:"price" bson:"price"`                   // In cents
+}
+
+// TextChange records the previous and new value of an optional text field
+type TextChange struct {
+	Previous *string `json:"previous,omitempty" bson:"previous,omitempty"`
+	New      *string `json:"new,omitempty" bson:"new,omitempty"`
+}
+
+// CustomerChange records the previous and new customer of an order
+type CustomerChange struct {
+	Previous *Customer `json:"previous,omitempty" bson:"previous,omitempty"`
+	New      *Customer `json:"new,omitempty" bson:"new,omitempty"`
+}
+
+// DiscountChange records the previous and new discount of an order
+type DiscountChange struct {
+	Previous *Discount `json:"previous,omitempty" bson:"previous,omitempty"`
+	New      *Discount `json:"new,omitempty" bson:"new,omitempty"`
+}
+
+// OrderDiff describes what a modification changed in an order
+type OrderDiff struct {
+	Added           []OrderProduct  `json:"added,omitempty" bson:"added,omitempty"`
+	Removed         []OrderProduct  `json:"removed,omitempty" bson:"removed,omitempty"`
+	Modified        []LineChange    `json:"modified,omitempty" bson:"modified,omitempty"`
+	ShippingAddress *TextChange     `json:"shipping_address,omitempty" bson:"shipping_address,omitempty"`
+	Customer        *CustomerChange `json:"customer,omitempty" bson:"customer,omitempty"`
+	Note            *TextChange     `json:"note,omitempty" bson:"note,omitempty"` // Only on edits stored before notes were append-only
+	NotesAdded      []OrderNote     `json:"notes_added,omitempty" bson:"notes_added,omitempty"`
+	Discount        *DiscountChange `json:"discount,omitempty" bson:"discount,omitempty"`
+	PreviousTotal   int64           `json:"previous_total" bson:"previous_total"` // In cents
+	Total           int64           `json:"total" bson:"total"`                   // In cents
+}
+
+// TotalDelta returns how much the total moved, in cents
+func (d OrderDiff) TotalDelta() int64 {
+	return d.Total - d.PreviousTotal
+}
+
+// IsEmpty reports whether the modification left the order unchanged
+func (d OrderDiff) IsEmpty() bool {
+	return len(d.Fields()) == 0 && d.TotalDelta() == 0
+}
+
+// ProductsChanged reports whether lines were added, removed or modified
+func (d OrderDiff) ProductsChanged() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Modified) > 0
+}
+
+// Fields lists the order fields the modification changed
+func (d OrderDiff) Fields() []string {
+	var fields []string
+	if d.ProductsChanged() {
+		fields = append(fields, FieldProducts)
+	}
+	if d.ShippingAddress != nil {
+		fields = append(fields, FieldShippingAddress)
+	}
+	if d.Customer != nil {
+		fields = append(fields, FieldCustomer)
+	}
+	if d.Note != nil || len(d.NotesAdded) > 0 {
+		fields = append(fields, FieldNote)
+	}
+	if d.Discount != nil {
+		fields = append(fields, FieldDiscount)
+	}
+	return fields
+}
+
+// Diff compares the state of an order before and after a modification.
+// Product lines are matched by ID, so reordering an otherwise identical list is not a change.
+func Diff(before, after *Order) OrderDiff {
+	diff := OrderDiff{PreviousTotal: before.Total, Total: after.Total}
+	diff.Added, diff.Removed, diff.Modified = diffLines(before.Products, after.Products)
+
+	if !equalText(before.ShippingAddress, after.ShippingAddress) {
+		diff.ShippingAddress = &TextChange{Previous: before.ShippingAddress, New: after.ShippingAddress}
+	}
+	if !equalCustomer(before.Customer, after.Customer) {
+		diff.Customer = &CustomerChange{Previous: before.Customer, New: after.Customer}
+	}
+	diff.NotesAdded = addedNotes(before, after)
+	if !equalDiscount(before.Discount, after.Discount) {
+		diff.Discount = &DiscountChange{Previous: before.Discount, New: after.Discount}
+	}
+	return diff
+}
+
+// diffLines matches the lines of both lists by product ID, price variation and addons.
+// Identical lines are paired first, so a product listed twice with different quantities
+// is not reported as modified when only its lines were swapped; the remaining lines
+// of the same item are paired in order and reported as modified.
+// Changing the variation or the addons of a line reports it as removed and added.
+func diffLines(before, after []OrderProduct) (added, removed []OrderProduct, modified []LineChange) {
+	matched := make([]bool, len(before))
+	var pending []OrderProduct
+	for _, line := range after {
+		i := unmatchedLine(before, matched, func(p OrderProduct) bool {
+			return sameItem(p, line) && p.Quantity == line.Quantity && p.Price == line.Price
+		})
+		if i < 0 {
+			pending = append(pending, line)
+			continue
+		}
+		matched[i] = true
+	}
+
+	for _, line := range pending {
+		i := unmatchedLine(before, matched, func(p OrderProduct) bool { return sameItem(p, line) })
+		if i < 0 {
+			added = append(added, line)
+			continue
+		}
+		matched[i] = true
+		modified = append(modified, LineChange{
+			ID:               line.ID,
+			Name:             line.Name,
+			PreviousQuantity: before[i].Quantity,
+			Quantity:         line.Quantity,
+			PreviousPrice:    before[i].Price,
+			Price:            line.Price,
+		})
+	}
+
+	for i, line := range before {
+		if !matched[i] {
+			removed = append(removed, line)
+		}
+	}
+	return added, removed, modified
+}
+
+// unmatchedLine returns the index of the first line not yet matched that satisfies match, or -1
+func unmatchedLine(lines []OrderProduct, matched []bool, match func(OrderProduct) bool) int {
+	for i, line := range lines {
+		if !matched[i] && match(line) {
+			return i
+		}
+	}
+	return -1
+}
+
+// equalText compares optional text values, treating nil and empty as the same
+func equalText(a, b *string) bool {
+	return textValue(a) == textValue(b)
+}
+
+func textValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// equalCustomer compares customers by value
+func equalCustomer(a, b *Customer) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// snapshot copies the parts of the order a modification replaces, for diffing afterwards
+func (o *Order) snapshot() *Order {
+	before := *o
+	before.Products = slices.Clone(o.Products)
+	before.Notes = slices.Clone(o.Notes)
+	return &before
+}
@@ -0,0 +1,203 @@
+package order
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// lineIDs lists the IDs of the lines, in order
+func lineIDs(products []OrderProduct) []string {
+	var ids []string
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestDiffLines(t *testing.T) {
+	burger := OrderProduct{ID: "p1", Name: "Burger", Price: 10000, Quantity: 2}
+	soda := OrderProduct{ID: "p2", Name: "Soda", Price: 3000, Quantity: 1}
+	fries := OrderProduct{ID: "p3", Name: "Fries", Price: 5000, Quantity: 1}
+	withCheese := burger
+	withCheese.Addons = []OrderProductAddon{{ID: "a1", Name: "Cheese", Price: 1000, Quantity: 1}}
+	withBacon := burger
+	withBacon.Addons = []OrderProductAddon{{ID: "a2", Name: "Bacon", Price: 1500, Quantity: 1}, {ID: "a1", Name: "Cheese", Price: 1000, Quantity: 1}}
+	baconReordered := burger
+	baconReordered.Addons = []OrderProductAddon{withBacon.Addons[1], withBacon.Addons[0]}
+	qty := func(p OrderProduct, n int) OrderProduct { p.Quantity = n; return p }
+	price := func(p OrderProduct, cents int64) OrderProduct { p.Price = cents; return p }
+
+	tests := []struct {
+		name         string
+		before       []OrderProduct
+		after        []OrderProduct
+		wantAdded    []string
+		wantRemoved  []string
+		wantModified []LineChange
+	}{
+		{"unchanged", []OrderProduct{burger, soda}, []OrderProduct{burger, soda}, nil, nil, nil},
+		{"reordered", []OrderProduct{burger, soda, fries}, []OrderProduct{fries, burger, soda}, nil, nil, nil},
+		{"addons reordered", []OrderProduct{baconReordered}, []OrderProduct{withBacon}, nil, nil, nil},
+		{"line added", []OrderProduct{burger}, []OrderProduct{burger, soda}, []string{"p2"}, nil, nil},
+		{"line added to an empty order", nil, []OrderProduct{burger}, []string{"p1"}, nil, nil},
+		{"line removed", []OrderProduct{burger, soda}, []OrderProduct{soda}, nil, []string{"p1"}, nil},
+		{"all lines replaced", []OrderProduct{burger, soda}, []OrderProduct{fries}, []string{"p3"}, []string{"p1", "p2"}, nil},
+		{"quantity changed", []OrderProduct{burger, soda}, []OrderProduct{soda, qty(burger, 5)}, nil, nil,
+			[]LineChange{{ID: "p1", Name: "Burger", PreviousQuantity: 2, Quantity: 5, PreviousPrice: 10000, Price: 10000}}},
+		{"price changed", []OrderProduct{soda}, []OrderProduct{price(soda, 3500)}, nil, nil,
+			[]LineChange{{ID: "p2", Name: "Soda", PreviousQuantity: 1, Quantity: 1, PreviousPrice: 3000, Price: 3500}}},
+		{"added, removed and modified at once", []OrderProduct{burger, soda}, []OrderProduct{fries, qty(burger, 1)}, []string{"p3"}, []string{"p2"},
+			[]LineChange{{ID: "p1", Name: "Burger", PreviousQuantity: 2, Quantity: 1, PreviousPrice: 10000, Price: 10000}}},
+		{"addon added", []OrderProduct{burger}, []OrderProduct{withCheese}, []string{"p1"}, []string{"p1"}, nil},
+		{"addon changed", []OrderProduct{withCheese}, []OrderProduct{withBacon}, []string{"p1"}, []string{"p1"}, nil},
+		{"same product on two lines swapped", []OrderProduct{burger, qty(burger, 7)}, []OrderProduct{qty(burger, 7), burger}, nil, nil, nil},
+		{"same product on two lines, one changed", []OrderProduct{burger, qty(burger, 7)}, []OrderProduct{qty(burger, 7), qty(burger, 3)}, nil, nil,
+			[]LineChange{{ID: "p1", Name: "Burger", PreviousQuantity: 2, Quantity: 3, PreviousPrice: 10000, Price: 10000}}},
+		{"same product gains a line", []OrderProduct{burger}, []OrderProduct{burger, qty(burger, 4)}, []string{"p1"}, nil, nil},
+		{"same product loses a line", []OrderProduct{burger, qty(burger, 4)}, []OrderProduct{qty(burger, 4)}, nil, []string{"p1"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, modified := diffLines(tt.before, tt.after)
+			if got := lineIDs(added); !slices.Equal(got, tt.wantAdded) {
+				t.Errorf("added = %v, want %v", got, tt.wantAdded)
+			}
+			if got := lineIDs(removed); !slices.Equal(got, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", got, tt.wantRemoved)
+			}
+			if !reflect.DeepEqual(modified, tt.wantModified) {
+				t.Errorf("modified = %+v, want %+v", modified, tt.wantModified)
+			}
+		})
+	}
+}
+
+// TestDiffIgnoresEveryReordering checks all orderings of a list, duplicates included
+func TestDiffIgnoresEveryReordering(t *testing.T) {
+	base := []OrderProduct{
+		{ID: "p1", Price: 10000, Quantity: 2},
+		{ID: "p2", Price: 3000, Quantity: 1},
+		{ID: "p1", Price: 10000, Quantity: 5},
+		{ID: "p3", Price: 5000, Quantity: 1},
+	}
+	var permute func(prefix, rest []OrderProduct)
+	permute = func(prefix, rest []OrderProduct) {
+		if len(rest) == 0 {
+			before := &Order{Products: base}
+			after := &Order{Products: prefix}
+			if d := Diff(before, after); !d.IsEmpty() {
+				t.Errorf("%v reported as changed: %+v", lineIDs(prefix), d)
+			}
+			return
+		}
+		for i := range rest {
+			next := slices.Concat(rest[:i:i], rest[i+1:])
+			permute(append(slices.Clone(prefix), rest[i]), next)
+		}
+	}
+	permute(nil, base)
+}
+
+func TestDiffFields(t *testing.T) {
+	address, otherAddress, empty := "Calle 1", "Calle 2", ""
+	before := func() *Order {
+		return &Order{
+			Products:        []OrderProduct{{ID: "p1", Price: 10000, Quantity: 1}},
+			ShippingAddress: &address,
+			Customer:        &Customer{Identification: "123", Name: "Ana"},
+			Total:           10000,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		change     func(o *Order)
+		wantFields []string
+		wantDelta  int64
+	}{
+		{"nothing", func(o *Order) {}, nil, 0},
+		{"address", func(o *Order) { o.ShippingAddress = &otherAddress }, []string{FieldShippingAddress}, 0},
+		{"address cleared", func(o *Order) { o.ShippingAddress = nil }, []string{FieldShippingAddress}, 0},
+		{"address emptied", func(o *Order) { o.ShippingAddress = &empty }, []string{FieldShippingAddress}, 0},
+		{"customer", func(o *Order) { o.Customer = &Customer{Identification: "123", Name: "Ana María"} }, []string{FieldCustomer}, 0},
+		{"note added", func(o *Order) { o.Notes = []OrderNote{{Text: "ring twice"}} }, []string{FieldNote}, 0},
+		{"discount", func(o *Order) { o.Discount = &Discount{Type: DiscountFixed, Value: 1000}; o.Total = 9000 }, []string{FieldDiscount}, -1000},
+		{"products and total", func(o *Order) { o.Products[0].Quantity = 3; o.Total = 30000 }, []string{FieldProducts}, 20000},
+		{"everything", func(o *Order) {
+			o.Products = nil
+			o.ShippingAddress = &otherAddress
+			o.Customer = nil
+			o.Notes = []OrderNote{{Text: "cancelled lines"}}
+			o.Discount = &Discount{Type: DiscountPercent, Value: 10}
+			o.Total = 0
+		}, []string{FieldProducts, FieldShippingAddress, FieldCustomer, FieldNote, FieldDiscount}, -10000},
+	}
+
+	// Optional text treats nil and empty as the same value
+	if d := Diff(&Order{ShippingAddress: &empty}, &Order{}); !d.IsEmpty() {
+		t.Errorf("empty to nil address reported as a change: %+v", d)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := before()
+			changed := old.snapshot()
+			tt.change(changed)
+			d := Diff(old, changed)
+			if got := d.Fields(); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+			if d.TotalDelta() != tt.wantDelta {
+				t.Errorf("total delta = %d, want %d", d.TotalDelta(), tt.wantDelta)
+			}
+			if want := len(tt.wantFields) == 0 && tt.wantDelta == 0; d.IsEmpty() != want {
+				t.Errorf("IsEmpty = %v, want %v", d.IsEmpty(), want)
+			}
+		})
+	}
+}
+
+func TestModifyReturnsAndRecordsChanges(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		products    []OrderProduct
+		wantFields  []string
+		wantHistory int
+	}{
+		{"reordered lines change nothing", []OrderProduct{{ID: "p2", Name: "Soda", Price: 3333, Quantity: 1}, {ID: "p1", Name: "Burger", Price: 10000, Quantity: 2}}, nil, 0},
+		{"new line recorded", []OrderProduct{{ID: "p1", Name: "Burger", Price: 10000, Quantity: 2}, {ID: "p2", Name: "Soda", Price: 3333, Quantity: 1}, {ID: "p3", Name: "Fries", Price: 5000, Quantity: 1}}, []string{FieldProducts}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			repo := newMemoryRepository(stored)
+			svc := NewService(repo)
+
+			result, err := svc.Modify(ctx, stored.Code, ModifyInput{Products: tt.products})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Changes.Fields(); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("changed fields = %v, want %v", got, tt.wantFields)
+			}
+			history := repo.stored(stored.ID).EditHistory
+			if len(history) != tt.wantHistory {
+				t.Fatalf("edit history has %d entries, want %d", len(history), tt.wantHistory)
+			}
+			if tt.wantHistory > 0 {
+				entry := history[0]
+				if entry.Actor != ActorUser || entry.Changes == nil || !reflect.DeepEqual(*entry.Changes, result.Changes) {
+					t.Errorf("history entry = %+v, want the returned changes by %s", entry, ActorUser)
+				}
+				if result.Changes.TotalDelta() != 5000 {
+					t.Errorf("total delta = %d, want 5000", result.Changes.TotalDelta())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package order
+
+import (
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// FieldDiscount is the order field holding the discount
+const FieldDiscount = "discount"
+
+// DiscountType selects how a discount value is applied
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "PERCENT" // Value is a whole percentage of the subtotal (0-100)
+	DiscountFixed   DiscountType = "FIXED"   // Value is an amount in cents
+)
+
+// Discount is a promotion applied to the order subtotal
+type Discount struct {
+	Type        DiscountType `json:"type" bson:"type"`
+	Value       int64        `json:"value" bson:"value"`
+	Description string       `json:"description,omitempty" bson:"description,omitempty"`
+}
+
+// Amount returns the discount on a subtotal, in cents, never more than the subtotal.
+// Percentages are rounded half up to the cent.
+func (d *Discount) Amount(subtotal int64) int64 {
+	if d == nil || subtotal <= 0 || d.Value <= 0 {
+		return 0
+	}
+	var amount int64
+	switch d.Type {
+	case DiscountPercent:
+		amount = (subtotal*d.Value + 50) / 100
+	case DiscountFixed:
+		amount = d.Value
+	}
+	return min(amount, subtotal)
+}
+
+// Validate checks the discount against the subtotal it applies to
+func (d *Discount) Validate(subtotal int64) error {
+	switch d.Type {
+	case DiscountPercent:
+		if d.Value < 0 || d.Value > 100 {
+			return apperrors.NewDomainError(ErrInvalidDiscountValue, "discount.value", d.Value)
+		}
+	case DiscountFixed:
+		if d.Value < 0 {
+			return apperrors.NewDomainError(ErrInvalidDiscountValue, "discount.value", d.Value)
+		}
+		if d.Value > subtotal {
+			return apperrors.NewDomainError(ErrDiscountExceedsSubtotal, "discount.value", d.Value)
+		}
+	default:
+		return apperrors.NewDomainError(ErrInvalidDiscountType, "discount.type", d.Type)
+	}
+	return nil
+}
+
+// setDiscount replaces the discount and recalculates the total; a zero value removes it
+func (o *Order) setDiscount(d *Discount) {
+	if d != nil && d.Value == 0 {
+		d = nil
+	}
+	o.Discount = d
+	o.CalculateTotal()
+}
+
+// equalDiscount compares discounts by value
+func equalDiscount(a, b *Discount) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,225 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDiscountAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount *Discount
+		subtotal int64
+		want     int64
+	}{
+		{"no discount", nil, 10000, 0},
+		{"percent", &Discount{Type: DiscountPercent, Value: 10}, 10000, 1000},
+		{"percent rounds half up", &Discount{Type: DiscountPercent, Value: 10}, 1005, 101},
+		{"percent rounds down below half", &Discount{Type: DiscountPercent, Value: 10}, 1004, 100},
+		{"percent of a peso amount", &Discount{Type: DiscountPercent, Value: 15}, 333, 50},
+		{"full percent", &Discount{Type: DiscountPercent, Value: 100}, 4599, 4599},
+		{"half of one cent rounds up", &Discount{Type: DiscountPercent, Value: 50}, 1, 1},
+		{"half of three cents rounds up", &Discount{Type: DiscountPercent, Value: 50}, 3, 2},
+		{"just under half a cent", &Discount{Type: DiscountPercent, Value: 49}, 1, 0},
+		{"one percent of 49 cents", &Discount{Type: DiscountPercent, Value: 1}, 49, 0},
+		{"one percent of 50 cents", &Discount{Type: DiscountPercent, Value: 1}, 50, 1},
+		{"99 percent of one cent", &Discount{Type: DiscountPercent, Value: 99}, 1, 1},
+		{"a third never loses a cent twice", &Discount{Type: DiscountPercent, Value: 33}, 101, 33},
+		{"large subtotal", &Discount{Type: DiscountPercent, Value: 15}, 1_000_000_000_001, 150_000_000_000},
+		{"negative value", &Discount{Type: DiscountFixed, Value: -100}, 10000, 0},
+		{"unknown type", &Discount{Type: "BOGO", Value: 10}, 10000, 0},
+		{"fixed", &Discount{Type: DiscountFixed, Value: 2500}, 10000, 2500},
+		{"fixed capped at the subtotal", &Discount{Type: DiscountFixed, Value: 12000}, 10000, 10000},
+		{"zero value", &Discount{Type: DiscountPercent, Value: 0}, 10000, 0},
+		{"empty subtotal", &Discount{Type: DiscountFixed, Value: 500}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.discount.Amount(tt.subtotal); got != tt.want {
+				t.Errorf("Amount(%d) = %d, want %d", tt.subtotal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscountValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		subtotal int64
+		want     error
+	}{
+		{"percent", Discount{Type: DiscountPercent, Value: 25}, 1000, nil},
+		{"percent above 100", Discount{Type: DiscountPercent, Value: 101}, 1000, ErrInvalidDiscountValue},
+		{"negative percent", Discount{Type: DiscountPercent, Value: -1}, 1000, ErrInvalidDiscountValue},
+		{"fixed", Discount{Type: DiscountFixed, Value: 1000}, 1000, nil},
+		{"fixed above the subtotal", Discount{Type: DiscountFixed, Value: 1001}, 1000, ErrDiscountExceedsSubtotal},
+		{"negative fixed", Discount{Type: DiscountFixed, Value: -5}, 1000, ErrInvalidDiscountValue},
+		{"unknown type", Discount{Type: "BOGO", Value: 1}, 1000, ErrInvalidDiscountType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.discount.Validate(tt.subtotal)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Validate = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateTotalWithDiscount(t *testing.T) {
+	tests := []struct {
+		name         string
+		products     []OrderProduct
+		discount     *Discount
+		wantSubtotal int64
+		wantDiscount int64
+		wantTotal    int64
+	}{
+		{"no discount", lines(2, 3, 1999), nil, 11994, 0, 11994},
+		{"percent on odd cents", lines(2, 3, 1999), &Discount{Type: DiscountPercent, Value: 15}, 11994, 1799, 10195},
+		{"percent rounds the half cent up", lines(1, 1, 1005), &Discount{Type: DiscountPercent, Value: 10}, 1005, 101, 904},
+		{
+			name:         "addons count toward the subtotal",
+			products:     []OrderProduct{{ID: "p1", Name: "Burger", Price: 999, Quantity: 3, Addons: []OrderProductAddon{{ID: "a1", Name: "Cheese", Price: 151, Quantity: 1}}}},
+			discount:     &Discount{Type: DiscountPercent, Value: 7},
+			wantSubtotal: 3450,
+			wantDiscount: 242,
+			wantTotal:    3208,
+		},
+		{"fixed", lines(1, 2, 2500), &Discount{Type: DiscountFixed, Value: 1}, 5000, 1, 4999},
+		{"fixed equal to the subtotal", lines(1, 2, 2500), &Discount{Type: DiscountFixed, Value: 5000}, 5000, 5000, 0},
+		{"full percent leaves a free order", lines(3, 1, 333), &Discount{Type: DiscountPercent, Value: 100}, 999, 999, 0},
+		{"free order with a discount", lines(1, 1, 0), &Discount{Type: DiscountPercent, Value: 50}, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Order{Products: tt.products, Discount: tt.discount}
+			o.CalculateTotal()
+			if o.Subtotal != tt.wantSubtotal || o.DiscountAmount != tt.wantDiscount || o.Total != tt.wantTotal {
+				t.Errorf("subtotal/discount/total = %d/%d/%d, want %d/%d/%d", o.Subtotal, o.DiscountAmount, o.Total, tt.wantSubtotal, tt.wantDiscount, tt.wantTotal)
+			}
+			if o.Total != o.Subtotal-o.DiscountAmount || o.Total < 0 {
+				t.Errorf("total %d is not subtotal %d minus discount %d", o.Total, o.Subtotal, o.DiscountAmount)
+			}
+			if got := o.ComputeTotal(); got != o.Total {
+				t.Errorf("ComputeTotal = %d, want %d", got, o.Total)
+			}
+		})
+	}
+}
+
+func TestServiceDiscounts(t *testing.T) {
+	ctx := context.Background()
+	tenPercent := &Discount{Type: DiscountPercent, Value: 10, Description: "Happy hour"}
+	note := "Window seat"
+
+	t.Run("create", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			discount  *Discount
+			wantTotal int64
+			wantErr   error
+		}{
+			{"percent", tenPercent, 20700, nil},
+			{"fixed", &Discount{Type: DiscountFixed, Value: 2000}, 21000, nil},
+			{"zero value is no discount", &Discount{Type: DiscountPercent, Value: 0}, 23000, nil},
+			{"percent above 100", &Discount{Type: DiscountPercent, Value: 150}, 0, ErrInvalidDiscountValue},
+			{"fixed above the subtotal", &Discount{Type: DiscountFixed, Value: 23001}, 0, ErrDiscountExceedsSubtotal},
+			{"unknown type", &Discount{Type: "BOGO", Value: 1}, 0, ErrInvalidDiscountType},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := newMemoryRepository()
+				input := onSiteInput([]OrderProduct{{ID: "p1", Name: "Burger", Price: 10000, Quantity: 2}, {ID: "p2", Name: "Soda", Price: 3000, Quantity: 1}})
+				input.Discount = tt.discount
+
+				o, err := NewService(repo).Create(ctx, input)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("err = %v, want %v", err, tt.wantErr)
+					}
+					if len(repo.orders) != 0 {
+						t.Error("order stored with an invalid discount")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if o.Subtotal != 23000 || o.Total != tt.wantTotal || o.DiscountAmount != 23000-tt.wantTotal {
+					t.Errorf("subtotal/discount/total = %d/%d/%d, want 23000/%d/%d", o.Subtotal, o.DiscountAmount, o.Total, 23000-tt.wantTotal, tt.wantTotal)
+				}
+				if tt.discount.Value == 0 && o.Discount != nil {
+					t.Errorf("discount = %+v, want none", o.Discount)
+				}
+			})
+		}
+	})
+
+	t.Run("modify", func(t *testing.T) {
+		// storedOrder has a 23333 subtotal
+		tests := []struct {
+			name         string
+			current      *Discount
+			input        ModifyInput
+			wantDiscount *Discount
+			wantTotal    int64
+			wantErr      error
+		}{
+			{"adds a discount", nil, ModifyInput{Discount: tenPercent}, tenPercent, 21000, nil},
+			{"replaces the discount", tenPercent, ModifyInput{Discount: &Discount{Type: DiscountFixed, Value: 333}}, &Discount{Type: DiscountFixed, Value: 333}, 23000, nil},
+			{"zero value removes it", tenPercent, ModifyInput{Discount: &Discount{Type: DiscountFixed}}, nil, 23333, nil},
+			{"kept when omitted", tenPercent, ModifyInput{Note: &note}, tenPercent, 21000, nil},
+			{
+				name:         "recalculated with the new lines",
+				current:      tenPercent,
+				input:        ModifyInput{Products: []OrderProduct{{ID: "p1", Name: "Burger", Price: 10005, Quantity: 1}}},
+				wantDiscount: tenPercent,
+				wantTotal:    9004,
+			},
+			{
+				name:    "fixed discount above the new subtotal",
+				current: &Discount{Type: DiscountFixed, Value: 20000},
+				input:   ModifyInput{Products: []OrderProduct{{ID: "p2", Name: "Soda", Price: 3333, Quantity: 1}}},
+				wantErr: ErrDiscountExceedsSubtotal,
+			},
+			{"percent above 100", nil, ModifyInput{Discount: &Discount{Type: DiscountPercent, Value: 101}}, nil, 0, ErrInvalidDiscountValue},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				stored := storedOrder(1, 23333, 0, 23333, nil)
+				stored.Discount = tt.current
+				stored.CalculateTotal()
+				repo := newMemoryRepository(stored)
+
+				result, err := NewService(repo).Modify(ctx, stored.Code, tt.input)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("err = %v, want %v", err, tt.wantErr)
+					}
+					if got := repo.stored(stored.ID); got.Version != 1 {
+						t.Errorf("order saved at version %d despite the error", got.Version)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				o := repo.stored(stored.ID)
+				if !equalDiscount(o.Discount, tt.wantDiscount) {
+					t.Errorf("discount = %+v, want %+v", o.Discount, tt.wantDiscount)
+				}
+				if o.Total != tt.wantTotal || o.Total != o.Subtotal-o.DiscountAmount || result.Order.Total != o.Total {
+					t.Errorf("subtotal/discount/total = %d/%d/%d, want total %d", o.Subtotal, o.DiscountAmount, o.Total, tt.wantTotal)
+				}
+			})
+		}
+	})
+}
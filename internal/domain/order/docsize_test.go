@@ -0,0 +1,108 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// jsonSize stands in for the BSON size the repository measures
+func jsonSize(doc interface{}) (int, error) {
+	b, err := json.Marshal(doc)
+	return len(b), err
+}
+
+// sizeLimitFor returns a limit that an order with n lines just fits
+func sizeLimitFor(t *testing.T, n int) int {
+	t.Helper()
+	o, err := NewService(newMemoryRepository()).ValidateCreate(context.Background(), onSiteInput(lines(n, 1, 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, err := jsonSize(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return size + 20 // Codes and timestamps vary slightly in length
+}
+
+func TestCreateRejectsOversizedOrders(t *testing.T) {
+	maxBytes := sizeLimitFor(t, 30)
+
+	tests := []struct {
+		name        string
+		lines       int
+		wantErr     error
+		wantWarning bool
+	}{
+		{"small order", 1, nil, false},
+		{"near the limit warns", 30, nil, true},
+		{"over the limit", 32, ErrOrderTooLarge, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			warned := false
+			limit := util.DocumentSizeLimit{MaxBytes: maxBytes, Size: jsonSize, OnNearLimit: func(string, int, int) { warned = true }}
+
+			_, err := NewService(repo, WithDocumentSizeLimit(limit)).Create(context.Background(), onSiteInput(lines(tt.lines, 1, 100)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if warned != tt.wantWarning {
+				t.Errorf("warned = %v, want %v", warned, tt.wantWarning)
+			}
+			if err == nil {
+				return
+			}
+			if !strings.Contains(err.Error(), fmt.Sprintf("max %d", maxBytes)) {
+				t.Errorf("error %q does not name the limit", err)
+			}
+			if len(repo.orders) != 0 {
+				t.Error("oversized order was stored")
+			}
+		})
+	}
+}
+
+func TestModifyRejectsOversizedOrders(t *testing.T) {
+	maxBytes := sizeLimitFor(t, 30)
+
+	tests := []struct {
+		name    string
+		lines   int
+		wantErr error
+	}{
+		{"grows within the limit", 2, nil},
+		{"grows past the limit", 32, ErrOrderTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithDocumentSizeLimit(util.DocumentSizeLimit{MaxBytes: maxBytes, Size: jsonSize}))
+			o, err := svc.Create(context.Background(), onSiteInput(lines(1, 1, 100)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = svc.Modify(context.Background(), o.Code, ModifyInput{Products: lines(tt.lines, 1, 100)})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.lines
+			if err != nil {
+				want = 1
+			}
+			if got := len(repo.stored(o.ID).Products); got != want {
+				t.Errorf("stored lines = %d, want %d", got, want)
+			}
+		})
+	}
+}
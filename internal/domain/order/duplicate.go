@@ -0,0 +1,186 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Reasons a line of the source order could not be repeated
+const (
+	UnavailableRemoved          = "removed"           // The product no longer exists
+	UnavailableOutOfStock       = "unavailable"       // The product is disabled or out of stock
+	UnavailablePriceUnset       = "price_unset"       // The line's price variation no longer exists and the product has several
+	UnavailableVariationRemoved = "variation_removed" // The line's variation type no longer exists on the product
+)
+
+// CatalogProduct is the current state of a product, used to re-validate order lines
+type CatalogProduct struct {
+	ID         string
+	Name       string
+	Available  bool             // Enabled and, with limited stock, at least one unit left
+	Prices     []int64          // Current price of every price variation, in cents
+	Variations map[string]int64 // Current price of every price variation by type, in cents
+}
+
+// Catalog looks up the current state of products
+type Catalog interface {
+	// FindCatalogProducts returns the products that still exist, keyed by ID
+	FindCatalogProducts(ctx context.Context, ids []string) (map[string]CatalogProduct, error)
+}
+
+// DuplicateInput represents input for repeating an existing order
+type DuplicateInput struct {
+	SaleType    *SaleType // When set, must match the source order
+	Channel     Channel   // Defaults to ChannelOther when empty
+	TableNumber *int      // ON_SITE only; defaults to the source order's table
+	Strict      bool      // Refuse the duplicate if any line is unavailable instead of dropping it
+}
+
+// PriceChange is a line whose current catalog price differs from the source order
+type PriceChange struct {
+	ProductID string
+	Name      string
+	OldPrice  int64
+	NewPrice  int64
+}
+
+// UnavailableLine is a source order line that could not be repeated
+type UnavailableLine struct {
+	ProductID string
+	Name      string
+	Quantity  int
+	Reason    string
+}
+
+// DuplicateResult reports the new order and how it differs from the source
+type DuplicateResult struct {
+	Source       string // Code of the duplicated order
+	Order        *Order // nil when the duplicate was refused
+	PriceChanges []PriceChange
+	Unavailable  []UnavailableLine // Dropped lines, or the lines that blocked a strict duplicate
+}
+
+// Duplicate creates a new order repeating the lines of an existing one at current catalog prices.
+// Customer, sale type and shipping address are copied; payment fields and the note are not.
+// Unavailable lines are dropped, or refuse the whole duplicate with ErrDuplicateItemsUnavailable in strict mode;
+// the result is returned alongside that error so callers can show the offending lines.
+func (s *Service) Duplicate(ctx context.Context, code string, input DuplicateInput) (*DuplicateResult, error) {
+	if s.catalog == nil {
+		return nil, ErrDuplicateNotConfigured
+	}
+
+	source, err := s.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if input.SaleType != nil && *input.SaleType != source.SaleType {
+		return nil, ErrDuplicateSaleTypeMismatch
+	}
+
+	ids := make([]string, len(source.Products))
+	for i, line := range source.Products {
+		ids[i] = line.ID
+	}
+	current, err := s.catalog.FindCatalogProducts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up catalog products: %w", err)
+	}
+
+	result := &DuplicateResult{Source: source.Code}
+	products := repriceLines(source.Products, current, result)
+	if len(result.Unavailable) > 0 && input.Strict {
+		return result, ErrDuplicateItemsUnavailable
+	}
+	if len(products) == 0 {
+		return result, ErrDuplicateItemsUnavailable
+	}
+
+	tableNumber := input.TableNumber
+	if tableNumber == nil {
+		tableNumber = source.TableNumber
+	}
+	var customer *Customer
+	if source.Customer != nil {
+		copied := *source.Customer
+		customer = &copied
+	}
+
+	o, err := s.Create(ctx, CreateInput{
+		CompanyID:       source.TenantCompanyID(),
+		SalePointID:     source.TenantSalePointID(),
+		SaleType:        source.SaleType,
+		Channel:         input.Channel,
+		Products:        products,
+		Customer:        customer,
+		ShippingAddress: source.ShippingAddress,
+		TableNumber:     tableNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Order = o
+	return result, nil
+}
+
+// repriceLines returns the lines that can be repeated at their current price,
+// recording price changes and unavailable lines in the result
+func repriceLines(lines []OrderProduct, current map[string]CatalogProduct, result *DuplicateResult) []OrderProduct {
+	products := make([]OrderProduct, 0, len(lines))
+	for _, line := range lines {
+		unavailable := UnavailableLine{ProductID: line.ID, Name: line.Name, Quantity: line.Quantity}
+
+		p, ok := current[line.ID]
+		if !ok {
+			unavailable.Reason = UnavailableRemoved
+			result.Unavailable = append(result.Unavailable, unavailable)
+			continue
+		}
+		if !p.Available {
+			unavailable.Reason = UnavailableOutOfStock
+			result.Unavailable = append(result.Unavailable, unavailable)
+			continue
+		}
+
+		price, ok := currentPrice(line.Price, p.Prices)
+		if line.VariationType != nil {
+			// Lines that record their variation are repriced from it
+			price, ok = p.Variations[*line.VariationType]
+			if !ok {
+				unavailable.Reason = UnavailableVariationRemoved
+				result.Unavailable = append(result.Unavailable, unavailable)
+				continue
+			}
+		}
+		if !ok {
+			unavailable.Reason = UnavailablePriceUnset
+			result.Unavailable = append(result.Unavailable, unavailable)
+			continue
+		}
+		if price != line.Price {
+			result.PriceChanges = append(result.PriceChanges, PriceChange{ProductID: line.ID, Name: p.Name, OldPrice: line.Price, NewPrice: price})
+		}
+
+		repeated := line
+		repeated.Name = p.Name
+		repeated.Price = price
+		repeated.SelectedObservations = slices.Clone(line.SelectedObservations)
+		repeated.Addons = slices.Clone(line.Addons)
+		products = append(products, repeated)
+	}
+	return products
+}
+
+// currentPrice resolves the current price of a line stored before lines recorded their price variation:
+// the old price is kept while some variation still has it; otherwise the price is only
+// known when the product has a single variation.
+func currentPrice(old int64, prices []int64) (int64, bool) {
+	if slices.Contains(prices, old) {
+		return old, true
+	}
+	if len(prices) == 1 {
+		return prices[0], true
+	}
+	return 0, false
+}
@@ -0,0 +1,214 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// catalogStub serves fixed catalog products
+type catalogStub map[string]CatalogProduct
+
+func (c catalogStub) FindCatalogProducts(ctx context.Context, ids []string) (map[string]CatalogProduct, error) {
+	found := make(map[string]CatalogProduct)
+	for _, id := range ids {
+		if p, ok := c[id]; ok {
+			found[id] = p
+		}
+	}
+	return found, nil
+}
+
+// duplicateSource is a paid delivery order with a note, a repeated burger and a soda
+func duplicateSource(status OrderStatus) *Order {
+	address := "Calle 1 # 2-3"
+	receipt := "https://example.com/receipt.png"
+	note := "Ring twice"
+	o := storedOrder(1, 23333, 0, 23333, nil)
+	o.Status = status
+	o.SaleType = SaleTypeDelivery
+	o.TableNumber = nil
+	o.ShippingAddress = &address
+	o.Customer = &Customer{Identification: "1020304050", IDType: "CC", Name: "Ana", Phone: "300 123 4567"}
+	o.PaymentStatus = PaymentConfirmed
+	o.PaymentReceiptURL = &receipt
+	o.Note = &note
+	o.Products = []OrderProduct{
+		{ID: "p1", Name: "Burger", Price: 10000, Quantity: 2},
+		{ID: "p2", Name: "Soda", Price: 3333, Quantity: 1},
+	}
+	return o
+}
+
+func TestDuplicate(t *testing.T) {
+	burger := CatalogProduct{ID: "p1", Name: "Burger", Available: true, Prices: []int64{8000, 10000}}
+	soda := CatalogProduct{ID: "p2", Name: "Soda", Available: true, Prices: []int64{3333}}
+	with := func(p CatalogProduct, change func(*CatalogProduct)) CatalogProduct {
+		change(&p)
+		return p
+	}
+	deliveryType, onSiteType := SaleTypeDelivery, SaleTypeOnSite
+
+	tests := []struct {
+		name            string
+		status          OrderStatus
+		catalog         catalogStub
+		input           DuplicateInput
+		wantErr         error
+		wantLines       map[string]int64 // Price by product ID of the new order
+		wantChanges     []PriceChange
+		wantUnavailable map[string]string // Reason by product ID
+	}{
+		{
+			name:      "unchanged catalog",
+			status:    StatusDelivered,
+			catalog:   catalogStub{"p1": burger, "p2": soda},
+			wantLines: map[string]int64{"p1": 10000, "p2": 3333},
+		},
+		{
+			name:        "price drift",
+			status:      StatusDelivered,
+			catalog:     catalogStub{"p1": with(burger, func(p *CatalogProduct) { p.Prices = []int64{8000, 10000, 12000} }), "p2": with(soda, func(p *CatalogProduct) { p.Prices = []int64{3000} })},
+			wantLines:   map[string]int64{"p1": 10000, "p2": 3000},
+			wantChanges: []PriceChange{{ProductID: "p2", Name: "Soda", OldPrice: 3333, NewPrice: 3000}},
+		},
+		{
+			name:        "price drift in strict mode",
+			status:      StatusDelivered,
+			catalog:     catalogStub{"p1": burger, "p2": with(soda, func(p *CatalogProduct) { p.Prices = []int64{3000} })},
+			input:       DuplicateInput{Strict: true},
+			wantLines:   map[string]int64{"p1": 10000, "p2": 3000},
+			wantChanges: []PriceChange{{ProductID: "p2", Name: "Soda", OldPrice: 3333, NewPrice: 3000}},
+		},
+		{
+			name:            "removed product dropped",
+			status:          StatusDelivered,
+			catalog:         catalogStub{"p1": burger},
+			wantLines:       map[string]int64{"p1": 10000},
+			wantUnavailable: map[string]string{"p2": UnavailableRemoved},
+		},
+		{
+			name:            "out of stock dropped",
+			status:          StatusDelivered,
+			catalog:         catalogStub{"p1": burger, "p2": with(soda, func(p *CatalogProduct) { p.Available = false })},
+			wantLines:       map[string]int64{"p1": 10000},
+			wantUnavailable: map[string]string{"p2": UnavailableOutOfStock},
+		},
+		{
+			name:            "out of stock in strict mode",
+			status:          StatusDelivered,
+			catalog:         catalogStub{"p1": burger, "p2": with(soda, func(p *CatalogProduct) { p.Available = false })},
+			input:           DuplicateInput{Strict: true},
+			wantErr:         ErrDuplicateItemsUnavailable,
+			wantUnavailable: map[string]string{"p2": UnavailableOutOfStock},
+		},
+		{
+			name:            "price unknown among several variations",
+			status:          StatusDelivered,
+			catalog:         catalogStub{"p1": burger, "p2": with(soda, func(p *CatalogProduct) { p.Prices = []int64{2500, 4000} })},
+			wantLines:       map[string]int64{"p1": 10000},
+			wantUnavailable: map[string]string{"p2": UnavailablePriceUnset},
+		},
+		{
+			name:            "nothing left",
+			status:          StatusDelivered,
+			catalog:         catalogStub{},
+			wantErr:         ErrDuplicateItemsUnavailable,
+			wantUnavailable: map[string]string{"p1": UnavailableRemoved, "p2": UnavailableRemoved},
+		},
+		{
+			name:      "cancelled order",
+			status:    StatusCancelled,
+			catalog:   catalogStub{"p1": burger, "p2": soda},
+			wantLines: map[string]int64{"p1": 10000, "p2": 3333},
+		},
+		{
+			name:      "same sale type",
+			status:    StatusDelivered,
+			catalog:   catalogStub{"p1": burger, "p2": soda},
+			input:     DuplicateInput{SaleType: &deliveryType},
+			wantLines: map[string]int64{"p1": 10000, "p2": 3333},
+		},
+		{
+			name:    "other sale type",
+			status:  StatusDelivered,
+			catalog: catalogStub{"p1": burger, "p2": soda},
+			input:   DuplicateInput{SaleType: &onSiteType},
+			wantErr: ErrDuplicateSaleTypeMismatch,
+		},
+		{
+			name:    "no catalog",
+			status:  StatusDelivered,
+			wantErr: ErrDuplicateNotConfigured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := duplicateSource(tt.status)
+			repo := newMemoryRepository(source)
+			var opts []Option
+			if tt.catalog != nil {
+				opts = append(opts, WithCatalog(tt.catalog))
+			}
+			svc := NewService(repo, opts...)
+
+			result, err := svc.Duplicate(context.Background(), source.Code, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if result != nil {
+				unavailable := make(map[string]string)
+				for _, line := range result.Unavailable {
+					unavailable[line.ProductID] = line.Reason
+				}
+				if len(unavailable) > 0 || len(tt.wantUnavailable) > 0 {
+					if !reflect.DeepEqual(unavailable, tt.wantUnavailable) {
+						t.Errorf("unavailable = %v, want %v", unavailable, tt.wantUnavailable)
+					}
+				}
+			}
+			if tt.wantErr != nil {
+				if len(repo.orders) != 1 {
+					t.Errorf("a refused duplicate stored %d orders", len(repo.orders)-1)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(result.PriceChanges, tt.wantChanges) {
+				t.Errorf("price changes = %+v, want %+v", result.PriceChanges, tt.wantChanges)
+			}
+			o := repo.stored(result.Order.ID)
+			if o == nil || o.ID == source.ID || o.Code == source.Code || result.Source != source.Code {
+				t.Fatalf("duplicate %+v is not a new order of %s", result.Order, source.Code)
+			}
+
+			prices := make(map[string]int64)
+			var total int64
+			for _, line := range o.Products {
+				prices[line.ID] = line.Price
+				total += line.Price * int64(line.Quantity)
+			}
+			if !reflect.DeepEqual(prices, tt.wantLines) {
+				t.Errorf("lines = %v, want %v", prices, tt.wantLines)
+			}
+			if o.Total != total {
+				t.Errorf("total = %d, want %d from the current prices", o.Total, total)
+			}
+
+			if o.Status != StatusCreated || o.SaleType != source.SaleType {
+				t.Errorf("status %s, sale type %s, want CREATED %s", o.Status, o.SaleType, source.SaleType)
+			}
+			if o.Customer == nil || o.Customer.Identification != source.Customer.Identification || o.ShippingAddress == nil || *o.ShippingAddress != *source.ShippingAddress {
+				t.Errorf("customer %+v, address %v not copied", o.Customer, o.ShippingAddress)
+			}
+			if o.CurrentPaymentStatus() == PaymentConfirmed || o.PaymentReceiptURL != nil {
+				t.Errorf("payment copied: %s %v", o.PaymentStatus, o.PaymentReceiptURL)
+			}
+			if o.Note != nil {
+				t.Errorf("note copied: %q", *o.Note)
+			}
+		})
+	}
+}
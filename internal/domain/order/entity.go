@@ -3,10 +3,16 @@ package order
 import (
 	"fmt"
 	"time"
+	"unicode/utf8"
 
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/util"
 	"github.com/google/uuid"
 )
 
+// MaxTextLength is the maximum number of characters for notes and observations
+const MaxTextLength = 500
+
 // OrderStatus represents the status of an order
 type OrderStatus string
 
@@ -19,6 +25,30 @@ const (
 	StatusCancelled      OrderStatus = "CANCELLED"
 )
 
+// AllStatuses lists every order status in lifecycle order
+var AllStatuses = []OrderStatus{
+	StatusCreated,
+	StatusVerified,
+	StatusInProgress,
+	StatusOutForDelivery,
+	StatusDelivered,
+	StatusCancelled,
+}
+
+// OpenStatuses lists the non-terminal order statuses
+var OpenStatuses = []OrderStatus{
+	StatusCreated,
+	StatusVerified,
+	StatusInProgress,
+	StatusOutForDelivery,
+}
+
+// TerminalStatuses lists the statuses an order can no longer leave
+var TerminalStatuses = []OrderStatus{
+	StatusDelivered,
+	StatusCancelled,
+}
+
 // SaleType represents the type of sale
 type SaleType string
 
@@ -27,6 +57,55 @@ const (
 	SaleTypeOnSite   SaleType = "ON_SITE"
 )
 
+// AllSaleTypes lists every sale type
+var AllSaleTypes = []SaleType{
+	SaleTypeDelivery,
+	SaleTypeOnSite,
+}
+
+// Channel represents the source an order was placed from
+type Channel string
+
+const (
+	ChannelWeb      Channel = "WEB"
+	ChannelPOS      Channel = "POS"
+	ChannelWhatsApp Channel = "WHATSAPP"
+	ChannelPhone    Channel = "PHONE"
+	ChannelOther    Channel = "OTHER"
+)
+
+// AllChannels lists every order channel
+var AllChannels = []Channel{
+	ChannelWeb,
+	ChannelPOS,
+	ChannelWhatsApp,
+	ChannelPhone,
+	ChannelOther,
+}
+
+// IsValidChannel checks if the channel is valid
+func IsValidChannel(channel Channel) bool {
+	for _, c := range AllChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// NoteVisibility controls whether the order note may be shown on public endpoints
+type NoteVisibility string
+
+const (
+	NoteInternal NoteVisibility = "INTERNAL" // Staff only; the default for notes written by staff
+	NotePublic   NoteVisibility = "PUBLIC"   // Shown to the customer; the default for notes sent with a new order
+)
+
+// IsValidNoteVisibility checks if the note visibility is valid
+func IsValidNoteVisibility(visibility NoteVisibility) bool {
+	return visibility == NoteInternal || visibility == NotePublic
+}
+
 // IDType represents the type of identification
 type IDType string
 
@@ -37,91 +116,184 @@ const (
 	IDTypeNIT      IDType = "NIT" // Tax ID for companies
 )
 
+// AllIDTypes lists every customer identification type
+var AllIDTypes = []IDType{
+	IDTypeCC,
+	IDTypeCE,
+	IDTypePassport,
+	IDTypeNIT,
+}
+
 // Order represents a sales order
 type Order struct {
-	ID                string         `json:"id" bson:"_id"`
-	Code              string         `json:"code" bson:"code"`
-	Status            OrderStatus    `json:"status" bson:"status"`
-	SaleType          SaleType       `json:"sale_type" bson:"sale_type"`
-	Products          []OrderProduct `json:"products" bson:"products"`
-	Total             int64          `json:"total" bson:"total"` // In cents
-	Note              *string        `json:"note,omitempty" bson:"note,omitempty"`
-	Customer          *Customer      `json:"customer,omitempty" bson:"customer,omitempty"`
-	ShippingAddress   *string        `json:"shipping_address,omitempty" bson:"shipping_address,omitempty"`
-	TableNumber       *int           `json:"table_number,omitempty" bson:"table_number,omitempty"`
-	PaymentReceiptURL *string        `json:"payment_receipt_url,omitempty" bson:"payment_receipt_url,omitempty"`
-	PaymentAccountID  *string        `json:"payment_account_id,omitempty" bson:"payment_account_id,omitempty"`
-	CreatedAt         time.Time      `json:"created_at" bson:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at" bson:"updated_at"`
+	ID                string            `json:"id" bson:"_id"`
+	Code              string            `json:"code" bson:"code"`
+	CompanyID         string            `json:"company_id" bson:"company_id,omitempty"`       // Empty on orders stored before tenants, see DefaultTenant
+	SalePointID       string            `json:"sale_point_id" bson:"sale_point_id,omitempty"` // Empty on orders stored before tenants, see DefaultTenant
+	Status            OrderStatus       `json:"status" bson:"status"`
+	SaleType          SaleType          `json:"sale_type" bson:"sale_type"`
+	Channel           Channel           `json:"channel" bson:"channel"`
+	Products          []OrderProduct    `json:"products" bson:"products"`
+	Subtotal          int64             `json:"subtotal" bson:"subtotal"` // Sum of the lines before the discount, in cents; 0 on orders stored before discounts existed
+	Discount          *Discount         `json:"discount,omitempty" bson:"discount,omitempty"`
+	DiscountAmount    int64             `json:"discount_amount" bson:"discount_amount,omitempty"` // In cents
+	Total             int64             `json:"total" bson:"total"`                               // Subtotal minus the discount, in cents
+	Notes             []OrderNote       `json:"notes,omitempty" bson:"notes,omitempty"`           // Append-only, oldest first; read through NoteList
+	Note              *string           `json:"-" bson:"note,omitempty"`                          // Single note of orders stored before Notes existed, moved into Notes on the next note change
+	NoteVisibility    NoteVisibility    `json:"-" bson:"note_visibility,omitempty"`               // Visibility of the legacy Note; empty is read as INTERNAL
+	Customer          *Customer         `json:"customer,omitempty" bson:"customer,omitempty"`
+	ShippingAddress   *string           `json:"shipping_address,omitempty" bson:"shipping_address,omitempty"`
+	TableNumber       *int              `json:"table_number,omitempty" bson:"table_number,omitempty"`
+	PaymentReceiptURL *string           `json:"payment_receipt_url,omitempty" bson:"payment_receipt_url,omitempty"`
+	PaymentAccountID  *string           `json:"payment_account_id,omitempty" bson:"payment_account_id,omitempty"`
+	PaymentStatus     PaymentStatus     `json:"payment_status,omitempty" bson:"payment_status,omitempty"`       // Empty on orders stored before payment statuses existed, see CurrentPaymentStatus
+	TotalAdjustments  []TotalAdjustment `json:"total_adjustments,omitempty" bson:"total_adjustments,omitempty"` // Audit trail of total corrections
+	StatusHistory     []StatusChange    `json:"status_history,omitempty" bson:"status_history,omitempty"`
+	VerifiedAt        *time.Time        `json:"verified_at,omitempty" bson:"verified_at,omitempty"` // First time the order reached each status, see StatusTime
+	InProgressAt      *time.Time        `json:"in_progress_at,omitempty" bson:"in_progress_at,omitempty"`
+	OutForDeliveryAt  *time.Time        `json:"out_for_delivery_at,omitempty" bson:"out_for_delivery_at,omitempty"`
+	DeliveredAt       *time.Time        `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+	CancelledAt       *time.Time        `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+	EditHistory       []FieldEdit       `json:"edit_history,omitempty" bson:"edit_history,omitempty"` // Audit trail of field edits and modifications
+	ArchivedAt        *time.Time        `json:"archived_at,omitempty" bson:"archived_at,omitempty"`   // Set once moved to the archive (read-only)
+	Version           int64             `json:"version" bson:"version"`                               // Incremented on every update, see checkVersion
+	CreatedAt         time.Time         `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at" bson:"updated_at"`
 }
 
 // OrderProduct represents a product in an order
 type OrderProduct struct {
-	ID          string  `json:"id" bson:"id"`
-	Name        string  `json:"name" bson:"name"`
-	Description *string `json:"description,omitempty" bson:"description,omitempty"`
-	Observation *string `json:"observation,omitempty" bson:"observation,omitempty"`
-	Price       int64   `json:"price" bson:"price"` // In cents
-	Quantity    int     `json:"quantity" bson:"quantity"`
+	ID                   string              `json:"id" bson:"id"`
+	Name                 string              `json:"name" bson:"name"`
+	Description          *string             `json:"description,omitempty" bson:"description,omitempty"`
+	Observation          *string             `json:"observation,omitempty" bson:"observation,omitempty"`
+	SelectedObservations []string            `json:"selected_observations,omitempty" bson:"selected_observations,omitempty"` // Picked from the product's quick observations
+	VariationType        *string             `json:"variation_type,omitempty" bson:"variation_type,omitempty"`               // Price variation bought, e.g. "Grande"
+	Price                int64               `json:"price" bson:"price"`                                                     // In cents, without addons
+	Addons               []OrderProductAddon `json:"addons,omitempty" bson:"addons,omitempty"`                               // Selected for every unit of the line
+	Quantity             int                 `json:"quantity" bson:"quantity"`
+}
+
+// sameItem reports whether both lines sell the same thing: product, price variation and addons
+func sameItem(a, b OrderProduct) bool {
+	return a.ID == b.ID && equalText(a.VariationType, b.VariationType) && sameAddons(a, b)
+}
+
+// TotalAdjustment records a correction of the stored order total
+type TotalAdjustment struct {
+	PreviousTotal int64     `json:"previous_total" bson:"previous_total"` // In cents
+	NewTotal      int64     `json:"new_total" bson:"new_total"`           // In cents
+	Reason        string    `json:"reason" bson:"reason"`
+	AdjustedAt    time.Time `json:"adjusted_at" bson:"adjusted_at"`
+}
+
+// Status change actors
+const (
+	ActorUser     = "user"     // Requested through the API
+	ActorSystem   = "system"   // Applied automatically, e.g. by auto-advance rules
+	ActorCustomer = "customer" // Requested by the customer through the tracking code
+)
+
+// StatusChange records a single status transition
+type StatusChange struct {
+	From      OrderStatus `json:"from" bson:"from"`
+	To        OrderStatus `json:"to" bson:"to"`
+	Actor     string      `json:"actor" bson:"actor"`
+	ChangedAt time.Time   `json:"changed_at" bson:"changed_at"`
 }
 
 // Customer represents customer information
 type Customer struct {
-	Identification string `json:"identification" bson:"identification"`
-	IDType         IDType `json:"id_type" bson:"id_type"`
-	Name           string `json:"name" bson:"name"`
-	Phone          string `json:"phone" bson:"phone"`
+	Identification  string `json:"identification" bson:"identification"`
+	IDType          IDType `json:"id_type" bson:"id_type"`
+	Name            string `json:"name" bson:"name"`
+	Phone           string `json:"phone" bson:"phone"`                                           // As entered
+	PhoneNormalized string `json:"phone_normalized,omitempty" bson:"phone_normalized,omitempty"` // E.164, used for lookups
+}
+
+// NormalizePhone stores the E.164 form of the customer's phone next to the raw input
+func (c *Customer) NormalizePhone(defaultCountryCode string) error {
+	if c.Phone == "" {
+		c.PhoneNormalized = ""
+		return nil
+	}
+	normalized, err := NormalizePhone(c.Phone, defaultCountryCode)
+	if err != nil {
+		return apperrors.NewDomainError(err, "customer.phone", c.Phone)
+	}
+	c.PhoneNormalized = normalized
+	return nil
 }
 
 // NewOrder creates a new order
 func NewOrder(saleType SaleType, products []OrderProduct) *Order {
 	now := time.Now()
 	order := &Order{
-		ID:        uuid.New().String(),
-		Code:      generateOrderCode(),
-		Status:    StatusCreated,
-		SaleType:  saleType,
-		Products:  products,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:            uuid.New().String(),
+		Code:          generateOrderCode(),
+		Status:        StatusCreated,
+		PaymentStatus: PaymentPending,
+		SaleType:      saleType,
+		Version:       1,
+		Channel:       ChannelOther,
+		Products:      products,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 	order.CalculateTotal()
 	return order
 }
 
-// CalculateTotal calculates the total amount from products
+// CalculateTotal calculates the subtotal from products and the total after the discount
 func (o *Order) CalculateTotal() {
-	total := int64(0)
+	o.Subtotal = o.ComputeSubtotal()
+	o.DiscountAmount = o.Discount.Amount(o.Subtotal)
+	o.Total = o.Subtotal - o.DiscountAmount
+}
+
+// ComputeTotal returns the total amount after the discount without modifying the order
+func (o *Order) ComputeTotal() int64 {
+	subtotal := o.ComputeSubtotal()
+	return subtotal - o.Discount.Amount(subtotal)
+}
+
+// ComputeSubtotal returns the total amount from products and their addons, before the discount
+func (o *Order) ComputeSubtotal() int64 {
+	subtotal := int64(0)
 	for _, product := range o.Products {
-		total += product.Price * int64(product.Quantity)
+		subtotal += product.LineTotal()
 	}
-	o.Total = total
+	return subtotal
 }
 
-// Validate validates the order business rules
+// Validate validates the order business rules.
+// Errors are returned as *apperrors.DomainError with the offending field.
 func (o *Order) Validate() error {
 	// Validate products
 	if len(o.Products) == 0 {
-		return ErrNoProducts
+		return apperrors.NewDomainError(ErrNoProducts, "products", nil)
 	}
 
 	for i, product := range o.Products {
 		if product.ID == "" {
-			return ErrInvalidProductID
+			return apperrors.NewIndexedDomainError(ErrInvalidProductID, fmt.Sprintf("products[%d].id", i), i, product.ID)
 		}
 		if product.Name == "" {
-			return ErrInvalidProductName
+			return apperrors.NewIndexedDomainError(ErrInvalidProductName, fmt.Sprintf("products[%d].name", i), i, product.Name)
 		}
 		if product.Quantity <= 0 {
-			return ErrInvalidProductQuantity
+			return apperrors.NewIndexedDomainError(ErrInvalidProductQuantity, fmt.Sprintf("products[%d].quantity", i), i, product.Quantity)
 		}
 		if product.Price < 0 {
-			return ErrInvalidProductPrice
+			return apperrors.NewIndexedDomainError(ErrInvalidProductPrice, fmt.Sprintf("products[%d].price", i), i, product.Price)
 		}
-		// Check for duplicate products
+		if err := product.validateAddons(i); err != nil {
+			return err
+		}
+		// Check for duplicate products; the same product in another variation or with other addons is a separate line
 		for j := i + 1; j < len(o.Products); j++ {
-			if o.Products[j].ID == product.ID {
-				return ErrDuplicateProduct
+			if sameItem(o.Products[j], product) {
+				return apperrors.NewIndexedDomainError(ErrDuplicateProduct, fmt.Sprintf("products[%d].id", j), j, product.ID)
 			}
 		}
 	}
@@ -130,37 +302,86 @@ func (o *Order) Validate() error {
 	switch o.SaleType {
 	case SaleTypeDelivery:
 		if o.Customer == nil {
-			return ErrCustomerRequiredForDelivery
+			return apperrors.NewDomainError(ErrCustomerRequiredForDelivery, "customer", nil)
 		}
 		if o.Customer.Name == "" {
-			return ErrCustomerNameRequired
+			return apperrors.NewDomainError(ErrCustomerNameRequired, "customer.name", nil)
 		}
 		if o.Customer.Phone == "" {
-			return ErrCustomerPhoneRequired
+			return apperrors.NewDomainError(ErrCustomerPhoneRequired, "customer.phone", nil)
 		}
 		if o.ShippingAddress == nil || *o.ShippingAddress == "" {
-			return ErrShippingAddressRequired
+			return apperrors.NewDomainError(ErrShippingAddressRequired, "shipping_address", nil)
 		}
 		if o.TableNumber != nil {
-			return ErrTableNumberNotAllowedForDelivery
+			return apperrors.NewDomainError(ErrTableNumberNotAllowedForDelivery, "table_number", *o.TableNumber)
 		}
 	case SaleTypeOnSite:
 		if o.TableNumber == nil {
-			return ErrTableNumberRequiredForOnSite
+			return apperrors.NewDomainError(ErrTableNumberRequiredForOnSite, "table_number", nil)
 		}
 		if *o.TableNumber <= 0 {
-			return ErrInvalidTableNumber
+			return apperrors.NewDomainError(ErrInvalidTableNumber, "table_number", *o.TableNumber)
 		}
 		if o.ShippingAddress != nil {
-			return ErrShippingAddressNotAllowedForOnSite
+			return apperrors.NewDomainError(ErrShippingAddressNotAllowedForOnSite, "shipping_address", nil)
 		}
 	default:
-		return ErrInvalidSaleType
+		return apperrors.NewDomainError(ErrInvalidSaleType, "sale_type", o.SaleType)
+	}
+
+	// Validate channel
+	if !IsValidChannel(o.Channel) {
+		return apperrors.NewDomainError(ErrInvalidChannel, "channel", o.Channel)
+	}
+
+	// Validate note visibility; empty is accepted on the legacy note, stored before visibility existed
+	if o.NoteVisibility != "" && !IsValidNoteVisibility(o.NoteVisibility) {
+		return apperrors.NewDomainError(ErrInvalidNoteVisibility, "note_visibility", o.NoteVisibility)
+	}
+	for i, note := range o.Notes {
+		if !IsValidNoteVisibility(note.Visibility) {
+			return apperrors.NewIndexedDomainError(ErrInvalidNoteVisibility, fmt.Sprintf("notes[%d].visibility", i), i, note.Visibility)
+		}
+	}
+
+	// Validate discount against the subtotal it applies to
+	if o.Discount != nil {
+		if err := o.Discount.Validate(o.ComputeSubtotal()); err != nil {
+			return err
+		}
 	}
 
 	// Validate status
 	if !o.IsValidStatus(o.Status) {
-		return ErrInvalidStatus
+		return apperrors.NewDomainError(ErrInvalidStatus, "status", o.Status)
+	}
+
+	return nil
+}
+
+// SanitizeText cleans the product free text fields (notes are sanitized when added).
+// Returns ErrBlankText if a provided value has no printable content
+// and ErrTextTooLong if it exceeds MaxTextLength characters.
+func (o *Order) SanitizeText() error {
+	for i := range o.Products {
+		description, err := sanitizeOptionalText(o.Products[i].Description)
+		if err != nil {
+			return apperrors.NewIndexedDomainError(err, fmt.Sprintf("products[%d].description", i), i, nil)
+		}
+		o.Products[i].Description = description
+
+		observation, err := sanitizeOptionalText(o.Products[i].Observation)
+		if err != nil {
+			return apperrors.NewIndexedDomainError(err, fmt.Sprintf("products[%d].observation", i), i, nil)
+		}
+		o.Products[i].Observation = observation
+
+		variation, err := sanitizeOptionalText(o.Products[i].VariationType)
+		if err != nil {
+			return apperrors.NewIndexedDomainError(err, fmt.Sprintf("products[%d].variation_type", i), i, nil)
+		}
+		o.Products[i].VariationType = variation
 	}
 
 	return nil
@@ -168,15 +389,7 @@ func (o *Order) Validate() error {
 
 // IsValidStatus checks if the status is valid
 func (o *Order) IsValidStatus(status OrderStatus) bool {
-	validStatuses := []OrderStatus{
-		StatusCreated,
-		StatusVerified,
-		StatusInProgress,
-		StatusOutForDelivery,
-		StatusDelivered,
-		StatusCancelled,
-	}
-	for _, s := range validStatuses {
+	for _, s := range AllStatuses {
 		if s == status {
 			return true
 		}
@@ -184,47 +397,10 @@ func (o *Order) IsValidStatus(status OrderStatus) bool {
 	return false
 }
 
-// CanTransitionTo checks if the order can transition to the given status
+// CanTransitionTo checks if the order can transition to the given status under the built-in
+// state machine of its sale type
 func (o *Order) CanTransitionTo(newStatus OrderStatus) bool {
-	// Define valid transitions
-	validTransitions := map[OrderStatus][]OrderStatus{
-		StatusCreated: {
-			StatusVerified,
-			StatusInProgress,
-			StatusCancelled,
-		},
-		StatusVerified: {
-			StatusInProgress,
-			StatusCancelled,
-		},
-		StatusInProgress: {
-			StatusOutForDelivery,
-			StatusDelivered, // Direct delivery for ON_SITE
-			StatusCancelled,
-		},
-		StatusOutForDelivery: {
-			StatusDelivered,
-			StatusCancelled,
-		},
-		StatusDelivered: {
-			// Terminal state - no transitions
-		},
-		StatusCancelled: {
-			// Terminal state - no transitions
-		},
-	}
-
-	allowedStatuses, exists := validTransitions[o.Status]
-	if !exists {
-		return false
-	}
-
-	for _, status := range allowedStatuses {
-		if status == newStatus {
-			return true
-		}
-	}
-	return false
+	return DefaultTransitionsFor(o.SaleType).Allows(o.Status, newStatus)
 }
 
 // CanBeModified checks if the order can be modified (products, address, etc.)
@@ -243,23 +419,37 @@ func (o *Order) CanBeModified() bool {
 	return true
 }
 
-// UpdateStatus updates the order status
-func (o *Order) UpdateStatus(newStatus OrderStatus) error {
+// UpdateStatus updates the order status, enforcing the given state machine
+func (o *Order) UpdateStatus(newStatus OrderStatus, transitions Transitions) error {
 	if !o.IsValidStatus(newStatus) {
 		return ErrInvalidStatus
 	}
 
-	if !o.CanTransitionTo(newStatus) {
-		return ErrInvalidStatusTransition
+	if !transitions.Allows(o.Status, newStatus) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, o.Status, newStatus)
 	}
 
-	o.Status = newStatus
-	o.UpdatedAt = time.Now()
+	o.changeStatus(newStatus, ActorUser, time.Now())
 	return nil
 }
 
-// UpdateProducts updates the order products and recalculates total
-func (o *Order) UpdateProducts(products []OrderProduct) error {
+// changeStatus sets the status and appends the transition to the status history
+func (o *Order) changeStatus(newStatus OrderStatus, actor string, at time.Time) {
+	o.StatusHistory = append(o.StatusHistory, StatusChange{
+		From:      o.Status,
+		To:        newStatus,
+		Actor:     actor,
+		ChangedAt: at,
+	})
+	o.Status = newStatus
+	o.recordStatusTime(newStatus, at)
+	o.UpdatedAt = at
+}
+
+// UpdateProducts updates the order products and recalculates total.
+// The order goes back to VERIFIED unless its state machine does not use that status; with
+// keepStatus only CREATED orders move to VERIFIED, so orders already in the kitchen stay there.
+func (o *Order) UpdateProducts(products []OrderProduct, transitions Transitions, keepStatus bool) error {
 	if !o.CanBeModified() {
 		return ErrOrderCannotBeModified
 	}
@@ -270,11 +460,34 @@ func (o *Order) UpdateProducts(products []OrderProduct) error {
 
 	o.Products = products
 	o.CalculateTotal()
-	o.Status = StatusVerified
+	if keepStatus && o.Status != StatusCreated {
+		o.UpdatedAt = time.Now()
+		return nil
+	}
+	if o.Status != StatusVerified && transitions.Uses(StatusVerified) {
+		o.changeStatus(StatusVerified, ActorUser, time.Now())
+	}
 	o.UpdatedAt = time.Now()
 	return nil
 }
 
+// sanitizeOptionalText sanitizes an optional free text value, leaving nil and empty values untouched
+func sanitizeOptionalText(value *string) (*string, error) {
+	if value == nil || *value == "" {
+		return value, nil
+	}
+
+	sanitized := util.SanitizeText(*value)
+	if sanitized == "" {
+		return nil, ErrBlankText
+	}
+	if utf8.RuneCountInString(sanitized) > MaxTextLength {
+		return nil, ErrTextTooLong
+	}
+
+	return &sanitized, nil
+}
+
 // generateOrderCode generates a unique order code
 func generateOrderCode() string {
 	timestamp := time.Now().UnixNano()
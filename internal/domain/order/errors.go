@@ -8,6 +8,7 @@ var (
 	ErrInvalidOrderID         = errors.New("invalid order ID")
 	ErrInvalidOrderCode       = errors.New("invalid order code")
 	ErrOrderCodeAlreadyExists = errors.New("order code already exists")
+	ErrOrderConflict          = errors.New("order was modified by another request")
 )
 
 // Product validation errors
@@ -21,6 +22,21 @@ var (
 	ErrProductsNotAllowedInPatch = errors.New("products cannot be updated via PATCH, use PUT instead")
 )
 
+// Addon validation errors
+var (
+	ErrInvalidAddonID       = errors.New("addon ID is required")
+	ErrInvalidAddonName     = errors.New("addon name is required")
+	ErrInvalidAddonPrice    = errors.New("addon price must be greater than or equal to 0")
+	ErrInvalidAddonQuantity = errors.New("addon quantity must be greater than 0")
+	ErrDuplicateAddon       = errors.New("duplicate addon in order line")
+)
+
+// Tenant errors
+var (
+	ErrCompanyIDRequired   = errors.New("company ID is required")
+	ErrSalePointIDRequired = errors.New("sale point ID is required")
+)
+
 // Customer validation errors
 var (
 	ErrCustomerRequiredForDelivery    = errors.New("customer information is required for delivery orders")
@@ -42,17 +58,105 @@ var (
 // Sale type and status errors
 var (
 	ErrInvalidSaleType         = errors.New("invalid sale type")
+	ErrInvalidChannel          = errors.New("invalid order channel")
+	ErrInvalidNoteVisibility   = errors.New("invalid note visibility")
 	ErrInvalidStatus           = errors.New("invalid order status")
 	ErrInvalidStatusTransition = errors.New("invalid status transition")
 	ErrOrderCannotBeModified   = errors.New("order cannot be modified in current status")
 	ErrOrderAlreadyCancelled   = errors.New("order is already cancelled")
 	ErrOrderAlreadyDelivered   = errors.New("order is already delivered")
+	ErrFieldNotEditable        = errors.New("field is not editable in the current status")
+)
+
+// Order size guard errors
+var (
+	ErrMaxLinesExceeded        = errors.New("order exceeds the maximum number of lines")
+	ErrMaxLineQuantityExceeded = errors.New("line quantity exceeds the maximum per line")
+	ErrMaxTotalExceeded        = errors.New("order total exceeds the maximum order total")
+	ErrOrderTooLarge           = errors.New("order too large")
+)
+
+// Phone errors
+var (
+	ErrInvalidPhone = errors.New("invalid phone number")
+)
+
+// Free text errors
+var (
+	ErrBlankText   = errors.New("text cannot be blank")
+	ErrTextTooLong = errors.New("text exceeds maximum length")
+)
+
+// Note errors
+var (
+	ErrTooManyNotes = errors.New("order has reached the maximum number of notes")
+)
+
+// Metrics errors
+var (
+	ErrInvalidSortField  = errors.New("invalid sort field")
+	ErrInvalidSortDir    = errors.New("invalid sort direction")
+	ErrInvalidDateFilter = errors.New("invalid date filter")
+)
+
+// Archive errors
+var (
+	ErrArchiveCutoffTooRecent = errors.New("archive cutoff is more recent than the configured minimum age")
+)
+
+// Search errors
+var (
+	ErrSearchQueryRequired = errors.New("search query is required")
+	ErrSearchQueryTooLong  = errors.New("search query exceeds maximum length")
+)
+
+// Tracking errors
+var (
+	ErrTrackingURLNotConfigured = errors.New("tracking URL template is not configured")
+)
+
+// Customer edit errors
+var (
+	ErrCustomerEditDisabled      = errors.New("customer edits are disabled")
+	ErrCustomerFieldNotEditable  = errors.New("field cannot be edited by customers")
+	ErrNoCustomerEditFields      = errors.New("no editable fields provided")
+	ErrCustomerEditNotAllowed    = errors.New("order can no longer be edited by the customer")
+	ErrCustomerEditWindowExpired = errors.New("customer edit window has expired")
+)
+
+// Duplication errors
+var (
+	ErrDuplicateNotConfigured    = errors.New("order duplication is not configured")
+	ErrDuplicateSaleTypeMismatch = errors.New("an order can only be duplicated with its own sale type")
+	ErrDuplicateItemsUnavailable = errors.New("some products of the order are no longer available")
+)
+
+// Batch status errors
+var (
+	ErrBatchEmpty    = errors.New("at least one order code is required")
+	ErrBatchTooLarge = errors.New("too many order codes in one batch")
+)
+
+// Report errors
+var (
+	ErrInvalidReportDate = errors.New("invalid report date")
 )
 
 // Payment errors
 var (
+	ErrInvalidPaymentStatus     = errors.New("invalid payment status")
+	ErrInvalidPaymentTransition = errors.New("invalid payment status transition")
+	ErrPaymentNotConfirmed      = errors.New("delivery orders cannot go out for delivery before the payment is confirmed")
 	ErrInvalidPaymentAccountID  = errors.New("invalid payment account ID")
 	ErrInvalidPaymentReceiptURL = errors.New("invalid payment receipt URL")
+	ErrReceiptHostNotAllowed    = errors.New("payment receipt URL host is not allowed")
+)
+
+// Discount errors
+var (
+	ErrInvalidDiscountType     = errors.New("invalid discount type")
+	ErrInvalidDiscountValue    = errors.New("discount percentage must be between 0 and 100 and amounts cannot be negative")
+	ErrDiscountExceedsSubtotal = errors.New("fixed discount exceeds the order subtotal")
 )
 
 // Total validation errors
@@ -60,3 +164,10 @@ var (
 	ErrTotalMismatch = errors.New("provided total does not match calculated total")
 	ErrInvalidTotal  = errors.New("invalid total amount")
 )
+
+// Tracking stream errors
+var (
+	ErrStatusStreamsDisabled = errors.New("tracking streams are disabled")
+	ErrTooManyStatusStreams  = errors.New("too many open tracking streams for this order")
+	ErrStatusFeedClosed      = errors.New("tracking streams are shutting down")
+)
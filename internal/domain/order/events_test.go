@@ -0,0 +1,59 @@
+package order
+
+import (
+	"context"
+	"testing"
+)
+
+// recordingEvents records the events the service reports
+type recordingEvents struct {
+	created []string
+	changes []string // "FROM->TO"
+}
+
+func (r *recordingEvents) OrderCreated(o *Order) { r.created = append(r.created, o.ID) }
+
+func (r *recordingEvents) OrderStatusChanged(o *Order, previous OrderStatus) {
+	r.changes = append(r.changes, string(previous)+"->"+string(o.Status))
+}
+
+func TestServiceRecordsLifecycleEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      *OrderStatus
+		note        *string
+		wantChanges []string
+	}{
+		{"cancelled", statusPtr(StatusCancelled), nil, []string{"CREATED->CANCELLED"}},
+		{"no status change", nil, text("Mesa junto a la ventana"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &recordingEvents{}
+			svc := NewService(newMemoryRepository(), WithEventRecorder(events))
+
+			o, err := svc.Create(context.Background(), onSiteInput(lines(1, 1, 100)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.PartialUpdate(context.Background(), o.Code, PartialUpdateInput{Status: tt.status, Note: tt.note}); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(events.created) != 1 || events.created[0] != o.ID {
+				t.Errorf("created events = %v, want [%s]", events.created, o.ID)
+			}
+			if len(events.changes) != len(tt.wantChanges) {
+				t.Fatalf("status changes = %v, want %v", events.changes, tt.wantChanges)
+			}
+			for i := range tt.wantChanges {
+				if events.changes[i] != tt.wantChanges[i] {
+					t.Errorf("status changes = %v, want %v", events.changes, tt.wantChanges)
+				}
+			}
+		})
+	}
+}
+
+func statusPtr(s OrderStatus) *OrderStatus { return &s }
@@ -0,0 +1,66 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+func TestCreateExpectedTotal(t *testing.T) {
+	cents := func(v int64) *int64 { return &v }
+	tenPercent := &Discount{Type: DiscountPercent, Value: 10}
+
+	tests := []struct {
+		name     string
+		products []OrderProduct
+		discount *Discount
+		expected *int64
+		wantErr  bool
+	}{
+		{"omitted", lines(2, 3, 1999), nil, nil, false},
+		{"matches", lines(2, 3, 1999), nil, cents(11994), false},
+		{"one cent low", lines(2, 3, 1999), nil, cents(11993), true},
+		{"one cent high", lines(2, 3, 1999), nil, cents(11995), true},
+		{"matches after the discount", lines(1, 1, 10000), tenPercent, cents(9000), false},
+		{"subtotal sent instead of the discounted total", lines(1, 1, 10000), tenPercent, cents(10000), true},
+		{"zero for a free order", lines(1, 1, 0), nil, cents(0), false},
+		{"zero for a paid order", lines(1, 1, 500), nil, cents(0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo)
+			input := onSiteInput(tt.products)
+			input.Discount, input.ExpectedTotal = tt.discount, tt.expected
+
+			o, err := svc.Create(context.Background(), input)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tt.expected != nil && o.Total != *tt.expected {
+					t.Errorf("total = %d, want %d", o.Total, *tt.expected)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrTotalMismatch) {
+				t.Fatalf("err = %v, want %v", err, ErrTotalMismatch)
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.As(err, &domainErr) || domainErr.Field != "total" {
+				t.Errorf("err = %#v, want a domain error on total", err)
+			}
+			if !strings.Contains(err.Error(), "calculated") {
+				t.Errorf("err = %v, want both amounts", err)
+			}
+			if len(repo.orders) != 0 {
+				t.Error("order stored despite the mismatch")
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package order
+
+import (
+	"context"
+	"fmt"
+)
+
+// ExportBatchSize is the number of orders read and written per export batch
+const ExportBatchSize = 200
+
+// ExportInput represents input for streaming matching orders
+type ExportInput struct {
+	Filters OrderFilters
+	After   *BatchCursor // Resume after this order; nil starts from the oldest match
+}
+
+// ExportEmitFunc receives one export batch and the cursor of its last order
+type ExportEmitFunc func(batch []*Order, last BatchCursor) error
+
+// Export walks the orders matching the filters oldest first, one batch at a time, and passes each
+// batch to emit along with the cursor of its last order, so the caller can checkpoint the stream.
+// It stops at the first emit error or as soon as ctx is cancelled, e.g. when the client disconnects.
+func (s *Service) Export(ctx context.Context, input ExportInput, emit ExportEmitFunc) error {
+	after := input.After
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		orders, err := s.repo.FindBatch(ctx, input.Filters, after, ExportBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get orders: %w", err)
+		}
+		if len(orders) == 0 {
+			return nil
+		}
+
+		last := orders[len(orders)-1]
+		after = &BatchCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		if err := emit(orders, *after); err != nil {
+			return err
+		}
+
+		if len(orders) < ExportBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// exportOrders builds n stored orders; the two around the first batch boundary share their creation time
+func exportOrders(n int) []*Order {
+	orders := make([]*Order, n)
+	for i := range orders {
+		orders[i] = storedOrder(i+1, 23333, 0, 23333, nil)
+	}
+	if n > ExportBatchSize {
+		orders[ExportBatchSize].CreatedAt = orders[ExportBatchSize-1].CreatedAt
+	}
+	return orders
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name        string
+		orders      int
+		after       int // Resume after the order at this 1-based position; 0 starts from the oldest
+		wantBatches []int
+	}{
+		{"no orders", 0, 0, nil},
+		{"one partial batch", 3, 0, []int{3}},
+		{"exactly one batch", ExportBatchSize, 0, []int{ExportBatchSize}},
+		{"several batches", 2*ExportBatchSize + 50, 0, []int{ExportBatchSize, ExportBatchSize, 50}},
+		{"resumed mid stream", 2*ExportBatchSize + 50, 150, []int{ExportBatchSize, 100}},
+		{"resumed on a shared timestamp", 2*ExportBatchSize + 50, ExportBatchSize, []int{ExportBatchSize, 50}},
+		{"resumed after the last order", 20, 20, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := exportOrders(tt.orders)
+			svc := NewService(newMemoryRepository(orders...))
+			input := ExportInput{}
+			if tt.after > 0 {
+				o := orders[tt.after-1]
+				input.After = &BatchCursor{CreatedAt: o.CreatedAt, ID: o.ID}
+			}
+
+			var batches []int
+			seen := make(map[string]bool)
+			next := tt.after
+			err := svc.Export(context.Background(), input, func(batch []*Order, last BatchCursor) error {
+				batches = append(batches, len(batch))
+				for _, o := range batch {
+					if seen[o.ID] {
+						t.Errorf("order %s exported twice", o.ID)
+					}
+					seen[o.ID] = true
+					if o.ID != orders[next].ID {
+						t.Errorf("exported %s, want %s", o.ID, orders[next].ID)
+					}
+					next++
+				}
+				if tail := batch[len(batch)-1]; last.ID != tail.ID || !last.CreatedAt.Equal(tail.CreatedAt) {
+					t.Errorf("cursor = %+v, want the last order of the batch %s", last, tail.ID)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(batches) != len(tt.wantBatches) {
+				t.Fatalf("batches = %v, want %v", batches, tt.wantBatches)
+			}
+			for i := range batches {
+				if batches[i] != tt.wantBatches[i] {
+					t.Errorf("batches = %v, want %v", batches, tt.wantBatches)
+				}
+			}
+			if next != tt.orders {
+				t.Errorf("stopped after %d orders, want %d", next, tt.orders)
+			}
+		})
+	}
+}
+
+func TestExportStops(t *testing.T) {
+	errWrite := errors.New("broken pipe")
+
+	tests := []struct {
+		name    string
+		emit    func(cancel context.CancelFunc) error
+		wantErr error
+	}{
+		{"client disconnected", func(cancel context.CancelFunc) error { cancel(); return nil }, context.Canceled},
+		{"write failed", func(cancel context.CancelFunc) error { return errWrite }, errWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(newMemoryRepository(exportOrders(3 * ExportBatchSize)...))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			batches := 0
+			err := svc.Export(ctx, ExportInput{}, func(batch []*Order, last BatchCursor) error {
+				batches++
+				return tt.emit(cancel)
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if batches != 1 {
+				t.Errorf("emitted %d batches after the stop, want 1", batches)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package order
+
+import (
+	"fmt"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// Fields that can be changed through a partial update (PATCH)
+const (
+	FieldNote              = "note"
+	FieldPaymentReceiptURL = "payment_receipt_url"
+	FieldPaymentAccountID  = "payment_account_id"
+)
+
+// IsFieldEditable reports whether a PATCH field may be changed while the order is in the given status.
+// Payment fields are editable until the order leaves for delivery, notes until it is delivered,
+// and nothing is editable on a cancelled order. The payment status follows its own lifecycle
+// (e.g. refunds after cancelling) and is always editable.
+func IsFieldEditable(field string, status OrderStatus) bool {
+	switch field {
+	case FieldPaymentStatus:
+		return true
+	case FieldPaymentReceiptURL, FieldPaymentAccountID:
+		return status == StatusCreated || status == StatusVerified || status == StatusInProgress
+	case FieldNote:
+		return status != StatusDelivered && status != StatusCancelled
+	default:
+		return false
+	}
+}
+
+// checkEditableFields returns an error listing every field that cannot be changed in the given status
+func checkEditableFields(status OrderStatus, fields []string) error {
+	var blocked apperrors.DomainErrors
+	for _, field := range fields {
+		if !IsFieldEditable(field, status) {
+			err := fmt.Errorf("%w: %s cannot be changed while the order is %s", ErrFieldNotEditable, field, status)
+			blocked = append(blocked, apperrors.NewDomainError(err, field, status))
+		}
+	}
+	if len(blocked) > 0 {
+		return blocked
+	}
+	return nil
+}
+
+// changedFields lists the fields a partial update sets
+func (input PartialUpdateInput) changedFields() []string {
+	var fields []string
+	if input.Note != nil || input.NoteVisibility != "" {
+		fields = append(fields, FieldNote)
+	}
+	if input.PaymentReceiptURL != nil {
+		fields = append(fields, FieldPaymentReceiptURL)
+	}
+	if input.PaymentAccountID != nil {
+		fields = append(fields, FieldPaymentAccountID)
+	}
+	if input.PaymentStatus != nil {
+		fields = append(fields, FieldPaymentStatus)
+	}
+	return fields
+}
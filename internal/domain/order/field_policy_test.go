@@ -0,0 +1,97 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+func TestIsFieldEditable(t *testing.T) {
+	statuses := []OrderStatus{StatusCreated, StatusVerified, StatusInProgress, StatusOutForDelivery, StatusDelivered, StatusCancelled}
+
+	// Editable flags in the order of statuses above
+	tests := []struct {
+		field    string
+		editable [6]bool
+	}{
+		{FieldPaymentReceiptURL, [6]bool{true, true, true, false, false, false}},
+		{FieldPaymentAccountID, [6]bool{true, true, true, false, false, false}},
+		{FieldNote, [6]bool{true, true, true, true, false, false}},
+		{FieldPaymentStatus, [6]bool{true, true, true, true, true, true}},
+		{"products", [6]bool{}},
+	}
+
+	for _, tt := range tests {
+		for i, status := range statuses {
+			if got := IsFieldEditable(tt.field, status); got != tt.editable[i] {
+				t.Errorf("IsFieldEditable(%s, %s) = %v, want %v", tt.field, status, got, tt.editable[i])
+			}
+		}
+	}
+}
+
+func TestCheckEditableFieldsListsEveryBlockedField(t *testing.T) {
+	err := checkEditableFields(StatusDelivered, []string{FieldNote, FieldPaymentStatus, FieldPaymentReceiptURL})
+
+	var blocked apperrors.DomainErrors
+	if !errors.As(err, &blocked) {
+		t.Fatalf("err = %v, want DomainErrors", err)
+	}
+	if !errors.Is(err, ErrFieldNotEditable) {
+		t.Errorf("err does not wrap ErrFieldNotEditable: %v", err)
+	}
+	var fields []string
+	for _, e := range blocked {
+		fields = append(fields, e.Field)
+	}
+	if len(fields) != 2 || fields[0] != FieldNote || fields[1] != FieldPaymentReceiptURL {
+		t.Errorf("blocked fields = %v, want [note payment_receipt_url]", fields)
+	}
+
+	if err := checkEditableFields(StatusCreated, []string{FieldNote, FieldPaymentReceiptURL}); err != nil {
+		t.Errorf("CREATED order: err = %v, want nil", err)
+	}
+}
+
+func TestPartialUpdateFieldPolicyOverride(t *testing.T) {
+	receipt := "https://cdn.example.com/late-receipt.png"
+	note := "Refund approved by manager"
+
+	tests := []struct {
+		name     string
+		override bool
+		wantErr  error
+	}{
+		{"cancelled orders are locked", false, ErrFieldNotEditable},
+		{"admin override", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancelled := storedOrder(1, 23333, 0, 23333, nil)
+			cancelled.Status = StatusCancelled
+			repo := newMemoryRepository(cancelled)
+			svc := NewService(repo)
+
+			_, err := svc.PartialUpdate(context.Background(), cancelled.Code, PartialUpdateInput{
+				Note:                &note,
+				PaymentReceiptURL:   &receipt,
+				OverrideFieldPolicy: tt.override,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			stored := repo.stored(cancelled.ID)
+			changed := stored.PaymentReceiptURL != nil && *stored.PaymentReceiptURL == receipt && len(stored.Notes) == 1
+			if changed != (tt.wantErr == nil) {
+				t.Errorf("order changed = %v, want %v", changed, tt.wantErr == nil)
+			}
+			if stored.Status != StatusCancelled {
+				t.Errorf("status = %s, want CANCELLED", stored.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetByID(t *testing.T) {
+	stored := storedOrder(7, 23333, 0, 23333, nil)
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode string
+		wantErr  error
+	}{
+		{"stored order", stored.ID, stored.Code, nil},
+		{"unknown order", "order-8", "", ErrOrderNotFound},
+		{"code is not an ID", stored.Code, "", ErrOrderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := NewService(newMemoryRepository(stored)).GetByID(context.Background(), tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (o.ID != tt.id || o.Code != tt.wantCode) {
+				t.Errorf("order = %s %s, want %s %s", o.ID, o.Code, tt.id, tt.wantCode)
+			}
+		})
+	}
+
+	t.Run("empty ID", func(t *testing.T) {
+		if _, err := NewService(newMemoryRepository(stored)).GetByID(context.Background(), ""); err == nil || errors.Is(err, ErrOrderNotFound) {
+			t.Errorf("err = %v, want a required ID error", err)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package order
+
+import (
+	"context"
+	"testing"
+)
+
+func TestModifyKeepStatus(t *testing.T) {
+	fries := []OrderProduct{{ID: "p1", Name: "Burger", Price: 10000, Quantity: 2}, {ID: "p3", Name: "Fries", Price: 5000, Quantity: 1}}
+	note := "Sin cebolla"
+
+	tests := []struct {
+		name         string
+		keep         bool
+		status       OrderStatus
+		input        ModifyInput
+		wantStatus   OrderStatus
+		wantProducts bool
+	}{
+		{"created verified", false, StatusCreated, ModifyInput{Products: fries}, StatusVerified, true},
+		{"verified stays verified", false, StatusVerified, ModifyInput{Products: fries}, StatusVerified, true},
+		{"in progress rewound", false, StatusInProgress, ModifyInput{Products: fries}, StatusVerified, true},
+		{"kept: created still verified", true, StatusCreated, ModifyInput{Products: fries}, StatusVerified, true},
+		{"kept: verified", true, StatusVerified, ModifyInput{Products: fries}, StatusVerified, true},
+		{"kept: in progress", true, StatusInProgress, ModifyInput{Products: fries}, StatusInProgress, true},
+		{"note only", false, StatusInProgress, ModifyInput{Note: &note}, StatusInProgress, false},
+		{"same products", true, StatusInProgress, ModifyInput{Products: storedOrder(1, 23333, 0, 23333, nil).Products}, StatusInProgress, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			stored.Status = tt.status
+			repo := newMemoryRepository(stored)
+			svc := NewService(repo, WithStatusKeptOnModify(tt.keep))
+
+			result, err := svc.Modify(context.Background(), stored.Code, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			saved := repo.stored(stored.ID)
+			if saved.Status != tt.wantStatus || result.Order.Status != tt.wantStatus {
+				t.Errorf("status = %s, returned %s, want %s", saved.Status, result.Order.Status, tt.wantStatus)
+			}
+			if got := result.Changes.ProductsChanged(); got != tt.wantProducts {
+				t.Errorf("products changed = %v, want %v", got, tt.wantProducts)
+			}
+			if tt.wantProducts && saved.Total != 25000 {
+				t.Errorf("total = %d, want the new products priced in", saved.Total)
+			}
+		})
+	}
+}
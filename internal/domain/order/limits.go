@@ -0,0 +1,38 @@
+package order
+
+import (
+	"fmt"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// OrderLimits guards against accidental oversized orders. A zero value disables a limit.
+type OrderLimits struct {
+	MaxLineQuantity int   // Maximum quantity on a single product line
+	MaxLines        int   // Maximum number of product lines
+	MaxTotal        int64 // Maximum order total, in cents
+}
+
+// Check returns a domain error naming the first limit the order exceeds
+func (l OrderLimits) Check(o *Order) error {
+	if l.MaxLines > 0 && len(o.Products) > l.MaxLines {
+		err := fmt.Errorf("%w: %d lines, max %d", ErrMaxLinesExceeded, len(o.Products), l.MaxLines)
+		return apperrors.NewDomainError(err, "products", len(o.Products))
+	}
+
+	if l.MaxLineQuantity > 0 {
+		for i, p := range o.Products {
+			if p.Quantity > l.MaxLineQuantity {
+				err := fmt.Errorf("%w: %d, max %d", ErrMaxLineQuantityExceeded, p.Quantity, l.MaxLineQuantity)
+				return apperrors.NewIndexedDomainError(err, fmt.Sprintf("products[%d].quantity", i), i, p.Quantity)
+			}
+		}
+	}
+
+	if l.MaxTotal > 0 && o.Total > l.MaxTotal {
+		err := fmt.Errorf("%w: %d, max %d", ErrMaxTotalExceeded, o.Total, l.MaxTotal)
+		return apperrors.NewDomainError(err, "total", o.Total)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// lines returns n product lines of the given quantity and unit price
+func lines(n, quantity int, price int64) []OrderProduct {
+	products := make([]OrderProduct, n)
+	for i := range products {
+		products[i] = OrderProduct{ID: "p" + string(rune('a'+i)), Name: "Item", Price: price, Quantity: quantity}
+	}
+	return products
+}
+
+// onSiteInput is a valid ON_SITE order with the given lines
+func onSiteInput(products []OrderProduct) CreateInput {
+	table := 4
+	return CreateInput{
+		CompanyID:   "11111111-1111-4111-8111-111111111111",
+		SalePointID: "22222222-2222-4222-8222-222222222222",
+		SaleType:    SaleTypeOnSite,
+		TableNumber: &table,
+		Products:    products,
+	}
+}
+
+var testLimits = OrderLimits{MaxLineQuantity: 50, MaxLines: 3, MaxTotal: 100000}
+
+func TestOrderLimitsCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    OrderLimits
+		products  []OrderProduct
+		wantErr   error
+		wantField string
+	}{
+		{"within every limit", testLimits, lines(3, 50, 100), nil, ""},
+		{"at the total limit", testLimits, lines(1, 10, 10000), nil, ""},
+		{"too many lines", testLimits, lines(4, 1, 100), ErrMaxLinesExceeded, "products"},
+		{"line quantity", testLimits, append(lines(1, 1, 100), OrderProduct{ID: "big", Name: "Item", Price: 100, Quantity: 1000}), ErrMaxLineQuantityExceeded, "products[1].quantity"},
+		{"total", testLimits, lines(1, 11, 10000), ErrMaxTotalExceeded, "total"},
+		{"zero limits are disabled", OrderLimits{}, lines(100, 1000, 1000000), nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOrder(SaleTypeOnSite, tt.products)
+			err := tt.limits.Check(o)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.As(err, &domainErr) || domainErr.Field != tt.wantField {
+				t.Errorf("field = %v, want %q", domainErr, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestCreateAndModifyLimitsOverride(t *testing.T) {
+	oversized := lines(1, 1000, 2000)
+
+	tests := []struct {
+		name     string
+		override bool
+		wantErr  error
+	}{
+		{"guards apply", false, ErrMaxLineQuantityExceeded},
+		{"override skips the guards", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run("create: "+tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithOrderLimits(testLimits))
+
+			input := onSiteInput(oversized)
+			input.OverrideLimits = tt.override
+			o, err := svc.Create(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && repo.stored(o.ID) == nil {
+				t.Error("overridden order was not stored")
+			}
+		})
+
+		t.Run("modify: "+tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithOrderLimits(testLimits))
+			o, err := svc.Create(context.Background(), onSiteInput(lines(1, 1, 2000)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = svc.Modify(context.Background(), o.Code, ModifyInput{Products: oversized, OverrideLimits: tt.override})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			want := 1
+			if tt.wantErr == nil {
+				want = 1000
+			}
+			if got := repo.stored(o.ID).Products[0].Quantity; got != want {
+				t.Errorf("stored quantity = %d, want %d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// DefaultMaxNotes is the number of notes an order can hold unless configured otherwise
+const DefaultMaxNotes = 50
+
+// MaxNoteAuthorLength is the maximum number of characters of a note author
+const MaxNoteAuthorLength = 100
+
+// OrderNote is one entry of the append-only notes of an order
+type OrderNote struct {
+	Text       string         `json:"text" bson:"text"`
+	Author     string         `json:"author" bson:"author"`
+	Visibility NoteVisibility `json:"visibility" bson:"visibility"`
+	CreatedAt  time.Time      `json:"created_at" bson:"created_at"`
+}
+
+// NoteList returns the notes of the order, oldest first. Orders stored before notes were a list
+// have a single note: it comes first, dated at creation, with its stored visibility (INTERNAL when empty).
+func (o *Order) NoteList() []OrderNote {
+	if o.Note == nil || *o.Note == "" {
+		return o.Notes
+	}
+	visibility := o.NoteVisibility
+	if visibility == "" {
+		visibility = NoteInternal
+	}
+	legacy := OrderNote{Text: *o.Note, Author: ActorUser, Visibility: visibility, CreatedAt: o.CreatedAt}
+	return append([]OrderNote{legacy}, o.Notes...)
+}
+
+// PublicNotes returns the notes that may be shown on public endpoints
+func (o *Order) PublicNotes() []OrderNote {
+	var public []OrderNote
+	for _, note := range o.NoteList() {
+		if note.Visibility == NotePublic {
+			public = append(public, note)
+		}
+	}
+	return public
+}
+
+// addNote sanitizes the text and appends it as a new note; an empty visibility uses the caller's default.
+// At most maxNotes notes are kept (no limit when <= 0).
+func (o *Order) addNote(text, author string, visibility, fallback NoteVisibility, maxNotes int, now time.Time) error {
+	if visibility == "" {
+		visibility = fallback
+	}
+	if !IsValidNoteVisibility(visibility) {
+		return apperrors.NewDomainError(ErrInvalidNoteVisibility, "note_visibility", visibility)
+	}
+
+	sanitized := util.SanitizeText(text)
+	if sanitized == "" {
+		return apperrors.NewDomainError(ErrBlankText, "note", nil)
+	}
+	if utf8.RuneCountInString(sanitized) > MaxTextLength {
+		return apperrors.NewDomainError(ErrTextTooLong, "note", nil)
+	}
+	author = util.SanitizeText(author)
+	if author == "" {
+		author = ActorUser
+	}
+	if utf8.RuneCountInString(author) > MaxNoteAuthorLength {
+		return apperrors.NewDomainError(ErrTextTooLong, "author", nil)
+	}
+
+	o.migrateLegacyNote()
+	if maxNotes > 0 && len(o.Notes) >= maxNotes {
+		return apperrors.NewDomainError(ErrTooManyNotes, "notes", maxNotes)
+	}
+	o.Notes = append(o.Notes, OrderNote{Text: sanitized, Author: author, Visibility: visibility, CreatedAt: now})
+	return nil
+}
+
+// setLatestNoteVisibility changes the visibility of the most recent note, if any
+func (o *Order) setLatestNoteVisibility(visibility NoteVisibility) error {
+	if !IsValidNoteVisibility(visibility) {
+		return apperrors.NewDomainError(ErrInvalidNoteVisibility, "note_visibility", visibility)
+	}
+	o.migrateLegacyNote()
+	if len(o.Notes) > 0 {
+		o.Notes[len(o.Notes)-1].Visibility = visibility
+	}
+	return nil
+}
+
+// migrateLegacyNote moves the single note of orders stored before notes were a list into Notes
+func (o *Order) migrateLegacyNote() {
+	o.Notes = slices.Clone(o.NoteList())
+	o.Note = nil
+	o.NoteVisibility = ""
+}
+
+// addedNotes returns the notes appended between two states of the same order
+func addedNotes(before, after *Order) []OrderNote {
+	previous, current := before.NoteList(), after.NoteList()
+	if len(current) <= len(previous) {
+		return nil
+	}
+	return current[len(previous):]
+}
+
+// applyNote appends the note of a staff update, or only changes the visibility of the latest note
+// when no note is sent
+func (s *Service) applyNote(o *Order, note *string, visibility NoteVisibility) error {
+	if note != nil {
+		return o.addNote(*note, ActorUser, visibility, NoteInternal, s.maxNotes, time.Now())
+	}
+	if visibility != "" {
+		return o.setLatestNoteVisibility(visibility)
+	}
+	return nil
+}
+
+// AddNoteInput represents a note appended to an order
+type AddNoteInput struct {
+	Text       string
+	Author     string         // Defaults to ActorUser
+	Visibility NoteVisibility // Defaults to INTERNAL
+}
+
+// AddNote appends a note to an order. Notes follow the field policy of the note field,
+// so they can be added until the order is delivered.
+func (s *Service) AddNote(ctx context.Context, code string, input AddNoteInput) (*Order, error) {
+	code = NormalizeCode(code)
+	if code == "" {
+		return nil, ErrInvalidOrderCode
+	}
+
+	order, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEditableFields(order.Status, []string{FieldNote}); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := order.addNote(input.Text, input.Author, input.Visibility, NoteInternal, s.maxNotes, now); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if err := s.checkDocumentSize(order); err != nil {
+		return nil, err
+	}
+	order.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+	return order, nil
+}
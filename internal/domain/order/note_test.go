@@ -0,0 +1,262 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNoteList(t *testing.T) {
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	legacy := "leave at the front desk"
+	empty := ""
+	listed := []OrderNote{{Text: "ring twice", Author: "caja", Visibility: NotePublic, CreatedAt: created.Add(time.Hour)}}
+
+	tests := []struct {
+		name       string
+		note       *string
+		visibility NoteVisibility
+		notes      []OrderNote
+		want       []OrderNote
+	}{
+		{"no notes", nil, "", nil, nil},
+		{"list only", nil, "", listed, listed},
+		{"empty legacy note ignored", &empty, NotePublic, listed, listed},
+		{"legacy note without visibility is internal", &legacy, "", nil,
+			[]OrderNote{{Text: legacy, Author: ActorUser, Visibility: NoteInternal, CreatedAt: created}}},
+		{"legacy note keeps its visibility", &legacy, NotePublic, nil,
+			[]OrderNote{{Text: legacy, Author: ActorUser, Visibility: NotePublic, CreatedAt: created}}},
+		{"legacy note comes first", &legacy, "", listed,
+			[]OrderNote{{Text: legacy, Author: ActorUser, Visibility: NoteInternal, CreatedAt: created}, listed[0]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Order{Note: tt.note, NoteVisibility: tt.visibility, Notes: tt.notes, CreatedAt: created}
+			got := o.NoteList()
+			if len(got) != len(tt.want) {
+				t.Fatalf("notes = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("note %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPublicNotes(t *testing.T) {
+	secret := "customer was rude last time"
+	o := &Order{
+		Note: &secret,
+		Notes: []OrderNote{
+			{Text: "leave at the front desk", Visibility: NotePublic},
+			{Text: "pays in cash", Visibility: NoteInternal},
+			{Text: "gate code 1234", Visibility: NotePublic},
+		},
+	}
+
+	got := o.PublicNotes()
+	if len(got) != 2 || got[0].Text != "leave at the front desk" || got[1].Text != "gate code 1234" {
+		t.Errorf("public notes = %+v", got)
+	}
+	for _, note := range got {
+		if note.Visibility != NotePublic {
+			t.Errorf("non public note returned: %+v", note)
+		}
+	}
+}
+
+// TestNoteVisibilityDefaults checks who gets which visibility when none is sent
+func TestNoteVisibilityDefaults(t *testing.T) {
+	ctx := context.Background()
+	text := "leave at the front desk"
+
+	tests := []struct {
+		name       string
+		visibility NoteVisibility
+		write      func(svc *Service, o *Order, visibility NoteVisibility) (*Order, error)
+		want       NoteVisibility
+	}{
+		{"create defaults to public", "", createWithNote, NotePublic},
+		{"create can keep it internal", NoteInternal, createWithNote, NoteInternal},
+		{"staff note defaults to internal", "", func(svc *Service, o *Order, v NoteVisibility) (*Order, error) {
+			return svc.AddNote(ctx, o.Code, AddNoteInput{Text: text, Visibility: v})
+		}, NoteInternal},
+		{"staff note can be public", NotePublic, func(svc *Service, o *Order, v NoteVisibility) (*Order, error) {
+			return svc.AddNote(ctx, o.Code, AddNoteInput{Text: text, Visibility: v})
+		}, NotePublic},
+		{"patch note defaults to internal", "", func(svc *Service, o *Order, v NoteVisibility) (*Order, error) {
+			return svc.PartialUpdate(ctx, o.Code, PartialUpdateInput{Note: &text, NoteVisibility: v})
+		}, NoteInternal},
+		{"modify note defaults to internal", "", func(svc *Service, o *Order, v NoteVisibility) (*Order, error) {
+			r, err := svc.Modify(ctx, o.Code, ModifyInput{Note: &text, NoteVisibility: v})
+			if err != nil {
+				return nil, err
+			}
+			return r.Order, nil
+		}, NoteInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			svc := NewService(newMemoryRepository(stored))
+			o, err := tt.write(svc, stored, tt.visibility)
+			if err != nil {
+				t.Fatal(err)
+			}
+			notes := o.NoteList()
+			if len(notes) == 0 || notes[len(notes)-1].Visibility != tt.want {
+				t.Errorf("notes = %+v, want the last one %s", notes, tt.want)
+			}
+		})
+	}
+}
+
+// createWithNote creates a new order whose note has the given visibility
+func createWithNote(svc *Service, _ *Order, visibility NoteVisibility) (*Order, error) {
+	text := "leave at the front desk"
+	input := onSiteInput(lines(1, 1, 100))
+	input.Note, input.NoteVisibility = &text, visibility
+	return svc.Create(context.Background(), input)
+}
+
+func TestNoteVisibilityUpdates(t *testing.T) {
+	ctx := context.Background()
+	legacy := "customer was rude last time"
+
+	t.Run("visibility alone changes the latest note", func(t *testing.T) {
+		stored := storedOrder(1, 23333, 0, 23333, nil)
+		stored.Notes = []OrderNote{{Text: "first", Visibility: NoteInternal}, {Text: "second", Visibility: NoteInternal}}
+		svc := NewService(newMemoryRepository(stored))
+
+		o, err := svc.PartialUpdate(ctx, stored.Code, PartialUpdateInput{NoteVisibility: NotePublic})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(o.Notes) != 2 || o.Notes[0].Visibility != NoteInternal || o.Notes[1].Visibility != NotePublic {
+			t.Errorf("notes = %+v", o.Notes)
+		}
+	})
+
+	t.Run("legacy note migrated on the first write", func(t *testing.T) {
+		stored := storedOrder(1, 23333, 0, 23333, nil)
+		stored.Note = &legacy
+		repo := newMemoryRepository(stored)
+		svc := NewService(repo)
+
+		if _, err := svc.AddNote(ctx, stored.Code, AddNoteInput{Text: "ring twice"}); err != nil {
+			t.Fatal(err)
+		}
+		saved := repo.stored(stored.ID)
+		if saved.Note != nil || saved.NoteVisibility != "" {
+			t.Errorf("legacy note kept: %v %q", saved.Note, saved.NoteVisibility)
+		}
+		if len(saved.Notes) != 2 || saved.Notes[0].Text != legacy || saved.Notes[0].Visibility != NoteInternal {
+			t.Errorf("notes = %+v, want the legacy note first and internal", saved.Notes)
+		}
+	})
+
+	t.Run("unknown visibility rejected", func(t *testing.T) {
+		stored := storedOrder(1, 23333, 0, 23333, nil)
+		svc := NewService(newMemoryRepository(stored))
+		_, err := svc.AddNote(ctx, stored.Code, AddNoteInput{Text: "ring twice", Visibility: "SECRET"})
+		if !errors.Is(err, ErrInvalidNoteVisibility) {
+			t.Errorf("err = %v, want %v", err, ErrInvalidNoteVisibility)
+		}
+	})
+}
+
+func TestAddNote(t *testing.T) {
+	ctx := context.Background()
+	legacy := "customer called"
+	existing := []OrderNote{{Text: "no onions confirmed", Author: "kitchen", Visibility: NoteInternal, CreatedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}}
+
+	tests := []struct {
+		name       string
+		prepare    func(o *Order)
+		maxNotes   int
+		input      AddNoteInput
+		wantErr    error
+		wantTexts  []string
+		wantAuthor string
+	}{
+		{"first note", nil, 0, AddNoteInput{Text: "ring twice", Author: "cashier"}, nil, []string{"ring twice"}, "cashier"},
+		{"author defaults to the user", nil, 0, AddNoteInput{Text: "ring twice"}, nil, []string{"ring twice"}, ActorUser},
+		{"appended after the others", func(o *Order) { o.Notes = slices.Clone(existing) }, 0, AddNoteInput{Text: "ring twice"}, nil, []string{"no onions confirmed", "ring twice"}, ActorUser},
+		{"legacy note moved first", func(o *Order) { o.Note = &legacy }, 0, AddNoteInput{Text: "ring twice"}, nil, []string{"customer called", "ring twice"}, ActorUser},
+		{"text sanitized", nil, 0, AddNoteInput{Text: "  ring\x00 twice \r\n"}, nil, []string{"ring twice"}, ActorUser},
+		{"longest text", nil, 0, AddNoteInput{Text: strings.Repeat("ñ", MaxTextLength)}, nil, []string{strings.Repeat("ñ", MaxTextLength)}, ActorUser},
+		{"text too long", nil, 0, AddNoteInput{Text: strings.Repeat("ñ", MaxTextLength+1)}, ErrTextTooLong, nil, ""},
+		{"blank text", nil, 0, AddNoteInput{Text: " \n "}, ErrBlankText, nil, ""},
+		{"author too long", nil, 0, AddNoteInput{Text: "ring twice", Author: strings.Repeat("a", MaxNoteAuthorLength+1)}, ErrTextTooLong, nil, ""},
+		{"unknown visibility", nil, 0, AddNoteInput{Text: "ring twice", Visibility: "SECRET"}, ErrInvalidNoteVisibility, nil, ""},
+		{"last note under the limit", func(o *Order) { o.Notes = slices.Clone(existing) }, 2, AddNoteInput{Text: "ring twice"}, nil, []string{"no onions confirmed", "ring twice"}, ActorUser},
+		{"limit reached", func(o *Order) { o.Notes = slices.Clone(existing) }, 1, AddNoteInput{Text: "ring twice"}, ErrTooManyNotes, nil, ""},
+		{"legacy note counts toward the limit", func(o *Order) { o.Note = &legacy }, 1, AddNoteInput{Text: "ring twice"}, ErrTooManyNotes, nil, ""},
+		{"delivered order", func(o *Order) { o.Status = StatusDelivered }, 0, AddNoteInput{Text: "ring twice"}, ErrFieldNotEditable, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			if tt.prepare != nil {
+				tt.prepare(stored)
+			}
+			repo := newMemoryRepository(stored)
+			opts := []Option{}
+			if tt.maxNotes > 0 {
+				opts = append(opts, WithMaxNotes(tt.maxNotes))
+			}
+			before := time.Now()
+
+			_, err := NewService(repo, opts...).AddNote(ctx, stored.Code, tt.input)
+			got := repo.stored(stored.ID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if got.Version != 1 {
+					t.Error("order saved despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			notes := got.NoteList()
+			texts := make([]string, len(notes))
+			for i, n := range notes {
+				texts[i] = n.Text
+			}
+			if !slices.Equal(texts, tt.wantTexts) {
+				t.Fatalf("notes = %q, want %q", texts, tt.wantTexts)
+			}
+			if got.Note != nil {
+				t.Error("legacy note kept next to the list")
+			}
+			last := notes[len(notes)-1]
+			if last.Author != tt.wantAuthor || last.Visibility != NoteInternal || last.CreatedAt.Before(before) {
+				t.Errorf("new note = %+v, want by %s, internal, dated now", last, tt.wantAuthor)
+			}
+			if len(notes) > 1 && notes[0].CreatedAt.After(last.CreatedAt) {
+				t.Error("notes out of order")
+			}
+		})
+	}
+}
+
+func TestAddNoteUnknownOrder(t *testing.T) {
+	svc := NewService(newMemoryRepository())
+	for code, want := range map[string]error{"ORD-999999": ErrOrderNotFound, "": ErrInvalidOrderCode} {
+		if _, err := svc.AddNote(context.Background(), code, AddNoteInput{Text: "ring twice"}); !errors.Is(err, want) {
+			t.Errorf("AddNote(%q) err = %v, want %v", code, err, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// TestGetAllPageLimits checks the service clamps for callers that skip the handler's validation
+func TestGetAllPageLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     *util.PageLimits
+		limit      int
+		offset     int
+		wantLimit  int
+		wantOffset int
+	}{
+		{"default limits", nil, 0, 0, util.DefaultPageLimits.DefaultLimit, 0},
+		{"configured default", &util.PageLimits{DefaultLimit: 25, MaxLimit: 500}, 0, 10, 25, 10},
+		{"configured maximum allows large pages", &util.PageLimits{DefaultLimit: 25, MaxLimit: 500}, 500, 0, 500, 0},
+		{"clamped to the maximum", &util.PageLimits{DefaultLimit: 25, MaxLimit: 500}, 5000, 0, 500, 0},
+		{"negative offset", nil, 10, -3, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			var opts []Option
+			if tt.limits != nil {
+				opts = append(opts, WithPageLimits(*tt.limits))
+			}
+
+			if _, _, err := NewService(repo, opts...).GetAll(context.Background(), OrderFilters{Limit: tt.limit, Offset: tt.offset}); err != nil {
+				t.Fatal(err)
+			}
+			got := repo.listed[0]
+			if got.Limit != tt.wantLimit || got.Offset != tt.wantOffset {
+				t.Errorf("limit, offset = %d, %d, want %d, %d", got.Limit, got.Offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+// TestGetAllTotal checks the page and the total come from the single FindAllWithCount call;
+// the memory repository has no Count or FindAll, so a second query would panic
+func TestGetAllTotal(t *testing.T) {
+	tests := []struct {
+		name      string
+		skipCount bool
+		wantTotal int64
+	}{
+		{"counted", false, 3},
+		{"count skipped", true, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(storedOrder(1, 23333, 0, 23333, nil), storedOrder(2, 23333, 0, 23333, nil), storedOrder(3, 23333, 0, 23333, nil))
+
+			orders, total, err := NewService(repo).GetAll(context.Background(), OrderFilters{SkipCount: tt.skipCount})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(repo.listed) != 1 {
+				t.Fatalf("listing calls = %d, want 1", len(repo.listed))
+			}
+			if total != tt.wantTotal || len(orders) != 3 {
+				t.Errorf("total = %d with %d orders, want %d with 3", total, len(orders), tt.wantTotal)
+			}
+		})
+	}
+}
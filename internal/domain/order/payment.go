@@ -0,0 +1,100 @@
+package order
+
+import (
+	"fmt"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// FieldPaymentStatus is the PATCH field holding the payment status
+const FieldPaymentStatus = "payment_status"
+
+// PaymentStatus tracks the payment of an order, independently of its fulfilment status
+type PaymentStatus string
+
+const (
+	PaymentPending         PaymentStatus = "PENDING"
+	PaymentReceiptUploaded PaymentStatus = "RECEIPT_UPLOADED"
+	PaymentConfirmed       PaymentStatus = "CONFIRMED"
+	PaymentRejected        PaymentStatus = "REJECTED"
+	PaymentRefunded        PaymentStatus = "REFUNDED"
+)
+
+// AllPaymentStatuses lists every payment status in lifecycle order
+var AllPaymentStatuses = []PaymentStatus{
+	PaymentPending,
+	PaymentReceiptUploaded,
+	PaymentConfirmed,
+	PaymentRejected,
+	PaymentRefunded,
+}
+
+// PaymentTransitions maps each payment status to the statuses it can move to.
+// Cash payments can be confirmed without a receipt; a rejected receipt can be replaced.
+var PaymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentPending:         {PaymentReceiptUploaded, PaymentConfirmed, PaymentRejected},
+	PaymentReceiptUploaded: {PaymentConfirmed, PaymentRejected},
+	PaymentRejected:        {PaymentReceiptUploaded, PaymentConfirmed},
+	PaymentConfirmed:       {PaymentRefunded},
+	PaymentRefunded:        {},
+}
+
+// IsValidPaymentStatus checks if the payment status is valid
+func IsValidPaymentStatus(status PaymentStatus) bool {
+	_, ok := PaymentTransitions[status]
+	return ok
+}
+
+// CurrentPaymentStatus returns the payment status of the order. Orders stored before payment
+// statuses existed have none: they are RECEIPT_UPLOADED when a receipt is attached, PENDING otherwise.
+func (o *Order) CurrentPaymentStatus() PaymentStatus {
+	if o.PaymentStatus != "" {
+		return o.PaymentStatus
+	}
+	if o.PaymentReceiptURL != nil && *o.PaymentReceiptURL != "" {
+		return PaymentReceiptUploaded
+	}
+	return PaymentPending
+}
+
+// UpdatePaymentStatus moves the payment to a new status following PaymentTransitions
+func (o *Order) UpdatePaymentStatus(status PaymentStatus) error {
+	if !IsValidPaymentStatus(status) {
+		return apperrors.NewDomainError(ErrInvalidPaymentStatus, FieldPaymentStatus, status)
+	}
+	current := o.CurrentPaymentStatus()
+	if current == status {
+		return nil
+	}
+	for _, next := range PaymentTransitions[current] {
+		if next == status {
+			o.PaymentStatus = status
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidPaymentTransition, current, status)
+}
+
+// receiptAttached records that a payment receipt was attached; payments that were not
+// yet confirmed (or were rejected) wait for the receipt to be reviewed
+func (o *Order) receiptAttached() {
+	switch o.CurrentPaymentStatus() {
+	case PaymentPending, PaymentRejected:
+		o.PaymentStatus = PaymentReceiptUploaded
+	}
+}
+
+// receiptRemoved records that the payment receipt was removed before it was reviewed
+func (o *Order) receiptRemoved() {
+	if o.CurrentPaymentStatus() == PaymentReceiptUploaded {
+		o.PaymentStatus = PaymentPending
+	}
+}
+
+// checkDispatchPayment rejects sending a DELIVERY order out before its payment is confirmed
+func checkDispatchPayment(o *Order, to OrderStatus) error {
+	if o.SaleType == SaleTypeDelivery && to == StatusOutForDelivery && o.CurrentPaymentStatus() != PaymentConfirmed {
+		return fmt.Errorf("%w: payment is %s", ErrPaymentNotConfirmed, o.CurrentPaymentStatus())
+	}
+	return nil
+}
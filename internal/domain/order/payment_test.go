@@ -0,0 +1,170 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestUpdatePaymentStatus(t *testing.T) {
+	// Every pair of statuses is either allowed by PaymentTransitions, a no-op, or rejected
+	for _, from := range AllPaymentStatuses {
+		for _, to := range AllPaymentStatuses {
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				o := &Order{PaymentStatus: from}
+				err := o.UpdatePaymentStatus(to)
+
+				switch {
+				case from == to, slices.Contains(PaymentTransitions[from], to):
+					if err != nil {
+						t.Fatalf("err = %v, want the move allowed", err)
+					}
+					if o.PaymentStatus != to {
+						t.Errorf("payment status = %s, want %s", o.PaymentStatus, to)
+					}
+				default:
+					if !errors.Is(err, ErrInvalidPaymentTransition) {
+						t.Fatalf("err = %v, want %v", err, ErrInvalidPaymentTransition)
+					}
+					if o.PaymentStatus != from {
+						t.Errorf("payment status = %s after a rejected move, want %s", o.PaymentStatus, from)
+					}
+				}
+			})
+		}
+	}
+
+	t.Run("unknown status", func(t *testing.T) {
+		o := &Order{PaymentStatus: PaymentPending}
+		if err := o.UpdatePaymentStatus("PAID"); !errors.Is(err, ErrInvalidPaymentStatus) {
+			t.Errorf("err = %v, want %v", err, ErrInvalidPaymentStatus)
+		}
+	})
+}
+
+func TestCurrentPaymentStatus(t *testing.T) {
+	receipt, empty := "https://cdn.example.com/receipt.jpg", ""
+
+	tests := []struct {
+		name  string
+		order Order
+		want  PaymentStatus
+	}{
+		{"stored status", Order{PaymentStatus: PaymentConfirmed, PaymentReceiptURL: &receipt}, PaymentConfirmed},
+		{"legacy order with a receipt", Order{PaymentReceiptURL: &receipt}, PaymentReceiptUploaded},
+		{"legacy order with an empty receipt", Order{PaymentReceiptURL: &empty}, PaymentPending},
+		{"legacy order without a receipt", Order{}, PaymentPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.order.CurrentPaymentStatus(); got != tt.want {
+				t.Errorf("CurrentPaymentStatus = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartialUpdatePayment(t *testing.T) {
+	ctx := context.Background()
+	receipt, removed := "https://cdn.example.com/receipt.jpg", ""
+	status := func(s OrderStatus) *OrderStatus { return &s }
+	payment := func(p PaymentStatus) *PaymentStatus { return &p }
+
+	tests := []struct {
+		name        string
+		saleType    SaleType
+		payment     PaymentStatus
+		paidOnly    bool // WithPaymentRequiredForDispatch
+		input       PartialUpdateInput
+		wantPayment PaymentStatus
+		wantStatus  OrderStatus
+		wantErr     error
+	}{
+		{"receipt uploaded", SaleTypeDelivery, PaymentPending, true, PartialUpdateInput{PaymentReceiptURL: &receipt}, PaymentReceiptUploaded, StatusInProgress, nil},
+		{"receipt replaces a rejected one", SaleTypeDelivery, PaymentRejected, true, PartialUpdateInput{PaymentReceiptURL: &receipt}, PaymentReceiptUploaded, StatusInProgress, nil},
+		{"receipt keeps a confirmed payment", SaleTypeDelivery, PaymentConfirmed, true, PartialUpdateInput{PaymentReceiptURL: &receipt}, PaymentConfirmed, StatusInProgress, nil},
+		{"receipt removed before review", SaleTypeDelivery, PaymentReceiptUploaded, true, PartialUpdateInput{PaymentReceiptURL: &removed}, PaymentPending, StatusInProgress, nil},
+		{"unpaid delivery cannot go out", SaleTypeDelivery, PaymentReceiptUploaded, true, PartialUpdateInput{Status: status(StatusOutForDelivery)}, PaymentReceiptUploaded, StatusInProgress, ErrPaymentNotConfirmed},
+		{"paid delivery goes out", SaleTypeDelivery, PaymentConfirmed, true, PartialUpdateInput{Status: status(StatusOutForDelivery)}, PaymentConfirmed, StatusOutForDelivery, nil},
+		{"confirm and dispatch at once", SaleTypeDelivery, PaymentReceiptUploaded, true, PartialUpdateInput{PaymentStatus: payment(PaymentConfirmed), Status: status(StatusOutForDelivery)}, PaymentConfirmed, StatusOutForDelivery, nil},
+		{"unpaid delivery goes out when not required", SaleTypeDelivery, PaymentPending, false, PartialUpdateInput{Status: status(StatusOutForDelivery)}, PaymentPending, StatusOutForDelivery, nil},
+		{"unpaid delivery handed over at the counter", SaleTypeDelivery, PaymentPending, true, PartialUpdateInput{Status: status(StatusDelivered)}, PaymentPending, StatusDelivered, nil},
+		{"unpaid on-site order served", SaleTypeOnSite, PaymentPending, true, PartialUpdateInput{Status: status(StatusDelivered)}, PaymentPending, StatusDelivered, nil},
+		{"refund a confirmed payment", SaleTypeDelivery, PaymentConfirmed, true, PartialUpdateInput{PaymentStatus: payment(PaymentRefunded)}, PaymentRefunded, StatusInProgress, nil},
+		{"refund a pending payment", SaleTypeDelivery, PaymentPending, true, PartialUpdateInput{PaymentStatus: payment(PaymentRefunded)}, PaymentPending, StatusInProgress, ErrInvalidPaymentTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			stored.SaleType, stored.Status, stored.PaymentStatus = tt.saleType, StatusInProgress, tt.payment
+			if tt.saleType == SaleTypeDelivery {
+				address := "Calle 1 # 2-3"
+				stored.TableNumber, stored.ShippingAddress = nil, &address
+				stored.Customer = &Customer{Name: "Ana", Phone: "+573001234567"}
+			}
+			repo := newMemoryRepository(stored)
+			svc := NewService(repo, WithPaymentRequiredForDispatch(tt.paidOnly))
+
+			_, err := svc.PartialUpdate(ctx, stored.Code, tt.input)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			got := repo.stored(stored.ID)
+			if got.PaymentStatus != tt.wantPayment || got.Status != tt.wantStatus {
+				t.Errorf("payment/status = %s/%s, want %s/%s", got.PaymentStatus, got.Status, tt.wantPayment, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestConfirmPayment(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		payment PaymentStatus
+		code    string
+		wantErr error
+	}{
+		{"pending cash payment", PaymentPending, "", nil},
+		{"uploaded receipt", PaymentReceiptUploaded, "", nil},
+		{"rejected receipt paid in cash", PaymentRejected, "", nil},
+		{"already confirmed", PaymentConfirmed, "", nil},
+		{"code in lower case", PaymentPending, "ord-000001", nil},
+		{"refunded", PaymentRefunded, "", ErrInvalidPaymentTransition},
+		{"unknown order", PaymentPending, "ORD-999999", ErrOrderNotFound},
+		{"blank code", PaymentPending, " ", ErrInvalidOrderCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			stored.PaymentStatus = tt.payment
+			repo := newMemoryRepository(stored)
+			code := stored.Code
+			if tt.code != "" {
+				code = tt.code
+			}
+
+			o, err := NewService(repo).ConfirmPayment(ctx, code)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if got := repo.stored(stored.ID).PaymentStatus; got != tt.payment {
+					t.Errorf("payment status = %s, want it unchanged", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.PaymentStatus != PaymentConfirmed || repo.stored(stored.ID).PaymentStatus != PaymentConfirmed {
+				t.Errorf("payment status = %s, want %s", o.PaymentStatus, PaymentConfirmed)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package order
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultPhoneCountryCode is applied to phones given without a country code
+const DefaultPhoneCountryCode = "57"
+
+// E.164 allows at most 15 digits; shorter than 8 cannot be a reachable number
+const (
+	minPhoneDigits = 8
+	maxPhoneDigits = 15
+)
+
+// minPhoneSearchDigits is the shortest search query read as a phone, so table numbers,
+// prices and street numbers keep matching as text
+const minPhoneSearchDigits = 7
+
+// NormalizePhone converts a phone as typed by a customer into E.164 ("+573001234567").
+// Separators (spaces, dashes, dots, parentheses) are stripped, "00" is read as an
+// international prefix and numbers without a country code get defaultCountryCode.
+func NormalizePhone(raw, defaultCountryCode string) (string, error) {
+	var digits strings.Builder
+	international := false
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+			international = true
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+			// Separator
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	number := digits.String()
+	if !international && strings.HasPrefix(number, "00") {
+		number, international = number[2:], true
+	}
+	if !international && !(len(number) > 10 && strings.HasPrefix(number, defaultCountryCode)) {
+		// Drop a national trunk prefix ("0...") before adding the country code.
+		// Longer numbers that already start with it (e.g. "573001234567") are kept as they are.
+		number = defaultCountryCode + strings.TrimLeft(number, "0")
+	}
+
+	if len(number) < minPhoneDigits || len(number) > maxPhoneDigits || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+
+	// Colombian numbers: 10 digit mobiles (3xx) and landlines (60x)
+	if national, ok := strings.CutPrefix(number, "57"); ok {
+		if len(national) != 10 || !(national[0] == '3' || strings.HasPrefix(national, "60")) {
+			return "", ErrInvalidPhone
+		}
+	}
+
+	return "+" + number, nil
+}
+
+// PhoneSearchDigits reports whether a search query looks like a phone typed in any format
+// ("300 123 4567", "+57 300-123-4567") and returns its digits, to be matched inside normalized phones
+func PhoneSearchDigits(query string) (string, bool) {
+	var digits strings.Builder
+	for i, r := range strings.TrimSpace(query) {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+
+	number := strings.TrimPrefix(digits.String(), "00")
+	if len(number) < minPhoneSearchDigits || len(number) > maxPhoneDigits {
+		return "", false
+	}
+	return number, true
+}
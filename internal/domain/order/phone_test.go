@@ -0,0 +1,122 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		country string
+		want    string
+		wantErr bool
+	}{
+		{"colombian mobile with spaces", "300 123 4567", "57", "+573001234567", false},
+		{"colombian mobile with dashes", "+57-300-1234567", "57", "+573001234567", false},
+		{"colombian mobile digits only", "3001234567", "57", "+573001234567", false},
+		{"colombian mobile with country code, no plus", "573001234567", "57", "+573001234567", false},
+		{"colombian mobile with parentheses and dots", "(300) 123.4567", "57", "+573001234567", false},
+		{"colombian landline", "601 234 5678", "57", "+576012345678", false},
+		{"00 international prefix", "0057 300 123 4567", "57", "+573001234567", false},
+		{"us number", "+1 (415) 555-2671", "57", "+14155552671", false},
+		{"spanish number", "+34 612 34 56 78", "57", "+34612345678", false},
+		{"uk number with 00", "0044 20 7946 0958", "57", "+442079460958", false},
+		{"other default country", "415 555 2671", "1", "+14155552671", false},
+		{"trunk prefix dropped", "0415 555 2671", "1", "+14155552671", false},
+		{"colombian number too short", "300 123 456", "57", "", true},
+		{"colombian number too long", "+57 300 123 45678", "57", "", true},
+		{"colombian number with an impossible prefix", "200 123 4567", "57", "", true},
+		{"too long for E.164", "+1234 5678 9012 3456", "57", "", true},
+		{"too short", "+1 234", "57", "", true},
+		{"letters", "300-CALL-NOW", "57", "", true},
+		{"plus in the middle", "57+3001234567", "57", "", true},
+		{"only separators", " - ( ) ", "57", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhone(tt.raw, tt.country)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizePhone(%q) err = %v, want error %v", tt.raw, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPhone) {
+				t.Errorf("err = %v, want %v", err, ErrInvalidPhone)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizePhone(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateNormalizesCustomerPhone(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        []Option
+		phone          string
+		wantNormalized string
+		wantErr        error
+	}{
+		{"formatted colombian mobile", nil, "300 123 4567", "+573001234567", nil},
+		{"configured country code", []Option{WithDefaultPhoneCountryCode("1")}, "415-555-2671", "+14155552671", nil},
+		{"impossible number", nil, "123", "", ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			input := onSiteInput(lines(1, 1, 100))
+			input.Customer = &Customer{Identification: "1020304050", IDType: "CC", Name: "Ana", Phone: tt.phone}
+
+			o, err := NewService(repo, tt.options...).Create(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var domainErr *apperrors.DomainError
+				if !errors.As(err, &domainErr) || domainErr.Field != "customer.phone" || domainErr.Value != tt.phone {
+					t.Errorf("error = %+v, want field customer.phone with the raw value", domainErr)
+				}
+				return
+			}
+			stored := repo.stored(o.ID).Customer
+			if stored.Phone != tt.phone || stored.PhoneNormalized != tt.wantNormalized {
+				t.Errorf("phone = %q, %q, want %q, %q", stored.Phone, stored.PhoneNormalized, tt.phone, tt.wantNormalized)
+			}
+		})
+	}
+}
+
+func TestPhoneSearchDigits(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		want   string
+		wantOK bool
+	}{
+		{"spaces", "300 123 4567", "3001234567", true},
+		{"international with dashes", "+57 300-123-4567", "573001234567", true},
+		{"00 prefix", "0057 300 123 4567", "573001234567", true},
+		{"parentheses and dots", "(300) 123.4567", "3001234567", true},
+		{"shortest phone", "123 4567", "1234567", true},
+		{"table number", "12", "", false},
+		{"street number", "calle 45 # 12-30", "", false},
+		{"too long for E.164", "1234 5678 9012 3456", "", false},
+		{"plus in the middle", "300+1234567", "", false},
+		{"letters", "300 CALL NOW", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PhoneSearchDigits(tt.query)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("PhoneSearchDigits(%q) = %q, %v, want %q, %v", tt.query, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
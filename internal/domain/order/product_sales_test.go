@@ -0,0 +1,68 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// salesRepository records the product sales queries it receives
+type salesRepository struct {
+	Repository
+	filters OrderFilters
+	sortBy  ProductSalesSort
+	calls   int
+}
+
+func (r *salesRepository) GetProductSales(ctx context.Context, filters OrderFilters, sortBy ProductSalesSort) ([]ProductSales, int64, error) {
+	r.filters, r.sortBy = filters, sortBy
+	r.calls++
+	return []ProductSales{{ProductID: "p1", TotalQuantity: 3, TotalRevenue: 3000}}, 1, nil
+}
+
+func TestGetProductSales(t *testing.T) {
+	limits := util.PageLimits{DefaultLimit: 20, MaxLimit: 100}
+
+	tests := []struct {
+		name       string
+		sortBy     ProductSalesSort
+		filters    OrderFilters
+		wantSort   ProductSalesSort
+		wantLimit  int
+		wantOffset int
+		wantErr    error
+	}{
+		{"revenue by default", "", OrderFilters{}, SortByRevenue, 20, 0, nil},
+		{"by quantity", SortByQuantity, OrderFilters{Limit: 50, Offset: 100}, SortByQuantity, 50, 100, nil},
+		{"by orders", SortByOrders, OrderFilters{Offset: -5}, SortByOrders, 20, 0, nil},
+		{"limit capped", SortByRevenue, OrderFilters{Limit: 1000}, SortByRevenue, 100, 0, nil},
+		{"unknown sort", "price", OrderFilters{}, "", 0, 0, ErrInvalidSortField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &salesRepository{}
+			svc := NewService(repo, WithPageLimits(limits))
+
+			sales, total, err := svc.GetProductSales(context.Background(), tt.filters, tt.sortBy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if repo.calls != 0 {
+					t.Error("invalid sort reached the repository")
+				}
+				return
+			}
+			if repo.sortBy != tt.wantSort || repo.filters.Limit != tt.wantLimit || repo.filters.Offset != tt.wantOffset {
+				t.Errorf("query = %s limit %d offset %d, want %s limit %d offset %d",
+					repo.sortBy, repo.filters.Limit, repo.filters.Offset, tt.wantSort, tt.wantLimit, tt.wantOffset)
+			}
+			if len(sales) != 1 || total != 1 {
+				t.Errorf("got %d rows, total %d", len(sales), total)
+			}
+		})
+	}
+}
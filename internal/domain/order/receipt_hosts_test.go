@@ -0,0 +1,56 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestPartialUpdateReceiptHostAllowlist(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		receipt   string
+		wantErr   error
+	}{
+		{"allowed host", []string{"receipts.example.com"}, "https://receipts.example.com/r.jpg", nil},
+		{"allowed uppercase host", []string{"receipts.example.com"}, "https://RECEIPTS.example.com/r.jpg", nil},
+		{"empty allowlist allows all", nil, "https://anywhere.example/r.jpg", nil},
+		{"removal skips the check", []string{"receipts.example.com"}, "", nil},
+		{"foreign host", []string{"receipts.example.com"}, "https://malware.example/r.jpg", ErrReceiptHostNotAllowed},
+		{"userinfo trick", []string{"receipts.example.com"}, "https://receipts.example.com@malware.example/r.jpg", ErrReceiptHostNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithReceiptHostAllowlist(util.NewHostAllowlist(tt.allowlist)))
+			o, err := svc.Create(context.Background(), onSiteInput(lines(1, 1, 100)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = svc.PartialUpdate(context.Background(), o.Code, PartialUpdateInput{PaymentReceiptURL: &tt.receipt})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.As(err, &domainErr) || domainErr.Field != "payment_receipt_url" {
+				t.Errorf("error = %+v, want field payment_receipt_url", domainErr)
+			}
+			if !strings.Contains(err.Error(), "malware.example") {
+				t.Errorf("error %q does not name the host", err)
+			}
+			if repo.stored(o.ID).PaymentReceiptURL != nil {
+				t.Error("rejected receipt was stored")
+			}
+		})
+	}
+}
@@ -1,35 +1,203 @@
 package order
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
 
 // OrderFilters represents filters for querying orders
 type OrderFilters struct {
-	DateFrom    *string
-	DateTo      *string
-	Status      *OrderStatus
-	SaleType    *SaleType
-	ProductID   *string
-	ProductName *string
-	MinTotal    *int64
-	MaxTotal    *int64
-	Limit       int
-	Offset      int
+	CompanyID        *string // DefaultTenant also matches orders stored without a company
+	SalePointID      *string // DefaultTenant also matches orders stored without a sale point
+	DateFrom         *string
+	DateTo           *string
+	Status           *OrderStatus
+	Statuses         []OrderStatus // Any of these statuses (combined with Status)
+	SaleType         *SaleType
+	Channel          *Channel
+	PaymentStatus    *PaymentStatus
+	ProductID        *string
+	ProductName      *string
+	MinTotal         *int64
+	MaxTotal         *int64
+	Search           *string          // Free text over customer, address, note and product names
+	Query            *string          // Quick search: code prefix, customer name or phone, product name
+	IncludeArchived  bool             // Include archived orders (metrics only)
+	ExcludeCancelled bool             // Leave cancelled orders out, combined with any status filter (metrics only)
+	SkipCount        bool             // Listings skip the total count and report -1
+	SortBy           OrderSortField   // Listing order; empty means created_at
+	SortDir          SortDirection    // Empty means descending
+	Cursor           *util.PageCursor // Cursor pagination: list the orders after it instead of skipping Offset
+	Limit            int
+	Offset           int
+}
+
+// OrderSortField is a column order listings can be sorted by
+type OrderSortField string
+
+const (
+	SortFieldCreatedAt OrderSortField = "created_at"
+	SortFieldUpdatedAt OrderSortField = "updated_at"
+	SortFieldTotal     OrderSortField = "total"
+	SortFieldStatus    OrderSortField = "status"
+)
+
+// IsValidOrderSortField checks if order listings can be sorted by the field
+func IsValidOrderSortField(field OrderSortField) bool {
+	switch field {
+	case SortFieldCreatedAt, SortFieldUpdatedAt, SortFieldTotal, SortFieldStatus:
+		return true
+	}
+	return false
+}
+
+// PageCursor returns the cursor of the listing page, sorted by field (created_at when empty),
+// that ends with the order
+func (o *Order) PageCursor(field OrderSortField) util.PageCursor {
+	var value string
+	switch field {
+	case SortFieldUpdatedAt:
+		value = o.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case SortFieldTotal:
+		value = strconv.FormatInt(o.Total, 10)
+	case SortFieldStatus:
+		value = string(o.Status)
+	default:
+		field = SortFieldCreatedAt
+		value = o.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return util.PageCursor{Field: string(field), Value: value, ID: o.ID}
+}
+
+// SortDirection is the direction of a listing sort
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// IsValidSortDirection checks if the sort direction is supported
+func IsValidSortDirection(dir SortDirection) bool {
+	return dir == SortAsc || dir == SortDesc
+}
+
+// DateFilterLayout is the date-only form accepted by the date filters, besides RFC3339
+const DateFilterLayout = "2006-01-02"
+
+// ParseDateBound parses a date filter value: an RFC3339 timestamp, or a YYYY-MM-DD date in loc.
+// A date-only value is the start of that day, or its last millisecond (23:59:59.999) when endOfDay is set.
+func ParseDateBound(value string, endOfDay bool, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	day, err := time.ParseInLocation(DateFilterLayout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q, expected YYYY-MM-DD or RFC3339", ErrInvalidDateFilter, value)
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1).Add(-time.Millisecond)
+	}
+	return day.UTC(), nil
+}
+
+// ParseDateRange returns the date filters that can actually be applied.
+// Values that are not valid RFC3339 timestamps are returned as nil.
+func (f OrderFilters) ParseDateRange() (from, to *time.Time) {
+	if f.DateFrom != nil {
+		if t, err := time.Parse(time.RFC3339, *f.DateFrom); err == nil {
+			from = &t
+		}
+	}
+	if f.DateTo != nil {
+		if t, err := time.Parse(time.RFC3339, *f.DateTo); err == nil {
+			to = &t
+		}
+	}
+	return from, to
 }
 
 // OrderMetrics represents aggregated order metrics
 type OrderMetrics struct {
-	TotalSales     int64                 `json:"total_sales"`
-	AvgTicket      int64                 `json:"avg_ticket"`
-	OrdersByStatus map[OrderStatus]int   `json:"orders_by_status"`
-	TopProducts    []ProductSalesSummary `json:"top_products"`
+	OrderCount       int64                        `json:"order_count"`
+	TotalSales       int64                        `json:"total_sales"` // After discounts
+	TotalDiscounts   int64                        `json:"total_discounts"`
+	AvgTicket        int64                        `json:"avg_ticket"`
+	OrdersByStatus   map[OrderStatus]int          `json:"orders_by_status"`
+	OrdersByChannel  map[Channel]int              `json:"orders_by_channel"`
+	OrdersBySaleType map[SaleType]SaleTypeMetrics `json:"orders_by_sale_type"`
+	OrdersByPayment  map[PaymentStatus]int        `json:"orders_by_payment_status"`
+	TopProducts      []ProductSalesSummary        `json:"top_products"`
+	StageDurations   []StageDuration              `json:"stage_durations"` // One per entry of Stages, in lifecycle order
+}
+
+// SaleTypeMetrics aggregates the orders of one sale type
+type SaleTypeMetrics struct {
+	OrderCount int64 `json:"order_count"`
+	TotalSales int64 `json:"total_sales"`
+	AvgTicket  int64 `json:"avg_ticket"`
 }
 
+// Metrics buckets for stored values outside the known enums (legacy or hand-edited documents)
+const (
+	StatusUnknown   OrderStatus   = "UNKNOWN"
+	ChannelUnknown  Channel       = "UNKNOWN"
+	SaleTypeUnknown SaleType      = "UNKNOWN"
+	PaymentUnknown  PaymentStatus = "UNKNOWN"
+)
+
 // ProductSalesSummary represents product sales aggregation
 type ProductSalesSummary struct {
 	ProductID     string `json:"product_id"`
 	Name          string `json:"name"`
 	TotalQuantity int    `json:"total_quantity"`
-	TotalRevenue  int64  `json:"total_revenue"`
+	TotalRevenue  int64  `json:"total_revenue"` // Addons included
+	AddonRevenue  int64  `json:"addon_revenue"` // Part of the revenue from addons
+}
+
+// ProductSalesSort is the column a per-product sales table is sorted by (descending)
+type ProductSalesSort string
+
+const (
+	SortByRevenue  ProductSalesSort = "revenue"
+	SortByQuantity ProductSalesSort = "quantity"
+	SortByOrders   ProductSalesSort = "orders"
+)
+
+// IsValidProductSalesSort checks if the sort column is supported
+func IsValidProductSalesSort(sort ProductSalesSort) bool {
+	return sort == SortByRevenue || sort == SortByQuantity || sort == SortByOrders
+}
+
+// ProductSales represents the sales of a single product over the filtered orders
+type ProductSales struct {
+	ProductID     string `json:"product_id" bson:"product_id"`
+	Name          string `json:"name" bson:"name"`
+	TotalQuantity int    `json:"total_quantity" bson:"total_quantity"`
+	TotalRevenue  int64  `json:"total_revenue" bson:"total_revenue"` // In cents, addons included
+	AddonRevenue  int64  `json:"addon_revenue" bson:"addon_revenue"` // Part of the revenue from addons, in cents
+	OrderCount    int    `json:"order_count" bson:"order_count"`
+	AvgPrice      int64  `json:"avg_price" bson:"avg_price"` // Revenue / quantity, in cents
+}
+
+// BatchCursor identifies the last order processed by a batch operation.
+// Orders are walked in ascending (created_at, id) order.
+type BatchCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// TotalFix is a stored total correction to apply to an order.
+// Subtotal and DiscountAmount are recomputed with the total so the three stay consistent.
+type TotalFix struct {
+	OrderID        string
+	Subtotal       int64
+	DiscountAmount int64
+	Adjustment     TotalAdjustment
 }
 
 // Repository defines the contract for order data operations
@@ -52,9 +220,45 @@ type Repository interface {
 	// Count returns the total number of orders matching filters
 	Count(ctx context.Context, filters OrderFilters) (int64, error)
 
+	// FindAllWithCount retrieves one page of orders and the total matching filters in a single query.
+	// The total is -1 when filters.SkipCount is set.
+	FindAllWithCount(ctx context.Context, filters OrderFilters) ([]*Order, int64, error)
+
+	// FindByCustomerIdentification retrieves one page of a customer's orders, newest first,
+	// with the stats of all their orders
+	FindByCustomerIdentification(ctx context.Context, q CustomerHistoryQuery) ([]*Order, CustomerStats, error)
+
 	// ExistsByCode checks if an order exists with the given code
 	ExistsByCode(ctx context.Context, code string) (bool, error)
 
 	// GetMetrics returns aggregated order metrics
 	GetMetrics(ctx context.Context, filters OrderFilters) (*OrderMetrics, error)
+
+	// GetProductSales returns one page of per-product sales over the orders matching filters,
+	// excluding cancelled orders unless a status filter is set, plus the total number of products
+	GetProductSales(ctx context.Context, filters OrderFilters, sortBy ProductSalesSort) ([]ProductSales, int64, error)
+
+	// FindBatch retrieves up to limit orders matching filters, oldest first, after the cursor (if any)
+	FindBatch(ctx context.Context, filters OrderFilters, after *BatchCursor, limit int) ([]*Order, error)
+
+	// ApplyTotalFixes updates stored totals, subtotals and discount amounts and appends the adjustment to each order's audit trail.
+	// An order is only updated if its stored total still equals the adjustment's previous total.
+	// Returns the number of orders updated.
+	ApplyTotalFixes(ctx context.Context, fixes []TotalFix) (int64, error)
+
+	// FindArchivable retrieves up to limit terminal orders created before olderThan, oldest first, after the cursor (if any)
+	FindArchivable(ctx context.Context, olderThan time.Time, after *BatchCursor, limit int) ([]*Order, error)
+
+	// ArchiveOrders copies the orders to the archive and removes them from the active collection.
+	// It is safe to retry: orders already archived are not duplicated. Returns the number of orders moved.
+	ArchiveOrders(ctx context.Context, orders []*Order) (int64, error)
+
+	// FindArchivedByCode retrieves an archived order by its tracking code
+	FindArchivedByCode(ctx context.Context, code string) (*Order, error)
+
+	// FindReferencedProductIDs returns which of the product IDs appear in orders created since the given time
+	FindReferencedProductIDs(ctx context.Context, productIDs []string, since time.Time) ([]string, error)
+
+	// CountOpenOrdersWithProduct counts the orders in a non-terminal status that contain the product
+	CountOpenOrdersWithProduct(ctx context.Context, productID string) (int64, error)
 }
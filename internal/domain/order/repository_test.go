@@ -0,0 +1,224 @@
+package order
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryRepository is an in-memory Repository for service tests; methods a test needs but it
+// does not implement panic through the embedded nil interface.
+type memoryRepository struct {
+	Repository
+
+	mu       sync.Mutex
+	orders   map[string]*Order // By ID, stored as copies
+	archived map[string]*Order // Orders moved by ArchiveOrders, by ID
+	fixes    []TotalFix        // Every fix passed to ApplyTotalFixes
+	listed   []OrderFilters    // The filters of every FindAllWithCount call
+
+	histories []CustomerHistoryQuery // The query of every FindByCustomerIdentification call
+}
+
+func newMemoryRepository(orders ...*Order) *memoryRepository {
+	r := &memoryRepository{orders: make(map[string]*Order), archived: make(map[string]*Order)}
+	for _, o := range orders {
+		r.orders[o.ID] = cloneOrder(o)
+	}
+	return r
+}
+
+// cloneOrder copies the order and the slices a service may modify in place
+func cloneOrder(o *Order) *Order {
+	c := *o
+	c.Products = slices.Clone(o.Products)
+	c.TotalAdjustments = slices.Clone(o.TotalAdjustments)
+	return &c
+}
+
+func (r *memoryRepository) Create(ctx context.Context, o *Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[o.ID] = cloneOrder(o)
+	return nil
+}
+
+func (r *memoryRepository) FindByID(ctx context.Context, id string) (*Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if o, ok := r.orders[id]; ok {
+		return cloneOrder(o), nil
+	}
+	return nil, ErrOrderNotFound
+}
+
+// FindByCode matches the code exactly, like the unique index does
+func (r *memoryRepository) FindByCode(ctx context.Context, code string) (*Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, o := range r.orders {
+		if o.Code == code {
+			return cloneOrder(o), nil
+		}
+	}
+	return nil, ErrOrderNotFound
+}
+
+// FindArchivedByCode matches the code exactly among the archived orders
+func (r *memoryRepository) FindArchivedByCode(ctx context.Context, code string) (*Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, o := range r.archived {
+		if o.Code == code {
+			return cloneOrder(o), nil
+		}
+	}
+	return nil, ErrOrderNotFound
+}
+
+// FindArchivable returns terminal orders created before olderThan, oldest first, after the cursor
+func (r *memoryRepository) FindArchivable(ctx context.Context, olderThan time.Time, after *BatchCursor, limit int) ([]*Order, error) {
+	batch, err := r.FindBatch(ctx, OrderFilters{}, after, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	archivable := batch[:0]
+	for _, o := range batch {
+		if slices.Contains(TerminalStatuses, o.Status) && o.CreatedAt.Before(olderThan) {
+			archivable = append(archivable, o)
+		}
+	}
+	return archivable[:min(limit, len(archivable))], nil
+}
+
+// ArchiveOrders moves the orders to the archive; orders archived before are not counted again
+func (r *memoryRepository) ArchiveOrders(ctx context.Context, orders []*Order) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var moved int64
+	for _, o := range orders {
+		if _, ok := r.orders[o.ID]; !ok {
+			continue
+		}
+		r.archived[o.ID] = cloneOrder(o)
+		delete(r.orders, o.ID)
+		moved++
+	}
+	return moved, nil
+}
+
+func (r *memoryRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	_, err := r.FindByCode(ctx, code)
+	return err == nil, nil
+}
+
+func (r *memoryRepository) Update(ctx context.Context, o *Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[o.ID]
+	if !ok {
+		return ErrOrderNotFound
+	}
+	if stored.Version != o.Version {
+		return ErrOrderConflict
+	}
+	o.Version++
+	r.orders[o.ID] = cloneOrder(o)
+	return nil
+}
+
+// FindBatch ignores the filters and returns the orders by creation time
+func (r *memoryRepository) FindBatch(ctx context.Context, filters OrderFilters, after *BatchCursor, limit int) ([]*Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var batch []*Order
+	for _, o := range r.orders {
+		if after == nil || o.CreatedAt.After(after.CreatedAt) || (o.CreatedAt.Equal(after.CreatedAt) && o.ID > after.ID) {
+			batch = append(batch, cloneOrder(o))
+		}
+	}
+	slices.SortFunc(batch, func(a, b *Order) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return batch[:min(limit, len(batch))], nil
+}
+
+// FindAllWithCount records the filters and returns every order, newest first, without filtering
+func (r *memoryRepository) FindAllWithCount(ctx context.Context, filters OrderFilters) ([]*Order, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listed = append(r.listed, filters)
+	var page []*Order
+	for _, o := range r.orders {
+		page = append(page, cloneOrder(o))
+	}
+	slices.SortFunc(page, func(a, b *Order) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	total := int64(len(page))
+	if filters.SkipCount {
+		total = -1
+	}
+	return page, total, nil
+}
+
+// FindByCustomerIdentification pages the customer's orders newest first and sums all of them
+// like the aggregation does; the tenant filters are ignored
+func (r *memoryRepository) FindByCustomerIdentification(ctx context.Context, q CustomerHistoryQuery) ([]*Order, CustomerStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.histories = append(r.histories, q)
+
+	var (
+		matched []*Order
+		stats   CustomerStats
+	)
+	for _, o := range r.orders {
+		if o.Customer == nil || o.Customer.Identification != q.Identification || q.IDType != nil && o.Customer.IDType != *q.IDType {
+			continue
+		}
+		matched = append(matched, cloneOrder(o))
+		stats.OrderCount++
+		if o.Status != StatusCancelled {
+			stats.LifetimeSpend += o.Total
+		}
+		if stats.LastOrderAt == nil || o.CreatedAt.After(*stats.LastOrderAt) {
+			createdAt := o.CreatedAt
+			stats.LastOrderAt = &createdAt
+		}
+	}
+	slices.SortFunc(matched, func(a, b *Order) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	matched = matched[min(q.Offset, len(matched)):]
+	return matched[:min(q.Limit, len(matched))], stats, nil
+}
+
+// ApplyTotalFixes applies the fixes whose previous total still matches, like the bulk write
+func (r *memoryRepository) ApplyTotalFixes(ctx context.Context, fixes []TotalFix) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixes = append(r.fixes, fixes...)
+	var fixed int64
+	for _, fix := range fixes {
+		o, ok := r.orders[fix.OrderID]
+		if !ok || o.Total != fix.Adjustment.PreviousTotal {
+			continue
+		}
+		o.Total = fix.Adjustment.NewTotal
+		o.Subtotal = fix.Subtotal
+		o.DiscountAmount = fix.DiscountAmount
+		o.TotalAdjustments = append(o.TotalAdjustments, fix.Adjustment)
+		fixed++
+	}
+	return fixed, nil
+}
+
+// stored returns a copy of the order as currently saved
+func (r *memoryRepository) stored(id string) *Order {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return cloneOrder(r.orders[id])
+}
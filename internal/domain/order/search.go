@@ -0,0 +1,77 @@
+package order
+
+import "strings"
+
+// MaxSearchQueryLength is the maximum length of a free text search query
+const MaxSearchQueryLength = 100
+
+// Searchable fields reported in SearchResult.MatchedOn
+const (
+	SearchFieldCustomerName    = "customer.name"
+	SearchFieldCustomerPhone   = "customer.phone"
+	SearchFieldShippingAddress = "shipping_address"
+	SearchFieldNote            = "note"
+	SearchFieldProductName     = "products.name"
+)
+
+// SearchResult is an order found by free text search with the fields that matched
+type SearchResult struct {
+	Order     *Order
+	MatchedOn []string
+}
+
+// NormalizeSearchQuery trims the query and checks its length
+func NormalizeSearchQuery(query string) (string, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	if query == "" {
+		return "", ErrSearchQueryRequired
+	}
+	if len([]rune(query)) > MaxSearchQueryLength {
+		return "", ErrSearchQueryTooLong
+	}
+	return query, nil
+}
+
+// MatchedFields returns the searchable fields containing any term of the query (case-insensitive)
+func (o *Order) MatchedFields(query string) []string {
+	terms := strings.Fields(strings.ToLower(query))
+	contains := func(value string) bool {
+		value = strings.ToLower(value)
+		for _, term := range terms {
+			if strings.Contains(value, term) {
+				return true
+			}
+		}
+		return false
+	}
+
+	matched := []string{}
+	if o.Customer != nil && contains(o.Customer.Name) {
+		matched = append(matched, SearchFieldCustomerName)
+	}
+	if o.Customer != nil && (contains(o.Customer.Phone) || matchesPhoneDigits(o.Customer.PhoneNormalized, query)) {
+		matched = append(matched, SearchFieldCustomerPhone)
+	}
+	if o.ShippingAddress != nil && contains(*o.ShippingAddress) {
+		matched = append(matched, SearchFieldShippingAddress)
+	}
+	for _, note := range o.NoteList() {
+		if contains(note.Text) {
+			matched = append(matched, SearchFieldNote)
+			break
+		}
+	}
+	for _, p := range o.Products {
+		if contains(p.Name) {
+			matched = append(matched, SearchFieldProductName)
+			break
+		}
+	}
+	return matched
+}
+
+// matchesPhoneDigits reports whether a phone-like query matches the normalized phone whatever its formatting
+func matchesPhoneDigits(normalized, query string) bool {
+	digits, ok := PhoneSearchDigits(query)
+	return ok && normalized != "" && strings.Contains(normalized, digits)
+}
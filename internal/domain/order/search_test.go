@@ -0,0 +1,99 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr error
+	}{
+		{"trimmed", "  maria  ", "maria", nil},
+		{"inner spaces collapsed", "calle \t 45", "calle 45", nil},
+		{"empty", "", "", ErrSearchQueryRequired},
+		{"only spaces", " \n ", "", ErrSearchQueryRequired},
+		{"at the limit", strings.Repeat("ñ", MaxSearchQueryLength), strings.Repeat("ñ", MaxSearchQueryLength), nil},
+		{"too long", strings.Repeat("a", MaxSearchQueryLength+1), "", ErrSearchQueryTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeSearchQuery(tt.query)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("NormalizeSearchQuery(%q) = %q, %v, want %q, %v", tt.query, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// searchableOrder is a delivery order for Maria to Calle 45 with two burgers
+func searchableOrder() *Order {
+	o := NewOrder(SaleTypeDelivery, []OrderProduct{{ID: "p1", Name: "Hamburguesa doble", Price: 1000, Quantity: 2}})
+	address := "Calle 45 # 12-30"
+	o.ShippingAddress = &address
+	note := "Timbre dañado, llamar al llegar"
+	o.Customer = &Customer{Name: "María Pérez", Phone: "300 123 4567", PhoneNormalized: "+573001234567"}
+	o.Note = &note
+	return o
+}
+
+func TestMatchedFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"customer name ignoring case", "MARÍA", []string{SearchFieldCustomerName}},
+		{"address", "calle", []string{SearchFieldShippingAddress}},
+		{"note", "timbre", []string{SearchFieldNote}},
+		{"product", "hamburguesa", []string{SearchFieldProductName}},
+		{"phone as entered", "300 123", []string{SearchFieldCustomerPhone}},
+		{"phone in another format", "+57 300-123-4567", []string{SearchFieldCustomerPhone}},
+		{"phone with dots and parentheses", "(300) 123.4567", []string{SearchFieldCustomerPhone}},
+		{"phone with 00 prefix", "0057 3001234567", []string{SearchFieldCustomerPhone}},
+		{"other phone", "310 987 6543", []string{}},
+		{"several fields", "maría hamburguesa", []string{SearchFieldCustomerName, SearchFieldProductName}},
+		{"nothing", "pizza", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchableOrder().MatchedFields(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchedFields(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	repo := newMemoryRepository(searchableOrder())
+	svc := NewService(repo)
+
+	results, total, err := svc.Search(context.Background(), "  calle   principal ", OrderFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(results) != 1 {
+		t.Fatalf("results = %d of %d, want 1 of 1", len(results), total)
+	}
+	if got := repo.listed[0].Search; got == nil || *got != "calle principal" {
+		t.Errorf("repository search = %v, want the normalized query", got)
+	}
+	if want := []string{SearchFieldShippingAddress}; !reflect.DeepEqual(results[0].MatchedOn, want) {
+		t.Errorf("matched on = %v, want %v", results[0].MatchedOn, want)
+	}
+
+	if _, _, err := svc.Search(context.Background(), " ", OrderFilters{}); !errors.Is(err, ErrSearchQueryRequired) {
+		t.Errorf("blank query err = %v, want %v", err, ErrSearchQueryRequired)
+	}
+	if len(repo.listed) != 1 {
+		t.Error("a blank query reached the repository")
+	}
+}
@@ -2,37 +2,310 @@ package order
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/emerarteaga/products-api/internal/util"
 )
 
+// EventRecorder receives order lifecycle events, e.g. for instrumentation
+type EventRecorder interface {
+	// OrderCreated is called after an order has been persisted
+	OrderCreated(o *Order)
+
+	// OrderStatusChanged is called after a status change has been persisted
+	OrderStatusChanged(o *Order, previous OrderStatus)
+}
+
+// noopEventRecorder discards all events
+type noopEventRecorder struct{}
+
+func (noopEventRecorder) OrderCreated(*Order)                    {}
+func (noopEventRecorder) OrderStatusChanged(*Order, OrderStatus) {}
+
+// ServiceAPI is the set of order use cases consumed by the HTTP handlers
+type ServiceAPI interface {
+	Create(ctx context.Context, input CreateInput) (*Order, error)
+	ValidateCreate(ctx context.Context, input CreateInput) (*Order, error)
+	GetByID(ctx context.Context, id string) (*Order, error)
+	GetByCode(ctx context.Context, code string) (*Order, error)
+	PartialUpdate(ctx context.Context, code string, input PartialUpdateInput) (*Order, error)
+	Modify(ctx context.Context, code string, input ModifyInput) (*ModifyResult, error)
+	GetAll(ctx context.Context, filters OrderFilters) ([]*Order, int64, error)
+	Search(ctx context.Context, query string, filters OrderFilters) ([]SearchResult, int64, error)
+	GetCustomerHistory(ctx context.Context, q CustomerHistoryQuery) (*CustomerHistory, error)
+	GetMetrics(ctx context.Context, filters OrderFilters) (*OrderMetrics, error)
+	GetProductSales(ctx context.Context, filters OrderFilters, sortBy ProductSalesSort) ([]ProductSales, int64, error)
+	RecalculateTotals(ctx context.Context, input RecalculateTotalsInput) (*RecalculateTotalsResult, error)
+	Archive(ctx context.Context, input ArchiveInput) (*ArchiveResult, error)
+	PageLimits() util.PageLimits
+	Limits() OrderLimits
+	Currency() money.Currency
+	FilterLocation() *time.Location
+	Transitions(saleType SaleType) Transitions
+	TrackingURL(code string) (string, error)
+	CustomerEdit(ctx context.Context, code string, input CustomerEditInput) (*Order, error)
+	GetZReport(ctx context.Context, date string, loc *time.Location) (*ZReport, error)
+	Duplicate(ctx context.Context, code string, input DuplicateInput) (*DuplicateResult, error)
+	Export(ctx context.Context, input ExportInput, emit ExportEmitFunc) error
+	SubscribeStatus(code string) (*StatusSubscription, error)
+	ConfirmPayment(ctx context.Context, code string) (*Order, error)
+	AddNote(ctx context.Context, code string, input AddNoteInput) (*Order, error)
+	BatchUpdateStatus(ctx context.Context, input BatchStatusInput) ([]BatchStatusResult, error)
+}
+
+// Compile-time check that Service implements ServiceAPI
+var _ ServiceAPI = (*Service)(nil)
+
 // Service handles business logic for orders
 type Service struct {
-	repo Repository
+	repo               Repository
+	pageLimits         util.PageLimits
+	events             EventRecorder
+	autoAdvance        []AutoAdvanceRule
+	archive            ArchivePolicy
+	receiptHosts       util.HostAllowlist
+	phoneCountryCode   string
+	limits             OrderLimits
+	sizeLimit          util.DocumentSizeLimit
+	currency           money.Currency
+	stateMachine       StateMachine
+	trackingURL        string
+	customerEditWindow time.Duration
+	catalog            Catalog
+	codes              CodeGenerator
+	feed               *StatusFeed
+	filterLocation     *time.Location
+	paidDispatch       bool
+	keepStatusOnModify bool
+	maxNotes           int
+}
+
+// Option configures optional service behavior
+type Option func(*Service)
+
+// WithPageLimits sets the default and maximum page size for order listings
+func WithPageLimits(limits util.PageLimits) Option {
+	return func(s *Service) {
+		s.pageLimits = limits
+	}
+}
+
+// WithEventRecorder sets the recorder notified about order lifecycle events
+func WithEventRecorder(recorder EventRecorder) Option {
+	return func(s *Service) {
+		s.events = recorder
+	}
+}
+
+// WithAutoAdvanceRules sets the rules that advance the order status automatically
+func WithAutoAdvanceRules(rules []AutoAdvanceRule) Option {
+	return func(s *Service) {
+		s.autoAdvance = rules
+	}
+}
+
+// WithArchivePolicy sets the bounds for archiving old terminal orders
+func WithArchivePolicy(policy ArchivePolicy) Option {
+	return func(s *Service) {
+		s.archive = policy
+	}
+}
+
+// WithReceiptHostAllowlist restricts the hosts payment receipt URLs may point at
+func WithReceiptHostAllowlist(allowlist util.HostAllowlist) Option {
+	return func(s *Service) {
+		s.receiptHosts = allowlist
+	}
+}
+
+// WithDefaultPhoneCountryCode sets the country code applied to phones entered without one
+func WithDefaultPhoneCountryCode(code string) Option {
+	return func(s *Service) {
+		s.phoneCountryCode = code
+	}
+}
+
+// WithOrderLimits sets the order size and value guards
+func WithOrderLimits(limits OrderLimits) Option {
+	return func(s *Service) {
+		s.limits = limits
+	}
+}
+
+// WithDocumentSizeLimit rejects orders whose stored document would exceed the soft size limit
+func WithDocumentSizeLimit(limit util.DocumentSizeLimit) Option {
+	return func(s *Service) {
+		s.sizeLimit = limit
+	}
+}
+
+// WithCurrency sets the currency of order amounts
+func WithCurrency(currency money.Currency) Option {
+	return func(s *Service) {
+		s.currency = currency
+	}
+}
+
+// WithStateMachine sets the per-sale-type status transitions; sale types without one use DefaultTransitionsFor
+func WithStateMachine(machine StateMachine) Option {
+	return func(s *Service) {
+		s.stateMachine = machine
+	}
+}
+
+// TrackingURLPlaceholder is replaced with the order code in the tracking URL template
+const TrackingURLPlaceholder = "{code}"
+
+// WithTrackingURLTemplate sets the customer tracking page URL; "{code}" is replaced with the order code
+func WithTrackingURLTemplate(template string) Option {
+	return func(s *Service) {
+		s.trackingURL = template
+	}
+}
+
+// WithCustomerEditWindow lets customers edit their order through the tracking code for this long after creation
+func WithCustomerEditWindow(window time.Duration) Option {
+	return func(s *Service) {
+		s.customerEditWindow = window
+	}
+}
+
+// WithCodeGenerator sets how new order codes are generated (legacy by default)
+func WithCodeGenerator(codes CodeGenerator) Option {
+	return func(s *Service) {
+		s.codes = codes
+	}
+}
+
+// WithCatalog sets the product lookup used to re-validate lines when duplicating orders
+func WithCatalog(catalog Catalog) Option {
+	return func(s *Service) {
+		s.catalog = catalog
+	}
+}
+
+// WithFilterLocation sets the time zone of date-only filter values (UTC by default)
+func WithFilterLocation(loc *time.Location) Option {
+	return func(s *Service) {
+		s.filterLocation = loc
+	}
+}
+
+// WithPaymentRequiredForDispatch blocks DELIVERY orders from going OUT_FOR_DELIVERY until their payment is CONFIRMED
+func WithPaymentRequiredForDispatch(required bool) Option {
+	return func(s *Service) {
+		s.paidDispatch = required
+	}
+}
+
+// WithMaxNotes bounds the notes of an order (DefaultMaxNotes by default, no limit when <= 0)
+func WithMaxNotes(max int) Option {
+	return func(s *Service) {
+		s.maxNotes = max
+	}
+}
+
+// WithStatusKeptOnModify stops PUT modifications from moving VERIFIED and IN_PROGRESS orders
+// back to VERIFIED; only CREATED orders are verified by a product change
+func WithStatusKeptOnModify(keep bool) Option {
+	return func(s *Service) {
+		s.keepStatusOnModify = keep
+	}
+}
+
+// WithStatusFeed publishes status changes to the feed behind the tracking streams
+func WithStatusFeed(feed *StatusFeed) Option {
+	return func(s *Service) {
+		s.feed = feed
+	}
 }
 
 // NewService creates a new order service
-func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{
+		repo:             repo,
+		pageLimits:       util.DefaultPageLimits,
+		events:           noopEventRecorder{},
+		archive:          DefaultArchivePolicy,
+		phoneCountryCode: DefaultPhoneCountryCode,
+		currency:         money.Default,
+		filterLocation:   time.UTC,
+		maxNotes:         DefaultMaxNotes,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// PageLimits returns the page size limits for order listings
+func (s *Service) PageLimits() util.PageLimits {
+	return s.pageLimits
+}
+
+// Limits returns the order size and value guards
+func (s *Service) Limits() OrderLimits {
+	return s.limits
+}
+
+// Currency returns the currency of order amounts
+func (s *Service) Currency() money.Currency {
+	return s.currency
+}
+
+// FilterLocation returns the time zone date-only filter values are interpreted in
+func (s *Service) FilterLocation() *time.Location {
+	return s.filterLocation
+}
+
+// TrackingURL returns the customer tracking page URL of an order code
+func (s *Service) TrackingURL(code string) (string, error) {
+	if s.trackingURL == "" {
+		return "", ErrTrackingURLNotConfigured
+	}
+	return strings.ReplaceAll(s.trackingURL, TrackingURLPlaceholder, url.PathEscape(code)), nil
+}
+
+// Transitions returns the effective state machine of a sale type
+func (s *Service) Transitions(saleType SaleType) Transitions {
+	return s.stateMachine.For(saleType)
 }
 
 // CreateInput represents input for creating an order
 type CreateInput struct {
+	CompanyID         string
+	SalePointID       string
 	SaleType          SaleType
+	Channel           Channel // Defaults to ChannelOther when empty
 	Products          []OrderProduct
-	Note              *string
+	Note              *string        // Becomes the first note
+	NoteVisibility    NoteVisibility // Defaults to PUBLIC: the note is sent with the order
 	Customer          *Customer
 	ShippingAddress   *string
 	TableNumber       *int
 	PaymentReceiptURL *string
 	PaymentAccountID  *string
+	Discount          *Discount
+	ExpectedTotal     *int64 // Total shown to the customer, in cents; rejected with ErrTotalMismatch when it differs
+	OverrideLimits    bool   // Skip the order size guards; only for trusted staff callers
 }
 
 // PartialUpdateInput represents input for partial update (PATCH)
 type PartialUpdateInput struct {
-	Status            *OrderStatus
-	Note              *string
-	PaymentReceiptURL *string
-	PaymentAccountID  *string
+	Status              *OrderStatus
+	Note                *string        // Appended as a new note
+	NoteVisibility      NoteVisibility // Defaults to INTERNAL for a new note; alone it changes the visibility of the latest note
+	PaymentReceiptURL   *string
+	PaymentAccountID    *string
+	PaymentStatus       *PaymentStatus
+	ExpectedVersion     *int64 // Version the client edited; ErrOrderConflict when the order changed since
+	OverrideFieldPolicy bool   // Skip the field-by-status policy; only for trusted admin callers
 }
 
 // ModifyInput represents input for full modification (PUT)
@@ -40,40 +313,124 @@ type ModifyInput struct {
 	Products        []OrderProduct
 	ShippingAddress *string
 	Customer        *Customer
-	Note            *string
+	Note            *string        // Appended as a new note
+	NoteVisibility  NoteVisibility // Defaults to INTERNAL for a new note; alone it changes the visibility of the latest note
+	Discount        *Discount      // Replaces the discount; a zero value removes it
+	ExpectedVersion *int64         // Version the client edited; ErrOrderConflict when the order changed since
+	OverrideLimits  bool           // Skip the order size guards; only for trusted staff callers
+}
+
+// ModifyResult holds the modified order and what the modification changed
+type ModifyResult struct {
+	Order   *Order
+	Changes OrderDiff
 }
 
 // Create creates a new order
 func (s *Service) Create(ctx context.Context, input CreateInput) (*Order, error) {
+	o, err := s.prepare(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Regenerate the code until it is unused; short codes collide as the order count grows
+	for attempt := 1; ; attempt++ {
+		exists, err := s.repo.ExistsByCode(ctx, o.Code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check code existence: %w", err)
+		}
+		if !exists {
+			break
+		}
+		if attempt == maxCodeAttempts {
+			return nil, fmt.Errorf("failed to generate a unique order code after %d attempts", maxCodeAttempts)
+		}
+		o.Code = s.codes.Generate()
+	}
+
+	// Save to repository
+	if err := s.repo.Create(ctx, o); err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	s.events.OrderCreated(o)
+	if o.Status != StatusCreated {
+		s.statusChanged(o, StatusCreated)
+	}
+	return o, nil
+}
+
+// ValidateCreate runs the same pipeline as Create without persisting anything.
+// It returns the order that Create would store, or the validation error Create would return.
+func (s *Service) ValidateCreate(ctx context.Context, input CreateInput) (*Order, error) {
+	return s.prepare(input)
+}
+
+// prepare builds, sanitizes and validates a new order; shared by Create and ValidateCreate
+func (s *Service) prepare(input CreateInput) (*Order, error) {
+	if err := validateTenant(input.CompanyID, input.SalePointID); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
 	// Create new order
 	o := NewOrder(input.SaleType, input.Products)
+	o.Code = s.codes.Generate()
+	o.CompanyID = input.CompanyID
+	o.SalePointID = input.SalePointID
 
 	// Set optional fields
-	o.Note = input.Note
+	if input.Channel != "" {
+		o.Channel = input.Channel
+	}
+	if input.Note != nil && *input.Note != "" {
+		if err := o.addNote(*input.Note, ActorUser, input.NoteVisibility, NotePublic, s.maxNotes, o.CreatedAt); err != nil {
+			return nil, fmt.Errorf("validation error: %w", err)
+		}
+	}
 	o.Customer = input.Customer
 	o.ShippingAddress = input.ShippingAddress
 	o.TableNumber = input.TableNumber
 	o.PaymentReceiptURL = input.PaymentReceiptURL
 	o.PaymentAccountID = input.PaymentAccountID
+	if o.PaymentReceiptURL != nil && *o.PaymentReceiptURL != "" {
+		o.receiptAttached()
+	}
+	if input.Discount != nil {
+		o.setDiscount(input.Discount)
+	}
+
+	// Sanitize free text before validation
+	if err := o.SanitizeText(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if o.Customer != nil {
+		if err := o.Customer.NormalizePhone(s.phoneCountryCode); err != nil {
+			return nil, fmt.Errorf("validation error: %w", err)
+		}
+	}
 
 	// Validate business rules
 	if err := o.Validate(); err != nil {
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
-
-	// Check if code already exists (very unlikely but possible)
-	exists, err := s.repo.ExistsByCode(ctx, o.Code)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check code existence: %w", err)
+	if !input.OverrideLimits {
+		if err := s.limits.Check(o); err != nil {
+			return nil, fmt.Errorf("validation error: %w", err)
+		}
 	}
-	if exists {
-		// Regenerate code and try again
-		o.Code = generateOrderCode()
+	if err := s.checkDocumentSize(o); err != nil {
+		return nil, err
 	}
 
-	// Save to repository
-	if err := s.repo.Create(ctx, o); err != nil {
-		return nil, fmt.Errorf("failed to create order: %w", err)
+	// Compare against the final total, once everything that affects it has been applied
+	if input.ExpectedTotal != nil && *input.ExpectedTotal != o.Total {
+		err := fmt.Errorf("%w: sent %d, calculated %d", ErrTotalMismatch, *input.ExpectedTotal, o.Total)
+		return nil, fmt.Errorf("validation error: %w", apperrors.NewDomainError(err, "total", *input.ExpectedTotal))
+	}
+
+	// Attaching a receipt at creation may advance the status
+	if o.PaymentReceiptURL != nil && *o.PaymentReceiptURL != "" {
+		applyAutoAdvance(o, s.autoAdvance, s.Transitions(o.SaleType), s.statusGuard(), TriggerPaymentReceipt)
 	}
 
 	return o, nil
@@ -95,11 +452,16 @@ func (s *Service) GetByID(ctx context.Context, id string) (*Order, error) {
 
 // GetByCode retrieves an order by tracking code
 func (s *Service) GetByCode(ctx context.Context, code string) (*Order, error) {
+	code = NormalizeCode(code)
 	if code == "" {
 		return nil, ErrInvalidOrderCode
 	}
 
 	order, err := s.repo.FindByCode(ctx, code)
+	if errors.Is(err, ErrOrderNotFound) {
+		// Old tracking links keep resolving once the order is archived
+		return s.repo.FindArchivedByCode(ctx, code)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +471,7 @@ func (s *Service) GetByCode(ctx context.Context, code string) (*Order, error) {
 
 // PartialUpdate updates an order partially (PATCH - no product changes)
 func (s *Service) PartialUpdate(ctx context.Context, code string, input PartialUpdateInput) (*Order, error) {
+	code = NormalizeCode(code)
 	if code == "" {
 		return nil, ErrInvalidOrderCode
 	}
@@ -118,36 +481,75 @@ func (s *Service) PartialUpdate(ctx context.Context, code string, input PartialU
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(order, input.ExpectedVersion); err != nil {
+		return nil, err
+	}
 
-	// Update allowed fields
-	if input.Status != nil {
-		if err := order.UpdateStatus(*input.Status); err != nil {
+	// Enforce which fields may change in the current status
+	if !input.OverrideFieldPolicy {
+		if err := checkEditableFields(order.Status, input.changedFields()); err != nil {
 			return nil, err
 		}
 	}
 
-	if input.Note != nil {
-		order.Note = input.Note
+	// Update allowed fields
+	previousStatus := order.Status
+	if err := s.applyNote(order, input.Note, input.NoteVisibility); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
 	if input.PaymentReceiptURL != nil {
+		if host, ok := s.receiptHosts.Check(*input.PaymentReceiptURL); *input.PaymentReceiptURL != "" && !ok {
+			err := fmt.Errorf("%w: %s", ErrReceiptHostNotAllowed, host)
+			return nil, fmt.Errorf("validation error: %w", apperrors.NewDomainError(err, "payment_receipt_url", *input.PaymentReceiptURL))
+		}
 		order.PaymentReceiptURL = input.PaymentReceiptURL
+		if *input.PaymentReceiptURL != "" {
+			order.receiptAttached()
+		} else {
+			order.receiptRemoved()
+		}
 	}
 
 	if input.PaymentAccountID != nil {
 		order.PaymentAccountID = input.PaymentAccountID
 	}
 
+	// Payment changes go first, so one request can confirm the payment and dispatch the order
+	if input.PaymentStatus != nil {
+		if err := order.UpdatePaymentStatus(*input.PaymentStatus); err != nil {
+			return nil, err
+		}
+	}
+	if input.Status != nil {
+		if err := s.checkStatusGuard(order, *input.Status); err != nil {
+			return nil, err
+		}
+		if err := order.UpdateStatus(*input.Status, s.Transitions(order.SaleType)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Evaluate auto-advance rules after the primary mutation
+	if input.PaymentReceiptURL != nil && *input.PaymentReceiptURL != "" {
+		applyAutoAdvance(order, s.autoAdvance, s.Transitions(order.SaleType), s.statusGuard(), TriggerPaymentReceipt)
+	}
+
 	// Update in repository
 	if err := s.repo.Update(ctx, order); err != nil {
 		return nil, fmt.Errorf("failed to update order: %w", err)
 	}
 
+	if order.Status != previousStatus {
+		s.statusChanged(order, previousStatus)
+	}
+
 	return order, nil
 }
 
-// Modify modifies an order (PUT - products allowed, auto VERIFIED)
-func (s *Service) Modify(ctx context.Context, code string, input ModifyInput) (*Order, error) {
+// Modify modifies an order (PUT - products allowed, auto VERIFIED unless WithStatusKeptOnModify)
+func (s *Service) Modify(ctx context.Context, code string, input ModifyInput) (*ModifyResult, error) {
+	code = NormalizeCode(code)
 	if code == "" {
 		return nil, ErrInvalidOrderCode
 	}
@@ -157,6 +559,9 @@ func (s *Service) Modify(ctx context.Context, code string, input ModifyInput) (*
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(order, input.ExpectedVersion); err != nil {
+		return nil, err
+	}
 
 	// Check if order can be modified
 	if !order.CanBeModified() {
@@ -164,8 +569,13 @@ func (s *Service) Modify(ctx context.Context, code string, input ModifyInput) (*
 	}
 
 	// Update products if provided
+	before := order.snapshot()
+	previousStatus := order.Status
+	if input.Discount != nil {
+		order.setDiscount(input.Discount)
+	}
 	if len(input.Products) > 0 {
-		if err := order.UpdateProducts(input.Products); err != nil {
+		if err := order.UpdateProducts(input.Products, s.Transitions(order.SaleType), s.keepStatusOnModify); err != nil {
 			return nil, err
 		}
 	}
@@ -176,47 +586,73 @@ func (s *Service) Modify(ctx context.Context, code string, input ModifyInput) (*
 	}
 
 	if input.Customer != nil {
+		if err := input.Customer.NormalizePhone(s.phoneCountryCode); err != nil {
+			return nil, fmt.Errorf("validation error: %w", err)
+		}
 		order.Customer = input.Customer
 	}
 
-	if input.Note != nil {
-		order.Note = input.Note
+	if err := s.applyNote(order, input.Note, input.NoteVisibility); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	// Sanitize free text before validation
+	if err := order.SanitizeText(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
 	// Validate updated order
 	if err := order.Validate(); err != nil {
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
+	if !input.OverrideLimits {
+		if err := s.limits.Check(order); err != nil {
+			return nil, fmt.Errorf("validation error: %w", err)
+		}
+	}
+	if err := s.checkDocumentSize(order); err != nil {
+		return nil, err
+	}
+
+	// Record what changed in the audit trail
+	changes := Diff(before, order)
+	if !changes.IsEmpty() {
+		order.EditHistory = append(order.EditHistory, FieldEdit{
+			Fields:   changes.Fields(),
+			Actor:    ActorUser,
+			EditedAt: time.Now(),
+			Changes:  &changes,
+		})
+	}
 
 	// Update in repository
 	if err := s.repo.Update(ctx, order); err != nil {
 		return nil, fmt.Errorf("failed to update order: %w", err)
 	}
 
-	return order, nil
+	if order.Status != previousStatus {
+		s.statusChanged(order, previousStatus)
+	}
+
+	return &ModifyResult{Order: order, Changes: changes}, nil
 }
 
 // GetAll retrieves all orders with filters
 func (s *Service) GetAll(ctx context.Context, filters OrderFilters) ([]*Order, int64, error) {
-	// Set default pagination
-	if filters.Limit <= 0 {
-		filters.Limit = 50
-	}
-	if filters.Limit > 100 {
-		filters.Limit = 100 // Maximum limit
-	}
+	// Apply configured pagination limits
+	filters.Limit = s.pageLimits.Resolve(filters.Limit)
 	if filters.Offset < 0 {
 		filters.Offset = 0
 	}
 
-	// Get total count with same filters
-	total, err := s.repo.Count(ctx, filters)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	// Cursor pages start right after the cursor and are not counted, which is what makes deep pages slow
+	if filters.Cursor != nil {
+		filters.Offset = 0
+		filters.SkipCount = true
 	}
 
-	// Get orders
-	orders, err := s.repo.FindAll(ctx, filters)
+	// The page and the total come from one query, so the total always matches the page
+	orders, total, err := s.repo.FindAllWithCount(ctx, filters)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get orders: %w", err)
 	}
@@ -224,6 +660,128 @@ func (s *Service) GetAll(ctx context.Context, filters OrderFilters) ([]*Order, i
 	return orders, total, nil
 }
 
+// Search finds orders by free text combined with the standard filters
+func (s *Service) Search(ctx context.Context, query string, filters OrderFilters) ([]SearchResult, int64, error) {
+	query, err := NormalizeSearchQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	filters.Search = &query
+
+	orders, total, err := s.GetAll(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]SearchResult, len(orders))
+	for i, o := range orders {
+		results[i] = SearchResult{Order: o, MatchedOn: o.MatchedFields(query)}
+	}
+
+	return results, total, nil
+}
+
+// Total recalculation batch bounds
+const (
+	defaultRecalculateBatchSize = 200
+	maxRecalculateBatchSize     = 1000
+)
+
+// RecalculateTotalsInput represents input for recalculating stored order totals
+type RecalculateTotalsInput struct {
+	Filters   OrderFilters
+	DryRun    bool
+	After     *BatchCursor // Resume after this order; nil starts from the oldest match
+	BatchSize int
+}
+
+// TotalDiscrepancy describes an order whose stored amounts do not match its line items
+type TotalDiscrepancy struct {
+	OrderID          string
+	Code             string
+	StoredTotal      int64
+	ComputedTotal    int64
+	StoredSubtotal   int64 // 0 on orders stored before discounts existed
+	ComputedSubtotal int64
+}
+
+// RecalculateTotalsResult reports the outcome of one recalculation batch
+type RecalculateTotalsResult struct {
+	DryRun        bool
+	Processed     int
+	Discrepancies []TotalDiscrepancy
+	Fixed         int64
+	NextCursor    *BatchCursor // nil when there are no more orders to process
+}
+
+// RecalculateTotals recomputes totals for one bounded batch of matching orders.
+// Discrepancies are always reported; they are only fixed when DryRun is false.
+// Call again with NextCursor to resume with the following batch.
+func (s *Service) RecalculateTotals(ctx context.Context, input RecalculateTotalsInput) (*RecalculateTotalsResult, error) {
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRecalculateBatchSize
+	}
+	if batchSize > maxRecalculateBatchSize {
+		batchSize = maxRecalculateBatchSize
+	}
+
+	orders, err := s.repo.FindBatch(ctx, input.Filters, input.After, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	result := &RecalculateTotalsResult{
+		DryRun:        input.DryRun,
+		Processed:     len(orders),
+		Discrepancies: []TotalDiscrepancy{},
+	}
+
+	now := time.Now()
+	var fixes []TotalFix
+	for _, o := range orders {
+		computed := *o
+		computed.CalculateTotal()
+		if computed.Total == o.Total && computed.Subtotal == o.Subtotal && computed.DiscountAmount == o.DiscountAmount {
+			continue
+		}
+		result.Discrepancies = append(result.Discrepancies, TotalDiscrepancy{
+			OrderID:          o.ID,
+			Code:             o.Code,
+			StoredTotal:      o.Total,
+			ComputedTotal:    computed.Total,
+			StoredSubtotal:   o.Subtotal,
+			ComputedSubtotal: computed.Subtotal,
+		})
+		fixes = append(fixes, TotalFix{
+			OrderID:        o.ID,
+			Subtotal:       computed.Subtotal,
+			DiscountAmount: computed.DiscountAmount,
+			Adjustment: TotalAdjustment{
+				PreviousTotal: o.Total,
+				NewTotal:      computed.Total,
+				Reason:        "recalculated from line items",
+				AdjustedAt:    now,
+			},
+		})
+	}
+
+	if !input.DryRun && len(fixes) > 0 {
+		fixed, err := s.repo.ApplyTotalFixes(ctx, fixes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fix order totals: %w", err)
+		}
+		result.Fixed = fixed
+	}
+
+	if len(orders) == batchSize {
+		last := orders[len(orders)-1]
+		result.NextCursor = &BatchCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return result, nil
+}
+
 // GetMetrics retrieves aggregated order metrics
 func (s *Service) GetMetrics(ctx context.Context, filters OrderFilters) (*OrderMetrics, error) {
 	metrics, err := s.repo.GetMetrics(ctx, filters)
@@ -233,3 +791,96 @@ func (s *Service) GetMetrics(ctx context.Context, filters OrderFilters) (*OrderM
 
 	return metrics, nil
 }
+
+// GetProductSales retrieves one page of per-product sales, sorted by revenue unless specified
+func (s *Service) GetProductSales(ctx context.Context, filters OrderFilters, sortBy ProductSalesSort) ([]ProductSales, int64, error) {
+	if sortBy == "" {
+		sortBy = SortByRevenue
+	}
+	if !IsValidProductSalesSort(sortBy) {
+		return nil, 0, ErrInvalidSortField
+	}
+
+	// Apply configured pagination limits
+	filters.Limit = s.pageLimits.Resolve(filters.Limit)
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	sales, total, err := s.repo.GetProductSales(ctx, filters, sortBy)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get product sales: %w", err)
+	}
+
+	return sales, total, nil
+}
+
+// checkDocumentSize rejects an order whose stored document would exceed the soft size limit
+func (s *Service) checkDocumentSize(o *Order) error {
+	size, exceeded, err := s.sizeLimit.Check(o.Code, o)
+	if err != nil {
+		return fmt.Errorf("failed to measure order size: %w", err)
+	}
+	if exceeded {
+		return fmt.Errorf("validation error: %w: %d bytes, max %d", ErrOrderTooLarge, size, s.sizeLimit.MaxBytes)
+	}
+	return nil
+}
+
+// SubscribeStatus starts receiving the status changes of an order for a tracking stream.
+// The order is not looked up; callers load it to send the current status.
+func (s *Service) SubscribeStatus(code string) (*StatusSubscription, error) {
+	if s.feed == nil {
+		return nil, ErrStatusStreamsDisabled
+	}
+	code = NormalizeCode(code)
+	if code == "" {
+		return nil, ErrInvalidOrderCode
+	}
+	return s.feed.Subscribe(code)
+}
+
+// statusChanged notifies the event recorder and the tracking streams of a persisted status change
+func (s *Service) statusChanged(o *Order, previous OrderStatus) {
+	s.events.OrderStatusChanged(o, previous)
+	if s.feed != nil {
+		s.feed.OrderStatusChanged(o, previous)
+	}
+}
+
+// ConfirmPayment marks the payment of an order as CONFIRMED
+func (s *Service) ConfirmPayment(ctx context.Context, code string) (*Order, error) {
+	code = NormalizeCode(code)
+	if code == "" {
+		return nil, ErrInvalidOrderCode
+	}
+
+	order, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err := order.UpdatePaymentStatus(PaymentConfirmed); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+	return order, nil
+}
+
+// statusGuard returns the business rules status changes must pass, or nil when there are none
+func (s *Service) statusGuard() StatusGuard {
+	if s.paidDispatch {
+		return checkDispatchPayment
+	}
+	return nil
+}
+
+// checkStatusGuard applies the status guard to a requested status change
+func (s *Service) checkStatusGuard(o *Order, to OrderStatus) error {
+	if guard := s.statusGuard(); guard != nil {
+		return guard(o, to)
+	}
+	return nil
+}
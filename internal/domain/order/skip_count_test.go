@@ -0,0 +1,31 @@
+package order
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetAllSkipCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		filters   OrderFilters
+		wantTotal int64
+	}{
+		{"counted", OrderFilters{}, 2},
+		{"count skipped", OrderFilters{SkipCount: true}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(validOrder(1), validOrder(2))
+
+			orders, total, err := NewService(repo).GetAll(context.Background(), tt.filters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != tt.wantTotal || len(orders) != 2 {
+				t.Errorf("got %d orders, total %d, want 2 and %d", len(orders), total, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package order
+
+import "testing"
+
+func TestSortValidation(t *testing.T) {
+	fields := []struct {
+		field OrderSortField
+		want  bool
+	}{
+		{SortFieldCreatedAt, true},
+		{SortFieldUpdatedAt, true},
+		{SortFieldTotal, true},
+		{SortFieldStatus, true},
+		{"", false},
+		{"code", false},
+		{"TOTAL", false},
+		{"total ", false},
+	}
+	for _, tt := range fields {
+		if got := IsValidOrderSortField(tt.field); got != tt.want {
+			t.Errorf("IsValidOrderSortField(%q) = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+
+	dirs := []struct {
+		dir  SortDirection
+		want bool
+	}{
+		{SortAsc, true},
+		{SortDesc, true},
+		{"", false},
+		{"ascending", false},
+		{"-1", false},
+	}
+	for _, tt := range dirs {
+		if got := IsValidSortDirection(tt.dir); got != tt.want {
+			t.Errorf("IsValidSortDirection(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
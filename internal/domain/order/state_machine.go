@@ -0,0 +1,250 @@
+package order
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Transitions maps each status to the statuses it can move to.
+// Statuses without an entry are not used by the sale type.
+type Transitions map[OrderStatus][]OrderStatus
+
+// DefaultTransitions is the built-in state machine of DELIVERY orders
+var DefaultTransitions = Transitions{
+	StatusCreated: {
+		StatusVerified,
+		StatusInProgress,
+		StatusCancelled,
+	},
+	StatusVerified: {
+		StatusInProgress,
+		StatusCancelled,
+	},
+	StatusInProgress: {
+		StatusOutForDelivery,
+		StatusDelivered, // Handed over at the counter
+		StatusCancelled,
+	},
+	StatusOutForDelivery: {
+		StatusDelivered,
+		StatusCancelled,
+	},
+	StatusDelivered: {
+		// Terminal state - no transitions
+	},
+	StatusCancelled: {
+		// Terminal state - no transitions
+	},
+}
+
+// DefaultOnSiteTransitions is the built-in state machine of ON_SITE orders, which are served
+// at the venue and never go out for delivery
+var DefaultOnSiteTransitions = Transitions{
+	StatusCreated: {
+		StatusVerified,
+		StatusInProgress,
+		StatusCancelled,
+	},
+	StatusVerified: {
+		StatusInProgress,
+		StatusCancelled,
+	},
+	StatusInProgress: {
+		StatusDelivered,
+		StatusCancelled,
+	},
+	StatusDelivered: {
+		// Terminal state - no transitions
+	},
+	StatusCancelled: {
+		// Terminal state - no transitions
+	},
+}
+
+// DefaultTransitionsFor returns the built-in state machine of a sale type
+func DefaultTransitionsFor(saleType SaleType) Transitions {
+	if saleType == SaleTypeOnSite {
+		return DefaultOnSiteTransitions
+	}
+	return DefaultTransitions
+}
+
+// Allows reports whether an order can move from one status to the other
+func (t Transitions) Allows(from, to OrderStatus) bool {
+	for _, status := range t[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Uses reports whether the status is part of the state machine
+func (t Transitions) Uses(status OrderStatus) bool {
+	_, ok := t[status]
+	return ok
+}
+
+// Statuses returns the statuses of the state machine in lifecycle order
+func (t Transitions) Statuses() []OrderStatus {
+	statuses := make([]OrderStatus, 0, len(t))
+	for _, status := range AllStatuses {
+		if t.Uses(status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// ParseTransitions parses edges in the form "FROM>TO", e.g. "CREATED>IN_PROGRESS".
+// Terminal statuses are always part of the result, so they never need an edge of their own.
+func ParseTransitions(specs []string) (Transitions, error) {
+	t := Transitions{}
+	for _, status := range TerminalStatuses {
+		t[status] = []OrderStatus{}
+	}
+	if err := t.addEdges(specs); err != nil {
+		return nil, err
+	}
+	return t, t.Validate()
+}
+
+// Extend returns a copy of the state machine with the extra "FROM>TO" edges added
+func (t Transitions) Extend(specs []string) (Transitions, error) {
+	extended := make(Transitions, len(t))
+	for status, targets := range t {
+		extended[status] = slices.Clone(targets)
+	}
+	if err := extended.addEdges(specs); err != nil {
+		return nil, err
+	}
+	return extended, extended.Validate()
+}
+
+// addEdges adds "FROM>TO" edges, skipping those already present
+func (t Transitions) addEdges(specs []string) error {
+	for _, spec := range specs {
+		from, to, ok := strings.Cut(strings.TrimSpace(spec), ">")
+		if !ok {
+			return fmt.Errorf("transition %q: expected FROM>TO", spec)
+		}
+		fromStatus := OrderStatus(strings.ToUpper(strings.TrimSpace(from)))
+		toStatus := OrderStatus(strings.ToUpper(strings.TrimSpace(to)))
+		probe := Order{}
+		if !probe.IsValidStatus(fromStatus) || !probe.IsValidStatus(toStatus) {
+			return fmt.Errorf("transition %q: %w", spec, ErrInvalidStatus)
+		}
+		if !t.Allows(fromStatus, toStatus) {
+			t[fromStatus] = append(t[fromStatus], toStatus)
+		}
+		if !t.Uses(toStatus) {
+			t[toStatus] = []OrderStatus{}
+		}
+	}
+	return nil
+}
+
+// Validate checks the state machine is usable: orders start at CREATED, terminal statuses
+// have no way out, every status is reachable from CREATED and every status can reach a terminal one
+func (t Transitions) Validate() error {
+	if len(t[StatusCreated]) == 0 {
+		return fmt.Errorf("state machine: %s must have at least one transition", StatusCreated)
+	}
+	for _, status := range TerminalStatuses {
+		if len(t[status]) > 0 {
+			return fmt.Errorf("state machine: terminal status %s cannot transition", status)
+		}
+	}
+	for from, targets := range t {
+		for _, to := range targets {
+			if to == from || to == StatusCreated {
+				return fmt.Errorf("state machine: invalid transition %s>%s", from, to)
+			}
+		}
+	}
+
+	reachable := t.reachableFrom(StatusCreated)
+	for _, status := range t.Statuses() {
+		if !reachable[status] && !isTerminal(status) {
+			return fmt.Errorf("state machine: status %s is unreachable from %s", status, StatusCreated)
+		}
+		if !isTerminal(status) && !t.reachesTerminal(status) {
+			return fmt.Errorf("state machine: status %s cannot reach a terminal status", status)
+		}
+	}
+	return nil
+}
+
+// reachableFrom returns the statuses reachable from start (including start)
+func (t Transitions) reachableFrom(start OrderStatus) map[OrderStatus]bool {
+	seen := map[OrderStatus]bool{start: true}
+	queue := []OrderStatus{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range t[current] {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+// reachesTerminal reports whether a terminal status can be reached from status
+func (t Transitions) reachesTerminal(status OrderStatus) bool {
+	for reached := range t.reachableFrom(status) {
+		if isTerminal(reached) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTerminal reports whether the status is terminal
+func isTerminal(status OrderStatus) bool {
+	for _, s := range TerminalStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// StateMachine holds the transitions of each sale type
+type StateMachine map[SaleType]Transitions
+
+// For returns the transitions of a sale type, falling back to its built-in state machine
+func (m StateMachine) For(saleType SaleType) Transitions {
+	if t, ok := m[saleType]; ok {
+		return t
+	}
+	return DefaultTransitionsFor(saleType)
+}
+
+// ParseStateMachine parses the configured transitions of each sale type. Overrides replace the
+// built-in state machine of the sale type; extra edges are then added to the resulting one.
+// Sale types without overrides or extras keep their built-in state machine.
+func ParseStateMachine(overrides, extras map[SaleType][]string) (StateMachine, error) {
+	m := StateMachine{}
+	for _, saleType := range AllSaleTypes {
+		t := DefaultTransitionsFor(saleType)
+		if len(overrides[saleType]) > 0 {
+			var err error
+			if t, err = ParseTransitions(overrides[saleType]); err != nil {
+				return nil, fmt.Errorf("%s transitions: %w", saleType, err)
+			}
+			m[saleType] = t
+		}
+		if len(extras[saleType]) > 0 {
+			extended, err := t.Extend(extras[saleType])
+			if err != nil {
+				return nil, fmt.Errorf("%s extra transitions: %w", saleType, err)
+			}
+			m[saleType] = extended
+		}
+	}
+	return m, nil
+}
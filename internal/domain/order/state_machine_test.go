@@ -0,0 +1,266 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Venue workflows used across the state machine tests
+var (
+	// skipVerified never uses VERIFIED
+	skipVerified = []string{"CREATED>IN_PROGRESS", "CREATED>CANCELLED", "IN_PROGRESS>DELIVERED", "IN_PROGRESS>CANCELLED"}
+	// mandatoryDispatch requires OUT_FOR_DELIVERY before DELIVERED
+	mandatoryDispatch = []string{
+		"CREATED>IN_PROGRESS", "CREATED>CANCELLED",
+		"IN_PROGRESS>OUT_FOR_DELIVERY", "IN_PROGRESS>CANCELLED",
+		"OUT_FOR_DELIVERY>DELIVERED", "OUT_FOR_DELIVERY>CANCELLED",
+	}
+)
+
+func TestUpdateStatus(t *testing.T) {
+	custom, err := ParseTransitions(skipVerified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphs := []struct {
+		name        string
+		transitions Transitions
+		wantEdges   int
+	}{
+		{"delivery", DefaultTransitionsFor(SaleTypeDelivery), 10},
+		{"on site", DefaultTransitionsFor(SaleTypeOnSite), 7},
+		{"custom", custom, 4},
+	}
+
+	// Every pair of statuses is either an edge of the graph or rejected naming the pair
+	for _, g := range graphs {
+		edges := 0
+		for _, from := range g.transitions.Statuses() {
+			for _, to := range AllStatuses {
+				t.Run(g.name+"/"+string(from)+" to "+string(to), func(t *testing.T) {
+					o := &Order{Status: from, CreatedAt: time.Now()}
+					err := o.UpdateStatus(to, g.transitions)
+
+					if slices.Contains(g.transitions[from], to) {
+						edges++
+						if err != nil {
+							t.Fatalf("err = %v, want the move allowed", err)
+						}
+						if o.Status != to || len(o.StatusHistory) != 1 || o.StatusHistory[0].From != from {
+							t.Errorf("status = %s, history %+v, want %s recorded", o.Status, o.StatusHistory, to)
+						}
+						return
+					}
+					if !errors.Is(err, ErrInvalidStatusTransition) {
+						t.Fatalf("err = %v, want %v", err, ErrInvalidStatusTransition)
+					}
+					if pair := string(from) + " to " + string(to); !strings.Contains(err.Error(), pair) {
+						t.Errorf("err = %q, want it to name %q", err, pair)
+					}
+					if o.Status != from || len(o.StatusHistory) != 0 {
+						t.Errorf("status = %s after a rejected move, want %s", o.Status, from)
+					}
+				})
+			}
+		}
+		if edges != g.wantEdges {
+			t.Errorf("%s allowed %d moves, want %d", g.name, edges, g.wantEdges)
+		}
+	}
+
+	t.Run("on site never goes out for delivery", func(t *testing.T) {
+		if DefaultOnSiteTransitions.Uses(StatusOutForDelivery) {
+			t.Error("OUT_FOR_DELIVERY is part of the ON_SITE state machine")
+		}
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		o := &Order{Status: StatusCreated}
+		if err := o.UpdateStatus("COOKING", DefaultTransitions); !errors.Is(err, ErrInvalidStatus) {
+			t.Errorf("err = %v, want %v", err, ErrInvalidStatus)
+		}
+	})
+}
+
+func TestParseTransitions(t *testing.T) {
+	tests := []struct {
+		name         string
+		specs        []string
+		wantErr      string // Substring of the expected error
+		wantStatuses []OrderStatus
+	}{
+		{
+			name:         "skip verified",
+			specs:        skipVerified,
+			wantStatuses: []OrderStatus{StatusCreated, StatusInProgress, StatusDelivered, StatusCancelled},
+		},
+		{
+			name:         "mandatory dispatch",
+			specs:        mandatoryDispatch,
+			wantStatuses: []OrderStatus{StatusCreated, StatusInProgress, StatusOutForDelivery, StatusDelivered, StatusCancelled},
+		},
+		{
+			name:         "case and spaces",
+			specs:        []string{" created > delivered "},
+			wantStatuses: []OrderStatus{StatusCreated, StatusDelivered, StatusCancelled},
+		},
+		{"missing separator", []string{"CREATED-IN_PROGRESS"}, "expected FROM>TO", nil},
+		{"unknown status", []string{"CREATED>COOKING"}, ErrInvalidStatus.Error(), nil},
+		{"no way out of created", []string{}, "CREATED must have at least one transition", nil},
+		{"terminal status transitions", []string{"CREATED>DELIVERED", "DELIVERED>CANCELLED"}, "terminal status DELIVERED cannot transition", nil},
+		{"back to created", []string{"CREATED>IN_PROGRESS", "IN_PROGRESS>CREATED"}, "invalid transition IN_PROGRESS>CREATED", nil},
+		{"self loop", []string{"CREATED>IN_PROGRESS", "IN_PROGRESS>IN_PROGRESS", "IN_PROGRESS>DELIVERED"}, "invalid transition IN_PROGRESS>IN_PROGRESS", nil},
+		{"unreachable status", []string{"CREATED>DELIVERED", "VERIFIED>DELIVERED"}, "status VERIFIED is unreachable", nil},
+		{"dead end", []string{"CREATED>IN_PROGRESS", "IN_PROGRESS>VERIFIED", "VERIFIED>IN_PROGRESS", "CREATED>CANCELLED"}, "cannot reach a terminal status", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTransitions(tt.specs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if statuses := got.Statuses(); !slices.Equal(statuses, tt.wantStatuses) {
+				t.Errorf("statuses = %v, want %v", statuses, tt.wantStatuses)
+			}
+		})
+	}
+}
+
+func TestTransitionsExtend(t *testing.T) {
+	extended, err := DefaultOnSiteTransitions.Extend([]string{"VERIFIED>DELIVERED"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !extended.Allows(StatusVerified, StatusDelivered) {
+		t.Error("extra edge VERIFIED>DELIVERED missing")
+	}
+	if DefaultOnSiteTransitions.Allows(StatusVerified, StatusDelivered) {
+		t.Error("Extend modified the built-in state machine")
+	}
+
+	if _, err := DefaultTransitions.Extend([]string{"CANCELLED>CREATED"}); err == nil {
+		t.Error("extending a terminal status was accepted")
+	}
+}
+
+func TestParseStateMachine(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[SaleType][]string
+		extras    map[SaleType][]string
+		saleType  SaleType
+		from, to  OrderStatus
+		want      bool
+		wantErr   bool
+	}{
+		{name: "defaults", saleType: SaleTypeDelivery, from: StatusInProgress, to: StatusDelivered, want: true},
+		{name: "on site never goes out for delivery", saleType: SaleTypeOnSite, from: StatusInProgress, to: StatusOutForDelivery, want: false},
+		{
+			name:      "override makes dispatch mandatory",
+			overrides: map[SaleType][]string{SaleTypeDelivery: mandatoryDispatch},
+			saleType:  SaleTypeDelivery, from: StatusInProgress, to: StatusDelivered, want: false,
+		},
+		{
+			name:      "override leaves the other sale type alone",
+			overrides: map[SaleType][]string{SaleTypeDelivery: mandatoryDispatch},
+			saleType:  SaleTypeOnSite, from: StatusCreated, to: StatusVerified, want: true,
+		},
+		{
+			name:      "override drops verified",
+			overrides: map[SaleType][]string{SaleTypeOnSite: skipVerified},
+			saleType:  SaleTypeOnSite, from: StatusCreated, to: StatusVerified, want: false,
+		},
+		{
+			name:     "extra edge on the default",
+			extras:   map[SaleType][]string{SaleTypeOnSite: {"CREATED>DELIVERED"}},
+			saleType: SaleTypeOnSite, from: StatusCreated, to: StatusDelivered, want: true,
+		},
+		{
+			name:      "extra edge on an override",
+			overrides: map[SaleType][]string{SaleTypeOnSite: skipVerified},
+			extras:    map[SaleType][]string{SaleTypeOnSite: {"CREATED>DELIVERED"}},
+			saleType:  SaleTypeOnSite, from: StatusCreated, to: StatusDelivered, want: true,
+		},
+		{name: "invalid override", overrides: map[SaleType][]string{SaleTypeDelivery: {"DELIVERED>CREATED"}}, wantErr: true},
+		{name: "invalid extra", extras: map[SaleType][]string{SaleTypeOnSite: {"CANCELLED>DELIVERED"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine, err := ParseStateMachine(tt.overrides, tt.extras)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := machine.For(tt.saleType).Allows(tt.from, tt.to); got != tt.want {
+				t.Errorf("%s allows %s>%s = %v, want %v", tt.saleType, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartialUpdateEnforcesTheStateMachine(t *testing.T) {
+	dispatch, err := ParseStateMachine(map[SaleType][]string{SaleTypeDelivery: mandatoryDispatch}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noVerify, err := ParseStateMachine(map[SaleType][]string{SaleTypeOnSite: skipVerified}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		machine  StateMachine
+		saleType SaleType
+		from, to OrderStatus
+		wantErr  error
+	}{
+		{"default delivers from the counter", nil, SaleTypeDelivery, StatusInProgress, StatusDelivered, nil},
+		{"dispatch required", dispatch, SaleTypeDelivery, StatusInProgress, StatusDelivered, ErrInvalidStatusTransition},
+		{"dispatch allowed", dispatch, SaleTypeDelivery, StatusInProgress, StatusOutForDelivery, nil},
+		{"default on site verifies", nil, SaleTypeOnSite, StatusCreated, StatusVerified, nil},
+		{"verified not used", noVerify, SaleTypeOnSite, StatusCreated, StatusVerified, ErrInvalidStatusTransition},
+		{"straight to the kitchen", noVerify, SaleTypeOnSite, StatusCreated, StatusInProgress, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			stored.SaleType = tt.saleType
+			stored.Status = tt.from
+			if tt.saleType == SaleTypeDelivery {
+				address := "Calle 1 # 2-3"
+				stored.TableNumber = nil
+				stored.ShippingAddress = &address
+			}
+			repo := newMemoryRepository(stored)
+			svc := NewService(repo, WithStateMachine(tt.machine))
+
+			_, err := svc.PartialUpdate(context.Background(), stored.Code, PartialUpdateInput{Status: &tt.to, OverrideFieldPolicy: true})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.to
+			if tt.wantErr != nil {
+				want = tt.from
+			}
+			if got := repo.stored(stored.ID).Status; got != want {
+				t.Errorf("stored status = %s, want %s", got, want)
+			}
+		})
+	}
+}
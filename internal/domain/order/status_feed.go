@@ -0,0 +1,153 @@
+package order
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultMaxStatusStreamsPerCode bounds the open tracking streams of a single order
+const DefaultMaxStatusStreamsPerCode = 5
+
+// StatusEvent is a status change pushed to tracking subscribers
+type StatusEvent struct {
+	Code      string
+	Status    OrderStatus
+	Previous  OrderStatus // Empty for the initial status sent on connect
+	UpdatedAt time.Time
+}
+
+// IsTerminal reports whether the order can no longer change status after this event
+func (e StatusEvent) IsTerminal() bool {
+	for _, s := range TerminalStatuses {
+		if e.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusFeed fans out order status changes to the subscribers of each order code.
+// It only sees the changes made by this process: instances behind a load balancer do not share events.
+type StatusFeed struct {
+	mu         sync.Mutex
+	subs       map[string]map[*StatusSubscription]struct{}
+	maxPerCode int
+	closed     bool
+}
+
+// NewStatusFeed creates a feed allowing at most maxPerCode subscriptions per order code
+// (DefaultMaxStatusStreamsPerCode when <= 0)
+func NewStatusFeed(maxPerCode int) *StatusFeed {
+	if maxPerCode <= 0 {
+		maxPerCode = DefaultMaxStatusStreamsPerCode
+	}
+	return &StatusFeed{
+		subs:       make(map[string]map[*StatusSubscription]struct{}),
+		maxPerCode: maxPerCode,
+	}
+}
+
+// StatusSubscription receives the status changes of one order until it is closed
+type StatusSubscription struct {
+	feed   *StatusFeed
+	code   string
+	events chan StatusEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Events delivers status changes. Only the latest pending change is kept for slow readers.
+func (s *StatusSubscription) Events() <-chan StatusEvent {
+	return s.events
+}
+
+// Done is closed when the subscription or the whole feed is closed
+func (s *StatusSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close unsubscribes; it is safe to call more than once
+func (s *StatusSubscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.feed.remove(s)
+}
+
+// Subscribe starts receiving the status changes of the order with the given code
+func (f *StatusFeed) Subscribe(code string) (*StatusSubscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, ErrStatusFeedClosed
+	}
+	subs := f.subs[code]
+	if len(subs) >= f.maxPerCode {
+		return nil, ErrTooManyStatusStreams
+	}
+	if subs == nil {
+		subs = make(map[*StatusSubscription]struct{})
+		f.subs[code] = subs
+	}
+
+	sub := &StatusSubscription{
+		feed:   f,
+		code:   code,
+		events: make(chan StatusEvent, 1),
+		done:   make(chan struct{}),
+	}
+	subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Close ends every subscription and rejects new ones, e.g. on shutdown
+func (f *StatusFeed) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	for _, subs := range f.subs {
+		for sub := range subs {
+			f.remove(sub)
+		}
+	}
+}
+
+// OrderStatusChanged publishes a persisted status change to the order's subscribers
+func (f *StatusFeed) OrderStatusChanged(o *Order, previous OrderStatus) {
+	event := StatusEvent{Code: o.Code, Status: o.Status, Previous: previous, UpdatedAt: o.UpdatedAt}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subs[o.Code] {
+		sub.publish(event)
+	}
+}
+
+// publish delivers the event without blocking, replacing a pending one the reader has not taken yet
+func (s *StatusSubscription) publish(event StatusEvent) {
+	select {
+	case s.events <- event:
+		return
+	default:
+	}
+	select {
+	case <-s.events:
+	default:
+	}
+	select {
+	case s.events <- event:
+	default:
+	}
+}
+
+// remove drops a subscription and closes its done channel; f.mu must be held
+func (f *StatusFeed) remove(sub *StatusSubscription) {
+	sub.once.Do(func() { close(sub.done) })
+
+	subs := f.subs[sub.code]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(f.subs, sub.code)
+	}
+}
@@ -0,0 +1,195 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// nextEvent waits briefly for an event; ok is false when none arrives
+func nextEvent(sub *StatusSubscription) (StatusEvent, bool) {
+	select {
+	case e := <-sub.Events():
+		return e, true
+	case <-time.After(100 * time.Millisecond):
+		return StatusEvent{}, false
+	}
+}
+
+func TestStatusFeedSubscriptionCap(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxPerCode int
+		wantMax    int
+	}{
+		{"default cap", 0, DefaultMaxStatusStreamsPerCode},
+		{"configured cap", 2, 2},
+		{"single stream", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := NewStatusFeed(tt.maxPerCode)
+			var subs []*StatusSubscription
+			for i := range tt.wantMax {
+				sub, err := feed.Subscribe("ORD-7F3K2Q")
+				if err != nil {
+					t.Fatalf("subscription %d: %v", i+1, err)
+				}
+				subs = append(subs, sub)
+			}
+			if _, err := feed.Subscribe("ORD-7F3K2Q"); !errors.Is(err, ErrTooManyStatusStreams) {
+				t.Errorf("subscription over the cap: err = %v, want %v", err, ErrTooManyStatusStreams)
+			}
+			if _, err := feed.Subscribe("ORD-OTHER2"); err != nil {
+				t.Errorf("another order is capped separately: %v", err)
+			}
+
+			// Closing a stream frees its slot, even when closed twice
+			subs[0].Close()
+			subs[0].Close()
+			if _, err := feed.Subscribe("ORD-7F3K2Q"); err != nil {
+				t.Errorf("slot not freed by Close: %v", err)
+			}
+			if _, err := feed.Subscribe("ORD-7F3K2Q"); !errors.Is(err, ErrTooManyStatusStreams) {
+				t.Errorf("double Close freed two slots: err = %v", err)
+			}
+		})
+	}
+}
+
+func TestStatusFeedDelivery(t *testing.T) {
+	feed := NewStatusFeed(0)
+	tracked, err := feed.Subscribe("ORD-7F3K2Q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := feed.Subscribe("ORD-OTHER2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := &Order{Code: "ORD-7F3K2Q", Status: StatusVerified}
+	feed.OrderStatusChanged(o, StatusCreated)
+	if e, ok := nextEvent(tracked); !ok || e.Status != StatusVerified || e.Previous != StatusCreated || e.Code != o.Code {
+		t.Errorf("event = %+v, %v", e, ok)
+	}
+	if e, ok := nextEvent(other); ok {
+		t.Errorf("other order received %+v", e)
+	}
+
+	// A slow reader only gets the latest pending change, and publishing never blocks
+	for _, status := range []OrderStatus{StatusInProgress, StatusOutForDelivery, StatusDelivered} {
+		o.Status = status
+		feed.OrderStatusChanged(o, StatusVerified)
+	}
+	if e, ok := nextEvent(tracked); !ok || e.Status != StatusDelivered {
+		t.Errorf("pending event = %+v, want the latest (DELIVERED)", e)
+	}
+	if e, ok := nextEvent(tracked); ok {
+		t.Errorf("stale event delivered: %+v", e)
+	}
+}
+
+func TestStatusFeedClose(t *testing.T) {
+	feed := NewStatusFeed(0)
+	sub, err := feed.Subscribe("ORD-7F3K2Q")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed.Close()
+	select {
+	case <-sub.Done():
+	default:
+		t.Error("subscription still open after the feed closed")
+	}
+	sub.Close() // Closing after the feed must not panic
+	if _, err := feed.Subscribe("ORD-7F3K2Q"); !errors.Is(err, ErrStatusFeedClosed) {
+		t.Errorf("err = %v, want %v", err, ErrStatusFeedClosed)
+	}
+}
+
+func TestStatusEventIsTerminal(t *testing.T) {
+	for _, status := range AllStatuses {
+		want := status == StatusDelivered || status == StatusCancelled
+		if got := (StatusEvent{Status: status}).IsTerminal(); got != want {
+			t.Errorf("%s: IsTerminal = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestServicePublishesStatusChanges(t *testing.T) {
+	ctx := context.Background()
+	note := "ring twice"
+	verified := StatusVerified
+
+	tests := []struct {
+		name       string
+		update     func(svc *Service, code string) error
+		wantStatus OrderStatus // Empty when nothing is published
+	}{
+		{"status change", func(svc *Service, code string) error {
+			_, err := svc.PartialUpdate(ctx, code, PartialUpdateInput{Status: &verified})
+			return err
+		}, StatusVerified},
+		{"note only", func(svc *Service, code string) error {
+			_, err := svc.PartialUpdate(ctx, code, PartialUpdateInput{Note: &note})
+			return err
+		}, ""},
+		{"modify verifies", func(svc *Service, code string) error {
+			_, err := svc.Modify(ctx, code, ModifyInput{Products: lines(1, 2, 1000)})
+			return err
+		}, StatusVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			feed := NewStatusFeed(0)
+			svc := NewService(newMemoryRepository(stored), WithStatusFeed(feed))
+
+			// Codes typed in any case subscribe to the stored code
+			sub, err := svc.SubscribeStatus("ord-000001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.update(svc, stored.Code); err != nil {
+				t.Fatal(err)
+			}
+
+			e, ok := nextEvent(sub)
+			if tt.wantStatus == "" {
+				if ok {
+					t.Errorf("published %+v without a status change", e)
+				}
+				return
+			}
+			if !ok || e.Status != tt.wantStatus || e.Previous != StatusCreated {
+				t.Errorf("event = %+v, %v, want %s from %s", e, ok, tt.wantStatus, StatusCreated)
+			}
+		})
+	}
+}
+
+func TestSubscribeStatusErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		code    string
+		wantErr error
+	}{
+		{"streams disabled", nil, "ORD-000001", ErrStatusStreamsDisabled},
+		{"blank code", []Option{WithStatusFeed(NewStatusFeed(0))}, "  ", ErrInvalidOrderCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(newMemoryRepository(), tt.opts...)
+			if _, err := svc.SubscribeStatus(tt.code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package order
+
+import (
+	"strings"
+	"time"
+)
+
+// StatusTime returns when the order first reached the status, or nil if it never did.
+// CREATED is the creation time; orders stored before status timestamps existed have none.
+func (o *Order) StatusTime(status OrderStatus) *time.Time {
+	switch status {
+	case StatusCreated:
+		if o.CreatedAt.IsZero() {
+			return nil
+		}
+		return &o.CreatedAt
+	case StatusVerified:
+		return o.VerifiedAt
+	case StatusInProgress:
+		return o.InProgressAt
+	case StatusOutForDelivery:
+		return o.OutForDeliveryAt
+	case StatusDelivered:
+		return o.DeliveredAt
+	case StatusCancelled:
+		return o.CancelledAt
+	}
+	return nil
+}
+
+// recordStatusTime stores when the order first reached the status. Later visits (e.g. back
+// to VERIFIED after a modification) keep the first time, so stage durations never overlap.
+func (o *Order) recordStatusTime(status OrderStatus, at time.Time) {
+	var field **time.Time
+	switch status {
+	case StatusVerified:
+		field = &o.VerifiedAt
+	case StatusInProgress:
+		field = &o.InProgressAt
+	case StatusOutForDelivery:
+		field = &o.OutForDeliveryAt
+	case StatusDelivered:
+		field = &o.DeliveredAt
+	case StatusCancelled:
+		field = &o.CancelledAt
+	default:
+		return
+	}
+	if *field == nil {
+		*field = &at
+	}
+}
+
+// Stage is the step between two consecutive statuses of the order lifecycle
+type Stage struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+// Key returns the stage name used in metrics, e.g. "created_to_verified"
+func (s Stage) Key() string {
+	return strings.ToLower(string(s.From)) + "_to_" + strings.ToLower(string(s.To))
+}
+
+// Stages lists the consecutive stages of the lifecycle measured by the SLA metrics.
+// ON_SITE orders skip OUT_FOR_DELIVERY, so they have no duration for the last two stages.
+var Stages = []Stage{
+	{From: StatusCreated, To: StatusVerified},
+	{From: StatusVerified, To: StatusInProgress},
+	{From: StatusInProgress, To: StatusOutForDelivery},
+	{From: StatusOutForDelivery, To: StatusDelivered},
+}
+
+// StageDuration is the average time the filtered orders spent in a stage.
+// Orders that skipped either status of the stage are not counted.
+type StageDuration struct {
+	Stage
+	OrderCount int64 // Orders with both timestamps
+	AvgSeconds int64 // 0 when OrderCount is 0
+}
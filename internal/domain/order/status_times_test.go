@@ -0,0 +1,102 @@
+package order
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordStatusTimes(t *testing.T) {
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return created.Add(time.Duration(minutes) * time.Minute) }
+
+	type step struct {
+		status  OrderStatus
+		minutes int
+	}
+	tests := []struct {
+		name  string
+		steps []step
+		want  map[OrderStatus]int // Minutes after creation; statuses missing here have no time
+	}{
+		{
+			name:  "delivery lifecycle",
+			steps: []step{{StatusVerified, 2}, {StatusInProgress, 5}, {StatusOutForDelivery, 20}, {StatusDelivered, 45}},
+			want:  map[OrderStatus]int{StatusCreated: 0, StatusVerified: 2, StatusInProgress: 5, StatusOutForDelivery: 20, StatusDelivered: 45},
+		},
+		{
+			name:  "on site skips out for delivery",
+			steps: []step{{StatusVerified, 1}, {StatusInProgress, 3}, {StatusDelivered, 15}},
+			want:  map[OrderStatus]int{StatusCreated: 0, StatusVerified: 1, StatusInProgress: 3, StatusDelivered: 15},
+		},
+		{
+			name:  "cancelled",
+			steps: []step{{StatusVerified, 1}, {StatusCancelled, 4}},
+			want:  map[OrderStatus]int{StatusCreated: 0, StatusVerified: 1, StatusCancelled: 4},
+		},
+		{
+			name:  "back to verified keeps the first time",
+			steps: []step{{StatusVerified, 1}, {StatusInProgress, 3}, {StatusVerified, 8}, {StatusInProgress, 10}},
+			want:  map[OrderStatus]int{StatusCreated: 0, StatusVerified: 1, StatusInProgress: 3},
+		},
+		{
+			name: "new order",
+			want: map[OrderStatus]int{StatusCreated: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Order{Status: StatusCreated, CreatedAt: created}
+			for _, s := range tt.steps {
+				o.changeStatus(s.status, ActorUser, at(s.minutes))
+			}
+
+			for _, status := range AllStatuses {
+				got := o.StatusTime(status)
+				minutes, ok := tt.want[status]
+				switch {
+				case !ok && got != nil:
+					t.Errorf("%s time = %v, want none", status, got)
+				case ok && (got == nil || !got.Equal(at(minutes))):
+					t.Errorf("%s time = %v, want %v", status, got, at(minutes))
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateStatusRecordsTime(t *testing.T) {
+	o := storedOrder(1, 23333, 0, 23333, nil)
+	before := time.Now()
+
+	if err := o.UpdateStatus(StatusVerified, DefaultTransitionsFor(o.SaleType)); err != nil {
+		t.Fatal(err)
+	}
+	if got := o.StatusTime(StatusVerified); got == nil || got.Before(before) || !got.Equal(o.UpdatedAt) {
+		t.Errorf("verified at %v, want the update time %v", got, o.UpdatedAt)
+	}
+	if o.StatusTime(StatusInProgress) != nil {
+		t.Error("a status the order never reached has a time")
+	}
+}
+
+func TestStatusTimeOfOrdersStoredWithoutTimes(t *testing.T) {
+	o := &Order{Status: StatusDelivered}
+	for _, status := range AllStatuses {
+		if got := o.StatusTime(status); got != nil {
+			t.Errorf("%s time = %v, want none", status, got)
+		}
+	}
+}
+
+func TestStageKey(t *testing.T) {
+	want := []string{"created_to_verified", "verified_to_in_progress", "in_progress_to_out_for_delivery", "out_for_delivery_to_delivered"}
+	if len(Stages) != len(want) {
+		t.Fatalf("got %d stages, want %d", len(Stages), len(want))
+	}
+	for i, stage := range Stages {
+		if stage.Key() != want[i] {
+			t.Errorf("stage %d key = %q, want %q", i, stage.Key(), want[i])
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package order
+
+import (
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// DefaultTenant is the company and sale point of orders stored before orders were scoped by tenant.
+// Filtering by it also matches those orders.
+const DefaultTenant = "default"
+
+// TenantCompanyID returns the company of the order, DefaultTenant for orders stored without one
+func (o *Order) TenantCompanyID() string {
+	if o.CompanyID == "" {
+		return DefaultTenant
+	}
+	return o.CompanyID
+}
+
+// TenantSalePointID returns the sale point of the order, DefaultTenant for orders stored without one
+func (o *Order) TenantSalePointID() string {
+	if o.SalePointID == "" {
+		return DefaultTenant
+	}
+	return o.SalePointID
+}
+
+// validateTenant checks that a new order belongs to a company and a sale point.
+// Stored orders are not checked, so orders from before tenants existed can still be modified.
+func validateTenant(companyID, salePointID string) error {
+	if companyID == "" {
+		return apperrors.NewDomainError(ErrCompanyIDRequired, "company_id", nil)
+	}
+	if salePointID == "" {
+		return apperrors.NewDomainError(ErrSalePointIDRequired, "sale_point_id", nil)
+	}
+	return nil
+}
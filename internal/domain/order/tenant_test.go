@@ -0,0 +1,109 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+func TestTenantDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		order         Order
+		wantCompany   string
+		wantSalePoint string
+	}{
+		{"scoped order", Order{CompanyID: "c1", SalePointID: "s1"}, "c1", "s1"},
+		{"stored before tenants", Order{}, DefaultTenant, DefaultTenant},
+		{"company only", Order{CompanyID: "c1"}, "c1", DefaultTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.order.TenantCompanyID(); got != tt.wantCompany {
+				t.Errorf("TenantCompanyID = %q, want %q", got, tt.wantCompany)
+			}
+			if got := tt.order.TenantSalePointID(); got != tt.wantSalePoint {
+				t.Errorf("TenantSalePointID = %q, want %q", got, tt.wantSalePoint)
+			}
+		})
+	}
+}
+
+func TestCreateRequiresTenant(t *testing.T) {
+	tests := []struct {
+		name      string
+		change    func(*CreateInput)
+		wantErr   error
+		wantField string
+	}{
+		{"scoped", func(in *CreateInput) {}, nil, ""},
+		{"no company", func(in *CreateInput) { in.CompanyID = "" }, ErrCompanyIDRequired, "company_id"},
+		{"no sale point", func(in *CreateInput) { in.SalePointID = "" }, ErrSalePointIDRequired, "sale_point_id"},
+		{"neither", func(in *CreateInput) { in.CompanyID, in.SalePointID = "", "" }, ErrCompanyIDRequired, "company_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			input := onSiteInput(lines(1, 1, 5000))
+			tt.change(&input)
+
+			o, err := NewService(repo).Create(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				var domainErr *apperrors.DomainError
+				if !errors.As(err, &domainErr) || domainErr.Field != tt.wantField {
+					t.Errorf("err = %v, want a domain error on %s", err, tt.wantField)
+				}
+				if len(repo.orders) != 0 {
+					t.Errorf("a refused order was stored")
+				}
+				return
+			}
+			stored := repo.stored(o.ID)
+			if stored == nil || stored.CompanyID != input.CompanyID || stored.SalePointID != input.SalePointID {
+				t.Errorf("stored = %+v, want company %s and sale point %s", stored, input.CompanyID, input.SalePointID)
+			}
+		})
+	}
+}
+
+func TestDuplicateKeepsTenant(t *testing.T) {
+	catalog := catalogStub{
+		"p1": {ID: "p1", Name: "Burger", Available: true, Prices: []int64{10000}},
+		"p2": {ID: "p2", Name: "Soda", Available: true, Prices: []int64{3333}},
+	}
+
+	tests := []struct {
+		name          string
+		companyID     string
+		salePointID   string
+		wantCompany   string
+		wantSalePoint string
+	}{
+		{"scoped order", "c1", "s1", "c1", "s1"},
+		{"order stored before tenants", "", "", DefaultTenant, DefaultTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := storedOrder(1, 23333, 0, 23333, nil)
+			source.CompanyID, source.SalePointID = tt.companyID, tt.salePointID
+			repo := newMemoryRepository(source)
+
+			result, err := NewService(repo, WithCatalog(catalog)).Duplicate(context.Background(), source.Code, DuplicateInput{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			o := repo.stored(result.Order.ID)
+			if o.CompanyID != tt.wantCompany || o.SalePointID != tt.wantSalePoint {
+				t.Errorf("duplicate tenant = %s/%s, want %s/%s", o.CompanyID, o.SalePointID, tt.wantCompany, tt.wantSalePoint)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func text(s string) *string { return &s }
+
+func TestOrderSanitizeText(t *testing.T) {
+	tests := []struct {
+		name            string
+		observation     *string
+		description     *string
+		wantObservation *string
+		wantErr         error
+	}{
+		{"nil fields", nil, nil, nil, nil},
+		{"empty fields are kept", text(""), text(""), text(""), nil},
+		{"cleaned", text(" Sin\x00 cebolla\r\n\r\n\r\nBien cocida "), nil, text("Sin cebolla\n\nBien cocida"), nil},
+		{"only whitespace", text(" \n\t "), nil, nil, ErrBlankText},
+		{"only control characters", text("\x1b\x00"), nil, nil, ErrBlankText},
+		{"at the limit", text(strings.Repeat("ñ", MaxTextLength)), nil, text(strings.Repeat("ñ", MaxTextLength)), nil},
+		{"over the limit", text(strings.Repeat("a", MaxTextLength+1)), nil, nil, ErrTextTooLong},
+		{"paste bomb", text(strings.Repeat("x", 10*1024)), nil, nil, ErrTextTooLong},
+		{"blank description", nil, text("\n\n"), nil, ErrBlankText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := lines(1, 1, 100)
+			products[0].Observation = tt.observation
+			products[0].Description = tt.description
+			o := NewOrder(SaleTypeOnSite, products)
+
+			err := o.SanitizeText()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			got := o.Products[0].Observation
+			if (got == nil) != (tt.wantObservation == nil) || got != nil && *got != *tt.wantObservation {
+				t.Errorf("observation = %q, want %q", deref(got), deref(tt.wantObservation))
+			}
+		})
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
+
+// TestCreateAndModifySanitizeText covers both write paths; Modify builds entities directly
+// so the binding limits of the handler never run there
+func TestCreateAndModifySanitizeText(t *testing.T) {
+	tests := []struct {
+		name            string
+		observation     string
+		note            string
+		wantObservation string
+		wantNote        string
+		wantErr         error
+	}{
+		{"cleaned", "Sin\x07 cebolla\n\n\n\nBien cocida", "Mesa\x1b junto a la ventana ", "Sin cebolla\n\nBien cocida", "Mesa junto a la ventana", nil},
+		{"blank observation", "\t\n", "ok", "", "", ErrBlankText},
+		{"blank note", "ok", " \r\n ", "", "", ErrBlankText},
+		{"long observation", strings.Repeat("a", 10*1024), "ok", "", "", ErrTextTooLong},
+		{"long note", "ok", strings.Repeat("a", MaxTextLength+1), "", "", ErrTextTooLong},
+	}
+
+	for _, tt := range tests {
+		withText := func() ([]OrderProduct, *string) {
+			products := lines(1, 1, 100)
+			products[0].Observation = text(tt.observation)
+			return products, text(tt.note)
+		}
+		check := func(t *testing.T, o *Order, err error) {
+			t.Helper()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := deref(o.Products[0].Observation); got != tt.wantObservation {
+				t.Errorf("observation = %q, want %q", got, tt.wantObservation)
+			}
+			notes := o.NoteList()
+			if got := notes[len(notes)-1].Text; got != tt.wantNote {
+				t.Errorf("note = %q, want %q", got, tt.wantNote)
+			}
+		}
+
+		t.Run("create: "+tt.name, func(t *testing.T) {
+			svc := NewService(newMemoryRepository())
+			input := onSiteInput(nil)
+			input.Products, input.Note = withText()
+			o, err := svc.Create(context.Background(), input)
+			check(t, o, err)
+		})
+
+		t.Run("modify: "+tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo)
+			created, err := svc.Create(context.Background(), onSiteInput(lines(1, 1, 100)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var input ModifyInput
+			input.Products, input.Note = withText()
+			result, err := svc.Modify(context.Background(), created.Code, input)
+			if err != nil {
+				check(t, nil, err)
+				if repo.stored(created.ID).Products[0].Observation != nil {
+					t.Error("rejected text was stored")
+				}
+				return
+			}
+			check(t, result.Order, nil)
+		})
+	}
+}
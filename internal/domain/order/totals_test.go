@@ -0,0 +1,127 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// storedOrder builds an order as loaded from the database with the given stored amounts
+func storedOrder(i int, subtotal, discountAmount, total int64, discount *Discount) *Order {
+	table := 1 + i%10
+	return &Order{
+		ID:             fmt.Sprintf("order-%d", i),
+		Code:           fmt.Sprintf("ORD-%06d", i),
+		Status:         StatusCreated,
+		SaleType:       SaleTypeOnSite,
+		Channel:        ChannelPOS,
+		TableNumber:    &table,
+		Products:       []OrderProduct{{ID: "p1", Name: "Burger", Price: 10000, Quantity: 2}, {ID: "p2", Name: "Soda", Price: 3333, Quantity: 1}},
+		Subtotal:       subtotal,
+		Discount:       discount,
+		DiscountAmount: discountAmount,
+		Total:          total,
+		Version:        1,
+		CreatedAt:      time.Date(2024, 1, 1, 0, i, 0, 0, time.UTC),
+	}
+}
+
+func TestRecalculateTotals(t *testing.T) {
+	tenPercent := &Discount{Type: DiscountPercent, Value: 10}
+
+	tests := []struct {
+		name               string
+		order              *Order
+		wantDiscrepancy    bool
+		wantSubtotal       int64
+		wantDiscountAmount int64
+		wantTotal          int64
+	}{
+		{"consistent", storedOrder(1, 23333, 0, 23333, nil), false, 23333, 0, 23333},
+		{"consistent with discount", storedOrder(2, 23333, 2333, 21000, tenPercent), false, 23333, 2333, 21000},
+		{"stale total", storedOrder(3, 23333, 0, 20000, nil), true, 23333, 0, 23333},
+		{"stored before discounts existed", storedOrder(4, 0, 0, 23333, nil), true, 23333, 0, 23333},
+		{"stale subtotal only", storedOrder(5, 20000, 0, 23333, nil), true, 23333, 0, 23333},
+		{"stale discount amount", storedOrder(6, 23333, 0, 21000, tenPercent), true, 23333, 2333, 21000},
+		{"everything stale", storedOrder(7, 1, 1, 1, tenPercent), true, 23333, 2333, 21000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, dryRun := range []bool{true, false} {
+				repo := newMemoryRepository(tt.order)
+				svc := NewService(repo)
+
+				result, err := svc.RecalculateTotals(context.Background(), RecalculateTotalsInput{DryRun: dryRun})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result.Processed != 1 {
+					t.Fatalf("processed = %d, want 1", result.Processed)
+				}
+				if got := len(result.Discrepancies) == 1; got != tt.wantDiscrepancy {
+					t.Fatalf("dry_run=%v: discrepancy reported = %v, want %v", dryRun, got, tt.wantDiscrepancy)
+				}
+				if tt.wantDiscrepancy {
+					d := result.Discrepancies[0]
+					if d.StoredTotal != tt.order.Total || d.ComputedTotal != tt.wantTotal ||
+						d.StoredSubtotal != tt.order.Subtotal || d.ComputedSubtotal != tt.wantSubtotal {
+						t.Errorf("discrepancy = %+v", d)
+					}
+				}
+
+				stored := repo.stored(tt.order.ID)
+				if dryRun || !tt.wantDiscrepancy {
+					if len(repo.fixes) != 0 || stored.Total != tt.order.Total || stored.Subtotal != tt.order.Subtotal {
+						t.Errorf("dry_run=%v: order changed without a fix: %+v", dryRun, repo.fixes)
+					}
+					continue
+				}
+
+				if result.Fixed != 1 {
+					t.Errorf("fixed = %d, want 1", result.Fixed)
+				}
+				if stored.Subtotal != tt.wantSubtotal || stored.DiscountAmount != tt.wantDiscountAmount || stored.Total != tt.wantTotal {
+					t.Errorf("stored subtotal/discount/total = %d/%d/%d, want %d/%d/%d", stored.Subtotal, stored.DiscountAmount, stored.Total,
+						tt.wantSubtotal, tt.wantDiscountAmount, tt.wantTotal)
+				}
+				if stored.Total != stored.Subtotal-stored.DiscountAmount {
+					t.Errorf("fixed order is inconsistent: total %d != subtotal %d - discount %d", stored.Total, stored.Subtotal, stored.DiscountAmount)
+				}
+				if n := len(stored.TotalAdjustments); n != 1 || stored.TotalAdjustments[0].PreviousTotal != tt.order.Total {
+					t.Errorf("total adjustments = %+v", stored.TotalAdjustments)
+				}
+			}
+		})
+	}
+}
+
+func TestRecalculateTotalsBatches(t *testing.T) {
+	var orders []*Order
+	for i := range 5 {
+		orders = append(orders, storedOrder(i, 0, 0, 1, nil))
+	}
+	repo := newMemoryRepository(orders...)
+	svc := NewService(repo)
+
+	var processed int
+	input := RecalculateTotalsInput{BatchSize: 2}
+	for calls := 1; ; calls++ {
+		result, err := svc.RecalculateTotals(context.Background(), input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		processed += result.Processed
+		if result.NextCursor == nil {
+			if calls != 3 {
+				t.Errorf("took %d calls, want 3", calls)
+			}
+			break
+		}
+		input.After = result.NextCursor
+	}
+	if processed != 5 {
+		t.Errorf("processed %d orders, want 5", processed)
+	}
+}
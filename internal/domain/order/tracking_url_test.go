@@ -0,0 +1,30 @@
+package order
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTrackingURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		code     string
+		want     string
+		wantErr  error
+	}{
+		{"path", "https://track.example.com/{code}", "ORD-1700000000-a1b2c3d4", "https://track.example.com/ORD-1700000000-a1b2c3d4", nil},
+		{"query string", "https://example.com/t?code={code}", "ORD-1", "https://example.com/t?code=ORD-1", nil},
+		{"code is escaped", "https://track.example.com/{code}", "ORD 1/2", "https://track.example.com/ORD%201%2F2", nil},
+		{"not configured", "", "ORD-1", "", ErrTrackingURLNotConfigured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(nil, WithTrackingURLTemplate(tt.template)).TrackingURL(tt.code)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("TrackingURL(%q) = %q, %v, want %q, %v", tt.code, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
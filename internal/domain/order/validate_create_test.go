@@ -0,0 +1,48 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidateCreateMatchesCreate(t *testing.T) {
+	overLimit := onSiteInput(lines(1, 11, 100))
+
+	tests := []struct {
+		name    string
+		input   func() CreateInput
+		wantErr error
+	}{
+		{"valid", func() CreateInput { return onSiteInput(lines(3, 1, 100)) }, nil},
+		{"missing table", func() CreateInput { in := onSiteInput(lines(1, 1, 100)); in.TableNumber = nil; return in }, ErrTableNumberRequiredForOnSite},
+		{"above the limits", func() CreateInput { return overLimit }, ErrMaxLineQuantityExceeded},
+		{"limits overridden", func() CreateInput { in := overLimit; in.OverrideLimits = true; return in }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithOrderLimits(OrderLimits{MaxLineQuantity: 10}))
+
+			preview, err := svc.ValidateCreate(context.Background(), tt.input())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateCreate err = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.orders) != 0 {
+				t.Fatal("ValidateCreate stored the order")
+			}
+
+			created, err := svc.Create(context.Background(), tt.input())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if preview.Total != created.Total || preview.Status != created.Status {
+				t.Errorf("preview %d/%s, created %d/%s", preview.Total, preview.Status, created.Total, created.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package order
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// validOrder is an ON_SITE order with n valid lines that passes Validate
+func validOrder(n int) *Order {
+	o := NewOrder(SaleTypeOnSite, lines(n, 1, 1000))
+	table := 4
+	o.TableNumber = &table
+	return o
+}
+
+func TestValidateReportsFieldContext(t *testing.T) {
+	address := "Calle 1 # 2-3"
+	tests := []struct {
+		name      string
+		mutate    func(o *Order)
+		wantErr   error
+		wantField string
+		wantIndex *int
+		wantValue interface{}
+	}{
+		{"valid", func(o *Order) {}, nil, "", nil, nil},
+		{"no products", func(o *Order) { o.Products = nil }, ErrNoProducts, "products", nil, nil},
+		{"4th of 12 lines has no quantity", func(o *Order) { o.Products[3].Quantity = 0 }, ErrInvalidProductQuantity, "products[3].quantity", intPtr(3), 0},
+		{"negative price", func(o *Order) { o.Products[11].Price = -5 }, ErrInvalidProductPrice, "products[11].price", intPtr(11), int64(-5)},
+		{"missing product id", func(o *Order) { o.Products[0].ID = "" }, ErrInvalidProductID, "products[0].id", intPtr(0), ""},
+		{"duplicate names the later line", func(o *Order) { o.Products[7].ID = o.Products[2].ID }, ErrDuplicateProduct, "products[7].id", intPtr(7), "pc"},
+		{"missing table", func(o *Order) { o.TableNumber = nil }, ErrTableNumberRequiredForOnSite, "table_number", nil, nil},
+		{"invalid table", func(o *Order) { zero := 0; o.TableNumber = &zero }, ErrInvalidTableNumber, "table_number", nil, 0},
+		{"address on site", func(o *Order) { o.ShippingAddress = &address }, ErrShippingAddressNotAllowedForOnSite, "shipping_address", nil, nil},
+		{"delivery without customer", func(o *Order) { o.SaleType = SaleTypeDelivery; o.TableNumber = nil }, ErrCustomerRequiredForDelivery, "customer", nil, nil},
+		{"invalid channel", func(o *Order) { o.Channel = "FAX" }, ErrInvalidChannel, "channel", nil, Channel("FAX")},
+		{"invalid status", func(o *Order) { o.Status = "LOST" }, ErrInvalidStatus, "status", nil, OrderStatus("LOST")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := validOrder(12)
+			tt.mutate(o)
+
+			err := o.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.As(err, &domainErr) {
+				t.Fatalf("err = %v, want a DomainError", err)
+			}
+			if domainErr.Field != tt.wantField {
+				t.Errorf("field = %q, want %q", domainErr.Field, tt.wantField)
+			}
+			if (domainErr.Index == nil) != (tt.wantIndex == nil) || domainErr.Index != nil && *domainErr.Index != *tt.wantIndex {
+				t.Errorf("index = %v, want %v", domainErr.Index, tt.wantIndex)
+			}
+			if domainErr.Value != tt.wantValue {
+				t.Errorf("value = %#v, want %#v", domainErr.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int { return &i }
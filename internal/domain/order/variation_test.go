@@ -0,0 +1,60 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+func TestCreateVariationLines(t *testing.T) {
+	text := func(s string) *string { return &s }
+	line := func(variation *string) OrderProduct {
+		return OrderProduct{ID: "p1", Name: "Soda", Price: 3000, Quantity: 1, VariationType: variation}
+	}
+
+	tests := []struct {
+		name       string
+		products   []OrderProduct
+		wantErr    error
+		wantField  string
+		wantStored []*string // Variation of every stored line
+	}{
+		{"one variation", []OrderProduct{line(text("Grande"))}, nil, "", []*string{text("Grande")}},
+		{"no variation", []OrderProduct{line(nil)}, nil, "", []*string{nil}},
+		{"same product in two variations", []OrderProduct{line(text("Grande")), line(text("Pequeña"))}, nil, "", []*string{text("Grande"), text("Pequeña")}},
+		{"with and without a variation", []OrderProduct{line(nil), line(text("Grande"))}, nil, "", []*string{nil, text("Grande")}},
+		{"variation trimmed", []OrderProduct{line(text("  Grande \n"))}, nil, "", []*string{text("Grande")}},
+		{"same variation twice", []OrderProduct{line(text("Grande")), line(text("Grande"))}, ErrDuplicateProduct, "products[1].id", nil},
+		{"same variation after trimming", []OrderProduct{line(text("Grande")), line(text(" Grande"))}, ErrDuplicateProduct, "products[1].id", nil},
+		{"empty variation is no variation", []OrderProduct{line(nil), line(text(""))}, ErrDuplicateProduct, "products[1].id", nil},
+		{"blank variation", []OrderProduct{line(text(" \t "))}, ErrBlankText, "products[0].variation_type", nil},
+		{"variation too long", []OrderProduct{line(text(strings.Repeat("x", MaxTextLength+1)))}, ErrTextTooLong, "products[0].variation_type", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			o, err := NewService(repo).Create(context.Background(), onSiteInput(tt.products))
+			if tt.wantErr != nil {
+				var domainErr *apperrors.DomainError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &domainErr) || domainErr.Field != tt.wantField {
+					t.Fatalf("err = %v, want %v on %s", err, tt.wantErr, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stored := repo.stored(o.ID)
+			for i, want := range tt.wantStored {
+				if got := stored.Products[i].VariationType; textValue(got) != textValue(want) || (got == nil) != (want == nil) {
+					t.Errorf("line %d variation = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package order
+
+import "fmt"
+
+// Orders are updated with optimistic locking: every save increments Version and only succeeds when
+// the stored version is still the one that was loaded, so concurrent edits can't overwrite each other.
+// Orders stored before versioning have no version field and load as version 0.
+
+// checkVersion rejects a change the client based on an outdated copy of the order
+func checkVersion(o *Order, expected *int64) error {
+	if expected != nil && *expected != o.Version {
+		return fmt.Errorf("%w: sent version %d, current version %d", ErrOrderConflict, *expected, o.Version)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// interleavingRepository runs another request to completion between loading and saving an order,
+// as when two staff members edit the same order at once
+type interleavingRepository struct {
+	*memoryRepository
+
+	other func() // Runs before the first save only; its own save goes straight through
+}
+
+func (r *interleavingRepository) Update(ctx context.Context, o *Order) error {
+	if other := r.other; other != nil {
+		r.other = nil
+		other()
+	}
+	return r.memoryRepository.Update(ctx, o)
+}
+
+func TestCheckVersion(t *testing.T) {
+	version := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name     string
+		stored   int64
+		expected *int64
+		wantErr  error
+	}{
+		{"no version sent", 3, nil, nil},
+		{"current version", 3, version(3), nil},
+		{"outdated version", 3, version(2), ErrOrderConflict},
+		{"version from the future", 3, version(4), ErrOrderConflict},
+		{"order stored before versioning", 0, version(0), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkVersion(&Order{Version: tt.stored}, tt.expected); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateWithExpectedVersion(t *testing.T) {
+	verified := StatusVerified
+	version := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name    string
+		update  func(svc *Service, code string, expected *int64) error
+		sent    *int64
+		wantErr error
+	}{
+		{"patch without a version", patchStatus(verified), nil, nil},
+		{"patch at the current version", patchStatus(verified), version(1), nil},
+		{"patch at an outdated version", patchStatus(verified), version(0), ErrOrderConflict},
+		{"put at the current version", putProducts(3), version(1), nil},
+		{"put at an outdated version", putProducts(3), version(0), ErrOrderConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			repo := newMemoryRepository(stored)
+
+			err := tt.update(NewService(repo), stored.Code, tt.sent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			wantVersion := int64(2)
+			if tt.wantErr != nil {
+				wantVersion = 1
+			}
+			if got := repo.stored(stored.ID); got.Version != wantVersion {
+				t.Errorf("stored version = %d, want %d", got.Version, wantVersion)
+			}
+		})
+	}
+}
+
+func TestInterleavedUpdatesConflict(t *testing.T) {
+	verified, cancelled := StatusVerified, StatusCancelled
+
+	tests := []struct {
+		name        string
+		loser       func(svc *Service, code string, expected *int64) error
+		winner      func(svc *Service, code string, expected *int64) error
+		wantStatus  OrderStatus
+		wantBurgers int
+	}{
+		{"put loses to a status patch", putProducts(5), patchStatus(verified), StatusVerified, 2},
+		{"status patch loses to a put", patchStatus(cancelled), putProducts(5), StatusVerified, 5}, // Changing lines verifies the order
+		{"status patch loses to another", patchStatus(verified), patchStatus(cancelled), StatusCancelled, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storedOrder(1, 23333, 0, 23333, nil)
+			repo := &interleavingRepository{memoryRepository: newMemoryRepository(stored)}
+			svc := NewService(repo)
+			var winnerErr error
+			repo.other = func() { winnerErr = tt.winner(svc, stored.Code, nil) }
+
+			// Neither request sends a version: each uses the one it loaded
+			if err := tt.loser(svc, stored.Code, nil); !errors.Is(err, ErrOrderConflict) {
+				t.Fatalf("loser err = %v, want %v", err, ErrOrderConflict)
+			}
+			if winnerErr != nil {
+				t.Fatalf("winner err = %v", winnerErr)
+			}
+
+			got := repo.stored(stored.ID)
+			if got.Version != 2 || got.Status != tt.wantStatus || got.Products[0].Quantity != tt.wantBurgers {
+				t.Errorf("stored = version %d, %s, %d burgers; want version 2, %s, %d burgers",
+					got.Version, got.Status, got.Products[0].Quantity, tt.wantStatus, tt.wantBurgers)
+			}
+		})
+	}
+}
+
+// patchStatus changes the status of an order like PATCH /orders does
+func patchStatus(status OrderStatus) func(svc *Service, code string, expected *int64) error {
+	return func(svc *Service, code string, expected *int64) error {
+		_, err := svc.PartialUpdate(context.Background(), code, PartialUpdateInput{Status: &status, ExpectedVersion: expected})
+		return err
+	}
+}
+
+// putProducts replaces the lines of an order like PUT /orders does, with the given number of burgers
+func putProducts(burgers int) func(svc *Service, code string, expected *int64) error {
+	return func(svc *Service, code string, expected *int64) error {
+		_, err := svc.Modify(context.Background(), code, ModifyInput{
+			Products:        []OrderProduct{{ID: "p1", Name: "Burger", Price: 10000, Quantity: burgers}, {ID: "p2", Name: "Soda", Price: 3333, Quantity: 1}},
+			ExpectedVersion: expected,
+		})
+		return err
+	}
+}
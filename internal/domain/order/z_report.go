@@ -0,0 +1,98 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ZReportDateLayout is the layout of the report date (a local calendar day)
+const ZReportDateLayout = "2006-01-02"
+
+// ZReportSaleType summarizes the non-cancelled orders of one sale type
+type ZReportSaleType struct {
+	OrderCount int64
+	Sales      int64
+}
+
+// ZReport is the end-of-day summary of one local calendar day.
+// Sales and top products only count non-cancelled orders; cancelled orders are reported apart.
+type ZReport struct {
+	Date            string
+	Timezone        string
+	From            time.Time // Inclusive UTC start of the day
+	To              time.Time // Inclusive UTC end of the day
+	OrderCount      int64     // Every order created during the day, cancelled included
+	Sales           int64
+	AvgTicket       int64
+	CancelledCount  int64
+	CancelledSales  int64
+	OrdersByStatus  map[OrderStatus]int
+	OrdersByChannel map[Channel]int
+	BySaleType      map[SaleType]ZReportSaleType
+	TopProducts     []ProductSalesSummary
+}
+
+// ZReportWindow returns the UTC bounds of the calendar day in the given location.
+// The day runs from local midnight to the next local midnight, so DST days last 23 or 25 hours.
+// The end is inclusive and rounded to the millisecond precision orders are stored with.
+func ZReportWindow(date string, loc *time.Location) (from, to time.Time, err error) {
+	start, err := time.ParseInLocation(ZReportDateLayout, date, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %q", ErrInvalidReportDate, date)
+	}
+	end := start.AddDate(0, 0, 1).Add(-time.Millisecond)
+	return start.UTC(), end.UTC(), nil
+}
+
+// GetZReport builds the Z report of a local calendar day by composing the metrics aggregation:
+// one run over every order of the day, one over the non-cancelled ones and one per sale type.
+// Archived orders are included so a report can be reproduced after its orders were archived.
+func (s *Service) GetZReport(ctx context.Context, date string, loc *time.Location) (*ZReport, error) {
+	from, to, err := ZReportWindow(date, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	dateFrom, dateTo := from.Format(time.RFC3339Nano), to.Format(time.RFC3339Nano)
+	day := OrderFilters{DateFrom: &dateFrom, DateTo: &dateTo, IncludeArchived: true}
+
+	all, err := s.GetMetrics(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := day
+	for _, status := range AllStatuses {
+		if status != StatusCancelled {
+			completed.Statuses = append(completed.Statuses, status)
+		}
+	}
+	sales, err := s.GetMetrics(ctx, completed)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ZReport{
+		Date:            date,
+		Timezone:        loc.String(),
+		From:            from,
+		To:              to,
+		OrderCount:      all.OrderCount,
+		Sales:           sales.TotalSales,
+		AvgTicket:       sales.AvgTicket,
+		CancelledCount:  int64(all.OrdersByStatus[StatusCancelled]),
+		CancelledSales:  all.TotalSales - sales.TotalSales,
+		OrdersByStatus:  all.OrdersByStatus,
+		OrdersByChannel: all.OrdersByChannel,
+		BySaleType:      make(map[SaleType]ZReportSaleType),
+		TopProducts:     sales.TopProducts,
+	}
+
+	for _, saleType := range AllSaleTypes {
+		m := sales.OrdersBySaleType[saleType]
+		report.BySaleType[saleType] = ZReportSaleType{OrderCount: m.OrderCount, Sales: m.TotalSales}
+	}
+
+	return report, nil
+}
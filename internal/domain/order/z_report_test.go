@@ -0,0 +1,129 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestZReportWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		date     string
+		tz       string
+		wantFrom string
+		wantTo   string
+		wantErr  error
+	}{
+		{"utc", "2024-06-01", "UTC", "2024-06-01T00:00:00Z", "2024-06-01T23:59:59.999Z", nil},
+		{"west of utc", "2024-06-01", "America/Bogota", "2024-06-01T05:00:00Z", "2024-06-02T04:59:59.999Z", nil},
+		{"east of utc", "2024-06-01", "Asia/Tokyo", "2024-05-31T15:00:00Z", "2024-06-01T14:59:59.999Z", nil},
+		{"dst starts, 23 hours", "2024-03-10", "America/New_York", "2024-03-10T05:00:00Z", "2024-03-11T03:59:59.999Z", nil},
+		{"dst ends, 25 hours", "2024-11-03", "America/New_York", "2024-11-03T04:00:00Z", "2024-11-04T04:59:59.999Z", nil},
+		{"leap day", "2024-02-29", "UTC", "2024-02-29T00:00:00Z", "2024-02-29T23:59:59.999Z", nil},
+		{"not a day", "2023-02-29", "UTC", "", "", ErrInvalidReportDate},
+		{"wrong layout", "01/06/2024", "UTC", "", "", ErrInvalidReportDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.tz)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from, to, err := ZReportWindow(tt.date, loc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := from.Format(time.RFC3339Nano); got != tt.wantFrom {
+				t.Errorf("from = %s, want %s", got, tt.wantFrom)
+			}
+			if got := to.Format(time.RFC3339Nano); got != tt.wantTo {
+				t.Errorf("to = %s, want %s", got, tt.wantTo)
+			}
+		})
+	}
+}
+
+// zReportRepository answers GetMetrics with all orders or, when statuses are filtered, the non-cancelled ones
+type zReportRepository struct {
+	Repository
+	filters []OrderFilters
+}
+
+func (r *zReportRepository) GetMetrics(ctx context.Context, filters OrderFilters) (*OrderMetrics, error) {
+	r.filters = append(r.filters, filters)
+	if len(filters.Statuses) == 0 {
+		return &OrderMetrics{
+			OrderCount:      5,
+			TotalSales:      90000,
+			OrdersByStatus:  map[OrderStatus]int{StatusDelivered: 3, StatusCreated: 1, StatusCancelled: 1},
+			OrdersByChannel: map[Channel]int{ChannelPOS: 4, ChannelWeb: 1},
+		}, nil
+	}
+	return &OrderMetrics{
+		OrderCount: 4,
+		TotalSales: 80000,
+		AvgTicket:  20000,
+		OrdersBySaleType: map[SaleType]SaleTypeMetrics{
+			SaleTypeOnSite: {OrderCount: 3, TotalSales: 50000},
+		},
+		TopProducts: []ProductSalesSummary{{ProductID: "p1", Name: "Burger", TotalQuantity: 6, TotalRevenue: 60000}},
+	}, nil
+}
+
+func TestGetZReport(t *testing.T) {
+	loc, err := time.LoadLocation("America/Bogota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &zReportRepository{}
+
+	report, err := NewService(repo).GetZReport(context.Background(), "2024-06-01", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.filters) != 2 {
+		t.Fatalf("GetMetrics called %d times, want 2", len(repo.filters))
+	}
+	for _, f := range repo.filters {
+		if f.DateFrom == nil || *f.DateFrom != "2024-06-01T05:00:00Z" || f.DateTo == nil || *f.DateTo != "2024-06-02T04:59:59.999Z" {
+			t.Errorf("window = %v..%v, want the Bogota day in UTC", f.DateFrom, f.DateTo)
+		}
+		if !f.IncludeArchived {
+			t.Error("archived orders are left out of the report")
+		}
+	}
+	if statuses := repo.filters[1].Statuses; len(statuses) != len(AllStatuses)-1 || slices.Contains(statuses, StatusCancelled) {
+		t.Errorf("sales statuses = %v, want every status but CANCELLED", statuses)
+	}
+
+	if report.Timezone != "America/Bogota" || report.Date != "2024-06-01" {
+		t.Errorf("report of %s %s", report.Date, report.Timezone)
+	}
+	if report.OrderCount != 5 || report.Sales != 80000 || report.AvgTicket != 20000 {
+		t.Errorf("orders %d, sales %d, avg %d, want 5, 80000, 20000", report.OrderCount, report.Sales, report.AvgTicket)
+	}
+	if report.CancelledCount != 1 || report.CancelledSales != 10000 {
+		t.Errorf("cancelled %d for %d, want 1 for 10000", report.CancelledCount, report.CancelledSales)
+	}
+	if got := report.BySaleType[SaleTypeOnSite]; got != (ZReportSaleType{OrderCount: 3, Sales: 50000}) {
+		t.Errorf("on site = %+v", got)
+	}
+	if got, ok := report.BySaleType[SaleTypeDelivery]; !ok || got != (ZReportSaleType{}) {
+		t.Errorf("delivery = %+v, %v, want an empty entry", got, ok)
+	}
+	if len(report.TopProducts) != 1 || report.TopProducts[0].ProductID != "p1" {
+		t.Errorf("top products = %v", report.TopProducts)
+	}
+
+	if _, err := NewService(repo).GetZReport(context.Background(), "June 1", loc); !errors.Is(err, ErrInvalidReportDate) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidReportDate)
+	}
+}
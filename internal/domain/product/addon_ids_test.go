@@ -0,0 +1,109 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// addonWithID is a valid addon with the given ID
+func addonWithID(id string) Addon {
+	return Addon{ID: id, Name: "Queso " + id, Price: 1000, IsAvailable: true}
+}
+
+// variationWith is a valid price variation including the given options
+func variationWith(kind string, options ...Addon) PriceVariation {
+	return PriceVariation{Type: kind, Price: 15000, IncludedAddons: IncludedAddons{MaxSelections: 1, Options: options}}
+}
+
+func TestValidateAddonIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		available  []Addon
+		variations []PriceVariation
+		wantErr    error
+		wantField  string
+		wantIndex  int
+	}{
+		{"distinct ids", []Addon{addonWithID("a1"), addonWithID("a2")},
+			[]PriceVariation{variationWith("Normal", addonWithID("o1"))}, nil, "", 0},
+		{"options referencing available addons", []Addon{addonWithID("a1")},
+			[]PriceVariation{variationWith("Normal", addonWithID("a1")), variationWith("Doble", addonWithID("a1"))}, nil, "", 0},
+		{"duplicate available addon", []Addon{addonWithID("a1"), addonWithID("a1")},
+			[]PriceVariation{{Type: "Normal", Price: 15000}}, ErrDuplicateAddonID, "available_addons[1].id", 1},
+		{"option repeated in a variation", []Addon{addonWithID("a1")},
+			[]PriceVariation{variationWith("Normal", addonWithID("a1"), addonWithID("a1"))},
+			ErrDuplicateAddonID, "price_variations[0].included_addons.options[1].id", 0},
+		{"option shared across variations", nil,
+			[]PriceVariation{variationWith("Normal", addonWithID("o1")), variationWith("Doble", addonWithID("o1"))},
+			ErrDuplicateAddonID, "price_variations[1].included_addons.options[0].id", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProduct("company", "sale-point", "Hamburguesa", "Platos", "")
+			p.PriceVariations = tt.variations
+			p.AvailableAddons = tt.available
+
+			err := p.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.As(err, &domainErr) || domainErr.Field != tt.wantField {
+				t.Fatalf("field = %v, want %q", domainErr, tt.wantField)
+			}
+			if domainErr.Index == nil || *domainErr.Index != tt.wantIndex {
+				t.Errorf("index = %v, want %d", domainErr.Index, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestCreateAndUpdateAssignAddonIDs(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+
+	input := testInput("")
+	input.AvailableAddons = []Addon{{Name: "Tocineta", Price: 2000}, addonWithID("a1")}
+	input.PriceVariations = []PriceVariation{variationWith("Normal", Addon{Name: "Papas"}, addonWithID("a1"))}
+
+	created, err := svc.Create(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := repo.stored(created.ID)
+	generated := stored.AvailableAddons[0].ID
+	option := stored.PriceVariations[0].IncludedAddons.Options[0].ID
+	if generated == "" || option == "" || generated == option {
+		t.Fatalf("addon ids = %q and %q, want distinct generated ids", generated, option)
+	}
+	if stored.AvailableAddons[1].ID != "a1" || stored.PriceVariations[0].IncludedAddons.Options[1].ID != "a1" {
+		t.Error("given addon ids were replaced")
+	}
+
+	// Updates keep the given IDs and generate the missing ones
+	addons := []Addon{stored.AvailableAddons[0], {Name: "Aguacate", Price: 1500}}
+	updated, err := svc.Update(ctx, created.ID, UpdateInput{AvailableAddons: &addons})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.AvailableAddons[0].ID != generated || updated.AvailableAddons[1].ID == "" {
+		t.Errorf("updated addon ids = %q and %q, want %q and a generated id",
+			updated.AvailableAddons[0].ID, updated.AvailableAddons[1].ID, generated)
+	}
+
+	duplicated := []Addon{addonWithID("a1"), addonWithID("a1")}
+	if _, err := svc.Update(ctx, created.ID, UpdateInput{AvailableAddons: &duplicated}); !errors.Is(err, ErrDuplicateAddonID) {
+		t.Errorf("err = %v, want %v", err, ErrDuplicateAddonID)
+	}
+	if repo.stored(created.ID).AvailableAddons[1].Name != "Aguacate" {
+		t.Error("a rejected update changed the addons")
+	}
+}
@@ -0,0 +1,39 @@
+package product
+
+import "time"
+
+// AvailabilityReason explains why a product can or cannot be ordered
+type AvailabilityReason string
+
+const (
+	ReasonInStock          AvailabilityReason = "IN_STOCK"
+	ReasonOutOfStock       AvailabilityReason = "OUT_OF_STOCK"
+	ReasonManuallyDisabled AvailabilityReason = "MANUALLY_DISABLED"
+	ReasonOutsideSchedule  AvailabilityReason = "OUTSIDE_SCHEDULE"
+)
+
+// Availability is whether a product can be ordered right now, and why
+type Availability struct {
+	Available bool
+	Reason    AvailabilityReason
+}
+
+// Availability derives the product's availability from its flags, stock and schedule at the current time
+func (p *Product) Availability() Availability {
+	return p.AvailabilityAt(time.Now())
+}
+
+// AvailabilityAt derives the product's availability at the given time. A manual disable wins over stock,
+// so re-enabling a product shows the real stock state, and stock wins over the schedule, which passes by itself.
+func (p *Product) AvailabilityAt(now time.Time) Availability {
+	switch {
+	case !p.IsAvailable:
+		return Availability{Reason: ReasonManuallyDisabled}
+	case !p.IsUnlimitedStock && (p.Stock == nil || *p.Stock <= 0):
+		return Availability{Reason: ReasonOutOfStock}
+	case p.AvailabilitySchedule != nil && !p.AvailabilitySchedule.OpenAt(now):
+		return Availability{Reason: ReasonOutsideSchedule}
+	default:
+		return Availability{Available: true, Reason: ReasonInStock}
+	}
+}
@@ -0,0 +1,40 @@
+package product
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAvailability(t *testing.T) {
+	stock := func(n int) *int { return &n }
+	monday8am := time.Date(2024, 6, 3, 8, 0, 0, 0, time.UTC)
+	breakfast := &AvailabilitySchedule{Timezone: "UTC", Windows: []AvailabilityWindow{{Day: "monday", Start: "06:00", End: "11:00"}}}
+	lunch := &AvailabilitySchedule{Timezone: "UTC", Windows: []AvailabilityWindow{{Day: "monday", Start: "12:00", End: "15:00"}}}
+
+	tests := []struct {
+		name      string
+		product   Product
+		wantAvail bool
+		want      AvailabilityReason
+	}{
+		{"unlimited", Product{IsAvailable: true, IsUnlimitedStock: true}, true, ReasonInStock},
+		{"in stock", Product{IsAvailable: true, Stock: stock(3)}, true, ReasonInStock},
+		{"sold out", Product{IsAvailable: true, Stock: stock(0)}, false, ReasonOutOfStock},
+		{"negative stock", Product{IsAvailable: true, Stock: stock(-2)}, false, ReasonOutOfStock},
+		{"limited without a stock", Product{IsAvailable: true}, false, ReasonOutOfStock},
+		{"disabled", Product{IsAvailable: false, IsUnlimitedStock: true}, false, ReasonManuallyDisabled},
+		{"disabled wins over sold out", Product{IsAvailable: false, Stock: stock(0)}, false, ReasonManuallyDisabled},
+		{"within schedule", Product{IsAvailable: true, IsUnlimitedStock: true, AvailabilitySchedule: breakfast}, true, ReasonInStock},
+		{"outside schedule", Product{IsAvailable: true, IsUnlimitedStock: true, AvailabilitySchedule: lunch}, false, ReasonOutsideSchedule},
+		{"sold out wins over schedule", Product{IsAvailable: true, Stock: stock(0), AvailabilitySchedule: lunch}, false, ReasonOutOfStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.product.AvailabilityAt(monday8am)
+			if got.Available != tt.wantAvail || got.Reason != tt.want {
+				t.Errorf("availability = %+v, want %v %s", got, tt.wantAvail, tt.want)
+			}
+		})
+	}
+}
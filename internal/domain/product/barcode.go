@@ -0,0 +1,68 @@
+package product
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+var (
+	// numericBarcodePattern matches a barcode made only of digits, which must then be a GTIN
+	numericBarcodePattern = regexp.MustCompile(`^[0-9]+$`)
+	// barcodePattern matches any other barcode, e.g. a Code 128 label: printable ASCII without spaces
+	barcodePattern = regexp.MustCompile(`^[!-~]{1,48}$`)
+)
+
+// NormalizeBarcode trims a barcode. A missing or blank barcode means the product has none and returns nil.
+func NormalizeBarcode(barcode *string) *string {
+	if barcode == nil {
+		return nil
+	}
+	normalized := strings.TrimSpace(*barcode)
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// IsValidBarcode reports whether a normalized barcode can be stored. Numeric barcodes must be an
+// EAN-8, UPC-A, EAN-13 or GTIN-14 with a valid check digit; others have 1 to 48 printable characters.
+func IsValidBarcode(barcode string) bool {
+	if !numericBarcodePattern.MatchString(barcode) {
+		return barcodePattern.MatchString(barcode)
+	}
+	switch len(barcode) {
+	case 8, 12, 13, 14:
+		return hasValidCheckDigit(barcode)
+	}
+	return false
+}
+
+// hasValidCheckDigit checks the GS1 check digit of a GTIN of any length: from the right, the digits
+// before the check digit are weighted 3, 1, 3, ... and the check digit completes the sum to a multiple of 10
+func hasValidCheckDigit(digits string) bool {
+	sum := 0
+	for i := len(digits) - 2; i >= 0; i-- {
+		weight := 1
+		if (len(digits)-2-i)%2 == 0 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+	return int(digits[len(digits)-1]-'0') == (10-sum%10)%10
+}
+
+// GetByBarcode retrieves the live product of a company with the given barcode; the barcode is normalized first
+func (s *Service) GetByBarcode(ctx context.Context, companyID, barcode string) (*Product, error) {
+	if companyID == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidCompanyID, "company_id", nil)
+	}
+	normalized := NormalizeBarcode(&barcode)
+	if normalized == nil || !IsValidBarcode(*normalized) {
+		return nil, apperrors.NewDomainError(ErrInvalidBarcode, "barcode", barcode)
+	}
+
+	return s.repo.FindByBarcode(ctx, companyID, *normalized)
+}
@@ -0,0 +1,124 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIsValidBarcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		barcode string
+		want    bool
+	}{
+		{"ean-13", "4006381333931", true},
+		{"ean-13 with a wrong check digit", "4006381333932", false},
+		{"ean-8", "96385074", true},
+		{"ean-8 with a wrong check digit", "96385075", false},
+		{"upc-a", "036000291452", true},
+		{"gtin-14", "10036000291459", true},
+		{"unsupported digit count", "123456789", false},
+		{"code 128 label", "LOT-2024/A", true},
+		{"ean-13 length with a letter is a label", "400638133393A", true},
+		{"inner space", "LOT 2024", false},
+		{"forty-nine characters", "ABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHI", false},
+		{"accent", "CAFÉ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidBarcode(tt.barcode); got != tt.want {
+				t.Errorf("IsValidBarcode(%q) = %v, want %v", tt.barcode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateAndUpdateBarcode(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+
+	input := testInput("")
+	barcode := " 4006381333931 "
+	input.Barcode = &barcode
+	p, err := svc.Create(ctx, input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if p.Barcode == nil || *p.Barcode != "4006381333931" {
+		t.Fatalf("created barcode = %v, want 4006381333931", p.Barcode)
+	}
+
+	invalid := "4006381333932"
+	if _, err := svc.Update(ctx, p.ID, UpdateInput{Barcode: &invalid}); !errors.Is(err, ErrInvalidBarcode) {
+		t.Errorf("invalid barcode error = %v, want %v", err, ErrInvalidBarcode)
+	}
+
+	name := "Margherita"
+	updated, err := svc.Update(ctx, p.ID, UpdateInput{Name: &name})
+	if err != nil || updated.Barcode == nil || *updated.Barcode != "4006381333931" {
+		t.Errorf("update without barcode = %v, %v; want the barcode kept", updated, err)
+	}
+
+	empty := ""
+	cleared, err := svc.Update(ctx, p.ID, UpdateInput{Barcode: &empty})
+	if err != nil || cleared.Barcode != nil {
+		t.Errorf("update with empty barcode = %v, %v; want the barcode removed", cleared, err)
+	}
+}
+
+func TestGetByBarcode(t *testing.T) {
+	barcode := "4006381333931"
+	repo := newMemoryRepository(&Product{ID: "pizza", CompanyID: "company-1", SalePointID: "sale-point-1", Barcode: &barcode})
+	svc := NewService(repo)
+
+	tests := []struct {
+		name      string
+		companyID string
+		barcode   string
+		wantID    string
+		wantErr   error
+	}{
+		{"trimmed match", "company-1", " 4006381333931 ", "pizza", nil},
+		{"other company", "company-2", "4006381333931", "", ErrProductNotFound},
+		{"missing company", "", "4006381333931", "", ErrInvalidCompanyID},
+		{"wrong check digit", "company-1", "4006381333932", "", ErrInvalidBarcode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := svc.GetByBarcode(context.Background(), tt.companyID, tt.barcode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && p.ID != tt.wantID {
+				t.Errorf("product = %s, want %s", p.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestCopyLeavesBarcodesBehind(t *testing.T) {
+	barcode, targetBarcode := "4006381333931", "96385074"
+	source := copySource()
+	source[0].Barcode = &barcode // Margarita, overwriting the target's
+	source[1].Barcode = &barcode // Hawaiana, created in the target
+	target := &Product{ID: "t-marg", CompanyID: "c1", SalePointID: "sp2", Name: "Margarita", Category: "Pizzas",
+		Barcode: &targetBarcode, IsAvailable: true, IsUnlimitedStock: true}
+	repo := newMemoryRepository(append(source, target)...)
+
+	_, err := NewService(repo).Copy(context.Background(), CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp2", Overwrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range salePointProducts(t, repo, "sp2") {
+		switch {
+		case p.ID == "t-marg" && (p.Barcode == nil || *p.Barcode != targetBarcode):
+			t.Errorf("overwritten margarita barcode = %v, want %s", p.Barcode, targetBarcode)
+		case p.ID != "t-marg" && p.Barcode != nil:
+			t.Errorf("copied %s barcode = %s, want none", p.Name, *p.Barcode)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package product
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxBatchIDs is the maximum number of product IDs a single batch fetch may list
+const MaxBatchIDs = 100
+
+// BatchInput lists the products to fetch at once, e.g. the products of a cart
+type BatchInput struct {
+	IDs           []string
+	OnlyAvailable bool // Report soft deleted and unavailable products as missing
+}
+
+// BatchResult holds the products found, in the order they were asked for, and the IDs that did not resolve
+type BatchResult struct {
+	Products []*Product
+	Missing  []string
+}
+
+// GetByIDs fetches the listed products in one query. Repeated IDs are returned once. Soft deleted and
+// unavailable products are returned too, so a cart can tell why they can't be ordered, unless OnlyAvailable is set.
+func (s *Service) GetByIDs(ctx context.Context, input BatchInput) (*BatchResult, error) {
+	ids := make([]string, 0, len(input.IDs))
+	seen := make(map[string]bool, len(input.IDs))
+	for _, id := range input.IDs {
+		if id == "" {
+			return nil, ErrEmptyBatch
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(ids) > MaxBatchIDs {
+		return nil, ErrBatchTooLarge
+	}
+
+	found, err := s.repo.FindByIDs(ctx, ids, !input.OnlyAvailable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	byID := make(map[string]*Product, len(found))
+	for _, p := range found {
+		if input.OnlyAvailable && !p.AvailabilityAt(s.now()).Available {
+			continue
+		}
+		byID[p.ID] = p
+	}
+
+	result := &BatchResult{Products: make([]*Product, 0, len(byID)), Missing: []string{}}
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			result.Products = append(result.Products, p)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGetByIDs(t *testing.T) {
+	available, outOfStock, deleted := NewProduct("c1", "sp1", "Burger", "Platos", ""), NewProduct("c1", "sp1", "Soda", "Bebidas", ""), NewProduct("c1", "sp1", "Salad", "Platos", "")
+	stock := 0
+	outOfStock.IsUnlimitedStock, outOfStock.Stock = false, &stock
+	deletedAt := time.Now()
+	deleted.DeletedAt = &deletedAt
+	service := NewService(newMemoryRepository(available, outOfStock, deleted))
+
+	tooMany := make([]string, MaxBatchIDs+1)
+	for i := range tooMany {
+		tooMany[i] = strconv.Itoa(i)
+	}
+
+	tests := []struct {
+		name        string
+		input       BatchInput
+		wantIDs     []string
+		wantMissing []string
+		wantErr     error
+	}{
+		{"request order, deleted included", BatchInput{IDs: []string{deleted.ID, "unknown", outOfStock.ID, available.ID}},
+			[]string{deleted.ID, outOfStock.ID, available.ID}, []string{"unknown"}, nil},
+		{"only available", BatchInput{IDs: []string{deleted.ID, outOfStock.ID, available.ID, available.ID}, OnlyAvailable: true},
+			[]string{available.ID}, []string{deleted.ID, outOfStock.ID}, nil},
+		{"repeated ids count once", BatchInput{IDs: append([]string{available.ID, available.ID}, tooMany[:MaxBatchIDs-1]...)}, nil, nil, nil},
+		{"no ids", BatchInput{}, nil, nil, ErrEmptyBatch},
+		{"blank id", BatchInput{IDs: []string{available.ID, ""}}, nil, nil, ErrEmptyBatch},
+		{"too many", BatchInput{IDs: tooMany}, nil, nil, ErrBatchTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.GetByIDs(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil || tt.wantIDs == nil {
+				return
+			}
+			var ids []string
+			for _, p := range result.Products {
+				ids = append(ids, p.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || !reflect.DeepEqual(result.Missing, tt.wantMissing) {
+				t.Errorf("products = %v, missing = %v; want %v and %v", ids, result.Missing, tt.wantIDs, tt.wantMissing)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package product
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxBulkAvailabilityIDs is the maximum number of product IDs a single bulk availability update may list
+const MaxBulkAvailabilityIDs = 500
+
+// AvailabilitySelector selects the products of a bulk availability update: explicit IDs, optionally
+// restricted to a sale point, or every product of a sale point's category
+type AvailabilitySelector struct {
+	IDs         []string
+	SalePointID string
+	Category    *string
+}
+
+// BulkAvailabilityInput marks the selected products as available or unavailable
+type BulkAvailabilityInput struct {
+	AvailabilitySelector
+	IsAvailable bool
+}
+
+// BulkAvailabilityResult reports the outcome of a bulk availability update
+type BulkAvailabilityResult struct {
+	Matched  int64 // Live products selected
+	Modified int64 // Selected products whose availability changed
+}
+
+// Validate checks that exactly one of IDs or Category selects the products, and that a category
+// comes with its sale point so another branch's menu is never touched
+func (s AvailabilitySelector) Validate() error {
+	switch {
+	case len(s.IDs) > 0 && s.Category != nil:
+		return ErrAmbiguousBulkAvailability
+	case len(s.IDs) > MaxBulkAvailabilityIDs:
+		return ErrBulkAvailabilityTooLarge
+	case len(s.IDs) > 0:
+		for _, id := range s.IDs {
+			if id == "" {
+				return ErrEmptyBulkAvailability
+			}
+		}
+		return nil
+	case s.Category == nil || *s.Category == "":
+		return ErrEmptyBulkAvailability
+	case s.SalePointID == "":
+		return ErrBulkAvailabilitySalePoint
+	default:
+		return nil
+	}
+}
+
+// BulkSetAvailability marks the selected live products as available or unavailable in a single update
+func (s *Service) BulkSetAvailability(ctx context.Context, input BulkAvailabilityInput) (*BulkAvailabilityResult, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.SetAvailability(ctx, input.AvailabilitySelector, input.IsAvailable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update availability: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBulkSetAvailability(t *testing.T) {
+	fritos := "Fritos"
+	empty := ""
+	deletedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tooMany := make([]string, MaxBulkAvailabilityIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("p%d", i)
+	}
+
+	tests := []struct {
+		name          string
+		selector      AvailabilitySelector
+		wantErr       error
+		wantResult    BulkAvailabilityResult
+		wantAvailable []string // Products left available
+	}{
+		{
+			name:          "category of a sale point",
+			selector:      AvailabilitySelector{SalePointID: "sp1", Category: &fritos},
+			wantResult:    BulkAvailabilityResult{Matched: 2, Modified: 1},
+			wantAvailable: []string{"soda", "other-fries"},
+		},
+		{
+			name:          "ids",
+			selector:      AvailabilitySelector{IDs: []string{"empanada", "soda", "deleted", "missing"}},
+			wantResult:    BulkAvailabilityResult{Matched: 2, Modified: 1},
+			wantAvailable: []string{"fries", "other-fries"},
+		},
+		{
+			name:          "ids within a sale point",
+			selector:      AvailabilitySelector{IDs: []string{"fries", "other-fries"}, SalePointID: "sp2"},
+			wantResult:    BulkAvailabilityResult{Matched: 1, Modified: 1},
+			wantAvailable: []string{"fries", "soda"},
+		},
+		{name: "category without sale point", selector: AvailabilitySelector{Category: &fritos}, wantErr: ErrBulkAvailabilitySalePoint},
+		{name: "ids and category", selector: AvailabilitySelector{IDs: []string{"fries"}, SalePointID: "sp1", Category: &fritos}, wantErr: ErrAmbiguousBulkAvailability},
+		{name: "neither", selector: AvailabilitySelector{SalePointID: "sp1"}, wantErr: ErrEmptyBulkAvailability},
+		{name: "empty category", selector: AvailabilitySelector{SalePointID: "sp1", Category: &empty}, wantErr: ErrEmptyBulkAvailability},
+		{name: "blank id", selector: AvailabilitySelector{IDs: []string{"fries", ""}}, wantErr: ErrEmptyBulkAvailability},
+		{name: "too many ids", selector: AvailabilitySelector{IDs: tooMany}, wantErr: ErrBulkAvailabilityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(
+				&Product{ID: "fries", SalePointID: "sp1", Category: "Fritos", IsAvailable: true},
+				&Product{ID: "empanada", SalePointID: "sp1", Category: "Fritos", IsAvailable: false},
+				&Product{ID: "soda", SalePointID: "sp1", Category: "Drinks", IsAvailable: true},
+				&Product{ID: "other-fries", SalePointID: "sp2", Category: "Fritos", IsAvailable: true},
+				&Product{ID: "deleted", SalePointID: "sp1", Category: "Fritos", IsAvailable: true, DeletedAt: &deletedAt},
+			)
+
+			input := BulkAvailabilityInput{AvailabilitySelector: tt.selector, IsAvailable: false}
+			result, err := NewService(repo).BulkSetAvailability(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if *result != tt.wantResult {
+				t.Errorf("result = %+v, want %+v", *result, tt.wantResult)
+			}
+
+			var available []string
+			for _, id := range []string{"fries", "empanada", "soda", "other-fries"} {
+				if repo.stored(id).IsAvailable {
+					available = append(available, id)
+				}
+			}
+			if fmt.Sprint(available) != fmt.Sprint(tt.wantAvailable) {
+				t.Errorf("available = %v, want %v", available, tt.wantAvailable)
+			}
+			if !repo.stored("deleted").IsAvailable {
+				t.Error("soft deleted product was updated")
+			}
+		})
+	}
+}
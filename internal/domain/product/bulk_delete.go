@@ -0,0 +1,119 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxBulkDelete is the maximum number of products a single bulk delete may remove
+const MaxBulkDelete = 1000
+
+// OrderReferences reports which products are referenced by orders
+type OrderReferences interface {
+	FindReferencedProductIDs(ctx context.Context, productIDs []string, since time.Time) ([]string, error)
+}
+
+// OpenOrderCounter counts the orders still in progress that contain a product
+type OpenOrderCounter interface {
+	CountOpenOrdersWithProduct(ctx context.Context, productID string) (int64, error)
+}
+
+// BulkDeleteInput selects the products to delete: explicit IDs, or a sale point with an optional category
+type BulkDeleteInput struct {
+	IDs         []string
+	SalePointID string
+	Category    *string
+	Force       bool // Delete even if recent orders reference some of the products
+	Hard        bool // Remove the products for good instead of soft deleting them
+}
+
+// BulkDeleteResult reports the outcome of a bulk delete
+type BulkDeleteResult struct {
+	Deleted    int64    // Products deleted
+	Skipped    int64    // Selected products that no longer existed, or were already soft deleted
+	Referenced []string // Selected products referenced by recent orders
+}
+
+// BulkDelete soft deletes the selected products, or removes them for good when Hard is set. A hard
+// delete fails with ErrProductInUse, whatever Force says, when open orders contain any of them. Unless
+// Force is set, nothing is deleted when recent orders reference any of them: the referenced IDs are
+// returned along with ErrProductsReferenced.
+func (s *Service) BulkDelete(ctx context.Context, input BulkDeleteInput) (*BulkDeleteResult, error) {
+	ids, err := s.bulkDeleteIDs(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkDeleteResult{Referenced: []string{}}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	if input.Hard {
+		if err := s.checkOpenOrders(ctx, ids...); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.orderRefs != nil && s.referenceWindow > 0 {
+		referenced, err := s.orderRefs.FindReferencedProductIDs(ctx, ids, time.Now().Add(-s.referenceWindow))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check order references: %w", err)
+		}
+		result.Referenced = referenced
+		if len(referenced) > 0 && !input.Force {
+			return result, ErrProductsReferenced
+		}
+	}
+
+	var deleted int64
+	if input.Hard {
+		deleted, err = s.repo.DeleteMany(ctx, ids)
+	} else {
+		deleted, err = s.repo.SoftDeleteMany(ctx, ids, time.Now())
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Deleted = deleted
+	result.Skipped = int64(len(ids)) - deleted
+
+	return result, nil
+}
+
+// bulkDeleteIDs resolves the selection to a deduplicated list of product IDs
+func (s *Service) bulkDeleteIDs(ctx context.Context, input BulkDeleteInput) ([]string, error) {
+	if len(input.IDs) > 0 {
+		if input.SalePointID != "" || input.Category != nil {
+			return nil, ErrAmbiguousBulkDelete
+		}
+		seen := make(map[string]bool, len(input.IDs))
+		ids := make([]string, 0, len(input.IDs))
+		for _, id := range input.IDs {
+			if id == "" {
+				return nil, ErrEmptyBulkDelete
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > MaxBulkDelete {
+			return nil, ErrBulkDeleteTooLarge
+		}
+		return ids, nil
+	}
+
+	if input.SalePointID == "" {
+		return nil, ErrEmptyBulkDelete
+	}
+	ids, err := s.repo.FindIDsBySalePointID(ctx, input.SalePointID, ProductFilters{Category: input.Category})
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > MaxBulkDelete {
+		return nil, ErrBulkDeleteTooLarge
+	}
+	return ids, nil
+}
@@ -0,0 +1,302 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// orderReferences is an OrderReferences fake reporting a fixed set of referenced products
+type orderReferences struct {
+	referenced map[string]bool
+	since      time.Time // The cutoff of the last call
+	calls      int
+}
+
+func (r *orderReferences) FindReferencedProductIDs(ctx context.Context, productIDs []string, since time.Time) ([]string, error) {
+	r.calls++
+	r.since = since
+	var found []string
+	for _, id := range productIDs {
+		if r.referenced[id] {
+			found = append(found, id)
+		}
+	}
+	return found, nil
+}
+
+// bulkDeleteIDs lists the products of bulkDeleteCatalog, sorted
+var bulkDeleteIDs = []string{"other", "soda", "taco-1", "taco-2"}
+
+// bulkDeleteCatalog returns a sale point with two tacos and a drink, and a product of another sale point
+func bulkDeleteCatalog() *memoryRepository {
+	return newMemoryRepository(
+		&Product{ID: "taco-1", SalePointID: "sp1", Category: "Tacos"},
+		&Product{ID: "taco-2", SalePointID: "sp1", Category: "Tacos"},
+		&Product{ID: "soda", SalePointID: "sp1", Category: "Drinks"},
+		&Product{ID: "other", SalePointID: "sp2", Category: "Tacos"},
+	)
+}
+
+func TestBulkDelete(t *testing.T) {
+	tacos := "Tacos"
+	tests := []struct {
+		name           string
+		input          BulkDeleteInput
+		softDeleted    []string // Soft deleted before the call
+		referenced     []string
+		wantErr        error
+		wantDeleted    int64
+		wantSkipped    int64
+		wantReferenced []string
+		wantRemaining  []string // Live products after the call
+		wantPurged     []string // Products no longer stored at all
+	}{
+		{
+			name:          "explicit ids",
+			input:         BulkDeleteInput{IDs: []string{"taco-1", "soda"}},
+			wantDeleted:   2,
+			wantRemaining: []string{"other", "taco-2"},
+		},
+		{
+			name:          "hard delete",
+			input:         BulkDeleteInput{IDs: []string{"taco-1", "soda"}, Hard: true},
+			wantDeleted:   2,
+			wantRemaining: []string{"other", "taco-2"},
+			wantPurged:    []string{"soda", "taco-1"},
+		},
+		{
+			name:          "already soft deleted products are skipped",
+			input:         BulkDeleteInput{IDs: []string{"taco-1", "taco-2"}},
+			softDeleted:   []string{"taco-1"},
+			wantDeleted:   1,
+			wantSkipped:   1,
+			wantRemaining: []string{"other", "soda"},
+		},
+		{
+			name:          "hard delete purges soft deleted products",
+			input:         BulkDeleteInput{IDs: []string{"taco-1", "taco-2"}, Hard: true},
+			softDeleted:   []string{"taco-1"},
+			wantDeleted:   2,
+			wantRemaining: []string{"other", "soda"},
+			wantPurged:    []string{"taco-1", "taco-2"},
+		},
+		{
+			name:          "missing and duplicate ids",
+			input:         BulkDeleteInput{IDs: []string{"taco-1", "taco-1", "gone"}},
+			wantDeleted:   1,
+			wantSkipped:   1,
+			wantRemaining: []string{"other", "soda", "taco-2"},
+		},
+		{
+			name:          "sale point selector",
+			input:         BulkDeleteInput{SalePointID: "sp1"},
+			wantDeleted:   3,
+			wantRemaining: []string{"other"},
+		},
+		{
+			name:          "sale point and category",
+			input:         BulkDeleteInput{SalePointID: "sp1", Category: &tacos},
+			wantDeleted:   2,
+			wantRemaining: []string{"other", "soda"},
+		},
+		{
+			name:           "referenced products block the deletion",
+			input:          BulkDeleteInput{SalePointID: "sp1"},
+			referenced:     []string{"taco-2"},
+			wantErr:        ErrProductsReferenced,
+			wantReferenced: []string{"taco-2"},
+			wantRemaining:  []string{"other", "soda", "taco-1", "taco-2"},
+		},
+		{
+			name:           "force deletes referenced products",
+			input:          BulkDeleteInput{IDs: []string{"taco-1", "taco-2"}, Force: true},
+			referenced:     []string{"taco-2"},
+			wantDeleted:    2,
+			wantReferenced: []string{"taco-2"},
+			wantRemaining:  []string{"other", "soda"},
+		},
+		{
+			name:          "unreferenced products with others referenced",
+			input:         BulkDeleteInput{IDs: []string{"soda"}},
+			referenced:    []string{"taco-2"},
+			wantDeleted:   1,
+			wantRemaining: []string{"other", "taco-1", "taco-2"},
+		},
+		{
+			name:          "empty sale point",
+			input:         BulkDeleteInput{SalePointID: "sp9"},
+			wantRemaining: []string{"other", "soda", "taco-1", "taco-2"},
+		},
+		{
+			name:          "empty selector",
+			input:         BulkDeleteInput{},
+			wantErr:       ErrEmptyBulkDelete,
+			wantRemaining: []string{"other", "soda", "taco-1", "taco-2"},
+		},
+		{
+			name:          "empty id",
+			input:         BulkDeleteInput{IDs: []string{"taco-1", ""}},
+			wantErr:       ErrEmptyBulkDelete,
+			wantRemaining: []string{"other", "soda", "taco-1", "taco-2"},
+		},
+		{
+			name:          "category without sale point",
+			input:         BulkDeleteInput{Category: &tacos},
+			wantErr:       ErrEmptyBulkDelete,
+			wantRemaining: []string{"other", "soda", "taco-1", "taco-2"},
+		},
+		{
+			name:          "ids and selector",
+			input:         BulkDeleteInput{IDs: []string{"taco-1"}, SalePointID: "sp1"},
+			wantErr:       ErrAmbiguousBulkDelete,
+			wantRemaining: []string{"other", "soda", "taco-1", "taco-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := bulkDeleteCatalog()
+			for _, id := range tt.softDeleted {
+				if err := repo.SoftDelete(context.Background(), id, time.Now()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			refs := &orderReferences{referenced: make(map[string]bool)}
+			for _, id := range tt.referenced {
+				refs.referenced[id] = true
+			}
+			svc := NewService(repo, WithOrderReferences(refs, 30*24*time.Hour))
+
+			result, err := svc.BulkDelete(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if result != nil {
+				if result.Deleted != tt.wantDeleted || result.Skipped != tt.wantSkipped {
+					t.Errorf("deleted %d, skipped %d, want %d and %d", result.Deleted, result.Skipped, tt.wantDeleted, tt.wantSkipped)
+				}
+				if len(result.Referenced) > 0 || len(tt.wantReferenced) > 0 {
+					if !slices.Equal(result.Referenced, tt.wantReferenced) {
+						t.Errorf("referenced = %v, want %v", result.Referenced, tt.wantReferenced)
+					}
+				}
+			}
+
+			var remaining, purged []string
+			for _, id := range bulkDeleteIDs {
+				p, ok := repo.products[id]
+				switch {
+				case !ok:
+					purged = append(purged, id)
+				case !p.IsDeleted():
+					remaining = append(remaining, id)
+				}
+			}
+			if !slices.Equal(remaining, tt.wantRemaining) {
+				t.Errorf("remaining = %v, want %v", remaining, tt.wantRemaining)
+			}
+			if !slices.Equal(purged, tt.wantPurged) {
+				t.Errorf("purged = %v, want %v", purged, tt.wantPurged)
+			}
+		})
+	}
+}
+
+func TestBulkDeleteReferenceWindow(t *testing.T) {
+	window := 14 * 24 * time.Hour
+	refs := &orderReferences{}
+	svc := NewService(bulkDeleteCatalog(), WithOrderReferences(refs, window))
+
+	before := time.Now()
+	if _, err := svc.BulkDelete(context.Background(), BulkDeleteInput{IDs: []string{"soda"}}); err != nil {
+		t.Fatal(err)
+	}
+	if refs.since.Before(before.Add(-window)) || refs.since.After(time.Now().Add(-window)) {
+		t.Errorf("references checked since %v, want %v ago", refs.since, window)
+	}
+
+	// Without a window nothing is checked
+	refs.calls = 0
+	svc = NewService(bulkDeleteCatalog(), WithOrderReferences(refs, 0))
+	if _, err := svc.BulkDelete(context.Background(), BulkDeleteInput{IDs: []string{"soda"}}); err != nil {
+		t.Fatal(err)
+	}
+	if refs.calls != 0 {
+		t.Errorf("references checked %d times without a window", refs.calls)
+	}
+}
+
+func TestBulkDeleteTooLarge(t *testing.T) {
+	ids := make([]string, MaxBulkDelete+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("p%d", i)
+	}
+	svc := NewService(newMemoryRepository())
+	if _, err := svc.BulkDelete(context.Background(), BulkDeleteInput{IDs: ids}); !errors.Is(err, ErrBulkDeleteTooLarge) {
+		t.Errorf("err = %v, want %v", err, ErrBulkDeleteTooLarge)
+	}
+}
+
+func TestBulkDeleteOpenOrders(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         BulkDeleteInput
+		wantErr       error
+		wantCalls     int
+		wantRemaining []string
+	}{
+		{
+			name:          "hard delete refused",
+			input:         BulkDeleteInput{IDs: []string{"soda", "taco-1"}, Hard: true},
+			wantErr:       ErrProductInUse,
+			wantCalls:     2,
+			wantRemaining: bulkDeleteIDs,
+		},
+		{
+			name:          "force does not skip the check",
+			input:         BulkDeleteInput{IDs: []string{"taco-1"}, Hard: true, Force: true},
+			wantErr:       ErrProductInUse,
+			wantCalls:     1,
+			wantRemaining: bulkDeleteIDs,
+		},
+		{
+			name:          "hard delete of idle products",
+			input:         BulkDeleteInput{IDs: []string{"soda", "taco-2"}, Hard: true},
+			wantCalls:     2,
+			wantRemaining: []string{"other", "taco-1"},
+		},
+		{
+			name:          "soft delete is not checked",
+			input:         BulkDeleteInput{IDs: []string{"taco-1"}},
+			wantRemaining: []string{"other", "soda", "taco-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := bulkDeleteCatalog()
+			counter := &openOrderCounter{counts: map[string]int64{"taco-1": 1}}
+			svc := NewService(repo, WithOpenOrderCounter(counter))
+
+			if _, err := svc.BulkDelete(context.Background(), tt.input); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if counter.calls != tt.wantCalls {
+				t.Errorf("counter calls = %d, want %d", counter.calls, tt.wantCalls)
+			}
+			var remaining []string
+			for _, id := range bulkDeleteIDs {
+				if p := repo.stored(id); p != nil && !p.IsDeleted() {
+					remaining = append(remaining, id)
+				}
+			}
+			if !slices.Equal(remaining, tt.wantRemaining) {
+				t.Errorf("remaining = %v, want %v", remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
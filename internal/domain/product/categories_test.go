@@ -0,0 +1,68 @@
+package product
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestGetCategories(t *testing.T) {
+	product := func(name, category string, available bool) *Product {
+		p := NewProduct("company-1", "sp-1", name, category, "")
+		p.IsAvailable = available
+		return p
+	}
+	limits := util.PageLimits{DefaultLimit: 2, MaxLimit: 10}
+
+	tests := []struct {
+		name      string
+		filters   CategoryFilters
+		want      []string
+		wantTotal int64
+		wantLimit int
+	}{
+		{"first page", CategoryFilters{}, []string{"Bebidas", "Platos"}, 4, 2},
+		{"second page", CategoryFilters{Offset: 2}, []string{"Postres", "Sopas"}, 4, 2},
+		{"only with available drops empty categories", CategoryFilters{OnlyWithAvailable: true, Limit: 10}, []string{"Bebidas", "Platos"}, 2, 10},
+		{"all ignores the page", CategoryFilters{All: true, Limit: 1, Offset: 3}, []string{"Bebidas", "Platos", "Postres", "Sopas"}, 4, 0},
+		{"limit capped", CategoryFilters{Limit: 50}, []string{"Bebidas", "Platos", "Postres", "Sopas"}, 4, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(
+				product("Burger", "Platos", true),
+				product("Soda", "Bebidas", true),
+				product("Flan", "Postres", false), // Only unavailable products
+				product("Sancocho", "Sopas", false),
+			)
+			svc := NewService(repo, WithCompanyPageLimits(limits), WithSalePointPageLimits(limits))
+
+			for _, list := range []func() ([]CategorySummary, int64, error){
+				func() ([]CategorySummary, int64, error) {
+					return svc.GetCategoriesByCompanyID(context.Background(), "company-1", tt.filters)
+				},
+				func() ([]CategorySummary, int64, error) {
+					return svc.GetCategoriesBySalePointID(context.Background(), "sp-1", tt.filters)
+				},
+			} {
+				categories, total, err := list()
+				if err != nil {
+					t.Fatal(err)
+				}
+				var names []string
+				for _, c := range categories {
+					names = append(names, c.Name)
+				}
+				if !slices.Equal(names, tt.want) || total != tt.wantTotal {
+					t.Errorf("categories = %v (total %d), want %v (total %d)", names, total, tt.want, tt.wantTotal)
+				}
+			}
+			if got := repo.categoryFilters[0].Limit; got != tt.wantLimit {
+				t.Errorf("repository limit = %d, want %d", got, tt.wantLimit)
+			}
+		})
+	}
+}
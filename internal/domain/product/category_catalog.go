@@ -0,0 +1,59 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// CategoryCatalog lists the categories a sale point defines
+type CategoryCatalog interface {
+	// FindActiveNames retrieves the names of the active categories of a sale point
+	FindActiveNames(ctx context.Context, salePointID string) ([]string, error)
+}
+
+// WithCategoryCatalog makes products name an active category of their sale point.
+// Sale points without active categories accept any category.
+func WithCategoryCatalog(catalog CategoryCatalog) Option {
+	return func(s *Service) {
+		s.categories = catalog
+	}
+}
+
+// checkCategory rejects a category the sale point does not define
+func (s *Service) checkCategory(ctx context.Context, p *Product) error {
+	names, err := s.activeCategories(ctx, p.SalePointID)
+	if err != nil {
+		return err
+	}
+	return matchCategory(p, names)
+}
+
+// activeCategories returns the categories products of the sale point must use; none when any is allowed
+func (s *Service) activeCategories(ctx context.Context, salePointID string) ([]string, error) {
+	if s.categories == nil {
+		return nil, nil
+	}
+	names, err := s.categories.FindActiveNames(ctx, salePointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check category: %w", err)
+	}
+	return names, nil
+}
+
+// matchCategory checks the product's category against the allowed names, if any. A category matching
+// one in different case takes its spelling, so products don't split a category by case.
+func matchCategory(p *Product, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	for _, name := range names {
+		if strings.EqualFold(name, p.Category) {
+			p.Category = name
+			return nil
+		}
+	}
+	return fmt.Errorf("validation error: %w", apperrors.NewDomainError(ErrUnknownCategory, "category", p.Category))
+}
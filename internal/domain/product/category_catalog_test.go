@@ -0,0 +1,82 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// memoryCategoryCatalog maps sale point IDs to their active category names
+type memoryCategoryCatalog map[string][]string
+
+func (c memoryCategoryCatalog) FindActiveNames(ctx context.Context, salePointID string) ([]string, error) {
+	return c[salePointID], nil
+}
+
+func TestCategoryCatalogValidation(t *testing.T) {
+	ctx := context.Background()
+	catalog := memoryCategoryCatalog{"sale-point-1": {"Pizzas", "Drinks"}}
+
+	create := func(svc *Service, salePointID, category string) (*Product, error) {
+		return svc.Create(ctx, CreateInput{
+			CompanyID:        "company-1",
+			SalePointID:      salePointID,
+			Name:             "Pizza",
+			Category:         category,
+			PriceVariations:  []PriceVariation{{Type: "small", Price: 1000}},
+			IsUnlimitedStock: true,
+		})
+	}
+
+	tests := []struct {
+		name         string
+		opts         []Option
+		salePointID  string
+		category     string
+		wantCategory string
+		wantErr      error
+	}{
+		{"defined category", []Option{WithCategoryCatalog(catalog)}, "sale-point-1", "Pizzas", "Pizzas", nil},
+		{"different case takes the defined spelling", []Option{WithCategoryCatalog(catalog)}, "sale-point-1", "drinks", "Drinks", nil},
+		{"unknown category", []Option{WithCategoryCatalog(catalog)}, "sale-point-1", "Burgers", "", ErrUnknownCategory},
+		{"sale point without categories", []Option{WithCategoryCatalog(catalog)}, "sale-point-2", "Burgers", "Burgers", nil},
+		{"validation disabled", nil, "sale-point-1", "Burgers", "Burgers", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := create(NewService(newMemoryRepository(), tt.opts...), tt.salePointID, tt.category)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && p.Category != tt.wantCategory {
+				t.Errorf("category = %q, want %q", p.Category, tt.wantCategory)
+			}
+		})
+	}
+}
+
+func TestUpdateChecksChangedCategoryOnly(t *testing.T) {
+	ctx := context.Background()
+	// Stored before the sale point defined its categories
+	legacy := NewProduct("company-1", "sale-point-1", "Pizza", "Old menu", "")
+	legacy.PriceVariations = []PriceVariation{{Type: "small", Price: 1000}}
+	repo := newMemoryRepository(legacy)
+	svc := NewService(repo, WithCategoryCatalog(memoryCategoryCatalog{"sale-point-1": {"Pizzas"}}))
+
+	name := "Margherita"
+	if _, err := svc.Update(ctx, legacy.ID, UpdateInput{Name: &name}); err != nil {
+		t.Fatalf("update keeping the category error = %v", err)
+	}
+
+	unknown := "Burgers"
+	if _, err := svc.Update(ctx, legacy.ID, UpdateInput{Category: &unknown}); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("update to an unknown category error = %v, want %v", err, ErrUnknownCategory)
+	}
+
+	defined := "pizzas"
+	updated, err := svc.Update(ctx, legacy.ID, UpdateInput{Category: &defined})
+	if err != nil || updated.Category != "Pizzas" {
+		t.Errorf("update to a defined category = %v, %v; want category Pizzas", updated, err)
+	}
+}
@@ -0,0 +1,89 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// CategoryRenameInput renames a category on every product of a company, or of one of its sale points
+type CategoryRenameInput struct {
+	CompanyID   string
+	SalePointID string // Optional: only rename within this sale point
+	From        string
+	To          string
+	Merge       bool // Also fold categories that differ from To only in case into To
+}
+
+// CategoryRenameResult reports the outcome of a category rename
+type CategoryRenameResult struct {
+	Renamed int64    `json:"renamed"` // Products whose category changed
+	Merged  []string `json:"merged"`  // Differently cased categories folded into the new name
+}
+
+// CategoryScope selects the products of a category rename
+type CategoryScope struct {
+	CompanyID   string
+	SalePointID string // Optional
+}
+
+// RenameCategory sets the new category on every product in the category, soft deleted ones included.
+// Renaming into a category that only differs in case from an existing one would leave both around,
+// so it fails with ErrCategoryCaseConflict unless Merge is set, which renames those products too.
+func (s *Service) RenameCategory(ctx context.Context, input CategoryRenameInput) (*CategoryRenameResult, error) {
+	input.From = strings.TrimSpace(input.From)
+	input.To = strings.TrimSpace(input.To)
+	if input.CompanyID == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidCompanyID, "company_id", nil)
+	}
+	if input.From == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidCategory, "from", nil)
+	}
+	if input.To == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidCategory, "to", nil)
+	}
+
+	scope := CategoryScope{CompanyID: input.CompanyID, SalePointID: input.SalePointID}
+	conflicts, err := s.caseConflicts(ctx, scope, input.From, input.To)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && !input.Merge {
+		return &CategoryRenameResult{Merged: conflicts}, ErrCategoryCaseConflict
+	}
+
+	renamed, err := s.repo.RenameCategory(ctx, scope, append([]string{input.From}, conflicts...), input.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename category: %w", err)
+	}
+	if conflicts == nil {
+		conflicts = []string{}
+	}
+	return &CategoryRenameResult{Renamed: renamed, Merged: conflicts}, nil
+}
+
+// caseConflicts returns the categories of the scope, other than from, that equal to ignoring case only
+func (s *Service) caseConflicts(ctx context.Context, scope CategoryScope, from, to string) ([]string, error) {
+	var (
+		categories []CategorySummary
+		err        error
+	)
+	if scope.SalePointID != "" {
+		categories, _, err = s.repo.FindCategoriesBySalePointID(ctx, scope.SalePointID, CategoryFilters{All: true})
+	} else {
+		categories, _, err = s.repo.FindCategoriesByCompanyID(ctx, scope.CompanyID, CategoryFilters{All: true})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	var conflicts []string
+	for _, category := range categories {
+		if category.Name != from && category.Name != to && strings.EqualFold(category.Name, to) {
+			conflicts = append(conflicts, category.Name)
+		}
+	}
+	return conflicts, nil
+}
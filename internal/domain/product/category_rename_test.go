@@ -0,0 +1,112 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRenameCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          CategoryRenameInput
+		wantErr        error
+		wantRenamed    int64
+		wantMerged     []string
+		wantCategories []string // Categories of company-1 afterwards
+	}{
+		{
+			name:           "rename",
+			input:          CategoryRenameInput{From: "Bebidas", To: "Drinks"},
+			wantRenamed:    2,
+			wantMerged:     []string{},
+			wantCategories: []string{"Drinks", "Postres", "postres"},
+		},
+		{
+			name:           "into an existing category",
+			input:          CategoryRenameInput{From: "Bebidas", To: "Postres"},
+			wantErr:        ErrCategoryCaseConflict,
+			wantMerged:     []string{"postres"},
+			wantCategories: []string{"Bebidas", "Postres", "postres"},
+		},
+		{
+			name:           "case collision",
+			input:          CategoryRenameInput{From: "Bebidas", To: "POSTRES"},
+			wantErr:        ErrCategoryCaseConflict,
+			wantMerged:     []string{"Postres", "postres"},
+			wantCategories: []string{"Bebidas", "Postres", "postres"},
+		},
+		{
+			name:           "merge",
+			input:          CategoryRenameInput{From: "Bebidas", To: "Postres", Merge: true},
+			wantRenamed:    3,
+			wantMerged:     []string{"postres"},
+			wantCategories: []string{"Postres"},
+		},
+		{
+			name:           "case fix",
+			input:          CategoryRenameInput{From: "postres", To: "Postres"},
+			wantRenamed:    1,
+			wantMerged:     []string{},
+			wantCategories: []string{"Bebidas", "Postres"},
+		},
+		{
+			name:           "sale point only",
+			input:          CategoryRenameInput{SalePointID: "sp-2", From: "Bebidas", To: "Drinks"},
+			wantRenamed:    1,
+			wantMerged:     []string{},
+			wantCategories: []string{"Bebidas", "Drinks", "Postres", "postres"},
+		},
+		{
+			name:           "unknown category",
+			input:          CategoryRenameInput{From: "Sopas", To: "Soups"},
+			wantMerged:     []string{},
+			wantCategories: []string{"Bebidas", "Postres", "postres"},
+		},
+		{name: "missing target", input: CategoryRenameInput{From: "Bebidas", To: "  "}, wantErr: ErrInvalidCategory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(
+				NewProduct("company-1", "sp-1", "Soda", "Bebidas", ""),
+				NewProduct("company-1", "sp-2", "Juice", "Bebidas", ""),
+				NewProduct("company-1", "sp-1", "Flan", "Postres", ""),
+				NewProduct("company-1", "sp-2", "Cake", "postres", ""),
+				NewProduct("company-2", "sp-3", "Water", "Bebidas", ""),
+			)
+			svc := NewService(repo)
+
+			tt.input.CompanyID = "company-1"
+			result, err := svc.RenameCategory(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if result != nil && (result.Renamed != tt.wantRenamed || !reflect.DeepEqual(result.Merged, tt.wantMerged)) {
+				t.Errorf("result = %+v, want %d renamed and %q merged", result, tt.wantRenamed, tt.wantMerged)
+			}
+			if tt.wantCategories == nil {
+				return
+			}
+
+			// The categories endpoint reads the products, so it reflects the rename at once
+			categories, _, err := svc.GetCategoriesByCompanyID(context.Background(), "company-1", CategoryFilters{All: true})
+			if err != nil {
+				t.Fatalf("GetCategoriesByCompanyID() error = %v", err)
+			}
+			names := make([]string, len(categories))
+			for i, c := range categories {
+				names[i] = c.Name
+			}
+			if !reflect.DeepEqual(names, tt.wantCategories) {
+				t.Errorf("categories = %q, want %q", names, tt.wantCategories)
+			}
+
+			other, _, _ := svc.GetCategoriesByCompanyID(context.Background(), "company-2", CategoryFilters{All: true})
+			if len(other) != 1 || other[0].Name != "Bebidas" {
+				t.Errorf("another company's categories changed: %+v", other)
+			}
+		})
+	}
+}
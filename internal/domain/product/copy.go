@@ -0,0 +1,251 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/google/uuid"
+)
+
+// CopyBatchSize is the number of products a sale point copy writes at a time
+const CopyBatchSize = 100
+
+// CopyInput copies the live products of one sale point into another
+type CopyInput struct {
+	FromSalePointID string
+	ToSalePointID   string
+	Overwrite       bool // Update target products with the name of a copied one instead of skipping them
+}
+
+// CopyResult reports the outcome of a copy
+type CopyResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"` // Source products whose name or SKU is taken in the target, whose category it lacks, or whose target product changed meanwhile
+}
+
+// Validate checks that both sale points are given and differ
+func (input CopyInput) Validate() error {
+	switch {
+	case input.FromSalePointID == "":
+		return apperrors.NewDomainError(ErrInvalidSalePointID, "from_sale_point_id", nil)
+	case input.ToSalePointID == "":
+		return apperrors.NewDomainError(ErrInvalidSalePointID, "to_sale_point_id", nil)
+	case input.FromSalePointID == input.ToSalePointID:
+		return apperrors.NewDomainError(ErrCopySameSalePoint, "to_sale_point_id", input.ToSalePointID)
+	}
+	return nil
+}
+
+// copyUpdate is a target product being overwritten and what it had before
+type copyUpdate struct {
+	product          *Product
+	previousPrices   []PriceVariation
+	previousCategory string
+}
+
+// salePointCopy holds the state of a copy while the source products stream in
+type salePointCopy struct {
+	input      CopyInput
+	categories []string            // Active categories of the target; none when any is allowed
+	companyID  string              // Company of the target's products, empty when it has none
+	byName     map[string]*Product // Target products by copyKey
+	skus       map[string]string   // Product ID by SKU in the target
+	seen       map[string]bool     // copyKeys of the source products handled so far
+	creates    []*Product
+	updates    []copyUpdate
+	result     CopyResult
+}
+
+// Copy copies the live products of a sale point into another, in the source's menu order. Copies get
+// new IDs and are placed last in their categories; a target whose products belong to another company
+// is rejected with ErrCopyCompanyMismatch. A product whose name, ignoring case and spacing,
+// is taken in the target is skipped, or with Overwrite updates the target product in place.
+// Barcodes are unique within the company, so copies have none and overwritten products keep theirs.
+// Products are read through a single cursor and written in batches of CopyBatchSize, so a failure
+// leaves the batches before it written. Overwrites that change prices are written one at a time when
+// price history is kept, and only those written are recorded.
+func (s *Service) Copy(ctx context.Context, input CopyInput) (*CopyResult, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.activeCategories(ctx, input.ToSalePointID)
+	if err != nil {
+		return nil, err
+	}
+	c := &salePointCopy{
+		input:      input,
+		categories: categories,
+		byName:     make(map[string]*Product),
+		skus:       make(map[string]string),
+		seen:       make(map[string]bool),
+	}
+
+	err = s.repo.EachBySalePointID(ctx, input.ToSalePointID, ProductFilters{}, func(p *Product) error {
+		c.companyID = p.CompanyID
+		c.byName[copyKey(p.Name)] = p
+		if p.SKU != nil {
+			c.skus[*p.SKU] = p.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target products: %w", err)
+	}
+
+	now := time.Now()
+	err = s.repo.EachBySalePointID(ctx, input.FromSalePointID, ProductFilters{}, func(p *Product) error {
+		if c.companyID != "" && p.CompanyID != c.companyID {
+			return apperrors.NewDomainError(ErrCopyCompanyMismatch, "to_sale_point_id", input.ToSalePointID)
+		}
+		c.add(p, now)
+		if len(c.creates)+len(c.updates) >= CopyBatchSize {
+			return s.flushCopy(ctx, c)
+		}
+		return nil
+	})
+	if err == nil {
+		err = s.flushCopy(ctx, c)
+	}
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy products: %w", err)
+	}
+	return &c.result, nil
+}
+
+// add queues the copy of a source product, or counts it as skipped
+func (c *salePointCopy) add(source *Product, now time.Time) {
+	key := copyKey(source.Name)
+	if c.seen[key] {
+		c.result.Skipped++
+		return
+	}
+	c.seen[key] = true
+
+	target, exists := c.byName[key]
+	if exists && !c.input.Overwrite {
+		c.result.Skipped++
+		return
+	}
+	if source.SKU != nil {
+		if owner, taken := c.skus[*source.SKU]; taken && (!exists || owner != target.ID) {
+			c.result.Skipped++
+			return
+		}
+	}
+
+	copied := source
+	if err := matchCategory(copied, c.categories); err != nil {
+		c.result.Skipped++
+		return
+	}
+
+	if !exists {
+		copied.ID = uuid.New().String()
+		copied.SalePointID = c.input.ToSalePointID
+		copied.CreatedAt = now
+		copied.UpdatedAt = now
+		copied.DeletedAt = nil
+		copied.Barcode = nil
+		copied.Version = 1
+		c.claimSKU(copied)
+		c.creates = append(c.creates, copied)
+		return
+	}
+
+	update := copyUpdate{product: copied, previousPrices: target.PriceVariations, previousCategory: target.Category}
+	copied.ID = target.ID
+	copied.CompanyID = target.CompanyID
+	copied.SalePointID = target.SalePointID
+	copied.Position = target.Position
+	copied.CreatedAt = target.CreatedAt
+	copied.DeletedAt = nil
+	copied.Barcode = target.Barcode
+	copied.Version = target.Version
+	if target.SKU != nil {
+		delete(c.skus, *target.SKU)
+	}
+	c.claimSKU(copied)
+	c.updates = append(c.updates, update)
+}
+
+// claimSKU records the SKU of a product queued for the target
+func (c *salePointCopy) claimSKU(p *Product) {
+	if p.SKU != nil {
+		c.skus[*p.SKU] = p.ID
+	}
+}
+
+// flushCopy writes the queued updates, then the queued creates, and adds them to the result.
+// Updates go first, so products moved to another category and new products share its end.
+func (s *Service) flushCopy(ctx context.Context, c *salePointCopy) error {
+	if len(c.updates) > 0 {
+		// A bulk write only reports how many products matched, not which. Updates that change prices
+		// are written one by one instead, so the price history only records the ones written.
+		var moved, batch []*Product
+		var priced []copyUpdate
+		for _, update := range c.updates {
+			if update.product.Category != update.previousCategory {
+				moved = append(moved, update.product)
+			}
+			if s.priceHistory != nil && !DiffPriceVariations(update.previousPrices, update.product.PriceVariations).IsEmpty() {
+				priced = append(priced, update)
+			} else {
+				batch = append(batch, update.product)
+			}
+		}
+		if err := s.placeAllLast(ctx, moved); err != nil {
+			return err
+		}
+		if len(batch) > 0 {
+			matched, err := s.repo.UpdateMany(ctx, batch)
+			if err != nil {
+				return err
+			}
+			// Target products edited since they were loaded keep the edit
+			c.result.Updated += int(matched)
+			c.result.Skipped += len(batch) - int(matched)
+		}
+		for _, update := range priced {
+			err := s.repo.Update(ctx, update.product)
+			if errors.Is(err, ErrProductConflict) || errors.Is(err, ErrProductNotFound) {
+				c.result.Skipped++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			c.result.Updated++
+			if err := s.recordPriceChange(ctx, update.product, update.previousPrices, ""); err != nil {
+				return err
+			}
+		}
+		c.updates = nil
+	}
+
+	if len(c.creates) > 0 {
+		if err := s.placeAllLast(ctx, c.creates); err != nil {
+			return err
+		}
+		if err := s.repo.CreateMany(ctx, c.creates); err != nil {
+			return err
+		}
+		c.result.Created += len(c.creates)
+		c.creates = nil
+	}
+	return nil
+}
+
+// copyKey is the name products are matched by across sale points: lowercase with single spaces
+func copyKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
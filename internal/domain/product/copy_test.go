@@ -0,0 +1,269 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// copySource is a sale point with a small menu: two pizzas, a drink and a soft deleted product
+func copySource() []*Product {
+	sku := "PZ-MARG"
+	deletedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	return []*Product{
+		{ID: "marg", CompanyID: "c1", SalePointID: "sp1", SKU: &sku, Name: "Margarita", Category: "Pizzas", Position: 1,
+			PriceVariations: []PriceVariation{{Type: "Normal", Price: 20000}}, IsAvailable: true, IsUnlimitedStock: true},
+		{ID: "hawa", CompanyID: "c1", SalePointID: "sp1", Name: "Hawaiana", Category: "Pizzas", Position: 0,
+			PriceVariations: []PriceVariation{{Type: "Normal", Price: 22000}}, IsAvailable: true, IsUnlimitedStock: true},
+		{ID: "lemo", CompanyID: "c1", SalePointID: "sp1", Name: "Limonada", Category: "Drinks",
+			PriceVariations: []PriceVariation{{Type: "Normal", Price: 5000}}, IsAvailable: true, IsUnlimitedStock: true},
+		{ID: "gone", CompanyID: "c1", SalePointID: "sp1", Name: "Calzone", Category: "Pizzas", Position: 2, DeletedAt: &deletedAt,
+			PriceVariations: []PriceVariation{{Type: "Normal", Price: 25000}}, IsAvailable: true, IsUnlimitedStock: true},
+	}
+}
+
+// salePointProducts returns the live products of the sale point in menu order
+func salePointProducts(t *testing.T, repo *memoryRepository, salePointID string) []*Product {
+	t.Helper()
+	var products []*Product
+	err := repo.EachBySalePointID(context.Background(), salePointID, ProductFilters{}, func(p *Product) error {
+		products = append(products, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return products
+}
+
+func TestCopyIntoEmptySalePoint(t *testing.T) {
+	repo := newMemoryRepository(copySource()...)
+
+	result, err := NewService(repo).Copy(context.Background(), CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (CopyResult{Created: 3}) {
+		t.Errorf("result = %+v, want 3 created", *result)
+	}
+
+	var got []string
+	for _, p := range salePointProducts(t, repo, "sp2") {
+		if slices.Contains([]string{"marg", "hawa", "lemo"}, p.ID) {
+			t.Errorf("%s kept its source ID", p.Name)
+		}
+		if p.CompanyID != "c1" {
+			t.Errorf("%s company = %q, want c1", p.Name, p.CompanyID)
+		}
+		got = append(got, fmt.Sprintf("%s %s %d", p.Category, p.Name, p.Position))
+	}
+	want := []string{"Drinks Limonada 0", "Pizzas Hawaiana 0", "Pizzas Margarita 1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("target menu = %v, want %v", got, want)
+	}
+	if len(salePointProducts(t, repo, "sp1")) != 3 {
+		t.Error("the source products changed")
+	}
+}
+
+func TestCopyIntoExistingSalePoint(t *testing.T) {
+	otherSKU := "PZ-MARG"
+	existing := []*Product{
+		{ID: "t-marg", CompanyID: "c1", SalePointID: "sp2", Name: "  MARGARITA ", Category: "Pizzas", Position: 5,
+			PriceVariations: []PriceVariation{{Type: "Normal", Price: 18000}}, IsAvailable: true, IsUnlimitedStock: true},
+		{ID: "t-soda", CompanyID: "c1", SalePointID: "sp2", SKU: &otherSKU, Name: "Soda", Category: "Drinks",
+			PriceVariations: []PriceVariation{{Type: "Normal", Price: 3000}}, IsAvailable: true, IsUnlimitedStock: true},
+	}
+
+	tests := []struct {
+		name       string
+		existing   []*Product
+		overwrite  bool
+		want       CopyResult
+		wantPrice  int64  // Of the target's Margarita
+		wantTarget string // ID of the target's Margarita
+	}{
+		{"skips taken names", existing[:1], false, CopyResult{Created: 2, Skipped: 1}, 18000, "t-marg"},
+		{"overwrites taken names", existing[:1], true, CopyResult{Created: 2, Updated: 1}, 20000, "t-marg"},
+		{"skips taken skus", existing[1:], true, CopyResult{Created: 2, Skipped: 1}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(append(copySource(), tt.existing...)...)
+			history := &memoryPriceHistory{}
+
+			result, err := NewService(repo, WithPriceHistory(history)).Copy(context.Background(),
+				CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp2", Overwrite: tt.overwrite})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *result != tt.want {
+				t.Errorf("result = %+v, want %+v", *result, tt.want)
+			}
+
+			var margaritas []*Product
+			for _, p := range salePointProducts(t, repo, "sp2") {
+				if copyKey(p.Name) == "margarita" {
+					margaritas = append(margaritas, p)
+				}
+			}
+			if tt.wantTarget == "" {
+				if len(margaritas) != 0 {
+					t.Errorf("copied a product with a taken SKU: %+v", margaritas[0])
+				}
+				return
+			}
+			if len(margaritas) != 1 {
+				t.Fatalf("target has %d margaritas, want 1", len(margaritas))
+			}
+			if m := margaritas[0]; m.ID != tt.wantTarget || m.Position != 5 || m.PriceVariations[0].Price != tt.wantPrice {
+				t.Errorf("target margarita = %s at %d for %d, want %s at 5 for %d",
+					m.ID, m.Position, m.PriceVariations[0].Price, tt.wantTarget, tt.wantPrice)
+			}
+			if tt.overwrite && (len(history.changes) != 1 || history.changes[0].ProductID != "t-marg") {
+				t.Errorf("price changes = %+v, want the overwritten margarita's", history.changes)
+			}
+		})
+	}
+}
+
+// racingRepository edits a product right before the first update is written, like an admin
+// saving it while a copy is running
+type racingRepository struct {
+	*memoryRepository
+	editID string
+	edited bool
+}
+
+func (r *racingRepository) edit() {
+	if r.edited {
+		return
+	}
+	r.edited = true
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.products[r.editID]
+	p.PriceVariations = []PriceVariation{{Type: "Normal", Price: 30000}}
+	p.Version++
+}
+
+func (r *racingRepository) Update(ctx context.Context, p *Product) error {
+	r.edit()
+	return r.memoryRepository.Update(ctx, p)
+}
+
+func (r *racingRepository) UpdateMany(ctx context.Context, products []*Product) (int64, error) {
+	r.edit()
+	return r.memoryRepository.UpdateMany(ctx, products)
+}
+
+func TestCopyOverwriteWithConcurrentEdit(t *testing.T) {
+	targets := []*Product{
+		{ID: "t-marg", CompanyID: "c1", SalePointID: "sp2", Name: "Margarita", Category: "Pizzas",
+			PriceVariations: []PriceVariation{{Type: "Normal", Price: 18000}}, IsAvailable: true, IsUnlimitedStock: true},
+		{ID: "t-hawa", CompanyID: "c1", SalePointID: "sp2", Name: "Hawaiana", Category: "Pizzas", Position: 1,
+			PriceVariations: []PriceVariation{{Type: "Normal", Price: 19000}}, IsAvailable: true, IsUnlimitedStock: true},
+	}
+	repo := &racingRepository{memoryRepository: newMemoryRepository(append(copySource(), targets...)...), editID: "t-marg"}
+	history := &memoryPriceHistory{}
+
+	result, err := NewService(repo, WithPriceHistory(history)).Copy(context.Background(),
+		CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp2", Overwrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (CopyResult{Created: 1, Updated: 1, Skipped: 1}); *result != want {
+		t.Errorf("result = %+v, want %+v", *result, want)
+	}
+
+	// The concurrent edit wins and only the written update is in the price history
+	if price := repo.stored("t-marg").PriceVariations[0].Price; price != 30000 {
+		t.Errorf("edited margarita costs %d, want the concurrent edit's 30000", price)
+	}
+	if price := repo.stored("t-hawa").PriceVariations[0].Price; price != 22000 {
+		t.Errorf("hawaiana costs %d, want the copied 22000", price)
+	}
+	if len(history.changes) != 1 || history.changes[0].ProductID != "t-hawa" {
+		t.Errorf("price changes = %+v, want only the hawaiana's", history.changes)
+	}
+}
+
+func TestCopyWritesInBatches(t *testing.T) {
+	var products []*Product
+	for i := range 250 {
+		products = append(products, &Product{
+			ID: fmt.Sprintf("p%03d", i), CompanyID: "c1", SalePointID: "sp1", Name: fmt.Sprintf("Product %d", i),
+			Category: "Menu", Position: i, IsAvailable: true, IsUnlimitedStock: true,
+		})
+	}
+	repo := newMemoryRepository(products...)
+
+	result, err := NewService(repo).Copy(context.Background(), CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 250 {
+		t.Errorf("created = %d, want 250", result.Created)
+	}
+	if want := []int{100, 100, 50}; !slices.Equal(repo.batches, want) {
+		t.Errorf("batches = %v, want %v", repo.batches, want)
+	}
+
+	copied := salePointProducts(t, repo, "sp2")
+	for i, p := range copied {
+		if p.Position != i || p.Name != fmt.Sprintf("Product %d", i) {
+			t.Fatalf("copy %d is %s at %d, want the source order", i, p.Name, p.Position)
+		}
+	}
+}
+
+func TestCopyRejections(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   CopyInput
+		wantErr error
+	}{
+		{"no source", CopyInput{ToSalePointID: "sp2"}, ErrInvalidSalePointID},
+		{"no target", CopyInput{FromSalePointID: "sp1"}, ErrInvalidSalePointID},
+		{"same sale point", CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp1"}, ErrCopySameSalePoint},
+		{"another company", CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp3"}, ErrCopyCompanyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(append(copySource(),
+				&Product{ID: "other", CompanyID: "c2", SalePointID: "sp3", Name: "Arepa", Category: "Arepas"})...)
+
+			_, err := NewService(repo).Copy(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.batches) != 0 {
+				t.Errorf("wrote %v after a rejected copy", repo.batches)
+			}
+		})
+	}
+}
+
+func TestCopySkipsCategoriesTheTargetLacks(t *testing.T) {
+	repo := newMemoryRepository(copySource()...)
+	catalog := memoryCategoryCatalog{"sp2": {"PIZZAS"}}
+
+	result, err := NewService(repo, WithCategoryCatalog(catalog)).Copy(context.Background(),
+		CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (CopyResult{Created: 2, Skipped: 1}) {
+		t.Errorf("result = %+v, want 2 created and the drink skipped", *result)
+	}
+	for _, p := range salePointProducts(t, repo, "sp2") {
+		if p.Category != "PIZZAS" {
+			t.Errorf("%s category = %q, want the target's spelling", p.Name, p.Category)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestGetByCompanyIDCursorSkipsCountAndOffset(t *testing.T) {
+	repo := newMemoryRepository()
+	_, total, err := NewService(repo).GetByCompanyID(context.Background(), "c1", ProductFilters{Cursor: &util.PageCursor{}, Offset: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != -1 {
+		t.Errorf("total = %d, want -1", total)
+	}
+	if listed := repo.listed[0]; listed.Offset != 0 || !listed.SkipCount {
+		t.Errorf("repository got offset %d and skip count %v, want 0 and true", listed.Offset, listed.SkipCount)
+	}
+}
+
+// TestCursorIterationWhileInserting walks a company's products page by page while new products are
+// being created, and checks every product that existed at the start is listed exactly once
+func TestCursorIterationWhileInserting(t *testing.T) {
+	const existing, pageSize = 53, 7
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := newMemoryRepository()
+	for i := 0; i < existing; i++ {
+		// Pairs share a creation time, so ties are broken by ID
+		repo.products[fmt.Sprintf("p%02d", i)] = &Product{ID: fmt.Sprintf("p%02d", i), CompanyID: "c1", CreatedAt: start.Add(time.Duration(i/2) * time.Second)}
+	}
+	svc := NewService(repo)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			p := &Product{ID: fmt.Sprintf("new%03d", i), CompanyID: "c1", CreatedAt: start.Add(time.Hour + time.Duration(i)*time.Millisecond)}
+			if err := repo.Create(context.Background(), p); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	seen := make(map[string]int)
+	cursor := &util.PageCursor{}
+	for pages := 0; ; pages++ {
+		if pages > existing {
+			t.Fatal("iteration does not end")
+		}
+		page, _, err := svc.GetByCompanyID(context.Background(), "c1", ProductFilters{Cursor: cursor, Limit: pageSize})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range page {
+			seen[p.ID]++
+		}
+		if len(page) < pageSize {
+			break
+		}
+		next := page[len(page)-1].PageCursor()
+		cursor = &next
+	}
+	close(stop)
+	wg.Wait()
+
+	for i := 0; i < existing; i++ {
+		if id := fmt.Sprintf("p%02d", i); seen[id] != 1 {
+			t.Errorf("%s listed %d times, want once", id, seen[id])
+		}
+	}
+	for id, n := range seen {
+		if n > 1 {
+			t.Errorf("%s listed %d times", id, n)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDelete(t *testing.T) {
+	deletedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		deleted     bool // The product was soft deleted before
+		hard        bool
+		wantErr     error
+		wantStored  bool
+		wantDeleted bool
+	}{
+		{"soft delete keeps the document", false, false, nil, true, true},
+		{"hard delete removes it", false, true, nil, false, false},
+		{"hard delete of a soft deleted product", true, true, nil, false, false},
+		{"soft deleted twice", true, false, ErrProductNotFound, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Product{ID: "p1", CompanyID: "c1", Name: "Hamburguesa"}
+			if tt.deleted {
+				p.DeletedAt = &deletedAt
+			}
+			repo := newMemoryRepository(p)
+			svc := NewService(repo)
+
+			if err := svc.Delete(context.Background(), "p1", tt.hard); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			stored := repo.stored("p1")
+			if (stored != nil) != tt.wantStored {
+				t.Fatalf("stored = %v, want stored %v", stored, tt.wantStored)
+			}
+			if stored != nil && stored.IsDeleted() != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", stored.IsDeleted(), tt.wantDeleted)
+			}
+			if tt.wantDeleted {
+				if _, err := svc.GetByID(context.Background(), "p1"); !errors.Is(err, ErrProductNotFound) {
+					t.Errorf("soft deleted product found: %v", err)
+				}
+			}
+		})
+	}
+
+	if err := NewService(newMemoryRepository()).Delete(context.Background(), "", false); err == nil {
+		t.Error("empty ID accepted")
+	}
+}
+
+// openOrderCounter is an OpenOrderCounter with fixed counts by product ID
+type openOrderCounter struct {
+	counts map[string]int64
+	err    error
+	calls  int
+}
+
+func (c *openOrderCounter) CountOpenOrdersWithProduct(ctx context.Context, productID string) (int64, error) {
+	c.calls++
+	return c.counts[productID], c.err
+}
+
+func TestDeleteProductInOpenOrders(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		hard       bool
+		counterErr error
+		wantErr    error
+		wantStored bool
+		wantCalls  int
+	}{
+		{"hard delete refused", "busy", true, nil, ErrProductInUse, true, 1},
+		{"hard delete of an idle product", "idle", true, nil, nil, false, 1},
+		{"soft delete is not checked", "busy", false, nil, nil, true, 0},
+		{"counter failure keeps the product", "idle", true, errors.New("connection refused"), nil, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(&Product{ID: "busy", Name: "Hamburguesa"}, &Product{ID: "idle", Name: "Limonada"})
+			counter := &openOrderCounter{counts: map[string]int64{"busy": 2}, err: tt.counterErr}
+			svc := NewService(repo, WithOpenOrderCounter(counter))
+
+			err := svc.Delete(context.Background(), tt.id, tt.hard)
+			switch {
+			case tt.counterErr != nil:
+				if !errors.Is(err, tt.counterErr) {
+					t.Fatalf("err = %v, want %v", err, tt.counterErr)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if stored := repo.stored(tt.id); (stored != nil) != tt.wantStored {
+				t.Errorf("stored = %v, want stored %v", stored, tt.wantStored)
+			}
+			if counter.calls != tt.wantCalls {
+				t.Errorf("counter calls = %d, want %d", counter.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	deletedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr error
+	}{
+		{"soft deleted product", "deleted", nil},
+		{"live product", "live", nil},
+		{"unknown product", "missing", ErrProductNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(
+				&Product{ID: "deleted", Name: "Hamburguesa", DeletedAt: &deletedAt},
+				&Product{ID: "live", Name: "Limonada"},
+			)
+
+			p, err := NewService(repo).Restore(context.Background(), tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if p.ID != tt.id || p.IsDeleted() {
+				t.Errorf("restored = %+v, want %s live", p, tt.id)
+			}
+			if repo.stored(tt.id).IsDeleted() {
+				t.Error("stored product still deleted")
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// jsonSize stands in for the BSON size the repository measures
+func jsonSize(doc interface{}) (int, error) {
+	b, err := json.Marshal(doc)
+	return len(b), err
+}
+
+// photos returns n distinct photo URLs
+func photos(n int) []string {
+	urls := make([]string, n)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://cdn.example.com/products/%04d.jpg", i)
+	}
+	return urls
+}
+
+func TestUpdateRejectsOversizedProducts(t *testing.T) {
+	const maxBytes = 4096
+
+	tests := []struct {
+		name    string
+		photos  []string
+		addons  []Addon
+		wantErr error
+	}{
+		{"few photos", photos(3), nil, nil},
+		{"huge photo array", photos(200), nil, ErrProductTooLarge},
+		{"huge addon array", nil, []Addon{{Name: "Queso", Photos: photos(200)}}, ErrProductTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithDocumentSizeLimit(util.DocumentSizeLimit{MaxBytes: maxBytes, Size: jsonSize}))
+			p, err := svc.Create(context.Background(), testInput("Original"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			photos, addons := tt.photos, tt.addons
+			_, err = svc.Update(context.Background(), p.ID, UpdateInput{Photos: &photos, AvailableAddons: &addons})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if !strings.Contains(err.Error(), fmt.Sprintf("max %d", maxBytes)) {
+				t.Errorf("error %q does not name the limit", err)
+			}
+			if stored := repo.stored(p.ID); len(stored.Photos) != 0 || len(stored.AvailableAddons) != 0 {
+				t.Error("oversized product was stored")
+			}
+		})
+	}
+}
+
+func TestCreateRejectsOversizedProducts(t *testing.T) {
+	repo := newMemoryRepository()
+	svc := NewService(repo, WithDocumentSizeLimit(util.DocumentSizeLimit{MaxBytes: 4096, Size: jsonSize}))
+	input := testInput("")
+	input.Photos = photos(200)
+
+	if _, err := svc.Create(context.Background(), input); !errors.Is(err, ErrProductTooLarge) {
+		t.Fatalf("err = %v, want %v", err, ErrProductTooLarge)
+	}
+	if len(repo.products) != 0 {
+		t.Error("oversized product was stored")
+	}
+}
@@ -1,28 +1,50 @@
 package product
 
 import (
+	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/util"
 	"github.com/google/uuid"
 )
 
+const (
+	// MaxDescriptionLength is the maximum number of characters for a product description
+	MaxDescriptionLength = 1000
+	// MaxQuickObservations is the maximum number of quick observations per product
+	MaxQuickObservations = 10
+	// MaxQuickObservationLength is the maximum number of characters for a quick observation
+	MaxQuickObservationLength = 50
+)
+
 // Product represents a product in the system with all its variations and addons
 type Product struct {
-	ID               string           `json:"id" bson:"_id"`
-	CompanyID        string           `json:"company_id" bson:"company_id"`
-	SalePointID      string           `json:"sale_point_id" bson:"sale_point_id"`
-	Name             string           `json:"name" bson:"name"`
-	Photos           []string         `json:"photos" bson:"photos"`
-	PriceVariations  []PriceVariation `json:"price_variations" bson:"price_variations"`
-	Category         string           `json:"category" bson:"category"`
-	Description      string           `json:"description" bson:"description"`
-	IsAddon          bool             `json:"is_addon" bson:"is_addon"`
-	IsAvailable      bool             `json:"is_available" bson:"is_available"`
-	IsUnlimitedStock bool             `json:"is_unlimited_stock" bson:"is_unlimited_stock"`
-	Stock            *int             `json:"stock" bson:"stock"` // Pointer to allow null
-	AvailableAddons  []Addon          `json:"available_addons" bson:"available_addons"`
-	CreatedAt        time.Time        `json:"created_at" bson:"created_at"`
-	UpdatedAt        time.Time        `json:"updated_at" bson:"updated_at"`
+	ID                   string                `json:"id" bson:"_id"`
+	CompanyID            string                `json:"company_id" bson:"company_id"`
+	SalePointID          string                `json:"sale_point_id" bson:"sale_point_id"`
+	SKU                  *string               `json:"sku" bson:"sku"`         // Internal POS code, unique per sale point; stored as null when unset
+	Barcode              *string               `json:"barcode" bson:"barcode"` // Scanned code, unique per company; stored as null when unset
+	Name                 string                `json:"name" bson:"name"`
+	Photos               []string              `json:"photos" bson:"photos"`
+	PriceVariations      []PriceVariation      `json:"price_variations" bson:"price_variations"`
+	Category             string                `json:"category" bson:"category"`
+	Position             int                   `json:"position" bson:"position"` // Display order within the category, lowest first
+	Description          string                `json:"description" bson:"description"`
+	IsAddon              bool                  `json:"is_addon" bson:"is_addon"`
+	IsAvailable          bool                  `json:"is_available" bson:"is_available"`
+	IsUnlimitedStock     bool                  `json:"is_unlimited_stock" bson:"is_unlimited_stock"`
+	Stock                *int                  `json:"stock" bson:"stock"` // Pointer to allow null
+	AvailableAddons      []Addon               `json:"available_addons" bson:"available_addons"`
+	QuickObservations    []string              `json:"quick_observations" bson:"quick_observations"`       // Predefined observations, e.g. "No onion"
+	Tags                 []string              `json:"tags" bson:"tags"`                                   // Lowercase labels across categories, e.g. "vegan"
+	AvailabilitySchedule *AvailabilitySchedule `json:"availability_schedule" bson:"availability_schedule"` // Weekly ordering windows; nil means any time
+	CreatedAt            time.Time             `json:"created_at" bson:"created_at"`
+	UpdatedAt            time.Time             `json:"updated_at" bson:"updated_at"`
+	DeletedAt            *time.Time            `json:"deleted_at,omitempty" bson:"deleted_at"` // Set by soft deletes; stored as null for live products
+	Version              int64                 `json:"version" bson:"version"`                 // Incremented on every update, see checkVersion
 }
 
 // PriceVariation represents a variation of the product with different pricing
@@ -51,21 +73,24 @@ type Addon struct {
 func NewProduct(companyID, salePointID, name, category, description string) *Product {
 	now := time.Now()
 	return &Product{
-		ID:               uuid.New().String(),
-		CompanyID:        companyID,
-		SalePointID:      salePointID,
-		Name:             name,
-		Category:         category,
-		Description:      description,
-		Photos:           []string{},
-		PriceVariations:  []PriceVariation{},
-		AvailableAddons:  []Addon{},
-		IsAddon:          false,
-		IsAvailable:      true,
-		IsUnlimitedStock: true,
-		Stock:            nil,
-		CreatedAt:        now,
-		UpdatedAt:        now,
+		ID:                uuid.New().String(),
+		CompanyID:         companyID,
+		SalePointID:       salePointID,
+		Name:              name,
+		Category:          category,
+		Description:       description,
+		Photos:            []string{},
+		PriceVariations:   []PriceVariation{},
+		AvailableAddons:   []Addon{},
+		QuickObservations: []string{},
+		Tags:              []string{},
+		IsAddon:           false,
+		IsAvailable:       true,
+		IsUnlimitedStock:  true,
+		Stock:             nil,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		Version:           1,
 	}
 }
 
@@ -80,78 +105,172 @@ func NewAddon(name string, price int64) *Addon {
 	}
 }
 
-// Validate performs business logic validation on the Product
+// Validate performs business logic validation on the Product.
+// Errors are returned as *apperrors.DomainError with the offending field.
 func (p *Product) Validate() error {
 	if p.CompanyID == "" {
-		return ErrInvalidCompanyID
+		return apperrors.NewDomainError(ErrInvalidCompanyID, "company_id", nil)
 	}
 	if p.SalePointID == "" {
-		return ErrInvalidSalePointID
+		return apperrors.NewDomainError(ErrInvalidSalePointID, "sale_point_id", nil)
 	}
 	if p.Name == "" {
-		return ErrInvalidName
+		return apperrors.NewDomainError(ErrInvalidName, "name", nil)
 	}
 	if p.Category == "" {
-		return ErrInvalidCategory
+		return apperrors.NewDomainError(ErrInvalidCategory, "category", nil)
 	}
 	if len(p.PriceVariations) == 0 {
-		return ErrNoPriceVariations
+		return apperrors.NewDomainError(ErrNoPriceVariations, "price_variations", nil)
+	}
+	if p.SKU != nil && !IsValidSKU(*p.SKU) {
+		return apperrors.NewDomainError(ErrInvalidSKU, "sku", *p.SKU)
+	}
+	if p.Barcode != nil && !IsValidBarcode(*p.Barcode) {
+		return apperrors.NewDomainError(ErrInvalidBarcode, "barcode", *p.Barcode)
 	}
 
 	// Validate stock logic
 	if !p.IsUnlimitedStock && p.Stock == nil {
-		return ErrInvalidStock
+		return apperrors.NewDomainError(ErrInvalidStock, "stock", nil)
 	}
 	if p.IsUnlimitedStock && p.Stock != nil {
-		return ErrStockMustBeNullForUnlimited
+		return apperrors.NewDomainError(ErrStockMustBeNullForUnlimited, "stock", *p.Stock)
 	}
 	if p.Stock != nil && *p.Stock < 0 {
-		return ErrNegativeStock
+		return apperrors.NewDomainError(ErrNegativeStock, "stock", *p.Stock)
+	}
+	if p.AvailabilitySchedule != nil {
+		if err := p.AvailabilitySchedule.Validate(); err != nil {
+			return err
+		}
 	}
 
 	// Validate price variations
 	for i, pv := range p.PriceVariations {
 		if pv.Type == "" {
-			return ErrInvalidPriceVariationType
+			return apperrors.NewIndexedDomainError(ErrInvalidPriceVariationType, fmt.Sprintf("price_variations[%d].type", i), i, pv.Type)
 		}
 		if pv.Price < 0 {
-			return ErrNegativePrice
+			return apperrors.NewIndexedDomainError(ErrNegativePrice, fmt.Sprintf("price_variations[%d].price", i), i, pv.Price)
 		}
 		if pv.IncludedAddons.MaxSelections < 0 {
-			return ErrInvalidMaxSelections
+			return apperrors.NewIndexedDomainError(ErrInvalidMaxSelections, fmt.Sprintf("price_variations[%d].included_addons.max_selections", i), i, pv.IncludedAddons.MaxSelections)
 		}
 		if pv.IncludedAddons.MaxSelections > 0 && len(pv.IncludedAddons.Options) == 0 {
-			return ErrNoOptionsForMaxSelections
+			return apperrors.NewIndexedDomainError(ErrNoOptionsForMaxSelections, fmt.Sprintf("price_variations[%d].included_addons.options", i), i, nil)
 		}
 
 		// Validate included addons
-		for _, addon := range pv.IncludedAddons.Options {
+		for j, addon := range pv.IncludedAddons.Options {
 			if addon.Name == "" {
-				return ErrInvalidAddonName
+				return apperrors.NewIndexedDomainError(ErrInvalidAddonName, fmt.Sprintf("price_variations[%d].included_addons.options[%d].name", i, j), i, addon.Name)
 			}
 			if addon.Price < 0 {
-				return ErrNegativeAddonPrice
+				return apperrors.NewIndexedDomainError(ErrNegativeAddonPrice, fmt.Sprintf("price_variations[%d].included_addons.options[%d].price", i, j), i, addon.Price)
 			}
 		}
 
 		// Check for duplicate price variation types
 		for j := i + 1; j < len(p.PriceVariations); j++ {
 			if p.PriceVariations[j].Type == pv.Type {
-				return ErrDuplicatePriceVariationType
+				return apperrors.NewIndexedDomainError(ErrDuplicatePriceVariationType, fmt.Sprintf("price_variations[%d].type", j), j, pv.Type)
 			}
 		}
 	}
 
 	// Validate available addons
-	for _, addon := range p.AvailableAddons {
+	for i, addon := range p.AvailableAddons {
 		if addon.Name == "" {
-			return ErrInvalidAddonName
+			return apperrors.NewIndexedDomainError(ErrInvalidAddonName, fmt.Sprintf("available_addons[%d].name", i), i, addon.Name)
 		}
 		if addon.Price < 0 {
-			return ErrNegativeAddonPrice
+			return apperrors.NewIndexedDomainError(ErrNegativeAddonPrice, fmt.Sprintf("available_addons[%d].price", i), i, addon.Price)
 		}
 	}
+	if err := p.validateAddonIDs(); err != nil {
+		return err
+	}
 
+	// Validate quick observations
+	if len(p.QuickObservations) > MaxQuickObservations {
+		return apperrors.NewDomainError(ErrTooManyQuickObservations, "quick_observations", len(p.QuickObservations))
+	}
+	for i, observation := range p.QuickObservations {
+		if strings.TrimSpace(observation) == "" {
+			return apperrors.NewIndexedDomainError(ErrInvalidQuickObservation, fmt.Sprintf("quick_observations[%d]", i), i, observation)
+		}
+		if utf8.RuneCountInString(observation) > MaxQuickObservationLength {
+			return apperrors.NewIndexedDomainError(ErrQuickObservationTooLong, fmt.Sprintf("quick_observations[%d]", i), i, observation)
+		}
+		for j := i + 1; j < len(p.QuickObservations); j++ {
+			if strings.EqualFold(p.QuickObservations[j], observation) {
+				return apperrors.NewIndexedDomainError(ErrDuplicateQuickObservation, fmt.Sprintf("quick_observations[%d]", j), j, p.QuickObservations[j])
+			}
+		}
+	}
+
+	return validateTags(p.Tags)
+}
+
+// validateAddonIDs checks that addon IDs can be referenced: available addons have distinct IDs, and
+// an included option either names an available addon by its ID or has an ID no other addon of the
+// product uses. A price variation cannot include the same addon twice.
+func (p *Product) validateAddonIDs() error {
+	available := make(map[string]bool, len(p.AvailableAddons))
+	for i, addon := range p.AvailableAddons {
+		if available[addon.ID] {
+			return apperrors.NewIndexedDomainError(ErrDuplicateAddonID, fmt.Sprintf("available_addons[%d].id", i), i, addon.ID)
+		}
+		available[addon.ID] = true
+	}
+
+	included := make(map[string]bool)
+	for i, pv := range p.PriceVariations {
+		options := make(map[string]bool, len(pv.IncludedAddons.Options))
+		for j, addon := range pv.IncludedAddons.Options {
+			if options[addon.ID] || (!available[addon.ID] && included[addon.ID]) {
+				return apperrors.NewIndexedDomainError(ErrDuplicateAddonID, fmt.Sprintf("price_variations[%d].included_addons.options[%d].id", i, j), i, addon.ID)
+			}
+			options[addon.ID] = true
+			included[addon.ID] = true
+		}
+	}
+	return nil
+}
+
+// AssignAddonIDs gives a new ID to every addon without one, available or included in a price variation
+func (p *Product) AssignAddonIDs() {
+	for i := range p.AvailableAddons {
+		if p.AvailableAddons[i].ID == "" {
+			p.AvailableAddons[i].ID = uuid.New().String()
+		}
+	}
+	for _, pv := range p.PriceVariations {
+		for j := range pv.IncludedAddons.Options {
+			if pv.IncludedAddons.Options[j].ID == "" {
+				pv.IncludedAddons.Options[j].ID = uuid.New().String()
+			}
+		}
+	}
+}
+
+// SanitizeText cleans the product description.
+// An empty description is allowed, but one with no printable content is rejected.
+func (p *Product) SanitizeText() error {
+	if p.Description == "" {
+		return nil
+	}
+
+	description := util.SanitizeText(p.Description)
+	if description == "" {
+		return apperrors.NewDomainError(ErrBlankDescription, "description", nil)
+	}
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return apperrors.NewDomainError(ErrDescriptionTooLong, "description", nil)
+	}
+
+	p.Description = description
 	return nil
 }
 
@@ -168,6 +287,17 @@ func (p *Product) UpdateStock(newStock *int) error {
 	return nil
 }
 
+// PageCursor returns the cursor of the listing page that ends with this product.
+// Listings are sorted by creation time, newest first.
+func (p *Product) PageCursor() util.PageCursor {
+	return util.PageCursor{Field: "created_at", Value: p.CreatedAt.UTC().Format(time.RFC3339Nano), ID: p.ID}
+}
+
+// IsDeleted reports whether the product was soft deleted
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
 // SetAvailability sets the availability status
 func (p *Product) SetAvailability(available bool) {
 	p.IsAvailable = available
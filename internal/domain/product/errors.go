@@ -10,26 +10,113 @@ var (
 	ErrInvalidName        = errors.New("product name is required")
 	ErrInvalidCategory    = errors.New("category is required")
 
+	// SKU errors
+	ErrInvalidSKU   = errors.New("sku must have 1 to 40 letters, digits, hyphens or underscores")
+	ErrDuplicateSKU = errors.New("sku is already used by another product of the sale point")
+
+	// Barcode errors
+	ErrInvalidBarcode   = errors.New("barcode must be a valid EAN-8, UPC-A, EAN-13 or GTIN-14 number, or 1 to 48 printable characters without spaces")
+	ErrDuplicateBarcode = errors.New("barcode is already used by another product of the company")
+
 	// Stock errors
 	ErrInvalidStock                  = errors.New("stock must be set when is_unlimited_stock is false")
 	ErrStockMustBeNullForUnlimited   = errors.New("stock must be null when is_unlimited_stock is true")
 	ErrNegativeStock                 = errors.New("stock cannot be negative")
 	ErrInsufficientStock             = errors.New("insufficient stock available")
 	ErrCannotUpdateStockForUnlimited = errors.New("cannot update stock for unlimited stock products")
+	ErrInvalidStockAdjustment        = errors.New("stock adjustment requires exactly one of delta or set")
 
 	// Price variation errors
 	ErrNoPriceVariations           = errors.New("at least one price variation is required")
 	ErrInvalidPriceVariationType   = errors.New("price variation type is required")
 	ErrNegativePrice               = errors.New("price cannot be negative")
 	ErrDuplicatePriceVariationType = errors.New("duplicate price variation type")
+	ErrInvalidPriceRange           = errors.New("cannot be greater than max_price") // Of min_price in listings
 	ErrInvalidMaxSelections        = errors.New("max_selections cannot be negative")
 	ErrNoOptionsForMaxSelections   = errors.New("options must be provided when max_selections > 0")
 
+	// Description errors
+	ErrBlankDescription   = errors.New("description cannot be blank")
+	ErrDescriptionTooLong = errors.New("description exceeds maximum length")
+
+	// Quick observation errors
+	ErrTooManyQuickObservations  = errors.New("a product can have at most 10 quick observations")
+	ErrInvalidQuickObservation   = errors.New("quick observation cannot be blank")
+	ErrQuickObservationTooLong   = errors.New("quick observation must be at most 50 characters long")
+	ErrDuplicateQuickObservation = errors.New("duplicate quick observation")
+
+	// Tag errors
+	ErrTooManyTags     = errors.New("a product can have at most 20 tags")
+	ErrInvalidTag      = errors.New("tag cannot be blank")
+	ErrTagTooLong      = errors.New("tag must be at most 40 characters long")
+	ErrInvalidTagsMode = errors.New("invalid tags mode")
+
 	// Addon errors
 	ErrInvalidAddonName   = errors.New("addon name is required")
 	ErrNegativeAddonPrice = errors.New("addon price cannot be negative")
+	ErrDuplicateAddonID   = errors.New("addon id is already used by another addon of the product")
 	ErrDuplicateAddon     = errors.New("addon already exists")
 
+	// Photo URL errors
+	ErrPhotoHostNotAllowed = errors.New("photo URL host is not allowed")
+
+	// Document size errors
+	ErrProductTooLarge = errors.New("product too large")
+
+	// Bulk delete errors
+	ErrEmptyBulkDelete     = errors.New("bulk delete requires product ids or a sale_point_id")
+	ErrAmbiguousBulkDelete = errors.New("bulk delete accepts either product ids or a sale point selector, not both")
+	ErrBulkDeleteTooLarge  = errors.New("bulk delete selects too many products")
+	ErrProductsReferenced  = errors.New("products are referenced by recent orders")
+
+	// Delete errors
+	ErrProductInUse = errors.New("product is in open orders")
+
+	// Category errors
+	ErrUnknownCategory = errors.New("category is not a category of the sale point")
+
+	// Category rename errors
+	ErrCategoryCaseConflict = errors.New("a category with the same name in different case already exists")
+
+	// Bulk availability errors
+	ErrEmptyBulkAvailability     = errors.New("bulk availability requires product ids or a category")
+	ErrAmbiguousBulkAvailability = errors.New("bulk availability accepts either product ids or a category, not both")
+	ErrBulkAvailabilityTooLarge  = errors.New("bulk availability accepts at most 500 product ids")
+	ErrBulkAvailabilitySalePoint = errors.New("sale_point_id is required to select products by category")
+
+	// Reorder errors
+	ErrEmptyReorder       = errors.New("reorder requires the ordered product ids")
+	ErrReorderTooLarge    = errors.New("reorder accepts at most 500 product ids")
+	ErrDuplicateReorderID = errors.New("product id is listed more than once")
+	ErrReorderMismatch    = errors.New("ordered ids must list every product of the category exactly once")
+
+	// Copy errors
+	ErrCopySameSalePoint   = errors.New("cannot copy products into their own sale point")
+	ErrCopyCompanyMismatch = errors.New("target sale point belongs to another company")
+
+	// Import errors
+	ErrImportEmpty        = errors.New("import file has no product rows")
+	ErrImportTooManyRows  = errors.New("import file has too many rows")
+	ErrImportInvalid      = errors.New("import file has invalid rows")
+	ErrInvalidImportPrice = errors.New("price must be a whole number in the smallest currency unit")
+	ErrInvalidImportStock = errors.New("stock must be a whole number or empty for unlimited stock")
+	ErrInvalidImportBool  = errors.New("is_available must be true or false")
+
+	// Availability schedule errors
+	ErrInvalidScheduleTimezone    = errors.New("schedule timezone must be an IANA name such as America/Bogota")
+	ErrTooManyScheduleWindows     = errors.New("a schedule can have at most 50 windows")
+	ErrInvalidScheduleDay         = errors.New("window day must be a weekday name, monday to sunday")
+	ErrInvalidScheduleTime        = errors.New("window start and end must be HH:MM times; end may be 24:00")
+	ErrEmptyScheduleWindow        = errors.New("window start and end cannot be equal")
+	ErrOverlappingScheduleWindows = errors.New("schedule windows overlap")
+
+	// Batch fetch errors
+	ErrEmptyBatch    = errors.New("batch fetch requires product ids")
+	ErrBatchTooLarge = errors.New("batch fetch accepts at most 100 product ids")
+
 	// Not found error
 	ErrProductNotFound = errors.New("product not found")
+
+	// Concurrency errors
+	ErrProductConflict = errors.New("product was modified by another request")
 )
@@ -0,0 +1,45 @@
+package product
+
+import (
+	"context"
+	"fmt"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// ExportInput represents input for streaming the products of a company
+type ExportInput struct {
+	CompanyID   string
+	SalePointID string         // Optional
+	Filters     ProductFilters // Pagination is ignored
+}
+
+// ExportEmitFunc receives each exported product
+type ExportEmitFunc func(p *Product) error
+
+// Export walks the company's products matching the filters oldest first and passes each one to emit,
+// without loading them all. Soft deleted products are only included with Filters.IncludeDeleted.
+// It stops at the first emit error or as soon as ctx is cancelled, e.g. when the client disconnects.
+func (s *Service) Export(ctx context.Context, input ExportInput, emit ExportEmitFunc) error {
+	if input.CompanyID == "" {
+		return apperrors.NewDomainError(ErrInvalidCompanyID, "company_id", nil)
+	}
+
+	filters := input.Filters
+	filters.SalePointID = nil
+	if input.SalePointID != "" {
+		filters.SalePointID = &input.SalePointID
+	}
+	filters.Cursor, filters.Limit, filters.Offset = nil, 0, 0
+
+	err := s.repo.EachByCompanyID(ctx, input.CompanyID, filters, func(p *Product) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return emit(p)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export products: %w", err)
+	}
+	return nil
+}
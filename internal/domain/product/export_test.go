@@ -0,0 +1,96 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestExport(t *testing.T) {
+	base := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	newAt := func(salePointID, name string, minutes int) *Product {
+		p := NewProduct("company-1", salePointID, name, "Platos", "")
+		p.CreatedAt = base.Add(time.Duration(minutes) * time.Minute)
+		return p
+	}
+	burger, soda, salad := newAt("sp-1", "Burger", 2), newAt("sp-1", "Soda", 1), newAt("sp-2", "Salad", 0)
+	deleted := newAt("sp-1", "Arepa", 3)
+	deletedAt := base
+	deleted.DeletedAt = &deletedAt
+	repo := newMemoryRepository(burger, soda, salad, deleted, NewProduct("company-2", "sp-3", "Other", "Platos", ""))
+	service := NewService(repo)
+
+	tests := []struct {
+		name  string
+		input ExportInput
+		want  []string
+	}{
+		{"company oldest first", ExportInput{CompanyID: "company-1"}, []string{"Salad", "Soda", "Burger"}},
+		{"one sale point", ExportInput{CompanyID: "company-1", SalePointID: "sp-1"}, []string{"Soda", "Burger"}},
+		{"deleted included", ExportInput{CompanyID: "company-1", SalePointID: "sp-1", Filters: ProductFilters{IncludeDeleted: true}},
+			[]string{"Soda", "Burger", "Arepa"}},
+		{"pagination ignored", ExportInput{CompanyID: "company-1", Filters: ProductFilters{Limit: 1, Offset: 1, Cursor: &util.PageCursor{}}},
+			[]string{"Salad", "Soda", "Burger"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			err := service.Export(context.Background(), tt.input, func(p *Product) error {
+				names = append(names, p.Name)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("exported = %v, want %v", names, tt.want)
+			}
+			listed := repo.listed[len(repo.listed)-1]
+			if listed.Limit != 0 || listed.Offset != 0 || listed.Cursor != nil {
+				t.Errorf("repository filters = %+v, want no pagination", listed)
+			}
+		})
+	}
+}
+
+func TestExportStops(t *testing.T) {
+	repo := newMemoryRepository(NewProduct("company-1", "sp-1", "Burger", "Platos", ""), NewProduct("company-1", "sp-1", "Soda", "Bebidas", ""))
+	service := NewService(repo)
+
+	t.Run("company required", func(t *testing.T) {
+		err := service.Export(context.Background(), ExportInput{}, func(*Product) error { return nil })
+		if !errors.Is(err, ErrInvalidCompanyID) {
+			t.Errorf("err = %v, want ErrInvalidCompanyID", err)
+		}
+	})
+
+	t.Run("emit error", func(t *testing.T) {
+		errWrite := errors.New("broken pipe")
+		emitted := 0
+		err := service.Export(context.Background(), ExportInput{CompanyID: "company-1"}, func(*Product) error {
+			emitted++
+			return errWrite
+		})
+		if !errors.Is(err, errWrite) || emitted != 1 {
+			t.Errorf("err = %v after %d products, want the write error after the first one", err, emitted)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		emitted := 0
+		err := service.Export(ctx, ExportInput{CompanyID: "company-1"}, func(*Product) error {
+			emitted++
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) || emitted != 1 {
+			t.Errorf("err = %v after %d products, want context.Canceled after the first one", err, emitted)
+		}
+	})
+}
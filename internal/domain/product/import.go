@@ -0,0 +1,258 @@
+package product
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// DefaultVariationType names the price variation of import rows without a variation type
+const DefaultVariationType = "default"
+
+// ImportLimits bounds product imports
+type ImportLimits struct {
+	MaxBytes int // Largest accepted file
+	MaxRows  int // Most data rows per file
+}
+
+// DefaultImportLimits are used when no import limits are configured
+var DefaultImportLimits = ImportLimits{MaxBytes: 2 << 20, MaxRows: 1000}
+
+// WithImportLimits sets the size limits of product imports
+func WithImportLimits(limits ImportLimits) Option {
+	return func(s *Service) {
+		s.importLimits = limits
+	}
+}
+
+// ImportLimits returns the size limits of product imports
+func (s *Service) ImportLimits() ImportLimits {
+	return s.importLimits
+}
+
+// ImportRow is one data row of an import file, as text. Rows with the same name describe
+// one product: each adds a price variation and the other columns come from the first one.
+type ImportRow struct {
+	Line          int // Line of the row in the file, the header being line 1
+	Name          string
+	Category      string
+	Description   string
+	Price         string // Whole number in the smallest currency unit
+	VariationType string // Empty means DefaultVariationType
+	IsAvailable   string // Empty means available
+	Stock         string // Empty means unlimited stock
+	Photos        string // URLs separated by "|"
+}
+
+// ImportInput represents input for importing the products of a sale point
+type ImportInput struct {
+	CompanyID   string
+	SalePointID string
+	Rows        []ImportRow
+	DryRun      bool // Validate the rows without creating the products
+}
+
+// ImportRowError reports why a row cannot be imported
+type ImportRowError struct {
+	Row     int    `json:"row"` // Line of the row in the file
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportResult reports the outcome of an import
+type ImportResult struct {
+	Rows     int              `json:"rows"`
+	Products int              `json:"products"` // Products described by the rows
+	Created  int              `json:"created"`
+	DryRun   bool             `json:"dry_run"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// importedProduct is a product being imported and the lines of the rows describing it
+type importedProduct struct {
+	input CreateInput
+	lines []int
+}
+
+// Import validates every row and creates all the products in one insert. Nothing is created when any
+// row is invalid: the result lists the errors and the error is ErrImportInvalid. A dry run only validates.
+func (s *Service) Import(ctx context.Context, input ImportInput) (*ImportResult, error) {
+	if input.CompanyID == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidCompanyID, "company_id", nil)
+	}
+	if input.SalePointID == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidSalePointID, "sale_point_id", nil)
+	}
+	if len(input.Rows) == 0 {
+		return nil, ErrImportEmpty
+	}
+	if len(input.Rows) > s.importLimits.MaxRows {
+		return nil, fmt.Errorf("%w: %d rows, max %d", ErrImportTooManyRows, len(input.Rows), s.importLimits.MaxRows)
+	}
+
+	result := &ImportResult{Rows: len(input.Rows), DryRun: input.DryRun, Errors: []ImportRowError{}}
+	imported := groupImportRows(input, result)
+	result.Products = len(imported)
+
+	categories, err := s.activeCategories(ctx, input.SalePointID)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*Product, 0, len(imported))
+	for _, item := range imported {
+		p := item.input.newProduct()
+		err := s.checkNewProduct(p)
+		if err == nil {
+			err = matchCategory(p, categories)
+		}
+		if err != nil {
+			rowErr, ok := importRowError(item.lines[0], err)
+			if !ok {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, rowErr)
+			continue
+		}
+		products = append(products, p)
+	}
+
+	if len(result.Errors) > 0 {
+		slices.SortStableFunc(result.Errors, func(a, b ImportRowError) int { return cmp.Compare(a.Row, b.Row) })
+		return result, ErrImportInvalid
+	}
+	if input.DryRun {
+		return result, nil
+	}
+
+	if err := s.placeAllLast(ctx, products); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateMany(ctx, products); err != nil {
+		return nil, fmt.Errorf("failed to import products: %w", err)
+	}
+	result.Created = len(products)
+	return result, nil
+}
+
+// groupImportRows converts the rows to one create input per product name, in order of appearance,
+// and adds the rows that cannot be converted to the result errors
+func groupImportRows(input ImportInput, result *ImportResult) []*importedProduct {
+	var imported []*importedProduct
+	byName := make(map[string]*importedProduct)
+
+	for _, row := range input.Rows {
+		variation, err := row.priceVariation()
+		if err != nil {
+			result.Errors = append(result.Errors, importErrorFor(row.Line, err))
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(row.Name))
+		if item, ok := byName[key]; ok {
+			item.input.PriceVariations = append(item.input.PriceVariations, variation)
+			item.lines = append(item.lines, row.Line)
+			continue
+		}
+
+		createInput, err := row.createInput(input.CompanyID, input.SalePointID)
+		if err != nil {
+			result.Errors = append(result.Errors, importErrorFor(row.Line, err))
+			continue
+		}
+		createInput.PriceVariations = []PriceVariation{variation}
+		item := &importedProduct{input: createInput, lines: []int{row.Line}}
+		byName[key] = item
+		imported = append(imported, item)
+	}
+
+	return imported
+}
+
+// priceVariation converts the price columns of the row
+func (row ImportRow) priceVariation() (PriceVariation, error) {
+	variation := PriceVariation{Type: strings.TrimSpace(row.VariationType)}
+	if variation.Type == "" {
+		variation.Type = DefaultVariationType
+	}
+
+	price, err := strconv.ParseInt(strings.TrimSpace(row.Price), 10, 64)
+	if err != nil {
+		return variation, apperrors.NewDomainError(ErrInvalidImportPrice, "price", row.Price)
+	}
+	variation.Price = price
+	return variation, nil
+}
+
+// createInput converts the product columns of the row
+func (row ImportRow) createInput(companyID, salePointID string) (CreateInput, error) {
+	input := CreateInput{
+		CompanyID:        companyID,
+		SalePointID:      salePointID,
+		Name:             strings.TrimSpace(row.Name),
+		Category:         strings.TrimSpace(row.Category),
+		Description:      row.Description,
+		IsAvailable:      true,
+		IsUnlimitedStock: true,
+	}
+
+	if value := strings.TrimSpace(row.IsAvailable); value != "" {
+		available, ok := parseImportBool(value)
+		if !ok {
+			return input, apperrors.NewDomainError(ErrInvalidImportBool, "is_available", row.IsAvailable)
+		}
+		input.IsAvailable = available
+	}
+
+	if value := strings.TrimSpace(row.Stock); value != "" {
+		stock, err := strconv.Atoi(value)
+		if err != nil {
+			return input, apperrors.NewDomainError(ErrInvalidImportStock, "stock", row.Stock)
+		}
+		input.IsUnlimitedStock = false
+		input.Stock = &stock
+	}
+
+	for _, photo := range strings.Split(row.Photos, "|") {
+		if photo = strings.TrimSpace(photo); photo != "" {
+			input.Photos = append(input.Photos, photo)
+		}
+	}
+
+	return input, nil
+}
+
+// parseImportBool reads the yes/no values spreadsheets use, in English or Spanish
+func parseImportBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true", "1", "yes", "y", "si", "sí", "s", "verdadero":
+		return true, true
+	case "false", "0", "no", "n", "falso":
+		return false, true
+	}
+	return false, false
+}
+
+// importRowError reports a validation error on the row; other errors are not the row's fault
+func importRowError(line int, err error) (ImportRowError, bool) {
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) || errors.Is(err, ErrProductTooLarge) {
+		return importErrorFor(line, err), true
+	}
+	return ImportRowError{}, false
+}
+
+// importErrorFor describes a validation error on the row
+func importErrorFor(line int, err error) ImportRowError {
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		return ImportRowError{Row: line, Field: domainErr.Field, Message: domainErr.Err.Error()}
+	}
+	return ImportRowError{Row: line, Message: strings.TrimPrefix(err.Error(), "validation error: ")}
+}
@@ -0,0 +1,114 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	rows := []ImportRow{
+		{Line: 2, Name: "Pizza", Category: "Pizzas", Price: "20000", VariationType: "large", Photos: "https://cdn.example.com/a.png | https://cdn.example.com/b.png"},
+		{Line: 3, Name: "Lemonade", Category: "Drinks", Description: "Fresh", Price: "6000", IsAvailable: "no", Stock: "12"},
+		{Line: 4, Name: " pizza ", Category: "ignored", Price: "12000", VariationType: "small"},
+	}
+
+	t.Run("creates one product per name", func(t *testing.T) {
+		repo := newMemoryRepository()
+		result, err := NewService(repo).Import(ctx, ImportInput{CompanyID: "company-1", SalePointID: "sale-point-1", Rows: rows})
+		if err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+		if result.Rows != 3 || result.Products != 2 || result.Created != 2 || len(result.Errors) != 0 {
+			t.Fatalf("result = %+v, want 3 rows, 2 products created", result)
+		}
+
+		byName := make(map[string]*Product)
+		for _, p := range repo.products {
+			byName[p.Name] = p
+		}
+		pizza, lemonade := byName["Pizza"], byName["Lemonade"]
+		if pizza == nil || lemonade == nil {
+			t.Fatalf("stored %v, want Pizza and Lemonade", byName)
+		}
+		wantPrices := []PriceVariation{{Type: "large", Price: 20000}, {Type: "small", Price: 12000}}
+		if !reflect.DeepEqual(pizza.PriceVariations, wantPrices) || pizza.Category != "Pizzas" || len(pizza.Photos) != 2 {
+			t.Errorf("pizza = %+v, want both variations, category Pizzas and 2 photos", pizza)
+		}
+		if !pizza.IsAvailable || !pizza.IsUnlimitedStock {
+			t.Errorf("pizza availability = %v, unlimited = %v; want the defaults", pizza.IsAvailable, pizza.IsUnlimitedStock)
+		}
+		if lemonade.IsAvailable || lemonade.IsUnlimitedStock || lemonade.Stock == nil || *lemonade.Stock != 12 {
+			t.Errorf("lemonade = %+v, want unavailable with 12 in stock", lemonade)
+		}
+		if lemonade.PriceVariations[0].Type != DefaultVariationType {
+			t.Errorf("lemonade variation = %q, want %q", lemonade.PriceVariations[0].Type, DefaultVariationType)
+		}
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		repo := newMemoryRepository()
+		result, err := NewService(repo).Import(ctx, ImportInput{CompanyID: "company-1", SalePointID: "sale-point-1", Rows: rows, DryRun: true})
+		if err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+		if result.Products != 2 || result.Created != 0 || len(repo.products) != 0 {
+			t.Errorf("result = %+v with %d stored, want 2 products and nothing created", result, len(repo.products))
+		}
+	})
+
+	t.Run("invalid rows block the import", func(t *testing.T) {
+		repo := newMemoryRepository()
+		invalid := append([]ImportRow{}, rows...)
+		invalid = append(invalid,
+			ImportRow{Line: 5, Name: "Soup", Category: "Soups", Price: "12.000"},
+			ImportRow{Line: 6, Name: "Salad", Category: "", Price: "9000"},
+			ImportRow{Line: 7, Name: "Juice", Category: "Drinks", Price: "5000", IsAvailable: "maybe"},
+			ImportRow{Line: 8, Name: "Pizza", Category: "Pizzas", Price: "15000", VariationType: "large"},
+		)
+
+		result, err := NewService(repo).Import(ctx, ImportInput{CompanyID: "company-1", SalePointID: "sale-point-1", Rows: invalid})
+		if !errors.Is(err, ErrImportInvalid) {
+			t.Fatalf("Import() error = %v, want %v", err, ErrImportInvalid)
+		}
+		want := []ImportRowError{
+			{Row: 2, Field: "price_variations[2].type", Message: ErrDuplicatePriceVariationType.Error()},
+			{Row: 5, Field: "price", Message: ErrInvalidImportPrice.Error()},
+			{Row: 6, Field: "category", Message: ErrInvalidCategory.Error()},
+			{Row: 7, Field: "is_available", Message: ErrInvalidImportBool.Error()},
+		}
+		if !reflect.DeepEqual(result.Errors, want) {
+			t.Errorf("errors = %+v, want %+v", result.Errors, want)
+		}
+		if len(repo.products) != 0 {
+			t.Errorf("stored %d products, want none", len(repo.products))
+		}
+	})
+
+	t.Run("checks categories once for the sale point", func(t *testing.T) {
+		catalog := memoryCategoryCatalog{"sale-point-1": {"Pizzas"}}
+		result, err := NewService(newMemoryRepository(), WithCategoryCatalog(catalog)).Import(ctx, ImportInput{CompanyID: "company-1", SalePointID: "sale-point-1", Rows: rows})
+		if !errors.Is(err, ErrImportInvalid) {
+			t.Fatalf("Import() error = %v, want %v", err, ErrImportInvalid)
+		}
+		want := []ImportRowError{{Row: 3, Field: "category", Message: ErrUnknownCategory.Error()}}
+		if !reflect.DeepEqual(result.Errors, want) {
+			t.Errorf("errors = %+v, want %+v", result.Errors, want)
+		}
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		svc := NewService(newMemoryRepository(), WithImportLimits(ImportLimits{MaxBytes: 1 << 10, MaxRows: 2}))
+		if _, err := svc.Import(ctx, ImportInput{CompanyID: "company-1", SalePointID: "sale-point-1", Rows: rows}); !errors.Is(err, ErrImportTooManyRows) {
+			t.Errorf("too many rows error = %v, want %v", err, ErrImportTooManyRows)
+		}
+		if _, err := svc.Import(ctx, ImportInput{CompanyID: "company-1", SalePointID: "sale-point-1"}); !errors.Is(err, ErrImportEmpty) {
+			t.Errorf("no rows error = %v, want %v", err, ErrImportEmpty)
+		}
+		if _, err := svc.Import(ctx, ImportInput{CompanyID: "company-1", Rows: rows[:1]}); !errors.Is(err, ErrInvalidSalePointID) {
+			t.Errorf("missing sale point error = %v, want %v", err, ErrInvalidSalePointID)
+		}
+	})
+}
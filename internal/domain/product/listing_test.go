@@ -0,0 +1,45 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetByCompanyIDSkipCount(t *testing.T) {
+	errUnavailable := errors.New("database unavailable")
+
+	tests := []struct {
+		name      string
+		skipCount bool
+		listErr   error
+		wantTotal int64
+		wantErr   error
+	}{
+		{"counted", false, nil, 2, nil},
+		{"count skipped", true, nil, -1, nil},
+		{"repository error", false, errUnavailable, 0, errUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := NewProduct("company-1", "sp-1", "Burger", "Platos", ""), NewProduct("company-1", "sp-1", "Soda", "Bebidas", "")
+			repo := newMemoryRepository(first, second, NewProduct("company-2", "sp-2", "Other", "Platos", ""))
+			repo.listErr = tt.listErr
+
+			products, total, err := NewService(repo).GetByCompanyID(context.Background(), "company-1", ProductFilters{SkipCount: tt.skipCount})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.listed) != 1 || repo.listed[0].SkipCount != tt.skipCount {
+				t.Fatalf("listing calls = %+v, want one with SkipCount %v", repo.listed, tt.skipCount)
+			}
+			if err != nil {
+				return
+			}
+			if total != tt.wantTotal || len(products) != 2 {
+				t.Errorf("got %d products, total %d, want 2 and %d", len(products), total, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package product
+
+import (
+	"context"
+	"fmt"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// MenuProductLimits bound the number of products a menu lists per category
+var MenuProductLimits = util.PageLimits{DefaultLimit: 50, MaxLimit: 100}
+
+// MenuFilters selects what a sale point's menu shows
+type MenuFilters struct {
+	IncludeUnavailable bool // Admin preview: also disabled and out of stock products, and inactive categories
+	ProductLimit       int  // Products listed per category, resolved with MenuProductLimits
+	Limit              int  // Categories per page
+	Offset             int
+}
+
+// MenuSection is one category of a menu with its first products in display order
+type MenuSection struct {
+	Category     string     `json:"category" bson:"category"`
+	ProductCount int        `json:"product_count" bson:"product_count"` // Matching products, also those past the product limit
+	Products     []*Product `json:"products" bson:"products"`
+}
+
+// GetMenu retrieves a page of a sale point's categories, each with its products, in a single query.
+// Categories follow the position of their category entity; those without one come last, by name.
+// Only products customers can order are listed unless IncludeUnavailable; schedules are not applied.
+func (s *Service) GetMenu(ctx context.Context, salePointID string, filters MenuFilters) ([]MenuSection, int64, error) {
+	if salePointID == "" {
+		return nil, 0, apperrors.NewDomainError(ErrInvalidSalePointID, "sale_point_id", nil)
+	}
+
+	filters.Limit = s.salePointPageLimits.Resolve(filters.Limit)
+	filters.ProductLimit = MenuProductLimits.Resolve(filters.ProductLimit)
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	sections, total, err := s.repo.FindMenu(ctx, salePointID, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get menu: %w", err)
+	}
+	return sections, total, nil
+}
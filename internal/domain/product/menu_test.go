@@ -0,0 +1,59 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// menuRepository records the filters of FindMenu
+type menuRepository struct {
+	*memoryRepository
+	filters []MenuFilters
+}
+
+func (r *menuRepository) FindMenu(ctx context.Context, salePointID string, filters MenuFilters) ([]MenuSection, int64, error) {
+	r.filters = append(r.filters, filters)
+	return []MenuSection{}, 0, nil
+}
+
+func TestGetMenuResolvesLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  MenuFilters
+		wanted MenuFilters
+	}{
+		{"defaults", MenuFilters{}, MenuFilters{ProductLimit: 50, Limit: 20}},
+		{"clamped", MenuFilters{ProductLimit: 500, Limit: 500, Offset: -1}, MenuFilters{ProductLimit: 100, Limit: 40}},
+		{"preview", MenuFilters{IncludeUnavailable: true, ProductLimit: 5, Limit: 10, Offset: 10},
+			MenuFilters{IncludeUnavailable: true, ProductLimit: 5, Limit: 10, Offset: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &menuRepository{memoryRepository: newMemoryRepository()}
+			svc := NewService(repo, WithSalePointPageLimits(util.PageLimits{DefaultLimit: 20, MaxLimit: 40}))
+
+			if _, _, err := svc.GetMenu(context.Background(), "sp1", tt.input); err != nil {
+				t.Fatal(err)
+			}
+			if len(repo.filters) != 1 || repo.filters[0] != tt.wanted {
+				t.Errorf("filters = %+v, want %+v", repo.filters, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestGetMenuRequiresSalePoint(t *testing.T) {
+	repo := &menuRepository{memoryRepository: newMemoryRepository()}
+
+	_, _, err := NewService(repo).GetMenu(context.Background(), "", MenuFilters{})
+	if !errors.Is(err, ErrInvalidSalePointID) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidSalePointID)
+	}
+	if len(repo.filters) != 0 {
+		t.Error("queried the menu of no sale point")
+	}
+}
@@ -0,0 +1,75 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestPhotoHostAllowlist(t *testing.T) {
+	allowed := "https://d1.cloudfront.net/burger.jpg"
+	foreign := "https://cdn.competitor.com/burger.jpg"
+
+	tests := []struct {
+		name      string
+		allowlist []string
+		photos    []string
+		addons    []Addon
+		wantField string
+	}{
+		{"allowed photo", []string{"*.cloudfront.net"}, []string{allowed}, nil, ""},
+		{"empty allowlist allows all", nil, []string{foreign}, nil, ""},
+		{"foreign photo", []string{"*.cloudfront.net"}, []string{allowed, foreign}, nil, "photos[1]"},
+		{"foreign addon photo", []string{"*.cloudfront.net"}, nil, []Addon{{Name: "Queso", Photos: []string{allowed}}, {Name: "Tocino", Photos: []string{foreign}}}, "available_addons[1].photos[0]"},
+	}
+
+	for _, tt := range tests {
+		check := func(t *testing.T, err error) {
+			t.Helper()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrPhotoHostNotAllowed) {
+				t.Fatalf("err = %v, want %v", err, ErrPhotoHostNotAllowed)
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.As(err, &domainErr) || domainErr.Field != tt.wantField {
+				t.Errorf("error field = %+v, want %s", domainErr, tt.wantField)
+			}
+			if got := err.Error(); !strings.Contains(got, "cdn.competitor.com") {
+				t.Errorf("error %q does not name the host", got)
+			}
+		}
+
+		t.Run("create: "+tt.name, func(t *testing.T) {
+			svc := NewService(newMemoryRepository(), WithPhotoHostAllowlist(util.NewHostAllowlist(tt.allowlist)))
+			input := testInput("")
+			input.Photos = tt.photos
+			input.AvailableAddons = tt.addons
+			_, err := svc.Create(context.Background(), input)
+			check(t, err)
+		})
+
+		t.Run("update: "+tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo, WithPhotoHostAllowlist(util.NewHostAllowlist(tt.allowlist)))
+			p, err := svc.Create(context.Background(), testInput(""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			photos, addons := tt.photos, tt.addons
+			_, err = svc.Update(context.Background(), p.ID, UpdateInput{Photos: &photos, AvailableAddons: &addons})
+			check(t, err)
+			if tt.wantField != "" && len(repo.stored(p.ID).Photos) != 0 {
+				t.Error("rejected photos were stored")
+			}
+		})
+	}
+}
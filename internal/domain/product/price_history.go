@@ -0,0 +1,137 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/google/uuid"
+)
+
+// PriceHistoryRepository stores the price changes of products
+type PriceHistoryRepository interface {
+	// Append records a price change
+	Append(ctx context.Context, change *PriceChange) error
+
+	// FindByProductID retrieves one page of a product's price changes, newest first, and their total
+	FindByProductID(ctx context.Context, productID string, limit, offset int) ([]PriceChange, int64, error)
+}
+
+// PriceChange records an update that changed a product's price variations
+type PriceChange struct {
+	ID        string           `json:"id" bson:"_id"`
+	ProductID string           `json:"product_id" bson:"product_id"`
+	Previous  []PriceVariation `json:"previous" bson:"previous"` // Variations before the update
+	New       []PriceVariation `json:"new" bson:"new"`           // Variations after the update
+	Diff      PriceDiff        `json:"diff" bson:"diff"`
+	Actor     string           `json:"actor,omitempty" bson:"actor,omitempty"` // Who made the change, when the client said so
+	ChangedAt time.Time        `json:"changed_at" bson:"changed_at"`
+}
+
+// PriceDiff describes how the price variations changed, by variation type
+type PriceDiff struct {
+	Added   []PriceVariation  `json:"added,omitempty" bson:"added,omitempty"`
+	Removed []PriceVariation  `json:"removed,omitempty" bson:"removed,omitempty"`
+	Changed []VariationChange `json:"changed,omitempty" bson:"changed,omitempty"`
+}
+
+// VariationChange records a variation type whose price changed
+type VariationChange struct {
+	Type          string `json:"type" bson:"type"`
+	PreviousPrice int64  `json:"previous_price" bson:"previous_price"` // In cents
+	Price         int64  `json:"price" bson:"price"`                   // In cents
+}
+
+// IsEmpty reports whether no price changed
+func (d PriceDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffPriceVariations compares two variation lists by type. Only prices count: reordering the
+// variations or editing their included addons is not a price change.
+func DiffPriceVariations(previous, current []PriceVariation) PriceDiff {
+	before := make(map[string]PriceVariation, len(previous))
+	for _, v := range previous {
+		before[v.Type] = v
+	}
+	after := make(map[string]bool, len(current))
+
+	var diff PriceDiff
+	for _, v := range current {
+		after[v.Type] = true
+		old, ok := before[v.Type]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, v)
+		case old.Price != v.Price:
+			diff.Changed = append(diff.Changed, VariationChange{Type: v.Type, PreviousPrice: old.Price, Price: v.Price})
+		}
+	}
+	for _, v := range previous {
+		if !after[v.Type] {
+			diff.Removed = append(diff.Removed, v)
+		}
+	}
+	return diff
+}
+
+// WithPriceHistory records the price changes of product updates in the repository
+func WithPriceHistory(history PriceHistoryRepository) Option {
+	return func(s *Service) {
+		s.priceHistory = history
+	}
+}
+
+// recordPriceChange stores the change from previous to the product's current variations, if any price changed
+func (s *Service) recordPriceChange(ctx context.Context, p *Product, previous []PriceVariation, actor string) error {
+	if s.priceHistory == nil {
+		return nil
+	}
+	diff := DiffPriceVariations(previous, p.PriceVariations)
+	if diff.IsEmpty() {
+		return nil
+	}
+
+	change := &PriceChange{
+		ID:        uuid.New().String(),
+		ProductID: p.ID,
+		Previous:  previous,
+		New:       p.PriceVariations,
+		Diff:      diff,
+		Actor:     actor,
+		ChangedAt: time.Now(),
+	}
+	if err := s.priceHistory.Append(ctx, change); err != nil {
+		return fmt.Errorf("failed to record price history: %w", err)
+	}
+	return nil
+}
+
+// GetPriceHistory retrieves one page of a product's price changes, newest first, and their total
+func (s *Service) GetPriceHistory(ctx context.Context, id string, limit, offset int) ([]PriceChange, int64, error) {
+	if id == "" {
+		return nil, 0, fmt.Errorf("product ID is required")
+	}
+
+	exists, err := s.repo.Exists(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !exists {
+		return nil, 0, ErrProductNotFound
+	}
+	if s.priceHistory == nil {
+		return []PriceChange{}, 0, nil
+	}
+
+	limit = util.DefaultPageLimits.Resolve(limit)
+	if offset < 0 {
+		offset = 0
+	}
+	changes, total, err := s.priceHistory.FindByProductID(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get price history: %w", err)
+	}
+	return changes, total, nil
+}
@@ -0,0 +1,180 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDiffPriceVariations(t *testing.T) {
+	small := PriceVariation{Type: "small", Price: 1000}
+	large := PriceVariation{Type: "large", Price: 1500}
+	family := PriceVariation{Type: "family", Price: 2500}
+
+	tests := []struct {
+		name     string
+		previous []PriceVariation
+		current  []PriceVariation
+		want     PriceDiff
+	}{
+		{"unchanged", []PriceVariation{small, large}, []PriceVariation{small, large}, PriceDiff{}},
+		{"reordered", []PriceVariation{small, large}, []PriceVariation{large, small}, PriceDiff{}},
+		{
+			"addons only",
+			[]PriceVariation{small},
+			[]PriceVariation{{Type: "small", Price: 1000, IncludedAddons: IncludedAddons{MaxSelections: 2}}},
+			PriceDiff{},
+		},
+		{"added", []PriceVariation{small}, []PriceVariation{small, large}, PriceDiff{Added: []PriceVariation{large}}},
+		{"removed", []PriceVariation{small, large}, []PriceVariation{small}, PriceDiff{Removed: []PriceVariation{large}}},
+		{
+			"changed",
+			[]PriceVariation{small, large},
+			[]PriceVariation{small, {Type: "large", Price: 1800}},
+			PriceDiff{Changed: []VariationChange{{Type: "large", PreviousPrice: 1500, Price: 1800}}},
+		},
+		{
+			"added, removed and changed",
+			[]PriceVariation{small, large},
+			[]PriceVariation{{Type: "small", Price: 900}, family},
+			PriceDiff{
+				Added:   []PriceVariation{family},
+				Removed: []PriceVariation{large},
+				Changed: []VariationChange{{Type: "small", PreviousPrice: 1000, Price: 900}},
+			},
+		},
+		{"from nothing", nil, []PriceVariation{small}, PriceDiff{Added: []PriceVariation{small}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffPriceVariations(tt.previous, tt.current)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffPriceVariations() = %+v, want %+v", got, tt.want)
+			}
+			if got.IsEmpty() != tt.want.IsEmpty() {
+				t.Errorf("IsEmpty() = %v, want %v", got.IsEmpty(), tt.want.IsEmpty())
+			}
+		})
+	}
+}
+
+// memoryPriceHistory is an in-memory PriceHistoryRepository
+type memoryPriceHistory struct {
+	mu      sync.Mutex
+	changes []PriceChange
+	err     error // Returned by Append when set
+}
+
+func (h *memoryPriceHistory) Append(ctx context.Context, change *PriceChange) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err != nil {
+		return h.err
+	}
+	h.changes = append(h.changes, *change)
+	return nil
+}
+
+func (h *memoryPriceHistory) FindByProductID(ctx context.Context, productID string, limit, offset int) ([]PriceChange, int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var matching []PriceChange
+	for i := len(h.changes) - 1; i >= 0; i-- {
+		if h.changes[i].ProductID == productID {
+			matching = append(matching, h.changes[i])
+		}
+	}
+	total := int64(len(matching))
+	matching = matching[min(offset, len(matching)):]
+	return matching[:min(limit, len(matching))], total, nil
+}
+
+func TestUpdateRecordsPriceHistory(t *testing.T) {
+	newProduct := func() *Product {
+		p := NewProduct("company-1", "sale-point-1", "Pizza", "Pizzas", "")
+		p.ID = "pizza"
+		p.PriceVariations = []PriceVariation{{Type: "small", Price: 1000}}
+		return p
+	}
+	name := "Margherita"
+	samePrices := []PriceVariation{{Type: "small", Price: 1000}}
+	newPrices := []PriceVariation{{Type: "small", Price: 1200}}
+
+	tests := []struct {
+		name        string
+		input       UpdateInput
+		wantEntries int
+	}{
+		{"name only", UpdateInput{Name: &name}, 0},
+		{"same prices", UpdateInput{PriceVariations: &samePrices}, 0},
+		{"new prices", UpdateInput{PriceVariations: &newPrices, Actor: "manager-7"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := &memoryPriceHistory{}
+			svc := NewService(newMemoryRepository(newProduct()), WithPriceHistory(history))
+
+			if _, err := svc.Update(context.Background(), "pizza", tt.input); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if len(history.changes) != tt.wantEntries {
+				t.Fatalf("history entries = %d, want %d", len(history.changes), tt.wantEntries)
+			}
+			if tt.wantEntries == 0 {
+				return
+			}
+
+			change := history.changes[0]
+			if change.ProductID != "pizza" || change.Actor != "manager-7" || change.ChangedAt.IsZero() {
+				t.Errorf("change = %+v", change)
+			}
+			if change.Previous[0].Price != 1000 || change.New[0].Price != 1200 {
+				t.Errorf("previous = %+v, new = %+v", change.Previous, change.New)
+			}
+			if len(change.Diff.Changed) != 1 {
+				t.Errorf("diff = %+v, want one changed variation", change.Diff)
+			}
+		})
+	}
+}
+
+func TestUpdateFailsWhenPriceHistoryFails(t *testing.T) {
+	p := NewProduct("company-1", "sale-point-1", "Pizza", "Pizzas", "")
+	p.ID = "pizza"
+	p.PriceVariations = []PriceVariation{{Type: "small", Price: 1000}}
+
+	appendErr := errors.New("connection reset")
+	svc := NewService(newMemoryRepository(p), WithPriceHistory(&memoryPriceHistory{err: appendErr}))
+
+	prices := []PriceVariation{{Type: "small", Price: 1200}}
+	if _, err := svc.Update(context.Background(), "pizza", UpdateInput{PriceVariations: &prices}); !errors.Is(err, appendErr) {
+		t.Fatalf("Update() error = %v, want %v", err, appendErr)
+	}
+}
+
+func TestGetPriceHistory(t *testing.T) {
+	p := NewProduct("company-1", "sale-point-1", "Pizza", "Pizzas", "")
+	p.ID = "pizza"
+	history := &memoryPriceHistory{changes: []PriceChange{
+		{ID: "1", ProductID: "pizza"},
+		{ID: "2", ProductID: "other"},
+		{ID: "3", ProductID: "pizza"},
+	}}
+	svc := NewService(newMemoryRepository(p), WithPriceHistory(history))
+
+	changes, total, err := svc.GetPriceHistory(context.Background(), "pizza", 1, 0)
+	if err != nil {
+		t.Fatalf("GetPriceHistory() error = %v", err)
+	}
+	if total != 2 || len(changes) != 1 || changes[0].ID != "3" {
+		t.Errorf("got %d changes of %d, first %+v; want the newest of 2", len(changes), total, changes)
+	}
+
+	if _, _, err := svc.GetPriceHistory(context.Background(), "missing", 10, 0); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("unknown product error = %v, want %v", err, ErrProductNotFound)
+	}
+}
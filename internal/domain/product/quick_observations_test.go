@@ -0,0 +1,48 @@
+package product
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+func TestValidateQuickObservations(t *testing.T) {
+	tests := []struct {
+		name         string
+		observations []string
+		wantErr      error
+		wantField    string
+	}{
+		{"none", nil, nil, ""},
+		{"valid", []string{"Sin cebolla", "Extra salsa", "Bien cocida"}, nil, ""},
+		{"at the count limit", strings.Split("a b c d e f g h i j", " "), nil, ""},
+		{"at the length limit", []string{strings.Repeat("ñ", MaxQuickObservationLength)}, nil, ""},
+		{"too many", strings.Split("a b c d e f g h i j k", " "), ErrTooManyQuickObservations, "quick_observations"},
+		{"blank", []string{"Sin cebolla", "  "}, ErrInvalidQuickObservation, "quick_observations[1]"},
+		{"too long", []string{strings.Repeat("a", MaxQuickObservationLength+1)}, ErrQuickObservationTooLong, "quick_observations[0]"},
+		{"duplicate ignoring case", []string{"Sin cebolla", "Extra salsa", "SIN CEBOLLA"}, ErrDuplicateQuickObservation, "quick_observations[2]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProduct("company", "sale-point", "Hamburguesa", "Platos", "")
+			p.PriceVariations = []PriceVariation{{Type: "Normal", Price: 15000}}
+			p.IsUnlimitedStock = true
+			p.QuickObservations = tt.observations
+
+			err := p.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				return
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.As(err, &domainErr) || domainErr.Field != tt.wantField {
+				t.Errorf("field = %v, want %q", domainErr, tt.wantField)
+			}
+		})
+	}
+}
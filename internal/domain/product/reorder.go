@@ -0,0 +1,118 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// MaxReorderIDs is the maximum number of products a single reorder may list
+const MaxReorderIDs = 500
+
+// ReorderInput sets the display order of the products of a sale point's category
+type ReorderInput struct {
+	SalePointID string
+	Category    string
+	OrderedIDs  []string // Every live product of the category, first shown first
+}
+
+// ReorderResult reports the outcome of a reorder
+type ReorderResult struct {
+	Reordered int64    `json:"reordered"` // Products whose position changed
+	Missing   []string `json:"missing"`   // Products of the category left out of ordered_ids
+	Unknown   []string `json:"unknown"`   // Listed ids that are not live products of the category
+}
+
+// Validate checks the selector and that ordered_ids lists each product once
+func (input ReorderInput) Validate() error {
+	switch {
+	case input.SalePointID == "":
+		return apperrors.NewDomainError(ErrInvalidSalePointID, "sale_point_id", nil)
+	case input.Category == "":
+		return apperrors.NewDomainError(ErrInvalidCategory, "category", nil)
+	case len(input.OrderedIDs) == 0:
+		return apperrors.NewDomainError(ErrEmptyReorder, "ordered_ids", nil)
+	case len(input.OrderedIDs) > MaxReorderIDs:
+		return apperrors.NewDomainError(ErrReorderTooLarge, "ordered_ids", len(input.OrderedIDs))
+	}
+
+	seen := make(map[string]bool, len(input.OrderedIDs))
+	for i, id := range input.OrderedIDs {
+		field := fmt.Sprintf("ordered_ids[%d]", i)
+		if id == "" {
+			return apperrors.NewIndexedDomainError(ErrEmptyReorder, field, i, id)
+		}
+		if seen[id] {
+			return apperrors.NewIndexedDomainError(ErrDuplicateReorderID, field, i, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Reorder gives the category's products the positions of their IDs in OrderedIDs, in a single write.
+// The IDs must be exactly the live products of the category: when some are left out or do not belong
+// to it, nothing changes and the error is ErrReorderMismatch, with the offending IDs in the result.
+func (s *Service) Reorder(ctx context.Context, input ReorderInput) (*ReorderResult, error) {
+	input.Category = strings.TrimSpace(input.Category)
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.FindIDsBySalePointID(ctx, input.SalePointID, ProductFilters{Category: &input.Category})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category products: %w", err)
+	}
+
+	result := &ReorderResult{Missing: []string{}, Unknown: []string{}}
+	for _, id := range current {
+		if !slices.Contains(input.OrderedIDs, id) {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+	for _, id := range input.OrderedIDs {
+		if !slices.Contains(current, id) {
+			result.Unknown = append(result.Unknown, id)
+		}
+	}
+	if len(result.Missing) > 0 || len(result.Unknown) > 0 {
+		return result, ErrReorderMismatch
+	}
+
+	reordered, err := s.repo.SetPositions(ctx, input.SalePointID, input.Category, input.OrderedIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder products: %w", err)
+	}
+	result.Reordered = reordered
+	return result, nil
+}
+
+// placeLast moves the product to the end of its category
+func (s *Service) placeLast(ctx context.Context, p *Product) error {
+	position, err := s.repo.NextPosition(ctx, p.SalePointID, p.Category)
+	if err != nil {
+		return fmt.Errorf("failed to get category position: %w", err)
+	}
+	p.Position = position
+	return nil
+}
+
+// placeAllLast moves new products of a sale point to the end of their categories, in the given order
+func (s *Service) placeAllLast(ctx context.Context, products []*Product) error {
+	next := make(map[string]int)
+	for _, p := range products {
+		position, ok := next[p.Category]
+		if !ok {
+			if err := s.placeLast(ctx, p); err != nil {
+				return err
+			}
+			position = p.Position
+		}
+		p.Position = position
+		next[p.Category] = position + 1
+	}
+	return nil
+}
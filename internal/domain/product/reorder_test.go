@@ -0,0 +1,200 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReorder(t *testing.T) {
+	deletedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tooMany := make([]string, MaxReorderIDs+1)
+
+	tests := []struct {
+		name          string
+		input         ReorderInput
+		wantErr       error
+		wantResult    *ReorderResult
+		wantPositions map[string]int
+	}{
+		{
+			name:          "reversed",
+			input:         ReorderInput{SalePointID: "sp1", Category: " Pizzas ", OrderedIDs: []string{"hawaiana", "pepperoni", "margarita"}},
+			wantResult:    &ReorderResult{Reordered: 2, Missing: []string{}, Unknown: []string{}},
+			wantPositions: map[string]int{"hawaiana": 0, "pepperoni": 1, "margarita": 2},
+		},
+		{
+			name:          "same order",
+			input:         ReorderInput{SalePointID: "sp1", Category: "Pizzas", OrderedIDs: []string{"margarita", "pepperoni", "hawaiana"}},
+			wantResult:    &ReorderResult{Reordered: 0, Missing: []string{}, Unknown: []string{}},
+			wantPositions: map[string]int{"margarita": 0, "pepperoni": 1, "hawaiana": 2},
+		},
+		{
+			name:       "missing product",
+			input:      ReorderInput{SalePointID: "sp1", Category: "Pizzas", OrderedIDs: []string{"hawaiana", "margarita"}},
+			wantErr:    ErrReorderMismatch,
+			wantResult: &ReorderResult{Missing: []string{"pepperoni"}, Unknown: []string{}},
+		},
+		{
+			name:       "foreign products",
+			input:      ReorderInput{SalePointID: "sp1", Category: "Pizzas", OrderedIDs: []string{"hawaiana", "margarita", "pepperoni", "soda", "other-pizza", "deleted", "nope"}},
+			wantErr:    ErrReorderMismatch,
+			wantResult: &ReorderResult{Missing: []string{}, Unknown: []string{"soda", "other-pizza", "deleted", "nope"}},
+		},
+		{name: "duplicate id", input: ReorderInput{SalePointID: "sp1", Category: "Pizzas", OrderedIDs: []string{"hawaiana", "hawaiana"}}, wantErr: ErrDuplicateReorderID},
+		{name: "blank id", input: ReorderInput{SalePointID: "sp1", Category: "Pizzas", OrderedIDs: []string{"hawaiana", ""}}, wantErr: ErrEmptyReorder},
+		{name: "no ids", input: ReorderInput{SalePointID: "sp1", Category: "Pizzas"}, wantErr: ErrEmptyReorder},
+		{name: "too many ids", input: ReorderInput{SalePointID: "sp1", Category: "Pizzas", OrderedIDs: tooMany}, wantErr: ErrReorderTooLarge},
+		{name: "no category", input: ReorderInput{SalePointID: "sp1", Category: " ", OrderedIDs: []string{"hawaiana"}}, wantErr: ErrInvalidCategory},
+		{name: "no sale point", input: ReorderInput{Category: "Pizzas", OrderedIDs: []string{"hawaiana"}}, wantErr: ErrInvalidSalePointID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(
+				&Product{ID: "margarita", SalePointID: "sp1", Category: "Pizzas", Position: 0},
+				&Product{ID: "pepperoni", SalePointID: "sp1", Category: "Pizzas", Position: 1},
+				&Product{ID: "hawaiana", SalePointID: "sp1", Category: "Pizzas", Position: 2},
+				&Product{ID: "soda", SalePointID: "sp1", Category: "Drinks"},
+				&Product{ID: "other-pizza", SalePointID: "sp2", Category: "Pizzas"},
+				&Product{ID: "deleted", SalePointID: "sp1", Category: "Pizzas", Position: 3, DeletedAt: &deletedAt},
+			)
+
+			result, err := NewService(repo).Reorder(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantResult != nil && !reflect.DeepEqual(result, tt.wantResult) {
+				t.Errorf("result = %+v, want %+v", result, tt.wantResult)
+			}
+			for id, want := range tt.wantPositions {
+				if got := repo.stored(id).Position; got != want {
+					t.Errorf("position of %s = %d, want %d", id, got, want)
+				}
+			}
+			if err != nil && repo.stored("hawaiana").Position != 2 {
+				t.Error("a rejected reorder moved products")
+			}
+		})
+	}
+}
+
+// menu lists the names of the live products of the sale point's category in menu order
+func menu(repo *memoryRepository, salePointID, category string) []string {
+	repo.mu.Lock()
+	var products []*Product
+	for _, p := range repo.products {
+		if p.SalePointID == salePointID && p.Category == category && !p.IsDeleted() {
+			products = append(products, p)
+		}
+	}
+	repo.mu.Unlock()
+
+	slices.SortFunc(products, compareMenuOrder)
+	names := make([]string, len(products))
+	for i, p := range products {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func TestPositionsAcrossCreatesAndReorders(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	service := NewService(repo)
+
+	ids := map[string]string{}
+	create := func(name, category string) {
+		t.Helper()
+		p, err := service.Create(ctx, CreateInput{
+			CompanyID: "c1", SalePointID: "sp1", Name: name, Category: category, IsUnlimitedStock: true,
+			PriceVariations: []PriceVariation{{Type: DefaultVariationType, Price: 1000}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = p.ID
+	}
+	reorder := func(names ...string) {
+		t.Helper()
+		ordered := make([]string, len(names))
+		for i, name := range names {
+			ordered[i] = ids[name]
+		}
+		if _, err := service.Reorder(ctx, ReorderInput{SalePointID: "sp1", Category: "Pizzas", OrderedIDs: ordered}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(category string, want ...string) {
+		t.Helper()
+		if got := menu(repo, "sp1", category); !slices.Equal(got, want) {
+			t.Fatalf("%s menu = %v, want %v", category, got, want)
+		}
+	}
+
+	create("Margarita", "Pizzas")
+	create("Pepperoni", "Pizzas")
+	create("Limonada", "Drinks")
+	create("Hawaiana", "Pizzas")
+	check("Pizzas", "Margarita", "Pepperoni", "Hawaiana")
+	check("Drinks", "Limonada")
+
+	reorder("Hawaiana", "Margarita", "Pepperoni")
+	create("Napolitana", "Pizzas")
+	check("Pizzas", "Hawaiana", "Margarita", "Pepperoni", "Napolitana")
+
+	reorder("Napolitana", "Hawaiana", "Pepperoni", "Margarita")
+	check("Pizzas", "Napolitana", "Hawaiana", "Pepperoni", "Margarita")
+
+	// Deleted products no longer count for the end of the category
+	if err := service.Delete(ctx, ids["Margarita"], false); err != nil {
+		t.Fatal(err)
+	}
+	create("Vegetariana", "Pizzas")
+	check("Pizzas", "Napolitana", "Hawaiana", "Pepperoni", "Vegetariana")
+
+	// Moving a product to another category shows it last there
+	drinks := "Drinks"
+	if _, err := service.Update(ctx, ids["Hawaiana"], UpdateInput{Category: &drinks}); err != nil {
+		t.Fatal(err)
+	}
+	check("Drinks", "Limonada", "Hawaiana")
+	check("Pizzas", "Napolitana", "Pepperoni", "Vegetariana")
+
+	// Other updates keep the position
+	name := "Pepperoni Doble"
+	if _, err := service.Update(ctx, ids["Pepperoni"], UpdateInput{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	check("Pizzas", "Napolitana", "Pepperoni Doble", "Vegetariana")
+}
+
+func TestImportPlacesProductsLast(t *testing.T) {
+	repo := newMemoryRepository(&Product{ID: "margarita", SalePointID: "sp1", Category: "Pizzas", Position: 4})
+	service := NewService(repo)
+
+	_, err := service.Import(context.Background(), ImportInput{
+		CompanyID:   "c1",
+		SalePointID: "sp1",
+		Rows: []ImportRow{
+			{Line: 2, Name: "Pepperoni", Category: "Pizzas", Price: "1000"},
+			{Line: 3, Name: "Limonada", Category: "Drinks", Price: "500"},
+			{Line: 4, Name: "Hawaiana", Category: "Pizzas", Price: "1000"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	positions := map[string]int{}
+	for _, p := range repo.products {
+		positions[p.Name] = p.Position
+	}
+	want := map[string]int{"": 4, "Pepperoni": 5, "Limonada": 0, "Hawaiana": 6}
+	if !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+}
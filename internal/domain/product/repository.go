@@ -1,44 +1,152 @@
 package product
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
 
 // ProductFilters represents filters for querying products
 type ProductFilters struct {
-	CompanyID   *string
-	SalePointID *string
-	Category    *string
-	IsAvailable *bool
-	IsAddon     *bool
-	Limit       int
-	Offset      int
+	CompanyID      *string
+	SalePointID    *string // Narrows company listings to one sale point
+	Category       *string
+	Categories     []string // Any of these categories (combined with Category)
+	MinPrice       *int64   // Some price variation costs at least this (cents)
+	MaxPrice       *int64   // Some price variation costs at most this (cents)
+	IsAvailable    *bool
+	IsAddon        *bool
+	AvailableNow   bool     // Only products that can be ordered now, schedules included (evaluated by the service)
+	Search         *string  // Words in the name or description, ignoring case and accents
+	Tags           []string // Lowercase tags; products need any of them, or all with TagsMode TagsAll
+	TagsMode       TagsMode
+	SkipCount      bool             // Listings skip the total count and report -1
+	IncludeDeleted bool             // Include soft deleted products (admin listings)
+	Cursor         *util.PageCursor // Cursor pagination: list the products after it instead of skipping Offset
+	Limit          int
+	Offset         int
+}
+
+// CategoryFilters represents filters for listing categories and tags
+type CategoryFilters struct {
+	OnlyWithAvailable bool // Drop categories without any available product
+	All               bool // Return every category, ignoring Limit and Offset
+	Limit             int
+	Offset            int
+}
+
+// CategorySummary represents a category with its product counts
+type CategorySummary struct {
+	Name           string `json:"name" bson:"name"`
+	ProductCount   int    `json:"product_count" bson:"product_count"`
+	AvailableCount int    `json:"available_count" bson:"available_count"`
 }
 
 // Repository defines the contract for product data operations
 type Repository interface {
-	// Create creates a new product
+	// Create creates a new product. Create and Update fail with ErrDuplicateSKU when another
+	// live product of the sale point has the SKU, and with ErrDuplicateBarcode when another
+	// live product of the company has the barcode.
 	Create(ctx context.Context, product *Product) error
 
+	// CreateMany creates the products in one insert
+	CreateMany(ctx context.Context, products []*Product) error
+
 	// FindByID retrieves a product by its ID
 	FindByID(ctx context.Context, id string) (*Product, error)
 
+	// FindByIDs retrieves the products with the given IDs in any order; IDs that match no product are
+	// left out. Soft deleted products are only included with includeDeleted.
+	FindByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*Product, error)
+
+	// FindBySKU retrieves the live product of a sale point with the given normalized SKU
+	FindBySKU(ctx context.Context, salePointID, sku string) (*Product, error)
+
+	// FindByBarcode retrieves the live product of a company with the given normalized barcode
+	FindByBarcode(ctx context.Context, companyID, barcode string) (*Product, error)
+
 	// FindByCompanyID retrieves all products for a company with optional filters
 	FindByCompanyID(ctx context.Context, companyID string, filters ProductFilters) ([]*Product, error)
 
 	// FindBySalePointID retrieves all products for a sale point with optional filters
 	FindBySalePointID(ctx context.Context, salePointID string, filters ProductFilters) ([]*Product, error)
 
+	// EachByCompanyID walks a company's products matching filters oldest first through a single cursor,
+	// calling fn for each one; pagination is ignored. It stops at the first fn error and returns it.
+	EachByCompanyID(ctx context.Context, companyID string, filters ProductFilters, fn func(*Product) error) error
+
+	// EachBySalePointID walks a sale point's products matching filters in menu order through a single cursor,
+	// calling fn for each one; pagination is ignored. It stops at the first fn error and returns it.
+	EachBySalePointID(ctx context.Context, salePointID string, filters ProductFilters, fn func(*Product) error) error
+
 	// FindAll retrieves all products with optional filters (deprecated, use FindByCompanyID or FindBySalePointID)
 	FindAll(ctx context.Context, limit, offset int) ([]*Product, error)
 
-	// Update updates an existing product
+	// Update updates an existing product if its stored version is still the product's, and increments it.
+	// It fails with ErrProductConflict when another write changed the product since it was loaded.
 	Update(ctx context.Context, product *Product) error
 
-	// Delete deletes a product by ID
+	// UpdateMany updates the live products with one bulk write and returns the number matched; like
+	// Update it increments their versions and skips products whose stored version moved on, leaving
+	// the skipped products as given.
+	// Every other write also increments the version of the products it changes.
+	UpdateMany(ctx context.Context, products []*Product) (int64, error)
+
+	// AdjustStock atomically applies the adjustment to a live product with limited stock and returns
+	// the updated product. It fails with ErrCannotUpdateStockForUnlimited for unlimited stock products
+	// and with ErrInsufficientStock when the stock would go below zero.
+	AdjustStock(ctx context.Context, id string, adjustment StockAdjustment) (*Product, error)
+
+	// Delete removes a product by ID for good, whether or not it was soft deleted
 	Delete(ctx context.Context, id string) error
 
-	// FindCategories retrieves all unique categories
-	FindCategoriesByCompanyID(ctx context.Context, companyID string) ([]string, error)
-	FindCategoriesBySalePointID(ctx context.Context, salePointID string) ([]string, error)
+	// SoftDelete marks a product as deleted at the given time; soft deleted products are not found again
+	SoftDelete(ctx context.Context, id string, at time.Time) error
+
+	// Restore clears the deleted mark of a product; restoring a live product is a no-op.
+	// It fails with ErrDuplicateSKU or ErrDuplicateBarcode when a live product took the SKU or barcode meanwhile.
+	Restore(ctx context.Context, id string) error
+
+	// FindIDsBySalePointID retrieves the IDs of the sale point's products matching filters (pagination is ignored)
+	FindIDsBySalePointID(ctx context.Context, salePointID string, filters ProductFilters) ([]string, error)
+
+	// NextPosition returns the position after the last live product of the sale point's category, 0 when it has none
+	NextPosition(ctx context.Context, salePointID, category string) (int, error)
+
+	// SetPositions gives the live products of the sale point's category the positions of their IDs in
+	// orderedIDs with a single bulk write, and returns the number whose position changed
+	SetPositions(ctx context.Context, salePointID, category string, orderedIDs []string) (int64, error)
+
+	// FindLowStock retrieves up to limit products with limited stock at or below the threshold, lowest stock first
+	FindLowStock(ctx context.Context, threshold, limit int) ([]*Product, error)
+
+	// SetAvailability sets is_available on the selected live products in a single update
+	SetAvailability(ctx context.Context, selector AvailabilitySelector, available bool) (*BulkAvailabilityResult, error)
+
+	// DeleteMany deletes the products with the given IDs for good and returns the number deleted
+	DeleteMany(ctx context.Context, ids []string) (int64, error)
+
+	// SoftDeleteMany marks the live products with the given IDs as deleted at the given time and returns the number marked
+	SoftDeleteMany(ctx context.Context, ids []string, at time.Time) (int64, error)
+
+	// FindCategories retrieves the unique categories, sorted by name, with the total number of categories
+	FindCategoriesByCompanyID(ctx context.Context, companyID string, filters CategoryFilters) ([]CategorySummary, int64, error)
+	FindCategoriesBySalePointID(ctx context.Context, salePointID string, filters CategoryFilters) ([]CategorySummary, int64, error)
+
+	// RenameCategory sets the category to on the scope's products in any of the from categories,
+	// soft deleted ones included, and returns the number changed
+	RenameCategory(ctx context.Context, scope CategoryScope, from []string, to string) (int64, error)
+
+	// FindTagsByCompanyID retrieves the distinct tags of a company's products, sorted by name, with the total number of tags
+	FindTagsByCompanyID(ctx context.Context, companyID string, filters CategoryFilters) ([]TagSummary, int64, error)
+
+	// SummarizeByCompanyID counts a company's live products by availability, overall and per category
+	SummarizeByCompanyID(ctx context.Context, companyID string) (*CompanySummary, error)
+
+	// FindMenu groups a sale point's live products by category with a single aggregation, returning
+	// a page of categories with up to filters.ProductLimit products each, and the number of categories
+	FindMenu(ctx context.Context, salePointID string, filters MenuFilters) ([]MenuSection, int64, error)
 
 	// Count returns the total number of products
 	Count(ctx context.Context) (int64, error)
@@ -49,6 +157,14 @@ type Repository interface {
 	// CountBySalePointID returns the total number of products for a sale point with filters
 	CountBySalePointID(ctx context.Context, salePointID string, filters ProductFilters) (int64, error)
 
+	// FindByCompanyIDWithCount retrieves one page of a company's products and the total matching filters
+	// in a single query. The total is -1 when filters.SkipCount is set.
+	FindByCompanyIDWithCount(ctx context.Context, companyID string, filters ProductFilters) ([]*Product, int64, error)
+
+	// FindBySalePointIDWithCount retrieves one page of a sale point's products and the total matching filters
+	// in a single query. The total is -1 when filters.SkipCount is set.
+	FindBySalePointIDWithCount(ctx context.Context, salePointID string, filters ProductFilters) ([]*Product, int64, error)
+
 	// Exists checks if a product exists
 	Exists(ctx context.Context, id string) (bool, error)
 }
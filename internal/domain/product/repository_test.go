@@ -0,0 +1,512 @@
+package product
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// memoryRepository is an in-memory Repository for service tests; methods a test needs but it
+// does not implement panic through the embedded nil interface.
+type memoryRepository struct {
+	Repository
+
+	mu       sync.Mutex
+	products map[string]*Product // By ID, stored as copies
+	listed   []ProductFilters    // The filters of every listing call
+	listErr  error               // Returned by listing calls when set
+	batches  []int               // The size of every CreateMany and UpdateMany call
+
+	categoryFilters []CategoryFilters // The filters of every category listing call
+}
+
+func newMemoryRepository(products ...*Product) *memoryRepository {
+	r := &memoryRepository{products: make(map[string]*Product)}
+	for _, p := range products {
+		r.products[p.ID] = cloneProduct(p)
+	}
+	return r
+}
+
+// cloneProduct copies the product and the slices a service may modify in place
+func cloneProduct(p *Product) *Product {
+	c := *p
+	c.Photos = slices.Clone(p.Photos)
+	c.PriceVariations = slices.Clone(p.PriceVariations)
+	c.AvailableAddons = slices.Clone(p.AvailableAddons)
+	if p.SKU != nil {
+		sku := *p.SKU
+		c.SKU = &sku
+	}
+	if p.Stock != nil {
+		stock := *p.Stock
+		c.Stock = &stock
+	}
+	return &c
+}
+
+func (r *memoryRepository) Create(ctx context.Context, p *Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.products[p.ID] = cloneProduct(p)
+	return nil
+}
+
+func (r *memoryRepository) CreateMany(ctx context.Context, products []*Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, len(products))
+	for _, p := range products {
+		r.products[p.ID] = cloneProduct(p)
+	}
+	return nil
+}
+
+// UpdateMany skips soft deleted products like the MongoDB bulk write
+func (r *memoryRepository) UpdateMany(ctx context.Context, products []*Product) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, len(products))
+	var matched int64
+	for _, p := range products {
+		if stored, ok := r.products[p.ID]; ok && !stored.IsDeleted() && stored.Version == p.Version {
+			p.Version++
+			r.products[p.ID] = cloneProduct(p)
+			matched++
+		}
+	}
+	return matched, nil
+}
+
+// FindByID skips soft deleted products like the MongoDB repository
+func (r *memoryRepository) FindByID(ctx context.Context, id string) (*Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.products[id]; ok && !p.IsDeleted() {
+		return cloneProduct(p), nil
+	}
+	return nil, ErrProductNotFound
+}
+
+// FindByIDs returns the matching products in no particular order, like a MongoDB $in query
+func (r *memoryRepository) FindByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*Product
+	for _, p := range r.products {
+		if slices.Contains(ids, p.ID) && (includeDeleted || !p.IsDeleted()) {
+			found = append(found, cloneProduct(p))
+		}
+	}
+	return found, nil
+}
+
+// FindBySKU skips soft deleted products like the MongoDB repository
+func (r *memoryRepository) FindBySKU(ctx context.Context, salePointID, sku string) (*Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.products {
+		if p.SalePointID == salePointID && p.SKU != nil && *p.SKU == sku && !p.IsDeleted() {
+			return cloneProduct(p), nil
+		}
+	}
+	return nil, ErrProductNotFound
+}
+
+// FindByBarcode skips soft deleted products like the MongoDB repository
+func (r *memoryRepository) FindByBarcode(ctx context.Context, companyID, barcode string) (*Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.products {
+		if p.CompanyID == companyID && p.Barcode != nil && *p.Barcode == barcode && !p.IsDeleted() {
+			return cloneProduct(p), nil
+		}
+	}
+	return nil, ErrProductNotFound
+}
+
+// Exists skips soft deleted products like the MongoDB repository
+func (r *memoryRepository) Exists(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	return ok && !p.IsDeleted(), nil
+}
+
+// Update checks the loaded version and increments it like the MongoDB repository
+func (r *memoryRepository) Update(ctx context.Context, p *Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.products[p.ID]
+	switch {
+	case !ok || stored.IsDeleted():
+		return ErrProductNotFound
+	case stored.Version != p.Version:
+		return ErrProductConflict
+	}
+	p.Version++
+	r.products[p.ID] = cloneProduct(p)
+	return nil
+}
+
+// AdjustStock applies the adjustment under the lock, guarding the stock like the MongoDB filter does
+func (r *memoryRepository) AdjustStock(ctx context.Context, id string, adjustment StockAdjustment) (*Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	switch {
+	case !ok || p.IsDeleted():
+		return nil, ErrProductNotFound
+	case p.IsUnlimitedStock:
+		return nil, ErrCannotUpdateStockForUnlimited
+	}
+
+	stock := 0
+	if adjustment.Set != nil {
+		stock = *adjustment.Set
+	} else {
+		if p.Stock != nil {
+			stock = *p.Stock
+		}
+		stock += *adjustment.Delta
+		if stock < 0 {
+			return nil, ErrInsufficientStock
+		}
+	}
+	p.Stock, p.UpdatedAt = &stock, time.Now()
+	p.Version++
+	return cloneProduct(p), nil
+}
+
+// FindByCompanyIDWithCount records the filters and returns the company's products without further
+// filtering. Cursor pages are sorted and seek past the cursor like the MongoDB repository.
+func (r *memoryRepository) FindByCompanyIDWithCount(ctx context.Context, companyID string, filters ProductFilters) ([]*Product, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listed = append(r.listed, filters)
+	if r.listErr != nil {
+		return nil, 0, r.listErr
+	}
+	var page []*Product
+	for _, p := range r.products {
+		if p.CompanyID == companyID && (filters.Cursor == nil || after(p, *filters.Cursor)) {
+			page = append(page, cloneProduct(p))
+		}
+	}
+	if filters.Cursor != nil {
+		slices.SortFunc(page, func(a, b *Product) int {
+			if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+				return c
+			}
+			return strings.Compare(b.ID, a.ID)
+		})
+		page = page[:min(filters.Limit, len(page))]
+	}
+	total := int64(len(page))
+	if filters.SkipCount {
+		total = -1
+	}
+	return page, total, nil
+}
+
+// EachByCompanyID records the filters and walks the company's products oldest first, honoring the
+// sale point and soft delete filters only
+func (r *memoryRepository) EachByCompanyID(ctx context.Context, companyID string, filters ProductFilters, fn func(*Product) error) error {
+	r.mu.Lock()
+	r.listed = append(r.listed, filters)
+	var matched []*Product
+	for _, p := range r.products {
+		if p.CompanyID != companyID || (p.IsDeleted() && !filters.IncludeDeleted) {
+			continue
+		}
+		if filters.SalePointID == nil || p.SalePointID == *filters.SalePointID {
+			matched = append(matched, cloneProduct(p))
+		}
+	}
+	r.mu.Unlock()
+
+	slices.SortFunc(matched, func(a, b *Product) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	for _, p := range matched {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EachBySalePointID walks the sale point's live products in menu order
+func (r *memoryRepository) EachBySalePointID(ctx context.Context, salePointID string, filters ProductFilters, fn func(*Product) error) error {
+	r.mu.Lock()
+	var matched []*Product
+	for _, p := range r.products {
+		if p.SalePointID == salePointID && !p.IsDeleted() {
+			matched = append(matched, cloneProduct(p))
+		}
+	}
+	r.mu.Unlock()
+
+	slices.SortFunc(matched, compareMenuOrder)
+	for _, p := range matched {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareMenuOrder orders products like the MongoDB menu order: by category, position, creation and ID
+func compareMenuOrder(a, b *Product) int {
+	if c := strings.Compare(a.Category, b.Category); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Position, b.Position); c != 0 {
+		return c
+	}
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// after reports whether p is listed after the cursor, newest first
+func after(p *Product, cursor util.PageCursor) bool {
+	if cursor.IsStart() {
+		return true
+	}
+	createdAt, _ := time.Parse(time.RFC3339Nano, cursor.Value)
+	return p.CreatedAt.Before(createdAt) || (p.CreatedAt.Equal(createdAt) && p.ID < cursor.ID)
+}
+
+func (r *memoryRepository) FindCategoriesByCompanyID(ctx context.Context, companyID string, filters CategoryFilters) ([]CategorySummary, int64, error) {
+	return r.findCategories(func(p *Product) bool { return p.CompanyID == companyID }, filters)
+}
+
+func (r *memoryRepository) FindCategoriesBySalePointID(ctx context.Context, salePointID string, filters CategoryFilters) ([]CategorySummary, int64, error) {
+	return r.findCategories(func(p *Product) bool { return p.SalePointID == salePointID }, filters)
+}
+
+// findCategories groups the matching products by category like the aggregation does
+func (r *memoryRepository) findCategories(match func(p *Product) bool, filters CategoryFilters) ([]CategorySummary, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.categoryFilters = append(r.categoryFilters, filters)
+
+	byName := make(map[string]*CategorySummary)
+	for _, p := range r.products {
+		if !match(p) || p.Category == "" {
+			continue
+		}
+		summary, ok := byName[p.Category]
+		if !ok {
+			summary = &CategorySummary{Name: p.Category}
+			byName[p.Category] = summary
+		}
+		summary.ProductCount++
+		if p.IsAvailable {
+			summary.AvailableCount++
+		}
+	}
+
+	var summaries []CategorySummary
+	for _, summary := range byName {
+		if !filters.OnlyWithAvailable || summary.AvailableCount > 0 {
+			summaries = append(summaries, *summary)
+		}
+	}
+	slices.SortFunc(summaries, func(a, b CategorySummary) int { return strings.Compare(a.Name, b.Name) })
+
+	total := int64(len(summaries))
+	if !filters.All {
+		summaries = summaries[min(filters.Offset, len(summaries)):]
+		summaries = summaries[:min(filters.Limit, len(summaries))]
+	}
+	return summaries, total, nil
+}
+
+// RenameCategory renames matching products, soft deleted ones included, like the MongoDB repository
+func (r *memoryRepository) RenameCategory(ctx context.Context, scope CategoryScope, from []string, to string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var renamed int64
+	for _, p := range r.products {
+		if p.CompanyID != scope.CompanyID || (scope.SalePointID != "" && p.SalePointID != scope.SalePointID) {
+			continue
+		}
+		if p.Category != to && slices.Contains(from, p.Category) {
+			p.Category = to
+			p.Version++
+			renamed++
+		}
+	}
+	return renamed, nil
+}
+
+// FindBySalePointID records the filters and returns the sale point's live products sorted by name,
+// honoring the availability filter only
+func (r *memoryRepository) FindBySalePointID(ctx context.Context, salePointID string, filters ProductFilters) ([]*Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listed = append(r.listed, filters)
+	var products []*Product
+	for _, p := range r.products {
+		if p.SalePointID != salePointID || p.IsDeleted() || (filters.IsAvailable != nil && p.IsAvailable != *filters.IsAvailable) {
+			continue
+		}
+		products = append(products, cloneProduct(p))
+	}
+	slices.SortFunc(products, func(a, b *Product) int { return strings.Compare(a.Name, b.Name) })
+	return products, nil
+}
+
+// FindIDsBySalePointID returns the IDs of the sale point's live products, honoring the category filter only
+func (r *memoryRepository) FindIDsBySalePointID(ctx context.Context, salePointID string, filters ProductFilters) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, p := range r.products {
+		if p.SalePointID != salePointID || p.IsDeleted() || (filters.Category != nil && p.Category != *filters.Category) {
+			continue
+		}
+		ids = append(ids, p.ID)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// NextPosition returns the position after the highest of the category's live products
+func (r *memoryRepository) NextPosition(ctx context.Context, salePointID, category string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next := 0
+	for _, p := range r.products {
+		if p.SalePointID == salePointID && p.Category == category && !p.IsDeleted() {
+			next = max(next, p.Position+1)
+		}
+	}
+	return next, nil
+}
+
+// SetPositions applies the positions with the guards of the MongoDB bulk write
+func (r *memoryRepository) SetPositions(ctx context.Context, salePointID, category string, orderedIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var modified int64
+	for i, id := range orderedIDs {
+		p, ok := r.products[id]
+		if !ok || p.SalePointID != salePointID || p.Category != category || p.IsDeleted() || p.Position == i {
+			continue
+		}
+		p.Position = i
+		p.Version++
+		modified++
+	}
+	return modified, nil
+}
+
+// Delete removes the product for good, soft deleted or not
+func (r *memoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.products[id]; !ok {
+		return ErrProductNotFound
+	}
+	delete(r.products, id)
+	return nil
+}
+
+// SoftDelete marks a live product as deleted
+func (r *memoryRepository) SoftDelete(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	if !ok || p.IsDeleted() {
+		return ErrProductNotFound
+	}
+	p.DeletedAt, p.UpdatedAt = &at, at
+	p.Version++
+	return nil
+}
+
+// Restore clears the deleted mark; live products match too
+func (r *memoryRepository) Restore(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	if !ok {
+		return ErrProductNotFound
+	}
+	p.DeletedAt = nil
+	p.Version++
+	return nil
+}
+
+// SetAvailability applies the selector like the MongoDB repository's filter
+func (r *memoryRepository) SetAvailability(ctx context.Context, selector AvailabilitySelector, available bool) (*BulkAvailabilityResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := &BulkAvailabilityResult{}
+	for _, p := range r.products {
+		switch {
+		case p.IsDeleted(),
+			len(selector.IDs) > 0 && !slices.Contains(selector.IDs, p.ID),
+			selector.SalePointID != "" && p.SalePointID != selector.SalePointID,
+			selector.Category != nil && p.Category != *selector.Category:
+			continue
+		}
+		result.Matched++
+		p.Version++
+		if p.IsAvailable != available {
+			p.IsAvailable = available
+			result.Modified++
+		}
+	}
+	return result, nil
+}
+
+// DeleteMany removes the products like the MongoDB repository, counting only those that existed
+func (r *memoryRepository) DeleteMany(ctx context.Context, ids []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for _, id := range ids {
+		if _, ok := r.products[id]; ok {
+			delete(r.products, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// SoftDeleteMany marks the live products like the MongoDB repository, counting only those marked
+func (r *memoryRepository) SoftDeleteMany(ctx context.Context, ids []string, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for _, id := range ids {
+		if p, ok := r.products[id]; ok && !p.IsDeleted() {
+			p.DeletedAt, p.UpdatedAt = &at, at
+			p.Version++
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *memoryRepository) stored(id string) *Product {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.products[id]; ok {
+		return cloneProduct(p)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package product
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// MaxScheduleWindows is the maximum number of windows of an availability schedule
+const MaxScheduleWindows = 50
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+)
+
+// scheduleDays maps the weekday names of schedule windows to their position in the week, Monday first
+var scheduleDays = map[string]int{
+	"monday": 0, "tuesday": 1, "wednesday": 2, "thursday": 3, "friday": 4, "saturday": 5, "sunday": 6,
+}
+
+// AvailabilitySchedule limits the times a product can be ordered to weekly windows in a timezone.
+// Products without a schedule can be ordered at any time.
+type AvailabilitySchedule struct {
+	Timezone string               `json:"timezone" bson:"timezone"` // IANA name, e.g. America/Bogota
+	Windows  []AvailabilityWindow `json:"windows" bson:"windows"`
+}
+
+// AvailabilityWindow is a weekly time range starting on Day. A window ending at or before its start
+// crosses midnight and ends on the next day, e.g. friday 22:00-02:00 ends early on Saturday.
+type AvailabilityWindow struct {
+	Day   string `json:"day" bson:"day"`     // monday to sunday
+	Start string `json:"start" bson:"start"` // HH:MM
+	End   string `json:"end" bson:"end"`     // HH:MM; 24:00 ends with the day
+}
+
+// interval is a window as minutes of the week, Monday 00:00 being 0; end may pass the end of the week
+type interval struct {
+	start, end int
+}
+
+// NormalizeSchedule trims and lowercases the schedule's fields. A schedule without windows is no schedule.
+func NormalizeSchedule(schedule *AvailabilitySchedule) *AvailabilitySchedule {
+	if schedule == nil || len(schedule.Windows) == 0 {
+		return nil
+	}
+	normalized := &AvailabilitySchedule{
+		Timezone: strings.TrimSpace(schedule.Timezone),
+		Windows:  make([]AvailabilityWindow, len(schedule.Windows)),
+	}
+	for i, w := range schedule.Windows {
+		normalized.Windows[i] = AvailabilityWindow{
+			Day:   strings.ToLower(strings.TrimSpace(w.Day)),
+			Start: strings.TrimSpace(w.Start),
+			End:   strings.TrimSpace(w.End),
+		}
+	}
+	return normalized
+}
+
+// Validate checks the timezone and that the windows are well formed and don't overlap
+func (s *AvailabilitySchedule) Validate() error {
+	if _, err := scheduleLocation(s.Timezone); s.Timezone == "" || err != nil {
+		return apperrors.NewDomainError(ErrInvalidScheduleTimezone, "availability_schedule.timezone", s.Timezone)
+	}
+	if len(s.Windows) > MaxScheduleWindows {
+		return apperrors.NewDomainError(ErrTooManyScheduleWindows, "availability_schedule.windows", len(s.Windows))
+	}
+
+	type indexed struct {
+		interval
+		index int
+	}
+	var intervals []indexed
+	for i, w := range s.Windows {
+		iv, err := w.interval()
+		if err != nil {
+			return apperrors.NewIndexedDomainError(err, fmt.Sprintf("availability_schedule.windows[%d]", i), i, w)
+		}
+		// A window running past Sunday midnight wraps to the start of the week
+		if iv.end > minutesPerWeek {
+			intervals = append(intervals, indexed{interval{0, iv.end - minutesPerWeek}, i})
+			iv.end = minutesPerWeek
+		}
+		intervals = append(intervals, indexed{iv, i})
+	}
+
+	slices.SortFunc(intervals, func(a, b indexed) int { return a.start - b.start })
+	for k := 1; k < len(intervals); k++ {
+		if prev, cur := intervals[k-1], intervals[k]; cur.start < prev.end {
+			i := max(prev.index, cur.index)
+			return apperrors.NewIndexedDomainError(
+				fmt.Errorf("%w: windows %d and %d", ErrOverlappingScheduleWindows, min(prev.index, cur.index), i),
+				fmt.Sprintf("availability_schedule.windows[%d]", i), i, s.Windows[i])
+		}
+	}
+	return nil
+}
+
+// OpenAt reports whether the time falls within one of the windows, in the schedule's timezone.
+// A schedule whose timezone cannot be loaded is never open; Validate rejects those.
+func (s *AvailabilitySchedule) OpenAt(t time.Time) bool {
+	loc, err := scheduleLocation(s.Timezone)
+	if err != nil {
+		return false
+	}
+	t = t.In(loc)
+	minute := ((int(t.Weekday())+6)%7)*minutesPerDay + t.Hour()*60 + t.Minute()
+
+	for _, w := range s.Windows {
+		iv, err := w.interval()
+		if err != nil {
+			continue
+		}
+		// The early hours of Monday may belong to a window that started on Sunday
+		if (minute >= iv.start && minute < iv.end) || minute+minutesPerWeek < iv.end {
+			return true
+		}
+	}
+	return false
+}
+
+// interval converts the window to minutes of the week
+func (w AvailabilityWindow) interval() (interval, error) {
+	day, ok := scheduleDays[w.Day]
+	if !ok {
+		return interval{}, ErrInvalidScheduleDay
+	}
+	start, ok := parseClock(w.Start)
+	if !ok || start == minutesPerDay {
+		return interval{}, ErrInvalidScheduleTime
+	}
+	end, ok := parseClock(w.End)
+	if !ok {
+		return interval{}, ErrInvalidScheduleTime
+	}
+	if end == start {
+		return interval{}, ErrEmptyScheduleWindow
+	}
+	if end < start {
+		end += minutesPerDay // Crosses midnight
+	}
+	offset := day * minutesPerDay
+	return interval{offset + start, offset + end}, nil
+}
+
+// parseClock reads an HH:MM time of day as minutes since midnight; 24:00 is the end of the day
+func parseClock(value string) (int, bool) {
+	if value == "24:00" {
+		return minutesPerDay, true
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// scheduleLocations caches the timezones of schedules, which listings evaluate for every product
+var scheduleLocations sync.Map
+
+// scheduleLocation loads a schedule's timezone once
+func scheduleLocation(name string) (*time.Location, error) {
+	if loc, ok := scheduleLocations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	scheduleLocations.Store(name, loc)
+	return loc, nil
+}
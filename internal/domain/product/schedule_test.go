@@ -0,0 +1,158 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestScheduleValidate(t *testing.T) {
+	window := func(day, start, end string) AvailabilityWindow {
+		return AvailabilityWindow{Day: day, Start: start, End: end}
+	}
+
+	tests := []struct {
+		name    string
+		tz      string
+		windows []AvailabilityWindow
+		wantErr error
+	}{
+		{"breakfast every weekday", "America/Bogota", []AvailabilityWindow{
+			window("monday", "06:00", "11:00"), window("tuesday", "06:00", "11:00"), window("friday", "06:00", "11:00"),
+		}, nil},
+		{"same day, back to back", "UTC", []AvailabilityWindow{window("monday", "06:00", "11:00"), window("monday", "11:00", "15:00")}, nil},
+		{"until the end of the day", "UTC", []AvailabilityWindow{window("monday", "18:00", "24:00"), window("tuesday", "00:00", "02:00")}, nil},
+		{"crossing midnight", "UTC", []AvailabilityWindow{window("friday", "22:00", "02:00"), window("saturday", "02:00", "04:00")}, nil},
+		{"timezone required", "", []AvailabilityWindow{window("monday", "06:00", "11:00")}, ErrInvalidScheduleTimezone},
+		{"unknown timezone", "Mars/Olympus", []AvailabilityWindow{window("monday", "06:00", "11:00")}, ErrInvalidScheduleTimezone},
+		{"unknown day", "UTC", []AvailabilityWindow{window("lunes", "06:00", "11:00")}, ErrInvalidScheduleDay},
+		{"bad time", "UTC", []AvailabilityWindow{window("monday", "6am", "11:00")}, ErrInvalidScheduleTime},
+		{"out of range time", "UTC", []AvailabilityWindow{window("monday", "06:00", "25:00")}, ErrInvalidScheduleTime},
+		{"start at 24:00", "UTC", []AvailabilityWindow{window("monday", "24:00", "02:00")}, ErrInvalidScheduleTime},
+		{"empty window", "UTC", []AvailabilityWindow{window("monday", "06:00", "06:00")}, ErrEmptyScheduleWindow},
+		{"overlap", "UTC", []AvailabilityWindow{window("monday", "06:00", "11:00"), window("monday", "10:00", "12:00")}, ErrOverlappingScheduleWindows},
+		{"overlap past midnight", "UTC", []AvailabilityWindow{window("friday", "22:00", "03:00"), window("saturday", "02:00", "04:00")}, ErrOverlappingScheduleWindows},
+		{"overlap past the end of the week", "UTC", []AvailabilityWindow{window("monday", "00:00", "01:00"), window("sunday", "23:00", "00:30")}, ErrOverlappingScheduleWindows},
+		{"duplicate window", "UTC", []AvailabilityWindow{window("monday", "06:00", "11:00"), window("monday", "06:00", "11:00")}, ErrOverlappingScheduleWindows},
+		{"too many windows", "UTC", make([]AvailabilityWindow, MaxScheduleWindows+1), ErrTooManyScheduleWindows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &AvailabilitySchedule{Timezone: tt.tz, Windows: tt.windows}
+			if err := schedule.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeSchedule(t *testing.T) {
+	if got := NormalizeSchedule(&AvailabilitySchedule{Timezone: "UTC"}); got != nil {
+		t.Errorf("schedule without windows = %+v, want none", got)
+	}
+	got := NormalizeSchedule(&AvailabilitySchedule{Timezone: " UTC ", Windows: []AvailabilityWindow{{Day: " Monday", Start: "06:00 ", End: "11:00"}}})
+	want := AvailabilityWindow{Day: "monday", Start: "06:00", End: "11:00"}
+	if got.Timezone != "UTC" || got.Windows[0] != want {
+		t.Errorf("normalized = %+v, want trimmed UTC and %+v", got, want)
+	}
+}
+
+func TestScheduleOpenAt(t *testing.T) {
+	bogota, err := time.LoadLocation("America/Bogota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := &AvailabilitySchedule{Timezone: "America/Bogota", Windows: []AvailabilityWindow{
+		{Day: "monday", Start: "06:00", End: "11:00"},
+		{Day: "friday", Start: "22:00", End: "02:00"},
+		{Day: "sunday", Start: "23:00", End: "01:00"},
+	}}
+	// 2024-06-03 is a Monday
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 6, day, hour, minute, 0, 0, bogota) }
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"start is included", at(3, 6, 0), true},
+		{"within", at(3, 10, 59), true},
+		{"end is excluded", at(3, 11, 0), false},
+		{"other day", at(4, 8, 0), false},
+		{"before midnight", at(7, 23, 30), true},
+		{"after midnight, next day", at(8, 1, 59), true},
+		{"after the window crossing midnight", at(8, 2, 0), false},
+		{"sunday night", at(9, 23, 30), true},
+		{"into monday from sunday", at(10, 0, 30), true},
+		{"into monday from the previous week's sunday", at(3, 0, 30), true},
+		{"converted from UTC", time.Date(2024, 6, 3, 11, 0, 0, 0, time.UTC), true}, // 06:00 in Bogota
+		{"UTC time on the same day but outside", time.Date(2024, 6, 3, 8, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.OpenAt(tt.at); got != tt.want {
+				t.Errorf("OpenAt(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAvailableNow(t *testing.T) {
+	monday8am := time.Date(2024, 6, 3, 8, 0, 0, 0, time.UTC)
+	newAvailable := func(name string, schedule *AvailabilitySchedule) *Product {
+		p := NewProduct("c1", "sp1", name, "Platos", "")
+		p.IsAvailable, p.IsUnlimitedStock, p.AvailabilitySchedule = true, true, schedule
+		return p
+	}
+	breakfast := &AvailabilitySchedule{Timezone: "UTC", Windows: []AvailabilityWindow{{Day: "monday", Start: "06:00", End: "11:00"}}}
+	dinner := &AvailabilitySchedule{Timezone: "UTC", Windows: []AvailabilityWindow{{Day: "monday", Start: "18:00", End: "23:00"}}}
+	disabled := newAvailable("Disabled", nil)
+	disabled.IsAvailable = false
+
+	repo := newMemoryRepository(
+		newAvailable("Arepa", breakfast), newAvailable("Bandeja", nil), newAvailable("Cazuela", dinner),
+		newAvailable("Desayuno", breakfast), disabled,
+	)
+	service := NewService(repo)
+	service.now = func() time.Time { return monday8am }
+
+	tests := []struct {
+		name      string
+		filters   ProductFilters
+		wantNames []string
+		wantTotal int64
+		wantErr   error
+	}{
+		{"schedules evaluated", ProductFilters{AvailableNow: true}, []string{"Arepa", "Bandeja", "Desayuno"}, 3, nil},
+		{"page cut after filtering", ProductFilters{AvailableNow: true, Limit: 2, Offset: 1}, []string{"Bandeja", "Desayuno"}, 3, nil},
+		{"offset past the end", ProductFilters{AvailableNow: true, Limit: 2, Offset: 5}, []string{}, 3, nil},
+		{"count skipped", ProductFilters{AvailableNow: true, SkipCount: true, Limit: 1}, []string{"Arepa"}, -1, nil},
+		{"unavailable requested", ProductFilters{AvailableNow: true, IsAvailable: new(bool)}, []string{}, 0, nil},
+		{"cursor pagination", ProductFilters{AvailableNow: true, Cursor: &util.PageCursor{}}, nil, 0, util.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, total, err := service.GetBySalePointID(context.Background(), "sp1", tt.filters)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			names := []string{}
+			for _, p := range products {
+				names = append(names, p.Name)
+			}
+			if total != tt.wantTotal || !slices.Equal(names, tt.wantNames) {
+				t.Errorf("products = %v (total %d), want %v (total %d)", names, total, tt.wantNames, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -3,51 +3,201 @@ package product
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/util"
 )
 
+// ServiceAPI is the set of product use cases consumed by the HTTP handlers
+type ServiceAPI interface {
+	Create(ctx context.Context, input CreateInput) (*Product, error)
+	GetByID(ctx context.Context, id string) (*Product, error)
+	GetBySKU(ctx context.Context, salePointID, sku string) (*Product, error)
+	GetByBarcode(ctx context.Context, companyID, barcode string) (*Product, error)
+	GetByIDs(ctx context.Context, input BatchInput) (*BatchResult, error)
+	GetByCompanyID(ctx context.Context, companyID string, filters ProductFilters) ([]*Product, int64, error)
+	GetBySalePointID(ctx context.Context, salePointID string, filters ProductFilters) ([]*Product, int64, error)
+	Update(ctx context.Context, id string, input UpdateInput) (*Product, error)
+	AdjustStock(ctx context.Context, id string, adjustment StockAdjustment) (*Product, error)
+	Delete(ctx context.Context, id string, hard bool) error
+	Restore(ctx context.Context, id string) (*Product, error)
+	GetPriceHistory(ctx context.Context, id string, limit, offset int) ([]PriceChange, int64, error)
+	BulkDelete(ctx context.Context, input BulkDeleteInput) (*BulkDeleteResult, error)
+	BulkSetAvailability(ctx context.Context, input BulkAvailabilityInput) (*BulkAvailabilityResult, error)
+	Reorder(ctx context.Context, input ReorderInput) (*ReorderResult, error)
+	Copy(ctx context.Context, input CopyInput) (*CopyResult, error)
+	GetLowStock(ctx context.Context, threshold, limit int) ([]*Product, error)
+	GetCompanySummary(ctx context.Context, companyID string) (*CompanySummary, error)
+	GetMenu(ctx context.Context, salePointID string, filters MenuFilters) ([]MenuSection, int64, error)
+	GetCategoriesByCompanyID(ctx context.Context, companyID string, filters CategoryFilters) ([]CategorySummary, int64, error)
+	GetCategoriesBySalePointID(ctx context.Context, salePointID string, filters CategoryFilters) ([]CategorySummary, int64, error)
+	RenameCategory(ctx context.Context, input CategoryRenameInput) (*CategoryRenameResult, error)
+	GetTagsByCompanyID(ctx context.Context, companyID string, filters CategoryFilters) ([]TagSummary, int64, error)
+	Import(ctx context.Context, input ImportInput) (*ImportResult, error)
+	Export(ctx context.Context, input ExportInput, emit ExportEmitFunc) error
+	CompanyPageLimits() util.PageLimits
+	SalePointPageLimits() util.PageLimits
+	ImportLimits() ImportLimits
+}
+
+// Compile-time check that Service implements ServiceAPI
+var _ ServiceAPI = (*Service)(nil)
+
 // Service handles business logic for products
 type Service struct {
-	repo Repository
+	repo                Repository
+	companyPageLimits   util.PageLimits
+	salePointPageLimits util.PageLimits
+	photoHosts          util.HostAllowlist
+	sizeLimit           util.DocumentSizeLimit
+	orderRefs           OrderReferences
+	referenceWindow     time.Duration
+	openOrders          OpenOrderCounter
+	priceHistory        PriceHistoryRepository
+	categories          CategoryCatalog
+	importLimits        ImportLimits
+	now                 func() time.Time // Clock of the availability schedules
+}
+
+// Option configures optional service behavior
+type Option func(*Service)
+
+// WithCompanyPageLimits sets the page size limits for company product listings
+func WithCompanyPageLimits(limits util.PageLimits) Option {
+	return func(s *Service) {
+		s.companyPageLimits = limits
+	}
+}
+
+// WithSalePointPageLimits sets the page size limits for sale point product listings
+func WithSalePointPageLimits(limits util.PageLimits) Option {
+	return func(s *Service) {
+		s.salePointPageLimits = limits
+	}
+}
+
+// WithPhotoHostAllowlist restricts the hosts photo URLs may point at
+func WithPhotoHostAllowlist(allowlist util.HostAllowlist) Option {
+	return func(s *Service) {
+		s.photoHosts = allowlist
+	}
+}
+
+// WithDocumentSizeLimit rejects products whose stored document would exceed the soft size limit
+func WithDocumentSizeLimit(limit util.DocumentSizeLimit) Option {
+	return func(s *Service) {
+		s.sizeLimit = limit
+	}
+}
+
+// WithOrderReferences makes bulk deletes refuse products referenced by orders placed within the window
+func WithOrderReferences(refs OrderReferences, window time.Duration) Option {
+	return func(s *Service) {
+		s.orderRefs = refs
+		s.referenceWindow = window
+	}
+}
+
+// WithOpenOrderCounter makes hard deletes refuse products that open orders contain
+func WithOpenOrderCounter(counter OpenOrderCounter) Option {
+	return func(s *Service) {
+		s.openOrders = counter
+	}
 }
 
 // NewService creates a new product service
-func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{
+		repo:                repo,
+		companyPageLimits:   util.DefaultPageLimits,
+		salePointPageLimits: util.DefaultPageLimits,
+		importLimits:        DefaultImportLimits,
+		now:                 time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CompanyPageLimits returns the page size limits for company product listings
+func (s *Service) CompanyPageLimits() util.PageLimits {
+	return s.companyPageLimits
+}
+
+// SalePointPageLimits returns the page size limits for sale point product listings
+func (s *Service) SalePointPageLimits() util.PageLimits {
+	return s.salePointPageLimits
 }
 
 // CreateInput represents input for creating a product
 type CreateInput struct {
-	CompanyID        string
-	SalePointID      string
-	Name             string
-	Description      string
-	Category         string
-	Photos           []string
-	PriceVariations  []PriceVariation
-	AvailableAddons  []Addon
-	IsAddon          bool
-	IsAvailable      bool
-	IsUnlimitedStock bool
-	Stock            *int
+	CompanyID            string
+	SalePointID          string
+	SKU                  *string
+	Barcode              *string
+	Name                 string
+	Description          string
+	Category             string
+	Photos               []string
+	PriceVariations      []PriceVariation
+	AvailableAddons      []Addon
+	QuickObservations    []string
+	Tags                 []string
+	IsAddon              bool
+	IsAvailable          bool
+	IsUnlimitedStock     bool
+	Stock                *int
+	AvailabilitySchedule *AvailabilitySchedule // Limits ordering to weekly windows (optional)
 }
 
 // UpdateInput represents input for updating a product
 type UpdateInput struct {
-	Name             *string
-	Description      *string
-	Category         *string
-	Photos           *[]string
-	PriceVariations  *[]PriceVariation
-	AvailableAddons  *[]Addon
-	IsAddon          *bool
-	IsAvailable      *bool
-	IsUnlimitedStock *bool
-	Stock            **int // Pointer to pointer to allow setting to nil
+	SKU                  *string // An empty SKU removes it
+	Barcode              *string // An empty barcode removes it
+	Name                 *string
+	Description          *string
+	Category             *string
+	Photos               *[]string
+	PriceVariations      *[]PriceVariation
+	AvailableAddons      *[]Addon
+	QuickObservations    *[]string
+	Tags                 *[]string
+	IsAddon              *bool
+	IsAvailable          *bool
+	IsUnlimitedStock     *bool
+	Stock                **int                 // Pointer to pointer to allow setting to nil
+	Actor                string                // Who makes the update, recorded in the price history (optional)
+	AvailabilitySchedule *AvailabilitySchedule // Replaces the schedule; one without windows removes it
+	ExpectedVersion      *int64                // Version the client edited; ErrProductConflict when the product changed since
 }
 
 // Create creates a new product
 func (s *Service) Create(ctx context.Context, input CreateInput) (*Product, error) {
-	// Create new product
+	p := input.newProduct()
+
+	if err := s.checkNewProduct(p); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategory(ctx, p); err != nil {
+		return nil, err
+	}
+	if err := s.placeLast(ctx, p); err != nil {
+		return nil, err
+	}
+
+	// Save to repository
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	return p, nil
+}
+
+// newProduct builds the product described by the input
+func (input CreateInput) newProduct() *Product {
 	p := NewProduct(input.CompanyID, input.SalePointID, input.Name, input.Category, input.Description)
 
 	// Set additional fields
@@ -57,22 +207,38 @@ func (s *Service) Create(ctx context.Context, input CreateInput) (*Product, erro
 
 	p.PriceVariations = input.PriceVariations
 	p.AvailableAddons = input.AvailableAddons
+	if len(input.QuickObservations) > 0 {
+		p.QuickObservations = input.QuickObservations
+	}
 	p.IsAddon = input.IsAddon
 	p.IsAvailable = input.IsAvailable
 	p.IsUnlimitedStock = input.IsUnlimitedStock
 	p.Stock = input.Stock
+	p.SKU = NormalizeSKU(input.SKU)
+	p.Barcode = NormalizeBarcode(input.Barcode)
+	if len(input.Tags) > 0 {
+		p.Tags = NormalizeTags(input.Tags)
+	}
+	p.AvailabilitySchedule = NormalizeSchedule(input.AvailabilitySchedule)
+	p.AssignAddonIDs()
+	return p
+}
+
+// checkNewProduct sanitizes a new product and runs the checks that need no storage
+func (s *Service) checkNewProduct(p *Product) error {
+	// Sanitize free text before validation
+	if err := p.SanitizeText(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
 
 	// Validate business rules
 	if err := p.Validate(); err != nil {
-		return nil, fmt.Errorf("validation error: %w", err)
+		return fmt.Errorf("validation error: %w", err)
 	}
-
-	// Save to repository
-	if err := s.repo.Create(ctx, p); err != nil {
-		return nil, fmt.Errorf("failed to create product: %w", err)
+	if err := s.checkPhotoHosts(p); err != nil {
+		return fmt.Errorf("validation error: %w", err)
 	}
-
-	return p, nil
+	return s.checkDocumentSize(p)
 }
 
 // GetByID retrieves a product by ID
@@ -95,24 +261,20 @@ func (s *Service) GetByCompanyID(ctx context.Context, companyID string, filters
 		return nil, 0, fmt.Errorf("company ID is required")
 	}
 
-	// Set default pagination
-	if filters.Limit <= 0 {
-		filters.Limit = 50
-	}
-	if filters.Limit > 100 {
-		filters.Limit = 100 // Maximum limit
-	}
+	// Apply configured pagination limits
+	filters.Limit = s.companyPageLimits.Resolve(filters.Limit)
 	if filters.Offset < 0 {
 		filters.Offset = 0
 	}
+	applyCursor(&filters)
 
-	// Get total count with same filters
-	total, err := s.repo.CountByCompanyID(ctx, companyID, filters)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count products: %w", err)
+	if filters.AvailableNow {
+		return s.availableNow(filters, func(all ProductFilters) ([]*Product, error) {
+			return s.repo.FindByCompanyID(ctx, companyID, all)
+		})
 	}
 
-	products, err := s.repo.FindByCompanyID(ctx, companyID, filters)
+	products, total, err := s.repo.FindByCompanyIDWithCount(ctx, companyID, filters)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get products: %w", err)
 	}
@@ -120,30 +282,35 @@ func (s *Service) GetByCompanyID(ctx context.Context, companyID string, filters
 	return products, total, nil
 }
 
+// GetLowStock retrieves up to limit products whose limited stock is at or below the threshold
+func (s *Service) GetLowStock(ctx context.Context, threshold, limit int) ([]*Product, error) {
+	products, err := s.repo.FindLowStock(ctx, threshold, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+	return products, nil
+}
+
 // GetBySalePointID retrieves products by sale point ID with filters
 func (s *Service) GetBySalePointID(ctx context.Context, salePointID string, filters ProductFilters) ([]*Product, int64, error) {
 	if salePointID == "" {
 		return nil, 0, fmt.Errorf("sale point ID is required")
 	}
 
-	// Set default pagination
-	if filters.Limit <= 0 {
-		filters.Limit = 50
-	}
-	if filters.Limit > 100 {
-		filters.Limit = 100 // Maximum limit
-	}
+	// Apply configured pagination limits
+	filters.Limit = s.salePointPageLimits.Resolve(filters.Limit)
 	if filters.Offset < 0 {
 		filters.Offset = 0
 	}
+	applyCursor(&filters)
 
-	// Get total count with same filters
-	total, err := s.repo.CountBySalePointID(ctx, salePointID, filters)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count products: %w", err)
+	if filters.AvailableNow {
+		return s.availableNow(filters, func(all ProductFilters) ([]*Product, error) {
+			return s.repo.FindBySalePointID(ctx, salePointID, all)
+		})
 	}
 
-	products, err := s.repo.FindBySalePointID(ctx, salePointID, filters)
+	products, total, err := s.repo.FindBySalePointIDWithCount(ctx, salePointID, filters)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get products: %w", err)
 	}
@@ -162,8 +329,20 @@ func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*Pr
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(product, input.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	previousPrices := slices.Clone(product.PriceVariations)
+	previousCategory := product.Category
 
 	// Update fields if provided
+	if input.SKU != nil {
+		product.SKU = NormalizeSKU(input.SKU)
+	}
+	if input.Barcode != nil {
+		product.Barcode = NormalizeBarcode(input.Barcode)
+	}
 	if input.Name != nil {
 		product.Name = *input.Name
 	}
@@ -182,6 +361,12 @@ func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*Pr
 	if input.AvailableAddons != nil {
 		product.AvailableAddons = *input.AvailableAddons
 	}
+	if input.QuickObservations != nil {
+		product.QuickObservations = *input.QuickObservations
+	}
+	if input.Tags != nil {
+		product.Tags = NormalizeTags(*input.Tags)
+	}
 	if input.IsAddon != nil {
 		product.IsAddon = *input.IsAddon
 	}
@@ -194,57 +379,216 @@ func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*Pr
 	if input.Stock != nil {
 		product.Stock = *input.Stock
 	}
+	if input.AvailabilitySchedule != nil {
+		product.AvailabilitySchedule = NormalizeSchedule(input.AvailabilitySchedule)
+	}
+	product.AssignAddonIDs()
+
+	// Sanitize free text before validation
+	if err := product.SanitizeText(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
 
 	// Validate business rules
 	if err := product.Validate(); err != nil {
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
+	if err := s.checkPhotoHosts(product); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	// Products keep their category when the sale point's categories change, until it is edited
+	if input.Category != nil {
+		if err := s.checkCategory(ctx, product); err != nil {
+			return nil, err
+		}
+	}
+	// A product moved to another category is shown last there
+	if product.Category != previousCategory {
+		if err := s.placeLast(ctx, product); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkDocumentSize(product); err != nil {
+		return nil, err
+	}
 
 	// Update in repository
 	if err := s.repo.Update(ctx, product); err != nil {
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
 
+	if input.PriceVariations != nil {
+		if err := s.recordPriceChange(ctx, product, previousPrices, input.Actor); err != nil {
+			return nil, err
+		}
+	}
+
 	return product, nil
 }
 
-// Delete deletes a product
-func (s *Service) Delete(ctx context.Context, id string) error {
+// Delete soft deletes a product so it can be restored, or removes it for good when hard is set
+func (s *Service) Delete(ctx context.Context, id string, hard bool) error {
 	if id == "" {
 		return fmt.Errorf("product ID is required")
 	}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return err
+	if hard {
+		if err := s.checkOpenOrders(ctx, id); err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, id)
 	}
+	return s.repo.SoftDelete(ctx, id, time.Now())
+}
 
+// checkOpenOrders fails with ErrProductInUse when open orders contain one of the products.
+// Receipts and reports of open orders still read the product; soft deletes keep it for them.
+func (s *Service) checkOpenOrders(ctx context.Context, ids ...string) error {
+	if s.openOrders == nil {
+		return nil
+	}
+	for _, id := range ids {
+		count, err := s.openOrders.CountOpenOrdersWithProduct(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to check open orders: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %d open orders contain %s", ErrProductInUse, count, id)
+		}
+	}
 	return nil
 }
 
+// Restore brings back a soft deleted product
+func (s *Service) Restore(ctx context.Context, id string) (*Product, error) {
+	if id == "" {
+		return nil, fmt.Errorf("product ID is required")
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, id)
+}
+
 // GetCategoriesByCompanyID retrieves categories for a company
-func (s *Service) GetCategoriesByCompanyID(ctx context.Context, companyID string) ([]string, error) {
+func (s *Service) GetCategoriesByCompanyID(ctx context.Context, companyID string, filters CategoryFilters) ([]CategorySummary, int64, error) {
 	if companyID == "" {
-		return nil, fmt.Errorf("company ID is required")
+		return nil, 0, fmt.Errorf("company ID is required")
 	}
 
-	categories, err := s.repo.FindCategoriesByCompanyID(ctx, companyID)
+	filters = resolveCategoryPage(filters, s.companyPageLimits)
+	categories, total, err := s.repo.FindCategoriesByCompanyID(ctx, companyID, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get categories: %w", err)
+		return nil, 0, fmt.Errorf("failed to get categories: %w", err)
 	}
 
-	return categories, nil
+	return categories, total, nil
 }
 
 // GetCategoriesBySalePointID retrieves categories for a sale point
-func (s *Service) GetCategoriesBySalePointID(ctx context.Context, salePointID string) ([]string, error) {
+func (s *Service) GetCategoriesBySalePointID(ctx context.Context, salePointID string, filters CategoryFilters) ([]CategorySummary, int64, error) {
 	if salePointID == "" {
-		return nil, fmt.Errorf("sale point ID is required")
+		return nil, 0, fmt.Errorf("sale point ID is required")
 	}
 
-	categories, err := s.repo.FindCategoriesBySalePointID(ctx, salePointID)
+	filters = resolveCategoryPage(filters, s.salePointPageLimits)
+	categories, total, err := s.repo.FindCategoriesBySalePointID(ctx, salePointID, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get categories: %w", err)
+		return nil, 0, fmt.Errorf("failed to get categories: %w", err)
 	}
 
-	return categories, nil
+	return categories, total, nil
+}
+
+// applyCursor makes cursor pages start right after the cursor. They are not counted, as counting
+// every matching product is what makes deep pages slow.
+func applyCursor(filters *ProductFilters) {
+	if filters.Cursor != nil {
+		filters.Offset = 0
+		filters.SkipCount = true
+	}
+}
+
+// availableNow lists the products matching filters that can be ordered now. Schedules are evaluated
+// here rather than in the query, so every match is read and the page is cut from the available ones.
+func (s *Service) availableNow(filters ProductFilters, findAll func(ProductFilters) ([]*Product, error)) ([]*Product, int64, error) {
+	if filters.Cursor != nil {
+		return nil, 0, fmt.Errorf("%w: available_now listings use offset pagination", util.ErrInvalidCursor)
+	}
+	if filters.IsAvailable != nil && !*filters.IsAvailable {
+		return []*Product{}, 0, nil
+	}
+
+	all := filters
+	available := true
+	all.IsAvailable = &available
+	all.Limit, all.Offset = 0, 0
+	products, err := findAll(all)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	now := s.now()
+	matched := make([]*Product, 0, len(products))
+	for _, p := range products {
+		if p.AvailabilityAt(now).Available {
+			matched = append(matched, p)
+		}
+	}
+
+	total := int64(len(matched))
+	if filters.SkipCount {
+		total = -1
+	}
+	start := min(filters.Offset, len(matched))
+	end := len(matched)
+	if filters.Limit > 0 {
+		end = min(start+filters.Limit, end)
+	}
+	return matched[start:end], total, nil
+}
+
+// resolveCategoryPage applies the listing's pagination limits unless every category was requested
+func resolveCategoryPage(filters CategoryFilters, limits util.PageLimits) CategoryFilters {
+	if filters.All {
+		filters.Limit, filters.Offset = 0, 0
+		return filters
+	}
+	filters.Limit = limits.Resolve(filters.Limit)
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+	return filters
+}
+
+// checkPhotoHosts rejects product and addon photos hosted outside the allowlist
+func (s *Service) checkPhotoHosts(p *Product) error {
+	for i, photo := range p.Photos {
+		if host, ok := s.photoHosts.Check(photo); !ok {
+			return apperrors.NewIndexedDomainError(fmt.Errorf("%w: %s", ErrPhotoHostNotAllowed, host), fmt.Sprintf("photos[%d]", i), i, photo)
+		}
+	}
+	for i, addon := range p.AvailableAddons {
+		for j, photo := range addon.Photos {
+			if host, ok := s.photoHosts.Check(photo); !ok {
+				field := fmt.Sprintf("available_addons[%d].photos[%d]", i, j)
+				return apperrors.NewIndexedDomainError(fmt.Errorf("%w: %s", ErrPhotoHostNotAllowed, host), field, i, photo)
+			}
+		}
+	}
+	return nil
+}
+
+// checkDocumentSize rejects a product whose stored document would exceed the soft size limit
+func (s *Service) checkDocumentSize(p *Product) error {
+	size, exceeded, err := s.sizeLimit.Check(p.ID, p)
+	if err != nil {
+		return fmt.Errorf("failed to measure product size: %w", err)
+	}
+	if exceeded {
+		return fmt.Errorf("validation error: %w: %d bytes, max %d", ErrProductTooLarge, size, s.sizeLimit.MaxBytes)
+	}
+	return nil
 }
@@ -0,0 +1,42 @@
+package product
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// skuPattern matches a normalized SKU
+var skuPattern = regexp.MustCompile(`^[A-Z0-9_-]{1,40}$`)
+
+// NormalizeSKU trims and uppercases a SKU. A missing or blank SKU means the product has none and returns nil.
+func NormalizeSKU(sku *string) *string {
+	if sku == nil {
+		return nil
+	}
+	normalized := strings.ToUpper(strings.TrimSpace(*sku))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// IsValidSKU reports whether a normalized SKU has 1 to 40 letters, digits, hyphens or underscores
+func IsValidSKU(sku string) bool {
+	return skuPattern.MatchString(sku)
+}
+
+// GetBySKU retrieves the live product of a sale point with the given SKU; the SKU is normalized first
+func (s *Service) GetBySKU(ctx context.Context, salePointID, sku string) (*Product, error) {
+	if salePointID == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidSalePointID, "sale_point_id", nil)
+	}
+	normalized := NormalizeSKU(&sku)
+	if normalized == nil || !IsValidSKU(*normalized) {
+		return nil, apperrors.NewDomainError(ErrInvalidSKU, "sku", sku)
+	}
+
+	return s.repo.FindBySKU(ctx, salePointID, *normalized)
+}
@@ -0,0 +1,109 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNormalizeSKU(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name      string
+		sku       *string
+		want      *string
+		wantValid bool
+	}{
+		{"missing", nil, nil, false},
+		{"blank", ptr("  "), nil, false},
+		{"uppercased and trimmed", ptr(" piz-01_a "), ptr("PIZ-01_A"), true},
+		{"forty characters", ptr("ABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJ"), ptr("ABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJ"), true},
+		{"too long", ptr("ABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJK"), ptr("ABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJK"), false},
+		{"inner space", ptr("PIZ 01"), ptr("PIZ 01"), false},
+		{"accent", ptr("café"), ptr("CAFÉ"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeSKU(tt.sku)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("NormalizeSKU() = %v, want %v", got, tt.want)
+			}
+			if got != nil && IsValidSKU(*got) != tt.wantValid {
+				t.Errorf("IsValidSKU(%q) = %v, want %v", *got, !tt.wantValid, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestCreateAndUpdateSKU(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+
+	sku := " piz-01 "
+	p, err := svc.Create(ctx, CreateInput{
+		CompanyID:        "company-1",
+		SalePointID:      "sale-point-1",
+		SKU:              &sku,
+		Name:             "Pizza",
+		Category:         "Pizzas",
+		PriceVariations:  []PriceVariation{{Type: "small", Price: 1000}},
+		IsUnlimitedStock: true,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if p.SKU == nil || *p.SKU != "PIZ-01" {
+		t.Fatalf("created sku = %v, want PIZ-01", p.SKU)
+	}
+
+	invalid := "PIZ 01"
+	if _, err := svc.Update(ctx, p.ID, UpdateInput{SKU: &invalid}); !errors.Is(err, ErrInvalidSKU) {
+		t.Errorf("invalid sku error = %v, want %v", err, ErrInvalidSKU)
+	}
+
+	name := "Margherita"
+	updated, err := svc.Update(ctx, p.ID, UpdateInput{Name: &name})
+	if err != nil || updated.SKU == nil || *updated.SKU != "PIZ-01" {
+		t.Errorf("update without sku = %v, %v; want the sku kept", updated, err)
+	}
+
+	empty := ""
+	cleared, err := svc.Update(ctx, p.ID, UpdateInput{SKU: &empty})
+	if err != nil || cleared.SKU != nil {
+		t.Errorf("update with empty sku = %v, %v; want the sku removed", cleared, err)
+	}
+}
+
+func TestGetBySKU(t *testing.T) {
+	sku := "PIZ-01"
+	repo := newMemoryRepository(&Product{ID: "pizza", SalePointID: "sale-point-1", SKU: &sku})
+	svc := NewService(repo)
+
+	tests := []struct {
+		name        string
+		salePointID string
+		sku         string
+		wantID      string
+		wantErr     error
+	}{
+		{"normalized match", "sale-point-1", "piz-01", "pizza", nil},
+		{"other sale point", "sale-point-2", "PIZ-01", "", ErrProductNotFound},
+		{"missing sale point", "", "PIZ-01", "", ErrInvalidSalePointID},
+		{"invalid sku", "sale-point-1", "PIZ 01", "", ErrInvalidSKU},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := svc.GetBySKU(context.Background(), tt.salePointID, tt.sku)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && p.ID != tt.wantID {
+				t.Errorf("product = %s, want %s", p.ID, tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package product
+
+import (
+	"context"
+	"fmt"
+)
+
+// StockAdjustment changes a product's limited stock: either by Delta, or to the absolute Set
+type StockAdjustment struct {
+	Delta *int // Added to the current stock; negative to decrement
+	Set   *int // Replaces the current stock
+}
+
+// Validate checks exactly one of Delta and Set is given and Set is not negative
+func (a StockAdjustment) Validate() error {
+	if (a.Delta == nil) == (a.Set == nil) {
+		return ErrInvalidStockAdjustment
+	}
+	if a.Set != nil && *a.Set < 0 {
+		return ErrNegativeStock
+	}
+	return nil
+}
+
+// AdjustStock atomically changes a product's stock and returns the updated product.
+// The stock never goes below zero: a decrement larger than the stock fails with ErrInsufficientStock.
+func (s *Service) AdjustStock(ctx context.Context, id string, adjustment StockAdjustment) (*Product, error) {
+	if id == "" {
+		return nil, fmt.Errorf("product ID is required")
+	}
+	if err := adjustment.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	return s.repo.AdjustStock(ctx, id, adjustment)
+}
@@ -0,0 +1,95 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdjustStock(t *testing.T) {
+	deletedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ptr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name       string
+		id         string
+		adjustment StockAdjustment
+		wantErr    error
+		wantStock  int
+	}{
+		{"decrement", "limited", StockAdjustment{Delta: ptr(-3)}, nil, 2},
+		{"decrement to zero", "limited", StockAdjustment{Delta: ptr(-5)}, nil, 0},
+		{"restock", "limited", StockAdjustment{Delta: ptr(10)}, nil, 15},
+		{"set", "limited", StockAdjustment{Set: ptr(25)}, nil, 25},
+		{"set to zero", "limited", StockAdjustment{Set: ptr(0)}, nil, 0},
+		{"more than in stock", "limited", StockAdjustment{Delta: ptr(-6)}, ErrInsufficientStock, 5},
+		{"negative set", "limited", StockAdjustment{Set: ptr(-1)}, ErrNegativeStock, 5},
+		{"delta and set", "limited", StockAdjustment{Delta: ptr(1), Set: ptr(1)}, ErrInvalidStockAdjustment, 5},
+		{"neither", "limited", StockAdjustment{}, ErrInvalidStockAdjustment, 5},
+		{"unlimited stock", "unlimited", StockAdjustment{Delta: ptr(-1)}, ErrCannotUpdateStockForUnlimited, 0},
+		{"soft deleted", "deleted", StockAdjustment{Delta: ptr(-1)}, ErrProductNotFound, 5},
+		{"unknown product", "missing", StockAdjustment{Delta: ptr(-1)}, ErrProductNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(
+				&Product{ID: "limited", Stock: ptr(5)},
+				&Product{ID: "unlimited", IsUnlimitedStock: true},
+				&Product{ID: "deleted", Stock: ptr(5), DeletedAt: &deletedAt},
+			)
+
+			p, err := NewService(repo).AdjustStock(context.Background(), tt.id, tt.adjustment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && *p.Stock != tt.wantStock {
+				t.Errorf("returned stock = %d, want %d", *p.Stock, tt.wantStock)
+			}
+			if stored := repo.stored(tt.id); stored != nil && stored.Stock != nil && *stored.Stock != tt.wantStock {
+				t.Errorf("stored stock = %d, want %d", *stored.Stock, tt.wantStock)
+			}
+		})
+	}
+}
+
+// TestAdjustStockConcurrent hammers a product with decrements and restocks and checks no update is
+// lost and the stock never goes below zero
+func TestAdjustStockConcurrent(t *testing.T) {
+	const initial, workers = 50, 200
+	stock := initial
+	repo := newMemoryRepository(&Product{ID: "p1", Stock: &stock})
+	svc := NewService(repo)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var sold, rejected int
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			decrement := -1
+			_, err := svc.AdjustStock(context.Background(), "p1", StockAdjustment{Delta: &decrement})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				sold++
+			case errors.Is(err, ErrInsufficientStock):
+				rejected++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if sold != initial || rejected != workers-initial {
+		t.Errorf("sold %d and rejected %d, want %d and %d", sold, rejected, initial, workers-initial)
+	}
+	if got := *repo.stored("p1").Stock; got != 0 {
+		t.Errorf("stock = %d, want 0", got)
+	}
+}
@@ -0,0 +1,45 @@
+package product
+
+import (
+	"context"
+	"fmt"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+// ProductCounts counts live products by what customers can order, following the availability
+// reasons but ignoring schedules: every product is counted once as available, disabled or out of stock
+type ProductCounts struct {
+	Total        int64 `json:"total" bson:"total"`
+	Available    int64 `json:"available" bson:"available"`         // IN_STOCK
+	Disabled     int64 `json:"disabled" bson:"disabled"`           // MANUALLY_DISABLED
+	OutOfStock   int64 `json:"out_of_stock" bson:"out_of_stock"`   // OUT_OF_STOCK
+	CatalogValue int64 `json:"catalog_value" bson:"catalog_value"` // Cheapest variation price × stock of limited stock products, in cents
+}
+
+// CategoryCounts counts the live products of one category
+type CategoryCounts struct {
+	Name          string `json:"name" bson:"name"`
+	ProductCounts `bson:",inline"`
+}
+
+// CompanySummary reports the catalog numbers of a company's live products, overall and per category
+type CompanySummary struct {
+	CompanyID     string `json:"company_id" bson:"-"`
+	ProductCounts `bson:",inline"`
+	Categories    []CategoryCounts `json:"categories" bson:"categories"` // Sorted by name
+}
+
+// GetCompanySummary counts the company's live products in a single aggregation
+func (s *Service) GetCompanySummary(ctx context.Context, companyID string) (*CompanySummary, error) {
+	if companyID == "" {
+		return nil, apperrors.NewDomainError(ErrInvalidCompanyID, "company_id", nil)
+	}
+
+	summary, err := s.repo.SummarizeByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize products: %w", err)
+	}
+	summary.CompanyID = companyID
+	return summary, nil
+}
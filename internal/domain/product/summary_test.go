@@ -0,0 +1,14 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetCompanySummaryRequiresCompany(t *testing.T) {
+	_, err := NewService(newMemoryRepository()).GetCompanySummary(context.Background(), "")
+	if !errors.Is(err, ErrInvalidCompanyID) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidCompanyID)
+	}
+}
@@ -0,0 +1,82 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+const (
+	// MaxTags is the maximum number of tags per product
+	MaxTags = 20
+	// MaxTagLength is the maximum number of characters for a tag
+	MaxTagLength = 40
+)
+
+// TagsMode tells whether a tag filter matches products with any or all of the tags
+type TagsMode string
+
+const (
+	TagsAny TagsMode = "any" // Default
+	TagsAll TagsMode = "all"
+)
+
+// AllTagsModes lists the accepted tag filter modes
+var AllTagsModes = []TagsMode{TagsAny, TagsAll}
+
+// TagSummary represents a tag with the number of products labelled with it
+type TagSummary struct {
+	Name           string `json:"name" bson:"name"`
+	ProductCount   int    `json:"product_count" bson:"product_count"`
+	AvailableCount int    `json:"available_count" bson:"available_count"`
+}
+
+// NormalizeTags trims and lowercases the tags and drops repeated ones, keeping the first occurrence.
+// Blank tags are kept so validation reports them.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// validateTags checks the number and length of normalized tags
+func validateTags(tags []string) error {
+	if len(tags) > MaxTags {
+		return apperrors.NewDomainError(ErrTooManyTags, "tags", len(tags))
+	}
+	for i, tag := range tags {
+		if tag == "" {
+			return apperrors.NewIndexedDomainError(ErrInvalidTag, fmt.Sprintf("tags[%d]", i), i, tag)
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return apperrors.NewIndexedDomainError(ErrTagTooLong, fmt.Sprintf("tags[%d]", i), i, tag)
+		}
+	}
+	return nil
+}
+
+// GetTagsByCompanyID retrieves the distinct tags of a company's products, sorted by name
+func (s *Service) GetTagsByCompanyID(ctx context.Context, companyID string, filters CategoryFilters) ([]TagSummary, int64, error) {
+	if companyID == "" {
+		return nil, 0, fmt.Errorf("company ID is required")
+	}
+
+	filters = resolveCategoryPage(filters, s.companyPageLimits)
+	tags, total, err := s.repo.FindTagsByCompanyID(ctx, companyID, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	return tags, total, nil
+}
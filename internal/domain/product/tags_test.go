@@ -0,0 +1,58 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{" Vegan", "SPICY", "vegan", "", "promo ", "Spicy"})
+	want := []string{"vegan", "spicy", "", "promo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeTags() = %q, want %q", got, want)
+	}
+}
+
+func TestCreateValidatesTags(t *testing.T) {
+	manyTags := make([]string, MaxTags+1)
+	for i := range manyTags {
+		manyTags[i] = strings.Repeat("t", i+1)
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		wantTags []string
+		wantErr  error
+	}{
+		{"normalized", []string{"Vegan", " promo", "VEGAN"}, []string{"vegan", "promo"}, nil},
+		{"none", nil, []string{}, nil},
+		{"twenty after deduplication", append(manyTags[:MaxTags:MaxTags], "T"), manyTags[:MaxTags], nil},
+		{"too many", manyTags, nil, ErrTooManyTags},
+		{"blank", []string{"vegan", "  "}, nil, ErrInvalidTag},
+		{"too long", []string{strings.Repeat("x", MaxTagLength+1)}, nil, ErrTagTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewService(newMemoryRepository()).Create(context.Background(), CreateInput{
+				CompanyID:        "company-1",
+				SalePointID:      "sale-point-1",
+				Name:             "Pizza",
+				Category:         "Pizzas",
+				PriceVariations:  []PriceVariation{{Type: "small", Price: 1000}},
+				IsUnlimitedStock: true,
+				Tags:             tt.tags,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(p.Tags, tt.wantTags) {
+				t.Errorf("tags = %q, want %q", p.Tags, tt.wantTags)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testInput is a valid product create input with the given description
+func testInput(description string) CreateInput {
+	return CreateInput{
+		CompanyID:        "11111111-1111-4111-8111-111111111111",
+		SalePointID:      "22222222-2222-4222-8222-222222222222",
+		Name:             "Hamburguesa",
+		Category:         "Platos",
+		Description:      description,
+		PriceVariations:  []PriceVariation{{Type: "Normal", Price: 15000}},
+		IsAvailable:      true,
+		IsUnlimitedStock: true,
+	}
+}
+
+func TestCreateAndUpdateSanitizeDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+		wantErr     error
+	}{
+		{"empty is allowed", "", "", nil},
+		{"cleaned", "  Carne\x00 de res\r\n\r\n\r\n\x1bCon papas  ", "Carne de res\n\nCon papas", nil},
+		{"only whitespace", " \n\t\r\n ", "", ErrBlankDescription},
+		{"only control characters", "\x07\x1b", "", ErrBlankDescription},
+		{"at the limit", strings.Repeat("é", MaxDescriptionLength), strings.Repeat("é", MaxDescriptionLength), nil},
+		{"over the limit", strings.Repeat("a", MaxDescriptionLength+1), "", ErrDescriptionTooLong},
+		{"paste bomb", strings.Repeat("a\n", 10*1024), "", ErrDescriptionTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run("create: "+tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			p, err := NewService(repo).Create(context.Background(), testInput(tt.description))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := repo.stored(p.ID).Description; got != tt.want {
+				t.Errorf("description = %q, want %q", got, tt.want)
+			}
+		})
+
+		t.Run("update: "+tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewService(repo)
+			p, err := svc.Create(context.Background(), testInput("Original"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			description := tt.description
+			_, err = svc.Update(context.Background(), p.ID, UpdateInput{Description: &description})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.want
+			if tt.wantErr != nil {
+				want = "Original"
+			}
+			if got := repo.stored(p.ID).Description; got != want {
+				t.Errorf("stored description = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package product
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+)
+
+func TestValidateIndexesPriceVariations(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(p *Product)
+		wantErr   error
+		wantField string
+	}{
+		{"variation without type", func(p *Product) { p.PriceVariations[1].Type = "" },
+			ErrInvalidPriceVariationType, "price_variations[1].type"},
+		{"included addon without name", func(p *Product) { p.PriceVariations[1].IncludedAddons.Options[0].Name = "" },
+			ErrInvalidAddonName, "price_variations[1].included_addons.options[0].name"},
+		{"included addon with negative price", func(p *Product) { p.PriceVariations[1].IncludedAddons.Options[0].Price = -1 },
+			ErrNegativeAddonPrice, "price_variations[1].included_addons.options[0].price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProduct("company", "sale-point", "Hamburguesa", "Platos", "")
+			p.PriceVariations = []PriceVariation{
+				variationWith("Normal", addonWithID("o1")),
+				variationWith("Doble", addonWithID("o2")),
+			}
+			tt.mutate(p)
+
+			err := p.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			var domainErr *apperrors.DomainError
+			if !errors.As(err, &domainErr) {
+				t.Fatalf("err = %v, want a DomainError", err)
+			}
+			if domainErr.Field != tt.wantField {
+				t.Errorf("field = %q, want %q", domainErr.Field, tt.wantField)
+			}
+			// Index is the price variation, so clients highlight the row the option belongs to
+			if domainErr.Index == nil || *domainErr.Index != 1 {
+				t.Errorf("index = %v, want the variation 1", domainErr.Index)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package product
+
+import "fmt"
+
+// checkVersion rejects an update the client based on an outdated copy of the product.
+// Products are updated with optimistic locking: every write increments Version and a full update only
+// succeeds when the stored version is still the one that was loaded, so two admins editing the same
+// product can't overwrite each other. Products stored before versioning load as version 0.
+func checkVersion(p *Product, expected *int64) error {
+	if expected != nil && *expected != p.Version {
+		return fmt.Errorf("%w: sent version %d, current version %d", ErrProductConflict, *expected, p.Version)
+	}
+	return nil
+}
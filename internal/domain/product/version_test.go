@@ -0,0 +1,167 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestUpdateRejectsStaleWriters(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+
+	created, err := svc.Create(ctx, testInput(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Version != 1 {
+		t.Fatalf("created version = %d, want 1", created.Version)
+	}
+
+	// Two admins open the product at version 1
+	first, second := "Hamburguesa doble", "Hamburguesa sencilla"
+	loaded := created.Version
+
+	updated, err := svc.Update(ctx, created.ID, UpdateInput{Name: &first, ExpectedVersion: &loaded})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("updated version = %d, want 2", updated.Version)
+	}
+
+	_, err = svc.Update(ctx, created.ID, UpdateInput{Name: &second, ExpectedVersion: &loaded})
+	if !errors.Is(err, ErrProductConflict) {
+		t.Fatalf("stale update err = %v, want %v", err, ErrProductConflict)
+	}
+	if stored := repo.stored(created.ID); stored.Name != first || stored.Version != 2 {
+		t.Errorf("stored = %q at version %d, want the first edit at version 2", stored.Name, stored.Version)
+	}
+}
+
+func TestUpdateConflictsWithWritesAfterItsRead(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	created, err := NewService(repo).Create(ctx, testInput(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both writers read the product before either saves
+	first, err := repo.FindByID(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := repo.FindByID(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.Name = "Hamburguesa doble"
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	second.Name = "Hamburguesa sencilla"
+	if err := repo.Update(ctx, second); !errors.Is(err, ErrProductConflict) {
+		t.Fatalf("second save err = %v, want %v", err, ErrProductConflict)
+	}
+	if second.Version != 1 {
+		t.Errorf("rejected product version = %d, want it left at 1", second.Version)
+	}
+
+	// Writes that don't load the product bump the version too, so they aren't undone either
+	stale := repo.stored(created.ID)
+	if err := repo.SoftDelete(ctx, created.ID, created.CreatedAt); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Restore(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Update(ctx, stale); !errors.Is(err, ErrProductConflict) {
+		t.Errorf("update after a soft delete and restore err = %v, want %v", err, ErrProductConflict)
+	}
+}
+
+func TestUpdateManySkipsStaleProducts(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+	fresh, err := svc.Create(ctx, testInput(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := svc.Create(ctx, testInput(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	staleCopy := repo.stored(stale.ID)
+	name := "Hamburguesa doble"
+	if _, err := svc.Update(ctx, stale.ID, UpdateInput{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+
+	freshCopy := repo.stored(fresh.ID)
+	matched, err := repo.UpdateMany(ctx, []*Product{freshCopy, staleCopy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matched != 1 {
+		t.Errorf("matched = %d, want 1", matched)
+	}
+	if freshCopy.Version != 2 || repo.stored(fresh.ID).Version != 2 {
+		t.Errorf("matched product version = %d, stored %d, want 2", freshCopy.Version, repo.stored(fresh.ID).Version)
+	}
+	if staleCopy.Version != 1 {
+		t.Errorf("skipped product version = %d, want it left at 1", staleCopy.Version)
+	}
+	if stored := repo.stored(stale.ID); stored.Name != name || stored.Version != 2 {
+		t.Errorf("stored = %q at version %d, want the edit at version 2", stored.Name, stored.Version)
+	}
+}
+
+func TestConcurrentUpdatesOfTheSameVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	svc := NewService(repo)
+	created, err := svc.Create(ctx, testInput(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const writers = 10
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		saved     int
+		conflicts int
+	)
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loaded := created.Version
+			available := false
+			_, err := svc.Update(ctx, created.ID, UpdateInput{IsAvailable: &available, ExpectedVersion: &loaded})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				saved++
+			case errors.Is(err, ErrProductConflict):
+				conflicts++
+			default:
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if saved != 1 || conflicts != writers-1 {
+		t.Errorf("saved %d and rejected %d, want 1 and %d", saved, conflicts, writers-1)
+	}
+	if v := repo.stored(created.ID).Version; v != 2 {
+		t.Errorf("version = %d, want 2", v)
+	}
+}
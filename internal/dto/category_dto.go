@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"github.com/emerarteaga/products-api/internal/domain/category"
+)
+
+// CreateCategoryRequest represents the request to create a category
+type CreateCategoryRequest struct {
+	CompanyID   string `json:"company_id" binding:"required"`
+	SalePointID string `json:"sale_point_id" binding:"required"`
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Position    int    `json:"position" binding:"gte=0"`
+	ImageURL    string `json:"image_url" binding:"omitempty,url"`
+	IsActive    *bool  `json:"is_active"` // Defaults to true
+}
+
+// ToCreateInput converts the request to service input
+func (r *CreateCategoryRequest) ToCreateInput() category.CreateInput {
+	return category.CreateInput{
+		CompanyID:   r.CompanyID,
+		SalePointID: r.SalePointID,
+		Name:        r.Name,
+		Position:    r.Position,
+		ImageURL:    r.ImageURL,
+		IsActive:    r.IsActive,
+	}
+}
+
+// UpdateCategoryRequest represents the request to update a category
+type UpdateCategoryRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Position *int    `json:"position" binding:"omitempty,gte=0"`
+	ImageURL *string `json:"image_url" binding:"omitempty,url|len=0"` // "" removes the image
+	IsActive *bool   `json:"is_active"`
+}
+
+// ToUpdateInput converts the request to service input
+func (r *UpdateCategoryRequest) ToUpdateInput() category.UpdateInput {
+	return category.UpdateInput{
+		Name:     r.Name,
+		Position: r.Position,
+		ImageURL: r.ImageURL,
+		IsActive: r.IsActive,
+	}
+}
+
+// CategoryListResponse lists the categories of a sale point in display order.
+// With source "products" the sale point defines no categories and the entries, which have no
+// ID, are the distinct categories of its products.
+type CategoryListResponse struct {
+	Categories []*category.Category `json:"categories"`
+	Source     string               `json:"source"`
+}
+
+// ToCategoryListResponse converts a category listing to response
+func ToCategoryListResponse(l *category.Listing) CategoryListResponse {
+	return CategoryListResponse{Categories: l.Categories, Source: string(l.Source)}
+}
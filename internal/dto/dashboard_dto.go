@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/money"
+)
+
+// DashboardResponse represents the key numbers shown on the admin dashboard.
+// Formatted amounts are included so the page can refresh without money formatting of its own.
+type DashboardResponse struct {
+	GeneratedAt       time.Time                   `json:"generated_at"`
+	Date              string                      `json:"date"`
+	Timezone          string                      `json:"timezone"`
+	OpenOrders        int64                       `json:"open_orders"`
+	OpenByStatus      []DashboardStatusCount      `json:"open_by_status"`
+	Today             DashboardTodayResponse      `json:"today"`
+	LowStock          []DashboardLowStockResponse `json:"low_stock"`
+	LowStockThreshold int                         `json:"low_stock_threshold"`
+	Currency          money.Currency              `json:"currency"`
+}
+
+// DashboardStatusCount is the number of open orders in one status
+type DashboardStatusCount struct {
+	Status order.OrderStatus `json:"status"`
+	Count  int               `json:"count"`
+}
+
+// DashboardTodayResponse summarizes today's orders in the dashboard time zone
+type DashboardTodayResponse struct {
+	OrderCount         int64  `json:"order_count"`
+	Sales              int64  `json:"sales"`
+	SalesFormatted     string `json:"sales_formatted"`
+	AvgTicket          int64  `json:"avg_ticket"`
+	AvgTicketFormatted string `json:"avg_ticket_formatted"`
+	CancelledCount     int64  `json:"cancelled_count"`
+}
+
+// DashboardLowStockResponse is a product running out of stock
+type DashboardLowStockResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	SalePointID string `json:"sale_point_id"`
+	Stock       int    `json:"stock"`
+}
+
+// ToDashboardResponse builds the dashboard from the open orders metrics, today's Z report and the low stock products
+func ToDashboardResponse(open *order.OrderMetrics, openStatuses []order.OrderStatus, today *order.ZReport, lowStock []*product.Product, threshold int, currency money.Currency) DashboardResponse {
+	byStatus := make([]DashboardStatusCount, len(openStatuses))
+	for i, status := range openStatuses {
+		byStatus[i] = DashboardStatusCount{Status: status, Count: open.OrdersByStatus[status]}
+	}
+
+	products := make([]DashboardLowStockResponse, 0, len(lowStock))
+	for _, p := range lowStock {
+		stock := 0
+		if p.Stock != nil {
+			stock = *p.Stock
+		}
+		products = append(products, DashboardLowStockResponse{ID: p.ID, Name: p.Name, SalePointID: p.SalePointID, Stock: stock})
+	}
+
+	return DashboardResponse{
+		GeneratedAt:  time.Now().UTC(),
+		Date:         today.Date,
+		Timezone:     today.Timezone,
+		OpenOrders:   open.OrderCount,
+		OpenByStatus: byStatus,
+		Today: DashboardTodayResponse{
+			OrderCount:         today.OrderCount,
+			Sales:              today.Sales,
+			SalesFormatted:     currency.Format(today.Sales),
+			AvgTicket:          today.AvgTicket,
+			AvgTicketFormatted: currency.Format(today.AvgTicket),
+			CancelledCount:     today.CancelledCount,
+		},
+		LowStock:          products,
+		LowStockThreshold: threshold,
+		Currency:          currency,
+	}
+}
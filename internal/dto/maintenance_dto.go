@@ -0,0 +1,11 @@
+package dto
+
+// SetReadOnlyRequest represents the request to toggle read-only mode
+type SetReadOnlyRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// ReadOnlyResponse represents the current read-only state
+type ReadOnlyResponse struct {
+	ReadOnly bool `json:"read_only"`
+}
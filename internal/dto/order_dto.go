@@ -1,27 +1,98 @@
 package dto
 
-import "github.com/emerarteaga/products-api/internal/domain/order"
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/emerarteaga/products-api/internal/response"
+)
+
+// errInvalidCursor is returned when a pagination or batch cursor cannot be decoded
+var errInvalidCursor = errors.New("invalid cursor")
 
 // CreateOrderRequest represents the request to create an order
 type CreateOrderRequest struct {
+	CompanyID         string                `json:"company_id" binding:"required,max=100"`
+	SalePointID       string                `json:"sale_point_id" binding:"required,max=100"`
 	SaleType          order.SaleType        `json:"sale_type" binding:"required,oneof=DELIVERY ON_SITE"`
+	Channel           order.Channel         `json:"channel" binding:"omitempty,order_channel"`
 	Products          []OrderProductRequest `json:"products" binding:"required,min=1,dive"`
 	Note              *string               `json:"note" binding:"omitempty,max=500"`
+	NoteVisibility    order.NoteVisibility  `json:"note_visibility" binding:"omitempty,oneof=INTERNAL PUBLIC"`
 	Customer          *CustomerRequest      `json:"customer" binding:"omitempty"`
 	ShippingAddress   *string               `json:"shipping_address" binding:"omitempty,max=500"`
 	TableNumber       *int                  `json:"table_number" binding:"omitempty,gte=1"`
 	PaymentReceiptURL *string               `json:"payment_receipt_url" binding:"omitempty,url"`
 	PaymentAccountID  *string               `json:"payment_account_id" binding:"omitempty"`
+	Discount          *DiscountRequest      `json:"discount" binding:"omitempty"`
+	Total             *int64                `json:"total" binding:"omitempty,gte=0"` // Optional, in cents; must match the calculated total
+}
+
+// DiscountRequest represents a discount on the order subtotal
+type DiscountRequest struct {
+	Type        order.DiscountType `json:"type" binding:"required,oneof=PERCENT FIXED"`
+	Value       int64              `json:"value" binding:"gte=0"` // Whole percentage (0-100) or amount in cents; 0 removes the discount on PUT
+	Description string             `json:"description" binding:"omitempty,max=200"`
+}
+
+// toDiscount converts the request to a domain discount, nil when absent
+func (r *DiscountRequest) toDiscount() *order.Discount {
+	if r == nil {
+		return nil
+	}
+	return &order.Discount{Type: r.Type, Value: r.Value, Description: r.Description}
 }
 
 // OrderProductRequest represents a product in the request
 type OrderProductRequest struct {
-	ID          string  `json:"id" binding:"required"`
-	Name        string  `json:"name" binding:"required,min=1,max=200"`
-	Description *string `json:"description" binding:"omitempty,max=500"`
-	Observation *string `json:"observation" binding:"omitempty,max=500"`
-	Price       int64   `json:"price" binding:"required,gte=0"`
-	Quantity    int     `json:"quantity" binding:"required,gte=1"`
+	ID                   string                     `json:"id" binding:"required"`
+	Name                 string                     `json:"name" binding:"required,min=1,max=200"`
+	Description          *string                    `json:"description" binding:"omitempty,max=500"`
+	Observation          *string                    `json:"observation" binding:"omitempty,max=500"`
+	SelectedObservations []string                   `json:"selected_observations" binding:"omitempty,max=10,dive,min=1,max=50"`
+	VariationType        *string                    `json:"variation_type" binding:"omitempty,min=1,max=100"`
+	Price                int64                      `json:"price" binding:"required,gte=0"`
+	Addons               []OrderProductAddonRequest `json:"addons" binding:"omitempty,max=20,dive"`
+	Quantity             int                        `json:"quantity" binding:"required,gte=1"`
+}
+
+// OrderProductAddonRequest represents an addon selected for every unit of a line
+type OrderProductAddonRequest struct {
+	ID       string `json:"id" binding:"required"`
+	Name     string `json:"name" binding:"required,min=1,max=200"`
+	Price    int64  `json:"price" binding:"gte=0"`
+	Quantity int    `json:"quantity" binding:"omitempty,gte=1,max=20"` // Defaults to 1
+}
+
+// ToOrderProduct converts the request line to a domain order line
+func (p OrderProductRequest) ToOrderProduct() order.OrderProduct {
+	var addons []order.OrderProductAddon
+	if len(p.Addons) > 0 {
+		addons = make([]order.OrderProductAddon, len(p.Addons))
+		for i, a := range p.Addons {
+			quantity := a.Quantity
+			if quantity == 0 {
+				quantity = 1
+			}
+			addons[i] = order.OrderProductAddon{ID: a.ID, Name: a.Name, Price: a.Price, Quantity: quantity}
+		}
+	}
+	return order.OrderProduct{
+		ID:                   p.ID,
+		Name:                 p.Name,
+		Description:          p.Description,
+		Observation:          p.Observation,
+		SelectedObservations: p.SelectedObservations,
+		VariationType:        p.VariationType,
+		Price:                p.Price,
+		Addons:               addons,
+		Quantity:             p.Quantity,
+	}
 }
 
 // CustomerRequest represents customer information in the request
@@ -37,14 +108,7 @@ func (r *CreateOrderRequest) ToCreateInput() order.CreateInput {
 	// Convert products
 	products := make([]order.OrderProduct, len(r.Products))
 	for i, p := range r.Products {
-		products[i] = order.OrderProduct{
-			ID:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Observation: p.Observation,
-			Price:       p.Price,
-			Quantity:    p.Quantity,
-		}
+		products[i] = p.ToOrderProduct()
 	}
 
 	// Convert customer if present
@@ -59,78 +123,340 @@ func (r *CreateOrderRequest) ToCreateInput() order.CreateInput {
 	}
 
 	return order.CreateInput{
+		CompanyID:         r.CompanyID,
+		SalePointID:       r.SalePointID,
 		SaleType:          r.SaleType,
+		Channel:           r.Channel,
 		Products:          products,
 		Note:              r.Note,
+		NoteVisibility:    r.NoteVisibility,
 		Customer:          customer,
 		ShippingAddress:   r.ShippingAddress,
 		TableNumber:       r.TableNumber,
 		PaymentReceiptURL: r.PaymentReceiptURL,
 		PaymentAccountID:  r.PaymentAccountID,
+		Discount:          r.Discount.toDiscount(),
+		ExpectedTotal:     r.Total,
 	}
 }
 
 // OrderCreatedResponse represents the response after creating an order
 type OrderCreatedResponse struct {
-	ID        string            `json:"id"`
-	Code      string            `json:"code"`
-	Status    order.OrderStatus `json:"status"`
-	SaleType  order.SaleType    `json:"sale_type"`
-	Total     int64             `json:"total"`
-	CreatedAt string            `json:"created_at"`
-	UpdatedAt string            `json:"updated_at"`
+	ID             string            `json:"id"`
+	Code           string            `json:"code"`
+	Status         order.OrderStatus `json:"status"`
+	SaleType       order.SaleType    `json:"sale_type"`
+	Subtotal       int64             `json:"subtotal"`
+	DiscountAmount int64             `json:"discount_amount"`
+	Total          int64             `json:"total"`
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
 }
 
 // ToCreatedResponse converts order to created response
 func ToCreatedResponse(o *order.Order) OrderCreatedResponse {
 	return OrderCreatedResponse{
-		ID:        o.ID,
-		Code:      o.Code,
-		Status:    o.Status,
-		SaleType:  o.SaleType,
-		Total:     o.Total,
-		CreatedAt: o.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:             o.ID,
+		Code:           o.Code,
+		Status:         o.Status,
+		SaleType:       o.SaleType,
+		Subtotal:       orderSubtotal(o),
+		DiscountAmount: o.DiscountAmount,
+		Total:          o.Total,
+		CreatedAt:      o.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// OrderValidationResponse represents the result of a dry-run order validation
+type OrderValidationResponse struct {
+	Valid   bool                             `json:"valid"`
+	Errors  []response.ValidationErrorDetail `json:"errors,omitempty"`
+	Preview *OrderPreviewResponse            `json:"preview,omitempty"`
+}
+
+// OrderPreviewResponse represents the order Create would store, with its totals breakdown
+type OrderPreviewResponse struct {
+	SaleType       order.SaleType     `json:"sale_type"`
+	Channel        order.Channel      `json:"channel"`
+	Status         order.OrderStatus  `json:"status"`
+	Lines          []OrderPreviewLine `json:"lines"`
+	Subtotal       int64              `json:"subtotal"` // In cents
+	Discount       *DiscountResponse  `json:"discount,omitempty"`
+	DiscountAmount int64              `json:"discount_amount"` // In cents
+	Total          int64              `json:"total"`           // In cents
+}
+
+// OrderPreviewLine represents a product line with its subtotal
+type OrderPreviewLine struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	VariationType *string `json:"variation_type,omitempty"`
+	Price         int64   `json:"price"`
+	Addons        int64   `json:"addons"` // Price of the addons of one unit, in cents
+	Quantity      int     `json:"quantity"`
+	Subtotal      int64   `json:"subtotal"` // (price + addons) * quantity, in cents
+}
+
+// ToValidOrderResponse converts a validated order to a preview response
+func ToValidOrderResponse(o *order.Order) OrderValidationResponse {
+	lines := make([]OrderPreviewLine, len(o.Products))
+	for i, p := range o.Products {
+		lines[i] = OrderPreviewLine{
+			ID:            p.ID,
+			Name:          p.Name,
+			VariationType: p.VariationType,
+			Price:         p.Price,
+			Addons:        p.AddonsPrice(),
+			Quantity:      p.Quantity,
+			Subtotal:      p.LineTotal(),
+		}
+	}
+
+	return OrderValidationResponse{
+		Valid: true,
+		Preview: &OrderPreviewResponse{
+			SaleType:       o.SaleType,
+			Channel:        o.Channel,
+			Status:         o.Status,
+			Lines:          lines,
+			Subtotal:       orderSubtotal(o),
+			Discount:       toDiscountResponse(o.Discount),
+			DiscountAmount: o.DiscountAmount,
+			Total:          o.Total,
+		},
+	}
+}
+
+// ToInvalidOrderResponse builds the response for an order that failed validation
+func ToInvalidOrderResponse(errs []response.ValidationErrorDetail) OrderValidationResponse {
+	return OrderValidationResponse{Valid: false, Errors: errs}
+}
+
+// APIInfoResponse represents the settings clients need to render amounts.
+// Amounts are integers in the currency's minor unit: divide by 10^minor_units.
+type APIInfoResponse struct {
+	Currency money.Currency `json:"currency"`
+}
+
+// OrderLimitsResponse represents the order size guards; null means no limit
+type OrderLimitsResponse struct {
+	MaxLineQuantity *int   `json:"max_line_quantity"`
+	MaxLines        *int   `json:"max_lines"`
+	MaxTotal        *int64 `json:"max_total"` // In cents
+}
+
+// ToOrderLimitsResponse converts order limits to response
+func ToOrderLimitsResponse(l order.OrderLimits) OrderLimitsResponse {
+	var resp OrderLimitsResponse
+	if l.MaxLineQuantity > 0 {
+		resp.MaxLineQuantity = &l.MaxLineQuantity
+	}
+	if l.MaxLines > 0 {
+		resp.MaxLines = &l.MaxLines
 	}
+	if l.MaxTotal > 0 {
+		resp.MaxTotal = &l.MaxTotal
+	}
+	return resp
+}
+
+// OrderStatusesResponse represents the effective state machine of a sale type
+type OrderStatusesResponse struct {
+	SaleType    order.SaleType                            `json:"sale_type"`
+	Statuses    []order.OrderStatus                       `json:"statuses"`    // In lifecycle order
+	Transitions map[order.OrderStatus][]order.OrderStatus `json:"transitions"` // Allowed next statuses per status
+	Terminal    []order.OrderStatus                       `json:"terminal"`
+}
+
+// ToOrderStatusesResponse converts a sale type's transitions to response
+func ToOrderStatusesResponse(saleType order.SaleType, t order.Transitions) OrderStatusesResponse {
+	resp := OrderStatusesResponse{
+		SaleType:    saleType,
+		Statuses:    t.Statuses(),
+		Transitions: make(map[order.OrderStatus][]order.OrderStatus, len(t)),
+		Terminal:    []order.OrderStatus{},
+	}
+	for _, status := range resp.Statuses {
+		next := t[status]
+		if next == nil {
+			next = []order.OrderStatus{}
+		}
+		resp.Transitions[status] = next
+		if len(next) == 0 {
+			resp.Terminal = append(resp.Terminal, status)
+		}
+	}
+	return resp
 }
 
 // OrderTrackResponse represents the public tracking response
 type OrderTrackResponse struct {
-	Code         string            `json:"code"`
-	Status       order.OrderStatus `json:"status"`
-	CustomerName string            `json:"customer_name"`
-	UpdatedAt    string            `json:"updated_at"`
+	Code         string               `json:"code"`
+	Status       order.OrderStatus    `json:"status"`
+	CustomerName string               `json:"customer_name"`
+	Notes        []PublicNoteResponse `json:"notes,omitempty"` // Only PUBLIC notes, when requested with include=notes
+	UpdatedAt    string               `json:"updated_at"`
 }
 
-// ToTrackResponse converts order to tracking response (public, limited data)
-func ToTrackResponse(o *order.Order) OrderTrackResponse {
+// PublicNoteResponse is an order note shown to the customer
+type PublicNoteResponse struct {
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToTrackResponse converts order to tracking response (public, limited data).
+// INTERNAL notes are never included.
+func ToTrackResponse(o *order.Order, includeNotes bool) OrderTrackResponse {
 	customerName := "Guest"
 	if o.Customer != nil {
 		customerName = o.Customer.Name
 	}
 
+	var notes []PublicNoteResponse
+	if includeNotes {
+		for _, n := range o.PublicNotes() {
+			notes = append(notes, PublicNoteResponse{Text: n.Text, CreatedAt: n.CreatedAt.Format("2006-01-02T15:04:05Z07:00")})
+		}
+	}
+
 	return OrderTrackResponse{
 		Code:         o.Code,
 		Status:       o.Status,
 		CustomerName: customerName,
+		Notes:        notes,
 		UpdatedAt:    o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
 
+// OrderStatusEventResponse is the data of a "status" event on the tracking stream
+type OrderStatusEventResponse struct {
+	Code           string            `json:"code"`
+	Status         order.OrderStatus `json:"status"`
+	PreviousStatus order.OrderStatus `json:"previous_status,omitempty"` // Omitted on the initial event
+	UpdatedAt      string            `json:"updated_at"`
+}
+
+// ToStatusEventResponse converts a status event to its stream payload
+func ToStatusEventResponse(e order.StatusEvent) OrderStatusEventResponse {
+	return OrderStatusEventResponse{
+		Code:           e.Code,
+		Status:         e.Status,
+		PreviousStatus: e.Previous,
+		UpdatedAt:      e.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
 // ===================================
 // STAGE 3: PARTIAL UPDATE (PATCH)
 // ===================================
 
 // PartialUpdateOrderRequest represents the request for partial update
 type PartialUpdateOrderRequest struct {
-	Code              string             `json:"code" binding:"required"`
-	Status            *order.OrderStatus `json:"status" binding:"omitempty,oneof=CREATED VERIFIED IN_PROGRESS OUT_FOR_DELIVERY DELIVERED CANCELLED"`
-	Note              *string            `json:"note" binding:"omitempty,max=500"`
-	PaymentReceiptURL *string            `json:"payment_receipt_url" binding:"omitempty,url"`
-	PaymentAccountID  *string            `json:"payment_account_id" binding:"omitempty"`
+	Code              string               `json:"code" binding:"required"`
+	Status            *order.OrderStatus   `json:"status" binding:"omitempty,oneof=CREATED VERIFIED IN_PROGRESS OUT_FOR_DELIVERY DELIVERED CANCELLED"`
+	Note              *string              `json:"note" binding:"omitempty,max=500"`
+	NoteVisibility    order.NoteVisibility `json:"note_visibility" binding:"omitempty,oneof=INTERNAL PUBLIC"`
+	PaymentReceiptURL *string              `json:"payment_receipt_url" binding:"omitempty,url"`
+	PaymentAccountID  *string              `json:"payment_account_id" binding:"omitempty"`
+	PaymentStatus     *order.PaymentStatus `json:"payment_status" binding:"omitempty,oneof=PENDING RECEIPT_UPLOADED CONFIRMED REJECTED REFUNDED"`
+	Version           *int64               `json:"version" binding:"omitempty,gte=0"` // Version the client edited; 409 if the order changed since
 	// Products explicitly NOT allowed in PATCH
 }
 
+// CustomerEditOrderRequest represents a customer's own edit through the tracking code.
+// Any other field in the body is rejected.
+type CustomerEditOrderRequest struct {
+	ShippingAddress *string `json:"shipping_address" binding:"omitempty,max=500"`
+	Note            *string `json:"note" binding:"omitempty,max=500"`
+}
+
+// ToCustomerEditInput converts the request to service input
+func (r *CustomerEditOrderRequest) ToCustomerEditInput() order.CustomerEditInput {
+	return order.CustomerEditInput{
+		ShippingAddress: r.ShippingAddress,
+		Note:            r.Note,
+	}
+}
+
+// AddOrderNoteRequest represents a note appended to an order
+type AddOrderNoteRequest struct {
+	Text       string               `json:"text" binding:"required,max=500"`
+	Author     string               `json:"author" binding:"omitempty,max=100"`
+	Visibility order.NoteVisibility `json:"visibility" binding:"omitempty,oneof=INTERNAL PUBLIC"`
+}
+
+// ToAddNoteInput converts the request to service input
+func (r *AddOrderNoteRequest) ToAddNoteInput() order.AddNoteInput {
+	return order.AddNoteInput{
+		Text:       r.Text,
+		Author:     r.Author,
+		Visibility: r.Visibility,
+	}
+}
+
+// DuplicateOrderRequest represents the options for repeating an order; the body is optional
+type DuplicateOrderRequest struct {
+	SaleType    *order.SaleType `json:"sale_type" binding:"omitempty,oneof=DELIVERY ON_SITE"`
+	Channel     order.Channel   `json:"channel" binding:"omitempty,order_channel"`
+	TableNumber *int            `json:"table_number" binding:"omitempty,gte=1"`
+	Strict      bool            `json:"strict"`
+}
+
+// ToDuplicateInput converts the request to service input
+func (r *DuplicateOrderRequest) ToDuplicateInput() order.DuplicateInput {
+	return order.DuplicateInput{
+		SaleType:    r.SaleType,
+		Channel:     r.Channel,
+		TableNumber: r.TableNumber,
+		Strict:      r.Strict,
+	}
+}
+
+// DuplicateOrderResponse represents the new order and how it differs from the source
+type DuplicateOrderResponse struct {
+	Source       string                    `json:"source"`
+	Order        *OrderResponse            `json:"order"` // null when a strict duplicate was refused
+	PriceChanges []PriceChangeResponse     `json:"price_changes"`
+	Unavailable  []UnavailableLineResponse `json:"unavailable"`
+}
+
+// PriceChangeResponse represents a line repeated at a different price
+type PriceChangeResponse struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	OldPrice  int64  `json:"old_price"`
+	NewPrice  int64  `json:"new_price"`
+}
+
+// UnavailableLineResponse represents a line that could not be repeated
+type UnavailableLineResponse struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	Reason    string `json:"reason"` // removed, unavailable or price_unset
+}
+
+// ToDuplicateOrderResponse converts a duplication result to response
+func ToDuplicateOrderResponse(r *order.DuplicateResult) DuplicateOrderResponse {
+	resp := DuplicateOrderResponse{
+		Source:       r.Source,
+		PriceChanges: make([]PriceChangeResponse, len(r.PriceChanges)),
+		Unavailable:  make([]UnavailableLineResponse, len(r.Unavailable)),
+	}
+	if r.Order != nil {
+		o := ToOrderResponse(r.Order)
+		resp.Order = &o
+	}
+	for i, c := range r.PriceChanges {
+		resp.PriceChanges[i] = PriceChangeResponse{ProductID: c.ProductID, Name: c.Name, OldPrice: c.OldPrice, NewPrice: c.NewPrice}
+	}
+	for i, l := range r.Unavailable {
+		resp.Unavailable[i] = UnavailableLineResponse{ProductID: l.ProductID, Name: l.Name, Quantity: l.Quantity, Reason: l.Reason}
+	}
+	return resp
+}
+
 // ToPartialUpdateInput converts DTO to service input
 func (r *PartialUpdateOrderRequest) ToPartialUpdateInput() order.PartialUpdateInput {
 	return order.PartialUpdateInput{
@@ -138,6 +464,8 @@ func (r *PartialUpdateOrderRequest) ToPartialUpdateInput() order.PartialUpdateIn
 		Note:              r.Note,
 		PaymentReceiptURL: r.PaymentReceiptURL,
 		PaymentAccountID:  r.PaymentAccountID,
+		PaymentStatus:     r.PaymentStatus,
+		ExpectedVersion:   r.Version,
 	}
 }
 
@@ -152,6 +480,9 @@ type ModifyOrderRequest struct {
 	ShippingAddress *string               `json:"shipping_address" binding:"omitempty,max=500"`
 	Customer        *CustomerRequest      `json:"customer" binding:"omitempty"`
 	Note            *string               `json:"note" binding:"omitempty,max=500"`
+	NoteVisibility  order.NoteVisibility  `json:"note_visibility" binding:"omitempty,oneof=INTERNAL PUBLIC"`
+	Discount        *DiscountRequest      `json:"discount" binding:"omitempty"`
+	Version         *int64                `json:"version" binding:"omitempty,gte=0"` // Version the client edited; 409 if the order changed since
 }
 
 // ToModifyInput converts DTO to service input
@@ -161,14 +492,7 @@ func (r *ModifyOrderRequest) ToModifyInput() order.ModifyInput {
 	if len(r.Products) > 0 {
 		products = make([]order.OrderProduct, len(r.Products))
 		for i, p := range r.Products {
-			products[i] = order.OrderProduct{
-				ID:          p.ID,
-				Name:        p.Name,
-				Description: p.Description,
-				Observation: p.Observation,
-				Price:       p.Price,
-				Quantity:    p.Quantity,
-			}
+			products[i] = p.ToOrderProduct()
 		}
 	}
 
@@ -188,6 +512,9 @@ func (r *ModifyOrderRequest) ToModifyInput() order.ModifyInput {
 		ShippingAddress: r.ShippingAddress,
 		Customer:        customer,
 		Note:            r.Note,
+		NoteVisibility:  r.NoteVisibility,
+		Discount:        r.Discount.toDiscount(),
+		ExpectedVersion: r.Version,
 	}
 }
 
@@ -197,79 +524,400 @@ func (r *ModifyOrderRequest) ToModifyInput() order.ModifyInput {
 
 // OrderResponse represents a complete order response
 type OrderResponse struct {
-	ID                string                 `json:"id"`
-	Code              string                 `json:"code"`
-	Status            order.OrderStatus      `json:"status"`
-	SaleType          order.SaleType         `json:"sale_type"`
-	Products          []OrderProductResponse `json:"products"`
-	Total             int64                  `json:"total"`
-	Note              *string                `json:"note,omitempty"`
-	Customer          *CustomerResponse      `json:"customer,omitempty"`
-	ShippingAddress   *string                `json:"shipping_address,omitempty"`
-	TableNumber       *int                   `json:"table_number,omitempty"`
-	PaymentReceiptURL *string                `json:"payment_receipt_url,omitempty"`
-	PaymentAccountID  *string                `json:"payment_account_id,omitempty"`
-	CreatedAt         string                 `json:"created_at"`
-	UpdatedAt         string                 `json:"updated_at"`
+	ID                string                       `json:"id"`
+	Code              string                       `json:"code"`
+	CompanyID         string                       `json:"company_id"`    // "default" for orders stored before tenants
+	SalePointID       string                       `json:"sale_point_id"` // "default" for orders stored before tenants
+	Status            order.OrderStatus            `json:"status"`
+	SaleType          order.SaleType               `json:"sale_type"`
+	Channel           order.Channel                `json:"channel"`
+	Products          []OrderProductResponse       `json:"products"`
+	Subtotal          int64                        `json:"subtotal"`
+	Discount          *DiscountResponse            `json:"discount,omitempty"`
+	DiscountAmount    int64                        `json:"discount_amount"`
+	Total             int64                        `json:"total"`
+	Notes             []OrderNoteResponse          `json:"notes"`
+	Customer          *CustomerResponse            `json:"customer,omitempty"`
+	ShippingAddress   *string                      `json:"shipping_address,omitempty"`
+	TableNumber       *int                         `json:"table_number,omitempty"`
+	PaymentReceiptURL *string                      `json:"payment_receipt_url,omitempty"`
+	PaymentAccountID  *string                      `json:"payment_account_id,omitempty"`
+	PaymentStatus     order.PaymentStatus          `json:"payment_status"`
+	Version           int64                        `json:"version"`               // Send back as If-Match or version to update safely
+	ArchivedAt        *string                      `json:"archived_at,omitempty"` // Archived orders are read-only
+	StatusHistory     []StatusChangeResponse       `json:"status_history,omitempty"`
+	StatusTimes       map[order.OrderStatus]string `json:"status_times,omitempty"` // First time the order reached each status after CREATED
+	EditHistory       []FieldEditResponse          `json:"edit_history,omitempty"`
+	CreatedAt         string                       `json:"created_at"`
+	UpdatedAt         string                       `json:"updated_at"`
+}
+
+// OrderNoteResponse represents an order note in the response
+type OrderNoteResponse struct {
+	Text       string               `json:"text"`
+	Author     string               `json:"author"`
+	Visibility order.NoteVisibility `json:"visibility"`
+	CreatedAt  string               `json:"created_at"`
+}
+
+// StatusChangeResponse represents a status transition in the response
+type StatusChangeResponse struct {
+	From      order.OrderStatus `json:"from"`
+	To        order.OrderStatus `json:"to"`
+	Actor     string            `json:"actor"`
+	ChangedAt string            `json:"changed_at"`
+}
+
+// FieldEditResponse represents a field edit in the response
+type FieldEditResponse struct {
+	Fields   []string              `json:"fields"`
+	Actor    string                `json:"actor"`
+	EditedAt string                `json:"edited_at"`
+	Changes  *OrderChangesResponse `json:"changes,omitempty"`
+}
+
+// ModifyOrderResponse represents the modified order and what the modification changed
+type ModifyOrderResponse struct {
+	OrderResponse
+	Changes         OrderChangesResponse `json:"changes"`
+	ProductsChanged bool                 `json:"products_changed"`
+}
+
+// OrderChangesResponse represents the diff between an order before and after a modification
+type OrderChangesResponse struct {
+	Fields          []string                `json:"fields"`
+	Products        ProductChangesResponse  `json:"products"`
+	ShippingAddress *TextChangeResponse     `json:"shipping_address,omitempty"`
+	Customer        *CustomerChangeResponse `json:"customer,omitempty"`
+	Note            *TextChangeResponse     `json:"note,omitempty"` // Only on edits stored before notes were append-only
+	NotesAdded      []OrderNoteResponse     `json:"notes_added,omitempty"`
+	Discount        *DiscountChangeResponse `json:"discount,omitempty"`
+	Total           TotalChangeResponse     `json:"total"`
+}
+
+// DiscountChangeResponse represents the previous and new discount
+type DiscountChangeResponse struct {
+	Previous *DiscountResponse `json:"previous"`
+	New      *DiscountResponse `json:"new"`
+}
+
+// ProductChangesResponse represents the product lines added, removed or changed
+type ProductChangesResponse struct {
+	Added    []OrderProductResponse `json:"added"`
+	Removed  []OrderProductResponse `json:"removed"`
+	Modified []LineChangeResponse   `json:"modified"`
+}
+
+// LineChangeResponse represents a product line whose quantity or price changed
+type LineChangeResponse struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	PreviousQuantity int    `json:"previous_quantity"`
+	Quantity         int    `json:"quantity"`
+	PreviousPrice    int64  `json:"previous_price"`
+	Price            int64  `json:"price"`
+}
+
+// TextChangeResponse represents the previous and new value of a text field
+type TextChangeResponse struct {
+	Previous *string `json:"previous"`
+	New      *string `json:"new"`
+}
+
+// CustomerChangeResponse represents the previous and new customer
+type CustomerChangeResponse struct {
+	Previous *CustomerResponse `json:"previous"`
+	New      *CustomerResponse `json:"new"`
+}
+
+// TotalChangeResponse represents how the order total moved, in cents
+type TotalChangeResponse struct {
+	Previous int64 `json:"previous"`
+	New      int64 `json:"new"`
+	Delta    int64 `json:"delta"`
+}
+
+// ToModifyOrderResponse converts a modification result to response
+func ToModifyOrderResponse(r *order.ModifyResult) ModifyOrderResponse {
+	return ModifyOrderResponse{
+		OrderResponse:   ToOrderResponse(r.Order),
+		Changes:         ToOrderChangesResponse(r.Changes),
+		ProductsChanged: r.Changes.ProductsChanged(),
+	}
+}
+
+// ToOrderChangesResponse converts an order diff to response
+func ToOrderChangesResponse(d order.OrderDiff) OrderChangesResponse {
+	resp := OrderChangesResponse{
+		Fields: d.Fields(),
+		Products: ProductChangesResponse{
+			Added:    make([]OrderProductResponse, len(d.Added)),
+			Removed:  make([]OrderProductResponse, len(d.Removed)),
+			Modified: make([]LineChangeResponse, len(d.Modified)),
+		},
+		Total: TotalChangeResponse{Previous: d.PreviousTotal, New: d.Total, Delta: d.TotalDelta()},
+	}
+	if resp.Fields == nil {
+		resp.Fields = []string{}
+	}
+	for i, p := range d.Added {
+		resp.Products.Added[i] = toOrderProductResponse(p)
+	}
+	for i, p := range d.Removed {
+		resp.Products.Removed[i] = toOrderProductResponse(p)
+	}
+	for i, c := range d.Modified {
+		resp.Products.Modified[i] = LineChangeResponse(c)
+	}
+	if d.ShippingAddress != nil {
+		resp.ShippingAddress = &TextChangeResponse{Previous: d.ShippingAddress.Previous, New: d.ShippingAddress.New}
+	}
+	if d.Customer != nil {
+		resp.Customer = &CustomerChangeResponse{
+			Previous: toCustomerResponse(d.Customer.Previous),
+			New:      toCustomerResponse(d.Customer.New),
+		}
+	}
+	if d.Note != nil {
+		resp.Note = &TextChangeResponse{Previous: d.Note.Previous, New: d.Note.New}
+	}
+	if len(d.NotesAdded) > 0 {
+		resp.NotesAdded = toOrderNotesResponse(d.NotesAdded)
+	}
+	if d.Discount != nil {
+		resp.Discount = &DiscountChangeResponse{
+			Previous: toDiscountResponse(d.Discount.Previous),
+			New:      toDiscountResponse(d.Discount.New),
+		}
+	}
+	return resp
+}
+
+// OrderSearchResponse represents an order found by free text search
+type OrderSearchResponse struct {
+	OrderResponse
+	MatchedOn []string `json:"matched_on"`
+}
+
+// ToSearchResponse converts a search result to response
+func ToSearchResponse(r order.SearchResult) OrderSearchResponse {
+	return OrderSearchResponse{
+		OrderResponse: ToOrderResponse(r.Order),
+		MatchedOn:     r.MatchedOn,
+	}
 }
 
 // OrderProductResponse represents a product in the response
 type OrderProductResponse struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
-	Observation *string `json:"observation,omitempty"`
-	Price       int64   `json:"price"`
-	Quantity    int     `json:"quantity"`
+	ID                   string                      `json:"id"`
+	Name                 string                      `json:"name"`
+	Description          *string                     `json:"description,omitempty"`
+	Observation          *string                     `json:"observation,omitempty"`
+	SelectedObservations []string                    `json:"selected_observations,omitempty"`
+	VariationType        *string                     `json:"variation_type,omitempty"`
+	Price                int64                       `json:"price"`
+	Addons               []OrderProductAddonResponse `json:"addons,omitempty"`
+	Quantity             int                         `json:"quantity"`
+	LineTotal            int64                       `json:"line_total"` // (price + addons) * quantity, in cents
+}
+
+// OrderProductAddonResponse represents an addon of an order line
+type OrderProductAddonResponse struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Price    int64  `json:"price"`
+	Quantity int    `json:"quantity"`
 }
 
 // CustomerResponse represents customer information in the response
 type CustomerResponse struct {
-	Identification string       `json:"identification"`
-	IDType         order.IDType `json:"id_type"`
-	Name           string       `json:"name"`
-	Phone          string       `json:"phone"`
+	Identification  string       `json:"identification"`
+	IDType          order.IDType `json:"id_type"`
+	Name            string       `json:"name"`
+	Phone           string       `json:"phone"`
+	PhoneNormalized string       `json:"phone_normalized,omitempty"`
+}
+
+// toOrderProductResponse converts an order line to response
+func toOrderProductResponse(p order.OrderProduct) OrderProductResponse {
+	return OrderProductResponse{
+		ID:                   p.ID,
+		Name:                 p.Name,
+		Description:          p.Description,
+		Observation:          p.Observation,
+		SelectedObservations: p.SelectedObservations,
+		VariationType:        p.VariationType,
+		Price:                p.Price,
+		Addons:               toOrderProductAddonsResponse(p.Addons),
+		Quantity:             p.Quantity,
+		LineTotal:            p.LineTotal(),
+	}
+}
+
+// toOrderProductAddonsResponse converts the addons of an order line to response
+func toOrderProductAddonsResponse(addons []order.OrderProductAddon) []OrderProductAddonResponse {
+	if len(addons) == 0 {
+		return nil
+	}
+	resp := make([]OrderProductAddonResponse, len(addons))
+	for i, a := range addons {
+		resp[i] = OrderProductAddonResponse{ID: a.ID, Name: a.Name, Price: a.Price, Quantity: a.Quantity}
+	}
+	return resp
+}
+
+// DiscountResponse represents the discount of an order
+type DiscountResponse struct {
+	Type        order.DiscountType `json:"type"`
+	Value       int64              `json:"value"`
+	Description string             `json:"description,omitempty"`
+}
+
+// toDiscountResponse converts a discount to response, nil when absent
+func toDiscountResponse(d *order.Discount) *DiscountResponse {
+	if d == nil {
+		return nil
+	}
+	return &DiscountResponse{Type: d.Type, Value: d.Value, Description: d.Description}
+}
+
+// toOrderNotesResponse converts order notes to response, never nil
+func toOrderNotesResponse(notes []order.OrderNote) []OrderNoteResponse {
+	resp := make([]OrderNoteResponse, len(notes))
+	for i, n := range notes {
+		resp[i] = OrderNoteResponse{
+			Text:       n.Text,
+			Author:     n.Author,
+			Visibility: n.Visibility,
+			CreatedAt:  n.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+	return resp
+}
+
+// orderSubtotal returns the total before the discount; orders stored before discounts have no subtotal field
+func orderSubtotal(o *order.Order) int64 {
+	return o.Total + o.DiscountAmount
+}
+
+// toCustomerResponse converts a customer to response, nil when absent
+func toCustomerResponse(c *order.Customer) *CustomerResponse {
+	if c == nil {
+		return nil
+	}
+	return &CustomerResponse{
+		Identification:  c.Identification,
+		IDType:          c.IDType,
+		Name:            c.Name,
+		Phone:           c.Phone,
+		PhoneNormalized: c.PhoneNormalized,
+	}
 }
 
 // ToOrderResponse converts order to full response
 func ToOrderResponse(o *order.Order) OrderResponse {
+	return toOrderResponse(o, make([]OrderProductResponse, len(o.Products)))
+}
+
+// ToOrderResponses converts a page of orders, allocating the product lines of all orders at once
+func ToOrderResponses(orders []*order.Order) []OrderResponse {
+	lines := 0
+	for _, o := range orders {
+		lines += len(o.Products)
+	}
+	products := make([]OrderProductResponse, lines)
+
+	responses := make([]OrderResponse, len(orders))
+	for i, o := range orders {
+		n := len(o.Products)
+		// Full slice expression so an append on one order can't overwrite the next
+		responses[i] = toOrderResponse(o, products[:n:n])
+		products = products[n:]
+	}
+	return responses
+}
+
+// toOrderResponse converts order to full response, filling products (len(o.Products)) with its lines
+func toOrderResponse(o *order.Order, products []OrderProductResponse) OrderResponse {
 	// Convert products
-	products := make([]OrderProductResponse, len(o.Products))
 	for i, p := range o.Products {
-		products[i] = OrderProductResponse{
-			ID:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Observation: p.Observation,
-			Price:       p.Price,
-			Quantity:    p.Quantity,
-		}
+		products[i] = toOrderProductResponse(p)
 	}
 
 	// Convert customer if present
-	var customer *CustomerResponse
-	if o.Customer != nil {
-		customer = &CustomerResponse{
-			Identification: o.Customer.Identification,
-			IDType:         o.Customer.IDType,
-			Name:           o.Customer.Name,
-			Phone:          o.Customer.Phone,
+	customer := toCustomerResponse(o.Customer)
+
+	// Convert status history
+	var history []StatusChangeResponse
+	if len(o.StatusHistory) > 0 {
+		history = make([]StatusChangeResponse, len(o.StatusHistory))
+		for i, h := range o.StatusHistory {
+			history[i] = StatusChangeResponse{
+				From:      h.From,
+				To:        h.To,
+				Actor:     h.Actor,
+				ChangedAt: h.ChangedAt.Format("2006-01-02T15:04:05Z07:00"),
+			}
+		}
+	}
+
+	var edits []FieldEditResponse
+	if len(o.EditHistory) > 0 {
+		edits = make([]FieldEditResponse, len(o.EditHistory))
+		for i, e := range o.EditHistory {
+			edits[i] = FieldEditResponse{
+				Fields:   e.Fields,
+				Actor:    e.Actor,
+				EditedAt: e.EditedAt.Format("2006-01-02T15:04:05Z07:00"),
+			}
+			if e.Changes != nil {
+				changes := ToOrderChangesResponse(*e.Changes)
+				edits[i].Changes = &changes
+			}
+		}
+	}
+
+	var archivedAt *string
+	if o.ArchivedAt != nil {
+		formatted := o.ArchivedAt.Format("2006-01-02T15:04:05Z07:00")
+		archivedAt = &formatted
+	}
+
+	var statusTimes map[order.OrderStatus]string
+	for _, status := range order.AllStatuses {
+		if at := o.StatusTime(status); at != nil && status != order.StatusCreated {
+			if statusTimes == nil {
+				statusTimes = make(map[order.OrderStatus]string)
+			}
+			statusTimes[status] = at.Format("2006-01-02T15:04:05Z07:00")
 		}
 	}
 
 	return OrderResponse{
 		ID:                o.ID,
 		Code:              o.Code,
+		CompanyID:         o.TenantCompanyID(),
+		SalePointID:       o.TenantSalePointID(),
 		Status:            o.Status,
 		SaleType:          o.SaleType,
+		Channel:           o.Channel,
 		Products:          products,
+		Subtotal:          orderSubtotal(o),
+		Discount:          toDiscountResponse(o.Discount),
+		DiscountAmount:    o.DiscountAmount,
 		Total:             o.Total,
-		Note:              o.Note,
+		Notes:             toOrderNotesResponse(o.NoteList()),
 		Customer:          customer,
 		ShippingAddress:   o.ShippingAddress,
 		TableNumber:       o.TableNumber,
 		PaymentReceiptURL: o.PaymentReceiptURL,
 		PaymentAccountID:  o.PaymentAccountID,
+		PaymentStatus:     o.CurrentPaymentStatus(),
+		Version:           o.Version,
+		ArchivedAt:        archivedAt,
+		StatusHistory:     history,
+		StatusTimes:       statusTimes,
+		EditHistory:       edits,
 		CreatedAt:         o.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:         o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
@@ -283,23 +931,354 @@ func ToOrderResponse(o *order.Order) OrderResponse {
 type OrderMetricsResponse struct {
 	Metrics     MetricsData                 `json:"metrics"`
 	TopProducts []order.ProductSalesSummary `json:"top_products"`
+	Currency    money.Currency              `json:"currency"`
+	Filters     AppliedFiltersResponse      `json:"filters"`
 }
 
 // MetricsData represents aggregated metrics
 type MetricsData struct {
-	TotalSales     int64                     `json:"total_sales"`
-	AvgTicket      int64                     `json:"avg_ticket"`
-	OrdersByStatus map[order.OrderStatus]int `json:"orders_by_status"`
+	OrderCount       int64                                    `json:"order_count"`
+	TotalSales       int64                                    `json:"total_sales"` // After discounts
+	TotalDiscounts   int64                                    `json:"total_discounts"`
+	AvgTicket        int64                                    `json:"avg_ticket"`
+	OrdersByStatus   map[order.OrderStatus]int                `json:"orders_by_status"`
+	OrdersByChannel  map[order.Channel]int                    `json:"orders_by_channel"`
+	OrdersBySaleType map[order.SaleType]order.SaleTypeMetrics `json:"orders_by_sale_type"`
+	OrdersByPayment  map[order.PaymentStatus]int              `json:"orders_by_payment_status"`
+	StageDurations   []StageDurationResponse                  `json:"stage_durations"`
+}
+
+// StageDurationResponse is the average time orders spent between two consecutive statuses
+type StageDurationResponse struct {
+	Stage      string            `json:"stage"` // e.g. "created_to_verified"
+	From       order.OrderStatus `json:"from"`
+	To         order.OrderStatus `json:"to"`
+	AvgSeconds int64             `json:"avg_seconds"`
+	OrderCount int64             `json:"order_count"` // Orders that went through both statuses; others are left out of the average
+}
+
+// AppliedFiltersResponse echoes the filters that were understood and applied.
+// Parameters that could not be parsed are omitted, so clients can detect them.
+type AppliedFiltersResponse struct {
+	CompanyID        *string              `json:"company_id,omitempty"`
+	SalePointID      *string              `json:"sale_point_id,omitempty"`
+	DateFrom         *string              `json:"date_from,omitempty"`
+	DateTo           *string              `json:"date_to,omitempty"`
+	Status           *order.OrderStatus   `json:"status,omitempty"`
+	Statuses         []order.OrderStatus  `json:"statuses,omitempty"`
+	SaleType         *order.SaleType      `json:"sale_type,omitempty"`
+	Channel          *order.Channel       `json:"channel,omitempty"`
+	PaymentStatus    *order.PaymentStatus `json:"payment_status,omitempty"`
+	ProductID        *string              `json:"product_id,omitempty"`
+	ProductName      *string              `json:"product_name,omitempty"`
+	MinTotal         *int64               `json:"min_total,omitempty"`
+	MaxTotal         *int64               `json:"max_total,omitempty"`
+	IncludeArchived  bool                 `json:"include_archived,omitempty"`
+	ExcludeCancelled bool                 `json:"exclude_cancelled,omitempty"`
+}
+
+// ToAppliedFiltersResponse converts order filters to the applied filters echo
+func ToAppliedFiltersResponse(f order.OrderFilters) AppliedFiltersResponse {
+	applied := AppliedFiltersResponse{
+		CompanyID:        f.CompanyID,
+		SalePointID:      f.SalePointID,
+		Status:           f.Status,
+		Statuses:         f.Statuses,
+		SaleType:         f.SaleType,
+		Channel:          f.Channel,
+		PaymentStatus:    f.PaymentStatus,
+		ProductID:        f.ProductID,
+		ProductName:      f.ProductName,
+		MinTotal:         f.MinTotal,
+		MaxTotal:         f.MaxTotal,
+		IncludeArchived:  f.IncludeArchived,
+		ExcludeCancelled: f.ExcludeCancelled,
+	}
+
+	dateFrom, dateTo := f.ParseDateRange()
+	if dateFrom != nil {
+		formatted := dateFrom.Format(time.RFC3339)
+		applied.DateFrom = &formatted
+	}
+	if dateTo != nil {
+		formatted := dateTo.Format(time.RFC3339)
+		applied.DateTo = &formatted
+	}
+
+	return applied
 }
 
 // ToMetricsResponse converts order metrics to response
-func ToMetricsResponse(m *order.OrderMetrics) OrderMetricsResponse {
+func ToMetricsResponse(m *order.OrderMetrics, filters order.OrderFilters, currency money.Currency) OrderMetricsResponse {
 	return OrderMetricsResponse{
 		Metrics: MetricsData{
-			TotalSales:     m.TotalSales,
-			AvgTicket:      m.AvgTicket,
-			OrdersByStatus: m.OrdersByStatus,
+			OrderCount:       m.OrderCount,
+			TotalSales:       m.TotalSales,
+			TotalDiscounts:   m.TotalDiscounts,
+			AvgTicket:        m.AvgTicket,
+			OrdersByStatus:   m.OrdersByStatus,
+			OrdersByChannel:  m.OrdersByChannel,
+			OrdersBySaleType: m.OrdersBySaleType,
+			OrdersByPayment:  m.OrdersByPayment,
+			StageDurations:   toStageDurationsResponse(m.StageDurations),
 		},
 		TopProducts: m.TopProducts,
+		Currency:    currency,
+		Filters:     ToAppliedFiltersResponse(filters),
+	}
+}
+
+// toStageDurationsResponse converts stage durations to response
+func toStageDurationsResponse(durations []order.StageDuration) []StageDurationResponse {
+	out := make([]StageDurationResponse, len(durations))
+	for i, d := range durations {
+		out[i] = StageDurationResponse{
+			Stage:      d.Key(),
+			From:       d.From,
+			To:         d.To,
+			AvgSeconds: d.AvgSeconds,
+			OrderCount: d.OrderCount,
+		}
 	}
+	return out
+}
+
+// CustomerStatsResponse summarizes every order of a customer
+type CustomerStatsResponse struct {
+	OrderCount    int64          `json:"order_count"`
+	LifetimeSpend int64          `json:"lifetime_spend"` // Orders not cancelled, in cents
+	LastOrderAt   *string        `json:"last_order_at,omitempty"`
+	Currency      money.Currency `json:"currency"`
+}
+
+// ToCustomerStatsResponse converts customer stats to response
+func ToCustomerStatsResponse(s order.CustomerStats, currency money.Currency) CustomerStatsResponse {
+	var lastOrderAt *string
+	if s.LastOrderAt != nil {
+		formatted := s.LastOrderAt.Format("2006-01-02T15:04:05Z07:00")
+		lastOrderAt = &formatted
+	}
+	return CustomerStatsResponse{
+		OrderCount:    s.OrderCount,
+		LifetimeSpend: s.LifetimeSpend,
+		LastOrderAt:   lastOrderAt,
+		Currency:      currency,
+	}
+}
+
+// ZReportResponse represents the daily Z report
+type ZReportResponse struct {
+	Date            string                                     `json:"date"`
+	Timezone        string                                     `json:"timezone"`
+	Window          ZReportWindowResponse                      `json:"window"`
+	OrderCount      int64                                      `json:"order_count"`
+	Sales           int64                                      `json:"sales"`
+	AvgTicket       int64                                      `json:"avg_ticket"`
+	CancelledCount  int64                                      `json:"cancelled_count"`
+	CancelledSales  int64                                      `json:"cancelled_sales"`
+	OrdersByStatus  map[order.OrderStatus]int                  `json:"orders_by_status"`
+	OrdersByChannel map[order.Channel]int                      `json:"orders_by_channel"`
+	BySaleType      map[order.SaleType]ZReportSaleTypeResponse `json:"by_sale_type"`
+	TopProducts     []order.ProductSalesSummary                `json:"top_products"`
+	Currency        money.Currency                             `json:"currency"`
+}
+
+// ZReportWindowResponse is the UTC window the report covers (both ends inclusive)
+type ZReportWindowResponse struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ZReportSaleTypeResponse summarizes the non-cancelled orders of one sale type
+type ZReportSaleTypeResponse struct {
+	OrderCount int64 `json:"order_count"`
+	Sales      int64 `json:"sales"`
+}
+
+// ToZReportResponse converts a Z report to response
+func ToZReportResponse(r *order.ZReport, currency money.Currency) ZReportResponse {
+	bySaleType := make(map[order.SaleType]ZReportSaleTypeResponse, len(r.BySaleType))
+	for saleType, summary := range r.BySaleType {
+		bySaleType[saleType] = ZReportSaleTypeResponse{OrderCount: summary.OrderCount, Sales: summary.Sales}
+	}
+
+	return ZReportResponse{
+		Date:     r.Date,
+		Timezone: r.Timezone,
+		Window: ZReportWindowResponse{
+			From: r.From.Format(time.RFC3339Nano),
+			To:   r.To.Format(time.RFC3339Nano),
+		},
+		OrderCount:      r.OrderCount,
+		Sales:           r.Sales,
+		AvgTicket:       r.AvgTicket,
+		CancelledCount:  r.CancelledCount,
+		CancelledSales:  r.CancelledSales,
+		OrdersByStatus:  r.OrdersByStatus,
+		OrdersByChannel: r.OrdersByChannel,
+		BySaleType:      bySaleType,
+		TopProducts:     r.TopProducts,
+		Currency:        currency,
+	}
+}
+
+// ===================================
+// BATCH STATUS UPDATE
+// ===================================
+
+// BatchStatusRequest represents a status change applied to several orders
+type BatchStatusRequest struct {
+	Codes  []string          `json:"codes" binding:"required,min=1,max=100,dive,required"`
+	Status order.OrderStatus `json:"status" binding:"required,oneof=CREATED VERIFIED IN_PROGRESS OUT_FOR_DELIVERY DELIVERED CANCELLED"`
+}
+
+// ToBatchStatusInput converts the request to service input
+func (r *BatchStatusRequest) ToBatchStatusInput() order.BatchStatusInput {
+	return order.BatchStatusInput{Codes: r.Codes, Status: r.Status}
+}
+
+// BatchStatusResponse reports the outcome of every order of a batch status update
+type BatchStatusResponse struct {
+	Status    order.OrderStatus         `json:"status"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+	Results   []BatchStatusItemResponse `json:"results"`
+}
+
+// BatchStatusItemResponse is the outcome of one order, with the HTTP status a single update would have returned
+type BatchStatusItemResponse struct {
+	Code       string            `json:"code"`
+	Success    bool              `json:"success"`
+	HTTPStatus int               `json:"http_status"`
+	Status     order.OrderStatus `json:"status,omitempty"` // Status after the update, on success
+	Error      string            `json:"error,omitempty"`
+}
+
+// ToBatchStatusResponse converts batch results to response; httpStatus maps a failed update to its HTTP status
+func ToBatchStatusResponse(status order.OrderStatus, results []order.BatchStatusResult, httpStatus func(error) int) BatchStatusResponse {
+	resp := BatchStatusResponse{Status: status, Results: make([]BatchStatusItemResponse, len(results))}
+	for i, r := range results {
+		item := BatchStatusItemResponse{Code: r.Code, HTTPStatus: http.StatusOK}
+		if r.Err != nil {
+			item.HTTPStatus = httpStatus(r.Err)
+			item.Error = r.Err.Error()
+			resp.Failed++
+		} else {
+			item.Success = true
+			item.Status = r.Order.Status
+			resp.Succeeded++
+		}
+		resp.Results[i] = item
+	}
+	return resp
+}
+
+// ===================================
+// ADMIN: TOTALS RECALCULATION
+// ===================================
+
+// RecalculateTotalsResponse represents the result of one recalculation batch
+type RecalculateTotalsResponse struct {
+	DryRun        bool                       `json:"dry_run"`
+	Processed     int                        `json:"processed"`
+	Fixed         int64                      `json:"fixed"`
+	Discrepancies []TotalDiscrepancyResponse `json:"discrepancies"`
+	NextCursor    *string                    `json:"next_cursor"` // null when all matching orders were processed
+}
+
+// TotalDiscrepancyResponse represents an order whose stored amounts do not match its line items
+type TotalDiscrepancyResponse struct {
+	Code             string `json:"code"`
+	StoredTotal      int64  `json:"stored_total"`
+	ComputedTotal    int64  `json:"computed_total"`
+	StoredSubtotal   int64  `json:"stored_subtotal"`
+	ComputedSubtotal int64  `json:"computed_subtotal"`
+}
+
+// ToRecalculateTotalsResponse converts a recalculation result to response
+func ToRecalculateTotalsResponse(r *order.RecalculateTotalsResult) RecalculateTotalsResponse {
+	discrepancies := make([]TotalDiscrepancyResponse, len(r.Discrepancies))
+	for i, d := range r.Discrepancies {
+		discrepancies[i] = TotalDiscrepancyResponse{
+			Code:             d.Code,
+			StoredTotal:      d.StoredTotal,
+			ComputedTotal:    d.ComputedTotal,
+			StoredSubtotal:   d.StoredSubtotal,
+			ComputedSubtotal: d.ComputedSubtotal,
+		}
+	}
+
+	var nextCursor *string
+	if r.NextCursor != nil {
+		encoded := EncodeBatchCursor(*r.NextCursor)
+		nextCursor = &encoded
+	}
+
+	return RecalculateTotalsResponse{
+		DryRun:        r.DryRun,
+		Processed:     r.Processed,
+		Fixed:         r.Fixed,
+		Discrepancies: discrepancies,
+		NextCursor:    nextCursor,
+	}
+}
+
+// ===================================
+// ADMIN: ARCHIVE
+// ===================================
+
+// ArchiveOrdersResponse reports the progress of an archive run
+type ArchiveOrdersResponse struct {
+	DryRun     bool    `json:"dry_run"`
+	OlderThan  string  `json:"older_than"`
+	Batches    int     `json:"batches"`
+	Scanned    int     `json:"scanned"`
+	Archived   int64   `json:"archived"`
+	Done       bool    `json:"done"`
+	NextCursor *string `json:"next_cursor"` // null when there is nothing left to archive
+}
+
+// ToArchiveOrdersResponse converts an archive result to response
+func ToArchiveOrdersResponse(r *order.ArchiveResult) ArchiveOrdersResponse {
+	var nextCursor *string
+	if r.NextCursor != nil {
+		encoded := EncodeBatchCursor(*r.NextCursor)
+		nextCursor = &encoded
+	}
+
+	return ArchiveOrdersResponse{
+		DryRun:     r.DryRun,
+		OlderThan:  r.OlderThan.Format("2006-01-02T15:04:05Z07:00"),
+		Batches:    r.Batches,
+		Scanned:    r.Scanned,
+		Archived:   r.Archived,
+		Done:       r.NextCursor == nil,
+		NextCursor: nextCursor,
+	}
+}
+
+// EncodeBatchCursor encodes a batch cursor as an opaque URL-safe string
+func EncodeBatchCursor(c order.BatchCursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeBatchCursor decodes a cursor produced by EncodeBatchCursor
+func DecodeBatchCursor(s string) (*order.BatchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	createdAtStr, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, errInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return &order.BatchCursor{CreatedAt: createdAt, ID: id}, nil
 }
@@ -0,0 +1,159 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/money"
+)
+
+func TestMetricsResponseEchoesAppliedFilters(t *testing.T) {
+	str := func(s string) *string { return &s }
+	delivered := order.StatusDelivered
+	onSite := order.SaleTypeOnSite
+
+	tests := []struct {
+		name        string
+		metrics     order.OrderMetrics
+		filters     order.OrderFilters
+		wantCount   string
+		wantFilters string
+	}{
+		{
+			name:        "zero matching orders",
+			filters:     order.OrderFilters{Status: &delivered, DateFrom: str("2024-05-01T00:00:00Z"), DateTo: str("2024-05-01T23:59:59Z")},
+			wantCount:   `"order_count":0`,
+			wantFilters: `"filters":{"date_from":"2024-05-01T00:00:00Z","date_to":"2024-05-01T23:59:59Z","status":"DELIVERED"}`,
+		},
+		{
+			name:        "single order",
+			metrics:     order.OrderMetrics{OrderCount: 1},
+			filters:     order.OrderFilters{SaleType: &onSite},
+			wantCount:   `"order_count":1`,
+			wantFilters: `"filters":{"sale_type":"ON_SITE"}`,
+		},
+		{
+			name:        "large window",
+			metrics:     order.OrderMetrics{OrderCount: 3_000_000_000, TotalSales: 9_000_000_000_000_000},
+			filters:     order.OrderFilters{DateFrom: str("2000-01-01T00:00:00Z"), DateTo: str("2030-12-31T23:59:59Z")},
+			wantCount:   `"order_count":3000000000`,
+			wantFilters: `"filters":{"date_from":"2000-01-01T00:00:00Z","date_to":"2030-12-31T23:59:59Z"}`,
+		},
+		{
+			name:        "unparseable date is not echoed",
+			filters:     order.OrderFilters{DateFrom: str("yesterday")},
+			wantCount:   `"order_count":0`,
+			wantFilters: `"filters":{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(ToMetricsResponse(&tt.metrics, tt.filters, money.Currency{Code: "COP"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{tt.wantCount, tt.wantFilters} {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("body misses %s: %s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestToOrderResponsesMatchesToOrderResponse(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 100} {
+		orders := mocks.SampleOrders(n)
+
+		perOrder := make([]OrderResponse, len(orders))
+		for i, o := range orders {
+			perOrder[i] = ToOrderResponse(o)
+		}
+
+		want, err := json.Marshal(perOrder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(ToOrderResponses(orders))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%d orders: bulk conversion differs from per-order conversion\n got: %s\nwant: %s", n, got, want)
+		}
+	}
+}
+
+func TestToOrderResponsesLinesAreIndependent(t *testing.T) {
+	responses := ToOrderResponses(mocks.SampleOrders(3))
+	next := responses[1].Products[0].ID
+
+	// Appending to one order's lines must not overwrite the next order's lines in the shared backing array
+	responses[0].Products = append(responses[0].Products, OrderProductResponse{ID: "appended"})
+	if responses[1].Products[0].ID != next {
+		t.Fatalf("append on order 0 overwrote order 1: %+v", responses[1].Products[0])
+	}
+}
+
+// BenchmarkToOrderResponses converts a full page (limit=100) the way GET /api/v1/orders does
+func BenchmarkToOrderResponses(b *testing.B) {
+	orders := mocks.SampleOrders(100)
+	b.ReportAllocs()
+	for b.Loop() {
+		ToOrderResponses(orders)
+	}
+}
+
+// BenchmarkToOrderResponseLoop is the per-order conversion ToOrderResponses replaced, kept as the baseline
+func BenchmarkToOrderResponseLoop(b *testing.B) {
+	orders := mocks.SampleOrders(100)
+	b.ReportAllocs()
+	for b.Loop() {
+		responses := make([]OrderResponse, len(orders))
+		for i, o := range orders {
+			responses[i] = ToOrderResponse(o)
+		}
+	}
+}
+
+// TestPublicResponsesHideInternalNotes checks every response served on public endpoints
+func TestPublicResponsesHideInternalNotes(t *testing.T) {
+	legacy := "legacy secret"
+	o := mocks.SampleOrders(1)[0]
+	o.Note = &legacy
+	o.Notes = []order.OrderNote{
+		{Text: "public hello", Visibility: order.NotePublic},
+		{Text: "internal secret", Visibility: order.NoteInternal},
+	}
+
+	public := map[string]any{
+		"track":                ToTrackResponse(o, false),
+		"track with notes":     ToTrackResponse(o, true),
+		"stream initial event": ToStatusEventResponse(order.StatusEvent{Code: o.Code, Status: o.Status}),
+		"stream status change": ToStatusEventResponse(order.StatusEvent{Code: o.Code, Status: o.Status, Previous: order.StatusCreated}),
+	}
+	for name, response := range public {
+		body, err := json.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(body), "secret") {
+			t.Errorf("%s response leaks an internal note: %s", name, body)
+		}
+	}
+
+	// The admin response shows every note with its visibility
+	body, err := json.Marshal(ToOrderResponse(o))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"text":"legacy secret"`, `"text":"internal secret"`, `"visibility":"INTERNAL"`, `"visibility":"PUBLIC"`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("admin response misses %s: %s", want, body)
+		}
+	}
+}
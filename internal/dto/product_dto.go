@@ -1,21 +1,55 @@
 package dto
 
-import "github.com/emerarteaga/products-api/internal/domain/product"
+import (
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+)
 
 // CreateProductRequest represents the request to create a product
 type CreateProductRequest struct {
-	CompanyID        string                  `json:"company_id" binding:"required"`
-	SalePointID      string                  `json:"sale_point_id" binding:"required"`
-	Name             string                  `json:"name" binding:"required,min=2,max=200"`
-	Description      string                  `json:"description" binding:"max=1000"`
-	Category         string                  `json:"category" binding:"required,min=2,max=100"`
-	Photos           []string                `json:"photos"`
-	PriceVariations  []PriceVariationRequest `json:"price_variations" binding:"required,min=1,dive"`
-	AvailableAddons  []AddonRequest          `json:"available_addons" binding:"dive"`
-	IsAddon          bool                    `json:"is_addon"`
-	IsAvailable      bool                    `json:"is_available"`
-	IsUnlimitedStock bool                    `json:"is_unlimited_stock"`
-	Stock            *int                    `json:"stock" binding:"omitempty,gte=0"`
+	CompanyID            string                       `json:"company_id" binding:"required"`
+	SalePointID          string                       `json:"sale_point_id" binding:"required"`
+	SKU                  *string                      `json:"sku" binding:"omitempty,sku"`
+	Barcode              *string                      `json:"barcode" binding:"omitempty,barcode"`
+	Name                 string                       `json:"name" binding:"required,min=2,max=200"`
+	Description          string                       `json:"description" binding:"max=1000"`
+	Category             string                       `json:"category" binding:"required,min=2,max=100"`
+	Photos               []string                     `json:"photos"`
+	PriceVariations      []PriceVariationRequest      `json:"price_variations" binding:"required,min=1,dive"`
+	AvailableAddons      []AddonRequest               `json:"available_addons" binding:"dive"`
+	QuickObservations    []string                     `json:"quick_observations" binding:"omitempty,max=10,dive,min=1,max=50"`
+	Tags                 []string                     `json:"tags" binding:"omitempty,max=20,dive,min=1,max=40"`
+	IsAddon              bool                         `json:"is_addon"`
+	IsAvailable          bool                         `json:"is_available"`
+	IsUnlimitedStock     bool                         `json:"is_unlimited_stock"`
+	Stock                *int                         `json:"stock" binding:"omitempty,gte=0"`
+	AvailabilitySchedule *AvailabilityScheduleRequest `json:"availability_schedule"` // Weekly ordering windows; none means any time
+}
+
+// AvailabilityScheduleRequest represents the weekly ordering windows of a product
+type AvailabilityScheduleRequest struct {
+	Timezone string                      `json:"timezone"`
+	Windows  []AvailabilityWindowRequest `json:"windows" binding:"max=50,dive"`
+}
+
+// AvailabilityWindowRequest represents one weekly window; an end before the start crosses midnight
+type AvailabilityWindowRequest struct {
+	Day   string `json:"day" binding:"required"`
+	Start string `json:"start" binding:"required"`
+	End   string `json:"end" binding:"required"`
+}
+
+// toAvailabilitySchedule converts the request schedule, if any, to the domain schedule
+func toAvailabilitySchedule(r *AvailabilityScheduleRequest) *product.AvailabilitySchedule {
+	if r == nil {
+		return nil
+	}
+	windows := make([]product.AvailabilityWindow, len(r.Windows))
+	for i, w := range r.Windows {
+		windows[i] = product.AvailabilityWindow{Day: w.Day, Start: w.Start, End: w.End}
+	}
+	return &product.AvailabilitySchedule{Timezone: r.Timezone, Windows: windows}
 }
 
 // PriceVariationRequest represents a price variation in the request
@@ -42,16 +76,22 @@ type AddonRequest struct {
 
 // UpdateProductRequest represents the request to update a product
 type UpdateProductRequest struct {
-	Name             *string                  `json:"name" binding:"omitempty,min=2,max=200"`
-	Description      *string                  `json:"description" binding:"omitempty,max=1000"`
-	Category         *string                  `json:"category" binding:"omitempty,min=2,max=100"`
-	Photos           *[]string                `json:"photos"`
-	PriceVariations  *[]PriceVariationRequest `json:"price_variations" binding:"omitempty,min=1,dive"`
-	AvailableAddons  *[]AddonRequest          `json:"available_addons" binding:"omitempty,dive"`
-	IsAddon          *bool                    `json:"is_addon"`
-	IsAvailable      *bool                    `json:"is_available"`
-	IsUnlimitedStock *bool                    `json:"is_unlimited_stock"`
-	Stock            **int                    `json:"stock" binding:"omitempty"`
+	SKU                  *string                      `json:"sku" binding:"omitempty,sku"`         // "" removes the SKU
+	Barcode              *string                      `json:"barcode" binding:"omitempty,barcode"` // "" removes the barcode
+	Name                 *string                      `json:"name" binding:"omitempty,min=2,max=200"`
+	Description          *string                      `json:"description" binding:"omitempty,max=1000"`
+	Category             *string                      `json:"category" binding:"omitempty,min=2,max=100"`
+	Photos               *[]string                    `json:"photos"`
+	PriceVariations      *[]PriceVariationRequest     `json:"price_variations" binding:"omitempty,min=1,dive"`
+	AvailableAddons      *[]AddonRequest              `json:"available_addons" binding:"omitempty,dive"`
+	QuickObservations    *[]string                    `json:"quick_observations" binding:"omitempty,max=10,dive,min=1,max=50"`
+	Tags                 *[]string                    `json:"tags" binding:"omitempty,max=20,dive,min=1,max=40"`
+	IsAddon              *bool                        `json:"is_addon"`
+	IsAvailable          *bool                        `json:"is_available"`
+	IsUnlimitedStock     *bool                        `json:"is_unlimited_stock"`
+	Stock                **int                        `json:"stock" binding:"omitempty"`
+	AvailabilitySchedule *AvailabilityScheduleRequest `json:"availability_schedule"`             // One without windows removes the schedule
+	Version              *int64                       `json:"version" binding:"omitempty,gte=0"` // Version the client edited; 409 if the product changed since
 }
 
 // ToCreateInput converts DTO to service input
@@ -93,32 +133,43 @@ func (r *CreateProductRequest) ToCreateInput() product.CreateInput {
 	}
 
 	return product.CreateInput{
-		CompanyID:        r.CompanyID,
-		SalePointID:      r.SalePointID,
-		Name:             r.Name,
-		Description:      r.Description,
-		Category:         r.Category,
-		Photos:           r.Photos,
-		PriceVariations:  priceVariations,
-		AvailableAddons:  availableAddons,
-		IsAddon:          r.IsAddon,
-		IsAvailable:      r.IsAvailable,
-		IsUnlimitedStock: r.IsUnlimitedStock,
-		Stock:            r.Stock,
+		CompanyID:            r.CompanyID,
+		SalePointID:          r.SalePointID,
+		SKU:                  r.SKU,
+		Barcode:              r.Barcode,
+		Name:                 r.Name,
+		Description:          r.Description,
+		Category:             r.Category,
+		Photos:               r.Photos,
+		PriceVariations:      priceVariations,
+		AvailableAddons:      availableAddons,
+		QuickObservations:    r.QuickObservations,
+		Tags:                 r.Tags,
+		IsAddon:              r.IsAddon,
+		IsAvailable:          r.IsAvailable,
+		IsUnlimitedStock:     r.IsUnlimitedStock,
+		Stock:                r.Stock,
+		AvailabilitySchedule: toAvailabilitySchedule(r.AvailabilitySchedule),
 	}
 }
 
 // ToUpdateInput converts DTO to service input
 func (r *UpdateProductRequest) ToUpdateInput() product.UpdateInput {
 	input := product.UpdateInput{
-		Name:             r.Name,
-		Description:      r.Description,
-		Category:         r.Category,
-		Photos:           r.Photos,
-		IsAddon:          r.IsAddon,
-		IsAvailable:      r.IsAvailable,
-		IsUnlimitedStock: r.IsUnlimitedStock,
-		Stock:            r.Stock,
+		SKU:                  r.SKU,
+		Barcode:              r.Barcode,
+		Name:                 r.Name,
+		Description:          r.Description,
+		Category:             r.Category,
+		Photos:               r.Photos,
+		QuickObservations:    r.QuickObservations,
+		Tags:                 r.Tags,
+		IsAddon:              r.IsAddon,
+		IsAvailable:          r.IsAvailable,
+		IsUnlimitedStock:     r.IsUnlimitedStock,
+		Stock:                r.Stock,
+		AvailabilitySchedule: toAvailabilitySchedule(r.AvailabilitySchedule),
+		ExpectedVersion:      r.Version,
 	}
 
 	// Convert price variations if provided
@@ -168,12 +219,38 @@ func (r *UpdateProductRequest) ToUpdateInput() product.UpdateInput {
 
 // ProductListResponse represents a simplified product for list views
 type ProductListResponse struct {
-	ID          string   `json:"id"`
-	Name        string   `json:"name"`
-	Photos      []string `json:"photos"`
-	Category    string   `json:"category"`
-	MinPrice    int64    `json:"min_price"` // Minimum price from variations
-	IsAvailable bool     `json:"is_available"`
+	ID                   string               `json:"id"`
+	SKU                  *string              `json:"sku,omitempty"`
+	Barcode              *string              `json:"barcode,omitempty"`
+	Name                 string               `json:"name"`
+	Photos               []string             `json:"photos"`
+	Category             string               `json:"category"`
+	Position             int                  `json:"position"`     // Display order within the category
+	MinPrice             int64                `json:"min_price"`    // Minimum price from variations
+	IsAvailable          bool                 `json:"is_available"` // Stored flag; see availability for what customers can order
+	Availability         AvailabilityResponse `json:"availability"`
+	IsCurrentlyAvailable bool                 `json:"is_currently_available"` // Whether it can be ordered now by the server's clock, schedule included
+	QuickObservations    []string             `json:"quick_observations"`
+	Tags                 []string             `json:"tags"`
+	DeletedAt            *time.Time           `json:"deleted_at,omitempty"` // Only listed with include_deleted=true
+	Version              int64                `json:"version"`              // Send back as If-Match or version to update safely
+}
+
+// AvailabilityResponse tells whether a product can be ordered and why
+type AvailabilityResponse struct {
+	Available bool                       `json:"available"`
+	Reason    product.AvailabilityReason `json:"reason"` // IN_STOCK, OUT_OF_STOCK, MANUALLY_DISABLED or OUTSIDE_SCHEDULE
+}
+
+// ProductResponse represents a product with its availability at the time of the request
+type ProductResponse struct {
+	*product.Product
+	IsCurrentlyAvailable bool `json:"is_currently_available"` // By the server's clock, schedule included
+}
+
+// ToProductResponse converts a product to response
+func ToProductResponse(p *product.Product) ProductResponse {
+	return ProductResponse{Product: p, IsCurrentlyAvailable: p.Availability().Available}
 }
 
 // ToListResponse converts a product to list response
@@ -188,13 +265,23 @@ func ToListResponse(p *product.Product) ProductListResponse {
 		}
 	}
 
+	availability := p.Availability()
 	return ProductListResponse{
-		ID:          p.ID,
-		Name:        p.Name,
-		Photos:      p.Photos,
-		Category:    p.Category,
-		MinPrice:    minPrice,
-		IsAvailable: p.IsAvailable,
+		ID:                   p.ID,
+		SKU:                  p.SKU,
+		Barcode:              p.Barcode,
+		Name:                 p.Name,
+		Photos:               p.Photos,
+		Category:             p.Category,
+		Position:             p.Position,
+		MinPrice:             minPrice,
+		IsAvailable:          p.IsAvailable,
+		Availability:         AvailabilityResponse{Available: availability.Available, Reason: availability.Reason},
+		IsCurrentlyAvailable: availability.Available,
+		QuickObservations:    p.QuickObservations,
+		Tags:                 p.Tags,
+		DeletedAt:            p.DeletedAt,
+		Version:              p.Version,
 	}
 }
 
@@ -206,3 +293,173 @@ func ToListResponses(products []*product.Product) []ProductListResponse {
 	}
 	return responses
 }
+
+// MenuSectionResponse is one category of a menu with its products in the simplified list view
+type MenuSectionResponse struct {
+	Category     string                `json:"category"`
+	ProductCount int                   `json:"product_count"` // Also counts the products past products_per_category
+	Products     []ProductListResponse `json:"products"`
+}
+
+// ToMenuResponses converts menu sections to responses
+func ToMenuResponses(sections []product.MenuSection) []MenuSectionResponse {
+	responses := make([]MenuSectionResponse, len(sections))
+	for i, s := range sections {
+		responses[i] = MenuSectionResponse{
+			Category:     s.Category,
+			ProductCount: s.ProductCount,
+			Products:     ToListResponses(s.Products),
+		}
+	}
+	return responses
+}
+
+// AdjustStockRequest changes a product's stock by delta or to an absolute value (exactly one of them)
+type AdjustStockRequest struct {
+	Delta *int `json:"delta"`
+	Set   *int `json:"set" binding:"omitempty,gte=0"`
+}
+
+// ToStockAdjustment converts the request to service input
+func (r *AdjustStockRequest) ToStockAdjustment() product.StockAdjustment {
+	return product.StockAdjustment{Delta: r.Delta, Set: r.Set}
+}
+
+// BatchProductsRequest lists the products to fetch at once
+type BatchProductsRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+// ToBatchInput converts the request to service input
+func (r *BatchProductsRequest) ToBatchInput(onlyAvailable bool) product.BatchInput {
+	return product.BatchInput{IDs: r.IDs, OnlyAvailable: onlyAvailable}
+}
+
+// BatchProductsResponse holds the products found, in request order, and the IDs that did not resolve
+type BatchProductsResponse struct {
+	Products []*product.Product `json:"products"`
+	Missing  []string           `json:"missing"`
+}
+
+// ToBatchProductsResponse converts a batch fetch result to response
+func ToBatchProductsResponse(r *product.BatchResult) BatchProductsResponse {
+	return BatchProductsResponse{Products: r.Products, Missing: r.Missing}
+}
+
+// BulkDeleteProductsRequest selects the products to delete: ids, or a sale point with an optional category
+type BulkDeleteProductsRequest struct {
+	IDs         []string `json:"ids" binding:"omitempty,dive,required"`
+	SalePointID string   `json:"sale_point_id"`
+	Category    *string  `json:"category"`
+}
+
+// ToBulkDeleteInput converts the request to service input
+func (r *BulkDeleteProductsRequest) ToBulkDeleteInput(force, hard bool) product.BulkDeleteInput {
+	return product.BulkDeleteInput{
+		IDs:         r.IDs,
+		SalePointID: r.SalePointID,
+		Category:    r.Category,
+		Force:       force,
+		Hard:        hard,
+	}
+}
+
+// BulkAvailabilityRequest marks products as available or unavailable: ids, or a sale point's category
+type BulkAvailabilityRequest struct {
+	IDs         []string `json:"ids" binding:"omitempty,max=500,dive,required"`
+	SalePointID string   `json:"sale_point_id"`
+	Category    *string  `json:"category"`
+	IsAvailable *bool    `json:"is_available" binding:"required"`
+}
+
+// ToBulkAvailabilityInput converts the request to service input
+func (r *BulkAvailabilityRequest) ToBulkAvailabilityInput() product.BulkAvailabilityInput {
+	return product.BulkAvailabilityInput{
+		AvailabilitySelector: product.AvailabilitySelector{
+			IDs:         r.IDs,
+			SalePointID: r.SalePointID,
+			Category:    r.Category,
+		},
+		IsAvailable: *r.IsAvailable,
+	}
+}
+
+// BulkAvailabilityResponse reports the outcome of a bulk availability update
+type BulkAvailabilityResponse struct {
+	Matched  int64 `json:"matched"`  // Live products selected
+	Modified int64 `json:"modified"` // Products whose availability changed
+}
+
+// ToBulkAvailabilityResponse converts a bulk availability result to response
+func ToBulkAvailabilityResponse(r *product.BulkAvailabilityResult) BulkAvailabilityResponse {
+	return BulkAvailabilityResponse{Matched: r.Matched, Modified: r.Modified}
+}
+
+// ReorderProductsRequest sets the display order of a sale point's category
+type ReorderProductsRequest struct {
+	SalePointID string   `json:"sale_point_id" binding:"required"`
+	Category    string   `json:"category" binding:"required"`
+	OrderedIDs  []string `json:"ordered_ids" binding:"required,min=1,max=500,dive,required"`
+}
+
+// ToReorderInput converts the request to service input
+func (r *ReorderProductsRequest) ToReorderInput() product.ReorderInput {
+	return product.ReorderInput{
+		SalePointID: r.SalePointID,
+		Category:    r.Category,
+		OrderedIDs:  r.OrderedIDs,
+	}
+}
+
+// CopyProductsRequest copies the products of a sale point into another
+type CopyProductsRequest struct {
+	FromSalePointID string `json:"from_sale_point_id" binding:"required"`
+	ToSalePointID   string `json:"to_sale_point_id" binding:"required"`
+	Overwrite       bool   `json:"overwrite"`
+}
+
+// ToCopyInput converts the request to service input
+func (r *CopyProductsRequest) ToCopyInput() product.CopyInput {
+	return product.CopyInput{
+		FromSalePointID: r.FromSalePointID,
+		ToSalePointID:   r.ToSalePointID,
+		Overwrite:       r.Overwrite,
+	}
+}
+
+// RenameCategoryRequest renames a category on the products of a company or one of its sale points
+type RenameCategoryRequest struct {
+	CompanyID   string `json:"company_id" binding:"required"`
+	SalePointID string `json:"sale_point_id"`
+	From        string `json:"from" binding:"required"`
+	To          string `json:"to" binding:"required,min=2,max=100"`
+}
+
+// ToCategoryRenameInput converts the request to service input
+func (r *RenameCategoryRequest) ToCategoryRenameInput(merge bool) product.CategoryRenameInput {
+	return product.CategoryRenameInput{
+		CompanyID:   r.CompanyID,
+		SalePointID: r.SalePointID,
+		From:        r.From,
+		To:          r.To,
+		Merge:       merge,
+	}
+}
+
+// BulkDeleteProductsResponse reports the outcome of a bulk delete
+type BulkDeleteProductsResponse struct {
+	Deleted              int64    `json:"deleted"`
+	Skipped              int64    `json:"skipped"`    // Selected products that no longer existed or were already deleted
+	Referenced           int      `json:"referenced"` // Selected products referenced by recent orders
+	ReferencedProductIDs []string `json:"referenced_product_ids"`
+}
+
+// ToBulkDeleteProductsResponse converts a bulk delete result to response
+func ToBulkDeleteProductsResponse(r *product.BulkDeleteResult) BulkDeleteProductsResponse {
+	return BulkDeleteProductsResponse{
+		Deleted:              r.Deleted,
+		Skipped:              r.Skipped,
+		Referenced:           len(r.Referenced),
+		ReferencedProductIDs: r.Referenced,
+	}
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+)
+
+func TestListResponseCarriesAvailabilityReason(t *testing.T) {
+	zero := 0
+	tests := []struct {
+		name    string
+		product product.Product
+		want    string
+	}{
+		{"in stock", product.Product{IsAvailable: true, IsUnlimitedStock: true}, `"availability":{"available":true,"reason":"IN_STOCK"}`},
+		{"sold out", product.Product{IsAvailable: true, Stock: &zero}, `"availability":{"available":false,"reason":"OUT_OF_STOCK"}`},
+		{"disabled", product.Product{IsUnlimitedStock: true}, `"availability":{"available":false,"reason":"MANUALLY_DISABLED"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(ToListResponse(&tt.product))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(body), tt.want) {
+				t.Errorf("list response misses %s: %s", tt.want, body)
+			}
+		})
+	}
+}
@@ -1,8 +1,65 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	ErrNotFound  = errors.New("resource not found")
 	ErrInvalidID = errors.New("invalid ID format")
 )
+
+// DomainError wraps a domain sentinel error with the context that caused it.
+// It unwraps to the sentinel, so errors.Is keeps matching it.
+type DomainError struct {
+	Err   error       // Sentinel error, e.g. order.ErrInvalidProductQuantity
+	Field string      // Field path, e.g. "products[3].quantity"
+	Index *int        // Index of the offending item when the field belongs to a list
+	Value interface{} // Offending value, if useful to the client
+}
+
+// NewDomainError creates a DomainError for a single field
+func NewDomainError(err error, field string, value interface{}) *DomainError {
+	return &DomainError{Err: err, Field: field, Value: value}
+}
+
+// NewIndexedDomainError creates a DomainError for a field of the item at index in a list
+func NewIndexedDomainError(err error, field string, index int, value interface{}) *DomainError {
+	return &DomainError{Err: err, Field: field, Index: &index, Value: value}
+}
+
+// Error implements the error interface
+func (e *DomainError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Err.Error())
+}
+
+// Unwrap returns the wrapped sentinel error
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// DomainErrors groups several field errors reported at once
+type DomainErrors []*DomainError
+
+// Error implements the error interface
+func (e DomainErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the grouped errors, so errors.Is and errors.As match any of them
+func (e DomainErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
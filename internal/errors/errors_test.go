@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errSentinel = errors.New("quantity must be positive")
+
+func TestDomainError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *DomainError
+		wantMsg string
+	}{
+		{"without field", NewDomainError(errSentinel, "", nil), "quantity must be positive"},
+		{"single field", NewDomainError(errSentinel, "quantity", 0), "quantity: quantity must be positive"},
+		{"indexed field", NewIndexedDomainError(errSentinel, "products[3].quantity", 3, -1), "products[3].quantity: quantity must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.wantMsg {
+				t.Errorf("Error() = %q, want %q", got, tt.wantMsg)
+			}
+
+			wrapped := fmt.Errorf("validation error: %w", tt.err)
+			if !errors.Is(wrapped, errSentinel) {
+				t.Error("errors.Is does not match the sentinel through the wrapper")
+			}
+			var domainErr *DomainError
+			if !errors.As(wrapped, &domainErr) || domainErr != tt.err {
+				t.Error("errors.As does not find the DomainError")
+			}
+		})
+	}
+}
+
+func TestIndexedDomainErrorKeepsIndex(t *testing.T) {
+	err := NewIndexedDomainError(errSentinel, "products[3].quantity", 3, -1)
+	if err.Index == nil || *err.Index != 3 {
+		t.Fatalf("index = %v, want 3", err.Index)
+	}
+	if err.Value != -1 {
+		t.Errorf("value = %v, want -1", err.Value)
+	}
+	if NewDomainError(errSentinel, "quantity", nil).Index != nil {
+		t.Error("a single field error has an index")
+	}
+}
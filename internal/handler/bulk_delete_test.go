@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestBulkDeleteProducts(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		anonymous  bool
+		body       string
+		result     *product.BulkDeleteResult
+		err        error
+		wantStatus int
+		wantForce  bool
+		wantHard   bool
+		wantBody   []string
+	}{
+		{
+			name:       "deleted",
+			body:       `{"ids":["p1","p2","p3"]}`,
+			result:     &product.BulkDeleteResult{Deleted: 2, Skipped: 1, Referenced: []string{}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{`"deleted":2`, `"skipped":1`, `"referenced":0`},
+		},
+		{
+			name:       "referenced products conflict",
+			body:       `{"sale_point_id":"sp1"}`,
+			result:     &product.BulkDeleteResult{Referenced: []string{"p2"}},
+			err:        product.ErrProductsReferenced,
+			wantStatus: http.StatusConflict,
+			wantBody:   []string{`"referenced":1`, `"referenced_product_ids":["p2"]`, "force=true"},
+		},
+		{
+			name:       "force",
+			query:      "?force=true",
+			body:       `{"sale_point_id":"sp1","category":"Tacos"}`,
+			result:     &product.BulkDeleteResult{Deleted: 3, Referenced: []string{"p2"}},
+			wantStatus: http.StatusOK,
+			wantForce:  true,
+			wantBody:   []string{`"deleted":3`, `"referenced_product_ids":["p2"]`},
+		},
+		{
+			name:       "anonymous force is ignored",
+			query:      "?force=true",
+			anonymous:  true,
+			body:       `{"sale_point_id":"sp1"}`,
+			result:     &product.BulkDeleteResult{Referenced: []string{"p2"}},
+			err:        product.ErrProductsReferenced,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "products in open orders",
+			query:      "?hard=true",
+			body:       `{"ids":["p1"]}`,
+			err:        fmt.Errorf("%w: 2 open orders contain p1", product.ErrProductInUse),
+			wantStatus: http.StatusConflict,
+			wantHard:   true,
+			wantBody:   []string{"Open orders contain some of the products"},
+		},
+		{
+			name:       "hard delete",
+			query:      "?hard=true",
+			body:       `{"ids":["p1"]}`,
+			result:     &product.BulkDeleteResult{Deleted: 1, Referenced: []string{}},
+			wantStatus: http.StatusOK,
+			wantHard:   true,
+		},
+		{
+			name:       "anonymous hard delete is soft",
+			query:      "?hard=true",
+			anonymous:  true,
+			body:       `{"ids":["p1"]}`,
+			result:     &product.BulkDeleteResult{Deleted: 1, Referenced: []string{}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "empty selector",
+			body:       `{}`,
+			err:        product.ErrEmptyBulkDelete,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Invalid product selection"},
+		},
+		{
+			name:       "ids and selector",
+			body:       `{"ids":["p1"],"sale_point_id":"sp1"}`,
+			err:        product.ErrAmbiguousBulkDelete,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too many products",
+			body:       `{"sale_point_id":"sp1"}`,
+			err:        product.ErrBulkDeleteTooLarge,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "empty id rejected by binding",
+			body:       `{"ids":["p1",""]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *product.BulkDeleteInput
+			service := &mocks.ProductService{
+				BulkDeleteFunc: func(ctx context.Context, input product.BulkDeleteInput) (*product.BulkDeleteResult, error) {
+					got = &input
+					return tt.result, tt.err
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodPost, "/api/v1/products/bulk-delete"+tt.query, tt.body, !tt.anonymous)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.result == nil && tt.err == nil && got != nil {
+				t.Fatalf("service called with %+v", *got)
+			}
+			if got != nil && (got.Force != tt.wantForce || got.Hard != tt.wantHard) {
+				t.Errorf("force = %v, hard = %v, want %v and %v", got.Force, got.Hard, tt.wantForce, tt.wantHard)
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// newCategoryRouter serves the category listing with its production path
+func newCategoryRouter(service product.ServiceAPI) *gin.Engine {
+	h := NewProductHandler(service)
+	router := gin.New()
+	router.GET("/api/v1/categories/sale-point/:sale_point_id", h.GetCategoriesBySalePointID)
+	return router
+}
+
+// categorySummaries are the product categories of a sale point, Postres without available products
+var categorySummaries = []product.CategorySummary{
+	{Name: "Bebidas", ProductCount: 3, AvailableCount: 3},
+	{Name: "Platos", ProductCount: 5, AvailableCount: 4},
+	{Name: "Postres", ProductCount: 2, AvailableCount: 0},
+}
+
+func TestListProductCategories(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		wantStatus    int
+		wantAvailable bool
+		wantAll       bool
+		wantBody      []string
+	}{
+		{"summaries page", "?limit=2", http.StatusOK, false, false, []string{`"name":"Bebidas","product_count":3,"available_count":3`, `"total_items":3`, `"page_size":2`}},
+		{"only with available", "?only_with_available=true", http.StatusOK, true, false, []string{`"total_items":2`}},
+		{"plain names", "?format=plain", http.StatusOK, false, true, []string{`"categories":["Bebidas","Platos","Postres"]`}},
+		{"plain names only with available", "?format=plain&only_with_available=true", http.StatusOK, true, true, []string{`"categories":["Bebidas","Platos"]`}},
+		{"invalid limit", "?limit=-", http.StatusBadRequest, false, false, []string{`"field":"limit"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *product.CategoryFilters
+			products := &mocks.ProductService{
+				SalePointPageLimitsFunc: func() util.PageLimits { return util.PageLimits{DefaultLimit: 20, MaxLimit: 100} },
+				GetCategoriesBySalePointIDFunc: func(ctx context.Context, salePointID string, filters product.CategoryFilters) ([]product.CategorySummary, int64, error) {
+					got = &filters
+					var rows []product.CategorySummary
+					for _, s := range categorySummaries {
+						if !filters.OnlyWithAvailable || s.AvailableCount > 0 {
+							rows = append(rows, s)
+						}
+					}
+					total := int64(len(rows))
+					if !filters.All {
+						rows = rows[:min(filters.Limit, len(rows))]
+					}
+					return rows, total, nil
+				},
+			}
+
+			w := serveJSON(newCategoryRouter(products), http.MethodGet, "/api/v1/categories/sale-point/sp-1"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+			if got != nil && (got.OnlyWithAvailable != tt.wantAvailable || got.All != tt.wantAll) {
+				t.Errorf("filters = %+v, want only with available %v, all %v", got, tt.wantAvailable, tt.wantAll)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/emerarteaga/products-api/internal/domain/category"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryHandler handles HTTP requests for category documents
+type CategoryHandler struct {
+	service category.ServiceAPI
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(service category.ServiceAPI) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// Create handles POST /api/v1/categories
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req dto.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	created, err := h.service.Create(c.Request.Context(), req.ToCreateInput())
+	if err != nil {
+		statusCode := h.mapErrorToStatusCode(err)
+		logger.Error("failed to create category", "error", err)
+		respondError(c, statusCode, err, "Failed to create category")
+		return
+	}
+
+	logger.Info("category created", "category_id", created.ID, "sale_point_id", created.SalePointID)
+	response.Success(c, http.StatusCreated, created, "Category created successfully")
+}
+
+// GetByID handles GET /api/v1/categories/:id
+func (h *CategoryHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	found, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, category.ErrCategoryNotFound) {
+			response.Error(c, http.StatusNotFound, err, "Category not found")
+			return
+		}
+		logger.Error("failed to get category", "error", err, "category_id", id)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get category")
+		return
+	}
+
+	response.Success(c, http.StatusOK, found, "")
+}
+
+// List handles GET /api/v1/categories?sale_point_id=...&only_active=true
+// Sale points without categories list the distinct categories of their products, with source "products".
+func (h *CategoryHandler) List(c *gin.Context) {
+	salePointID := c.Query("sale_point_id")
+	filters := category.ListFilters{OnlyActive: c.Query("only_active") == "true"}
+
+	listing, err := h.service.List(c.Request.Context(), salePointID, filters)
+	if err != nil {
+		if isDomainError(err) {
+			respondError(c, http.StatusBadRequest, err, "Invalid filter parameters")
+			return
+		}
+		logger.Error("failed to list categories", "error", err, "sale_point_id", salePointID)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get categories")
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.ToCategoryListResponse(listing), "")
+}
+
+// Update handles PUT /api/v1/categories/:id
+func (h *CategoryHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	updated, err := h.service.Update(c.Request.Context(), id, req.ToUpdateInput())
+	if err != nil {
+		statusCode := h.mapErrorToStatusCode(err)
+		logger.Error("failed to update category", "error", err, "category_id", id)
+		respondError(c, statusCode, err, "Failed to update category")
+		return
+	}
+
+	logger.Info("category updated", "category_id", id)
+	response.Success(c, http.StatusOK, updated, "Category updated successfully")
+}
+
+// Delete handles DELETE /api/v1/categories/:id
+// Products keep the name of a deleted category.
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, category.ErrCategoryNotFound) {
+			response.Error(c, http.StatusNotFound, err, "Category not found")
+			return
+		}
+		logger.Error("failed to delete category", "error", err, "category_id", id)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to delete category")
+		return
+	}
+
+	logger.Info("category deleted", "category_id", id)
+	response.Success(c, http.StatusOK, nil, "Category deleted successfully")
+}
+
+// mapErrorToStatusCode maps domain errors to HTTP status codes
+func (h *CategoryHandler) mapErrorToStatusCode(err error) int {
+	switch {
+	case errors.Is(err, category.ErrCategoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, category.ErrDuplicateCategory):
+		return http.StatusConflict
+	case isDomainError(err):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/category"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/gin-gonic/gin"
+)
+
+func newCategoryDocumentRouter(service category.ServiceAPI) *gin.Engine {
+	h := NewCategoryHandler(service)
+	router := gin.New()
+	categories := router.Group("/api/v1/categories")
+	categories.POST("", h.Create)
+	categories.GET("", h.List)
+	categories.GET("/:id", h.GetByID)
+	categories.PUT("/:id", h.Update)
+	categories.DELETE("/:id", h.Delete)
+	return router
+}
+
+func TestCreateCategoryHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"valid", `{"company_id": "company-1", "sale_point_id": "sale-point-1", "name": "Pizzas", "position": 1}`, nil, http.StatusCreated},
+		{"missing name", `{"company_id": "company-1", "sale_point_id": "sale-point-1"}`, nil, http.StatusBadRequest},
+		{"negative position", `{"company_id": "company-1", "sale_point_id": "sale-point-1", "name": "Pizzas", "position": -1}`, nil, http.StatusBadRequest},
+		{"invalid image url", `{"company_id": "company-1", "sale_point_id": "sale-point-1", "name": "Pizzas", "image_url": "pizzas.png"}`, nil, http.StatusBadRequest},
+		{"duplicate", `{"company_id": "company-1", "sale_point_id": "sale-point-1", "name": "Pizzas"}`, fmt.Errorf("failed to create category: %w", category.ErrDuplicateCategory), http.StatusConflict},
+		{"image host not allowed", `{"company_id": "company-1", "sale_point_id": "sale-point-1", "name": "Pizzas", "image_url": "https://evil.example.org/a.png"}`,
+			fmt.Errorf("validation error: %w", apperrors.NewDomainError(category.ErrImageHostNotAllowed, "image_url", nil)), http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.CategoryService{
+				CreateFunc: func(ctx context.Context, input category.CreateInput) (*category.Category, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return category.NewCategory(input.CompanyID, input.SalePointID, input.Name), nil
+				},
+			}
+
+			w := serveJSON(newCategoryDocumentRouter(service), http.MethodPost, "/api/v1/categories", tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestListCategoriesHandler(t *testing.T) {
+	var gotSalePoint string
+	var gotFilters category.ListFilters
+	service := &mocks.CategoryService{
+		ListFunc: func(ctx context.Context, salePointID string, filters category.ListFilters) (*category.Listing, error) {
+			gotSalePoint, gotFilters = salePointID, filters
+			if salePointID == "" {
+				return nil, apperrors.NewDomainError(category.ErrInvalidSalePointID, "sale_point_id", nil)
+			}
+			return &category.Listing{
+				Categories: []*category.Category{{SalePointID: salePointID, Name: "Burgers", IsActive: true}},
+				Source:     category.SourceProducts,
+			}, nil
+		},
+	}
+	router := newCategoryDocumentRouter(service)
+
+	w := serveJSON(router, http.MethodGet, "/api/v1/categories?sale_point_id=sale-point-1&only_active=true", "", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if gotSalePoint != "sale-point-1" || !gotFilters.OnlyActive {
+		t.Errorf("listed %q with %+v, want sale-point-1 with only active", gotSalePoint, gotFilters)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"source":"products"`) || !strings.Contains(body, `"name":"Burgers"`) {
+		t.Errorf("body = %s, want the fallback categories", body)
+	}
+
+	if w := serveJSON(router, http.MethodGet, "/api/v1/categories", "", false); w.Code != http.StatusBadRequest {
+		t.Errorf("missing sale point status = %d, want 400: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateAndDeleteCategoryHandler(t *testing.T) {
+	service := &mocks.CategoryService{
+		UpdateFunc: func(ctx context.Context, id string, input category.UpdateInput) (*category.Category, error) {
+			if id != "c1" {
+				return nil, category.ErrCategoryNotFound
+			}
+			c := category.NewCategory("company-1", "sale-point-1", "Pizzas")
+			if input.ImageURL != nil {
+				c.ImageURL = *input.ImageURL
+			}
+			return c, nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			if id != "c1" {
+				return category.ErrCategoryNotFound
+			}
+			return nil
+		},
+	}
+	router := newCategoryDocumentRouter(service)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"update", http.MethodPut, "/api/v1/categories/c1", `{"position": 3, "is_active": false}`, http.StatusOK},
+		{"remove image", http.MethodPut, "/api/v1/categories/c1", `{"image_url": ""}`, http.StatusOK},
+		{"invalid image", http.MethodPut, "/api/v1/categories/c1", `{"image_url": "pizzas.png"}`, http.StatusBadRequest},
+		{"update missing", http.MethodPut, "/api/v1/categories/c2", `{"position": 3}`, http.StatusNotFound},
+		{"delete", http.MethodDelete, "/api/v1/categories/c1", "", http.StatusOK},
+		{"delete missing", http.MethodDelete, "/api/v1/categories/c2", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveJSON(router, tt.method, tt.target, tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateProductUnknownCategory(t *testing.T) {
+	service := &mocks.ProductService{
+		CreateFunc: func(ctx context.Context, input product.CreateInput) (*product.Product, error) {
+			return nil, fmt.Errorf("validation error: %w", apperrors.NewDomainError(product.ErrUnknownCategory, "category", input.Category))
+		},
+	}
+	body := `{
+		"company_id": "company-1", "sale_point_id": "sale-point-1",
+		"name": "Pizza", "category": "Burgers", "is_unlimited_stock": true,
+		"price_variations": [{"type": "small", "price": 1000}]
+	}`
+
+	w := serveJSON(newProductRouter(service), http.MethodPost, "/api/v1/products", body, false)
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"field":"category"`) {
+		t.Errorf("status = %d, want 422 on the category field: %s", w.Code, w.Body.String())
+	}
+}
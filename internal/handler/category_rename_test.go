@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestRenameCategoryHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		err        error
+		wantStatus int
+		wantMerge  bool
+		wantBody   string
+	}{
+		{
+			name:       "rename",
+			target:     "/api/v1/categories/rename",
+			body:       `{"company_id": "c1", "from": "Bebidas", "to": "Drinks"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"renamed":4`,
+		},
+		{
+			name:       "case collision",
+			target:     "/api/v1/categories/rename",
+			body:       `{"company_id": "c1", "from": "Bebidas", "to": "Postres"}`,
+			err:        product.ErrCategoryCaseConflict,
+			wantStatus: http.StatusConflict,
+			wantBody:   `"merged":["postres"]`,
+		},
+		{
+			name:       "merge",
+			target:     "/api/v1/categories/rename?merge=true",
+			body:       `{"company_id": "c1", "sale_point_id": "sp1", "from": "Bebidas", "to": "Postres"}`,
+			wantStatus: http.StatusOK,
+			wantMerge:  true,
+		},
+		{
+			name:       "missing target",
+			target:     "/api/v1/categories/rename",
+			body:       `{"company_id": "c1", "from": "Bebidas"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got product.CategoryRenameInput
+			service := &mocks.ProductService{
+				RenameCategoryFunc: func(ctx context.Context, input product.CategoryRenameInput) (*product.CategoryRenameResult, error) {
+					got = input
+					if tt.err != nil {
+						return &product.CategoryRenameResult{Merged: []string{"postres"}}, fmt.Errorf("rename: %w", tt.err)
+					}
+					return &product.CategoryRenameResult{Renamed: 4, Merged: []string{}}, nil
+				},
+			}
+			router := newProductRouter(service)
+			router.POST("/api/v1/categories/rename", NewProductHandler(service).RenameCategory)
+
+			w := serveJSON(router, http.MethodPost, tt.target, tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got.Merge != tt.wantMerge {
+				t.Errorf("merge = %v, want %v", got.Merge, tt.wantMerge)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
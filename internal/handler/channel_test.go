@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateChannel(t *testing.T) {
+	const base = `"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+		"products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]`
+
+	tests := []struct {
+		name        string
+		bodyChannel string
+		header      string
+		wantStatus  int
+		wantChannel order.Channel
+	}{
+		{"omitted is left to the service default", "", "", http.StatusCreated, ""},
+		{"from the body", "WHATSAPP", "", http.StatusCreated, order.ChannelWhatsApp},
+		{"header fallback", "", "pos", http.StatusCreated, order.ChannelPOS},
+		{"body wins over the header", "WEB", "POS", http.StatusCreated, order.ChannelWeb},
+		{"unknown in the body", "FAX", "", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.Channel
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					got = &input.Channel
+					return order.NewOrder(order.SaleTypeOnSite, nil), nil
+				},
+			}
+
+			body := "{" + base
+			if tt.bodyChannel != "" {
+				body += `, "channel": "` + tt.bodyChannel + `"`
+			}
+			body += "}"
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.header != "" {
+				req.Header.Set("X-Channel", tt.header)
+			}
+			w := httptest.NewRecorder()
+			newOrderRouter(service).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				if got != nil {
+					t.Error("rejected request reached the service")
+				}
+				return
+			}
+			if *got != tt.wantChannel {
+				t.Errorf("channel = %q, want %q", *got, tt.wantChannel)
+			}
+		})
+	}
+}
+
+func TestGetAllChannelFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       order.Channel
+	}{
+		{"known channel", "?channel=WHATSAPP", http.StatusOK, order.ChannelWhatsApp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.Channel
+			service := &mocks.OrderService{
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					got = filters.Channel
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && (got == nil || *got != tt.want) {
+				t.Errorf("channel filter = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
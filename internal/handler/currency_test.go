@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/money"
+)
+
+func TestResponsesCarryTheCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency money.Currency
+		want     string
+	}{
+		{"pesos", money.Currency{Code: "COP", MinorUnits: 0}, `"currency":{"code":"COP","minor_units":0}`},
+		{"dollars", money.Currency{Code: "USD", MinorUnits: 2}, `"currency":{"code":"USD","minor_units":2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				CurrencyFunc: func() money.Currency { return tt.currency },
+				GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+					return &order.OrderMetrics{}, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/info", NewOrderHandler(service).GetInfo)
+
+			for _, target := range []string{"/api/v1/info", "/api/v1/orders/metrics"} {
+				w := serveJSON(router, http.MethodGet, target, "", false)
+				if w.Code != http.StatusOK {
+					t.Fatalf("%s: status = %d, body %s", target, w.Code, w.Body.String())
+				}
+				if !strings.Contains(w.Body.String(), tt.want) {
+					t.Errorf("%s: body misses %s: %s", target, tt.want, w.Body.String())
+				}
+			}
+		})
+	}
+}
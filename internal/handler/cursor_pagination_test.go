@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// pageMeta decodes the meta of a paginated response, whichever its shape
+type pageMeta struct {
+	Meta map[string]json.RawMessage `json:"meta"`
+}
+
+func decodeMeta(t *testing.T, body []byte) map[string]json.RawMessage {
+	t.Helper()
+	var page pageMeta
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatal(err)
+	}
+	return page.Meta
+}
+
+func sampleProducts(n int) []*product.Product {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	products := make([]*product.Product, n)
+	for i := range products {
+		products[i] = &product.Product{ID: fmt.Sprintf("p%d", i), Name: "Arepa", CreatedAt: start.Add(-time.Duration(i) * time.Minute)}
+	}
+	return products
+}
+
+func TestProductListCursorMeta(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		returned   int
+		wantCursor *util.PageCursor // Cursor passed to the service; nil for offset pagination
+		wantNext   bool
+		wantOffset bool // Offset meta (current_page) instead of cursor meta
+	}{
+		{"offset pagination by default", "?limit=2", 2, nil, false, true},
+		{"first cursor page", "?limit=2&cursor=", 2, &util.PageCursor{}, true, false},
+		{"next cursor page", "?limit=2&cursor=" + util.PageCursor{Field: "created_at", Value: "2024-06-01T12:00:00Z", ID: "p1"}.Encode(), 2,
+			&util.PageCursor{Field: "created_at", Value: "2024-06-01T12:00:00Z", ID: "p1"}, true, false},
+		{"last cursor page", "?limit=2&cursor=", 1, &util.PageCursor{}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got product.ProductFilters
+			service := &mocks.ProductService{
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					got = filters
+					return sampleProducts(tt.returned), 10, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/c1"+tt.query, "", true)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if (got.Cursor == nil) != (tt.wantCursor == nil) || (got.Cursor != nil && *got.Cursor != *tt.wantCursor) {
+				t.Errorf("cursor = %v, want %v", got.Cursor, tt.wantCursor)
+			}
+
+			meta := decodeMeta(t, w.Body.Bytes())
+			if _, ok := meta["current_page"]; ok != tt.wantOffset {
+				t.Errorf("meta = %s, want offset meta %v", w.Body.String(), tt.wantOffset)
+			}
+			if tt.wantOffset {
+				return
+			}
+			var next *string
+			if err := json.Unmarshal(meta["next_cursor"], &next); err != nil {
+				t.Fatal(err)
+			}
+			if (next != nil) != tt.wantNext {
+				t.Fatalf("next_cursor = %v, want one %v", next, tt.wantNext)
+			}
+			if next != nil {
+				cursor, err := util.DecodePageCursor(*next)
+				if err != nil || cursor.ID != "p1" {
+					t.Errorf("next cursor = %+v, %v, want the last product p1", cursor, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCursorRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{"garbled cursor", "?cursor=%25%25", nil},
+		{"cursor the repository refuses", "?cursor=", fmt.Errorf("%w: search results are ranked", util.ErrInvalidCursor)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.ProductService{
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					return nil, 0, tt.err
+				},
+			}
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/c1"+tt.query, "", true)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400, body %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOrderListCursor(t *testing.T) {
+	var got order.OrderFilters
+	service := &mocks.OrderService{
+		GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+			got = filters
+			return mocks.SampleOrders(3), -1, nil
+		},
+	}
+
+	w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders?limit=3&sort=total&order=asc&cursor=", "", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	if got.Cursor == nil || !got.Cursor.IsStart() {
+		t.Fatalf("cursor = %v, want the start", got.Cursor)
+	}
+
+	var next string
+	if err := json.Unmarshal(decodeMeta(t, w.Body.Bytes())["next_cursor"], &next); err != nil {
+		t.Fatal(err)
+	}
+	last := mocks.SampleOrders(3)[2]
+	if want := last.PageCursor(order.SortFieldTotal).Encode(); next != want {
+		t.Errorf("next_cursor = %s, want the total and ID of the last order", next)
+	}
+
+	// The next page passes the cursor back as is
+	w = serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders?limit=3&sort=total&order=asc&cursor="+url.QueryEscape(next), "", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	if got.Cursor == nil || got.Cursor.ID != last.ID || got.Cursor.Field != "total" {
+		t.Errorf("cursor = %+v, want the last order by total", got.Cursor)
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCustomerEdit(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantBody   string
+		wantCalled bool
+	}{
+		{"address", `{"shipping_address": "Calle 10 # 20-30"}`, nil, http.StatusOK, `"success":true`, true},
+		{"note", `{"note": "No onions"}`, nil, http.StatusOK, `"success":true`, true},
+		{"field outside the whitelist", `{"note": "x", "total": 1, "status": "DELIVERED"}`, nil, http.StatusForbidden, "status, total", false},
+		{"not json", `note=x`, nil, http.StatusBadRequest, `"success":false`, false},
+		{"too long", `{"note": "` + strings.Repeat("a", 501) + `"}`, nil, http.StatusBadRequest, `"success":false`, false},
+		{"disabled", `{"note": "x"}`, order.ErrCustomerEditDisabled, http.StatusForbidden, "Customer edits are disabled", true},
+		{"nothing to edit", `{}`, order.ErrNoCustomerEditFields, http.StatusBadRequest, "Nothing to edit", true},
+		{"window expired", `{"note": "x"}`, order.ErrCustomerEditWindowExpired, http.StatusConflict, "contact the store", true},
+		{"order moved on", `{"note": "x"}`, fmt.Errorf("%w: order is VERIFIED", order.ErrCustomerEditNotAllowed), http.StatusConflict, "contact the store", true},
+		{"unknown code", `{"note": "x"}`, order.ErrOrderNotFound, http.StatusNotFound, `"success":false`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.OrderService{
+				CustomerEditFunc: func(ctx context.Context, code string, input order.CustomerEditInput) (*order.Order, error) {
+					called = true
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					o := order.NewOrder(order.SaleTypeDelivery, []order.OrderProduct{{ID: "p1", Name: "Burger", Price: 1000, Quantity: 1}})
+					o.Code = code
+					o.ShippingAddress = input.ShippingAddress
+					return o, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.PATCH("/api/v1/orders/track/:code", NewOrderHandler(service).CustomerEdit)
+
+			w := serveJSON(router, http.MethodPatch, "/api/v1/orders/track/ORD-7KQ2M9", tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if called != tt.wantCalled {
+				t.Errorf("service called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"html/template"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+//go:embed templates/dashboard.html
+var dashboardFS embed.FS
+
+// dashboardTemplate is parsed once; a broken template fails at startup, not on the first request
+var dashboardTemplate = template.Must(template.ParseFS(dashboardFS, "templates/dashboard.html"))
+
+// DashboardSettings configures what the admin dashboard shows
+type DashboardSettings struct {
+	Location          *time.Location // "Today" is the calendar day in this zone
+	LowStockThreshold int            // Products with this stock or less are listed
+	LowStockLimit     int            // Maximum low stock products listed
+	RefreshInterval   time.Duration  // How often the page polls the data endpoint
+}
+
+// DashboardHandler serves the embedded admin dashboard
+type DashboardHandler struct {
+	orders   order.ServiceAPI
+	products product.ServiceAPI
+	settings DashboardSettings
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(orders order.ServiceAPI, products product.ServiceAPI, settings DashboardSettings) *DashboardHandler {
+	return &DashboardHandler{orders: orders, products: products, settings: settings}
+}
+
+// dashboardPage is the template data of the dashboard page
+type dashboardPage struct {
+	Data           dto.DashboardResponse
+	DataURL        string
+	RefreshSeconds int
+}
+
+// Page handles GET /api/v1/admin/dashboard
+// It renders the current numbers server-side; inline JS then polls the data endpoint.
+func (h *DashboardHandler) Page(c *gin.Context) {
+	data, err := h.load(c.Request.Context())
+	if err != nil {
+		logger.Error("failed to load dashboard", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to load dashboard")
+		return
+	}
+
+	var page bytes.Buffer
+	if err := dashboardTemplate.Execute(&page, dashboardPage{
+		Data:           data,
+		DataURL:        c.Request.URL.Path + "/data",
+		RefreshSeconds: int(h.settings.RefreshInterval / time.Second),
+	}); err != nil {
+		logger.Error("failed to render dashboard", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to render dashboard")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// Data handles GET /api/v1/admin/dashboard/data
+func (h *DashboardHandler) Data(c *gin.Context) {
+	data, err := h.load(c.Request.Context())
+	if err != nil {
+		logger.Error("failed to load dashboard data", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to load dashboard data")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, http.StatusOK, data, "")
+}
+
+// load gathers the open orders, today's Z report and the low stock products concurrently
+func (h *DashboardHandler) load(ctx context.Context) (dto.DashboardResponse, error) {
+	var openStatuses []order.OrderStatus
+	for _, status := range order.AllStatuses {
+		if !slices.Contains(order.TerminalStatuses, status) {
+			openStatuses = append(openStatuses, status)
+		}
+	}
+	today := time.Now().In(h.settings.Location).Format(order.ZReportDateLayout)
+
+	var (
+		open     *order.OrderMetrics
+		report   *order.ZReport
+		lowStock []*product.Product
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		open, err = h.orders.GetMetrics(gctx, order.OrderFilters{Statuses: openStatuses})
+		return err
+	})
+	g.Go(func() (err error) {
+		report, err = h.orders.GetZReport(gctx, today, h.settings.Location)
+		return err
+	})
+	g.Go(func() (err error) {
+		lowStock, err = h.products.GetLowStock(gctx, h.settings.LowStockThreshold, h.settings.LowStockLimit)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return dto.DashboardResponse{}, err
+	}
+
+	return dto.ToDashboardResponse(open, openStatuses, report, lowStock, h.settings.LowStockThreshold, h.orders.Currency()), nil
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/gin-gonic/gin"
+)
+
+// dashboardCalls records what the dashboard asked the services for
+type dashboardCalls struct {
+	statuses  []order.OrderStatus
+	date      string
+	loc       string
+	threshold int
+	limit     int
+}
+
+// newDashboardRouter serves the dashboard over services returning the given figures, or failing with err
+func newDashboardRouter(metrics *order.OrderMetrics, report *order.ZReport, lowStock []*product.Product, err error) (*gin.Engine, *dashboardCalls) {
+	calls := &dashboardCalls{}
+	orders := &mocks.OrderService{
+		GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+			calls.statuses = filters.Statuses
+			return metrics, err
+		},
+		GetZReportFunc: func(ctx context.Context, date string, loc *time.Location) (*order.ZReport, error) {
+			calls.date, calls.loc = date, loc.String()
+			r := *report
+			r.Date, r.Timezone = date, loc.String()
+			return &r, nil
+		},
+		CurrencyFunc: func() money.Currency { return money.Currency{Code: "USD", MinorUnits: 2} },
+	}
+	products := &mocks.ProductService{
+		GetLowStockFunc: func(ctx context.Context, threshold, limit int) ([]*product.Product, error) {
+			calls.threshold, calls.limit = threshold, limit
+			return lowStock, nil
+		},
+	}
+
+	loc, _ := time.LoadLocation("America/Bogota")
+	h := NewDashboardHandler(orders, products, DashboardSettings{Location: loc, LowStockThreshold: 5, LowStockLimit: 20, RefreshInterval: 30 * time.Second})
+	router := gin.New()
+	router.GET("/api/v1/admin/dashboard", h.Page)
+	router.GET("/api/v1/admin/dashboard/data", h.Data)
+	return router, calls
+}
+
+func TestDashboardPage(t *testing.T) {
+	stock := 2
+	busy := &order.OrderMetrics{OrderCount: 4, OrdersByStatus: map[order.OrderStatus]int{order.StatusCreated: 3, order.StatusInProgress: 1}}
+	busyDay := &order.ZReport{OrderCount: 12, Sales: 1234550, AvgTicket: 102879, CancelledCount: 1}
+	lowStock := []*product.Product{{ID: "p1", Name: `Salsa <script>alert("x")</script>`, Stock: &stock}}
+
+	tests := []struct {
+		name        string
+		metrics     *order.OrderMetrics
+		report      *order.ZReport
+		lowStock    []*product.Product
+		wantBody    []string
+		notWantBody []string
+	}{
+		{
+			name:    "busy day",
+			metrics: busy, report: busyDay, lowStock: lowStock,
+			wantBody: []string{
+				`data-field="sales">12345.50<`,
+				`data-field="order_count">12<`,
+				`data-field="avg_ticket">1028.79<`,
+				`data-field="open_orders">4<`,
+				"<td>CREATED</td><td class=\"count\">3</td>",
+				"<td>IN_PROGRESS</td><td class=\"count\">1</td>",
+				"Salsa &lt;script&gt;",
+				`data-field="timezone">America/Bogota<`,
+				`"/api/v1/admin/dashboard/data"`,
+				"refreshMs =  30  * 1000",
+			},
+			notWantBody: []string{"<script>alert", `<td class="empty">`},
+		},
+		{
+			name:    "zero data",
+			metrics: &order.OrderMetrics{}, report: &order.ZReport{},
+			wantBody: []string{
+				`data-field="sales">0.00<`,
+				`data-field="open_orders">0<`,
+				"<td>CREATED</td><td class=\"count\">0</td>",
+				`<td class="empty">No products running low</td>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := newDashboardRouter(tt.metrics, tt.report, tt.lowStock, nil)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/admin/dashboard", "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if got := w.Header().Get("Cache-Control"); got != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", got)
+			}
+			body := w.Body.String()
+			for _, want := range tt.wantBody {
+				if !strings.Contains(body, want) {
+					t.Errorf("page misses %s", want)
+				}
+			}
+			for _, notWant := range tt.notWantBody {
+				if strings.Contains(body, notWant) {
+					t.Errorf("page has %s", notWant)
+				}
+			}
+			if strings.Contains(body, "http://") || strings.Contains(body, "https://") {
+				t.Error("page loads external assets")
+			}
+		})
+	}
+}
+
+func TestDashboardData(t *testing.T) {
+	stock := 0
+	metrics := &order.OrderMetrics{OrderCount: 2, OrdersByStatus: map[order.OrderStatus]int{order.StatusVerified: 2}}
+	report := &order.ZReport{OrderCount: 7, Sales: 50000, AvgTicket: 7143, CancelledCount: 2}
+	router, calls := newDashboardRouter(metrics, report, []*product.Product{{ID: "p1", Name: "Agua", SalePointID: "sp1", Stock: &stock}}, nil)
+
+	w := serveJSON(router, http.MethodGet, "/api/v1/admin/dashboard/data", "", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	for _, status := range order.TerminalStatuses {
+		if slices.Contains(calls.statuses, status) {
+			t.Errorf("open orders include terminal status %s", status)
+		}
+	}
+	if len(calls.statuses) != len(order.AllStatuses)-len(order.TerminalStatuses) {
+		t.Errorf("open statuses = %v", calls.statuses)
+	}
+	loc, _ := time.LoadLocation("America/Bogota")
+	if today := time.Now().In(loc).Format(order.ZReportDateLayout); calls.date != today || calls.loc != "America/Bogota" {
+		t.Errorf("report of %s %s, want today %s in America/Bogota", calls.date, calls.loc, today)
+	}
+	if calls.threshold != 5 || calls.limit != 20 {
+		t.Errorf("low stock threshold %d, limit %d, want 5, 20", calls.threshold, calls.limit)
+	}
+
+	var resp struct {
+		Data struct {
+			OpenOrders   int64 `json:"open_orders"`
+			OpenByStatus []struct {
+				Status order.OrderStatus `json:"status"`
+				Count  int               `json:"count"`
+			} `json:"open_by_status"`
+			Today struct {
+				OrderCount     int64  `json:"order_count"`
+				SalesFormatted string `json:"sales_formatted"`
+				CancelledCount int64  `json:"cancelled_count"`
+			} `json:"today"`
+			LowStock []struct {
+				ID    string `json:"id"`
+				Stock int    `json:"stock"`
+			} `json:"low_stock"`
+			LowStockThreshold int `json:"low_stock_threshold"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	data := resp.Data
+	if data.OpenOrders != 2 || data.Today.OrderCount != 7 || data.Today.SalesFormatted != "500.00" || data.Today.CancelledCount != 2 {
+		t.Errorf("data = %+v", data)
+	}
+	if len(data.OpenByStatus) != len(calls.statuses) || data.OpenByStatus[1].Status != order.StatusVerified || data.OpenByStatus[1].Count != 2 {
+		t.Errorf("open by status = %+v", data.OpenByStatus)
+	}
+	if len(data.LowStock) != 1 || data.LowStock[0].ID != "p1" || data.LowStock[0].Stock != 0 || data.LowStockThreshold != 5 {
+		t.Errorf("low stock = %+v, threshold %d", data.LowStock, data.LowStockThreshold)
+	}
+}
+
+func TestDashboardServiceError(t *testing.T) {
+	router, _ := newDashboardRouter(nil, &order.ZReport{}, nil, errors.New("database unavailable"))
+
+	for _, path := range []string{"/api/v1/admin/dashboard", "/api/v1/admin/dashboard/data"} {
+		t.Run(path, func(t *testing.T) {
+			w := serveJSON(router, http.MethodGet, path, "", false)
+			if w.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want 500", w.Code)
+			}
+			if strings.Contains(w.Body.String(), "<html") {
+				t.Error("a partial page was rendered")
+			}
+		})
+	}
+}
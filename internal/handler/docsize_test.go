@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/gin-gonic/gin"
+)
+
+func TestOversizedDocumentsAreUnprocessable(t *testing.T) {
+	tooLarge := func(sentinel error) error {
+		return fmt.Errorf("validation error: %w: 1048700 bytes, max 1048576", sentinel)
+	}
+
+	tests := []struct {
+		name   string
+		router *gin.Engine
+		method string
+		target string
+		body   string
+	}{
+		{
+			name: "order create",
+			router: newOrderRouter(&mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					return nil, tooLarge(order.ErrOrderTooLarge)
+				},
+			}),
+			method: http.MethodPost,
+			target: "/api/v1/orders",
+			body: `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+				"products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]}`,
+		},
+		{
+			name: "order modify",
+			router: newOrderRouter(&mocks.OrderService{
+				ModifyFunc: func(ctx context.Context, code string, input order.ModifyInput) (*order.ModifyResult, error) {
+					return nil, tooLarge(order.ErrOrderTooLarge)
+				},
+			}),
+			method: http.MethodPut,
+			target: "/api/v1/orders",
+			body:   `{"code": "ORD-7F3A00", "products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]}`,
+		},
+		{
+			name: "product update",
+			router: newProductRouter(&mocks.ProductService{
+				UpdateFunc: func(ctx context.Context, id string, input product.UpdateInput) (*product.Product, error) {
+					return nil, tooLarge(product.ErrProductTooLarge)
+				},
+			}),
+			method: http.MethodPut,
+			target: "/api/v1/products/11111111-1111-4111-8111-111111111111",
+			body:   `{"photos": ["https://cdn.example.com/1.jpg"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveJSON(tt.router, tt.method, tt.target, tt.body, false)
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, http.StatusUnprocessableEntity, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), "too large") || !strings.Contains(w.Body.String(), "max 1048576") {
+				t.Errorf("body does not explain the limit: %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// update rewrites the golden files in testdata with the current output
+var update = flag.Bool("update", false, "update golden files")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	gin.SetMode(gin.TestMode)
+	logger.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	if err := RegisterValidators(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// assertGolden compares got with testdata/<name>, rewriting the file instead when -update is set
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := "testdata/" + name
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run go test -update to create it): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("output differs from %s (run go test -update after checking the change)\n got: %s\nwant: %s", path, got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ReadOnlySwitch is the runtime read-only state toggled during maintenance windows
+type ReadOnlySwitch interface {
+	Enabled() bool
+	Set(enabled bool)
+}
+
+// MaintenanceHandler handles HTTP requests for maintenance operations
+type MaintenanceHandler struct {
+	readOnly ReadOnlySwitch
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(readOnly ReadOnlySwitch) *MaintenanceHandler {
+	return &MaintenanceHandler{readOnly: readOnly}
+}
+
+// SetReadOnly handles POST /api/v1/admin/read-only
+func (h *MaintenanceHandler) SetReadOnly(c *gin.Context) {
+	var req dto.SetReadOnlyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	h.readOnly.Set(*req.Enabled)
+	logger.Warn("read-only mode changed", "enabled", *req.Enabled, "ip", c.ClientIP())
+
+	response.Success(c, http.StatusOK, dto.ReadOnlyResponse{ReadOnly: h.readOnly.Enabled()}, "")
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateWithAddons(t *testing.T) {
+	body := func(addons string) string {
+		return `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+			"products": [{"id": "p1", "name": "Burger", "price": 10000, "quantity": 2, "addons": ` + addons + `}]}`
+	}
+	many := make([]string, 21)
+	for i := range many {
+		many[i] = fmt.Sprintf(`{"id": "a%d", "name": "Addon", "price": 100}`, i)
+	}
+
+	tests := []struct {
+		name       string
+		addons     string
+		wantStatus int
+		wantAddons []order.OrderProductAddon
+		wantBody   []string
+	}{
+		{
+			name:       "quantity defaults to one",
+			addons:     `[{"id": "a1", "name": "Cheese", "price": 300}, {"id": "a2", "name": "Bacon", "price": 450, "quantity": 2}]`,
+			wantStatus: http.StatusCreated,
+			wantAddons: []order.OrderProductAddon{{ID: "a1", Name: "Cheese", Price: 300, Quantity: 1}, {ID: "a2", Name: "Bacon", Price: 450, Quantity: 2}},
+		},
+		{"free addon", `[{"id": "a1", "name": "Napkins", "price": 0}]`, http.StatusCreated, []order.OrderProductAddon{{ID: "a1", Name: "Napkins", Price: 0, Quantity: 1}}, nil},
+		{"none", `[]`, http.StatusCreated, nil, nil},
+		{"missing name", `[{"id": "a1", "price": 300}]`, http.StatusBadRequest, nil, []string{`"field":"name"`}},
+		{"negative price", `[{"id": "a1", "name": "Cheese", "price": -1}]`, http.StatusBadRequest, nil, []string{`"field":"price"`}},
+		{"quantity too large", `[{"id": "a1", "name": "Cheese", "price": 300, "quantity": 21}]`, http.StatusBadRequest, nil, []string{`"field":"quantity"`}},
+		{"too many addons", "[" + strings.Join(many, ",") + "]", http.StatusBadRequest, nil, []string{`"field":"addons"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.CreateInput
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					got = &input
+					return mocks.SampleOrders(1)[0], nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPost, "/api/v1/orders", body(tt.addons), false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+			if tt.wantStatus != http.StatusCreated {
+				if got != nil {
+					t.Error("service called with invalid addons")
+				}
+				return
+			}
+			if addons := got.Products[0].Addons; !reflect.DeepEqual(addons, tt.wantAddons) {
+				t.Errorf("addons = %+v, want %+v", addons, tt.wantAddons)
+			}
+		})
+	}
+}
+
+func TestOrderResponseRendersAddons(t *testing.T) {
+	service := &mocks.OrderService{
+		GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) {
+			o := order.NewOrder(order.SaleTypeOnSite, []order.OrderProduct{
+				{ID: "p1", Name: "Burger", Price: 10000, Quantity: 2, Addons: []order.OrderProductAddon{{ID: "a1", Name: "Cheese", Price: 300, Quantity: 2}}},
+				{ID: "p2", Name: "Soda", Price: 3000, Quantity: 1},
+			})
+			o.Code = code
+			return o, nil
+		},
+	}
+	router := newOrderRouter(service)
+	router.GET("/api/v1/orders/:code", NewOrderHandler(service).GetByCode)
+
+	w := serveJSON(router, http.MethodGet, "/api/v1/orders/ORD-7KQ2M9", "", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	for _, want := range []string{
+		`"addons":[{"id":"a1","name":"Cheese","price":300,"quantity":2}],"quantity":2,"line_total":21200`,
+		`"name":"Soda","price":3000,"quantity":1,"line_total":3000`,
+		`"subtotal":24200`,
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("body misses %s: %s", want, w.Body.String())
+		}
+	}
+}
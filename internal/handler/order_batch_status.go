@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// BatchUpdateStatus handles POST /api/v1/orders/batch/status.
+// Every order is updated on its own; the response is 200 with one result per code (207-style),
+// each with the HTTP status a single PATCH would have returned.
+func (h *OrderHandler) BatchUpdateStatus(c *gin.Context) {
+	var req dto.BatchStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	results, err := h.service.BatchUpdateStatus(c.Request.Context(), req.ToBatchStatusInput())
+	if err != nil {
+		if errors.Is(err, order.ErrBatchEmpty) || errors.Is(err, order.ErrBatchTooLarge) {
+			response.Error(c, http.StatusBadRequest, err, "Invalid batch")
+			return
+		}
+		statusCode := h.mapErrorToStatusCode(err)
+		if statusCode == http.StatusInternalServerError {
+			logger.Error("failed to batch update order status", "error", err)
+		}
+		respondError(c, statusCode, err, "Failed to update orders")
+		return
+	}
+
+	resp := dto.ToBatchStatusResponse(req.Status, results, h.mapErrorToStatusCode)
+	logger.Info("order status batch updated",
+		"status", req.Status,
+		"orders", len(resp.Results),
+		"succeeded", resp.Succeeded,
+		"failed", resp.Failed,
+	)
+	response.Success(c, http.StatusOK, resp, "")
+}
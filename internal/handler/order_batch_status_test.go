@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestBatchUpdateStatus(t *testing.T) {
+	service := &mocks.OrderService{
+		BatchUpdateStatusFunc: func(ctx context.Context, input order.BatchStatusInput) ([]order.BatchStatusResult, error) {
+			delivered := mocks.SampleOrders(1)[0]
+			delivered.Status = input.Status
+			return []order.BatchStatusResult{
+				{Code: "ORD-000001", Order: delivered},
+				{Code: "ORD-000002", Err: fmt.Errorf("%w: CREATED to DELIVERED", order.ErrInvalidStatusTransition)},
+				{Code: "ORD-999999", Err: order.ErrOrderNotFound},
+				{Code: "ORD-000003", Err: errors.New("connection refused")},
+			}, nil
+		},
+	}
+	router := newOrderRouter(service)
+	router.POST("/api/v1/orders/batch/status", NewOrderHandler(service).BatchUpdateStatus)
+
+	w := serveJSON(router, http.MethodPost, "/api/v1/orders/batch/status", `{"codes": ["ORD-000001", "ORD-000002", "ORD-999999", "ORD-000003"], "status": "DELIVERED"}`, false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 even with failures, body %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Data dto.BatchStatusResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []dto.BatchStatusItemResponse{
+		{Code: "ORD-000001", Success: true, HTTPStatus: http.StatusOK, Status: order.StatusDelivered},
+		{Code: "ORD-000002", HTTPStatus: http.StatusConflict, Error: "invalid status transition: CREATED to DELIVERED"},
+		{Code: "ORD-999999", HTTPStatus: http.StatusNotFound, Error: order.ErrOrderNotFound.Error()},
+		{Code: "ORD-000003", HTTPStatus: http.StatusInternalServerError, Error: "connection refused"},
+	}
+	if body.Data.Status != order.StatusDelivered || body.Data.Succeeded != 1 || body.Data.Failed != 3 {
+		t.Errorf("summary = %s %d/%d, want DELIVERED 1/3", body.Data.Status, body.Data.Succeeded, body.Data.Failed)
+	}
+	if len(body.Data.Results) != len(want) {
+		t.Fatalf("results = %+v, want %+v", body.Data.Results, want)
+	}
+	for i := range want {
+		if body.Data.Results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, body.Data.Results[i], want[i])
+		}
+	}
+}
+
+func TestBatchUpdateStatusBadRequests(t *testing.T) {
+	codes := func(n int) string {
+		c := make([]string, n)
+		for i := range c {
+			c[i] = fmt.Sprintf(`"ORD-%06d"`, i)
+		}
+		return "[" + strings.Join(c, ",") + "]"
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		err      error // Returned by the service
+		wantBody string
+	}{
+		{"no codes", `{"codes": [], "status": "DELIVERED"}`, nil, `"field":"codes"`},
+		{"codes missing", `{"status": "DELIVERED"}`, nil, `"field":"codes"`},
+		{"too many codes", `{"codes": ` + codes(101) + `, "status": "DELIVERED"}`, nil, `"field":"codes"`},
+		{"blank code", `{"codes": ["ORD-000001", ""], "status": "DELIVERED"}`, nil, `"field":"codes[1]"`},
+		{"unknown status", `{"codes": ["ORD-000001"], "status": "SERVED"}`, nil, `"field":"status"`},
+		{"not JSON", `codes=ORD-000001`, nil, ""},
+		{"rejected by the service", `{"codes": ["ORD-000001"], "status": "DELIVERED"}`, order.ErrBatchTooLarge, "Invalid batch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.OrderService{
+				BatchUpdateStatusFunc: func(ctx context.Context, input order.BatchStatusInput) ([]order.BatchStatusResult, error) {
+					called = true
+					return nil, tt.err
+				},
+			}
+			router := newOrderRouter(service)
+			router.POST("/api/v1/orders/batch/status", NewOrderHandler(service).BatchUpdateStatus)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/orders/batch/status", tt.body, false)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400, body %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if called != (tt.err != nil) {
+				t.Errorf("service called = %v", called)
+			}
+		})
+	}
+}
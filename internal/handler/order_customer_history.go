@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCustomerHistory handles GET /api/v1/orders/customer/:identification?id_type=CC (admin).
+// It returns a page of the customer's orders, newest first, with a summary of all their orders.
+func (h *OrderHandler) GetCustomerHistory(c *gin.Context) {
+	q := order.CustomerHistoryQuery{Identification: c.Param("identification")}
+
+	if idTypeStr := c.Query("id_type"); idTypeStr != "" {
+		idType := order.IDType(idTypeStr)
+		if err := enumParam("id_type", idType, order.AllIDTypes, order.ErrInvalidIDType); err != nil {
+			respondQueryError(c, err, "Invalid filter parameters")
+			return
+		}
+		q.IDType = &idType
+	}
+	if companyID := c.Query("company_id"); companyID != "" {
+		q.CompanyID = &companyID
+	}
+	if salePointID := c.Query("sale_point_id"); salePointID != "" {
+		q.SalePointID = &salePointID
+	}
+
+	limit, offset, err := parsePagination(c, h.service.PageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+	q.Limit = limit
+	q.Offset = offset
+
+	history, err := h.service.GetCustomerHistory(c.Request.Context(), q)
+	if err != nil {
+		if isDomainError(err) {
+			respondError(c, http.StatusBadRequest, err, "Invalid customer identification")
+			return
+		}
+		logger.Error("failed to get customer orders", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get customer orders")
+		return
+	}
+
+	response.PaginatedWithSummary(c, http.StatusOK,
+		dto.ToOrderResponses(history.Orders),
+		dto.ToCustomerStatsResponse(history.Stats, h.service.Currency()),
+		history.Stats.OrderCount, limit, offset,
+	)
+}
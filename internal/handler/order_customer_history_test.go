@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestGetCustomerHistory(t *testing.T) {
+	last := time.Date(2024, 6, 1, 18, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantQuery  string // The query the service receives, as "identification id_type company limit offset"
+		wantBody   []string
+	}{
+		{"history", "", http.StatusOK, "1020304050 - - 20 0", []string{
+			`"code":"ORD-`,
+			`"summary":{"order_count":42,"lifetime_spend":125000,"last_order_at":"2024-06-01T18:05:00Z","currency":`,
+			`"current_page":1,"total_pages":3,"total_items":42,"page_size":20`,
+		}},
+		{"id type and page", "?id_type=NIT&limit=10&offset=10", http.StatusOK, "1020304050 NIT - 10 10", []string{`"current_page":2,"total_pages":5`}},
+		{"company", "?company_id=c1", http.StatusOK, "1020304050 - c1 20 0", nil},
+		{"unknown id type", "?id_type=DNI", http.StatusBadRequest, "", []string{`"field":"id_type"`, "must be one of: CC, CE"}},
+		{"limit not a number", "?limit=all", http.StatusBadRequest, "", []string{`"field":"limit"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.CustomerHistoryQuery
+			service := &mocks.OrderService{
+				PageLimitsFunc: func() util.PageLimits { return util.PageLimits{DefaultLimit: 20, MaxLimit: 100} },
+				GetCustomerHistoryFunc: func(ctx context.Context, q order.CustomerHistoryQuery) (*order.CustomerHistory, error) {
+					got = &q
+					return &order.CustomerHistory{
+						Orders: mocks.SampleOrders(2),
+						Stats:  order.CustomerStats{OrderCount: 42, LifetimeSpend: 125000, LastOrderAt: &last},
+					}, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/customer/:identification", NewOrderHandler(service).GetCustomerHistory)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/customer/1020304050"+tt.query, "", true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+			if tt.wantStatus != http.StatusOK {
+				if got != nil {
+					t.Error("rejected request reached the service")
+				}
+				return
+			}
+			idType, company := "-", "-"
+			if got.IDType != nil {
+				idType = string(*got.IDType)
+			}
+			if got.CompanyID != nil {
+				company = *got.CompanyID
+			}
+			if q := fmt.Sprintf("%s %s %s %d %d", got.Identification, idType, company, got.Limit, got.Offset); q != tt.wantQuery {
+				t.Errorf("query = %q, want %q", q, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestGetCustomerHistoryErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"identification rejected", apperrors.NewDomainError(order.ErrCustomerIdentificationRequired, "identification", ""), http.StatusBadRequest},
+		{"database down", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetCustomerHistoryFunc: func(ctx context.Context, q order.CustomerHistoryQuery) (*order.CustomerHistory, error) {
+					return nil, tt.err
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/customer/:identification", NewOrderHandler(service).GetCustomerHistory)
+
+			if w := serveJSON(router, http.MethodGet, "/api/v1/orders/customer/%20", "", true); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetCustomerHistoryWithoutOrders(t *testing.T) {
+	service := &mocks.OrderService{
+		GetCustomerHistoryFunc: func(ctx context.Context, q order.CustomerHistoryQuery) (*order.CustomerHistory, error) {
+			return &order.CustomerHistory{}, nil
+		},
+	}
+	router := newOrderRouter(service)
+	router.GET("/api/v1/orders/customer/:identification", NewOrderHandler(service).GetCustomerHistory)
+
+	w := serveJSON(router, http.MethodGet, "/api/v1/orders/customer/1111111111", "", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	for _, want := range []string{`"data":[]`, `"order_count":0,"lifetime_spend":0,"currency"`, `"total_items":0`} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("body misses %s: %s", want, w.Body.String())
+		}
+	}
+	if strings.Contains(w.Body.String(), "last_order_at") {
+		t.Errorf("customer without orders has a last order: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestDateFilters(t *testing.T) {
+	bogota, err := time.LoadLocation("America/Bogota")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		loc      *time.Location
+		wantFrom string
+		wantTo   string
+	}{
+		{"RFC3339", "?date_from=2024-05-01T08:00:00-05:00&date_to=2024-05-31T18:00:00Z", time.UTC, "2024-05-01T13:00:00Z", "2024-05-31T18:00:00Z"},
+		{"date only", "?date_from=2024-05-01&date_to=2024-05-31", time.UTC, "2024-05-01T00:00:00Z", "2024-05-31T23:59:59.999Z"},
+		{"date only in the filter zone", "?date_from=2024-05-01&date_to=2024-05-31", bogota, "2024-05-01T05:00:00Z", "2024-06-01T04:59:59.999Z"},
+		{"mixed", "?date_from=2024-05-01T12:00:00Z&date_to=2024-05-01", bogota, "2024-05-01T12:00:00Z", "2024-05-02T04:59:59.999Z"},
+		{"only from", "?date_from=2024-05-01", time.UTC, "2024-05-01T00:00:00Z", ""},
+		{"only to", "?date_to=2024-05-01", time.UTC, "", "2024-05-01T23:59:59.999Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got order.OrderFilters
+			service := &mocks.OrderService{
+				FilterLocationFunc: func() *time.Location { return tt.loc },
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					got = filters
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", true)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if from := deref(got.DateFrom); from != tt.wantFrom {
+				t.Errorf("date_from = %q, want %q", from, tt.wantFrom)
+			}
+			if to := deref(got.DateTo); to != tt.wantTo {
+				t.Errorf("date_to = %q, want %q", to, tt.wantTo)
+			}
+			// The repository reads the bounds back as RFC3339
+			from, to := got.ParseDateRange()
+			if (from == nil) != (tt.wantFrom == "") || (to == nil) != (tt.wantTo == "") {
+				t.Errorf("ParseDateRange dropped a bound: from %v, to %v", from, to)
+			}
+		})
+	}
+}
+
+func TestDateFiltersRejectInvalidValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		query     string
+		wantField string
+	}{
+		{"list with a bad from", "/api/v1/orders", "?date_from=2024-13-01", "date_from"},
+		{"list with a bad to", "/api/v1/orders", "?date_from=2024-05-01&date_to=05/31/2024", "date_to"},
+		{"list with a timestamp missing its zone", "/api/v1/orders", "?date_to=2024-05-31T10:00:00", "date_to"},
+		{"metrics", "/api/v1/orders/metrics", "?date_from=yesterday", "date_from"},
+		{"metrics export", "/api/v1/orders/metrics/export", "?date_to=2024-02-30", "date_to"},
+		{"order export", "/api/v1/orders/export", "?format=csv&date_from=tomorrow", "date_from"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.OrderService{
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					called = true
+					return nil, 0, nil
+				},
+				GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+					called = true
+					return &order.OrderMetrics{}, nil
+				},
+				ExportFunc: func(ctx context.Context, input order.ExportInput, emit order.ExportEmitFunc) error {
+					called = true
+					return nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/export", NewOrderHandler(service).ExportOrders)
+
+			w := serveJSON(router, http.MethodGet, tt.path+tt.query, "", true)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400, body %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantField) {
+				t.Errorf("error does not name %s: %s", tt.wantField, w.Body.String())
+			}
+			if called {
+				t.Error("service called with an invalid date filter")
+			}
+		})
+	}
+}
+
+// deref returns the filter value, or "" when it is unset
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
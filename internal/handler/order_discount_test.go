@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateWithDiscount(t *testing.T) {
+	const order1 = `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+		"products": [{"id": "p1", "name": "Burger", "price": 1005, "quantity": 1}]`
+
+	tests := []struct {
+		name       string
+		discount   string
+		wantStatus int
+		wantBody   []string
+	}{
+		{"no discount", "", http.StatusCreated, []string{`"subtotal":1005`, `"discount_amount":0`, `"total":1005`}},
+		{
+			name:       "percent rounded to the cent",
+			discount:   `{"type": "PERCENT", "value": 10, "description": "Happy hour"}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   []string{`"subtotal":1005`, `"discount_amount":101`, `"total":904`},
+		},
+		{"fixed", `{"type": "FIXED", "value": 5}`, http.StatusCreated, []string{`"discount_amount":5`, `"total":1000`}},
+		{"percent above 100", `{"type": "PERCENT", "value": 101}`, http.StatusUnprocessableEntity, []string{`"field":"discount.value"`}},
+		{"fixed above the subtotal", `{"type": "FIXED", "value": 1006}`, http.StatusUnprocessableEntity, []string{`"field":"discount.value"`}},
+		{"unknown type", `{"type": "BOGO", "value": 1}`, http.StatusBadRequest, nil},
+		{"missing type", `{"value": 1}`, http.StatusBadRequest, nil},
+		{"negative value", `{"type": "FIXED", "value": -1}`, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					o := order.NewOrder(input.SaleType, input.Products)
+					o.Code, o.Discount = "ORD-7KQ2M9", input.Discount
+					if o.Discount != nil {
+						if err := o.Discount.Validate(o.ComputeSubtotal()); err != nil {
+							return nil, fmt.Errorf("validation error: %w", err)
+						}
+					}
+					o.CalculateTotal()
+					return o, nil
+				},
+			}
+
+			body := order1 + `}`
+			if tt.discount != "" {
+				body = order1 + `, "discount": ` + tt.discount + `}`
+			}
+			w := serveJSON(newOrderRouter(service), http.MethodPost, "/api/v1/orders", body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestModifyForwardsDiscount(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *order.Discount
+	}{
+		{"omitted", `{"code": "ORD-7KQ2M9", "note": "Window seat"}`, nil},
+		{"replaced", `{"code": "ORD-7KQ2M9", "discount": {"type": "FIXED", "value": 500}}`, &order.Discount{Type: order.DiscountFixed, Value: 500}},
+		{"removed with a zero value", `{"code": "ORD-7KQ2M9", "discount": {"type": "PERCENT", "value": 0}}`, &order.Discount{Type: order.DiscountPercent}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.Discount
+			service := &mocks.OrderService{
+				ModifyFunc: func(ctx context.Context, code string, input order.ModifyInput) (*order.ModifyResult, error) {
+					got = input.Discount
+					return &order.ModifyResult{Order: mocks.SampleOrders(1)[0]}, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPut, "/api/v1/orders", tt.body, false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("discount = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// Duplicate handles POST /api/v1/orders/:code/duplicate ("order again").
+// The new order uses current catalog prices; lines that are no longer available are dropped,
+// or block the duplicate with 409 when the body sets "strict": true.
+func (h *OrderHandler) Duplicate(c *gin.Context) {
+	code := c.Param("code")
+
+	var req dto.DuplicateOrderRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Warn("invalid request body", "error", err)
+			if errorMsg, details := FormatValidationErrors(err); details != nil {
+				response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+				return
+			}
+			response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+			return
+		}
+	}
+	applyChannelHeader(c, &req.Channel)
+
+	result, err := h.service.Duplicate(c.Request.Context(), code, req.ToDuplicateInput())
+	if err != nil {
+		switch {
+		case errors.Is(err, order.ErrDuplicateItemsUnavailable) && result != nil:
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+				"message": "Some products are no longer available",
+				"data":    dto.ToDuplicateOrderResponse(result),
+			})
+		case errors.Is(err, order.ErrDuplicateSaleTypeMismatch):
+			response.Error(c, http.StatusUnprocessableEntity, err, "Order cannot be duplicated with a different sale type")
+		case errors.Is(err, order.ErrDuplicateNotConfigured):
+			response.Error(c, http.StatusServiceUnavailable, err, "Order duplication is not enabled")
+		default:
+			statusCode := h.mapErrorToStatusCode(err)
+			if statusCode == http.StatusInternalServerError {
+				logger.Error("failed to duplicate order", "error", err, "code", code)
+			}
+			respondError(c, statusCode, err, "Failed to duplicate order")
+		}
+		return
+	}
+
+	logger.Info("order duplicated",
+		"source", result.Source,
+		"code", result.Order.Code,
+		"price_changes", len(result.PriceChanges),
+		"unavailable", len(result.Unavailable),
+	)
+	response.Success(c, http.StatusCreated, dto.ToDuplicateOrderResponse(result), "Order created successfully")
+}
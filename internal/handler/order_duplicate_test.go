@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestDuplicate(t *testing.T) {
+	changes := []order.PriceChange{{ProductID: "p1", Name: "Burger", OldPrice: 10000, NewPrice: 11000}}
+	unavailable := []order.UnavailableLine{{ProductID: "p2", Name: "Soda", Quantity: 1, Reason: order.UnavailableOutOfStock}}
+
+	tests := []struct {
+		name            string
+		body            string
+		err             error
+		wantStatus      int
+		wantInput       order.DuplicateInput
+		wantOrder       bool
+		wantUnavailable int
+	}{
+		{"no body", "", nil, http.StatusCreated, order.DuplicateInput{}, true, 1},
+		{"strict with a channel", `{"strict": true, "channel": "WEB"}`, nil, http.StatusCreated, order.DuplicateInput{Strict: true, Channel: order.ChannelWeb}, true, 1},
+		{"strict refused", `{"strict": true}`, order.ErrDuplicateItemsUnavailable, http.StatusConflict, order.DuplicateInput{Strict: true}, false, 1},
+		{"other sale type", `{"sale_type": "ON_SITE"}`, order.ErrDuplicateSaleTypeMismatch, http.StatusUnprocessableEntity, order.DuplicateInput{}, false, 0},
+		{"not configured", "", order.ErrDuplicateNotConfigured, http.StatusServiceUnavailable, order.DuplicateInput{}, false, 0},
+		{"unknown order", "", order.ErrOrderNotFound, http.StatusNotFound, order.DuplicateInput{}, false, 0},
+		{"invalid sale type", `{"sale_type": "TAKEAWAY"}`, nil, http.StatusBadRequest, order.DuplicateInput{}, false, 0},
+		{"invalid table", `{"table_number": 0}`, nil, http.StatusBadRequest, order.DuplicateInput{}, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.DuplicateInput
+			service := &mocks.OrderService{
+				DuplicateFunc: func(ctx context.Context, code string, input order.DuplicateInput) (*order.DuplicateResult, error) {
+					got = &input
+					result := &order.DuplicateResult{Source: code, PriceChanges: changes, Unavailable: unavailable}
+					if tt.err == order.ErrDuplicateItemsUnavailable {
+						return result, tt.err
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					result.Order = order.NewOrder(order.SaleTypeDelivery, []order.OrderProduct{{ID: "p1", Name: "Burger", Price: 11000, Quantity: 2}})
+					result.Order.Code = "ORD-NEW123"
+					return result, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.POST("/api/v1/orders/:code/duplicate", NewOrderHandler(service).Duplicate)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/orders/ORD-7KQ2M9/duplicate", tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusBadRequest {
+				if got != nil {
+					t.Error("service called with an invalid body")
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("service not called")
+			}
+			if got.Strict != tt.wantInput.Strict || got.Channel != tt.wantInput.Channel {
+				t.Errorf("input = %+v, want %+v", *got, tt.wantInput)
+			}
+
+			var resp struct {
+				Data struct {
+					Source string `json:"source"`
+					Order  *struct {
+						Code string `json:"code"`
+					} `json:"order"`
+					PriceChanges []struct {
+						NewPrice int64 `json:"new_price"`
+					} `json:"price_changes"`
+					Unavailable []struct {
+						Reason string `json:"reason"`
+					} `json:"unavailable"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if (resp.Data.Order != nil) != tt.wantOrder {
+				t.Errorf("order = %+v, want present %v", resp.Data.Order, tt.wantOrder)
+			}
+			if len(resp.Data.Unavailable) != tt.wantUnavailable {
+				t.Errorf("unavailable = %+v, want %d lines", resp.Data.Unavailable, tt.wantUnavailable)
+			}
+			if tt.wantOrder && (resp.Data.Source != "ORD-7KQ2M9" || resp.Data.Order.Code != "ORD-NEW123" || len(resp.Data.PriceChanges) != 1) {
+				t.Errorf("response = %+v", resp.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// resumeTokenTrailer carries the token of the last exported order once the stream ends
+const resumeTokenTrailer = "X-Resume-Token"
+
+// orderExportWriter writes the orders of an export stream in one format
+type orderExportWriter interface {
+	contentType() string
+	extension() string
+	header() error
+	write(o *order.Order) error
+	// checkpoint records that every order up to the token was written
+	checkpoint(token string) error
+	complete() error
+}
+
+// ExportOrders handles GET /api/v1/orders/export?format=csv|ndjson&resume_token=
+// Orders matching the list filters are streamed oldest first and flushed after every batch.
+// Each batch ends with a checkpoint carrying its resume token (a "# resume_token=" comment line in CSV,
+// a {"resume_token": ...} line in NDJSON); after a dropped connection the client passes the last token
+// it received to continue right after that order. The final token is also sent as the X-Resume-Token trailer.
+func (h *OrderHandler) ExportOrders(c *gin.Context) {
+	var w orderExportWriter
+	switch format := c.DefaultQuery("format", "csv"); format {
+	case "csv":
+		w = newCSVOrderExport(c.Writer, h.service.Currency())
+	case "ndjson":
+		w = newNDJSONOrderExport(c.Writer)
+	default:
+		response.Error(c, http.StatusBadRequest, fmt.Errorf("unsupported export format: %s", format), "Format must be csv or ndjson")
+		return
+	}
+
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+	input := order.ExportInput{Filters: filters}
+	if token := c.Query("resume_token"); token != "" {
+		after, err := dto.DecodeBatchCursor(token)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, err, "Invalid resume token")
+			return
+		}
+		input.After = after
+	}
+
+	c.Header("Content-Type", w.contentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, ordersExportFilename(filters, w.extension())))
+	c.Header("Trailer", resumeTokenTrailer)
+	c.Status(http.StatusOK)
+
+	// A resumed stream continues a file the client already has, so the header is not repeated
+	if input.After == nil {
+		if err := w.header(); err != nil {
+			logger.Error("failed to write order export", "error", err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	rows := 0
+	lastToken := c.Query("resume_token")
+	err = h.service.Export(ctx, input, func(batch []*order.Order, last order.BatchCursor) error {
+		for _, o := range batch {
+			if err := w.write(o); err != nil {
+				return err
+			}
+		}
+		token := dto.EncodeBatchCursor(last)
+		if err := w.checkpoint(token); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		rows += len(batch)
+		lastToken = token
+		// Stop reading from the database as soon as the client is gone
+		return ctx.Err()
+	})
+	if err == nil {
+		err = w.complete()
+	}
+	if lastToken != "" {
+		c.Writer.Header().Set(resumeTokenTrailer, lastToken)
+	}
+
+	// Headers are already sent, so errors can only be logged
+	switch {
+	case ctx.Err() != nil:
+		logger.Info("order export stopped, client disconnected", "rows", rows, "resume_token", lastToken)
+	case err != nil:
+		logger.Error("failed to write order export", "error", err, "rows", rows, "resume_token", lastToken)
+	default:
+		logger.Info("order export completed", "rows", rows)
+	}
+}
+
+// csvOrderExport writes one CSV row per order; checkpoints are comment lines
+type csvOrderExport struct {
+	out      io.Writer
+	cw       *csv.Writer
+	currency money.Currency
+}
+
+func newCSVOrderExport(out io.Writer, currency money.Currency) *csvOrderExport {
+	return &csvOrderExport{out: out, cw: csv.NewWriter(out), currency: currency}
+}
+
+func (e *csvOrderExport) contentType() string { return "text/csv; charset=utf-8" }
+
+func (e *csvOrderExport) extension() string { return "csv" }
+
+func (e *csvOrderExport) header() error {
+	return e.cw.Write([]string{
+		"id", "code", "created_at", "updated_at", "status", "sale_type", "channel",
+		"customer_name", "table_number", "items", "total_cents", "total",
+	})
+}
+
+func (e *csvOrderExport) write(o *order.Order) error {
+	customerName := ""
+	if o.Customer != nil {
+		customerName = o.Customer.Name
+	}
+	tableNumber := ""
+	if o.TableNumber != nil {
+		tableNumber = strconv.Itoa(*o.TableNumber)
+	}
+	items := 0
+	for _, p := range o.Products {
+		items += p.Quantity
+	}
+
+	return e.cw.Write([]string{
+		o.ID,
+		o.Code,
+		o.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		o.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		string(o.Status),
+		string(o.SaleType),
+		string(o.Channel),
+		customerName,
+		tableNumber,
+		strconv.Itoa(items),
+		strconv.FormatInt(o.Total, 10),
+		e.currency.Format(o.Total),
+	})
+}
+
+func (e *csvOrderExport) checkpoint(token string) error {
+	return e.comment("resume_token=" + token)
+}
+
+func (e *csvOrderExport) complete() error {
+	return e.comment("complete")
+}
+
+// comment flushes the pending rows and writes a "#" line between them
+func (e *csvOrderExport) comment(text string) error {
+	e.cw.Flush()
+	if err := e.cw.Error(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.out, "# "+text+"\n")
+	return err
+}
+
+// ndjsonOrderExport writes one order object per line; checkpoints are {"resume_token": ...} lines
+type ndjsonOrderExport struct {
+	enc *json.Encoder
+}
+
+func newNDJSONOrderExport(out io.Writer) *ndjsonOrderExport {
+	return &ndjsonOrderExport{enc: json.NewEncoder(out)}
+}
+
+func (e *ndjsonOrderExport) contentType() string { return "application/x-ndjson" }
+
+func (e *ndjsonOrderExport) extension() string { return "ndjson" }
+
+func (e *ndjsonOrderExport) header() error { return nil }
+
+func (e *ndjsonOrderExport) write(o *order.Order) error {
+	return e.enc.Encode(dto.ToOrderResponse(o))
+}
+
+func (e *ndjsonOrderExport) checkpoint(token string) error {
+	return e.enc.Encode(gin.H{"resume_token": token})
+}
+
+func (e *ndjsonOrderExport) complete() error {
+	return e.enc.Encode(gin.H{"complete": true})
+}
+
+// ordersExportFilename builds the attachment filename from the applied date range
+func ordersExportFilename(filters order.OrderFilters, extension string) string {
+	from, to := "start", "now"
+	dateFrom, dateTo := filters.ParseDateRange()
+	if dateFrom != nil {
+		from = dateFrom.Format("2006-01-02")
+	}
+	if dateTo != nil {
+		to = dateTo.Format("2006-01-02")
+	}
+	return fmt.Sprintf("orders_%s_%s.%s", from, to, extension)
+}
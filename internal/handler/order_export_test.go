@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+// exportRepository serves the batches of a fixed list of orders sorted oldest first
+type exportRepository struct {
+	order.Repository
+	orders []*order.Order
+}
+
+func (r *exportRepository) FindBatch(ctx context.Context, filters order.OrderFilters, after *order.BatchCursor, limit int) ([]*order.Order, error) {
+	start := 0
+	if after != nil {
+		start = slices.IndexFunc(r.orders, func(o *order.Order) bool { return o.ID == after.ID }) + 1
+	}
+	return r.orders[start:min(start+limit, len(r.orders))], nil
+}
+
+// newExportServer serves the export endpoint over a real connection, backed by the order service
+func newExportServer(t *testing.T, n int) (*httptest.Server, []string) {
+	t.Helper()
+	repo := &exportRepository{}
+	var ids []string
+	for i := range n {
+		o := order.NewOrder(order.SaleTypeOnSite, []order.OrderProduct{{ID: "p1", Name: "Burger", Price: 12000, Quantity: 1}})
+		o.ID, o.Code = fmt.Sprintf("order-%04d", i), fmt.Sprintf("ORD-%06d", i)
+		o.CreatedAt = time.Date(2024, 6, 1, 0, 0, i, 0, time.UTC)
+		repo.orders = append(repo.orders, o)
+		ids = append(ids, o.ID)
+	}
+	svc := order.NewService(repo)
+	service := &mocks.OrderService{ExportFunc: svc.Export}
+	router := newOrderRouter(service)
+	router.GET("/api/v1/orders/export", NewOrderHandler(service).ExportOrders)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv, ids
+}
+
+// exportLine classifies a line of either export format: an order ID, a resume token or the end marker
+func exportLine(format, line string) (id, token string, complete bool) {
+	if format == "csv" {
+		switch {
+		case strings.HasPrefix(line, "# resume_token="):
+			return "", strings.TrimPrefix(line, "# resume_token="), false
+		case line == "# complete":
+			return "", "", true
+		case strings.HasPrefix(line, "id,"):
+			return "", "", false
+		}
+		id, _, _ = strings.Cut(line, ",")
+		return id, "", false
+	}
+	var v struct {
+		ID          string `json:"id"`
+		ResumeToken string `json:"resume_token"`
+		Complete    bool   `json:"complete"`
+	}
+	_ = json.Unmarshal([]byte(line), &v)
+	return v.ID, v.ResumeToken, v.Complete
+}
+
+func TestExportOrdersResume(t *testing.T) {
+	for _, format := range []string{"csv", "ndjson"} {
+		t.Run(format, func(t *testing.T) {
+			srv, ids := newExportServer(t, 2*order.ExportBatchSize+30)
+
+			get := func(ctx context.Context, token string) *http.Response {
+				t.Helper()
+				url := srv.URL + "/api/v1/orders/export?format=" + format
+				if token != "" {
+					url += "&resume_token=" + token
+				}
+				req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+				req.Header.Set("Authorization", "Bearer "+testAdminToken)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("status = %d", resp.StatusCode)
+				}
+				return resp
+			}
+
+			// A slow client drops the connection after the first checkpoint and part of the next batch
+			ctx, cancel := context.WithCancel(context.Background())
+			resp := get(ctx, "")
+			var rows []string
+			var pending []string // Rows after the last checkpoint, which the resume repeats
+			var token string
+			lines := bufio.NewScanner(resp.Body)
+			for lines.Scan() {
+				time.Sleep(50 * time.Microsecond)
+				id, checkpoint, complete := exportLine(format, lines.Text())
+				if complete {
+					t.Fatal("stream completed before the client dropped it")
+				}
+				if checkpoint != "" {
+					rows, pending, token = append(rows, pending...), nil, checkpoint
+				}
+				if id != "" {
+					pending = append(pending, id)
+				}
+				if token != "" && len(pending) == 10 {
+					break
+				}
+			}
+			cancel()
+			resp.Body.Close()
+			if len(rows) != order.ExportBatchSize {
+				t.Fatalf("first checkpoint after %d rows, want %d", len(rows), order.ExportBatchSize)
+			}
+
+			resp = get(context.Background(), token)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			complete := false
+			for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+				if format == "csv" && strings.HasPrefix(line, "id,") {
+					t.Error("resumed stream repeats the CSV header")
+				}
+				id, _, done := exportLine(format, line)
+				if id != "" {
+					rows = append(rows, id)
+				}
+				complete = complete || done
+			}
+			if !complete {
+				t.Errorf("resumed stream did not complete:\n%s", body)
+			}
+			if !slices.Equal(rows, ids) {
+				t.Errorf("partial plus resumed export has %d rows, want the %d orders once and in order", len(rows), len(ids))
+			}
+			if resp.Trailer.Get(resumeTokenTrailer) == "" {
+				t.Errorf("no %s trailer", resumeTokenTrailer)
+			}
+		})
+	}
+}
+
+func TestExportOrdersBadRequests(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown format", "?format=xlsx"},
+		{"malformed resume token", "?resume_token=not-a-token"},
+		{"resume token without an id", "?resume_token=MjAyNC0wNi0wMVQwMDowMDowMFp8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.OrderService{
+				ExportFunc: func(ctx context.Context, input order.ExportInput, emit order.ExportEmitFunc) error {
+					called = true
+					return nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/export", NewOrderHandler(service).ExportOrders)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/export"+tt.query, "", true)
+			if w.Code != http.StatusBadRequest || called {
+				t.Errorf("status = %d, service called = %v, want 400 without a call", w.Code, called)
+			}
+		})
+	}
+}
+
+func TestExportOrdersFirstPage(t *testing.T) {
+	srv, ids := newExportServer(t, 3)
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/orders/export?format=csv&date_from=2024-06-01T00:00:00Z&date_to=2024-06-02T00:00:00Z", nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if got := resp.Header.Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename="orders_2024-06-01_2024-06-02.csv"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != 6 || !strings.HasPrefix(lines[0], "id,code,") || lines[5] != "# complete" {
+		t.Fatalf("body:\n%s", body)
+	}
+	for i, id := range ids {
+		if !strings.HasPrefix(lines[i+1], id+",") {
+			t.Errorf("row %d = %q, want order %s", i+1, lines[i+1], id)
+		}
+	}
+}
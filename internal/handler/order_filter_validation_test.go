@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestFilterValidation(t *testing.T) {
+	delivery, web, confirmed := order.SaleTypeDelivery, order.ChannelWeb, order.PaymentConfirmed
+	verified := order.StatusVerified
+
+	tests := []struct {
+		name      string
+		query     string
+		wantField string // Rejected parameter; "" when the request is valid
+		want      func(f order.OrderFilters) bool
+	}{
+		{"one status", "?status=VERIFIED", "", func(f order.OrderFilters) bool { return reflect.DeepEqual(f.Status, &verified) }},
+		{"several statuses", "?status=CREATED, VERIFIED", "", func(f order.OrderFilters) bool {
+			return reflect.DeepEqual(f.Statuses, []order.OrderStatus{order.StatusCreated, order.StatusVerified})
+		}},
+		{"sale type", "?sale_type=DELIVERY", "", func(f order.OrderFilters) bool { return reflect.DeepEqual(f.SaleType, &delivery) }},
+		{"channel", "?channel=WEB", "", func(f order.OrderFilters) bool { return reflect.DeepEqual(f.Channel, &web) }},
+		{"payment status", "?payment_status=CONFIRMED", "", func(f order.OrderFilters) bool { return reflect.DeepEqual(f.PaymentStatus, &confirmed) }},
+		{"totals", "?min_total=100&max_total=900", "", func(f order.OrderFilters) bool { return *f.MinTotal == 100 && *f.MaxTotal == 900 }},
+		{"unknown status", "?status=FOO", "status", nil},
+		{"lower case status", "?status=created", "status", nil},
+		{"unknown status in a list", "?status=CREATED,FOO", "status", nil},
+		{"unknown sale type", "?sale_type=PICKUP", "sale_type", nil},
+		{"unknown channel", "?channel=FAX", "channel", nil},
+		{"unknown payment status", "?payment_status=MAYBE", "payment_status", nil},
+		{"min total not a number", "?min_total=cheap", "min_total", nil},
+		{"max total not a number", "?max_total=1e3", "max_total", nil},
+	}
+
+	for _, path := range []string{"/api/v1/orders", "/api/v1/orders/metrics"} {
+		for _, tt := range tests {
+			t.Run(path+" "+tt.name, func(t *testing.T) {
+				var got *order.OrderFilters
+				service := &mocks.OrderService{
+					GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+						got = &filters
+						return nil, 0, nil
+					},
+					GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+						got = &filters
+						return &order.OrderMetrics{}, nil
+					},
+				}
+
+				w := serveJSON(newOrderRouter(service), http.MethodGet, path+strings.ReplaceAll(tt.query, " ", "%20"), "", true)
+				if tt.wantField == "" {
+					if w.Code != http.StatusOK {
+						t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+					}
+					if !tt.want(*got) {
+						t.Errorf("filters = %+v", *got)
+					}
+					return
+				}
+				if w.Code != http.StatusBadRequest {
+					t.Fatalf("status = %d, want 400, body %s", w.Code, w.Body.String())
+				}
+				if !strings.Contains(w.Body.String(), `"field":"`+tt.wantField+`"`) {
+					t.Errorf("error does not name %s: %s", tt.wantField, w.Body.String())
+				}
+				if got != nil {
+					t.Error("rejected request reached the service")
+				}
+			})
+		}
+	}
+}
+
+func TestFilterValidationListsAllowedValues(t *testing.T) {
+	service := &mocks.OrderService{}
+
+	w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders?sale_type=PICKUP", "", true)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	for _, saleType := range order.AllSaleTypes {
+		if !strings.Contains(w.Body.String(), string(saleType)) {
+			t.Errorf("allowed values miss %s: %s", saleType, w.Body.String())
+		}
+	}
+}
+
+func TestPaginationValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantField string
+	}{
+		{"limit not a number", "?limit=ten", "limit"},
+		{"limit decimal", "?limit=2.5", "limit"},
+		{"offset not a number", "?offset=first", "offset"},
+		{"offset with a unit", "?offset=20px", "offset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.OrderService{
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					called = true
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", true)
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"field":"`+tt.wantField+`"`) {
+				t.Errorf("status = %d, body %s, want 400 naming %s", w.Code, w.Body.String(), tt.wantField)
+			}
+			if !strings.Contains(w.Body.String(), "must be a whole number") {
+				t.Errorf("body does not explain the value: %s", w.Body.String())
+			}
+			if called {
+				t.Error("rejected request reached the service")
+			}
+		})
+	}
+}
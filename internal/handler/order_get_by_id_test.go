@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestGetByID(t *testing.T) {
+	const id = "3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b"
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"found", nil, http.StatusOK, `"id":"` + id + `"`},
+		{"unknown order", order.ErrOrderNotFound, http.StatusNotFound, "Order not found"},
+		{"database down", errors.New("connection refused"), http.StatusInternalServerError, "Failed to get order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			service := &mocks.OrderService{
+				GetByIDFunc: func(ctx context.Context, requested string) (*order.Order, error) {
+					got = requested
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					o := mocks.SampleOrders(1)[0]
+					o.ID = requested
+					return o, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/id/:id", NewOrderHandler(service).GetByID)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/id/"+id, "", true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got != id {
+				t.Errorf("service asked for %q, want %q", got, id)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -1,28 +1,35 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/emerarteaga/products-api/internal/domain/order"
 	"github.com/emerarteaga/products-api/internal/dto"
 	"github.com/emerarteaga/products-api/internal/infra/logger"
 	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/emerarteaga/products-api/internal/util"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // OrderHandler handles HTTP requests for orders
 type OrderHandler struct {
-	service *order.Service
+	service order.ServiceAPI
 }
 
 // NewOrderHandler creates a new order handler
-func NewOrderHandler(service *order.Service) *OrderHandler {
+func NewOrderHandler(service order.ServiceAPI) *OrderHandler {
 	return &OrderHandler{service: service}
 }
 
-// Create handles POST /api/v1/orders
+// Create handles POST /api/v1/orders; admins may skip the order size guards with ?override=true
 func (h *OrderHandler) Create(c *gin.Context) {
 	var req dto.CreateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -45,22 +52,63 @@ func (h *OrderHandler) Create(c *gin.Context) {
 		return
 	}
 
+	applyChannelHeader(c, &req.Channel)
+
 	// Convert DTO to service input
 	input := req.ToCreateInput()
+	input.OverrideLimits = staffFlag(c, "override")
 
 	o, err := h.service.Create(c.Request.Context(), input)
 	if err != nil {
 		// Map domain errors to HTTP status codes
 		statusCode := h.mapErrorToStatusCode(err)
 		logger.Error("failed to create order", "error", err)
-		response.Error(c, statusCode, err, "Failed to create order")
+		respondError(c, statusCode, err, "Failed to create order")
 		return
 	}
 
-	logger.Info("order created", "order_id", o.ID, "code", o.Code, "sale_type", o.SaleType)
+	logger.Info("order created", "order_id", o.ID, "code", o.Code, "sale_type", o.SaleType, "channel", o.Channel)
 	response.Success(c, http.StatusCreated, dto.ToCreatedResponse(o), "Order created successfully")
 }
 
+// Validate handles POST /api/v1/orders/validate.
+// It runs the same pipeline as Create without persisting and always reports
+// validation problems in the body, so checkouts can preview the order.
+func (h *OrderHandler) Validate(c *gin.Context) {
+	var req dto.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Success(c, http.StatusOK, dto.ToInvalidOrderResponse(errorDetails(err)), "")
+		return
+	}
+
+	applyChannelHeader(c, &req.Channel)
+
+	input := req.ToCreateInput()
+	input.OverrideLimits = staffFlag(c, "override")
+
+	o, err := h.service.ValidateCreate(c.Request.Context(), input)
+	if err != nil {
+		if statusCode := h.mapErrorToStatusCode(err); statusCode >= http.StatusInternalServerError {
+			logger.Error("failed to validate order", "error", err)
+			response.Error(c, statusCode, err, "Failed to validate order")
+			return
+		}
+		response.Success(c, http.StatusOK, dto.ToInvalidOrderResponse(errorDetails(err)), "")
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.ToValidOrderResponse(o), "")
+}
+
+// applyChannelHeader falls back to the X-Channel header when the body omits the channel
+func applyChannelHeader(c *gin.Context, channel *order.Channel) {
+	if *channel == "" {
+		if header := strings.TrimSpace(c.GetHeader("X-Channel")); header != "" {
+			*channel = order.Channel(strings.ToUpper(header))
+		}
+	}
+}
+
 // Track handles GET /api/v1/orders/track/:code
 func (h *OrderHandler) Track(c *gin.Context) {
 	code := c.Param("code")
@@ -77,13 +125,106 @@ func (h *OrderHandler) Track(c *gin.Context) {
 	}
 
 	// Return limited public information
-	response.Success(c, http.StatusOK, dto.ToTrackResponse(o), "")
+	response.Success(c, http.StatusOK, dto.ToTrackResponse(o, includes(c, "notes")), "")
+}
+
+// includes reports whether the comma-separated include query parameter lists the given section
+func includes(c *gin.Context, section string) bool {
+	for _, s := range strings.Split(c.Query("include"), ",") {
+		if strings.EqualFold(strings.TrimSpace(s), section) {
+			return true
+		}
+	}
+	return false
 }
 
-// PartialUpdate handles PATCH /api/v1/orders
+// CustomerEdit handles PATCH /api/v1/orders/track/:code (public)
+// Customers may only change shipping_address and note, shortly after placing the order.
+func (h *OrderHandler) CustomerEdit(c *gin.Context) {
+	code := c.Param("code")
+
+	body, err := c.GetRawData()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	// Reject any field outside the whitelist before decoding
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+	var forbidden []string
+	for field := range fields {
+		if !slices.Contains(order.CustomerEditableFields, field) {
+			forbidden = append(forbidden, field)
+		}
+	}
+	if len(forbidden) > 0 {
+		slices.Sort(forbidden)
+		err := fmt.Errorf("%w: %s", order.ErrCustomerFieldNotEditable, strings.Join(forbidden, ", "))
+		response.Error(c, http.StatusForbidden, err, "Only shipping_address and note can be edited")
+		return
+	}
+
+	var req dto.CustomerEditOrderRequest
+	if err := binding.JSON.BindBody(body, &req); err != nil {
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	o, err := h.service.CustomerEdit(c.Request.Context(), code, req.ToCustomerEditInput())
+	if err != nil {
+		switch {
+		case errors.Is(err, order.ErrCustomerEditDisabled):
+			response.Error(c, http.StatusForbidden, err, "Customer edits are disabled")
+		case errors.Is(err, order.ErrNoCustomerEditFields):
+			response.Error(c, http.StatusBadRequest, err, "Nothing to edit")
+		case errors.Is(err, order.ErrCustomerEditNotAllowed),
+			errors.Is(err, order.ErrCustomerEditWindowExpired):
+			response.Error(c, http.StatusConflict, err, "Order can no longer be edited, please contact the store")
+		default:
+			statusCode := h.mapErrorToStatusCode(err)
+			if statusCode == http.StatusInternalServerError {
+				logger.Error("failed to apply customer edit", "error", err, "code", code)
+			}
+			respondError(c, statusCode, err, "Failed to update order")
+		}
+		return
+	}
+
+	logger.Info("order edited by customer", "order_id", o.ID, "code", o.Code)
+	response.Success(c, http.StatusOK, dto.ToTrackResponse(o, includes(c, "notes")), "Order updated successfully")
+}
+
+// PartialUpdate handles PATCH /api/v1/orders; admins may skip the editable fields policy with ?override=true
 func (h *OrderHandler) PartialUpdate(c *gin.Context) {
+	// The body is read once: checked for products, then bound
+	body, err := c.GetRawData()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	// Check if products are being sent (not allowed in PATCH)
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+	if _, hasProducts := fields["products"]; hasProducts {
+		logger.Warn("products not allowed in PATCH")
+		response.Error(c, http.StatusBadRequest, order.ErrProductsNotAllowedInPatch, "Products cannot be updated via PATCH, use PUT instead")
+		return
+	}
+
 	var req dto.PartialUpdateOrderRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := binding.JSON.BindBody(body, &req); err != nil {
 		logger.Warn("invalid request body", "error", err)
 		// Format validation errors for user-friendly response
 		errorMsg, details := FormatValidationErrors(err)
@@ -103,32 +244,30 @@ func (h *OrderHandler) PartialUpdate(c *gin.Context) {
 		return
 	}
 
-	// Check if products are being sent (not allowed in PATCH)
-	var rawData map[string]interface{}
-	if err := c.ShouldBindJSON(&rawData); err == nil {
-		if _, hasProducts := rawData["products"]; hasProducts {
-			logger.Warn("products not allowed in PATCH", "code", req.Code)
-			response.Error(c, http.StatusBadRequest, order.ErrProductsNotAllowedInPatch, "Products cannot be updated via PATCH, use PUT instead")
-			return
-		}
-	}
-
 	// Convert DTO to service input
 	input := req.ToPartialUpdateInput()
+	input.OverrideFieldPolicy = staffFlag(c, "override")
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err, "Invalid order version")
+		return
+	}
+	input.ExpectedVersion = version
 
 	o, err := h.service.PartialUpdate(c.Request.Context(), req.Code, input)
 	if err != nil {
 		statusCode := h.mapErrorToStatusCode(err)
 		logger.Error("failed to partial update order", "error", err, "code", req.Code)
-		response.Error(c, statusCode, err, "Failed to update order")
+		respondError(c, statusCode, err, "Failed to update order")
 		return
 	}
 
 	logger.Info("order partially updated", "order_id", o.ID, "code", o.Code, "status", o.Status)
+	setVersionTag(c, o.Version)
 	response.Success(c, http.StatusOK, dto.ToOrderResponse(o), "Order updated successfully")
 }
 
-// Modify handles PUT /api/v1/orders
+// Modify handles PUT /api/v1/orders; admins may skip the order size guards with ?override=true
 func (h *OrderHandler) Modify(c *gin.Context) {
 	var req dto.ModifyOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -153,22 +292,37 @@ func (h *OrderHandler) Modify(c *gin.Context) {
 
 	// Convert DTO to service input
 	input := req.ToModifyInput()
+	input.OverrideLimits = staffFlag(c, "override")
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err, "Invalid order version")
+		return
+	}
+	input.ExpectedVersion = version
 
-	o, err := h.service.Modify(c.Request.Context(), req.Code, input)
+	result, err := h.service.Modify(c.Request.Context(), req.Code, input)
 	if err != nil {
 		statusCode := h.mapErrorToStatusCode(err)
 		logger.Error("failed to modify order", "error", err, "code", req.Code)
-		response.Error(c, statusCode, err, "Failed to modify order")
+		respondError(c, statusCode, err, "Failed to modify order")
 		return
 	}
 
-	logger.Info("order modified", "order_id", o.ID, "code", o.Code, "status", o.Status)
-	response.Success(c, http.StatusOK, dto.ToOrderResponse(o), "Order modified successfully")
+	o := result.Order
+	logger.Info("order modified", "order_id", o.ID, "code", o.Code, "status", o.Status, "changed_fields", result.Changes.Fields(), "products_changed", result.Changes.ProductsChanged())
+	setVersionTag(c, o.Version)
+	response.Success(c, http.StatusOK, dto.ToModifyOrderResponse(result), "Order modified successfully")
 }
 
 // GetMetrics handles GET /api/v1/orders/metrics
 func (h *OrderHandler) GetMetrics(c *gin.Context) {
-	filters := h.parseFilters(c)
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+	filters.IncludeArchived = c.Query("include_archived") == "true"
+	filters.ExcludeCancelled = c.Query("exclude_cancelled") == "true"
 
 	metrics, err := h.service.GetMetrics(c.Request.Context(), filters)
 	if err != nil {
@@ -177,27 +331,147 @@ func (h *OrderHandler) GetMetrics(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, dto.ToMetricsResponse(metrics), "")
+	response.Success(c, http.StatusOK, dto.ToMetricsResponse(metrics, filters, h.service.Currency()), "")
+}
+
+// GetProductSales handles GET /api/v1/orders/metrics/products (per-product drill-down)
+func (h *OrderHandler) GetProductSales(c *gin.Context) {
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+	filters.IncludeArchived = c.Query("include_archived") == "true"
+
+	limit, offset, err := parsePagination(c, h.service.PageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+	filters.Limit = limit
+	filters.Offset = offset
+
+	sortBy := order.ProductSalesSort(strings.ToLower(c.Query("sort_by")))
+	sales, total, err := h.service.GetProductSales(c.Request.Context(), filters, sortBy)
+	if err != nil {
+		if errors.Is(err, order.ErrInvalidSortField) {
+			response.Error(c, http.StatusBadRequest, err, "sort_by must be one of: revenue, quantity, orders")
+			return
+		}
+		logger.Error("failed to get product sales", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get product sales")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, sales, total, filters.Limit, filters.Offset)
 }
 
 // GetAll handles GET /api/v1/orders
 func (h *OrderHandler) GetAll(c *gin.Context) {
-	filters := h.parseFilters(c)
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+
+	limit, offset, err := parsePagination(c, h.service.PageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+	filters.Limit = limit
+	filters.Offset = offset
+	if filters.Cursor, err = parseCursor(c); err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+
+	if err := parseSort(c, &filters); err != nil {
+		response.Error(c, http.StatusBadRequest, err, "sort must be one of: created_at, updated_at, total, status; order must be asc or desc")
+		return
+	}
+
+	// Quick search box (a blank box lists everything); /orders/search uses q for its own free text search
+	if query, err := order.NormalizeSearchQuery(c.Query("q")); err == nil {
+		filters.Query = &query
+	} else if errors.Is(err, order.ErrSearchQueryTooLong) {
+		response.Error(c, http.StatusBadRequest, err, "Invalid search query")
+		return
+	}
 
 	orders, total, err := h.service.GetAll(c.Request.Context(), filters)
 	if err != nil {
+		if errors.Is(err, util.ErrInvalidCursor) {
+			response.Error(c, http.StatusBadRequest, err, "Invalid pagination parameters")
+			return
+		}
 		logger.Error("failed to get orders", "error", err)
 		response.Error(c, http.StatusInternalServerError, err, "Failed to get orders")
 		return
 	}
 
-	// Convert to response
-	orderResponses := make([]dto.OrderResponse, len(orders))
-	for i, o := range orders {
-		orderResponses[i] = dto.ToOrderResponse(o)
+	if filters.Cursor != nil {
+		next := nextCursor(orders, filters.Limit, func(o *order.Order) util.PageCursor { return o.PageCursor(filters.SortBy) })
+		response.CursorPaginated(c, http.StatusOK, dto.ToOrderResponses(orders), filters.Limit, next)
+		return
 	}
+	response.Paginated(c, http.StatusOK, dto.ToOrderResponses(orders), total, filters.Limit, filters.Offset)
+}
 
-	response.Paginated(c, http.StatusOK, orderResponses, total, filters.Limit, filters.Offset)
+// GetInfo handles GET /api/v1/info (settings clients need to render amounts)
+func (h *OrderHandler) GetInfo(c *gin.Context) {
+	response.Success(c, http.StatusOK, dto.APIInfoResponse{Currency: h.service.Currency()}, "")
+}
+
+// GetLimits handles GET /api/v1/orders/limits
+func (h *OrderHandler) GetLimits(c *gin.Context) {
+	response.Success(c, http.StatusOK, dto.ToOrderLimitsResponse(h.service.Limits()), "")
+}
+
+// GetStatuses handles GET /api/v1/orders/statuses?sale_type=DELIVERY
+func (h *OrderHandler) GetStatuses(c *gin.Context) {
+	saleType := order.SaleType(c.Query("sale_type"))
+	if err := enumParam("sale_type", saleType, order.AllSaleTypes, order.ErrInvalidSaleType); err != nil {
+		respondQueryError(c, err, "sale_type must be DELIVERY or ON_SITE")
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.ToOrderStatusesResponse(saleType, h.service.Transitions(saleType)), "")
+}
+
+// Search handles GET /api/v1/orders/search?q=... (admin free text search)
+func (h *OrderHandler) Search(c *gin.Context) {
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+
+	limit, offset, err := parsePagination(c, h.service.PageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+	filters.Limit = limit
+	filters.Offset = offset
+
+	results, total, err := h.service.Search(c.Request.Context(), c.Query("q"), filters)
+	if err != nil {
+		if errors.Is(err, order.ErrSearchQueryRequired) || errors.Is(err, order.ErrSearchQueryTooLong) {
+			response.Error(c, http.StatusBadRequest, err, "Invalid search query")
+			return
+		}
+		logger.Error("failed to search orders", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to search orders")
+		return
+	}
+
+	searchResponses := make([]dto.OrderSearchResponse, len(results))
+	for i, r := range results {
+		searchResponses[i] = dto.ToSearchResponse(r)
+	}
+
+	response.Paginated(c, http.StatusOK, searchResponses, total, filters.Limit, filters.Offset)
 }
 
 // GetByCode handles GET /api/v1/orders/:code (internal/admin use)
@@ -215,39 +489,205 @@ func (h *OrderHandler) GetByCode(c *gin.Context) {
 		return
 	}
 
+	setVersionTag(c, o.Version)
+	response.Success(c, http.StatusOK, dto.ToOrderResponse(o), "")
+}
+
+// GetByID handles GET /api/v1/orders/id/:id (internal use)
+func (h *OrderHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	o, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, order.ErrOrderNotFound) {
+			response.Error(c, http.StatusNotFound, err, "Order not found")
+			return
+		}
+		logger.Error("failed to get order", "error", err, "id", id)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get order")
+		return
+	}
+
+	setVersionTag(c, o.Version)
 	response.Success(c, http.StatusOK, dto.ToOrderResponse(o), "")
 }
 
-// parseFilters parses query parameters into OrderFilters
-func (h *OrderHandler) parseFilters(c *gin.Context) order.OrderFilters {
+// RecalculateTotals handles POST /api/v1/admin/orders/recalculate-totals
+// It processes one bounded batch; clients resume with the returned next_cursor.
+func (h *OrderHandler) RecalculateTotals(c *gin.Context) {
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+
+	input := order.RecalculateTotalsInput{
+		Filters: filters,
+		DryRun:  c.DefaultQuery("dry_run", "true") != "false",
+	}
+
+	if batchSizeStr := c.Query("batch_size"); batchSizeStr != "" {
+		batchSize, err := strconv.Atoi(batchSizeStr)
+		if err != nil || batchSize <= 0 {
+			response.Error(c, http.StatusBadRequest, errors.New("batch_size must be a positive integer"), "Invalid batch size")
+			return
+		}
+		input.BatchSize = batchSize
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := dto.DecodeBatchCursor(cursor)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, err, "Invalid cursor")
+			return
+		}
+		input.After = after
+	}
+
+	result, err := h.service.RecalculateTotals(c.Request.Context(), input)
+	if err != nil {
+		logger.Error("failed to recalculate order totals", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to recalculate order totals")
+		return
+	}
+
+	logger.Info("order totals recalculated",
+		"dry_run", result.DryRun,
+		"processed", result.Processed,
+		"discrepancies", len(result.Discrepancies),
+		"fixed", result.Fixed,
+	)
+	response.Success(c, http.StatusOK, dto.ToRecalculateTotalsResponse(result), "")
+}
+
+// Archive handles POST /api/v1/admin/orders/archive?older_than=2023-01-01&dry_run=true
+func (h *OrderHandler) Archive(c *gin.Context) {
+	olderThanStr := c.Query("older_than")
+	olderThan, err := time.Parse("2006-01-02", olderThanStr)
+	if err != nil {
+		if olderThan, err = time.Parse(time.RFC3339, olderThanStr); err != nil {
+			response.Error(c, http.StatusBadRequest, errors.New("older_than must be a date (YYYY-MM-DD) or RFC3339 timestamp"), "Invalid cutoff")
+			return
+		}
+	}
+
+	input := order.ArchiveInput{
+		OlderThan: olderThan,
+		DryRun:    c.DefaultQuery("dry_run", "true") != "false",
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := dto.DecodeBatchCursor(cursor)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, err, "Invalid cursor")
+			return
+		}
+		input.After = after
+	}
+
+	result, err := h.service.Archive(c.Request.Context(), input)
+	if err != nil {
+		if errors.Is(err, order.ErrArchiveCutoffTooRecent) {
+			response.Error(c, http.StatusBadRequest, err, "Invalid cutoff")
+			return
+		}
+		logger.Error("failed to archive orders", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to archive orders")
+		return
+	}
+
+	logger.Info("orders archived",
+		"dry_run", result.DryRun,
+		"older_than", result.OlderThan,
+		"batches", result.Batches,
+		"scanned", result.Scanned,
+		"archived", result.Archived,
+	)
+	response.Success(c, http.StatusOK, dto.ToArchiveOrdersResponse(result), "")
+}
+
+// parseFilters parses query parameters into OrderFilters (pagination is parsed separately).
+// Dates may be RFC3339 or YYYY-MM-DD in the service's filter time zone; invalid dates are an error.
+func (h *OrderHandler) parseFilters(c *gin.Context) (order.OrderFilters, error) {
 	filters := order.OrderFilters{}
 
-	// Parse pagination
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	filters.Limit = limit
-	filters.Offset = offset
+	// Parse tenant filters
+	if companyID := c.Query("company_id"); companyID != "" {
+		filters.CompanyID = &companyID
+	}
+	if salePointID := c.Query("sale_point_id"); salePointID != "" {
+		filters.SalePointID = &salePointID
+	}
 
-	// Parse date filters
+	// Parse date filters; date-only values cover the whole day
+	loc := h.service.FilterLocation()
 	if dateFrom := c.Query("date_from"); dateFrom != "" {
-		filters.DateFrom = &dateFrom
+		from, err := order.ParseDateBound(dateFrom, false, loc)
+		if err != nil {
+			return filters, fmt.Errorf("date_from: %w", err)
+		}
+		formatted := from.Format(time.RFC3339Nano)
+		filters.DateFrom = &formatted
 	}
 	if dateTo := c.Query("date_to"); dateTo != "" {
-		filters.DateTo = &dateTo
+		to, err := order.ParseDateBound(dateTo, true, loc)
+		if err != nil {
+			return filters, fmt.Errorf("date_to: %w", err)
+		}
+		formatted := to.Format(time.RFC3339Nano)
+		filters.DateTo = &formatted
 	}
 
-	// Parse status filter
+	// Parse status filter (comma-separated for several statuses); unknown statuses are rejected
+	// rather than silently matching nothing
 	if statusStr := c.Query("status"); statusStr != "" {
-		status := order.OrderStatus(statusStr)
-		filters.Status = &status
+		statuses := strings.Split(statusStr, ",")
+		if len(statuses) == 1 {
+			status := order.OrderStatus(statusStr)
+			if err := enumParam("status", status, order.AllStatuses, order.ErrInvalidStatus); err != nil {
+				return filters, err
+			}
+			filters.Status = &status
+		} else {
+			for _, s := range statuses {
+				if s = strings.TrimSpace(s); s != "" {
+					status := order.OrderStatus(s)
+					if err := enumParam("status", status, order.AllStatuses, order.ErrInvalidStatus); err != nil {
+						return filters, err
+					}
+					filters.Statuses = append(filters.Statuses, status)
+				}
+			}
+		}
 	}
 
 	// Parse sale type filter
 	if saleTypeStr := c.Query("sale_type"); saleTypeStr != "" {
 		saleType := order.SaleType(saleTypeStr)
+		if err := enumParam("sale_type", saleType, order.AllSaleTypes, order.ErrInvalidSaleType); err != nil {
+			return filters, err
+		}
 		filters.SaleType = &saleType
 	}
 
+	// Parse channel filter
+	if channelStr := c.Query("channel"); channelStr != "" {
+		channel := order.Channel(channelStr)
+		if err := enumParam("channel", channel, order.AllChannels, order.ErrInvalidChannel); err != nil {
+			return filters, err
+		}
+		filters.Channel = &channel
+	}
+
+	// Parse payment status filter
+	if paymentStr := c.Query("payment_status"); paymentStr != "" {
+		payment := order.PaymentStatus(paymentStr)
+		if err := enumParam("payment_status", payment, order.AllPaymentStatuses, order.ErrInvalidPaymentStatus); err != nil {
+			return filters, err
+		}
+		filters.PaymentStatus = &payment
+	}
+
 	// Parse product filters
 	if productID := c.Query("product_id"); productID != "" {
 		filters.ProductID = &productID
@@ -257,18 +697,38 @@ func (h *OrderHandler) parseFilters(c *gin.Context) order.OrderFilters {
 	}
 
 	// Parse total filters
-	if minTotalStr := c.Query("min_total"); minTotalStr != "" {
-		if minTotal, err := strconv.ParseInt(minTotalStr, 10, 64); err == nil {
-			filters.MinTotal = &minTotal
+	if minTotal, ok, err := intParam(c, "min_total"); err != nil {
+		return filters, err
+	} else if ok {
+		filters.MinTotal = &minTotal
+	}
+	if maxTotal, ok, err := intParam(c, "max_total"); err != nil {
+		return filters, err
+	} else if ok {
+		filters.MaxTotal = &maxTotal
+	}
+
+	// Listings can skip the total count when the client doesn't need it
+	filters.SkipCount = c.Query("skip_count") == "true"
+
+	return filters, nil
+}
+
+// parseSort parses the sort and order query parameters of order listings (default created_at desc)
+func parseSort(c *gin.Context, filters *order.OrderFilters) error {
+	if sortBy := strings.ToLower(c.Query("sort")); sortBy != "" {
+		filters.SortBy = order.OrderSortField(sortBy)
+		if !order.IsValidOrderSortField(filters.SortBy) {
+			return fmt.Errorf("%w: %s", order.ErrInvalidSortField, sortBy)
 		}
 	}
-	if maxTotalStr := c.Query("max_total"); maxTotalStr != "" {
-		if maxTotal, err := strconv.ParseInt(maxTotalStr, 10, 64); err == nil {
-			filters.MaxTotal = &maxTotal
+	if dir := strings.ToLower(c.Query("order")); dir != "" {
+		filters.SortDir = order.SortDirection(dir)
+		if !order.IsValidSortDirection(filters.SortDir) {
+			return fmt.Errorf("%w: %s", order.ErrInvalidSortDir, dir)
 		}
 	}
-
-	return filters
+	return nil
 }
 
 // mapErrorToStatusCode maps domain errors to HTTP status codes
@@ -280,25 +740,50 @@ func (h *OrderHandler) mapErrorToStatusCode(err error) int {
 		return http.StatusConflict
 	case errors.Is(err, order.ErrOrderCannotBeModified):
 		return http.StatusConflict
-	case errors.Is(err, order.ErrOrderCodeAlreadyExists):
+	case errors.Is(err, order.ErrFieldNotEditable):
+		return http.StatusConflict
+	case errors.Is(err, order.ErrOrderCodeAlreadyExists),
+		errors.Is(err, order.ErrOrderConflict):
+		return http.StatusConflict
+	case errors.Is(err, order.ErrInvalidPaymentTransition),
+		errors.Is(err, order.ErrPaymentNotConfirmed):
 		return http.StatusConflict
 	case errors.Is(err, order.ErrNoProducts),
+		errors.Is(err, order.ErrCompanyIDRequired),
+		errors.Is(err, order.ErrSalePointIDRequired),
 		errors.Is(err, order.ErrInvalidProductID),
 		errors.Is(err, order.ErrInvalidProductName),
 		errors.Is(err, order.ErrInvalidProductQuantity),
 		errors.Is(err, order.ErrInvalidProductPrice),
 		errors.Is(err, order.ErrDuplicateProduct),
+		errors.Is(err, order.ErrInvalidAddonID),
+		errors.Is(err, order.ErrInvalidAddonName),
+		errors.Is(err, order.ErrInvalidAddonPrice),
+		errors.Is(err, order.ErrInvalidAddonQuantity),
+		errors.Is(err, order.ErrDuplicateAddon),
 		errors.Is(err, order.ErrCustomerRequiredForDelivery),
 		errors.Is(err, order.ErrCustomerNameRequired),
 		errors.Is(err, order.ErrCustomerPhoneRequired),
 		errors.Is(err, order.ErrShippingAddressRequired),
 		errors.Is(err, order.ErrTableNumberRequiredForOnSite),
 		errors.Is(err, order.ErrInvalidSaleType),
+		errors.Is(err, order.ErrInvalidChannel),
+		errors.Is(err, order.ErrInvalidNoteVisibility),
 		errors.Is(err, order.ErrInvalidStatus),
-		errors.Is(err, order.ErrTotalMismatch):
+		errors.Is(err, order.ErrTotalMismatch),
+		errors.Is(err, order.ErrBlankText),
+		errors.Is(err, order.ErrTextTooLong),
+		errors.Is(err, order.ErrReceiptHostNotAllowed),
+		errors.Is(err, order.ErrInvalidPhone),
+		errors.Is(err, order.ErrMaxLinesExceeded),
+		errors.Is(err, order.ErrMaxLineQuantityExceeded),
+		errors.Is(err, order.ErrMaxTotalExceeded),
+		errors.Is(err, order.ErrOrderTooLarge):
 		return http.StatusUnprocessableEntity
 	case errors.Is(err, order.ErrProductsNotAllowedInPatch):
 		return http.StatusBadRequest
+	case isDomainError(err):
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
 	}
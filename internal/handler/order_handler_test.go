@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/gin-gonic/gin"
+)
+
+// testAdminToken authenticates requests built with admin set in serveJSON
+const testAdminToken = "test-admin-token-0123456789"
+
+// newOrderRouter serves the order routes under test with the production paths and admin authentication
+func newOrderRouter(service order.ServiceAPI) *gin.Engine {
+	h := NewOrderHandler(service)
+	router := gin.New()
+	router.Use(customhttp.Authenticate(testAdminToken))
+	orders := router.Group("/api/v1/orders")
+	orders.GET("", h.GetAll)
+	orders.GET("/search", h.Search)
+	orders.GET("/metrics", h.GetMetrics)
+	orders.GET("/metrics/export", h.ExportMetrics)
+	orders.GET("/metrics/products", h.GetProductSales)
+	orders.POST("", h.Create)
+	orders.POST("/validate", h.Validate)
+	orders.PUT("", h.Modify)
+	orders.PATCH("", h.PartialUpdate)
+	return router
+}
+
+// serveJSON sends a request with a JSON body, with the admin token when admin is set
+func serveJSON(router *gin.Engine, method, target, body string, admin bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if admin {
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetMetricsEchoesAppliedFilters(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		metrics     order.OrderMetrics
+		wantCount   string
+		wantFilters string
+	}{
+		{
+			name:        "zero matching orders",
+			query:       "?status=DELIVERED&date_from=2024-05-01T00:00:00Z&date_to=2024-05-01T23:59:59Z",
+			wantCount:   `"order_count":0`,
+			wantFilters: `"filters":{"date_from":"2024-05-01T00:00:00Z","date_to":"2024-05-01T23:59:59Z","status":"DELIVERED"}`,
+		},
+		{
+			name:        "single order",
+			query:       "?sale_type=ON_SITE",
+			metrics:     order.OrderMetrics{OrderCount: 1, TotalSales: 0},
+			wantCount:   `"order_count":1`,
+			wantFilters: `"filters":{"sale_type":"ON_SITE"}`,
+		},
+		{
+			name:        "large window",
+			query:       "?date_from=2000-01-01T00:00:00Z&date_to=2030-12-31T23:59:59Z",
+			metrics:     order.OrderMetrics{OrderCount: 3_000_000_000, TotalSales: 9_000_000_000_000_000},
+			wantCount:   `"order_count":3000000000`,
+			wantFilters: `"filters":{"date_from":"2000-01-01T00:00:00Z","date_to":"2030-12-31T23:59:59Z"}`,
+		},
+		{
+			name:        "no filters",
+			wantCount:   `"order_count":0`,
+			wantFilters: `"filters":{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+					m := tt.metrics
+					return &m, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders/metrics"+tt.query, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			for _, want := range []string{tt.wantCount, tt.wantFilters} {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+// TestPartialUpdateErrorMapping covers the service error to status mapping without a database
+func TestPartialUpdateErrorMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"not found", order.ErrOrderNotFound, http.StatusNotFound},
+		{"invalid transition", fmt.Errorf("%w: CREATED -> DELIVERED", order.ErrInvalidStatusTransition), http.StatusConflict},
+		{"cannot be modified", order.ErrOrderCannotBeModified, http.StatusConflict},
+		{"version conflict", order.ErrOrderConflict, http.StatusConflict},
+		{"validation", fmt.Errorf("validation error: %w", order.ErrBlankText), http.StatusUnprocessableEntity},
+		{"unexpected", fmt.Errorf("connection reset"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				PartialUpdateFunc: func(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPatch, "/api/v1/orders", `{"code": "ORD-7F3A00", "status": "CANCELLED"}`, false)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPartialUpdateBlockedFieldsAre409(t *testing.T) {
+	const body = `{"code": "ORD-7F3A00", "note": "late", "payment_receipt_url": "https://cdn.example.com/r.png"}`
+	service := &mocks.OrderService{
+		PartialUpdateFunc: func(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error) {
+			return nil, apperrors.DomainErrors{
+				apperrors.NewDomainError(order.ErrFieldNotEditable, order.FieldNote, order.StatusCancelled),
+				apperrors.NewDomainError(order.ErrFieldNotEditable, order.FieldPaymentReceiptURL, order.StatusCancelled),
+			}
+		},
+	}
+
+	w := serveJSON(newOrderRouter(service), http.MethodPatch, "/api/v1/orders", body, false)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409 (body %s)", w.Code, w.Body.String())
+	}
+	for _, field := range []string{`"field":"note"`, `"field":"payment_receipt_url"`} {
+		if !strings.Contains(w.Body.String(), field) {
+			t.Errorf("details miss %s: %s", field, w.Body.String())
+		}
+	}
+}
+
+// pageService returns the given orders for any list request
+func pageService(orders []*order.Order, total int64) *mocks.OrderService {
+	return &mocks.OrderService{
+		GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+			return orders, total, nil
+		},
+	}
+}
+
+// TestGetAllGolden pins the exact bytes of an order list page, whichever JSON encoder gin is built with
+func TestGetAllGolden(t *testing.T) {
+	router := newOrderRouter(pageService(mocks.SampleOrders(20), 57))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/orders?limit=20&offset=20", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	assertGolden(t, "orders_page.golden", w.Body.Bytes())
+}
+
+// BenchmarkGetAllHandler measures GET /api/v1/orders?limit=100 without the database: conversion and JSON encoding
+func BenchmarkGetAllHandler(b *testing.B) {
+	router := newOrderRouter(pageService(mocks.SampleOrders(100), 1000))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?limit=100", nil)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			b.Fatalf("status = %d", w.Code)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportMetrics handles GET /api/v1/orders/metrics/export
+// It renders the same metrics as GetMetrics as a CSV file with two sections:
+// a summary table and the top products table. Raw amounts are in the currency's minor unit
+// (*_cents columns); the unsuffixed columns are the same amounts formatted in the configured currency.
+func (h *OrderHandler) ExportMetrics(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" {
+		response.Error(c, http.StatusBadRequest, fmt.Errorf("unsupported export format: %s", format), "Only csv format is supported")
+		return
+	}
+
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+	filters.IncludeArchived = c.Query("include_archived") == "true"
+	filters.ExcludeCancelled = c.Query("exclude_cancelled") == "true"
+
+	metrics, err := h.service.GetMetrics(c.Request.Context(), filters)
+	if err != nil {
+		logger.Error("failed to get metrics for export", "error", err)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to export metrics")
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, metricsExportFilename(filters)))
+	c.Status(http.StatusOK)
+
+	if err := writeMetricsCSV(c.Writer, metrics, h.service.Currency()); err != nil {
+		// Headers are already sent, so the error can only be logged
+		logger.Error("failed to write metrics export", "error", err)
+	}
+}
+
+// writeMetricsCSV writes the summary and top products sections separated by an empty line
+func writeMetricsCSV(w io.Writer, m *order.OrderMetrics, currency money.Currency) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"metric", "value"},
+		{"currency", currency.Code},
+		{"order_count", strconv.FormatInt(m.OrderCount, 10)},
+		{"total_sales_cents", strconv.FormatInt(m.TotalSales, 10)},
+		{"total_sales", currency.Format(m.TotalSales)},
+		{"total_discounts_cents", strconv.FormatInt(m.TotalDiscounts, 10)},
+		{"avg_ticket_cents", strconv.FormatInt(m.AvgTicket, 10)},
+		{"avg_ticket", currency.Format(m.AvgTicket)},
+	}
+	for _, status := range order.AllStatuses {
+		rows = append(rows, []string{"orders_" + string(status), strconv.Itoa(m.OrdersByStatus[status])})
+	}
+	if count, ok := m.OrdersByStatus[order.StatusUnknown]; ok {
+		rows = append(rows, []string{"orders_" + string(order.StatusUnknown), strconv.Itoa(count)})
+	}
+	for _, channel := range order.AllChannels {
+		rows = append(rows, []string{"channel_" + string(channel), strconv.Itoa(m.OrdersByChannel[channel])})
+	}
+	if count, ok := m.OrdersByChannel[order.ChannelUnknown]; ok {
+		rows = append(rows, []string{"channel_" + string(order.ChannelUnknown), strconv.Itoa(count)})
+	}
+	for _, status := range order.AllPaymentStatuses {
+		rows = append(rows, []string{"payment_" + string(status), strconv.Itoa(m.OrdersByPayment[status])})
+	}
+	if count, ok := m.OrdersByPayment[order.PaymentUnknown]; ok {
+		rows = append(rows, []string{"payment_" + string(order.PaymentUnknown), strconv.Itoa(count)})
+	}
+	saleTypes := order.AllSaleTypes
+	if _, ok := m.OrdersBySaleType[order.SaleTypeUnknown]; ok {
+		saleTypes = append(slices.Clone(saleTypes), order.SaleTypeUnknown)
+	}
+	for _, saleType := range saleTypes {
+		st := m.OrdersBySaleType[saleType]
+		prefix := "sale_type_" + string(saleType)
+		rows = append(rows,
+			[]string{prefix + "_orders", strconv.FormatInt(st.OrderCount, 10)},
+			[]string{prefix + "_sales_cents", strconv.FormatInt(st.TotalSales, 10)},
+			[]string{prefix + "_avg_ticket_cents", strconv.FormatInt(st.AvgTicket, 10)},
+		)
+	}
+	for _, d := range m.StageDurations {
+		rows = append(rows,
+			[]string{"stage_" + d.Key() + "_avg_seconds", strconv.FormatInt(d.AvgSeconds, 10)},
+			[]string{"stage_" + d.Key() + "_orders", strconv.FormatInt(d.OrderCount, 10)},
+		)
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	// Blank line between sections
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+
+	if err := cw.Write([]string{"product_id", "name", "total_quantity", "total_revenue_cents", "total_revenue", "addon_revenue_cents"}); err != nil {
+		return err
+	}
+	for _, p := range m.TopProducts {
+		if err := cw.Write([]string{
+			p.ProductID,
+			p.Name,
+			strconv.Itoa(p.TotalQuantity),
+			strconv.FormatInt(p.TotalRevenue, 10),
+			currency.Format(p.TotalRevenue),
+			strconv.FormatInt(p.AddonRevenue, 10),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// metricsExportFilename builds the attachment filename from the applied date range
+func metricsExportFilename(filters order.OrderFilters) string {
+	from, to := "start", "now"
+	dateFrom, dateTo := filters.ParseDateRange()
+	if dateFrom != nil {
+		from = dateFrom.Format("2006-01-02")
+	}
+	if dateTo != nil {
+		to = dateTo.Format("2006-01-02")
+	}
+	return fmt.Sprintf("order-metrics_%s_%s.csv", from, to)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/money"
+)
+
+// exportMetrics has amounts that need decimals and names that need CSV escaping
+var exportMetrics = order.OrderMetrics{
+	OrderCount:      3,
+	TotalSales:      1234567,
+	TotalDiscounts:  1500,
+	AvgTicket:       411522,
+	OrdersByStatus:  map[order.OrderStatus]int{order.StatusDelivered: 2, order.StatusCancelled: 1},
+	OrdersByChannel: map[order.Channel]int{order.ChannelPOS: 2, order.ChannelUnknown: 1},
+	OrdersBySaleType: map[order.SaleType]order.SaleTypeMetrics{
+		order.SaleTypeOnSite: {OrderCount: 3, TotalSales: 1234567, AvgTicket: 411522},
+	},
+	OrdersByPayment: map[order.PaymentStatus]int{order.PaymentConfirmed: 3},
+	TopProducts: []order.ProductSalesSummary{
+		{ProductID: "p1", Name: `Hamburguesa "doble", con queso`, TotalQuantity: 4, TotalRevenue: 1000050, AddonRevenue: 50},
+		{ProductID: "p2", Name: "Limonada\nde coco", TotalQuantity: 1, TotalRevenue: 7},
+	},
+}
+
+func TestWriteMetricsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeMetricsCSV(&buf, &exportMetrics, money.Currency{Code: "USD", MinorUnits: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != wantMetricsCSV {
+		t.Errorf("csv =\n%s\nwant\n%s", got, wantMetricsCSV)
+	}
+}
+
+func TestMetricsExportFilename(t *testing.T) {
+	from, to := "2024-05-01T00:00:00Z", "2024-05-07T23:59:59Z"
+	tests := []struct {
+		name    string
+		filters order.OrderFilters
+		want    string
+	}{
+		{"date range", order.OrderFilters{DateFrom: &from, DateTo: &to}, "order-metrics_2024-05-01_2024-05-07.csv"},
+		{"open range", order.OrderFilters{}, "order-metrics_start_now.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := metricsExportFilename(tt.filters); got != tt.want {
+				t.Errorf("filename = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// wantMetricsCSV has the formatted amounts in dollars, every status, channel, payment status and sale type, the UNKNOWN bucket, the blank line between sections and names escaped for CSV
+const wantMetricsCSV = `metric,value
+currency,USD
+order_count,3
+total_sales_cents,1234567
+total_sales,12345.67
+total_discounts_cents,1500
+avg_ticket_cents,411522
+avg_ticket,4115.22
+orders_CREATED,0
+orders_VERIFIED,0
+orders_IN_PROGRESS,0
+orders_OUT_FOR_DELIVERY,0
+orders_DELIVERED,2
+orders_CANCELLED,1
+channel_WEB,0
+channel_POS,2
+channel_WHATSAPP,0
+channel_PHONE,0
+channel_OTHER,0
+channel_UNKNOWN,1
+payment_PENDING,0
+payment_RECEIPT_UPLOADED,0
+payment_CONFIRMED,3
+payment_REJECTED,0
+payment_REFUNDED,0
+sale_type_DELIVERY_orders,0
+sale_type_DELIVERY_sales_cents,0
+sale_type_DELIVERY_avg_ticket_cents,0
+sale_type_ON_SITE_orders,3
+sale_type_ON_SITE_sales_cents,1234567
+sale_type_ON_SITE_avg_ticket_cents,411522
+
+product_id,name,total_quantity,total_revenue_cents,total_revenue,addon_revenue_cents
+p1,"Hamburguesa ""doble"", con queso",4,1000050,10000.50,50
+p2,"Limonada
+de coco",1,7,0.07,0
+`
+
+func TestExportMetricsRejectsUnknownFormat(t *testing.T) {
+	service := &mocks.OrderService{}
+
+	w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders/metrics/export?format=xlsx", "", false)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestMetricsKeepUnknownBuckets(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{"json", "/api/v1/orders/metrics", []string{`"UNKNOWN":2`, `"DELIVERED":1`}},
+		{"csv", "/api/v1/orders/metrics/export", []string{"orders_UNKNOWN,2", "channel_UNKNOWN,1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+					m := exportMetrics
+					m.OrdersByStatus = map[order.OrderStatus]int{order.StatusDelivered: 1, order.StatusUnknown: 2}
+					return &m, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, tt.target, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestMetricsBySaleType(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		metrics     order.OrderMetrics
+		wantExclude bool
+		wantBody    []string
+	}{
+		{
+			name:   "breakdown per sale type",
+			target: "/api/v1/orders/metrics",
+			metrics: order.OrderMetrics{OrdersBySaleType: map[order.SaleType]order.SaleTypeMetrics{
+				order.SaleTypeDelivery: {OrderCount: 2, TotalSales: 30000, AvgTicket: 15000},
+			}},
+			wantBody: []string{`"orders_by_sale_type":{"DELIVERY":{"order_count":2,"total_sales":30000,"avg_ticket":15000}}`},
+		},
+		{
+			name:     "empty breakdown is an object",
+			target:   "/api/v1/orders/metrics",
+			metrics:  order.OrderMetrics{OrdersBySaleType: map[order.SaleType]order.SaleTypeMetrics{}},
+			wantBody: []string{`"orders_by_sale_type":{}`},
+		},
+		{
+			name:        "cancelled orders excluded",
+			target:      "/api/v1/orders/metrics?exclude_cancelled=true",
+			wantExclude: true,
+			wantBody:    []string{`"exclude_cancelled":true`},
+		},
+		{
+			name:   "only true excludes",
+			target: "/api/v1/orders/metrics?exclude_cancelled=yes",
+		},
+		{
+			name:        "export excludes cancelled orders",
+			target:      "/api/v1/orders/metrics/export?exclude_cancelled=true",
+			wantExclude: true,
+			wantBody:    []string{"sale_type_DELIVERY_orders,0", "sale_type_ON_SITE_orders,0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got order.OrderFilters
+			service := &mocks.OrderService{
+				GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+					got = filters
+					m := tt.metrics
+					return &m, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, tt.target, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got.ExcludeCancelled != tt.wantExclude {
+				t.Errorf("ExcludeCancelled = %v, want %v", got.ExcludeCancelled, tt.wantExclude)
+			}
+			if !tt.wantExclude && strings.Contains(w.Body.String(), "exclude_cancelled") {
+				t.Errorf("filters echo exclude_cancelled: %s", w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
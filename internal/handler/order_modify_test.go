@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestModifyResponseChanges(t *testing.T) {
+	const body = `{"code": "ORD-7F3A00", "products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]}`
+	address := "Calle 5"
+
+	tests := []struct {
+		name     string
+		changes  order.OrderDiff
+		wantJSON []string
+	}{
+		{"nothing changed", order.OrderDiff{PreviousTotal: 5000, Total: 5000},
+			[]string{`"fields":[]`, `"products":{"added":[],"removed":[],"modified":[]}`, `"total":{"previous":5000,"new":5000,"delta":0}`, `"products_changed":false`}},
+		{"lines changed", order.OrderDiff{
+			Added:         []order.OrderProduct{{ID: "p3", Name: "Fries", Price: 500, Quantity: 1}},
+			Removed:       []order.OrderProduct{{ID: "p2", Name: "Soda", Price: 300, Quantity: 1}},
+			Modified:      []order.LineChange{{ID: "p1", Name: "Burger", PreviousQuantity: 2, Quantity: 1, PreviousPrice: 1000, Price: 1000}},
+			PreviousTotal: 2300, Total: 1500,
+		}, []string{`"fields":["products"]`, `"id":"p3"`, `"id":"p2"`, `"previous_quantity":2,"quantity":1`, `"delta":-800`, `"products_changed":true`}},
+		{"address changed", order.OrderDiff{ShippingAddress: &order.TextChange{New: &address}, PreviousTotal: 1000, Total: 1000},
+			[]string{`"fields":["shipping_address"]`, `"shipping_address":{"previous":null,"new":"Calle 5"}`, `"products_changed":false`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				ModifyFunc: func(ctx context.Context, code string, input order.ModifyInput) (*order.ModifyResult, error) {
+					return &order.ModifyResult{Order: mocks.SampleOrders(1)[0], Changes: tt.changes}, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPut, "/api/v1/orders", body, true)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			var envelope struct {
+				Data struct {
+					Code    string          `json:"code"`
+					Changes json.RawMessage `json:"changes"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+				t.Fatal(err)
+			}
+			if envelope.Data.Code == "" || len(envelope.Data.Changes) == 0 {
+				t.Fatalf("response misses the order or its changes: %s", w.Body.String())
+			}
+			for _, want := range tt.wantJSON {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("response misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
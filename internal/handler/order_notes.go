@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AddNote handles POST /api/v1/orders/:code/notes.
+// Notes are append-only; blank or too long notes and orders at the note limit answer 422 with details.
+func (h *OrderHandler) AddNote(c *gin.Context) {
+	code := c.Param("code")
+
+	var req dto.AddOrderNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	o, err := h.service.AddNote(c.Request.Context(), code, req.ToAddNoteInput())
+	if err != nil {
+		statusCode := h.mapErrorToStatusCode(err)
+		if statusCode == http.StatusInternalServerError {
+			logger.Error("failed to add order note", "error", err, "code", code)
+		}
+		respondError(c, statusCode, err, "Failed to add note")
+		return
+	}
+
+	logger.Info("order note added", "order_id", o.ID, "code", o.Code, "notes", len(o.Notes))
+	response.Success(c, http.StatusCreated, dto.ToOrderResponse(o), "Note added successfully")
+}
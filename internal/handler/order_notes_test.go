@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestAddNote(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantInput  order.AddNoteInput
+		wantBody   []string
+	}{
+		{
+			name:       "appended",
+			body:       `{"text": "no onions confirmed", "author": "kitchen"}`,
+			wantStatus: http.StatusCreated,
+			wantInput:  order.AddNoteInput{Text: "no onions confirmed", Author: "kitchen"},
+			wantBody:   []string{`"notes":[{"text":"customer called"`, `{"text":"no onions confirmed","author":"kitchen","visibility":"INTERNAL","created_at":"2024-06-01T12:05:00Z"}`},
+		},
+		{
+			name:       "public note",
+			body:       `{"text": "ring twice", "visibility": "PUBLIC"}`,
+			wantStatus: http.StatusCreated,
+			wantInput:  order.AddNoteInput{Text: "ring twice", Visibility: order.NotePublic},
+		},
+		{"missing text", `{"author": "kitchen"}`, nil, http.StatusBadRequest, order.AddNoteInput{}, []string{`"field":"text"`}},
+		{"text too long", `{"text": "` + strings.Repeat("a", 501) + `"}`, nil, http.StatusBadRequest, order.AddNoteInput{}, []string{`"field":"text"`}},
+		{"author too long", `{"text": "ok", "author": "` + strings.Repeat("a", 101) + `"}`, nil, http.StatusBadRequest, order.AddNoteInput{}, []string{`"field":"author"`}},
+		{"unknown visibility", `{"text": "ok", "visibility": "SECRET"}`, nil, http.StatusBadRequest, order.AddNoteInput{}, []string{`"field":"visibility"`}},
+		{"not JSON", `text=ok`, nil, http.StatusBadRequest, order.AddNoteInput{}, nil},
+		{
+			name:       "note limit reached",
+			body:       `{"text": "one more"}`,
+			err:        fmt.Errorf("validation error: %w", apperrors.NewDomainError(order.ErrTooManyNotes, "notes", 50)),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantInput:  order.AddNoteInput{Text: "one more"},
+			wantBody:   []string{`"field":"notes"`},
+		},
+		{
+			name:       "blank after sanitizing",
+			body:       `{"text": "\u0000"}`,
+			err:        fmt.Errorf("validation error: %w", apperrors.NewDomainError(order.ErrBlankText, "note", nil)),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantInput:  order.AddNoteInput{Text: "\x00"},
+			wantBody:   []string{`"field":"note"`},
+		},
+		{
+			name:       "delivered order",
+			body:       `{"text": "late"}`,
+			err:        apperrors.NewDomainError(order.ErrFieldNotEditable, order.FieldNote, order.StatusDelivered),
+			wantStatus: http.StatusConflict,
+			wantInput:  order.AddNoteInput{Text: "late"},
+		},
+		{"unknown order", `{"text": "late"}`, order.ErrOrderNotFound, http.StatusNotFound, order.AddNoteInput{Text: "late"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.AddNoteInput
+			service := &mocks.OrderService{
+				AddNoteFunc: func(ctx context.Context, code string, input order.AddNoteInput) (*order.Order, error) {
+					got = &input
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					o := mocks.SampleOrders(1)[0]
+					legacy := "customer called"
+					o.Code, o.Note = code, &legacy
+					o.Notes = []order.OrderNote{{Text: input.Text, Author: input.Author, Visibility: order.NoteInternal, CreatedAt: time.Date(2024, 6, 1, 12, 5, 0, 0, time.UTC)}}
+					return o, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.POST("/api/v1/orders/:code/notes", NewOrderHandler(service).AddNote)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/orders/ORD-7KQ2M9/notes", tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+			if tt.wantStatus == http.StatusBadRequest {
+				if got != nil {
+					t.Error("service called with an invalid request")
+				}
+				return
+			}
+			if got == nil || *got != tt.wantInput {
+				t.Errorf("input = %+v, want %+v", got, tt.wantInput)
+			}
+		})
+	}
+}
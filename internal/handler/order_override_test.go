@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestOverrideLimitsIsStaffOnly(t *testing.T) {
+	const (
+		createBody = `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+			"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 500}]}`
+		modifyBody = `{"code": "ORD-7F3A00", "products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 500}]}`
+	)
+
+	tests := []struct {
+		name  string
+		query string
+		admin bool
+		want  bool
+	}{
+		{"admin override", "?override=true", true, true},
+		{"anonymous override is ignored", "?override=true", false, false},
+		{"admin without the flag", "", true, false},
+		{"flag not true", "?override=1", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]bool{}
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					got["create"] = input.OverrideLimits
+					return mocks.SampleOrders(1)[0], nil
+				},
+				ValidateCreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					got["validate"] = input.OverrideLimits
+					return mocks.SampleOrders(1)[0], nil
+				},
+				ModifyFunc: func(ctx context.Context, code string, input order.ModifyInput) (*order.ModifyResult, error) {
+					got["modify"] = input.OverrideLimits
+					return &order.ModifyResult{Order: mocks.SampleOrders(1)[0]}, nil
+				},
+			}
+			router := newOrderRouter(service)
+
+			requests := []struct{ method, path, body, call string }{
+				{http.MethodPost, "/api/v1/orders", createBody, "create"},
+				{http.MethodPost, "/api/v1/orders/validate", createBody, "validate"},
+				{http.MethodPut, "/api/v1/orders", modifyBody, "modify"},
+			}
+			for _, r := range requests {
+				w := serveJSON(router, r.method, r.path+tt.query, r.body, tt.admin)
+				if w.Code >= http.StatusBadRequest {
+					t.Fatalf("%s status = %d, body %s", r.call, w.Code, w.Body.String())
+				}
+				if override, called := got[r.call]; !called || override != tt.want {
+					t.Errorf("%s override = %v (called %v), want %v", r.call, override, called, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOverrideFieldPolicyIsStaffOnly(t *testing.T) {
+	const body = `{"code": "ORD-7F3A00", "payment_receipt_url": "https://example.com/receipt.png"}`
+
+	tests := []struct {
+		name  string
+		query string
+		admin bool
+		want  bool
+	}{
+		{"admin override", "?override=true", true, true},
+		{"anonymous override is ignored", "?override=true", false, false},
+		{"admin without the flag", "", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.PartialUpdateInput
+			service := &mocks.OrderService{
+				PartialUpdateFunc: func(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error) {
+					got = &input
+					return mocks.SampleOrders(1)[0], nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPatch, "/api/v1/orders"+tt.query, body, tt.admin)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got == nil || got.OverrideFieldPolicy != tt.want {
+				t.Errorf("input = %+v, want OverrideFieldPolicy %v", got, tt.want)
+			}
+		})
+	}
+}
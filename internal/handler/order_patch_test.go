@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestPartialUpdateBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantInput  func(t *testing.T, code string, input order.PartialUpdateInput) // nil when the service must not be called
+	}{
+		{"products rejected", `{"code": "ORD-7F3A00", "status": "VERIFIED", "products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]}`,
+			http.StatusBadRequest, order.ErrProductsNotAllowedInPatch.Error(), nil},
+		{"empty products rejected", `{"code": "ORD-7F3A00", "products": []}`, http.StatusBadRequest, order.ErrProductsNotAllowedInPatch.Error(), nil},
+		{"null products rejected", `{"code": "ORD-7F3A00", "products": null}`, http.StatusBadRequest, order.ErrProductsNotAllowedInPatch.Error(), nil},
+		{"products checked before validation", `{"products": []}`, http.StatusBadRequest, order.ErrProductsNotAllowedInPatch.Error(), nil},
+		{"status update bound", `{"code": "ORD-7F3A00", "status": "VERIFIED", "note": "ring twice", "version": 3}`, http.StatusOK, `"success":true`,
+			func(t *testing.T, code string, input order.PartialUpdateInput) {
+				if code != "ORD-7F3A00" || input.Status == nil || *input.Status != order.StatusVerified ||
+					input.Note == nil || *input.Note != "ring twice" || input.ExpectedVersion == nil || *input.ExpectedVersion != 3 {
+					t.Errorf("service got %s %+v", code, input)
+				}
+			}},
+		{"payment fields bound", `{"code": "ORD-7F3A00", "payment_status": "CONFIRMED", "payment_account_id": "acc-1"}`, http.StatusOK, `"success":true`,
+			func(t *testing.T, code string, input order.PartialUpdateInput) {
+				if input.PaymentStatus == nil || *input.PaymentStatus != order.PaymentConfirmed || input.PaymentAccountID == nil || *input.PaymentAccountID != "acc-1" {
+					t.Errorf("service got %+v", input)
+				}
+			}},
+		{"missing code", `{"status": "VERIFIED"}`, http.StatusBadRequest, `"field":"code"`, nil},
+		{"unknown status", `{"code": "ORD-7F3A00", "status": "LOST"}`, http.StatusBadRequest, `"field":"status"`, nil},
+		{"malformed JSON", `{"code": "ORD-7F3A00",`, http.StatusBadRequest, "Invalid request body", nil},
+		{"not an object", `["ORD-7F3A00"]`, http.StatusBadRequest, "Invalid request body", nil},
+		{"empty body", ``, http.StatusBadRequest, "Invalid request body", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.OrderService{
+				PartialUpdateFunc: func(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error) {
+					called = true
+					if tt.wantInput != nil {
+						tt.wantInput(t, code, input)
+					}
+					return mocks.SampleOrders(1)[0], nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPatch, "/api/v1/orders", tt.body, true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if called != (tt.wantInput != nil) {
+				t.Errorf("service called = %v", called)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ConfirmPayment handles POST /api/v1/orders/:code/payment/confirm (admin).
+// Confirming an already confirmed payment is a no-op; refunded payments answer 409.
+func (h *OrderHandler) ConfirmPayment(c *gin.Context) {
+	code := c.Param("code")
+
+	o, err := h.service.ConfirmPayment(c.Request.Context(), code)
+	if err != nil {
+		statusCode := h.mapErrorToStatusCode(err)
+		if statusCode == http.StatusInternalServerError {
+			logger.Error("failed to confirm payment", "error", err, "code", code)
+		}
+		respondError(c, statusCode, err, "Failed to confirm payment")
+		return
+	}
+
+	logger.Info("order payment confirmed", "order_id", o.ID, "code", o.Code)
+	response.Success(c, http.StatusOK, dto.ToOrderResponse(o), "Payment confirmed successfully")
+}
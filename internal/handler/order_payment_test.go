@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestConfirmPayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"confirmed", nil, http.StatusOK, `"payment_status":"CONFIRMED"`},
+		{"refunded payment", fmt.Errorf("%w: REFUNDED to CONFIRMED", order.ErrInvalidPaymentTransition), http.StatusConflict, "REFUNDED to CONFIRMED"},
+		{"unknown order", order.ErrOrderNotFound, http.StatusNotFound, ""},
+		{"database down", errors.New("connection refused"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCode string
+			service := &mocks.OrderService{
+				ConfirmPaymentFunc: func(ctx context.Context, code string) (*order.Order, error) {
+					gotCode = code
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					o := mocks.SampleOrders(1)[0]
+					o.Code, o.PaymentStatus = code, order.PaymentConfirmed
+					return o, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.POST("/api/v1/orders/:code/payment/confirm", NewOrderHandler(service).ConfirmPayment)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/orders/ORD-7KQ2M9/payment/confirm", "", true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if gotCode != "ORD-7KQ2M9" {
+				t.Errorf("service got code %q", gotCode)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPaymentStatusFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       order.PaymentStatus
+	}{
+		{"none", "", http.StatusOK, ""},
+		{"confirmed", "?payment_status=CONFIRMED", http.StatusOK, order.PaymentConfirmed},
+		{"receipt uploaded", "?payment_status=RECEIPT_UPLOADED", http.StatusOK, order.PaymentReceiptUploaded},
+		{"unknown", "?payment_status=PAID", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.OrderFilters
+			service := &mocks.OrderService{
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					got = &filters
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if got != nil {
+					t.Error("service called with an invalid payment status")
+				}
+				return
+			}
+			if gotStatus := got.PaymentStatus; (gotStatus == nil) != (tt.want == "") || gotStatus != nil && *gotStatus != tt.want {
+				t.Errorf("payment status filter = %v, want %q", gotStatus, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// QR code image size bounds, in pixels
+const (
+	defaultQRSize = 256
+	minQRSize     = 128
+	maxQRSize     = 1024
+)
+
+// GetQR handles GET /api/v1/orders/:code/qr?size=256&format=png
+// It renders a QR code encoding the order's tracking page URL, for printing on receipts.
+func (h *OrderHandler) GetQR(c *gin.Context) {
+	code := c.Param("code")
+
+	size := defaultQRSize
+	if sizeStr := c.Query("size"); sizeStr != "" {
+		parsed, err := strconv.Atoi(sizeStr)
+		if err != nil || parsed < minQRSize || parsed > maxQRSize {
+			response.Error(c, http.StatusBadRequest, fmt.Errorf("size must be an integer between %d and %d", minQRSize, maxQRSize), "Invalid size")
+			return
+		}
+		size = parsed
+	}
+
+	format := c.DefaultQuery("format", "png")
+	if format != "png" && format != "svg" {
+		response.Error(c, http.StatusBadRequest, fmt.Errorf("unsupported QR format: %s", format), "Only png and svg formats are supported")
+		return
+	}
+
+	if _, err := h.service.GetByCode(c.Request.Context(), code); err != nil {
+		if errors.Is(err, order.ErrOrderNotFound) {
+			response.Error(c, http.StatusNotFound, err, "Order not found")
+			return
+		}
+		logger.Error("failed to get order for QR", "error", err, "code", code)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to generate QR code")
+		return
+	}
+
+	trackingURL, err := h.service.TrackingURL(code)
+	if err != nil {
+		response.Error(c, http.StatusServiceUnavailable, err, "QR codes are not enabled")
+		return
+	}
+
+	qr, err := qrcode.New(trackingURL, qrcode.Medium)
+	if err != nil {
+		logger.Error("failed to encode QR code", "error", err, "code", code)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to generate QR code")
+		return
+	}
+
+	// The tracking URL of a code never changes, so clients and CDNs may keep the image
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+
+	if format == "svg" {
+		c.Data(http.StatusOK, "image/svg+xml", renderQRSVG(qr.Bitmap(), size))
+		return
+	}
+
+	png, err := qr.PNG(size)
+	if err != nil {
+		logger.Error("failed to render QR code", "error", err, "code", code)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to generate QR code")
+		return
+	}
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// renderQRSVG draws the QR modules (quiet zone included) as a single path, one module per viewBox unit
+func renderQRSVG(bitmap [][]bool, size int) []byte {
+	n := len(bitmap)
+	var path strings.Builder
+	for y, row := range bitmap {
+		// Merge horizontal runs of dark modules to keep the path short
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, n, n)
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="#fff"/>`, n, n)
+	fmt.Fprintf(&svg, `<path d="%s" fill="#000"/>`, path.String())
+	svg.WriteString(`</svg>`)
+	return []byte(svg.String())
+}
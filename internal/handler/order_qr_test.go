@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// qrService knows one order and builds tracking URLs from template
+func qrService(template string) *mocks.OrderService {
+	urls := order.NewService(nil, order.WithTrackingURLTemplate(template))
+	return &mocks.OrderService{
+		GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) {
+			if code != "ORD-1700000000-a1b2c3d4" {
+				return nil, order.ErrOrderNotFound
+			}
+			return &order.Order{Code: code}, nil
+		},
+		TrackingURLFunc: urls.TrackingURL,
+	}
+}
+
+// decodeQR reads the text of the QR code in an image
+func decodeQR(t *testing.T, img image.Image) string {
+	t.Helper()
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, nil)
+	if err != nil {
+		t.Fatalf("decode QR: %v", err)
+	}
+	return result.GetText()
+}
+
+// svgRun matches one run of dark modules drawn by renderQRSVG
+var svgRun = regexp.MustCompile(`M(\d+) (\d+)h(\d+)v1h-\d+z`)
+
+// rasterizeQRSVG draws the runs of an SVG QR code at 4 pixels per module
+func rasterizeQRSVG(t *testing.T, svg []byte) image.Image {
+	t.Helper()
+	viewBox := regexp.MustCompile(`viewBox="0 0 (\d+) \d+"`).FindSubmatch(svg)
+	if viewBox == nil {
+		t.Fatalf("no viewBox in %s", svg)
+	}
+	const scale = 4
+	n, _ := strconv.Atoi(string(viewBox[1]))
+	img := image.NewGray(image.Rect(0, 0, n*scale, n*scale))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for _, run := range svgRun.FindAllSubmatch(svg, -1) {
+		x, _ := strconv.Atoi(string(run[1]))
+		y, _ := strconv.Atoi(string(run[2]))
+		w, _ := strconv.Atoi(string(run[3]))
+		for px := x * scale; px < (x+w)*scale; px++ {
+			for py := y * scale; py < (y+1)*scale; py++ {
+				img.SetGray(px, py, color.Gray{})
+			}
+		}
+	}
+	return img
+}
+
+func TestGetQR(t *testing.T) {
+	const code = "ORD-1700000000-a1b2c3d4"
+	const template = "https://track.example.com/{code}"
+	wantURL := "https://track.example.com/" + code
+
+	tests := []struct {
+		name        string
+		target      string
+		template    string
+		wantStatus  int
+		wantType    string
+		wantPixels  int // Image width and height of PNG responses
+		wantInImage string
+	}{
+		{"default png", "/api/v1/orders/" + code + "/qr", template, http.StatusOK, "image/png", 256, wantURL},
+		{"smallest size", "/api/v1/orders/" + code + "/qr?size=128", template, http.StatusOK, "image/png", 128, wantURL},
+		{"largest size", "/api/v1/orders/" + code + "/qr?size=1024&format=png", template, http.StatusOK, "image/png", 1024, wantURL},
+		{"svg", "/api/v1/orders/" + code + "/qr?format=svg&size=300", template, http.StatusOK, "image/svg+xml", 0, wantURL},
+		{"code in the query string", "/api/v1/orders/" + code + "/qr", "https://example.com/t?code={code}", http.StatusOK, "image/png", 256, "https://example.com/t?code=" + code},
+		{"size too small", "/api/v1/orders/" + code + "/qr?size=127", template, http.StatusBadRequest, "", 0, ""},
+		{"size too large", "/api/v1/orders/" + code + "/qr?size=1025", template, http.StatusBadRequest, "", 0, ""},
+		{"size not a number", "/api/v1/orders/" + code + "/qr?size=big", template, http.StatusBadRequest, "", 0, ""},
+		{"unknown format", "/api/v1/orders/" + code + "/qr?format=gif", template, http.StatusBadRequest, "", 0, ""},
+		{"unknown code", "/api/v1/orders/ORD-1700000000-ffffffff/qr", template, http.StatusNotFound, "", 0, ""},
+		{"template not configured", "/api/v1/orders/" + code + "/qr", "", http.StatusServiceUnavailable, "", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := qrService(tt.template)
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/:code/qr", NewOrderHandler(service).GetQR)
+
+			w := serveJSON(router, http.MethodGet, tt.target, "", true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("content type = %q, want %q", got, tt.wantType)
+			}
+			if got := w.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+				t.Errorf("cache control = %q", got)
+			}
+
+			var img image.Image
+			if tt.wantType == "image/svg+xml" {
+				if !bytes.Contains(w.Body.Bytes(), []byte(`width="300" height="300"`)) {
+					t.Errorf("svg size missing: %.120s", w.Body.String())
+				}
+				img = rasterizeQRSVG(t, w.Body.Bytes())
+			} else {
+				var err error
+				if img, err = png.Decode(w.Body); err != nil {
+					t.Fatal(err)
+				}
+				if b := img.Bounds(); b.Dx() != tt.wantPixels || b.Dy() != tt.wantPixels {
+					t.Errorf("image is %dx%d, want %d", b.Dx(), b.Dy(), tt.wantPixels)
+				}
+			}
+			if got := decodeQR(t, img); got != tt.wantInImage {
+				t.Errorf("QR encodes %q, want %q", got, tt.wantInImage)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestGetAllQuickSearch(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantQuery  string // "" when no quick search reaches the service
+	}{
+		{"no search", "", http.StatusOK, ""},
+		{"code prefix", "?q=ORD-7F", http.StatusOK, "ORD-7F"},
+		{"spaces collapsed", "?q=++Ana+++Maria+", http.StatusOK, "Ana Maria"},
+		{"blank box lists everything", "?q=+++", http.StatusOK, ""},
+		{"combined with filters and paging", "?q=taco&status=CREATED&limit=5&offset=10", http.StatusOK, "taco"},
+		{"too long", "?q=" + strings.Repeat("a", order.MaxSearchQueryLength+1), http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.OrderFilters
+			service := &mocks.OrderService{
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					got = &filters
+					return mocks.SampleOrders(2), 12, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if got != nil {
+					t.Error("service called with an invalid search")
+				}
+				return
+			}
+			if q := deref(got.Query); q != tt.wantQuery {
+				t.Errorf("query = %q, want %q", q, tt.wantQuery)
+			}
+			if !strings.Contains(w.Body.String(), `"total_items":12`) {
+				t.Errorf("search results lost the count: %s", w.Body.String())
+			}
+			if strings.Contains(tt.query, "status") && (got.Status == nil || *got.Status != order.StatusCreated || got.Limit != 5 || got.Offset != 10) {
+				t.Errorf("filters = %+v, want the status and page kept", got)
+			}
+		})
+	}
+}
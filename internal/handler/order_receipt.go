@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/receipt"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GetReceipt handles GET /api/v1/orders/:code/receipt?variant=customer&tz=America/Bogota
+// It renders the order as 80-column plain text for thermal printers: the customer receipt
+// (prices, total, tracking link) or the kitchen ticket (observations, table, notes, no prices).
+func (h *OrderHandler) GetReceipt(c *gin.Context) {
+	code := c.Param("code")
+
+	variant := receipt.Variant(c.DefaultQuery("variant", string(receipt.VariantCustomer)))
+	if !receipt.IsValidVariant(variant) {
+		response.Error(c, http.StatusBadRequest, fmt.Errorf("unsupported receipt variant: %s", variant), "variant must be customer or kitchen")
+		return
+	}
+
+	loc, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err, "tz must be an IANA time zone, e.g. America/Bogota")
+		return
+	}
+
+	o, err := h.service.GetByCode(c.Request.Context(), code)
+	if err != nil {
+		if errors.Is(err, order.ErrOrderNotFound) {
+			response.Error(c, http.StatusNotFound, err, "Order not found")
+			return
+		}
+		logger.Error("failed to get order for receipt", "error", err, "code", code)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to render receipt")
+		return
+	}
+
+	opts := receipt.Options{Currency: h.service.Currency(), Location: loc}
+	if trackingURL, err := h.service.TrackingURL(o.Code); err == nil {
+		opts.TrackingURL = trackingURL
+	}
+
+	var body bytes.Buffer
+	if err := receipt.Render(&body, o, variant, opts); err != nil {
+		logger.Error("failed to render receipt", "error", err, "code", code, "variant", variant)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to render receipt")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", body.Bytes())
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestGetReceipt(t *testing.T) {
+	const code = "ORD-7KQ2M9"
+
+	tests := []struct {
+		name        string
+		query       string
+		trackingErr error
+		wantStatus  int
+		wantBody    []string
+		notWantBody []string
+	}{
+		{"customer by default", "", nil, http.StatusOK, []string{"RECEIPT", "TOTAL", "Track your order", "2024-06-01 01:30"}, []string{"KITCHEN"}},
+		{"kitchen", "?variant=kitchen", nil, http.StatusOK, []string{"KITCHEN - ON SITE", "TABLE", "1 x Burger"}, []string{"TOTAL", "Track your order"}},
+		{"local time", "?tz=America/Bogota", nil, http.StatusOK, []string{"2024-05-31 20:30"}, nil},
+		{"tracking disabled", "", order.ErrTrackingURLNotConfigured, http.StatusOK, []string{"TOTAL"}, []string{"Track your order"}},
+		{"unknown variant", "?variant=bar", nil, http.StatusBadRequest, []string{"variant must be customer or kitchen"}, nil},
+		{"unknown time zone", "?tz=Mars/Olympus", nil, http.StatusBadRequest, []string{"IANA time zone"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetByCodeFunc: func(ctx context.Context, c string) (*order.Order, error) {
+					table := 3
+					o := order.NewOrder(order.SaleTypeOnSite, []order.OrderProduct{{ID: "p1", Name: "Burger", Price: 12000, Quantity: 1}})
+					o.Code, o.TableNumber = c, &table
+					o.CreatedAt = time.Date(2024, 6, 1, 1, 30, 0, 0, time.UTC)
+					return o, nil
+				},
+				TrackingURLFunc: func(c string) (string, error) {
+					return "https://track.example.com/" + c, tt.trackingErr
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/:code/receipt", NewOrderHandler(service).GetReceipt)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/"+code+"/receipt"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				if got := w.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+					t.Errorf("Content-Type = %q", got)
+				}
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %q:\n%s", want, w.Body.String())
+				}
+			}
+			for _, notWant := range tt.notWantBody {
+				if strings.Contains(w.Body.String(), notWant) {
+					t.Errorf("body has %q:\n%s", notWant, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGetReceiptErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"unknown order", order.ErrOrderNotFound, http.StatusNotFound},
+		{"database down", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) { return nil, tt.err },
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/:code/receipt", NewOrderHandler(service).GetReceipt)
+
+			if w := serveJSON(router, http.MethodGet, "/api/v1/orders/ORD-7KQ2M9/receipt", "", false); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestGetAllSort(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBy     order.OrderSortField
+		wantDir    order.SortDirection
+	}{
+		{"default left to the repository", "", http.StatusOK, "", ""},
+		{"total ascending", "?sort=total&order=asc", http.StatusOK, order.SortFieldTotal, order.SortAsc},
+		{"updated descending", "?sort=updated_at&order=desc", http.StatusOK, order.SortFieldUpdatedAt, order.SortDesc},
+		{"field only", "?sort=status", http.StatusOK, order.SortFieldStatus, ""},
+		{"direction only", "?order=asc", http.StatusOK, "", order.SortAsc},
+		{"any case", "?sort=Created_At&order=ASC", http.StatusOK, order.SortFieldCreatedAt, order.SortAsc},
+		{"unknown field", "?sort=code", http.StatusBadRequest, "", ""},
+		{"field of another resource", "?sort=price&order=asc", http.StatusBadRequest, "", ""},
+		{"unknown direction", "?sort=total&order=up", http.StatusBadRequest, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.OrderFilters
+			service := &mocks.OrderService{
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					got = &filters
+					return mocks.SampleOrders(2), 2, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if got != nil {
+					t.Error("service called with an invalid sort")
+				}
+				return
+			}
+			if got.SortBy != tt.wantBy || got.SortDir != tt.wantDir {
+				t.Errorf("sort = %q %q, want %q %q", got.SortBy, got.SortDir, tt.wantBy, tt.wantDir)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestMetricsStageDurations(t *testing.T) {
+	durations := []order.StageDuration{
+		{Stage: order.Stages[0], OrderCount: 4, AvgSeconds: 90},
+		{Stage: order.Stages[1], OrderCount: 4, AvgSeconds: 300},
+		{Stage: order.Stages[2]},
+		{Stage: order.Stages[3]},
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		wantBody []string
+	}{
+		{"metrics in seconds", "/api/v1/orders/metrics", []string{
+			`{"stage":"created_to_verified","from":"CREATED","to":"VERIFIED","avg_seconds":90,"order_count":4}`,
+			`{"stage":"verified_to_in_progress","from":"VERIFIED","to":"IN_PROGRESS","avg_seconds":300,"order_count":4}`,
+			`{"stage":"out_for_delivery_to_delivered","from":"OUT_FOR_DELIVERY","to":"DELIVERED","avg_seconds":0,"order_count":0}`,
+		}},
+		{"export rows", "/api/v1/orders/metrics/export", []string{
+			"stage_created_to_verified_avg_seconds,90",
+			"stage_created_to_verified_orders,4",
+			"stage_in_progress_to_out_for_delivery_orders,0",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+					return &order.OrderMetrics{StageDurations: durations}, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, tt.target, "", true)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s:\n%s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestOrderResponseStatusTimes(t *testing.T) {
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	verified, delivered := created.Add(2*time.Minute), created.Add(30*time.Minute)
+
+	tests := []struct {
+		name        string
+		change      func(o *order.Order)
+		wantBody    string
+		notWantBody string
+	}{
+		{"times reached", func(o *order.Order) { o.VerifiedAt, o.DeliveredAt = &verified, &delivered },
+			`"status_times":{"DELIVERED":"2024-06-01T12:30:00Z","VERIFIED":"2024-06-01T12:02:00Z"}`, `"CREATED":"`},
+		{"new order", func(o *order.Order) {}, "", `"status_times"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) {
+					o := mocks.SampleOrders(1)[0]
+					o.CreatedAt, o.VerifiedAt, o.InProgressAt, o.OutForDeliveryAt, o.DeliveredAt, o.CancelledAt = created, nil, nil, nil, nil, nil
+					tt.change(o)
+					return o, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/:code", NewOrderHandler(service).GetByCode)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/ORD-7KQ2M9", "", true)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if strings.Contains(w.Body.String(), tt.notWantBody) {
+				t.Errorf("body has %s: %s", tt.notWantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestTenantFilters(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		query         string
+		wantCompany   string
+		wantSalePoint string
+	}{
+		{"list by company", "/api/v1/orders", "?company_id=c1", "c1", ""},
+		{"list by sale point", "/api/v1/orders", "?sale_point_id=s1", "", "s1"},
+		{"list by both", "/api/v1/orders", "?company_id=c1&sale_point_id=s1", "c1", "s1"},
+		{"list of the default tenant", "/api/v1/orders", "?company_id=" + order.DefaultTenant, order.DefaultTenant, ""},
+		{"empty values ignored", "/api/v1/orders", "?company_id=&sale_point_id=", "", ""},
+		{"metrics", "/api/v1/orders/metrics", "?company_id=c1&sale_point_id=s1", "c1", "s1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got order.OrderFilters
+			service := &mocks.OrderService{
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					got = filters
+					return nil, 0, nil
+				},
+				GetMetricsFunc: func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+					got = filters
+					return &order.OrderMetrics{}, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, tt.path+tt.query, "", true)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if company := deref(got.CompanyID); company != tt.wantCompany {
+				t.Errorf("company_id = %q, want %q", company, tt.wantCompany)
+			}
+			if salePoint := deref(got.SalePointID); salePoint != tt.wantSalePoint {
+				t.Errorf("sale_point_id = %q, want %q", salePoint, tt.wantSalePoint)
+			}
+			if tt.path == "/api/v1/orders/metrics" {
+				for field, value := range map[string]string{"company_id": tt.wantCompany, "sale_point_id": tt.wantSalePoint} {
+					if want := fmt.Sprintf("%q:%q", field, value); !strings.Contains(w.Body.String(), want) {
+						t.Errorf("applied filters miss %s: %s", want, w.Body.String())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreateRequiresTenant(t *testing.T) {
+	body := func(tenant string) string {
+		return `{` + tenant + `"sale_type": "ON_SITE", "table_number": 2,
+			"products": [{"id": "p1", "name": "Soda", "price": 3000, "quantity": 1}]}`
+	}
+
+	tests := []struct {
+		name       string
+		tenant     string
+		serviceErr error
+		wantStatus int
+		wantField  string
+	}{
+		{"scoped", `"company_id": "c1", "sale_point_id": "s1",`, nil, http.StatusCreated, ""},
+		{"refused by the service", `"company_id": "c1", "sale_point_id": "s1",`, apperrors.NewDomainError(order.ErrSalePointIDRequired, "sale_point_id", nil), http.StatusUnprocessableEntity, "sale_point_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.CreateInput
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					got = &input
+					if tt.serviceErr != nil {
+						return nil, fmt.Errorf("validation error: %w", tt.serviceErr)
+					}
+					return mocks.SampleOrders(1)[0], nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPost, "/api/v1/orders", body(tt.tenant), false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantField != "" {
+				if !strings.Contains(w.Body.String(), `"field":"`+tt.wantField+`"`) {
+					t.Errorf("error does not name %s: %s", tt.wantField, w.Body.String())
+				}
+				if tt.wantStatus == http.StatusBadRequest && got != nil {
+					t.Error("service called with an invalid request")
+				}
+				return
+			}
+			if got.CompanyID != "c1" || got.SalePointID != "s1" {
+				t.Errorf("tenant = %s/%s, want c1/s1", got.CompanyID, got.SalePointID)
+			}
+		})
+	}
+}
+
+func TestOrderResponseRendersTenant(t *testing.T) {
+	tests := []struct {
+		name      string
+		companyID string
+		want      string
+	}{
+		{"scoped order", "c1", `"company_id":"c1","sale_point_id":"s1"`},
+		{"order stored before tenants", "", `"company_id":"default","sale_point_id":"default"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) {
+					o := mocks.SampleOrders(1)[0]
+					o.Code, o.CompanyID, o.SalePointID = code, tt.companyID, ""
+					if tt.companyID != "" {
+						o.SalePointID = "s1"
+					}
+					return o, nil
+				},
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/:code", NewOrderHandler(service).GetByCode)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/ORD-7KQ2M9", "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("body misses %s: %s", tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateExpectedTotal(t *testing.T) {
+	const order1 = `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+		"products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]`
+
+	mismatch := func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+		err := fmt.Errorf("%w: sent %d, calculated %d", order.ErrTotalMismatch, *input.ExpectedTotal, 1000)
+		return nil, fmt.Errorf("validation error: %w", apperrors.NewDomainError(err, "total", *input.ExpectedTotal))
+	}
+
+	cents := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name       string
+		body       string
+		create     func(ctx context.Context, input order.CreateInput) (*order.Order, error)
+		wantStatus int
+		wantTotal  *int64 // ExpectedTotal the service must receive
+		wantBody   string
+	}{
+		{"omitted", order1 + `}`, nil, http.StatusCreated, nil, ""},
+		{"forwarded", order1 + `, "total": 1000}`, nil, http.StatusCreated, cents(1000), ""},
+		{"mismatch is 422", order1 + `, "total": 999}`, mismatch, http.StatusUnprocessableEntity, nil, `"field":"total"`},
+		{"negative", order1 + `, "total": -1}`, nil, http.StatusBadRequest, nil, `"field":"total"`},
+		{"not a number", order1 + `, "total": "1000"}`, nil, http.StatusBadRequest, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *int64
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					got = input.ExpectedTotal
+					if tt.create != nil {
+						return tt.create(ctx, input)
+					}
+					return mocks.SampleOrders(1)[0], nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPost, "/api/v1/orders", tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			if (got == nil) != (tt.wantTotal == nil) || got != nil && *got != *tt.wantTotal {
+				t.Errorf("ExpectedTotal = %v, want %v", got, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// trackStreamHeartbeat is how often an idle tracking stream sends a comment so proxies keep it open.
+// It is a variable so tests can shorten it.
+var trackStreamHeartbeat = 15 * time.Second
+
+// TrackStream handles GET /api/v1/orders/track/:code/stream (public, Server-Sent Events).
+// The current status is sent on connect and a "status" event follows every change, until the order
+// is DELIVERED or CANCELLED. Idle streams get a ": heartbeat" comment every 15 seconds.
+func (h *OrderHandler) TrackStream(c *gin.Context) {
+	code := c.Param("code")
+
+	// Subscribe before loading the order so no change between the two is missed
+	sub, err := h.service.SubscribeStatus(code)
+	if err != nil {
+		switch {
+		case errors.Is(err, order.ErrStatusStreamsDisabled):
+			response.Error(c, http.StatusNotFound, err, "Tracking streams are disabled")
+		case errors.Is(err, order.ErrTooManyStatusStreams):
+			response.Error(c, http.StatusTooManyRequests, err, "Too many open streams for this order")
+		case errors.Is(err, order.ErrStatusFeedClosed):
+			response.Error(c, http.StatusServiceUnavailable, err, "Server is shutting down")
+		default:
+			response.Error(c, http.StatusBadRequest, err, "Invalid order code")
+		}
+		return
+	}
+	defer sub.Close()
+
+	o, err := h.service.GetByCode(c.Request.Context(), code)
+	if err != nil {
+		if errors.Is(err, order.ErrOrderNotFound) {
+			response.Error(c, http.StatusNotFound, err, "Order not found")
+			return
+		}
+		logger.Error("failed to track order", "error", err, "code", code)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to track order")
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+	c.Status(http.StatusOK)
+
+	current := order.StatusEvent{Code: o.Code, Status: o.Status, UpdatedAt: o.UpdatedAt}
+	c.SSEvent("status", dto.ToStatusEventResponse(current))
+	c.Writer.Flush()
+	if current.IsTerminal() {
+		return
+	}
+
+	heartbeat := time.NewTicker(trackStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case event := <-sub.Events():
+			c.SSEvent("status", dto.ToStatusEventResponse(event))
+			c.Writer.Flush()
+			if event.IsTerminal() {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
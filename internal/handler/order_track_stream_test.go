@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+const streamCode = "ORD-7KQ2M9"
+
+// streamService serves an order in the given status and subscribes to the feed
+func streamService(feed *order.StatusFeed, status order.OrderStatus) *mocks.OrderService {
+	return &mocks.OrderService{
+		SubscribeStatusFunc: feed.Subscribe,
+		GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) {
+			o := order.NewOrder(order.SaleTypeOnSite, nil)
+			o.Code, o.Status = code, status
+			return o, nil
+		},
+	}
+}
+
+// openStream connects to the tracking stream over a real connection
+func openStream(t *testing.T, service order.ServiceAPI) (*http.Response, *bufio.Reader, context.CancelFunc) {
+	t.Helper()
+	router := newOrderRouter(service)
+	router.GET("/api/v1/orders/track/:code/stream", NewOrderHandler(service).TrackStream)
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/orders/track/"+streamCode+"/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body), cancel
+}
+
+// readEvent reads up to the blank line ending the next event or comment
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("stream ended after %q: %v", lines, err)
+		}
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			return strings.Join(lines, "\n")
+		}
+		lines = append(lines, line)
+	}
+}
+
+// waitForSlot polls until the feed accepts a new subscription for the code, i.e. the stream unsubscribed
+func waitForSlot(t *testing.T, feed *order.StatusFeed) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		sub, err := feed.Subscribe(streamCode)
+		if err == nil {
+			sub.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stream did not unsubscribe: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTrackStreamFollowsStatusChanges(t *testing.T) {
+	feed := order.NewStatusFeed(1)
+	resp, r, cancel := openStream(t, streamService(feed, order.StatusCreated))
+	defer cancel()
+
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/event-stream") {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if first := readEvent(t, r); !strings.Contains(first, "event:status") || !strings.Contains(first, `"status":"CREATED"`) || strings.Contains(first, "previous_status") {
+		t.Fatalf("initial event = %q", first)
+	}
+
+	o := &order.Order{Code: streamCode, Status: order.StatusVerified}
+	feed.OrderStatusChanged(o, order.StatusCreated)
+	if event := readEvent(t, r); !strings.Contains(event, `"status":"VERIFIED"`) || !strings.Contains(event, `"previous_status":"CREATED"`) {
+		t.Errorf("change event = %q", event)
+	}
+
+	o.Status = order.StatusDelivered
+	feed.OrderStatusChanged(o, order.StatusVerified)
+	if event := readEvent(t, r); !strings.Contains(event, `"status":"DELIVERED"`) {
+		t.Errorf("final event = %q", event)
+	}
+	// The stream closes after a terminal status and gives its slot back
+	if _, err := r.ReadString('\n'); err == nil {
+		t.Error("stream still open after DELIVERED")
+	}
+	waitForSlot(t, feed)
+}
+
+func TestTrackStreamClosesForTerminalOrders(t *testing.T) {
+	for _, status := range order.TerminalStatuses {
+		t.Run(string(status), func(t *testing.T) {
+			feed := order.NewStatusFeed(1)
+			_, r, cancel := openStream(t, streamService(feed, status))
+			defer cancel()
+
+			if event := readEvent(t, r); !strings.Contains(event, `"status":"`+string(status)+`"`) {
+				t.Errorf("event = %q", event)
+			}
+			if _, err := r.ReadString('\n'); err == nil {
+				t.Error("stream left open")
+			}
+		})
+	}
+}
+
+func TestTrackStreamClientDisconnect(t *testing.T) {
+	feed := order.NewStatusFeed(1)
+	_, r, cancel := openStream(t, streamService(feed, order.StatusInProgress))
+	readEvent(t, r)
+
+	cancel()
+	waitForSlot(t, feed)
+}
+
+func TestTrackStreamHeartbeat(t *testing.T) {
+	defer func(d time.Duration) { trackStreamHeartbeat = d }(trackStreamHeartbeat)
+	trackStreamHeartbeat = 10 * time.Millisecond
+
+	_, r, cancel := openStream(t, streamService(order.NewStatusFeed(1), order.StatusInProgress))
+	defer cancel()
+	readEvent(t, r)
+	if comment := readEvent(t, r); comment != ": heartbeat" {
+		t.Errorf("idle stream sent %q, want a heartbeat comment", comment)
+	}
+}
+
+func TestTrackStreamShutdown(t *testing.T) {
+	feed := order.NewStatusFeed(1)
+	_, r, cancel := openStream(t, streamService(feed, order.StatusInProgress))
+	defer cancel()
+	readEvent(t, r)
+
+	feed.Close()
+	if _, err := r.ReadString('\n'); err == nil {
+		t.Error("stream left open after the feed closed")
+	}
+}
+
+func TestTrackStreamErrors(t *testing.T) {
+	full := order.NewStatusFeed(1)
+	if _, err := full.Subscribe(streamCode); err != nil {
+		t.Fatal(err)
+	}
+	closed := order.NewStatusFeed(1)
+	closed.Close()
+
+	tests := []struct {
+		name         string
+		subscribe    func(code string) (*order.StatusSubscription, error)
+		getErr       error
+		wantStatus   int
+		wantUnsubbed bool // The subscription must be released when the order cannot be loaded
+	}{
+		{"streams disabled", func(string) (*order.StatusSubscription, error) { return nil, order.ErrStatusStreamsDisabled }, nil, http.StatusNotFound, false},
+		{"too many streams", full.Subscribe, nil, http.StatusTooManyRequests, false},
+		{"shutting down", closed.Subscribe, nil, http.StatusServiceUnavailable, false},
+		{"invalid code", func(string) (*order.StatusSubscription, error) { return nil, order.ErrInvalidOrderCode }, nil, http.StatusBadRequest, false},
+		{"unknown order", nil, order.ErrOrderNotFound, http.StatusNotFound, true},
+		{"database down", nil, errors.New("connection refused"), http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := order.NewStatusFeed(1)
+			subscribe := tt.subscribe
+			if subscribe == nil {
+				subscribe = feed.Subscribe
+			}
+			service := &mocks.OrderService{
+				SubscribeStatusFunc: subscribe,
+				GetByCodeFunc:       func(ctx context.Context, code string) (*order.Order, error) { return nil, tt.getErr },
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/track/:code/stream", NewOrderHandler(service).TrackStream)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/track/"+streamCode+"/stream", "", false)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantUnsubbed {
+				waitForSlot(t, feed)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+// notedOrder is an order with public, internal and legacy notes
+func notedOrder(code string) *order.Order {
+	legacy := "legacy: customer was rude last time"
+	o := order.NewOrder(order.SaleTypeDelivery, []order.OrderProduct{{ID: "p1", Name: "Burger", Price: 12000, Quantity: 1}})
+	o.Code, o.Note = code, &legacy
+	o.Customer = &order.Customer{Name: "Ana"}
+	o.Notes = []order.OrderNote{
+		{Text: "leave at the front desk", Author: "caja", Visibility: order.NotePublic},
+		{Text: "internal: pays in cash", Author: "caja", Visibility: order.NoteInternal},
+	}
+	return o
+}
+
+func TestTrackNotes(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		wantBody    []string
+		notWantBody []string
+	}{
+		{"notes not requested", "", []string{`"customer_name":"Ana"`}, []string{`"notes"`, "front desk", "internal:", "legacy:"}},
+		{"public notes requested", "?include=notes", []string{`"notes":[{"text":"leave at the front desk"`}, []string{"internal:", "legacy:", `"author"`, `"visibility"`}},
+		{"other includes ignored", "?include=history", nil, []string{`"notes"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) { return notedOrder(code), nil },
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/track/:code", NewOrderHandler(service).Track)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/track/ORD-7KQ2M9"+tt.query, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+			for _, notWant := range tt.notWantBody {
+				if strings.Contains(w.Body.String(), notWant) {
+					t.Errorf("body has %s: %s", notWant, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestTrackErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"unknown order", order.ErrOrderNotFound, http.StatusNotFound},
+		{"database down", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) { return nil, tt.err },
+			}
+			router := newOrderRouter(service)
+			router.GET("/api/v1/orders/track/:code", NewOrderHandler(service).Track)
+
+			if w := serveJSON(router, http.MethodGet, "/api/v1/orders/track/ORD-7KQ2M9", "", false); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
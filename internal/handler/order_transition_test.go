@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestRejectedTransitionNamesThePair(t *testing.T) {
+	tests := []struct {
+		name     string
+		saleType order.SaleType
+		from     order.OrderStatus
+		to       string
+		wantBody string
+	}{
+		{"on site never goes out for delivery", order.SaleTypeOnSite, order.StatusInProgress, "OUT_FOR_DELIVERY", "IN_PROGRESS to OUT_FOR_DELIVERY"},
+		{"delivered is terminal", order.SaleTypeDelivery, order.StatusDelivered, "CANCELLED", "DELIVERED to CANCELLED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				PartialUpdateFunc: func(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error) {
+					o := &order.Order{Code: code, SaleType: tt.saleType, Status: tt.from}
+					return nil, o.UpdateStatus(*input.Status, order.DefaultTransitionsFor(tt.saleType))
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPatch, "/api/v1/orders", `{"code": "ORD-7F3A00", "status": "`+tt.to+`"}`, false)
+			if w.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, http.StatusConflict, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateWithVariationType(t *testing.T) {
+	body := func(line string) string {
+		return `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+			"products": [{"id": "p1", "name": "Soda", "price": 3000, "quantity": 1` + line + `}]}`
+	}
+
+	tests := []struct {
+		name       string
+		line       string
+		wantStatus int
+		want       string // Variation the service receives; "" for none
+	}{
+		{"variation", `, "variation_type": "Grande"`, http.StatusCreated, "Grande"},
+		{"no variation", "", http.StatusCreated, ""},
+		{"null variation", `, "variation_type": null`, http.StatusCreated, ""},
+		{"empty variation", `, "variation_type": ""`, http.StatusBadRequest, ""},
+		{"variation too long", `, "variation_type": "` + strings.Repeat("x", 101) + `"`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.CreateInput
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					got = &input
+					return mocks.SampleOrders(1)[0], nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPost, "/api/v1/orders", body(tt.line), false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				if !strings.Contains(w.Body.String(), `"field":"variation_type"`) {
+					t.Errorf("error does not name variation_type: %s", w.Body.String())
+				}
+				return
+			}
+			if variation := deref(got.Products[0].VariationType); variation != tt.want {
+				t.Errorf("variation = %q, want %q", variation, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderResponseRendersVariationType(t *testing.T) {
+	grande := "Grande"
+	service := &mocks.OrderService{
+		GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) {
+			o := order.NewOrder(order.SaleTypeOnSite, []order.OrderProduct{
+				{ID: "p1", Name: "Soda", Price: 4000, Quantity: 1, VariationType: &grande},
+				{ID: "p2", Name: "Burger", Price: 10000, Quantity: 1},
+			})
+			o.Code = code
+			return o, nil
+		},
+	}
+	router := newOrderRouter(service)
+	router.GET("/api/v1/orders/:code", NewOrderHandler(service).GetByCode)
+
+	w := serveJSON(router, http.MethodGet, "/api/v1/orders/ORD-7KQ2M9", "", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"name":"Soda","variation_type":"Grande"`) {
+		t.Errorf("variation missing: %s", w.Body.String())
+	}
+	if strings.Count(w.Body.String(), "variation_type") != 1 {
+		t.Errorf("line without a variation renders one: %s", w.Body.String())
+	}
+}
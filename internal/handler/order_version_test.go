@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestOrderUpdateExpectedVersion(t *testing.T) {
+	bodies := map[string]func(version string) string{
+		http.MethodPatch: func(version string) string {
+			return `{"code": "ORD-7F3A00", "status": "VERIFIED"` + version + `}`
+		},
+		http.MethodPut: func(version string) string {
+			return `{"code": "ORD-7F3A00", "products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]` + version + `}`
+		},
+	}
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		version     string // Appended to the body
+		wantStatus  int
+		wantVersion string // Version the service receives; "" for none
+	}{
+		{"no version", "", "", http.StatusOK, ""},
+		{"body version", "", `, "version": 3`, http.StatusOK, "3"},
+		{"If-Match", `"3"`, "", http.StatusOK, "3"},
+		{"weak If-Match", `W/"3"`, "", http.StatusOK, "3"},
+		{"If-Match any", "*", `, "version": 3`, http.StatusOK, "3"},
+		{"If-Match agreeing with the body", `"3"`, `, "version": 3`, http.StatusOK, "3"},
+		{"If-Match disagreeing with the body", `"4"`, `, "version": 3`, http.StatusBadRequest, ""},
+		{"If-Match without a version", `"abc"`, "", http.StatusBadRequest, ""},
+		{"negative If-Match", `"-1"`, "", http.StatusBadRequest, ""},
+		{"negative body version", "", `, "version": -1`, http.StatusBadRequest, ""},
+	}
+
+	for method, body := range bodies {
+		for _, tt := range tests {
+			t.Run(method+"/"+tt.name, func(t *testing.T) {
+				var got *int64
+				called := false
+				saved := func(expected *int64) *order.Order {
+					called, got = true, expected
+					o := mocks.SampleOrders(1)[0]
+					o.Version = 7
+					return o
+				}
+				service := &mocks.OrderService{
+					PartialUpdateFunc: func(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error) {
+						return saved(input.ExpectedVersion), nil
+					},
+					ModifyFunc: func(ctx context.Context, code string, input order.ModifyInput) (*order.ModifyResult, error) {
+						return &order.ModifyResult{Order: saved(input.ExpectedVersion)}, nil
+					},
+				}
+
+				req := httptest.NewRequest(method, "/api/v1/orders", strings.NewReader(body(tt.version)))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+testAdminToken)
+				if tt.ifMatch != "" {
+					req.Header.Set("If-Match", tt.ifMatch)
+				}
+				w := httptest.NewRecorder()
+				newOrderRouter(service).ServeHTTP(w, req)
+
+				if w.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+				}
+				if tt.wantStatus != http.StatusOK {
+					if called {
+						t.Error("service called with an invalid version")
+					}
+					return
+				}
+				if version := fmt.Sprint(deref64(got)); version != tt.wantVersion {
+					t.Errorf("expected version = %q, want %q", version, tt.wantVersion)
+				}
+				if etag := w.Header().Get("ETag"); etag != `"7"` {
+					t.Errorf("ETag = %q, want the saved version", etag)
+				}
+				if !strings.Contains(w.Body.String(), `"version":7`) {
+					t.Errorf("body misses the saved version: %s", w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestOrderUpdateConflict(t *testing.T) {
+	tests := []struct {
+		method string
+		body   string
+	}{
+		{http.MethodPatch, `{"code": "ORD-7F3A00", "status": "VERIFIED", "version": 1}`},
+		{http.MethodPut, `{"code": "ORD-7F3A00", "products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}], "version": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			conflict := fmt.Errorf("%w: sent version 1, current version 2", order.ErrOrderConflict)
+			service := &mocks.OrderService{
+				PartialUpdateFunc: func(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error) {
+					return nil, conflict
+				},
+				ModifyFunc: func(ctx context.Context, code string, input order.ModifyInput) (*order.ModifyResult, error) {
+					return nil, conflict
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), tt.method, "/api/v1/orders", tt.body, true)
+			if w.Code != http.StatusConflict {
+				t.Errorf("status = %d, want 409, body %s", w.Code, w.Body.String())
+			}
+			if w.Header().Get("ETag") != "" {
+				t.Errorf("a refused update sent an ETag")
+			}
+		})
+	}
+}
+
+func TestGetByCodeSendsVersionTag(t *testing.T) {
+	service := &mocks.OrderService{
+		GetByCodeFunc: func(ctx context.Context, code string) (*order.Order, error) {
+			o := mocks.SampleOrders(1)[0]
+			o.Version = 4
+			return o, nil
+		},
+	}
+	router := newOrderRouter(service)
+	router.GET("/api/v1/orders/:code", NewOrderHandler(service).GetByCode)
+
+	w := serveJSON(router, http.MethodGet, "/api/v1/orders/ORD-7KQ2M9", "", true)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"4"` {
+		t.Errorf("status = %d, ETag = %q, want 200 with \"4\"", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+// deref64 returns the pointed version, or "" when there is none
+func deref64(v *int64) any {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/emerarteaga/products-api/internal/receipt"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GetZReport handles GET /api/v1/reports/z?date=2024-06-01&tz=America/Bogota&format=json
+// The report covers one local calendar day; date defaults to today and tz to UTC.
+// format=csv and format=txt return a downloadable file instead of JSON.
+func (h *OrderHandler) GetZReport(c *gin.Context) {
+	loc, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err, "tz must be an IANA time zone, e.g. America/Bogota")
+		return
+	}
+
+	date := c.DefaultQuery("date", time.Now().In(loc).Format(order.ZReportDateLayout))
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" && format != "txt" {
+		response.Error(c, http.StatusBadRequest, fmt.Errorf("unsupported report format: %s", format), "Only json, csv and txt formats are supported")
+		return
+	}
+
+	report, err := h.service.GetZReport(c.Request.Context(), date, loc)
+	if err != nil {
+		if errors.Is(err, order.ErrInvalidReportDate) {
+			response.Error(c, http.StatusBadRequest, err, "date must be formatted as YYYY-MM-DD")
+			return
+		}
+		logger.Error("failed to get Z report", "error", err, "date", date, "tz", loc.String())
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get Z report")
+		return
+	}
+
+	currency := h.service.Currency()
+	if format == "json" {
+		response.Success(c, http.StatusOK, dto.ToZReportResponse(report, currency), "")
+		return
+	}
+
+	contentType, write := "text/csv; charset=utf-8", writeZReportCSV
+	if format == "txt" {
+		contentType, write = "text/plain; charset=utf-8", writeZReportText
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="z-report_%s.%s"`, report.Date, format))
+	c.Status(http.StatusOK)
+
+	if err := write(c.Writer, report, currency); err != nil {
+		// Headers are already sent, so the error can only be logged
+		logger.Error("failed to write Z report", "error", err, "format", format)
+	}
+}
+
+// writeZReportCSV writes the summary and top products sections separated by an empty line,
+// with the same column conventions as the metrics export
+func writeZReportCSV(w io.Writer, r *order.ZReport, currency money.Currency) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"metric", "value"},
+		{"date", r.Date},
+		{"timezone", r.Timezone},
+		{"window_from", r.From.Format(time.RFC3339Nano)},
+		{"window_to", r.To.Format(time.RFC3339Nano)},
+		{"currency", currency.Code},
+		{"order_count", strconv.FormatInt(r.OrderCount, 10)},
+		{"sales_cents", strconv.FormatInt(r.Sales, 10)},
+		{"sales", currency.Format(r.Sales)},
+		{"avg_ticket_cents", strconv.FormatInt(r.AvgTicket, 10)},
+		{"avg_ticket", currency.Format(r.AvgTicket)},
+		{"cancelled_count", strconv.FormatInt(r.CancelledCount, 10)},
+		{"cancelled_sales_cents", strconv.FormatInt(r.CancelledSales, 10)},
+		{"cancelled_sales", currency.Format(r.CancelledSales)},
+	}
+	for _, saleType := range []order.SaleType{order.SaleTypeDelivery, order.SaleTypeOnSite} {
+		summary := r.BySaleType[saleType]
+		rows = append(rows,
+			[]string{"sale_type_" + string(saleType) + "_count", strconv.FormatInt(summary.OrderCount, 10)},
+			[]string{"sale_type_" + string(saleType) + "_sales_cents", strconv.FormatInt(summary.Sales, 10)},
+		)
+	}
+	for _, status := range order.AllStatuses {
+		rows = append(rows, []string{"orders_" + string(status), strconv.Itoa(r.OrdersByStatus[status])})
+	}
+	for _, channel := range order.AllChannels {
+		rows = append(rows, []string{"channel_" + string(channel), strconv.Itoa(r.OrdersByChannel[channel])})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	// Blank line between sections
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+
+	if err := cw.Write([]string{"product_id", "name", "total_quantity", "total_revenue_cents", "total_revenue"}); err != nil {
+		return err
+	}
+	for _, p := range r.TopProducts {
+		if err := cw.Write([]string{
+			p.ProductID,
+			p.Name,
+			strconv.Itoa(p.TotalQuantity),
+			strconv.FormatInt(p.TotalRevenue, 10),
+			currency.Format(p.TotalRevenue),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeZReportText writes the report as fixed-width plain text in the thermal receipt layout
+func writeZReportText(w io.Writer, r *order.ZReport, currency money.Currency) error {
+	var b strings.Builder
+	rule, separator := receipt.Rule("="), receipt.Rule("-")
+
+	b.WriteString(rule)
+	b.WriteString(receipt.Center("Z REPORT"))
+	b.WriteString(rule)
+	b.WriteString(receipt.Columns("Date", r.Date+" ("+r.Timezone+")"))
+	b.WriteString(receipt.Columns("From (UTC)", r.From.Format(time.RFC3339Nano)))
+	b.WriteString(receipt.Columns("To (UTC)", r.To.Format(time.RFC3339Nano)))
+	b.WriteString(separator)
+	b.WriteString(receipt.Columns("Orders", strconv.FormatInt(r.OrderCount, 10)))
+	b.WriteString(receipt.Columns("Sales", currency.Format(r.Sales)))
+	b.WriteString(receipt.Columns("Average ticket", currency.Format(r.AvgTicket)))
+	b.WriteString(receipt.Columns("Cancelled orders", strconv.FormatInt(r.CancelledCount, 10)))
+	b.WriteString(receipt.Columns("Cancelled amount", currency.Format(r.CancelledSales)))
+
+	b.WriteString(separator)
+	b.WriteString(receipt.Center("BY SALE TYPE"))
+	for _, saleType := range []order.SaleType{order.SaleTypeDelivery, order.SaleTypeOnSite} {
+		summary := r.BySaleType[saleType]
+		b.WriteString(receipt.Columns(fmt.Sprintf("%s (%d)", saleType, summary.OrderCount), currency.Format(summary.Sales)))
+	}
+
+	b.WriteString(separator)
+	b.WriteString(receipt.Center("BY STATUS"))
+	for _, status := range order.AllStatuses {
+		b.WriteString(receipt.Columns(string(status), strconv.Itoa(r.OrdersByStatus[status])))
+	}
+
+	b.WriteString(separator)
+	b.WriteString(receipt.Center("BY CHANNEL"))
+	for _, channel := range order.AllChannels {
+		b.WriteString(receipt.Columns(string(channel), strconv.Itoa(r.OrdersByChannel[channel])))
+	}
+
+	b.WriteString(separator)
+	b.WriteString(receipt.Center("TOP PRODUCTS"))
+	if len(r.TopProducts) == 0 {
+		b.WriteString(receipt.Center("No sales"))
+	}
+	for _, p := range r.TopProducts {
+		b.WriteString(receipt.Columns(fmt.Sprintf("%dx %s", p.TotalQuantity, p.Name), currency.Format(p.TotalRevenue)))
+	}
+	b.WriteString(rule)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/emerarteaga/products-api/internal/receipt"
+)
+
+// zReportService builds the report window with the domain math and fixed figures
+func zReportService(gotLoc *string) *mocks.OrderService {
+	return &mocks.OrderService{
+		GetZReportFunc: func(ctx context.Context, date string, loc *time.Location) (*order.ZReport, error) {
+			*gotLoc = loc.String()
+			from, to, err := order.ZReportWindow(date, loc)
+			if err != nil {
+				return nil, err
+			}
+			return &order.ZReport{
+				Date: date, Timezone: loc.String(), From: from, To: to,
+				OrderCount: 5, Sales: 8000050, AvgTicket: 2000012, CancelledCount: 1, CancelledSales: 1000000,
+				OrdersByStatus:  map[order.OrderStatus]int{order.StatusDelivered: 3, order.StatusCreated: 1, order.StatusCancelled: 1},
+				OrdersByChannel: map[order.Channel]int{order.ChannelPOS: 4, order.ChannelWeb: 1},
+				BySaleType: map[order.SaleType]order.ZReportSaleType{
+					order.SaleTypeOnSite:   {OrderCount: 3, Sales: 5000050},
+					order.SaleTypeDelivery: {OrderCount: 1, Sales: 3000000},
+				},
+				TopProducts: []order.ProductSalesSummary{
+					{ProductID: "p1", Name: "Hamburguesa, doble", TotalQuantity: 6, TotalRevenue: 6000000},
+					{ProductID: "p2", Name: "Limonada de coco", TotalQuantity: 4, TotalRevenue: 2000050},
+				},
+			}, nil
+		},
+		CurrencyFunc: func() money.Currency { return money.Currency{Code: "USD", MinorUnits: 2} },
+	}
+}
+
+func TestGetZReportFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		contentType string
+		golden      string
+	}{
+		{"csv", "csv", "text/csv; charset=utf-8", "z_report.csv.golden"},
+		{"printable text", "txt", "text/plain; charset=utf-8", "z_report.txt.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loc string
+			service := zReportService(&loc)
+			router := newOrderRouter(service)
+			router.GET("/api/v1/reports/z", NewOrderHandler(service).GetZReport)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/reports/z?date=2024-06-01&tz=America/Bogota&format="+tt.format, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got, want := w.Header().Get("Content-Disposition"), `attachment; filename="z-report_2024-06-01.`+tt.format+`"`; got != want {
+				t.Errorf("Content-Disposition = %q, want %q", got, want)
+			}
+			if tt.format == "txt" {
+				for i, line := range strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n") {
+					if n := utf8.RuneCountInString(line); n > receipt.Width {
+						t.Errorf("line %d is %d columns wide, want at most %d: %q", i+1, n, receipt.Width, line)
+					}
+				}
+			}
+			assertGolden(t, tt.golden, w.Body.Bytes())
+		})
+	}
+}
+
+func TestGetZReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantLoc    string
+		wantFrom   string
+		wantTo     string
+	}{
+		{"bogota day", "?date=2024-06-01&tz=America/Bogota", http.StatusOK, "America/Bogota", "2024-06-01T05:00:00Z", "2024-06-02T04:59:59.999Z"},
+		{"utc by default", "?date=2024-06-01", http.StatusOK, "UTC", "2024-06-01T00:00:00Z", "2024-06-01T23:59:59.999Z"},
+		{"explicit json", "?date=2024-11-03&tz=America/New_York&format=json", http.StatusOK, "America/New_York", "2024-11-03T04:00:00Z", "2024-11-04T04:59:59.999Z"},
+		{"unknown time zone", "?date=2024-06-01&tz=Mars/Olympus", http.StatusBadRequest, "", "", ""},
+		{"bad date", "?date=2024-13-01", http.StatusBadRequest, "UTC", "", ""},
+		{"unsupported format", "?date=2024-06-01&format=pdf", http.StatusBadRequest, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loc string
+			service := zReportService(&loc)
+			router := newOrderRouter(service)
+			router.GET("/api/v1/reports/z", NewOrderHandler(service).GetZReport)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/reports/z"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if loc != tt.wantLoc {
+				t.Errorf("service got time zone %q, want %q", loc, tt.wantLoc)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Data struct {
+					Timezone string `json:"timezone"`
+					Window   struct {
+						From string `json:"from"`
+						To   string `json:"to"`
+					} `json:"window"`
+					Sales    int64          `json:"sales"`
+					Currency money.Currency `json:"currency"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Data.Window.From != tt.wantFrom || resp.Data.Window.To != tt.wantTo {
+				t.Errorf("window = %s..%s, want %s..%s", resp.Data.Window.From, resp.Data.Window.To, tt.wantFrom, tt.wantTo)
+			}
+			if resp.Data.Timezone != tt.wantLoc || resp.Data.Sales != 8000050 || resp.Data.Currency.Code != "USD" {
+				t.Errorf("report = %+v", resp.Data)
+			}
+		})
+	}
+}
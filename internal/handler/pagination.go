@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// parsePagination parses the limit and offset query parameters.
+// A missing or non-positive limit falls back to the endpoint default,
+// while a limit above the endpoint maximum or a value that is not a number is rejected.
+func parsePagination(c *gin.Context, limits util.PageLimits) (int, int, error) {
+	limit, _, err := intParam(c, "limit")
+	if err != nil {
+		return 0, 0, err
+	}
+	offset, _, err := intParam(c, "offset")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if limit > int64(limits.MaxLimit) {
+		return 0, 0, &queryParamError{
+			Param: "limit",
+			Value: c.Query("limit"),
+			Err:   fmt.Errorf("must be less than or equal to %d", limits.MaxLimit),
+		}
+	}
+	if limit <= 0 {
+		limit = int64(limits.DefaultLimit)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return int(limit), int(offset), nil
+}
+
+// cappedIntParam parses an optional size parameter, rejecting values above max; 0 when it is absent
+func cappedIntParam(c *gin.Context, param string, max int) (int, error) {
+	n, _, err := intParam(c, param)
+	if err != nil {
+		return 0, err
+	}
+	if n > int64(max) {
+		return 0, &queryParamError{
+			Param: param,
+			Value: c.Query(param),
+			Err:   fmt.Errorf("must be less than or equal to %d", max),
+		}
+	}
+	return int(n), nil
+}
+
+// parseCursor parses the cursor query parameter, which opts into cursor pagination: nil when it is
+// absent, the start of the listing when it is empty, and the decoded cursor otherwise
+func parseCursor(c *gin.Context) (*util.PageCursor, error) {
+	value, ok := c.GetQuery("cursor")
+	if !ok {
+		return nil, nil
+	}
+	cursor, err := util.DecodePageCursor(value)
+	if err != nil {
+		return nil, &queryParamError{Param: "cursor", Value: value, Err: err}
+	}
+	return &cursor, nil
+}
+
+// nextCursor returns the encoded cursor of the page after a full page, or "" after the last page
+func nextCursor[T any](items []T, limit int, cursorOf func(T) util.PageCursor) string {
+	if len(items) == 0 || len(items) < limit {
+		return ""
+	}
+	return cursorOf(items[len(items)-1]).Encode()
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestGetAllPageLimits(t *testing.T) {
+	limits := util.PageLimits{DefaultLimit: 25, MaxLimit: 500}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantLimit  int
+		wantOffset int
+		wantBody   string
+	}{
+		{"missing limit uses the endpoint default", "", http.StatusOK, 25, 0, `"page_size":25`},
+		{"zero limit uses the endpoint default", "?limit=0", http.StatusOK, 25, 0, `"page_size":25`},
+		{"within the endpoint maximum", "?limit=300&offset=600", http.StatusOK, 300, 600, `"current_page":3,"total_pages":1,"total_items":0,"page_size":300`},
+		{"at the endpoint maximum", "?limit=500", http.StatusOK, 500, 0, `"page_size":500`},
+		{"negative offset starts at zero", "?offset=-1", http.StatusOK, 25, 0, `"current_page":1`},
+		{"above the maximum is rejected", "?limit=501", http.StatusBadRequest, 0, 0, `"field":"limit"`},
+		{"not a number is rejected", "?limit=many", http.StatusBadRequest, 0, 0, `"field":"limit"`},
+		{"offset not a number is rejected", "?offset=x", http.StatusBadRequest, 0, 0, `"field":"offset"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *order.OrderFilters
+			service := &mocks.OrderService{
+				PageLimitsFunc: func() util.PageLimits { return limits },
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					got = &filters
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if got != nil {
+					t.Error("rejected request reached the service")
+				}
+				return
+			}
+			if got.Limit != tt.wantLimit || got.Offset != tt.wantOffset {
+				t.Errorf("limit, offset = %d, %d, want %d, %d", got.Limit, got.Offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+// TestPaginationMetaFromTotal checks the page metadata is derived from the total listed with the page
+func TestPaginationMetaFromTotal(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		total    int64
+		wantMeta string
+	}{
+		{"first page", "?limit=20", 42, `"current_page":1,"total_pages":3,"total_items":42,"page_size":20`},
+		{"middle page", "?limit=20&offset=20", 42, `"current_page":2,"total_pages":3,"total_items":42,"page_size":20`},
+		{"last partial page", "?limit=20&offset=40", 42, `"current_page":3,"total_pages":3,"total_items":42,"page_size":20`},
+		{"exact pages", "?limit=20", 40, `"current_page":1,"total_pages":2,"total_items":40,"page_size":20`},
+		{"nothing matched", "?limit=20", 0, `"current_page":1,"total_pages":1,"total_items":0,"page_size":20`},
+	}
+
+	for _, tt := range tests {
+		t.Run("orders: "+tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					return mocks.SampleOrders(2), tt.total, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", true)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMeta) {
+				t.Errorf("body misses %s: %s", tt.wantMeta, w.Body.String())
+			}
+		})
+
+		t.Run("company products: "+tt.name, func(t *testing.T) {
+			service := &mocks.ProductService{
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					return []*product.Product{product.NewProduct(companyID, "sp-1", "Burger", "Platos", "")}, tt.total, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/company-1"+tt.query, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMeta) {
+				t.Errorf("body misses %s: %s", tt.wantMeta, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateCustomerPhone(t *testing.T) {
+	tests := []struct {
+		name           string
+		phone          string
+		wantStatus     int
+		wantBody       string
+		wantNormalized string
+	}{
+		{"international format", "+57-300-1234567", http.StatusCreated, `"status":"CREATED"`, "+573001234567"},
+		{"national format", "300 123 4567", http.StatusCreated, `"status":"CREATED"`, "+573001234567"},
+		{"impossible number", "200 123 4567", http.StatusUnprocessableEntity, `"field":"customer.phone"`, ""},
+		{"too short for binding", "123", http.StatusBadRequest, `"success":false`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var customer *order.Customer
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					customer = input.Customer
+					if err := input.Customer.NormalizePhone(order.DefaultPhoneCountryCode); err != nil {
+						return nil, fmt.Errorf("validation error: %w", err)
+					}
+					o := order.NewOrder(input.SaleType, input.Products)
+					o.Customer = input.Customer
+					return o, nil
+				},
+			}
+			body := `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+				"customer": {"identification": "1020304050", "id_type": "CC", "name": "Ana", "phone": "` + tt.phone + `"},
+				"products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]}`
+
+			w := serveJSON(newOrderRouter(service), http.MethodPost, "/api/v1/orders", body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if tt.wantNormalized == "" {
+				return
+			}
+			if customer.Phone != tt.phone || customer.PhoneNormalized != tt.wantNormalized {
+				t.Errorf("phone = %q, %q, want %q, %q", customer.Phone, customer.PhoneNormalized, tt.phone, tt.wantNormalized)
+			}
+		})
+	}
+}
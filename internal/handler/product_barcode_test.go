@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateProductBarcode(t *testing.T) {
+	body := func(barcode string) string {
+		return fmt.Sprintf(`{
+			"company_id": "company-1", "sale_point_id": "sale-point-1", "barcode": %q,
+			"name": "Pizza", "category": "Pizzas", "is_unlimited_stock": true,
+			"price_variations": [{"type": "small", "price": 1000}]
+		}`, barcode)
+	}
+
+	tests := []struct {
+		name       string
+		barcode    string
+		err        error
+		wantStatus int
+	}{
+		{"ean-13", "4006381333931", nil, http.StatusCreated},
+		{"empty barcode", "", nil, http.StatusCreated},
+		{"label", "LOT-2024/A", nil, http.StatusCreated},
+		{"wrong check digit", "4006381333932", nil, http.StatusBadRequest},
+		{"inner space", "LOT 2024", nil, http.StatusBadRequest},
+		{"duplicate", "4006381333931", fmt.Errorf("failed to create product: %w", product.ErrDuplicateBarcode), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.ProductService{
+				CreateFunc: func(ctx context.Context, input product.CreateInput) (*product.Product, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &product.Product{ID: "p1", Barcode: product.NormalizeBarcode(input.Barcode)}, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodPost, "/api/v1/products", body(tt.barcode), false)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetByBarcodeHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+	}{
+		{"found", "/api/v1/products/barcode/4006381333931?company_id=company-1", nil, http.StatusOK},
+		{"not found", "/api/v1/products/barcode/4006381333931?company_id=company-1", product.ErrProductNotFound, http.StatusNotFound},
+		{"missing company", "/api/v1/products/barcode/4006381333931", apperrors.NewDomainError(product.ErrInvalidCompanyID, "company_id", nil), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCompany, gotBarcode string
+			service := &mocks.ProductService{
+				GetByBarcodeFunc: func(ctx context.Context, companyID, barcode string) (*product.Product, error) {
+					gotCompany, gotBarcode = companyID, barcode
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &product.Product{ID: "p1", Barcode: &barcode}, nil
+				},
+			}
+			router := newProductRouter(service)
+			router.GET("/api/v1/products/barcode/:barcode", NewProductHandler(service).GetByBarcode)
+
+			w := serveJSON(router, http.MethodGet, tt.target, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if gotBarcode != "4006381333931" {
+				t.Errorf("barcode = %q, want 4006381333931", gotBarcode)
+			}
+			if tt.wantStatus == http.StatusOK && gotCompany != "company-1" {
+				t.Errorf("company = %q, want company-1", gotCompany)
+			}
+			// Same shape as the lookups by ID and SKU
+			if tt.wantStatus == http.StatusOK && !strings.Contains(w.Body.String(), `"is_currently_available":`) {
+				t.Errorf("body is not a product response: %s", w.Body.String())
+			}
+		})
+	}
+}
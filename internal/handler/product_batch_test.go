@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+// batchRepository returns its products matching the IDs, last stored first, like an unsorted $in query
+type batchRepository struct {
+	product.Repository
+	products []*product.Product
+	queried  [][]string
+}
+
+func (r *batchRepository) FindByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*product.Product, error) {
+	r.queried = append(r.queried, ids)
+	var found []*product.Product
+	for i := len(r.products) - 1; i >= 0; i-- {
+		p := r.products[i]
+		if (includeDeleted || !p.IsDeleted()) && slices.Contains(ids, p.ID) {
+			found = append(found, p)
+		}
+	}
+	return found, nil
+}
+
+func TestGetByIDsHandler(t *testing.T) {
+	newProduct := func(id string) *product.Product {
+		p := product.NewProduct("company-1", "sp-1", "Product "+id, "Platos", "")
+		p.ID = id
+		return p
+	}
+	burger, soda, closed, deleted := newProduct("p1"), newProduct("p2"), newProduct("p3"), newProduct("p4")
+	closed.IsAvailable = false
+	deletedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	deleted.DeletedAt = &deletedAt
+
+	tests := []struct {
+		name        string
+		target      string
+		body        string
+		wantStatus  int
+		wantIDs     []string
+		wantMissing []string
+		wantQueried []string
+	}{
+		{
+			name:        "request order with missing ids",
+			target:      "/api/v1/products/batch",
+			body:        `{"ids": ["p2", "nope", "p1", "p3", "p4"]}`,
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"p2", "p1", "p3", "p4"},
+			wantMissing: []string{"nope"},
+			wantQueried: []string{"p2", "nope", "p1", "p3", "p4"},
+		},
+		{
+			name:        "repeated ids are fetched and returned once",
+			target:      "/api/v1/products/batch",
+			body:        `{"ids": ["p1", "p2", "p1", "gone", "gone", "p2"]}`,
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"p1", "p2"},
+			wantMissing: []string{"gone"},
+			wantQueried: []string{"p1", "p2", "gone"},
+		},
+		{
+			name:        "only available",
+			target:      "/api/v1/products/batch?only_available=true",
+			body:        `{"ids": ["p3", "p1", "p4"]}`,
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"p1"},
+			wantMissing: []string{"p3", "p4"},
+			wantQueried: []string{"p3", "p1", "p4"},
+		},
+		{name: "no ids", target: "/api/v1/products/batch", body: `{"ids": []}`, wantStatus: http.StatusBadRequest},
+		{name: "blank id", target: "/api/v1/products/batch", body: `{"ids": ["p1", ""]}`, wantStatus: http.StatusBadRequest},
+		{name: "too many ids", target: "/api/v1/products/batch", body: `{"ids": ["p1"` + strings.Repeat(`, "p1"`, 100) + `]}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &batchRepository{products: []*product.Product{burger, soda, closed, deleted}}
+			svc := product.NewService(repo)
+			service := &mocks.ProductService{GetByIDsFunc: svc.GetByIDs}
+			router := newProductRouter(service)
+			router.POST("/api/v1/products/batch", NewProductHandler(service).GetByIDs)
+
+			w := serveJSON(router, http.MethodPost, tt.target, tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if len(repo.queried) != 0 {
+					t.Errorf("repository queried with %v, want no query", repo.queried)
+				}
+				return
+			}
+
+			var body struct {
+				Data struct {
+					Products []product.Product `json:"products"`
+					Missing  []string          `json:"missing"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, p := range body.Data.Products {
+				ids = append(ids, p.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || !reflect.DeepEqual(body.Data.Missing, tt.wantMissing) {
+				t.Errorf("products = %v, missing = %v; want %v and %v", ids, body.Data.Missing, tt.wantIDs, tt.wantMissing)
+			}
+			if len(repo.queried) != 1 || !reflect.DeepEqual(repo.queried[0], tt.wantQueried) {
+				t.Errorf("repository queried with %v, want one query for %v", repo.queried, tt.wantQueried)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestBulkSetAvailabilityHandler(t *testing.T) {
+	fritos := "Fritos"
+
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantInput  *product.BulkAvailabilityInput
+	}{
+		{
+			name:       "category",
+			body:       `{"sale_point_id": "sp1", "category": "Fritos", "is_available": false}`,
+			wantStatus: http.StatusOK,
+			wantInput:  &product.BulkAvailabilityInput{AvailabilitySelector: product.AvailabilitySelector{SalePointID: "sp1", Category: &fritos}},
+		},
+		{
+			name:       "ids",
+			body:       `{"ids": ["p1", "p2"], "is_available": true}`,
+			wantStatus: http.StatusOK,
+			wantInput:  &product.BulkAvailabilityInput{AvailabilitySelector: product.AvailabilitySelector{IDs: []string{"p1", "p2"}}, IsAvailable: true},
+		},
+		{name: "missing is_available", body: `{"ids": ["p1"]}`, wantStatus: http.StatusBadRequest},
+		{name: "blank id", body: `{"ids": [""], "is_available": false}`, wantStatus: http.StatusBadRequest},
+		{
+			name:       "category without sale point",
+			body:       `{"category": "Fritos", "is_available": false}`,
+			err:        product.ErrBulkAvailabilitySalePoint,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ids and category",
+			body:       `{"ids": ["p1"], "sale_point_id": "sp1", "category": "Fritos", "is_available": false}`,
+			err:        product.ErrAmbiguousBulkAvailability,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *product.BulkAvailabilityInput
+			service := &mocks.ProductService{
+				BulkSetAvailabilityFunc: func(ctx context.Context, input product.BulkAvailabilityInput) (*product.BulkAvailabilityResult, error) {
+					got = &input
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &product.BulkAvailabilityResult{Matched: 3, Modified: 2}, nil
+				},
+			}
+			router := newProductRouter(service)
+			router.POST("/api/v1/products/bulk/availability", NewProductHandler(service).BulkSetAvailability)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/products/bulk/availability", tt.body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantInput != nil && !reflect.DeepEqual(got, tt.wantInput) {
+				t.Errorf("input = %+v, want %+v", got, tt.wantInput)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != `{"success":true,"data":{"matched":3,"modified":2}}` {
+				t.Errorf("body = %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCopyProducts(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		result     *product.CopyResult
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"copied", `{"from_sale_point_id":"sp1","to_sale_point_id":"sp2","overwrite":true}`,
+			&product.CopyResult{Created: 3, Updated: 1, Skipped: 2}, nil, http.StatusOK, `"created":3,"updated":1,"skipped":2`},
+		{"same sale point", `{"from_sale_point_id":"sp1","to_sale_point_id":"sp1"}`,
+			nil, apperrors.NewDomainError(product.ErrCopySameSalePoint, "to_sale_point_id", "sp1"), http.StatusBadRequest, `"field":"to_sale_point_id"`},
+		{"duplicate sku", `{"from_sale_point_id":"sp1","to_sale_point_id":"sp2"}`,
+			nil, fmt.Errorf("failed to copy products: %w", product.ErrDuplicateSKU), http.StatusConflict, `copied SKU`},
+		{"no target", `{"from_sale_point_id":"sp1"}`, nil, nil, http.StatusBadRequest, `is required`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got product.CopyInput
+			service := &mocks.ProductService{
+				CopyFunc: func(ctx context.Context, input product.CopyInput) (*product.CopyResult, error) {
+					got = input
+					return tt.result, tt.err
+				},
+			}
+			router := newProductRouter(service)
+			router.POST("/api/v1/products/copy", NewProductHandler(service).CopyProducts)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/products/copy", tt.body, false)
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("status = %d, want %d with %s: %s", w.Code, tt.wantStatus, tt.wantBody, w.Body.String())
+			}
+			if tt.name == "copied" {
+				want := product.CopyInput{FromSalePointID: "sp1", ToSalePointID: "sp2", Overwrite: true}
+				if got != want {
+					t.Errorf("input = %+v, want %+v", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// productExportFlushEvery is the number of products written between flushes of the response
+const productExportFlushEvery = 100
+
+// productExportColumns are the import columns, led by the product and sale point of each row
+var productExportColumns = append([]string{"product_id", "sale_point_id"}, importColumns...)
+
+// productExportWriter writes the products of an export stream in one format
+type productExportWriter interface {
+	contentType() string
+	extension() string
+	header() error
+	write(p *product.Product) error
+	flush() error
+}
+
+// ExportProducts handles GET /api/v1/products/export?company_id=&sale_point_id=&format=csv|ndjson
+// The company's products matching the list filters are streamed oldest first; include_deleted=true from
+// an admin adds the soft deleted ones. CSV files have one row per price variation, sharing the product_id
+// column, and use the import columns so an exported sale point can be imported again. NDJSON has one
+// product per line.
+func (h *ProductHandler) ExportProducts(c *gin.Context) {
+	var w productExportWriter
+	switch format := c.DefaultQuery("format", "csv"); format {
+	case "csv":
+		w = newCSVProductExport(c.Writer)
+	case "ndjson":
+		w = newNDJSONProductExport(c.Writer)
+	default:
+		response.Error(c, http.StatusBadRequest, fmt.Errorf("unsupported export format: %s", format), "Format must be csv or ndjson")
+		return
+	}
+
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+	input := product.ExportInput{
+		CompanyID:   c.Query("company_id"),
+		SalePointID: c.Query("sale_point_id"),
+		Filters:     filters,
+	}
+
+	// The stream starts with the first product, so errors found before it still get a JSON response
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Type", w.contentType())
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products_%s.%s"`, time.Now().Format("2006-01-02"), w.extension()))
+		c.Status(http.StatusOK)
+		return w.header()
+	}
+
+	ctx := c.Request.Context()
+	rows := 0
+	err = h.service.Export(ctx, input, func(p *product.Product) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := w.write(p); err != nil {
+			return err
+		}
+		rows++
+		if rows%productExportFlushEvery == 0 {
+			if err := w.flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if !started {
+		if err == nil {
+			// No product matches: the file only has the header
+			err = start()
+		} else if isDomainError(err) {
+			respondError(c, http.StatusBadRequest, err, "Invalid export parameters")
+			return
+		} else {
+			logger.Error("failed to export products", "error", err, "company_id", input.CompanyID)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to export products")
+			return
+		}
+	}
+	if err == nil {
+		err = w.flush()
+	}
+
+	// Headers are already sent, so errors can only be logged
+	switch {
+	case ctx.Err() != nil:
+		logger.Info("product export stopped, client disconnected", "rows", rows, "company_id", input.CompanyID)
+	case err != nil:
+		logger.Error("failed to write product export", "error", err, "rows", rows, "company_id", input.CompanyID)
+	default:
+		logger.Info("product export completed", "rows", rows, "company_id", input.CompanyID)
+	}
+}
+
+// csvProductExport writes one CSV row per price variation of each product
+type csvProductExport struct {
+	cw *csv.Writer
+}
+
+func newCSVProductExport(out io.Writer) *csvProductExport {
+	return &csvProductExport{cw: csv.NewWriter(out)}
+}
+
+func (e *csvProductExport) contentType() string { return "text/csv; charset=utf-8" }
+
+func (e *csvProductExport) extension() string { return "csv" }
+
+func (e *csvProductExport) header() error {
+	return e.cw.Write(productExportColumns)
+}
+
+func (e *csvProductExport) write(p *product.Product) error {
+	stock := ""
+	if !p.IsUnlimitedStock && p.Stock != nil {
+		stock = strconv.Itoa(*p.Stock)
+	}
+	row := func(price, variationType string) []string {
+		return []string{
+			p.ID,
+			p.SalePointID,
+			p.Name,
+			p.Category,
+			p.Description,
+			price,
+			variationType,
+			strconv.FormatBool(p.IsAvailable),
+			stock,
+			strings.Join(p.Photos, "|"),
+		}
+	}
+
+	if len(p.PriceVariations) == 0 {
+		return e.cw.Write(row("", ""))
+	}
+	for _, variation := range p.PriceVariations {
+		if err := e.cw.Write(row(strconv.FormatInt(variation.Price, 10), variation.Type)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvProductExport) flush() error {
+	e.cw.Flush()
+	return e.cw.Error()
+}
+
+// ndjsonProductExport writes one product object per line
+type ndjsonProductExport struct {
+	enc *json.Encoder
+}
+
+func newNDJSONProductExport(out io.Writer) *ndjsonProductExport {
+	return &ndjsonProductExport{enc: json.NewEncoder(out)}
+}
+
+func (e *ndjsonProductExport) contentType() string { return "application/x-ndjson" }
+
+func (e *ndjsonProductExport) extension() string { return "ndjson" }
+
+func (e *ndjsonProductExport) header() error { return nil }
+
+func (e *ndjsonProductExport) write(p *product.Product) error {
+	return e.enc.Encode(p)
+}
+
+func (e *ndjsonProductExport) flush() error { return nil }
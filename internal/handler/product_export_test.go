@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+// exportProductRepository walks a fixed list of products and records the products inserted
+type exportProductRepository struct {
+	product.Repository
+	products []*product.Product
+	created  []*product.Product
+}
+
+func (r *exportProductRepository) EachByCompanyID(ctx context.Context, companyID string, filters product.ProductFilters, fn func(*product.Product) error) error {
+	for _, p := range r.products {
+		if p.CompanyID != companyID || (p.IsDeleted() && !filters.IncludeDeleted) {
+			continue
+		}
+		if filters.SalePointID != nil && p.SalePointID != *filters.SalePointID {
+			continue
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NextPosition starts every category empty
+func (r *exportProductRepository) NextPosition(ctx context.Context, salePointID, category string) (int, error) {
+	return 0, nil
+}
+
+func (r *exportProductRepository) CreateMany(ctx context.Context, products []*product.Product) error {
+	r.created = append(r.created, products...)
+	return nil
+}
+
+// newExportProductRouter serves export and import backed by the product service over the products
+func newExportProductRouter(products ...*product.Product) (*exportProductRepository, *httptest.Server) {
+	repo := &exportProductRepository{products: products}
+	svc := product.NewService(repo)
+	service := &mocks.ProductService{ExportFunc: svc.Export, ImportFunc: svc.Import}
+	h := NewProductHandler(service)
+	router := newProductRouter(service)
+	router.GET("/api/v1/products/export", h.ExportProducts)
+	router.POST("/api/v1/products/import", h.ImportProducts)
+	return repo, httptest.NewServer(router)
+}
+
+// exportFixture builds the products of the round trip tests in sale-point-1 of company-1
+func exportFixture() []*product.Product {
+	stock := 12
+	pizza := product.NewProduct("company-1", "sale-point-1", "Pizza", "Pizzas", "Mozzarella, tomate y \"albahaca\"")
+	pizza.Photos = []string{"https://a.example/1.png", "https://a.example/2.png"}
+	pizza.PriceVariations = []product.PriceVariation{{Type: "personal", Price: 18000}, {Type: "large", Price: 32000}}
+	pizza.IsUnlimitedStock, pizza.Stock = false, &stock
+
+	lemonade := product.NewProduct("company-1", "sale-point-1", "Limonada", "Bebidas", "")
+	lemonade.PriceVariations = []product.PriceVariation{{Type: product.DefaultVariationType, Price: 6000}}
+	lemonade.IsAvailable = false
+
+	deleted := product.NewProduct("company-1", "sale-point-1", "Arepa", "Entradas", "")
+	deleted.PriceVariations = []product.PriceVariation{{Type: product.DefaultVariationType, Price: 5000}}
+	deletedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	deleted.DeletedAt = &deletedAt
+
+	other := product.NewProduct("company-1", "sale-point-2", "Jugo", "Bebidas", "")
+	other.PriceVariations = []product.PriceVariation{{Type: product.DefaultVariationType, Price: 7000}}
+
+	return []*product.Product{pizza, lemonade, deleted, other}
+}
+
+// importedFields keeps the product fields the export file carries
+func importedFields(p *product.Product) product.Product {
+	variations := make([]product.PriceVariation, len(p.PriceVariations))
+	for i, v := range p.PriceVariations {
+		variations[i] = product.PriceVariation{Type: v.Type, Price: v.Price}
+	}
+	return product.Product{
+		CompanyID:        p.CompanyID,
+		SalePointID:      p.SalePointID,
+		Name:             p.Name,
+		Category:         p.Category,
+		Description:      p.Description,
+		Photos:           p.Photos,
+		PriceVariations:  variations,
+		IsAvailable:      p.IsAvailable,
+		IsUnlimitedStock: p.IsUnlimitedStock,
+		Stock:            p.Stock,
+	}
+}
+
+func TestExportProductsRoundTrip(t *testing.T) {
+	products := exportFixture()
+	repo, srv := newExportProductRouter(products...)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/products/export?company_id=company-1&sale_point_id=sale-point-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="products_`) || !strings.HasSuffix(got, `.csv"`) {
+		t.Errorf("Content-Disposition = %q, want a products CSV attachment", got)
+	}
+
+	var file strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	var productIDs []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		file.WriteString(line + "\n")
+		if id, _, _ := strings.Cut(line, ","); id != "product_id" {
+			productIDs = append(productIDs, id)
+		}
+	}
+	// One row per price variation; the deleted product and the other sale point are left out
+	wantIDs := []string{products[0].ID, products[0].ID, products[1].ID}
+	if !reflect.DeepEqual(productIDs, wantIDs) {
+		t.Fatalf("row product IDs = %v, want %v\n%s", productIDs, wantIDs, file.String())
+	}
+
+	w := httptest.NewRecorder()
+	req := importRequest(t, "/api/v1/products/import", file.String())
+	srv.Config.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("import status = %d: %s", w.Code, w.Body.String())
+	}
+
+	if len(repo.created) != 2 {
+		t.Fatalf("imported %d products, want 2", len(repo.created))
+	}
+	for i, p := range repo.created {
+		if p.ID == products[i].ID {
+			t.Errorf("imported product %q kept its ID, want a new one", p.Name)
+		}
+		if got, want := importedFields(p), importedFields(products[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("imported product = %+v\nwant %+v", got, want)
+		}
+	}
+}
+
+func TestExportProductsNDJSON(t *testing.T) {
+	products := exportFixture()
+	_, srv := newExportProductRouter(products...)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/products/export?company_id=company-1&format=ndjson&include_deleted=true", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var p product.Product
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, p.ID)
+	}
+	want := []string{products[0].ID, products[1].ID, products[2].ID, products[3].ID}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("exported IDs = %v, want every product of the company, deleted included: %v", ids, want)
+	}
+}
+
+func TestExportProductsRequests(t *testing.T) {
+	category, available := "Pizzas", true
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+		want       product.ExportInput
+	}{
+		{"unsupported format", "?company_id=company-1&format=xml", http.StatusBadRequest, "Format must be csv or ndjson", product.ExportInput{}},
+		{"company required", "", http.StatusBadRequest, "company_id", product.ExportInput{Filters: product.ProductFilters{TagsMode: product.TagsAny}}},
+		{"empty export has the header", "?company_id=company-1&category=Pizzas&is_available=true", http.StatusOK, "product_id,sale_point_id,name",
+			product.ExportInput{CompanyID: "company-1", Filters: product.ProductFilters{Category: &category, IsAvailable: &available, TagsMode: product.TagsAny}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got product.ExportInput
+			svc := product.NewService(&exportProductRepository{})
+			service := &mocks.ProductService{
+				ExportFunc: func(ctx context.Context, input product.ExportInput, emit product.ExportEmitFunc) error {
+					got = input
+					return svc.Export(ctx, input, emit)
+				},
+			}
+			router := newProductRouter(service)
+			router.GET("/api/v1/products/export", NewProductHandler(service).ExportProducts)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/products/export"+tt.query, "", false)
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("status = %d, want %d with %s: %s", w.Code, tt.wantStatus, tt.wantBody, w.Body.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("export input = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,22 +3,23 @@ package handler
 import (
 	"errors"
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/emerarteaga/products-api/internal/domain/product"
 	"github.com/emerarteaga/products-api/internal/dto"
 	"github.com/emerarteaga/products-api/internal/infra/logger"
 	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/emerarteaga/products-api/internal/util"
 	"github.com/gin-gonic/gin"
 )
 
 // ProductHandler handles HTTP requests for products
 type ProductHandler struct {
-	service *product.Service
+	service product.ServiceAPI
 }
 
 // NewProductHandler creates a new product handler
-func NewProductHandler(service *product.Service) *ProductHandler {
+func NewProductHandler(service product.ServiceAPI) *ProductHandler {
 	return &ProductHandler{service: service}
 }
 
@@ -50,12 +51,14 @@ func (h *ProductHandler) Create(c *gin.Context) {
 
 	p, err := h.service.Create(c.Request.Context(), input)
 	if err != nil {
+		statusCode := h.mapErrorToStatusCode(err)
 		logger.Error("failed to create product", "error", err)
-		response.Error(c, http.StatusInternalServerError, err, "Failed to create product")
+		respondError(c, statusCode, err, "Failed to create product")
 		return
 	}
 
 	logger.Info("product created", "product_id", p.ID, "company_id", p.CompanyID, "sale_point_id", p.SalePointID)
+	setVersionTag(c, p.Version)
 	response.Success(c, http.StatusCreated, p, "Product created successfully")
 }
 
@@ -74,7 +77,78 @@ func (h *ProductHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, p, "")
+	setVersionTag(c, p.Version)
+	response.Success(c, http.StatusOK, dto.ToProductResponse(p), "")
+}
+
+// GetBySKU handles GET /api/v1/products/sku/:sku?sale_point_id=...
+func (h *ProductHandler) GetBySKU(c *gin.Context) {
+	sku := c.Param("sku")
+
+	p, err := h.service.GetBySKU(c.Request.Context(), c.Query("sale_point_id"), sku)
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrProductNotFound):
+			response.Error(c, http.StatusNotFound, err, "Product not found")
+		case isDomainError(err):
+			respondError(c, http.StatusBadRequest, err, "Invalid SKU lookup")
+		default:
+			logger.Error("failed to get product by sku", "error", err, "sku", sku)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to get product")
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.ToProductResponse(p), "")
+}
+
+// GetByIDs handles POST /api/v1/products/batch?only_available=true
+// It returns up to 100 products in the order asked for, and the IDs that did not resolve as missing.
+func (h *ProductHandler) GetByIDs(c *gin.Context) {
+	var req dto.BatchProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.GetByIDs(c.Request.Context(), req.ToBatchInput(c.Query("only_available") == "true"))
+	if err != nil {
+		if errors.Is(err, product.ErrEmptyBatch) || errors.Is(err, product.ErrBatchTooLarge) {
+			response.Error(c, http.StatusBadRequest, err, "Invalid product selection")
+			return
+		}
+		logger.Error("failed to get products", "error", err, "ids", len(req.IDs))
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get products")
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.ToBatchProductsResponse(result), "")
+}
+
+// GetByBarcode handles GET /api/v1/products/barcode/:barcode?company_id=...
+func (h *ProductHandler) GetByBarcode(c *gin.Context) {
+	barcode := c.Param("barcode")
+
+	p, err := h.service.GetByBarcode(c.Request.Context(), c.Query("company_id"), barcode)
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrProductNotFound):
+			response.Error(c, http.StatusNotFound, err, "Product not found")
+		case isDomainError(err):
+			respondError(c, http.StatusBadRequest, err, "Invalid barcode lookup")
+		default:
+			logger.Error("failed to get product by barcode", "error", err, "barcode", barcode)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to get product")
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, dto.ToProductResponse(p), "")
 }
 
 // GetByCompanyID handles GET /api/v1/products/company/:company_id
@@ -82,18 +156,54 @@ func (h *ProductHandler) GetByCompanyID(c *gin.Context) {
 	companyID := c.Param("company_id")
 
 	// Parse filters from query parameters
-	filters := h.parseFilters(c)
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+
+	limit, offset, err := parsePagination(c, h.service.CompanyPageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+	filters.Limit = limit
+	filters.Offset = offset
+	if filters.Cursor, err = parseCursor(c); err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
 
 	products, total, err := h.service.GetByCompanyID(c.Request.Context(), companyID, filters)
 	if err != nil {
+		if errors.Is(err, util.ErrInvalidCursor) {
+			response.Error(c, http.StatusBadRequest, err, "Invalid pagination parameters")
+			return
+		}
 		logger.Error("failed to get products", "error", err, "company_id", companyID)
 		response.Error(c, http.StatusInternalServerError, err, "Failed to get products")
 		return
 	}
 
-	// Convert to list responses (simplified view)
-	listResponses := dto.ToListResponses(products)
-	response.Paginated(c, http.StatusOK, listResponses, total, filters.Limit, filters.Offset)
+	respondProducts(c, products, total, filters)
+}
+
+// GetCompanySummary handles GET /api/v1/products/company/:company_id/summary
+func (h *ProductHandler) GetCompanySummary(c *gin.Context) {
+	companyID := c.Param("company_id")
+
+	summary, err := h.service.GetCompanySummary(c.Request.Context(), companyID)
+	if err != nil {
+		if isDomainError(err) {
+			respondError(c, http.StatusBadRequest, err, "Invalid company")
+			return
+		}
+		logger.Error("failed to summarize products", "error", err, "company_id", companyID)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get product summary")
+		return
+	}
+
+	response.Success(c, http.StatusOK, summary, "")
 }
 
 // GetBySalePointID handles GET /api/v1/products/sale-point/:sale_point_id
@@ -101,20 +211,86 @@ func (h *ProductHandler) GetBySalePointID(c *gin.Context) {
 	salePointID := c.Param("sale_point_id")
 
 	// Parse filters from query parameters
-	filters := h.parseFilters(c)
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		respondQueryError(c, err, "Invalid filter parameters")
+		return
+	}
+
+	limit, offset, err := parsePagination(c, h.service.SalePointPageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+	filters.Limit = limit
+	filters.Offset = offset
+	if filters.Cursor, err = parseCursor(c); err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
 
 	products, total, err := h.service.GetBySalePointID(c.Request.Context(), salePointID, filters)
 	if err != nil {
+		if errors.Is(err, util.ErrInvalidCursor) {
+			response.Error(c, http.StatusBadRequest, err, "Invalid pagination parameters")
+			return
+		}
 		logger.Error("failed to get products", "error", err, "sale_point_id", salePointID)
 		response.Error(c, http.StatusInternalServerError, err, "Failed to get products")
 		return
 	}
 
-	// Convert to list responses (simplified view)
+	respondProducts(c, products, total, filters)
+}
+
+// respondProducts writes a page of products in the simplified list view, as an offset or a cursor page
+func respondProducts(c *gin.Context, products []*product.Product, total int64, filters product.ProductFilters) {
 	listResponses := dto.ToListResponses(products)
+	if filters.Cursor != nil {
+		next := nextCursor(products, filters.Limit, (*product.Product).PageCursor)
+		response.CursorPaginated(c, http.StatusOK, listResponses, filters.Limit, next)
+		return
+	}
 	response.Paginated(c, http.StatusOK, listResponses, total, filters.Limit, filters.Offset)
 }
 
+// GetMenu handles GET /api/v1/products/sale-point/:sale_point_id/menu?include_unavailable=true
+// Pagination applies to categories; products_per_category caps the products of each one.
+// The include_unavailable preview is for admins; storefront callers always get the orderable menu.
+func (h *ProductHandler) GetMenu(c *gin.Context) {
+	salePointID := c.Param("sale_point_id")
+
+	limit, offset, err := parsePagination(c, h.service.SalePointPageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+	perCategory, err := cappedIntParam(c, "products_per_category", product.MenuProductLimits.MaxLimit)
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+
+	filters := product.MenuFilters{
+		IncludeUnavailable: staffFlag(c, "include_unavailable"),
+		ProductLimit:       perCategory,
+		Limit:              limit,
+		Offset:             offset,
+	}
+	sections, total, err := h.service.GetMenu(c.Request.Context(), salePointID, filters)
+	if err != nil {
+		if isDomainError(err) {
+			respondError(c, http.StatusBadRequest, err, "Invalid sale point")
+			return
+		}
+		logger.Error("failed to get menu", "error", err, "sale_point_id", salePointID)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get menu")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, dto.ToMenuResponses(sections), total, limit, offset)
+}
+
 // Update handles PUT /api/v1/products/:id
 func (h *ProductHandler) Update(c *gin.Context) {
 	id := c.Param("id")
@@ -142,6 +318,13 @@ func (h *ProductHandler) Update(c *gin.Context) {
 
 	// Convert DTO to service input
 	input := req.ToUpdateInput()
+	input.Actor = strings.TrimSpace(c.GetHeader(actorHeader))
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err, "Invalid product version")
+		return
+	}
+	input.ExpectedVersion = version
 
 	p, err := h.service.Update(c.Request.Context(), id, input)
 	if err != nil {
@@ -149,75 +332,459 @@ func (h *ProductHandler) Update(c *gin.Context) {
 			response.Error(c, http.StatusNotFound, err, "Product not found")
 			return
 		}
+		statusCode := h.mapErrorToStatusCode(err)
 		logger.Error("failed to update product", "error", err, "product_id", id)
-		response.Error(c, http.StatusInternalServerError, err, "Failed to update product")
+		respondError(c, statusCode, err, "Failed to update product")
 		return
 	}
 
-	logger.Info("product updated", "product_id", id)
-	response.Success(c, http.StatusOK, p, "Product updated successfully")
+	logger.Info("product updated", "product_id", id, "version", p.Version)
+	setVersionTag(c, p.Version)
+	response.Success(c, http.StatusOK, dto.ToProductResponse(p), "Product updated successfully")
 }
 
-// Delete handles DELETE /api/v1/products/:id
+// AdjustStock handles PATCH /api/v1/products/:id/stock
+// The change is atomic, so concurrent decrements never take the stock below zero.
+func (h *ProductHandler) AdjustStock(c *gin.Context) {
+	id := c.Param("id")
+
+	var req dto.AdjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	p, err := h.service.AdjustStock(c.Request.Context(), id, req.ToStockAdjustment())
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrProductNotFound):
+			response.Error(c, http.StatusNotFound, err, "Product not found")
+		case errors.Is(err, product.ErrInsufficientStock):
+			response.Error(c, http.StatusConflict, err, "Not enough stock")
+		case errors.Is(err, product.ErrInvalidStockAdjustment):
+			response.Error(c, http.StatusBadRequest, err, "Invalid stock adjustment")
+		default:
+			statusCode := h.mapErrorToStatusCode(err)
+			logger.Error("failed to adjust stock", "error", err, "product_id", id)
+			respondError(c, statusCode, err, "Failed to adjust stock")
+		}
+		return
+	}
+
+	logger.Info("product stock adjusted", "product_id", id)
+	response.Success(c, http.StatusOK, p, "Stock updated successfully")
+}
+
+// Delete handles DELETE /api/v1/products/:id?hard=true
+// Products are soft deleted and can be restored; hard=true from an admin removes them for good.
 func (h *ProductHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
+	hard := staffFlag(c, "hard")
 
-	err := h.service.Delete(c.Request.Context(), id)
+	err := h.service.Delete(c.Request.Context(), id, hard)
 	if err != nil {
 		if errors.Is(err, product.ErrProductNotFound) {
 			response.Error(c, http.StatusNotFound, err, "Product not found")
 			return
 		}
+		if errors.Is(err, product.ErrProductInUse) {
+			response.Error(c, http.StatusConflict, err, "Open orders contain the product; delete it without hard=true to archive it instead")
+			return
+		}
 		logger.Error("failed to delete product", "error", err, "product_id", id)
 		response.Error(c, http.StatusInternalServerError, err, "Failed to delete product")
 		return
 	}
 
-	logger.Info("product deleted", "product_id", id)
+	logger.Info("product deleted", "product_id", id, "hard", hard)
 	response.Success(c, http.StatusOK, nil, "Product deleted successfully")
 }
 
+// Restore handles POST /api/v1/products/:id/restore
+func (h *ProductHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+
+	p, err := h.service.Restore(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, product.ErrProductNotFound) {
+			response.Error(c, http.StatusNotFound, err, "Product not found")
+			return
+		}
+		if errors.Is(err, product.ErrDuplicateSKU) {
+			response.Error(c, http.StatusConflict, err, "Another product of the sale point uses the SKU")
+			return
+		}
+		if errors.Is(err, product.ErrDuplicateBarcode) {
+			response.Error(c, http.StatusConflict, err, "Another product of the company uses the barcode")
+			return
+		}
+		logger.Error("failed to restore product", "error", err, "product_id", id)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to restore product")
+		return
+	}
+
+	logger.Info("product restored", "product_id", id)
+	response.Success(c, http.StatusOK, p, "Product restored successfully")
+}
+
+// BulkDelete handles POST /api/v1/products/bulk-delete?force=true&hard=true
+// Products are soft deleted; hard=true removes them for good unless open orders contain any of them.
+// Products referenced by recent orders block the deletion with 409 unless force=true.
+// Both flags are staff-only.
+func (h *ProductHandler) BulkDelete(c *gin.Context) {
+	var req dto.BulkDeleteProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	force := staffFlag(c, "force")
+	hard := staffFlag(c, "hard")
+	result, err := h.service.BulkDelete(c.Request.Context(), req.ToBulkDeleteInput(force, hard))
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrProductsReferenced):
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+				"message": "Some products are referenced by recent orders; an admin can retry with force=true to delete them anyway",
+				"data":    dto.ToBulkDeleteProductsResponse(result),
+			})
+		case errors.Is(err, product.ErrProductInUse):
+			response.Error(c, http.StatusConflict, err, "Open orders contain some of the products; delete them without hard=true to archive them instead")
+		case errors.Is(err, product.ErrEmptyBulkDelete),
+			errors.Is(err, product.ErrAmbiguousBulkDelete),
+			errors.Is(err, product.ErrBulkDeleteTooLarge):
+			response.Error(c, http.StatusBadRequest, err, "Invalid product selection")
+		default:
+			logger.Error("failed to bulk delete products", "error", err)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to delete products")
+		}
+		return
+	}
+
+	logger.Info("products bulk deleted",
+		"deleted", result.Deleted,
+		"skipped", result.Skipped,
+		"referenced", len(result.Referenced),
+		"force", force,
+		"hard", hard,
+	)
+	response.Success(c, http.StatusOK, dto.ToBulkDeleteProductsResponse(result), "")
+}
+
+// BulkSetAvailability handles POST /api/v1/products/bulk/availability
+// It marks every product of a sale point's category, or up to 500 listed products, as available or not.
+func (h *ProductHandler) BulkSetAvailability(c *gin.Context) {
+	var req dto.BulkAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	input := req.ToBulkAvailabilityInput()
+	result, err := h.service.BulkSetAvailability(c.Request.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrEmptyBulkAvailability),
+			errors.Is(err, product.ErrAmbiguousBulkAvailability),
+			errors.Is(err, product.ErrBulkAvailabilityTooLarge),
+			errors.Is(err, product.ErrBulkAvailabilitySalePoint):
+			response.Error(c, http.StatusBadRequest, err, "Invalid product selection")
+		default:
+			logger.Error("failed to bulk update availability", "error", err)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to update availability")
+		}
+		return
+	}
+
+	category := ""
+	if input.Category != nil {
+		category = *input.Category
+	}
+	logger.Info("products availability bulk updated",
+		"is_available", input.IsAvailable,
+		"sale_point_id", input.SalePointID,
+		"category", category,
+		"ids", len(input.IDs),
+		"matched", result.Matched,
+		"modified", result.Modified,
+	)
+	response.Success(c, http.StatusOK, dto.ToBulkAvailabilityResponse(result), "")
+}
+
+// ReorderProducts handles POST /api/v1/products/reorder
+// ordered_ids must list every live product of the category exactly once; otherwise nothing changes and
+// the response is 409 with the missing and unknown ids.
+func (h *ProductHandler) ReorderProducts(c *gin.Context) {
+	var req dto.ReorderProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.Reorder(c.Request.Context(), req.ToReorderInput())
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrReorderMismatch):
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+				"message": "List every product of the category exactly once",
+				"data":    result,
+			})
+		case isDomainError(err):
+			respondError(c, http.StatusBadRequest, err, "Invalid reorder")
+		default:
+			logger.Error("failed to reorder products", "error", err, "sale_point_id", req.SalePointID)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to reorder products")
+		}
+		return
+	}
+
+	logger.Info("products reordered",
+		"sale_point_id", req.SalePointID,
+		"category", req.Category,
+		"ids", len(req.OrderedIDs),
+		"reordered", result.Reordered,
+	)
+	response.Success(c, http.StatusOK, result, "")
+}
+
+// CopyProducts handles POST /api/v1/products/copy
+func (h *ProductHandler) CopyProducts(c *gin.Context) {
+	var req dto.CopyProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.Copy(c.Request.Context(), req.ToCopyInput())
+	if err != nil {
+		switch {
+		case isDomainError(err):
+			respondError(c, http.StatusBadRequest, err, "Invalid copy")
+		case errors.Is(err, product.ErrDuplicateSKU):
+			response.Error(c, http.StatusConflict, err, "Another product of the target sale point uses a copied SKU")
+		default:
+			logger.Error("failed to copy products", "error", err,
+				"from_sale_point_id", req.FromSalePointID, "to_sale_point_id", req.ToSalePointID)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to copy products")
+		}
+		return
+	}
+
+	logger.Info("products copied",
+		"from_sale_point_id", req.FromSalePointID,
+		"to_sale_point_id", req.ToSalePointID,
+		"overwrite", req.Overwrite,
+		"created", result.Created,
+		"updated", result.Updated,
+		"skipped", result.Skipped,
+	)
+	response.Success(c, http.StatusOK, result, "")
+}
+
 // GetCategoriesByCompanyID handles GET /api/v1/categories/company/:company_id
 func (h *ProductHandler) GetCategoriesByCompanyID(c *gin.Context) {
 	companyID := c.Param("company_id")
 
-	categories, err := h.service.GetCategoriesByCompanyID(c.Request.Context(), companyID)
+	filters, err := parseCategoryFilters(c, h.service.CompanyPageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+
+	categories, total, err := h.service.GetCategoriesByCompanyID(c.Request.Context(), companyID, filters)
 	if err != nil {
 		logger.Error("failed to get categories", "error", err, "company_id", companyID)
 		response.Error(c, http.StatusInternalServerError, err, "Failed to get categories")
 		return
 	}
 
-	response.Success(c, http.StatusOK, gin.H{"categories": categories}, "")
+	respondSummaries(c, "categories", categories, categoryName, total, filters)
 }
 
 // GetCategoriesBySalePointID handles GET /api/v1/categories/sale-point/:sale_point_id
 func (h *ProductHandler) GetCategoriesBySalePointID(c *gin.Context) {
 	salePointID := c.Param("sale_point_id")
 
-	categories, err := h.service.GetCategoriesBySalePointID(c.Request.Context(), salePointID)
+	filters, err := parseCategoryFilters(c, h.service.SalePointPageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+
+	categories, total, err := h.service.GetCategoriesBySalePointID(c.Request.Context(), salePointID, filters)
 	if err != nil {
 		logger.Error("failed to get categories", "error", err, "sale_point_id", salePointID)
 		response.Error(c, http.StatusInternalServerError, err, "Failed to get categories")
 		return
 	}
 
-	response.Success(c, http.StatusOK, gin.H{"categories": categories}, "")
+	respondSummaries(c, "categories", categories, categoryName, total, filters)
 }
 
-// parseFilters parses query parameters into ProductFilters
-func (h *ProductHandler) parseFilters(c *gin.Context) product.ProductFilters {
-	filters := product.ProductFilters{}
+// RenameCategory handles POST /api/v1/categories/rename?merge=true
+// Renaming into a differently cased duplicate of an existing category returns 409 unless merge=true.
+func (h *ProductHandler) RenameCategory(c *gin.Context) {
+	var req dto.RenameCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("invalid request body", "error", err)
+		if errorMsg, details := FormatValidationErrors(err); details != nil {
+			response.ValidationError(c, http.StatusBadRequest, errorMsg, "Validation failed", errorDetails(err))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	merge := c.Query("merge") == "true"
+	result, err := h.service.RenameCategory(c.Request.Context(), req.ToCategoryRenameInput(merge))
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrCategoryCaseConflict):
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+				"message": "Retry with merge=true to merge the existing categories into the new name",
+				"data":    result,
+			})
+		case isDomainError(err):
+			respondError(c, http.StatusBadRequest, err, "Invalid category rename")
+		default:
+			logger.Error("failed to rename category", "error", err, "company_id", req.CompanyID)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to rename category")
+		}
+		return
+	}
+
+	logger.Info("category renamed",
+		"company_id", req.CompanyID,
+		"sale_point_id", req.SalePointID,
+		"from", req.From,
+		"to", req.To,
+		"renamed", result.Renamed,
+		"merged", len(result.Merged),
+	)
+	response.Success(c, http.StatusOK, result, "")
+}
 
-	// Parse limit and offset
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+// GetTagsByCompanyID handles GET /api/v1/tags/company/:company_id
+// It accepts the same query parameters as the categories endpoints.
+func (h *ProductHandler) GetTagsByCompanyID(c *gin.Context) {
+	companyID := c.Param("company_id")
+
+	filters, err := parseCategoryFilters(c, h.service.CompanyPageLimits())
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+
+	tags, total, err := h.service.GetTagsByCompanyID(c.Request.Context(), companyID, filters)
+	if err != nil {
+		logger.Error("failed to get tags", "error", err, "company_id", companyID)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get tags")
+		return
+	}
+
+	respondSummaries(c, "tags", tags, tagName, total, filters)
+}
+
+// parseCategoryFilters parses the category and tag query parameters.
+// format=plain keeps the legacy unpaginated list of names for existing clients.
+func parseCategoryFilters(c *gin.Context, limits util.PageLimits) (product.CategoryFilters, error) {
+	filters := product.CategoryFilters{
+		OnlyWithAvailable: c.Query("only_with_available") == "true",
+		All:               c.Query("format") == "plain",
+	}
+	if filters.All {
+		return filters, nil
+	}
+
+	limit, offset, err := parsePagination(c, limits)
+	if err != nil {
+		return filters, err
+	}
 	filters.Limit = limit
 	filters.Offset = offset
+	return filters, nil
+}
+
+// respondSummaries writes either the plain list of names under key or a page of category or tag summaries
+func respondSummaries[T any](c *gin.Context, key string, summaries []T, name func(T) string, total int64, filters product.CategoryFilters) {
+	if filters.All {
+		names := make([]string, len(summaries))
+		for i, s := range summaries {
+			names[i] = name(s)
+		}
+		response.Success(c, http.StatusOK, gin.H{key: names}, "")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, summaries, total, filters.Limit, filters.Offset)
+}
+
+func categoryName(s product.CategorySummary) string { return s.Name }
 
-	// Parse category filter
+func tagName(s product.TagSummary) string { return s.Name }
+
+// parseFilters parses query parameters into ProductFilters (pagination is parsed separately)
+func (h *ProductHandler) parseFilters(c *gin.Context) (product.ProductFilters, error) {
+	filters := product.ProductFilters{}
+
+	// Parse category filter (comma-separated for several categories)
 	if category := c.Query("category"); category != "" {
-		filters.Category = &category
+		categories := strings.Split(category, ",")
+		if len(categories) == 1 {
+			filters.Category = &category
+		} else {
+			for _, cat := range categories {
+				if cat = strings.TrimSpace(cat); cat != "" {
+					filters.Categories = append(filters.Categories, cat)
+				}
+			}
+		}
+	}
+
+	// Parse price range filters (cents); a product matches when one of its variations is in range
+	if minPrice, ok, err := intParam(c, "min_price"); err != nil {
+		return filters, err
+	} else if ok {
+		filters.MinPrice = &minPrice
+	}
+	if maxPrice, ok, err := intParam(c, "max_price"); err != nil {
+		return filters, err
+	} else if ok {
+		filters.MaxPrice = &maxPrice
+	}
+	if filters.MinPrice != nil && filters.MaxPrice != nil && *filters.MinPrice > *filters.MaxPrice {
+		return filters, &queryParamError{Param: "min_price", Value: c.Query("min_price"), Err: product.ErrInvalidPriceRange}
 	}
 
 	// Parse is_available filter
@@ -232,5 +799,63 @@ func (h *ProductHandler) parseFilters(c *gin.Context) product.ProductFilters {
 		filters.IsAddon = &isAddon
 	}
 
-	return filters
+	// Parse search over name and description (best matches first)
+	if search := strings.Join(strings.Fields(c.Query("search")), " "); search != "" {
+		filters.Search = &search
+	}
+
+	// Only products that can be ordered now, by their availability schedule
+	filters.AvailableNow = c.Query("available_now") == "true"
+
+	// Listings can skip the total count when the client doesn't need it
+	filters.SkipCount = c.Query("skip_count") == "true"
+
+	// Admin listings can show soft deleted products to restore them
+	filters.IncludeDeleted = staffFlag(c, "include_deleted")
+
+	// Parse tags filter (comma-separated, matching any of them unless tags_mode=all)
+	var tags []string
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		filters.Tags = product.NormalizeTags(tags)
+	}
+	filters.TagsMode = product.TagsAny
+	if mode := c.Query("tags_mode"); mode != "" {
+		filters.TagsMode = product.TagsMode(strings.ToLower(mode))
+		if err := enumParam("tags_mode", filters.TagsMode, product.AllTagsModes, product.ErrInvalidTagsMode); err != nil {
+			return filters, err
+		}
+	}
+
+	return filters, nil
+}
+
+// mapErrorToStatusCode maps domain errors to HTTP status codes
+func (h *ProductHandler) mapErrorToStatusCode(err error) int {
+	switch {
+	case errors.Is(err, product.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, product.ErrBlankDescription),
+		errors.Is(err, product.ErrDescriptionTooLong),
+		errors.Is(err, product.ErrTooManyQuickObservations),
+		errors.Is(err, product.ErrInvalidQuickObservation),
+		errors.Is(err, product.ErrQuickObservationTooLong),
+		errors.Is(err, product.ErrDuplicateQuickObservation),
+		errors.Is(err, product.ErrPhotoHostNotAllowed),
+		errors.Is(err, product.ErrProductTooLarge),
+		errors.Is(err, product.ErrNegativeStock),
+		errors.Is(err, product.ErrCannotUpdateStockForUnlimited):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, product.ErrDuplicateSKU), errors.Is(err, product.ErrDuplicateBarcode),
+		errors.Is(err, product.ErrProductConflict):
+		return http.StatusConflict
+	case isDomainError(err):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
 }
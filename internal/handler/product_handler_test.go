@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/gin-gonic/gin"
+)
+
+// newProductRouter serves the product routes under test with the production paths and admin authentication
+func newProductRouter(service product.ServiceAPI) *gin.Engine {
+	h := NewProductHandler(service)
+	router := gin.New()
+	router.Use(customhttp.Authenticate(testAdminToken))
+	products := router.Group("/api/v1/products")
+	products.POST("", h.Create)
+	products.POST("/bulk-delete", h.BulkDelete)
+	products.PUT("/:id", h.Update)
+	products.GET("/company/:company_id", h.GetByCompanyID)
+	return router
+}
+
+// validatingProductService builds and validates the product like the service would, without storage
+func validatingProductService() *mocks.ProductService {
+	return &mocks.ProductService{
+		CreateFunc: func(ctx context.Context, input product.CreateInput) (*product.Product, error) {
+			p := product.NewProduct(input.CompanyID, input.SalePointID, input.Name, input.Category, input.Description)
+			p.PriceVariations = input.PriceVariations
+			p.QuickObservations = input.QuickObservations
+			p.IsUnlimitedStock = input.IsUnlimitedStock
+			if err := p.Validate(); err != nil {
+				return nil, err
+			}
+			return p, nil
+		},
+	}
+}
+
+// productBody is a valid create request with the given quick observations JSON array
+func productBody(quickObservations string) string {
+	return `{"company_id":"c1","sale_point_id":"s1","name":"Hamburguesa","category":"Platos",` +
+		`"price_variations":[{"type":"Normal","price":15000}],"is_unlimited_stock":true,` +
+		`"quick_observations":` + quickObservations + `}`
+}
+
+func TestCreateProductQuickObservations(t *testing.T) {
+	tests := []struct {
+		name         string
+		observations string
+		wantStatus   int
+		wantBody     string
+	}{
+		{"valid", `["Sin cebolla","Extra salsa"]`, http.StatusCreated, `"quick_observations":["Sin cebolla","Extra salsa"]`},
+		{"too many", `["a","b","c","d","e","f","g","h","i","j","k"]`, http.StatusBadRequest, `quick_observations`},
+		{"too long", `["` + strings.Repeat("a", 51) + `"]`, http.StatusBadRequest, `quick_observations`},
+		{"blank", `["Sin cebolla","   "]`, http.StatusUnprocessableEntity, `quick_observations[1]`},
+		{"duplicate", `["Sin cebolla","sin cebolla"]`, http.StatusUnprocessableEntity, `quick_observations[1]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveJSON(newProductRouter(validatingProductService()), http.MethodPost, "/api/v1/products", productBody(tt.observations), false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestProductSearchFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string // Empty when no search applies
+	}{
+		{"", ""},
+		{"?search=%20%20", ""},
+		{"?search=arepa", "arepa"},
+		{"?search=%20salsa%20%20jalape%C3%B1o%20", "salsa jalapeño"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got *product.ProductFilters
+			service := &mocks.ProductService{
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					got = &filters
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/c1"+tt.query, "", true)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			search := ""
+			if got.Search != nil {
+				search = *got.Search
+			}
+			if search != tt.want {
+				t.Errorf("search = %q, want %q", search, tt.want)
+			}
+		})
+	}
+}
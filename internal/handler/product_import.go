@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+// importFormOverhead is the room left in the request body for the multipart headers and form fields
+const importFormOverhead = 64 << 10
+
+// utf8BOM starts the CSV files Excel saves as UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// importColumns lists the columns of an import file; name, category and price are required
+var importColumns = []string{"name", "category", "description", "price", "variation_type", "is_available", "stock", "photos"}
+
+// errInvalidImportFile reports a file that cannot be read as an import CSV
+var errInvalidImportFile = errors.New("invalid import file")
+
+// ImportProducts handles POST /api/v1/products/import?dry_run=true
+// The multipart form carries company_id, sale_point_id and the CSV as file. Nothing is created when
+// any row is invalid; the response then has status 422 and lists the errors by row in data.errors.
+func (h *ProductHandler) ImportProducts(c *gin.Context) {
+	limits := h.service.ImportLimits()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(limits.MaxBytes)+importFormOverhead)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(c, http.StatusRequestEntityTooLarge, err, fmt.Sprintf("Import files are limited to %d bytes", limits.MaxBytes))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "A CSV file is required in the file form field")
+		return
+	}
+	defer file.Close()
+	if header.Size > int64(limits.MaxBytes) {
+		response.Error(c, http.StatusRequestEntityTooLarge, fmt.Errorf("file has %d bytes", header.Size), fmt.Sprintf("Import files are limited to %d bytes", limits.MaxBytes))
+		return
+	}
+
+	rows, err := parseImportCSV(file, limits.MaxRows)
+	if err != nil {
+		if errors.Is(err, product.ErrImportTooManyRows) {
+			response.Error(c, http.StatusBadRequest, err, fmt.Sprintf("Import files are limited to %d rows", limits.MaxRows))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, "Invalid CSV file")
+		return
+	}
+
+	input := product.ImportInput{
+		CompanyID:   c.PostForm("company_id"),
+		SalePointID: c.PostForm("sale_point_id"),
+		Rows:        rows,
+		DryRun:      c.Query("dry_run") == "true",
+	}
+	result, err := h.service.Import(c.Request.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrImportInvalid):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"success": false,
+				"error":   err.Error(),
+				"message": "No products were imported; fix the rows listed in data.errors",
+				"data":    result,
+			})
+		case isDomainError(err), errors.Is(err, product.ErrImportEmpty), errors.Is(err, product.ErrImportTooManyRows):
+			respondError(c, http.StatusBadRequest, err, "Invalid import")
+		default:
+			logger.Error("failed to import products", "error", err, "sale_point_id", input.SalePointID)
+			response.Error(c, http.StatusInternalServerError, err, "Failed to import products")
+		}
+		return
+	}
+
+	if result.DryRun {
+		response.Success(c, http.StatusOK, result, "All rows are valid")
+		return
+	}
+	logger.Info("products imported",
+		"company_id", input.CompanyID,
+		"sale_point_id", input.SalePointID,
+		"rows", result.Rows,
+		"created", result.Created,
+	)
+	response.Success(c, http.StatusCreated, result, "Products imported successfully")
+}
+
+// parseImportCSV reads the rows of an import file. The header names the columns, in any order and case;
+// unknown columns are ignored. Excel files are accepted as saved in any locale: with or without a UTF-8 BOM,
+// separated by commas or, as Spanish locales do, semicolons.
+func parseImportCSV(r io.Reader, maxRows int) ([]product.ImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = importDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, product.ErrImportEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidImportFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "category", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q, expected %s", errInvalidImportFile, required, strings.Join(importColumns, ", "))
+		}
+	}
+
+	var rows []product.ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidImportFile, err)
+		}
+		if isBlankRecord(record) {
+			continue // Excel keeps trailing rows of empty cells
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: max %d", product.ErrImportTooManyRows, maxRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		rows = append(rows, product.ImportRow{
+			Line:          line,
+			Name:          cell("name"),
+			Category:      cell("category"),
+			Description:   cell("description"),
+			Price:         cell("price"),
+			VariationType: cell("variation_type"),
+			IsAvailable:   cell("is_available"),
+			Stock:         cell("stock"),
+			Photos:        cell("photos"),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, product.ErrImportEmpty
+	}
+	return rows, nil
+}
+
+// importDelimiter picks the separator the header line uses most, semicolon or comma
+func importDelimiter(data []byte) rune {
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// isBlankRecord reports whether every cell of the record is empty
+func isBlankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+// importRequest builds the multipart import form with the CSV as file
+func importRequest(t *testing.T, target, csv string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("company_id", "company-1")
+	_ = form.WriteField("sale_point_id", "sale-point-1")
+	file, err := form.CreateFormFile("file", "menu.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.Write([]byte(csv))
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestParseImportCSV(t *testing.T) {
+	want := []product.ImportRow{
+		{Line: 2, Name: "Pizza", Category: "Pizzas", Price: "20000", VariationType: "large", Photos: "https://a.example/1.png|https://a.example/2.png"},
+		{Line: 4, Name: "Limonada", Category: "Bebidas", Description: "Natural; sin azúcar", Price: "6000", IsAvailable: "sí"},
+	}
+
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"commas", "name,category,price,variation_type,photos,description,is_available\n" +
+			"Pizza,Pizzas,20000,large,https://a.example/1.png|https://a.example/2.png,,\n" +
+			",,,,,,\n" +
+			"Limonada,Bebidas,6000,,,\"Natural; sin azúcar\",sí\n"},
+		{"excel in a spanish locale", "\xEF\xBB\xBFName;Category;Price;Variation_Type;Photos;Description;Is_Available;Notes\r\n" +
+			"Pizza;Pizzas;20000;large;https://a.example/1.png|https://a.example/2.png;;;check\r\n" +
+			";;;;;;;\r\n" +
+			"Limonada;Bebidas;6000;;;\"Natural; sin azúcar\";sí\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseImportCSV(strings.NewReader(tt.csv), 10)
+			if err != nil {
+				t.Fatalf("parseImportCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(rows, want) {
+				t.Errorf("rows = %+v\nwant %+v", rows, want)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name    string
+		csv     string
+		maxRows int
+		wantErr error
+	}{
+		{"missing price column", "name,category\nPizza,Pizzas\n", 10, errInvalidImportFile},
+		{"empty file", "", 10, product.ErrImportEmpty},
+		{"header only", "name,category,price\n", 10, product.ErrImportEmpty},
+		{"too many rows", "name,category,price\nA,B,1\nC,D,2\n", 1, product.ErrImportTooManyRows},
+		{"unterminated quote", "name,category,price\n\"Pizza,Pizzas,1\n", 10, errInvalidImportFile},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseImportCSV(strings.NewReader(tt.csv), tt.maxRows); !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseImportCSV() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportProductsHandler(t *testing.T) {
+	const csv = "name,category,price\nPizza,Pizzas,20000\n"
+
+	tests := []struct {
+		name       string
+		target     string
+		csv        string
+		result     *product.ImportResult
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"created", "/api/v1/products/import", csv, &product.ImportResult{Rows: 1, Products: 1, Created: 1}, nil, http.StatusCreated, `"created":1`},
+		{"dry run", "/api/v1/products/import?dry_run=true", csv, &product.ImportResult{Rows: 1, Products: 1, DryRun: true}, nil, http.StatusOK, `"dry_run":true`},
+		{"invalid rows", "/api/v1/products/import", csv,
+			&product.ImportResult{Rows: 1, Errors: []product.ImportRowError{{Row: 2, Field: "price", Message: "bad"}}}, product.ErrImportInvalid,
+			http.StatusUnprocessableEntity, `"errors":[{"row":2,"field":"price","message":"bad"}]`},
+		{"invalid file", "/api/v1/products/import", "name\nPizza\n", nil, nil, http.StatusBadRequest, `missing column`},
+		{"file too large", "/api/v1/products/import", csv + strings.Repeat("x", 2048), nil, nil, http.StatusRequestEntityTooLarge, `limited to 1024 bytes`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got product.ImportInput
+			service := &mocks.ProductService{
+				ImportFunc: func(ctx context.Context, input product.ImportInput) (*product.ImportResult, error) {
+					got = input
+					return tt.result, tt.err
+				},
+				ImportLimitsFunc: func() product.ImportLimits { return product.ImportLimits{MaxBytes: 1024, MaxRows: 10} },
+			}
+			router := newProductRouter(service)
+			router.POST("/api/v1/products/import", NewProductHandler(service).ImportProducts)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, importRequest(t, tt.target, tt.csv))
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("status = %d, want %d with %s: %s", w.Code, tt.wantStatus, tt.wantBody, w.Body.String())
+			}
+			if tt.result != nil && (got.CompanyID != "company-1" || got.SalePointID != "sale-point-1" || got.DryRun != tt.result.DryRun) {
+				t.Errorf("import input = %+v, want the form tenants and dry run %v", got, tt.result.DryRun)
+			}
+		})
+	}
+}
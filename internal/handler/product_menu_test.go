@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestGetMenuResponseShape(t *testing.T) {
+	var got product.MenuFilters
+	service := &mocks.ProductService{
+		GetMenuFunc: func(ctx context.Context, salePointID string, filters product.MenuFilters) ([]product.MenuSection, int64, error) {
+			got = filters
+			return []product.MenuSection{
+				{Category: "Pizzas", ProductCount: 3, Products: []*product.Product{
+					{ID: "p1", Name: "Margarita", Category: "Pizzas", IsAvailable: true, IsUnlimitedStock: true,
+						PriceVariations: []product.PriceVariation{{Type: "Normal", Price: 20000}, {Type: "Small", Price: 15000}}},
+				}},
+				{Category: "Drinks", ProductCount: 1, Products: []*product.Product{
+					{ID: "p2", Name: "Limonada", Category: "Drinks", IsAvailable: false, IsUnlimitedStock: true},
+				}},
+			}, 4, nil
+		},
+	}
+	router := newProductRouter(service)
+	router.GET("/api/v1/products/sale-point/:sale_point_id/menu", NewProductHandler(service).GetMenu)
+
+	w := serveJSON(router, http.MethodGet,
+		"/api/v1/products/sale-point/sp1/menu?include_unavailable=true&products_per_category=1&limit=2&offset=2", "", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if want := (product.MenuFilters{IncludeUnavailable: true, ProductLimit: 1, Limit: 2, Offset: 2}); got != want {
+		t.Errorf("filters = %+v, want %+v", got, want)
+	}
+
+	var body struct {
+		Data []struct {
+			Category     string                   `json:"category"`
+			ProductCount int                      `json:"product_count"`
+			Products     []map[string]interface{} `json:"products"`
+		} `json:"data"`
+		Meta struct {
+			TotalItems int64 `json:"total_items"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Data) != 2 || body.Meta.TotalItems != 4 {
+		t.Fatalf("got %d sections of %d, want 2 of 4: %s", len(body.Data), body.Meta.TotalItems, w.Body.String())
+	}
+	pizzas := body.Data[0]
+	if pizzas.Category != "Pizzas" || pizzas.ProductCount != 3 || len(pizzas.Products) != 1 {
+		t.Fatalf("first section = %+v, want Pizzas with 1 of 3 products", pizzas)
+	}
+	// Products use the simplified list view
+	margarita := pizzas.Products[0]
+	if margarita["min_price"] != float64(15000) || margarita["price_variations"] != nil {
+		t.Errorf("product = %v, want the list view with min_price 15000", margarita)
+	}
+	wantAvailability := map[string]interface{}{"available": false, "reason": "MANUALLY_DISABLED"}
+	if got := body.Data[1].Products[0]["availability"]; !reflect.DeepEqual(got, wantAvailability) {
+		t.Errorf("disabled product availability = %v, want %v", got, wantAvailability)
+	}
+}
+
+func TestMenuPreviewIsStaffOnly(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		admin bool
+		want  bool
+	}{
+		{"admin preview", "?include_unavailable=true", true, true},
+		{"storefront preview is ignored", "?include_unavailable=true", false, false},
+		{"storefront menu", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *product.MenuFilters
+			service := &mocks.ProductService{
+				GetMenuFunc: func(ctx context.Context, salePointID string, filters product.MenuFilters) ([]product.MenuSection, int64, error) {
+					got = &filters
+					return nil, 0, nil
+				},
+			}
+			router := newProductRouter(service)
+			router.GET("/api/v1/products/sale-point/:sale_point_id/menu", NewProductHandler(service).GetMenu)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/products/sale-point/sp1/menu"+tt.query, "", tt.admin)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+			}
+			if got == nil || got.IncludeUnavailable != tt.want {
+				t.Errorf("filters = %+v, want IncludeUnavailable %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMenuErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+		wantCalled bool
+	}{
+		{"too many products per category", "/api/v1/products/sale-point/sp1/menu?products_per_category=101", nil, http.StatusBadRequest, false},
+		{"invalid products per category", "/api/v1/products/sale-point/sp1/menu?products_per_category=many", nil, http.StatusBadRequest, false},
+		{"invalid sale point", "/api/v1/products/sale-point/sp1/menu",
+			apperrors.NewDomainError(product.ErrInvalidSalePointID, "sale_point_id", nil), http.StatusBadRequest, true},
+		{"repository failure", "/api/v1/products/sale-point/sp1/menu", errors.New("connection refused"), http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.ProductService{
+				GetMenuFunc: func(ctx context.Context, salePointID string, filters product.MenuFilters) ([]product.MenuSection, int64, error) {
+					called = true
+					return nil, 0, tt.err
+				},
+			}
+			router := newProductRouter(service)
+			router.GET("/api/v1/products/sale-point/:sale_point_id/menu", NewProductHandler(service).GetMenu)
+
+			w := serveJSON(router, http.MethodGet, tt.target, "", false)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if called != tt.wantCalled {
+				t.Errorf("service called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestProductPriceFilters(t *testing.T) {
+	price := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+		wantMin    *int64
+		wantMax    *int64
+	}{
+		{"no range", "", http.StatusOK, "", nil, nil},
+		{"range", "?min_price=500&max_price=900&category=Pizzas", http.StatusOK, "", price(500), price(900)},
+		{"min only", "?min_price=500", http.StatusOK, "", price(500), nil},
+		{"same bounds", "?min_price=500&max_price=500", http.StatusOK, "", price(500), price(500)},
+		{"not a number", "?max_price=9.5", http.StatusBadRequest, `'max_price' must be a whole number`, nil, nil},
+		{"min above max", "?min_price=900&max_price=500", http.StatusBadRequest, `'min_price' cannot be greater than max_price`, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *product.ProductFilters
+			service := &mocks.ProductService{
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					got = &filters
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/c1"+tt.query, "", false)
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("status = %d, want %d with %s: %s", w.Code, tt.wantStatus, tt.wantBody, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if got != nil {
+					t.Error("service called with rejected filters")
+				}
+				return
+			}
+			if !equalPrice(got.MinPrice, tt.wantMin) || !equalPrice(got.MaxPrice, tt.wantMax) {
+				t.Errorf("range = %v-%v, want %v-%v", got.MinPrice, got.MaxPrice, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func equalPrice(a, b *int64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// actorHeader optionally names who makes a product update; it is recorded in the price history
+const actorHeader = "X-Actor"
+
+// GetPriceHistory handles GET /api/v1/products/:id/price-history?limit=50&offset=0
+// Changes are listed newest first.
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	limit, offset, err := parsePagination(c, util.DefaultPageLimits)
+	if err != nil {
+		respondQueryError(c, err, "Invalid pagination parameters")
+		return
+	}
+
+	changes, total, err := h.service.GetPriceHistory(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		if errors.Is(err, product.ErrProductNotFound) {
+			response.Error(c, http.StatusNotFound, err, "Product not found")
+			return
+		}
+		logger.Error("failed to get price history", "error", err, "product_id", id)
+		response.Error(c, http.StatusInternalServerError, err, "Failed to get price history")
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, changes, total, limit, offset)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestGetPriceHistoryHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+		wantLimit  int
+		wantOffset int
+	}{
+		{"default page", "/api/v1/products/p1/price-history", nil, http.StatusOK, 50, 0},
+		{"explicit page", "/api/v1/products/p1/price-history?limit=10&offset=20", nil, http.StatusOK, 10, 20},
+		{"invalid limit", "/api/v1/products/p1/price-history?limit=abc", nil, http.StatusBadRequest, 0, 0},
+		{"unknown product", "/api/v1/products/p1/price-history", product.ErrProductNotFound, http.StatusNotFound, 50, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLimit, gotOffset int
+			service := &mocks.ProductService{
+				GetPriceHistoryFunc: func(ctx context.Context, id string, limit, offset int) ([]product.PriceChange, int64, error) {
+					gotLimit, gotOffset = limit, offset
+					if tt.err != nil {
+						return nil, 0, tt.err
+					}
+					return []product.PriceChange{{ID: "c1", ProductID: id}}, 1, nil
+				},
+			}
+			router := newProductRouter(service)
+			router.GET("/api/v1/products/:id/price-history", NewProductHandler(service).GetPriceHistory)
+
+			w := serveJSON(router, http.MethodGet, tt.target, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if gotLimit != tt.wantLimit || gotOffset != tt.wantOffset {
+				t.Errorf("limit, offset = %d, %d, want %d, %d", gotLimit, gotOffset, tt.wantLimit, tt.wantOffset)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data []product.PriceChange `json:"data"`
+				Meta struct {
+					TotalItems int64 `json:"total_items"`
+				} `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if len(body.Data) != 1 || body.Data[0].ProductID != "p1" || body.Meta.TotalItems != 1 {
+				t.Errorf("response = %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdatePassesActorHeader(t *testing.T) {
+	var gotActor string
+	service := &mocks.ProductService{
+		UpdateFunc: func(ctx context.Context, id string, input product.UpdateInput) (*product.Product, error) {
+			gotActor = input.Actor
+			return &product.Product{ID: id}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/products/p1", strings.NewReader(`{"name": "Pizza"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Actor", "  manager-7 ")
+	w := httptest.NewRecorder()
+	newProductRouter(service).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if gotActor != "manager-7" {
+		t.Errorf("actor = %q, want %q", gotActor, "manager-7")
+	}
+}
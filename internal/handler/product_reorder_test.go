@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestReorderProducts(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		result     *product.ReorderResult
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"reordered", `{"sale_point_id":"sp1","category":"Pizzas","ordered_ids":["p2","p1"]}`,
+			&product.ReorderResult{Reordered: 2, Missing: []string{}, Unknown: []string{}}, nil, http.StatusOK, `"reordered":2`},
+		{"mismatch", `{"sale_point_id":"sp1","category":"Pizzas","ordered_ids":["p2","p9"]}`,
+			&product.ReorderResult{Missing: []string{"p1"}, Unknown: []string{"p9"}}, product.ErrReorderMismatch, http.StatusConflict, `"missing":["p1"],"unknown":["p9"]`},
+		{"duplicate id", `{"sale_point_id":"sp1","category":"Pizzas","ordered_ids":["p2","p2"]}`,
+			nil, apperrors.NewIndexedDomainError(product.ErrDuplicateReorderID, "ordered_ids[1]", 1, "p2"), http.StatusBadRequest, `"field":"ordered_ids[1]"`},
+		{"no ids", `{"sale_point_id":"sp1","category":"Pizzas","ordered_ids":[]}`, nil, nil, http.StatusBadRequest, `at least 1`},
+		{"no category", `{"sale_point_id":"sp1","ordered_ids":["p1"]}`, nil, nil, http.StatusBadRequest, `category`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got product.ReorderInput
+			service := &mocks.ProductService{
+				ReorderFunc: func(ctx context.Context, input product.ReorderInput) (*product.ReorderResult, error) {
+					got = input
+					return tt.result, tt.err
+				},
+			}
+			router := newProductRouter(service)
+			router.POST("/api/v1/products/reorder", NewProductHandler(service).ReorderProducts)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/products/reorder", tt.body, false)
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("status = %d, want %d with %s: %s", w.Code, tt.wantStatus, tt.wantBody, w.Body.String())
+			}
+			if tt.name == "reordered" {
+				want := product.ReorderInput{SalePointID: "sp1", Category: "Pizzas", OrderedIDs: []string{"p2", "p1"}}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("input = %+v, want %+v", got, want)
+				}
+			}
+		})
+	}
+}
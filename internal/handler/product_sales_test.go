@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestGetProductSales(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		wantStatus   int
+		wantSort     order.ProductSalesSort
+		wantArchived bool
+		wantBody     string
+	}{
+		{"defaults", "", http.StatusOK, "", false, `"total_items":3`},
+		{"sort is case insensitive", "?sort_by=Quantity", http.StatusOK, order.SortByQuantity, false, `"product_id":"p1"`},
+		{"page and filters", "?sort_by=orders&limit=2&offset=2&sale_type=ON_SITE&include_archived=true", http.StatusOK, order.SortByOrders, true, `"current_page":2`},
+		{"unknown sort", "?sort_by=price", http.StatusBadRequest, "price", false, "sort_by must be one of"},
+		{"invalid pagination", "?limit=x", http.StatusBadRequest, "", false, `"field":"limit"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilters *order.OrderFilters
+			var gotSort order.ProductSalesSort
+			service := &mocks.OrderService{
+				GetProductSalesFunc: func(ctx context.Context, filters order.OrderFilters, sortBy order.ProductSalesSort) ([]order.ProductSales, int64, error) {
+					gotFilters, gotSort = &filters, sortBy
+					if !order.IsValidProductSalesSort(sortBy) && sortBy != "" {
+						return nil, 0, order.ErrInvalidSortField
+					}
+					return []order.ProductSales{{ProductID: "p1", Name: "Burger", TotalQuantity: 4, TotalRevenue: 48000, OrderCount: 3}}, 3, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders/metrics/products"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if gotFilters == nil {
+				return
+			}
+			if gotSort != tt.wantSort || gotFilters.IncludeArchived != tt.wantArchived {
+				t.Errorf("sort %q, archived %v, want %q, %v", gotSort, gotFilters.IncludeArchived, tt.wantSort, tt.wantArchived)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateProductSchedule(t *testing.T) {
+	body := func(schedule string) string {
+		return `{"company_id":"c1","sale_point_id":"s1","name":"Desayuno","category":"Platos",` +
+			`"price_variations":[{"type":"Normal","price":15000}],"is_unlimited_stock":true,` +
+			`"availability_schedule":` + schedule + `}`
+	}
+
+	tests := []struct {
+		name       string
+		schedule   string
+		wantStatus int
+		wantBody   string
+	}{
+		{"breakfast", `{"timezone":"America/Bogota","windows":[{"day":"Monday","start":"06:00","end":"11:00"}]}`,
+			http.StatusCreated, `"windows":[{"day":"monday","start":"06:00","end":"11:00"}]`},
+		{"no windows", `{"timezone":"America/Bogota","windows":[]}`, http.StatusCreated, `"availability_schedule":null`},
+		{"missing end", `{"timezone":"UTC","windows":[{"day":"monday","start":"06:00"}]}`, http.StatusBadRequest, `'end' is required`},
+		{"unknown timezone", `{"timezone":"Mars/Olympus","windows":[{"day":"monday","start":"06:00","end":"11:00"}]}`,
+			http.StatusUnprocessableEntity, `availability_schedule.timezone`},
+		{"overlap", `{"timezone":"UTC","windows":[{"day":"friday","start":"22:00","end":"03:00"},{"day":"saturday","start":"02:00","end":"04:00"}]}`,
+			http.StatusUnprocessableEntity, `availability_schedule.windows[1]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.ProductService{
+				CreateFunc: func(ctx context.Context, input product.CreateInput) (*product.Product, error) {
+					p := product.NewProduct(input.CompanyID, input.SalePointID, input.Name, input.Category, input.Description)
+					p.PriceVariations = input.PriceVariations
+					p.IsUnlimitedStock = input.IsUnlimitedStock
+					p.AvailabilitySchedule = product.NormalizeSchedule(input.AvailabilitySchedule)
+					if err := p.Validate(); err != nil {
+						return nil, err
+					}
+					return p, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodPost, "/api/v1/products", body(tt.schedule), false)
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("status = %d, want %d with %s: %s", w.Code, tt.wantStatus, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetProductCurrentlyAvailable(t *testing.T) {
+	// A window three days from today is closed whatever the time
+	day := strings.ToLower(time.Now().UTC().AddDate(0, 0, 3).Weekday().String())
+	closed := &product.AvailabilitySchedule{Timezone: "UTC", Windows: []product.AvailabilityWindow{{Day: day, Start: "10:00", End: "11:00"}}}
+	tests := []struct {
+		name     string
+		schedule *product.AvailabilitySchedule
+		want     string
+	}{
+		{"without schedule", nil, `"is_currently_available":true`},
+		{"outside schedule", closed, `"is_currently_available":false`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.ProductService{
+				GetByIDFunc: func(ctx context.Context, id string) (*product.Product, error) {
+					p := product.NewProduct("c1", "s1", "Desayuno", "Platos", "")
+					p.IsAvailable, p.IsUnlimitedStock, p.AvailabilitySchedule = true, true, tt.schedule
+					return p, nil
+				},
+			}
+			router := newProductRouter(service)
+			router.GET("/api/v1/products/:id", NewProductHandler(service).GetByID)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/products/p1", "", false)
+			if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("status = %d, want 200 with %s: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestListProductsAvailableNow(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", false},
+		{"?available_now=false", false},
+		{"?available_now=true", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got product.ProductFilters
+			service := &mocks.ProductService{
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					got = filters
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/c1"+tt.query, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got.AvailableNow != tt.want {
+				t.Errorf("AvailableNow = %v, want %v", got.AvailableNow, tt.want)
+			}
+		})
+	}
+}
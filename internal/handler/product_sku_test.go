@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateProductSKU(t *testing.T) {
+	body := func(sku string) string {
+		return fmt.Sprintf(`{
+			"company_id": "company-1", "sale_point_id": "sale-point-1", "sku": %q,
+			"name": "Pizza", "category": "Pizzas", "is_unlimited_stock": true,
+			"price_variations": [{"type": "small", "price": 1000}]
+		}`, sku)
+	}
+
+	tests := []struct {
+		name       string
+		sku        string
+		err        error
+		wantStatus int
+	}{
+		{"lowercase sku", "piz-01", nil, http.StatusCreated},
+		{"empty sku", "", nil, http.StatusCreated},
+		{"invalid characters", "PIZ 01", nil, http.StatusBadRequest},
+		{"too long", "ABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJK", nil, http.StatusBadRequest},
+		{"duplicate", "PIZ-01", fmt.Errorf("failed to create product: %w", product.ErrDuplicateSKU), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.ProductService{
+				CreateFunc: func(ctx context.Context, input product.CreateInput) (*product.Product, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &product.Product{ID: "p1", SKU: product.NormalizeSKU(input.SKU)}, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodPost, "/api/v1/products", body(tt.sku), false)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetBySKUHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+	}{
+		{"found", "/api/v1/products/sku/PIZ-01?sale_point_id=sale-point-1", nil, http.StatusOK},
+		{"not found", "/api/v1/products/sku/PIZ-01?sale_point_id=sale-point-1", product.ErrProductNotFound, http.StatusNotFound},
+		{"missing sale point", "/api/v1/products/sku/PIZ-01", apperrors.NewDomainError(product.ErrInvalidSalePointID, "sale_point_id", nil), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSalePoint, gotSKU string
+			service := &mocks.ProductService{
+				GetBySKUFunc: func(ctx context.Context, salePointID, sku string) (*product.Product, error) {
+					gotSalePoint, gotSKU = salePointID, sku
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &product.Product{ID: "p1", SKU: &sku}, nil
+				},
+			}
+			router := newProductRouter(service)
+			router.GET("/api/v1/products/sku/:sku", NewProductHandler(service).GetBySKU)
+
+			w := serveJSON(router, http.MethodGet, tt.target, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if gotSKU != "PIZ-01" {
+				t.Errorf("sku = %q, want PIZ-01", gotSKU)
+			}
+			if tt.wantStatus == http.StatusOK && gotSalePoint != "sale-point-1" {
+				t.Errorf("sale point = %q, want sale-point-1", gotSalePoint)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestDeleteProduct(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		admin      bool
+		err        error
+		wantHard   bool
+		wantStatus int
+	}{
+		{"soft by default", "", true, nil, false, http.StatusOK},
+		{"hard delete", "?hard=true", true, nil, true, http.StatusOK},
+		{"hard only when true", "?hard=1", true, nil, false, http.StatusOK},
+		{"hard only for admins", "?hard=true", false, nil, false, http.StatusOK},
+		{"unknown product", "", true, product.ErrProductNotFound, false, http.StatusNotFound},
+		{"in open orders", "?hard=true", true, fmt.Errorf("%w: 2 open orders", product.ErrProductInUse), true, http.StatusConflict},
+		{"database down", "", true, errors.New("connection refused"), false, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotHard *bool
+			service := &mocks.ProductService{
+				DeleteFunc: func(ctx context.Context, id string, hard bool) error {
+					gotHard = &hard
+					return tt.err
+				},
+			}
+			router := newProductRouter(service)
+			router.DELETE("/api/v1/products/:id", NewProductHandler(service).Delete)
+
+			w := serveJSON(router, http.MethodDelete, "/api/v1/products/p1"+tt.query, "", tt.admin)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if gotHard == nil || *gotHard != tt.wantHard {
+				t.Errorf("hard = %v, want %v", gotHard, tt.wantHard)
+			}
+		})
+	}
+}
+
+func TestRestoreProduct(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"restored", nil, http.StatusOK, `"id":"p1"`},
+		{"unknown product", product.ErrProductNotFound, http.StatusNotFound, ""},
+		{"database down", errors.New("connection refused"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.ProductService{
+				RestoreFunc: func(ctx context.Context, id string) (*product.Product, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &product.Product{ID: id, Name: "Hamburguesa"}, nil
+				},
+			}
+			router := newProductRouter(service)
+			router.POST("/api/v1/products/:id/restore", NewProductHandler(service).Restore)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/products/p1/restore", "", true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+			if tt.err == nil && strings.Contains(w.Body.String(), "deleted_at") {
+				t.Errorf("restored product still deleted: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestIncludeDeletedFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		admin bool
+		want  bool
+	}{
+		{"", true, false},
+		{"?include_deleted=true", true, true},
+		{"?include_deleted=yes", true, false},
+		{"?include_deleted=true", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s admin=%v", tt.query, tt.admin), func(t *testing.T) {
+			var got *product.ProductFilters
+			service := &mocks.ProductService{
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					got = &filters
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/c1"+tt.query, "", tt.admin)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got.IncludeDeleted != tt.want {
+				t.Errorf("include deleted = %v, want %v", got.IncludeDeleted, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/gin-gonic/gin"
+)
+
+func newStockRouter(service product.ServiceAPI) *gin.Engine {
+	router := newProductRouter(service)
+	router.PATCH("/api/v1/products/:id/stock", NewProductHandler(service).AdjustStock)
+	return router
+}
+
+func TestAdjustStockHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantCalled bool
+	}{
+		{"decrement", `{"delta": -3}`, nil, http.StatusOK, true},
+		{"set", `{"set": 25}`, nil, http.StatusOK, true},
+		{"negative set", `{"set": -1}`, nil, http.StatusBadRequest, false},
+		{"not a number", `{"delta": "three"}`, nil, http.StatusBadRequest, false},
+		{"delta and set", `{"delta": 1, "set": 2}`, product.ErrInvalidStockAdjustment, http.StatusBadRequest, true},
+		{"insufficient stock", `{"delta": -30}`, product.ErrInsufficientStock, http.StatusConflict, true},
+		{"unlimited stock", `{"delta": -1}`, product.ErrCannotUpdateStockForUnlimited, http.StatusUnprocessableEntity, true},
+		{"unknown product", `{"delta": -1}`, product.ErrProductNotFound, http.StatusNotFound, true},
+		{"database down", `{"delta": -1}`, errors.New("connection refused"), http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.ProductService{
+				AdjustStockFunc: func(ctx context.Context, id string, adjustment product.StockAdjustment) (*product.Product, error) {
+					called = true
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					stock := 5
+					return &product.Product{ID: id, Stock: &stock}, nil
+				},
+			}
+
+			w := serveJSON(newStockRouter(service), http.MethodPatch, "/api/v1/products/p1/stock", tt.body, true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if called != tt.wantCalled {
+				t.Errorf("service called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+// TestAdjustStockHandlerConcurrent hammers the endpoint with decrements against a guarded stock and
+// checks every unit is sold exactly once and the rest are rejected with 409
+func TestAdjustStockHandlerConcurrent(t *testing.T) {
+	const initial, requests = 40, 120
+	var mu sync.Mutex
+	stock := initial
+	service := &mocks.ProductService{
+		AdjustStockFunc: func(ctx context.Context, id string, adjustment product.StockAdjustment) (*product.Product, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if stock+*adjustment.Delta < 0 {
+				return nil, product.ErrInsufficientStock
+			}
+			stock += *adjustment.Delta
+			current := stock
+			return &product.Product{ID: id, Stock: &current}, nil
+		},
+	}
+	router := newStockRouter(service)
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses <- serveJSON(router, http.MethodPatch, "/api/v1/products/p1/stock", `{"delta": -1}`, true).Code
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	if counts[http.StatusOK] != initial || counts[http.StatusConflict] != requests-initial {
+		t.Errorf("statuses = %v, want %d OK and %d conflicts", counts, initial, requests-initial)
+	}
+	if stock != 0 {
+		t.Errorf("stock = %d, want 0", stock)
+	}
+}
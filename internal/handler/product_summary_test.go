@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestGetCompanySummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		summary    *product.CompanySummary
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"summary", &product.CompanySummary{
+			CompanyID:     "c1",
+			ProductCounts: product.ProductCounts{Total: 2, Available: 1, OutOfStock: 1, CatalogValue: 5000},
+			Categories:    []product.CategoryCounts{{Name: "Pizzas", ProductCounts: product.ProductCounts{Total: 2, Available: 1, OutOfStock: 1, CatalogValue: 5000}}},
+		}, nil, http.StatusOK, `"company_id":"c1","total":2,"available":1,"disabled":0,"out_of_stock":1,"catalog_value":5000,"categories":[{"name":"Pizzas"`},
+		{"empty company", &product.CompanySummary{CompanyID: "c1", Categories: []product.CategoryCounts{}}, nil, http.StatusOK, `"total":0,`},
+		{"repository failure", nil, errors.New("connection refused"), http.StatusInternalServerError, `Failed to get product summary`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.ProductService{
+				GetCompanySummaryFunc: func(ctx context.Context, companyID string) (*product.CompanySummary, error) {
+					if companyID != "c1" {
+						t.Errorf("company = %q, want c1", companyID)
+					}
+					return tt.summary, tt.err
+				},
+			}
+			router := newProductRouter(service)
+			router.GET("/api/v1/products/company/:company_id/summary", NewProductHandler(service).GetCompanySummary)
+
+			w := serveJSON(router, http.MethodGet, "/api/v1/products/company/c1/summary", "", false)
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("status = %d, want %d with %s: %s", w.Code, tt.wantStatus, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestProductTagsFilter(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantTags   []string
+		wantMode   product.TagsMode
+	}{
+		{"", http.StatusOK, nil, product.TagsAny},
+		{"?tags=Vegan,%20promo,,vegan", http.StatusOK, []string{"vegan", "promo"}, product.TagsAny},
+		{"?tags=vegan,promo&tags_mode=ALL", http.StatusOK, []string{"vegan", "promo"}, product.TagsAll},
+		{"?tags=vegan&tags_mode=some", http.StatusBadRequest, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got *product.ProductFilters
+			service := &mocks.ProductService{
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					got = &filters
+					return nil, 0, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/c1"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if !reflect.DeepEqual(got.Tags, tt.wantTags) || got.TagsMode != tt.wantMode {
+				t.Errorf("tags = %q (%s), want %q (%s)", got.Tags, got.TagsMode, tt.wantTags, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestGetTagsByCompanyIDHandler(t *testing.T) {
+	var got product.CategoryFilters
+	service := &mocks.ProductService{
+		GetTagsByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.CategoryFilters) ([]product.TagSummary, int64, error) {
+			got = filters
+			return []product.TagSummary{{Name: "promo", ProductCount: 2}, {Name: "vegan", ProductCount: 5, AvailableCount: 4}}, 2, nil
+		},
+	}
+	router := newProductRouter(service)
+	router.GET("/api/v1/tags/company/:company_id", NewProductHandler(service).GetTagsByCompanyID)
+
+	w := serveJSON(router, http.MethodGet, "/api/v1/tags/company/c1?format=plain", "", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if !got.All {
+		t.Error("format=plain did not request every tag")
+	}
+	if want := `"tags":["promo","vegan"]`; !strings.Contains(w.Body.String(), want) {
+		t.Errorf("body = %s, want %s", w.Body.String(), want)
+	}
+
+	w = serveJSON(router, http.MethodGet, "/api/v1/tags/company/c1?limit=1&only_with_available=true", "", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if got.All || got.Limit != 1 || !got.OnlyWithAvailable {
+		t.Errorf("filters = %+v, want one page of tags with available products", got)
+	}
+}
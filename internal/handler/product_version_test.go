@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestProductUpdateExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifMatch     string
+		version     string // Appended to the body
+		wantStatus  int
+		wantVersion string // Version the service receives; "" for none
+	}{
+		{"no version", "", "", http.StatusOK, ""},
+		{"body version", "", `, "version": 3`, http.StatusOK, "3"},
+		{"If-Match", `"3"`, "", http.StatusOK, "3"},
+		{"If-Match agreeing with the body", `"3"`, `, "version": 3`, http.StatusOK, "3"},
+		{"If-Match disagreeing with the body", `"4"`, `, "version": 3`, http.StatusBadRequest, ""},
+		{"If-Match without a version", `"abc"`, "", http.StatusBadRequest, ""},
+		{"negative body version", "", `, "version": -1`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *int64
+			called := false
+			service := &mocks.ProductService{
+				UpdateFunc: func(ctx context.Context, id string, input product.UpdateInput) (*product.Product, error) {
+					called, got = true, input.ExpectedVersion
+					return &product.Product{ID: id, Name: "Pizza", Version: 7}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/products/p1", strings.NewReader(`{"name": "Pizza"`+tt.version+`}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			newProductRouter(service).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if called {
+					t.Error("service called with an invalid version")
+				}
+				return
+			}
+			if version := fmt.Sprint(deref64(got)); version != tt.wantVersion {
+				t.Errorf("expected version = %q, want %q", version, tt.wantVersion)
+			}
+			if etag := w.Header().Get("ETag"); etag != `"7"` {
+				t.Errorf("ETag = %q, want the saved version", etag)
+			}
+			// Same product response as GetByID
+			for _, want := range []string{`"version":7`, `"is_currently_available":`} {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestProductUpdateConflict(t *testing.T) {
+	// The second of two admins who opened version 1 saves after the first one
+	saves := 0
+	service := &mocks.ProductService{
+		UpdateFunc: func(ctx context.Context, id string, input product.UpdateInput) (*product.Product, error) {
+			saves++
+			if saves > 1 {
+				return nil, fmt.Errorf("%w: sent version %d, current version 2", product.ErrProductConflict, *input.ExpectedVersion)
+			}
+			return &product.Product{ID: id, Version: 2}, nil
+		},
+	}
+	router := newProductRouter(service)
+
+	for i, want := range []int{http.StatusOK, http.StatusConflict} {
+		w := serveJSON(router, http.MethodPut, "/api/v1/products/p1", `{"name": "Pizza", "version": 1}`, false)
+		if w.Code != want {
+			t.Errorf("save %d status = %d, want %d, body %s", i+1, w.Code, want, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// errInvalidNumber is reported for numeric query parameters that are not whole numbers
+var errInvalidNumber = errors.New("must be a whole number")
+
+// queryParamError reports a query parameter whose value was rejected
+type queryParamError struct {
+	Param   string
+	Value   string
+	Allowed []string // Accepted values of enum parameters
+	Err     error    // Sentinel error, e.g. order.ErrInvalidStatus
+}
+
+// Error implements the error interface
+func (e *queryParamError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Param, e.Err.Error())
+}
+
+// Unwrap returns the wrapped sentinel error
+func (e *queryParamError) Unwrap() error {
+	return e.Err
+}
+
+// message returns the user-facing description of the rejected value
+func (e *queryParamError) message() string {
+	if len(e.Allowed) > 0 {
+		return fmt.Sprintf("'%s' must be one of: %s", e.Param, strings.Join(e.Allowed, ", "))
+	}
+	return fmt.Sprintf("'%s' %s", e.Param, e.Err.Error())
+}
+
+// enumParam checks that value is one of allowed, returning a queryParamError listing them otherwise
+func enumParam[T ~string](param string, value T, allowed []T, err error) error {
+	for _, a := range allowed {
+		if a == value {
+			return nil
+		}
+	}
+	names := make([]string, len(allowed))
+	for i, a := range allowed {
+		names[i] = string(a)
+	}
+	return &queryParamError{Param: param, Value: string(value), Allowed: names, Err: err}
+}
+
+// intParam parses an optional whole number query parameter; ok is false when it is missing
+func intParam(c *gin.Context, param string) (n int64, ok bool, err error) {
+	raw := c.Query(param)
+	if raw == "" {
+		return 0, false, nil
+	}
+	n, err = strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, false, &queryParamError{Param: param, Value: raw, Err: errInvalidNumber}
+	}
+	return n, true, nil
+}
+
+// staffFlag reports whether a staff-only flag such as ?override=true is set by an admin.
+// Callers without the admin token get the default behaviour, as if the flag were missing.
+func staffFlag(c *gin.Context, param string) bool {
+	return c.Query(param) == "true" && util.IsAdmin(c.Request.Context())
+}
+
+// respondQueryError sends a 400 for rejected query parameters, with one detail per
+// parameter in the validation error format so clients can show the allowed values
+func respondQueryError(c *gin.Context, err error, message string) {
+	var paramErr *queryParamError
+	if !errors.As(err, &paramErr) {
+		response.Error(c, http.StatusBadRequest, err, message)
+		return
+	}
+	details := []response.ValidationErrorDetail{{
+		Field:   paramErr.Param,
+		Message: paramErr.message(),
+		Value:   paramErr.Value,
+	}}
+	response.ValidationError(c, http.StatusBadRequest, err.Error(), message, details)
+}
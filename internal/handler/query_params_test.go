@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/gin-gonic/gin"
+)
+
+// queryContext returns a gin context for a GET request with the given query string
+func queryContext(query string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/"+query, nil)
+	return c, w
+}
+
+func TestEnumParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   order.SaleType
+		wantErr bool
+	}{
+		{"known", order.SaleTypeDelivery, false},
+		{"unknown", "PICKUP", true},
+		{"wrong case", "delivery", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := enumParam("sale_type", tt.value, order.AllSaleTypes, order.ErrInvalidSaleType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var paramErr *queryParamError
+			if !errors.As(err, &paramErr) || !errors.Is(err, order.ErrInvalidSaleType) {
+				t.Fatalf("err = %v, want a queryParamError wrapping ErrInvalidSaleType", err)
+			}
+			if paramErr.Value != string(tt.value) || len(paramErr.Allowed) != len(order.AllSaleTypes) {
+				t.Errorf("err = %+v, want the value and every sale type", paramErr)
+			}
+		})
+	}
+}
+
+func TestIntParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    int64
+		wantOK  bool
+		wantErr bool
+	}{
+		{"missing", "", 0, false, false},
+		{"empty", "?n=", 0, false, false},
+		{"number", "?n=42", 42, true, false},
+		{"negative", "?n=-3", -3, true, false},
+		{"word", "?n=many", 0, false, true},
+		{"decimal", "?n=1.5", 0, false, true},
+		{"too large", "?n=99999999999999999999", 0, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := queryContext(tt.query)
+			n, ok, err := intParam(c, "n")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if n != tt.want || ok != tt.wantOK {
+				t.Errorf("intParam = %d, %v, want %d, %v", n, ok, tt.want, tt.wantOK)
+			}
+			if err != nil && !errors.Is(err, errInvalidNumber) {
+				t.Errorf("err = %v, want %v", err, errInvalidNumber)
+			}
+		})
+	}
+}
+
+func TestRespondQueryError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantBody []string
+	}{
+		{"enum", enumParam("status", order.OrderStatus("DONE"), order.AllStatuses, order.ErrInvalidStatus),
+			[]string{`"field":"status"`, `"value":"DONE"`, "'status' must be one of: CREATED, "}},
+		{"number", &queryParamError{Param: "limit", Value: "ten", Err: errInvalidNumber},
+			[]string{`"field":"limit"`, `"value":"ten"`, "'limit' must be a whole number"}},
+		{"other error", errors.New("bad cursor"), []string{`"error":"bad cursor"`, `"message":"Invalid filters"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := queryContext("")
+			respondQueryError(c, tt.err, "Invalid filters")
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", w.Code)
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
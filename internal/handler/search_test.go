@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestSearchOrders(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"results with matched fields", "?q=maria", nil, http.StatusOK, `"matched_on":["customer.name"]`},
+		{"missing query", "", order.ErrSearchQueryRequired, http.StatusBadRequest, `"Invalid search query"`},
+		{"query too long", "?q=" + strings.Repeat("a", order.MaxSearchQueryLength+1), order.ErrSearchQueryTooLong, http.StatusBadRequest, `"Invalid search query"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				SearchFunc: func(ctx context.Context, query string, filters order.OrderFilters) ([]order.SearchResult, int64, error) {
+					if tt.err != nil {
+						return nil, 0, tt.err
+					}
+					return []order.SearchResult{{Order: order.NewOrder(order.SaleTypeOnSite, nil), MatchedOn: []string{order.SearchFieldCustomerName}}}, 1, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders/search"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body misses %s: %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/mocks"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+func TestListingsSkipCount(t *testing.T) {
+	limits := util.PageLimits{DefaultLimit: 20, MaxLimit: 100}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantSkip bool
+		wantMeta string
+	}{
+		{"counted by default", "", false, `"total_pages":3,"total_items":42`},
+		{"skip_count=true", "?skip_count=true", true, `"total_pages":-1,"total_items":-1`},
+		{"only true skips", "?skip_count=1", false, `"total_items":42`},
+	}
+
+	for _, tt := range tests {
+		t.Run("orders: "+tt.name, func(t *testing.T) {
+			var got order.OrderFilters
+			service := &mocks.OrderService{
+				PageLimitsFunc: func() util.PageLimits { return limits },
+				GetAllFunc: func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+					got = filters
+					if filters.SkipCount {
+						return nil, -1, nil
+					}
+					return nil, 42, nil
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodGet, "/api/v1/orders"+tt.query, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got.SkipCount != tt.wantSkip {
+				t.Errorf("SkipCount = %v, want %v", got.SkipCount, tt.wantSkip)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMeta) {
+				t.Errorf("body misses %s: %s", tt.wantMeta, w.Body.String())
+			}
+		})
+
+		t.Run("company products: "+tt.name, func(t *testing.T) {
+			var got product.ProductFilters
+			service := &mocks.ProductService{
+				CompanyPageLimitsFunc: func() util.PageLimits { return limits },
+				GetByCompanyIDFunc: func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+					got = filters
+					if filters.SkipCount {
+						return nil, -1, nil
+					}
+					return nil, 42, nil
+				},
+			}
+
+			w := serveJSON(newProductRouter(service), http.MethodGet, "/api/v1/products/company/company-1"+tt.query, "", false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			if got.SkipCount != tt.wantSkip {
+				t.Errorf("SkipCount = %v, want %v", got.SkipCount, tt.wantSkip)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMeta) {
+				t.Errorf("body misses %s: %s", tt.wantMeta, w.Body.String())
+			}
+		})
+	}
+}
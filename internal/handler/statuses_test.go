@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+)
+
+func TestGetStatuses(t *testing.T) {
+	machine, err := order.ParseStateMachine(map[order.SaleType][]string{
+		order.SaleTypeDelivery: {
+			"CREATED>IN_PROGRESS", "CREATED>CANCELLED",
+			"IN_PROGRESS>OUT_FOR_DELIVERY", "IN_PROGRESS>CANCELLED",
+			"OUT_FOR_DELIVERY>DELIVERED", "OUT_FOR_DELIVERY>CANCELLED",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := order.NewService(nil, order.WithStateMachine(machine))
+	router := newOrderRouter(service)
+	router.GET("/api/v1/orders/statuses", NewOrderHandler(service).GetStatuses)
+
+	tests := []struct {
+		name         string
+		query        string
+		wantStatus   int
+		wantStatuses []order.OrderStatus
+		wantNext     []order.OrderStatus // Allowed after IN_PROGRESS
+	}{
+		{
+			name:         "configured delivery workflow",
+			query:        "?sale_type=DELIVERY",
+			wantStatus:   http.StatusOK,
+			wantStatuses: []order.OrderStatus{order.StatusCreated, order.StatusInProgress, order.StatusOutForDelivery, order.StatusDelivered, order.StatusCancelled},
+			wantNext:     []order.OrderStatus{order.StatusOutForDelivery, order.StatusCancelled},
+		},
+		{
+			name:         "built-in on site workflow",
+			query:        "?sale_type=ON_SITE",
+			wantStatus:   http.StatusOK,
+			wantStatuses: []order.OrderStatus{order.StatusCreated, order.StatusVerified, order.StatusInProgress, order.StatusDelivered, order.StatusCancelled},
+			wantNext:     []order.OrderStatus{order.StatusDelivered, order.StatusCancelled},
+		},
+		{name: "missing sale type", wantStatus: http.StatusBadRequest},
+		{name: "unknown sale type", query: "?sale_type=PICKUP", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveJSON(router, http.MethodGet, "/api/v1/orders/statuses"+tt.query, "", false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data dto.OrderStatusesResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(body.Data.Statuses, tt.wantStatuses) {
+				t.Errorf("statuses = %v, want %v", body.Data.Statuses, tt.wantStatuses)
+			}
+			if next := body.Data.Transitions[order.StatusInProgress]; !slices.Equal(next, tt.wantNext) {
+				t.Errorf("after IN_PROGRESS = %v, want %v", next, tt.wantNext)
+			}
+			wantTerminal := []order.OrderStatus{order.StatusDelivered, order.StatusCancelled}
+			if !slices.Equal(body.Data.Terminal, wantTerminal) {
+				t.Errorf("terminal = %v, want %v", body.Data.Terminal, wantTerminal)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+)
+
+// createOnlyRepository stores created orders; the other methods panic through the nil interface
+type createOnlyRepository struct {
+	order.Repository
+	created []*order.Order
+}
+
+func (r *createOnlyRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	return false, nil
+}
+
+func (r *createOnlyRepository) Create(ctx context.Context, o *order.Order) error {
+	r.created = append(r.created, o)
+	return nil
+}
+
+func TestValidateAndCreateAgree(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantValid bool
+	}{
+		{"valid on site order", `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+			"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 2}]}`, true},
+		{"binding error", `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "PICKUP",
+			"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 2}]}`, false},
+		{"missing table number", `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE",
+			"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 2}]}`, false},
+		{"delivery without address", `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "DELIVERY",
+			"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 2}]}`, false},
+		{"impossible phone", `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+			"customer": {"identification": "1020304050", "id_type": "CC", "name": "Ana", "phone": "200 123 4567"},
+			"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 2}]}`, false},
+		{"invalid channel", `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2, "channel": "FAX",
+			"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 2}]}`, false},
+		{"above the quantity limit", `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+			"products": [{"id": "p1", "name": "Burger", "price": 12000, "quantity": 11}]}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &createOnlyRepository{}
+			service := order.NewService(repo, order.WithOrderLimits(order.OrderLimits{MaxLineQuantity: 10}))
+			router := newOrderRouter(service)
+
+			w := serveJSON(router, http.MethodPost, "/api/v1/orders/validate", tt.body, false)
+			if w.Code != http.StatusOK {
+				t.Fatalf("validate status = %d, body %s", w.Code, w.Body.String())
+			}
+			var validated struct {
+				Data struct {
+					Valid   bool `json:"valid"`
+					Preview *struct {
+						Total int64 `json:"total"`
+					} `json:"preview"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &validated); err != nil {
+				t.Fatal(err)
+			}
+			if len(repo.created) != 0 {
+				t.Fatal("validate stored the order")
+			}
+
+			w = serveJSON(router, http.MethodPost, "/api/v1/orders", tt.body, false)
+			created := w.Code == http.StatusCreated
+
+			if validated.Data.Valid != tt.wantValid || created != tt.wantValid {
+				t.Fatalf("validate valid = %v, create status %d, want both to accept: %v; body %s",
+					validated.Data.Valid, w.Code, tt.wantValid, w.Body.String())
+			}
+			if created && validated.Data.Preview.Total != repo.created[0].Total {
+				t.Errorf("preview total = %d, created total = %d", validated.Data.Preview.Total, repo.created[0].Total)
+			}
+		})
+	}
+}
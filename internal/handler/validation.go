@@ -1,12 +1,45 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/response"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
+// RegisterValidators registers the custom binding tags on gin's validator engine
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected binding validator engine")
+	}
+	if err := v.RegisterValidation("order_channel", func(fl validator.FieldLevel) bool {
+		return order.IsValidChannel(order.Channel(fl.Field().String()))
+	}); err != nil {
+		return err
+	}
+	// SKUs and barcodes are validated as the service stores them, empty meaning none
+	if err := v.RegisterValidation("sku", func(fl validator.FieldLevel) bool {
+		sku := fl.Field().String()
+		normalized := product.NormalizeSKU(&sku)
+		return normalized == nil || product.IsValidSKU(*normalized)
+	}); err != nil {
+		return err
+	}
+	return v.RegisterValidation("barcode", func(fl validator.FieldLevel) bool {
+		barcode := fl.Field().String()
+		normalized := product.NormalizeBarcode(&barcode)
+		return normalized == nil || product.IsValidBarcode(*normalized)
+	})
+}
+
 // ValidationError represents a field-level validation error
 type ValidationError struct {
 	Field   string `json:"field"`
@@ -51,6 +84,61 @@ func FormatValidationErrors(err error) (string, []ValidationError) {
 	return fmt.Sprintf("Validation failed for %d field(s)", len(details)), details
 }
 
+// respondError sends an error response, adding the field context of domain errors as details
+func respondError(c *gin.Context, statusCode int, err error, message string) {
+	if details := domainErrorDetails(err); details != nil {
+		response.ValidationError(c, statusCode, err.Error(), message, details)
+		return
+	}
+	response.Error(c, statusCode, err, message)
+}
+
+// domainErrorDetails returns one detail per domain error carried by err, or nil if there is none
+func domainErrorDetails(err error) []response.ValidationErrorDetail {
+	var domainErrs apperrors.DomainErrors
+	if !errors.As(err, &domainErrs) {
+		var domainErr *apperrors.DomainError
+		if !errors.As(err, &domainErr) {
+			return nil
+		}
+		domainErrs = apperrors.DomainErrors{domainErr}
+	}
+
+	details := make([]response.ValidationErrorDetail, len(domainErrs))
+	for i, domainErr := range domainErrs {
+		details[i] = response.ValidationErrorDetail{
+			Field:   domainErr.Field,
+			Message: domainErr.Err.Error(),
+			Index:   domainErr.Index,
+			Value:   domainErr.Value,
+		}
+	}
+	return details
+}
+
+// errorDetails converts binding and domain validation errors into response details
+func errorDetails(err error) []response.ValidationErrorDetail {
+	if _, details := FormatValidationErrors(err); details != nil {
+		responseDetails := make([]response.ValidationErrorDetail, len(details))
+		for i, d := range details {
+			responseDetails[i] = response.ValidationErrorDetail{Field: d.Field, Message: d.Message}
+		}
+		return responseDetails
+	}
+
+	if details := domainErrorDetails(err); details != nil {
+		return details
+	}
+
+	return []response.ValidationErrorDetail{{Message: err.Error()}}
+}
+
+// isDomainError reports whether err carries domain validation context
+func isDomainError(err error) bool {
+	var domainErr *apperrors.DomainError
+	return errors.As(err, &domainErr)
+}
+
 // formatFieldName converts field name to snake_case for consistency
 func formatFieldName(field string) string {
 	// Convert from PascalCase to snake_case
@@ -116,6 +204,19 @@ func getValidationMessage(e validator.FieldError) string {
 	case "url":
 		return fmt.Sprintf("'%s' must be a valid URL", field)
 
+	case "order_channel":
+		channels := make([]string, len(order.AllChannels))
+		for i, ch := range order.AllChannels {
+			channels[i] = string(ch)
+		}
+		return fmt.Sprintf("'%s' must be one of: %s", field, strings.Join(channels, ", "))
+
+	case "sku":
+		return fmt.Sprintf("'%s' must have 1 to 40 letters, digits, hyphens or underscores", field)
+
+	case "barcode":
+		return fmt.Sprintf("'%s' must be a valid EAN-8, UPC-A, EAN-13 or GTIN-14 number, or 1 to 48 printable characters without spaces", field)
+
 	case "uuid":
 		return fmt.Sprintf("'%s' must be a valid UUID", field)
 
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	apperrors "github.com/emerarteaga/products-api/internal/errors"
+	"github.com/emerarteaga/products-api/internal/mocks"
+)
+
+func TestCreateSurfacesDomainErrorContext(t *testing.T) {
+	const body = `{"company_id": "c1", "sale_point_id": "s1", "sale_type": "ON_SITE", "table_number": 2,
+		"products": [{"id": "p1", "name": "Burger", "price": 1000, "quantity": 1}]}`
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantDetails []string
+	}{
+		{
+			name:        "indexed field",
+			err:         fmt.Errorf("validation error: %w", apperrors.NewIndexedDomainError(order.ErrInvalidProductQuantity, "products[3].quantity", 3, 0)),
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantDetails: []string{`"field":"products[3].quantity"`, `"index":3`, `"message":"` + order.ErrInvalidProductQuantity.Error() + `"`},
+		},
+		{
+			name:        "single field with value",
+			err:         fmt.Errorf("validation error: %w", apperrors.NewDomainError(order.ErrInvalidChannel, "channel", order.Channel("FAX"))),
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantDetails: []string{`"field":"channel"`, `"value":"FAX"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.OrderService{
+				CreateFunc: func(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := serveJSON(newOrderRouter(service), http.MethodPost, "/api/v1/orders", body, false)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			for _, want := range tt.wantDetails {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body misses %s: %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errInvalidIfMatch  = errors.New(`If-Match must hold a version, e.g. "3"`)
+	errVersionMismatch = errors.New("If-Match and version disagree")
+)
+
+// expectedVersion returns the order or product version the client edited, from the If-Match header (the ETag of
+// a previous response) or the version field of the body; nil when the client sent neither
+func expectedVersion(c *gin.Context, body *int64) (*int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return body, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), 10, 64)
+	if err != nil || version < 0 {
+		return nil, errInvalidIfMatch
+	}
+	if body != nil && *body != version {
+		return nil, errVersionMismatch
+	}
+	return &version, nil
+}
+
+// setVersionTag sends the order or product version as the ETag, for the client to echo in If-Match
+func setVersionTag(c *gin.Context, version int64) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
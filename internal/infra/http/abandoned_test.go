@@ -0,0 +1,51 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAbandonedLogsClientDisconnects(t *testing.T) {
+	tests := []struct {
+		name    string
+		cancel  bool
+		wantLog bool
+	}{
+		{"completed request", false, false},
+		{"client disconnected", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			previous := logger.Log
+			logger.Log = slog.New(slog.NewTextHandler(&logs, nil))
+			defer func() { logger.Log = previous }()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			router := gin.New()
+			router.Use(customhttp.Abandoned())
+			router.GET("/slow", func(c *gin.Context) {
+				if tt.cancel {
+					cancel() // The client goes away while the handler runs
+				}
+				c.Status(http.StatusOK)
+			})
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx))
+			if got := strings.Contains(logs.String(), "request abandoned by client"); got != tt.wantLog {
+				t.Errorf("logged = %v, want %v: %s", got, tt.wantLog, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// UnauthorizedErrorCode is the machine-readable code returned for admin routes called without valid credentials
+const UnauthorizedErrorCode = "UNAUTHORIZED"
+
+// adminRealm is the Basic auth realm browsers show when prompting for the admin token
+const adminRealm = `Basic realm="admin"`
+
+// Authenticate returns a middleware marking requests that carry the admin token, either as
+// "Authorization: Bearer <token>" or as the Basic auth password (so browsers can open the dashboard).
+// Requests without it continue unmarked; RequireAdmin rejects them where needed.
+// An empty token authenticates nobody.
+func Authenticate(token string) gin.HandlerFunc {
+	want := sha256.Sum256([]byte(token))
+
+	return func(c *gin.Context) {
+		if token != "" {
+			if got, ok := credential(c.Request); ok {
+				// Hash both sides so the comparison time does not leak the token length
+				sum := sha256.Sum256([]byte(got))
+				if subtle.ConstantTimeCompare(sum[:], want[:]) == 1 {
+					c.Request = c.Request.WithContext(util.WithAdmin(c.Request.Context()))
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin returns a middleware rejecting requests not marked by Authenticate with 401
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if util.IsAdmin(c.Request.Context()) {
+			c.Next()
+			return
+		}
+
+		c.Header("WWW-Authenticate", adminRealm)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Admin credentials required",
+			"code":    UnauthorizedErrorCode,
+		})
+	}
+}
+
+// credential extracts the token from a Bearer or Basic Authorization header
+func credential(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if scheme, token, found := strings.Cut(header, " "); found && strings.EqualFold(scheme, "Bearer") {
+		token = strings.TrimSpace(token)
+		return token, token != ""
+	}
+	if _, password, ok := r.BasicAuth(); ok && password != "" {
+		return password, true
+	}
+	return "", false
+}
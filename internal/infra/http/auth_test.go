@@ -0,0 +1,94 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+const testAdminToken = "s3cret-admin-token"
+
+// newAuthRouter serves an open route reporting whether the caller is an admin and an admin-only route
+func newAuthRouter(token string) *gin.Engine {
+	router := gin.New()
+	router.Use(customhttp.Authenticate(token))
+	router.GET("/open", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"admin": util.IsAdmin(c.Request.Context())})
+	})
+	admin := router.Group("/admin", customhttp.RequireAdmin())
+	admin.POST("/action", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return router
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string // Configured ADMIN_TOKEN
+		authorize  func(r *http.Request)
+		wantStatus int
+	}{
+		{"bearer token", testAdminToken, func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+testAdminToken) }, http.StatusNoContent},
+		{"bearer scheme in lowercase", testAdminToken, func(r *http.Request) { r.Header.Set("Authorization", "bearer "+testAdminToken) }, http.StatusNoContent},
+		{"basic auth password", testAdminToken, func(r *http.Request) { r.SetBasicAuth("anyone", testAdminToken) }, http.StatusNoContent},
+		{"no credentials", testAdminToken, func(r *http.Request) {}, http.StatusUnauthorized},
+		{"wrong token", testAdminToken, func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") }, http.StatusUnauthorized},
+		{"token prefix", testAdminToken, func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+testAdminToken[:8]) }, http.StatusUnauthorized},
+		{"empty bearer", testAdminToken, func(r *http.Request) { r.Header.Set("Authorization", "Bearer ") }, http.StatusUnauthorized},
+		{"basic auth username only", testAdminToken, func(r *http.Request) { r.SetBasicAuth(testAdminToken, "") }, http.StatusUnauthorized},
+		{"no token configured", "", func(r *http.Request) { r.Header.Set("Authorization", "Bearer ") }, http.StatusUnauthorized},
+		{"no token configured, basic", "", func(r *http.Request) { r.SetBasicAuth("admin", "x") }, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/action", nil)
+			tt.authorize(req)
+			w := httptest.NewRecorder()
+			newAuthRouter(tt.token).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusUnauthorized {
+				if got := w.Header().Get("WWW-Authenticate"); got == "" {
+					t.Error("missing WWW-Authenticate header")
+				}
+				assertErrorCode(t, w, customhttp.UnauthorizedErrorCode)
+			}
+		})
+	}
+}
+
+func TestAuthenticateMarksAdminRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"admin", "Bearer " + testAdminToken, `{"admin":true}`},
+		{"anonymous", "", `{"admin":false}`},
+		{"wrong token", "Bearer nope", `{"admin":false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/open", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			newAuthRouter(testAdminToken).ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("open route status = %d, want 200", w.Code)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
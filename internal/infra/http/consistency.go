@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Read-after-write signals accepted on read requests
+const (
+	ReadAfterWriteParam  = "read_after_write"
+	ReadAfterWriteHeader = "X-Read-After-Write"
+	ReadAfterWriteCookie = "read_after_write"
+)
+
+// ReadAfterWrite returns a middleware that routes a read to the database primary when the client asks for
+// its latest writes: read_after_write=true, the X-Read-After-Write header, or the cookie set by a write
+// within the last window. Writes set that cookie; a zero window disables it.
+func ReadAfterWrite(window time.Duration) gin.HandlerFunc {
+	maxAge := int(window / time.Second)
+
+	return func(c *gin.Context) {
+		if !isReadMethod(c.Request.Method) {
+			if maxAge > 0 {
+				http.SetCookie(c.Writer, &http.Cookie{
+					Name:     ReadAfterWriteCookie,
+					Value:    "1",
+					Path:     "/",
+					MaxAge:   maxAge,
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			c.Next()
+			return
+		}
+
+		if wantsReadAfterWrite(c, maxAge > 0) {
+			c.Request = c.Request.WithContext(util.WithReadPreferencePrimary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// wantsReadAfterWrite reports whether the read request carries any read-after-write signal
+func wantsReadAfterWrite(c *gin.Context, cookieEnabled bool) bool {
+	if c.Query(ReadAfterWriteParam) == "true" || c.GetHeader(ReadAfterWriteHeader) == "true" {
+		return true
+	}
+	if !cookieEnabled {
+		return false
+	}
+	_, err := c.Cookie(ReadAfterWriteCookie)
+	return err == nil
+}
@@ -0,0 +1,154 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/handler"
+	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/emerarteaga/products-api/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+// laggingRepository simulates a replica set whose secondary has not caught up: writes reach the
+// primary only, and reads see them only when the context asks for the primary
+type laggingRepository struct {
+	order.Repository
+
+	mu        sync.Mutex
+	primary   order.Order
+	secondary order.Order
+}
+
+func (r *laggingRepository) FindByCode(ctx context.Context, code string) (*order.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o := r.secondary
+	if util.IsReadPreferencePrimary(ctx) {
+		o = r.primary
+	}
+	if o.Code != code {
+		return nil, order.ErrOrderNotFound
+	}
+	return &o, nil
+}
+
+func (r *laggingRepository) Update(ctx context.Context, o *order.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o.Version++
+	r.primary = *o
+	return nil
+}
+
+func TestReadAfterWriteSeesTheFreshStatus(t *testing.T) {
+	const code = "ORD-1700000000-a1b2c3d4"
+	table := 3
+	stored := order.Order{
+		ID: "o1", Code: code, Status: order.StatusCreated, SaleType: order.SaleTypeOnSite, TableNumber: &table,
+		Products: []order.OrderProduct{{ID: "p1", Name: "Taco", Price: 1000, Quantity: 1}},
+		Subtotal: 1000, Total: 1000, Version: 1,
+	}
+
+	tests := []struct {
+		name       string
+		window     time.Duration
+		target     string
+		header     bool
+		sendCookie bool
+		wantStatus order.OrderStatus
+	}{
+		{"plain read is stale", time.Minute, "/api/v1/orders/track/" + code, false, false, order.StatusCreated},
+		{"query parameter", time.Minute, "/api/v1/orders/track/" + code + "?read_after_write=true", false, false, order.StatusInProgress},
+		{"header", time.Minute, "/api/v1/orders/track/" + code, true, false, order.StatusInProgress},
+		{"cookie set by the write", time.Minute, "/api/v1/orders/track/" + code, false, true, order.StatusInProgress},
+		{"cookie ignored when disabled", 0, "/api/v1/orders/track/" + code, false, true, order.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &laggingRepository{primary: stored, secondary: stored}
+			h := handler.NewOrderHandler(order.NewService(repo))
+			router := gin.New()
+			router.Use(customhttp.ReadAfterWrite(tt.window))
+			router.PATCH("/api/v1/orders", h.PartialUpdate)
+			router.GET("/api/v1/orders/track/:code", h.Track)
+
+			write := serve(router, http.MethodPatch, "/api/v1/orders", `{"code":"`+code+`","status":"IN_PROGRESS"}`, false)
+			if write.Code != http.StatusOK {
+				t.Fatalf("update status = %d: %s", write.Code, write.Body.String())
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header {
+				req.Header.Set(customhttp.ReadAfterWriteHeader, "true")
+			}
+			if tt.sendCookie {
+				cookies := write.Result().Cookies()
+				if tt.window > 0 && len(cookies) == 0 {
+					t.Fatal("the write did not set the read-after-write cookie")
+				}
+				// Sent as a browser would; with the window disabled it must be ignored
+				req.AddCookie(&http.Cookie{Name: customhttp.ReadAfterWriteCookie, Value: "1"})
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var body struct {
+				Data struct {
+					Status order.OrderStatus `json:"status"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", w.Body.String(), err)
+			}
+			if body.Data.Status != tt.wantStatus {
+				t.Errorf("tracked status = %s, want %s", body.Data.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestReadAfterWriteCookie(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		window     time.Duration
+		wantMaxAge int // 0 when no cookie is expected
+	}{
+		{"write sets the cookie", http.MethodPost, 5 * time.Second, 5},
+		{"read does not", http.MethodGet, 5 * time.Second, 0},
+		{"disabled window", http.MethodPatch, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(customhttp.ReadAfterWrite(tt.window))
+			router.Handle(tt.method, "/x", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := serve(router, tt.method, "/x", "", false)
+			var found *http.Cookie
+			for _, cookie := range w.Result().Cookies() {
+				if cookie.Name == customhttp.ReadAfterWriteCookie {
+					found = cookie
+				}
+			}
+			if tt.wantMaxAge == 0 {
+				if found != nil {
+					t.Errorf("unexpected cookie %v", found)
+				}
+				return
+			}
+			if found == nil || found.MaxAge != tt.wantMaxAge || !found.HttpOnly || found.Path != "/" {
+				t.Errorf("cookie = %v, want max age %d, HttpOnly and path /", found, tt.wantMaxAge)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package http_test
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	logger.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	os.Exit(m.Run())
+}
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -17,7 +19,18 @@ func Logger() gin.HandlerFunc {
 		c.Next()
 		latency := time.Since(start)
 		statusCode := c.Writer.Status()
-		logger.Info("HTTP request", "method", method, "path", path, "status", statusCode, "latency", latency.String(), "ip", c.ClientIP())
+		logger.Access("HTTP request", "method", method, "path", path, "status", statusCode, "latency", latency.String(), "ip", c.ClientIP())
+	}
+}
+
+// Abandoned logs requests whose client disconnected before the response was complete
+func Abandoned() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		if errors.Is(c.Request.Context().Err(), context.Canceled) {
+			logger.Warn("request abandoned by client", "method", c.Request.Method, "path", c.Request.URL.Path, "latency", time.Since(start).String(), "ip", c.ClientIP())
+		}
 	}
 }
 
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidIDFormatCode is the machine-readable code returned for malformed path IDs
+const InvalidIDFormatCode = "INVALID_ID_FORMAT"
+
+// maxParamLength bounds path IDs regardless of the configured format
+const maxParamLength = 128
+
+// ValidateParam returns a middleware rejecting requests whose path parameter does not match
+// the pattern with 400, before any handler or database work. A nil pattern only checks the length.
+func ValidateParam(name string, pattern *regexp.Regexp) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.Param(name)
+		if len(value) > maxParamLength || (pattern != nil && !pattern.MatchString(value)) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid " + name + " format",
+				"code":    InvalidIDFormatCode,
+			})
+			return
+		}
+		c.Next()
+	}
+}
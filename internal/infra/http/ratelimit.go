@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit returns a middleware allowing at most limit requests per client IP in each window.
+// A limit <= 0 disables it. Counters are kept in memory, so limits apply per instance.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	var (
+		mu          sync.Mutex
+		windowStart = time.Now()
+		counts      = make(map[string]int)
+	)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+
+		mu.Lock()
+		// Fixed window: start over (dropping stale clients) once the window has elapsed
+		if now.Sub(windowStart) >= window {
+			windowStart = now
+			counts = make(map[string]int)
+		}
+		ip := c.ClientIP()
+		counts[ip]++
+		count := counts[ip]
+		retryAfter := window - now.Sub(windowStart)
+		mu.Unlock()
+
+		if count > limit {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"success": false, "error": "Too many requests"})
+			return
+		}
+
+		c.Next()
+	}
+}
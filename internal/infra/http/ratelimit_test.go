@@ -0,0 +1,78 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		window     time.Duration
+		requests   []string // Client IP of each request, in order
+		wantStatus []int
+	}{
+		{
+			name:       "within the limit",
+			limit:      2,
+			window:     time.Minute,
+			requests:   []string{"10.0.0.1", "10.0.0.1"},
+			wantStatus: []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:       "over the limit",
+			limit:      2,
+			window:     time.Minute,
+			requests:   []string{"10.0.0.1", "10.0.0.1", "10.0.0.1"},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:       "limits are per client",
+			limit:      1,
+			window:     time.Minute,
+			requests:   []string{"10.0.0.1", "10.0.0.2", "10.0.0.1"},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:       "disabled",
+			limit:      0,
+			window:     time.Minute,
+			requests:   []string{"10.0.0.1", "10.0.0.1", "10.0.0.1"},
+			wantStatus: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:       "window elapsed",
+			limit:      1,
+			window:     time.Nanosecond,
+			requests:   []string{"10.0.0.1", "10.0.0.1"},
+			wantStatus: []int{http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/search", customhttp.RateLimit(tt.limit, tt.window), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			for i, ip := range tt.requests {
+				req := httptest.NewRequest(http.MethodGet, "/search", nil)
+				req.RemoteAddr = ip + ":1234"
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				if w.Code != tt.wantStatus[i] {
+					t.Fatalf("request %d status = %d, want %d", i, w.Code, tt.wantStatus[i])
+				}
+				if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+					t.Error("limited response has no Retry-After header")
+				}
+			}
+		})
+	}
+}
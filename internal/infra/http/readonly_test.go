@@ -0,0 +1,136 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/handler"
+	customhttp "github.com/emerarteaga/products-api/internal/infra/http"
+	"github.com/gin-gonic/gin"
+)
+
+const toggleRoute = "/api/v1/admin/read-only"
+
+// newReadOnlyRouter mirrors the production wiring: the toggle is admin-only and exempt from the write block
+func newReadOnlyRouter(mode *customhttp.ReadOnlyMode) *gin.Engine {
+	router := gin.New()
+	router.Use(customhttp.Authenticate(testAdminToken))
+	router.Use(customhttp.ReadOnly(mode, 120, toggleRoute))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	v1 := router.Group("/api/v1")
+	v1.GET("/products/:id", ok)
+	v1.HEAD("/products/:id", ok)
+	v1.POST("/products", ok)
+	v1.PUT("/products/:id", ok)
+	v1.PATCH("/orders", ok)
+	v1.DELETE("/products/:id", ok)
+
+	admin := v1.Group("/admin", customhttp.RequireAdmin())
+	admin.POST("/read-only", handler.NewMaintenanceHandler(mode).SetReadOnly)
+	admin.POST("/orders/archive", ok)
+	return router
+}
+
+func serve(router *gin.Engine, method, path, body string, admin bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if admin {
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func assertErrorCode(t *testing.T, w *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	var body struct {
+		Success bool   `json:"success"`
+		Code    string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", w.Body.String(), err)
+	}
+	if body.Success || body.Code != want {
+		t.Errorf("body = %s, want success false and code %s", w.Body.String(), want)
+	}
+}
+
+func TestReadOnlyExemptions(t *testing.T) {
+	tests := []struct {
+		method, path string
+		body         string
+		wantStatus   int
+	}{
+		{http.MethodGet, "/api/v1/products/p1", "", http.StatusOK},
+		{http.MethodHead, "/api/v1/products/p1", "", http.StatusOK},
+		{http.MethodPost, "/api/v1/products", "", http.StatusServiceUnavailable},
+		{http.MethodPut, "/api/v1/products/p1", "", http.StatusServiceUnavailable},
+		{http.MethodPatch, "/api/v1/orders", "", http.StatusServiceUnavailable},
+		{http.MethodDelete, "/api/v1/products/p1", "", http.StatusServiceUnavailable},
+		{http.MethodPost, "/api/v1/admin/orders/archive", "", http.StatusServiceUnavailable},
+		{http.MethodPost, toggleRoute, `{"enabled": true}`, http.StatusOK},
+	}
+
+	router := newReadOnlyRouter(customhttp.NewReadOnlyMode(true))
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := serve(router, tt.method, tt.path, tt.body, true)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				if got := w.Header().Get("Retry-After"); got != "120" {
+					t.Errorf("Retry-After = %q, want 120", got)
+				}
+				assertErrorCode(t, w, customhttp.ReadOnlyErrorCode)
+			}
+		})
+	}
+}
+
+func TestReadOnlyRuntimeToggle(t *testing.T) {
+	mode := customhttp.NewReadOnlyMode(false)
+	router := newReadOnlyRouter(mode)
+
+	if w := serve(router, http.MethodPost, "/api/v1/products", "", false); w.Code != http.StatusOK {
+		t.Fatalf("write before toggling: status = %d, want 200", w.Code)
+	}
+
+	// Without the admin token the toggle is refused and the mode is unchanged
+	if w := serve(router, http.MethodPost, toggleRoute, `{"enabled": true}`, false); w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous toggle: status = %d, want 401", w.Code)
+	}
+	if mode.Enabled() {
+		t.Fatal("anonymous toggle enabled read-only mode")
+	}
+
+	w := serve(router, http.MethodPost, toggleRoute, `{"enabled": true}`, true)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"read_only":true`) {
+		t.Fatalf("enable: status = %d, body %s", w.Code, w.Body.String())
+	}
+	if w := serve(router, http.MethodPost, "/api/v1/products", "", false); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("write while read-only: status = %d, want 503", w.Code)
+	}
+	if w := serve(router, http.MethodGet, "/api/v1/products/p1", "", false); w.Code != http.StatusOK {
+		t.Fatalf("read while read-only: status = %d, want 200", w.Code)
+	}
+
+	// The toggle stays writable so the mode can be switched off again
+	if w := serve(router, http.MethodPost, toggleRoute, `{"enabled": false}`, true); w.Code != http.StatusOK {
+		t.Fatalf("disable: status = %d, want 200", w.Code)
+	}
+	if w := serve(router, http.MethodPost, "/api/v1/products", "", false); w.Code != http.StatusOK {
+		t.Fatalf("write after disabling: status = %d, want 200", w.Code)
+	}
+
+	if w := serve(router, http.MethodPost, toggleRoute, `{}`, true); w.Code != http.StatusBadRequest {
+		t.Fatalf("toggle without enabled: status = %d, want 400", w.Code)
+	}
+}
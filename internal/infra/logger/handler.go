@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// levelRangeHandler only passes records with min <= level < max (no upper bound when max is nil)
+type levelRangeHandler struct {
+	slog.Handler
+	min slog.Level
+	max *slog.Level
+}
+
+// Enabled reports whether the level is in range and enabled by the wrapped handler
+func (h *levelRangeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.min || (h.max != nil && level >= *h.max) {
+		return false
+	}
+	return h.Handler.Enabled(ctx, level)
+}
+
+// WithAttrs keeps the level range on derived handlers
+func (h *levelRangeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelRangeHandler{Handler: h.Handler.WithAttrs(attrs), min: h.min, max: h.max}
+}
+
+// WithGroup keeps the level range on derived handlers
+func (h *levelRangeHandler) WithGroup(name string) slog.Handler {
+	return &levelRangeHandler{Handler: h.Handler.WithGroup(name), min: h.min, max: h.max}
+}
+
+// fanoutHandler sends each record to every handler that accepts its level
+type fanoutHandler []slog.Handler
+
+// Enabled reports whether any handler accepts the level
+func (f fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle writes the record to every handler that accepts it; one failing sink does not stop the others
+func (f fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs applies the attributes to every handler
+func (f fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanoutHandler, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+// WithGroup applies the group to every handler
+func (f fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make(fanoutHandler, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
@@ -1,42 +1,129 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/config"
+)
+
+// Log output destinations
+const (
+	OutputStdout = "stdout" // Every record; warnings and errors go to stderr instead when it is enabled
+	OutputStderr = "stderr" // Warnings and errors only
+	OutputFile   = "file"   // Every record, to a rotating file
 )
 
 var Log *slog.Logger
 
-// InitLogger initializes the global logger
-func InitLogger(level, format string) {
-	var logLevel slog.Level
+// accessLog receives the HTTP access logs; it is Log unless access logs have their own sink
+var accessLog *slog.Logger
+
+// closers are the file sinks opened by InitLogger
+var closers []io.Closer
+
+// InitLogger initializes the global logger with the configured outputs.
+// It fails if a file sink cannot be opened for writing.
+func InitLogger(cfg config.LoggerConfig) error {
+	opts := &slog.HandlerOptions{
+		Level: parseLevel(cfg.Level),
+	}
+
+	var files []io.Closer
+	openFile := func(path string) (*RollingFile, error) {
+		f, err := OpenRollingFile(path, int64(cfg.File.MaxSizeMB)<<20, cfg.File.MaxBackups, time.Duration(cfg.File.MaxAgeDays)*24*time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+		return f, nil
+	}
+	fail := func(err error) error {
+		for _, f := range files {
+			f.Close()
+		}
+		return err
+	}
+
+	outputs := make(map[string]bool, len(cfg.Outputs))
+	for _, output := range cfg.Outputs {
+		outputs[output] = true
+	}
+
+	var handlers fanoutHandler
+	if outputs[OutputStdout] {
+		var max *slog.Level
+		if outputs[OutputStderr] {
+			// Split: warnings and errors only go to stderr
+			warn := slog.LevelWarn
+			max = &warn
+		}
+		handlers = append(handlers, &levelRangeHandler{Handler: newHandler(os.Stdout, cfg.Format, opts), min: slog.LevelDebug, max: max})
+	}
+	if outputs[OutputStderr] {
+		handlers = append(handlers, &levelRangeHandler{Handler: newHandler(os.Stderr, cfg.Format, opts), min: slog.LevelWarn})
+	}
+	if outputs[OutputFile] {
+		f, err := openFile(cfg.File.Path)
+		if err != nil {
+			return fail(err)
+		}
+		handlers = append(handlers, newHandler(f, cfg.Format, opts))
+	}
+	if len(handlers) == 0 {
+		return fail(errors.New("no log output configured"))
+	}
 
+	log := slog.New(handlers)
+	access := log
+	if cfg.HTTPAccessSeparate {
+		f, err := openFile(cfg.HTTPAccessFile)
+		if err != nil {
+			return fail(fmt.Errorf("access log: %w", err))
+		}
+		access = slog.New(newHandler(f, cfg.Format, opts))
+	}
+
+	Close()
+	Log, accessLog, closers = log, access, files
+	slog.SetDefault(Log)
+	return nil
+}
+
+// Close closes the file sinks; call it on shutdown so buffered records reach the disk
+func Close() error {
+	var errs []error
+	for _, c := range closers {
+		errs = append(errs, c.Close())
+	}
+	closers = nil
+	return errors.Join(errs...)
+}
+
+// parseLevel maps the configured level name, defaulting to info
+func parseLevel(level string) slog.Level {
 	switch level {
 	case "debug":
-		logLevel = slog.LevelDebug
-	case "info":
-		logLevel = slog.LevelInfo
+		return slog.LevelDebug
 	case "warn":
-		logLevel = slog.LevelWarn
+		return slog.LevelWarn
 	case "error":
-		logLevel = slog.LevelError
+		return slog.LevelError
 	default:
-		logLevel = slog.LevelInfo
-	}
-
-	opts := &slog.HandlerOptions{
-		Level: logLevel,
+		return slog.LevelInfo
 	}
+}
 
-	var handler slog.Handler
+// newHandler returns a JSON or text handler writing to w
+func newHandler(w io.Writer, format string, opts *slog.HandlerOptions) slog.Handler {
 	if format == "json" {
-		handler = slog.NewJSONHandler(os.Stdout, opts)
-	} else {
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		return slog.NewJSONHandler(w, opts)
 	}
-
-	Log = slog.New(handler)
-	slog.SetDefault(Log)
+	return slog.NewTextHandler(w, opts)
 }
 
 // Debug logs a debug message with structured fields
@@ -58,3 +145,8 @@ func Warn(msg string, args ...any) {
 func Error(msg string, args ...any) {
 	Log.Error(msg, args...)
 }
+
+// Access logs an HTTP access record, to its own sink when LOGGER_HTTP_ACCESS_SEPARATE is set
+func Access(msg string, args ...any) {
+	accessLog.Info(msg, args...)
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/config"
+)
+
+// captureStd points os.Stdout and os.Stderr at files for the test and returns their paths
+func captureStd(t *testing.T) (stdout, stderr string) {
+	t.Helper()
+	dir := t.TempDir()
+	stdout, stderr = filepath.Join(dir, "stdout"), filepath.Join(dir, "stderr")
+	out, err := os.Create(stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errOut, err := os.Create(stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origOut, origErr, origLog, origAccess := os.Stdout, os.Stderr, Log, accessLog
+	os.Stdout, os.Stderr = out, errOut
+	t.Cleanup(func() {
+		Close()
+		os.Stdout, os.Stderr, Log, accessLog = origOut, origErr, origLog, origAccess
+		out.Close()
+		errOut.Close()
+	})
+	return stdout, stderr
+}
+
+func TestInitLoggerOutputs(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputs    []string
+		separate   bool
+		wantStdout []string
+		wantStderr []string
+		wantFile   []string
+		wantAccess []string
+	}{
+		{
+			name:       "stdout only",
+			outputs:    []string{OutputStdout},
+			wantStdout: []string{"info record", "warn record", "error record", "GET /"},
+		},
+		{
+			name:       "stderr split",
+			outputs:    []string{OutputStdout, OutputStderr},
+			wantStdout: []string{"info record", "GET /"},
+			wantStderr: []string{"warn record", "error record"},
+		},
+		{
+			name:       "stderr alone drops info",
+			outputs:    []string{OutputStderr},
+			wantStderr: []string{"warn record", "error record"},
+		},
+		{
+			name:       "file next to the split",
+			outputs:    []string{OutputStdout, OutputStderr, OutputFile},
+			wantStdout: []string{"info record", "GET /"},
+			wantStderr: []string{"warn record", "error record"},
+			wantFile:   []string{"info record", "warn record", "error record", "GET /"},
+		},
+		{
+			name:       "separate access log",
+			outputs:    []string{OutputStdout, OutputFile},
+			separate:   true,
+			wantStdout: []string{"info record", "warn record", "error record"},
+			wantFile:   []string{"info record", "warn record", "error record"},
+			wantAccess: []string{"GET /"},
+		},
+	}
+
+	records := []string{"info record", "warn record", "error record", "GET /"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := captureStd(t)
+			dir := t.TempDir()
+			cfg := config.LoggerConfig{
+				Level:              "info",
+				Format:             "text",
+				Outputs:            tt.outputs,
+				File:               config.LogFileConfig{Path: filepath.Join(dir, "app.log"), MaxSizeMB: 1},
+				HTTPAccessSeparate: tt.separate,
+				HTTPAccessFile:     filepath.Join(dir, "access.log"),
+			}
+			if err := InitLogger(cfg); err != nil {
+				t.Fatal(err)
+			}
+
+			Debug("debug record")
+			Info("info record")
+			Warn("warn record")
+			Error("error record")
+			Access("GET /")
+			if err := Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			sinks := []struct {
+				name string
+				path string
+				want []string
+			}{
+				{"stdout", stdout, tt.wantStdout},
+				{"stderr", stderr, tt.wantStderr},
+				{"file", cfg.File.Path, tt.wantFile},
+				{"access file", cfg.HTTPAccessFile, tt.wantAccess},
+			}
+			for _, sink := range sinks {
+				got := readLog(t, sink.path)
+				if strings.Contains(got, "debug record") {
+					t.Errorf("%s has a record below the level", sink.name)
+				}
+				for _, record := range records {
+					if has, want := strings.Contains(got, record), slices.Contains(sink.want, record); has != want {
+						t.Errorf("%s has %q = %v, want %v:\n%s", sink.name, record, has, want, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInitLoggerFailsLoudly(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unwritable := filepath.Join(blocker, "app.log")
+
+	tests := []struct {
+		name    string
+		cfg     config.LoggerConfig
+		wantErr string
+	}{
+		{"no outputs", config.LoggerConfig{}, "no log output configured"},
+		{"unwritable file", config.LoggerConfig{Outputs: []string{OutputFile}, File: config.LogFileConfig{Path: unwritable, MaxSizeMB: 1}}, unwritable},
+		{
+			name: "unwritable access file",
+			cfg: config.LoggerConfig{
+				Outputs:            []string{OutputStdout},
+				File:               config.LogFileConfig{MaxSizeMB: 1},
+				HTTPAccessSeparate: true,
+				HTTPAccessFile:     unwritable,
+			},
+			wantErr: "access log",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureStd(t)
+			before := Log
+
+			err := InitLogger(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if Log != before {
+				t.Error("a failed init replaced the logger")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RollingFile is an io.Writer that rotates the file once it would grow past MaxSize.
+// Rotated files are renamed to path.1 (newest) through path.<maxBackups>; older ones are deleted,
+// as are backups last written more than maxAge ago.
+type RollingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration // Zero keeps backups regardless of age
+	file       *os.File
+	size       int64
+}
+
+// OpenRollingFile opens (or creates) the log file, creating its directory if needed.
+// It fails if the path is not writable, so misconfigured sinks are caught at startup.
+func OpenRollingFile(path string, maxSize int64, maxBackups int, maxAge time.Duration) (*RollingFile, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("log file %s: max size must be positive", path)
+	}
+	r := &RollingFile{path: path, maxSize: maxSize, maxBackups: maxBackups, maxAge: maxAge}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("log file %s: %w", path, err)
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write appends p to the file, rotating first if p would push it past the size limit
+func (r *RollingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (r *RollingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// open opens the log file for appending and picks up its current size
+func (r *RollingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("log file %s: %w", r.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("log file %s: %w", r.path, err)
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts the backups by one, moves the current file to path.1 and starts a new one
+func (r *RollingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("log file %s: %w", r.path, err)
+	}
+
+	if r.maxBackups > 0 {
+		// The oldest backup is overwritten by the shift, so it is dropped first
+		os.Remove(r.backupPath(r.maxBackups))
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			os.Rename(r.backupPath(i), r.backupPath(i+1))
+		}
+		if err := os.Rename(r.path, r.backupPath(1)); err != nil {
+			return fmt.Errorf("log file %s: %w", r.path, err)
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return fmt.Errorf("log file %s: %w", r.path, err)
+	}
+
+	r.removeExpiredBackups()
+	return r.open()
+}
+
+// removeExpiredBackups deletes backups last written before the max age
+func (r *RollingFile) removeExpiredBackups() {
+	if r.maxAge <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-r.maxAge)
+	for i := 1; i <= r.maxBackups; i++ {
+		if info, err := os.Stat(r.backupPath(i)); err == nil && info.ModTime().Before(cutoff) {
+			os.Remove(r.backupPath(i))
+		}
+	}
+}
+
+// backupPath returns the name of the n-th most recent backup
+func (r *RollingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", r.path, n)
+}
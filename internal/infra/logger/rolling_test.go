@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readLog returns the content of a log file, or "" when it does not exist
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestRollingFileRotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxBackups  int
+		writes      []string
+		wantCurrent string
+		wantBackups []string // path.1 first
+	}{
+		{"under the limit", 2, []string{"aaaa\n", "bbbb\n"}, "aaaa\nbbbb\n", []string{"", ""}},
+		{"exactly the limit", 2, []string{"aaaa\n", "bbbbb\n"}, "aaaa\nbbbbb\n", []string{"", ""}},
+		{"past the limit", 2, []string{"aaaa\n", "bbbb\n", "cccc\n"}, "cccc\n", []string{"aaaa\nbbbb\n", ""}},
+		{"backups shift", 2, []string{"aaaaaaa\n", "bbbbbbb\n", "ccccccc\n"}, "ccccccc\n", []string{"bbbbbbb\n", "aaaaaaa\n"}},
+		{"oldest backup dropped", 2, []string{"aaaaaaa\n", "bbbbbbb\n", "ccccccc\n", "ddddddd\n"}, "ddddddd\n", []string{"ccccccc\n", "bbbbbbb\n"}},
+		{"no backups kept", 0, []string{"aaaaaaa\n", "bbbbbbb\n"}, "bbbbbbb\n", []string{""}},
+		{"oversized record is not split", 1, []string{"a\n", strings.Repeat("b", 20) + "\n"}, strings.Repeat("b", 20) + "\n", []string{"a\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logs", "app.log")
+			f, err := OpenRollingFile(path, 11, tt.maxBackups, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			for _, w := range tt.writes {
+				if n, err := f.Write([]byte(w)); err != nil || n != len(w) {
+					t.Fatalf("write %q = %d, %v", w, n, err)
+				}
+			}
+
+			if got := readLog(t, path); got != tt.wantCurrent {
+				t.Errorf("current = %q, want %q", got, tt.wantCurrent)
+			}
+			for i, want := range tt.wantBackups {
+				if got := readLog(t, f.backupPath(i+1)); got != want {
+					t.Errorf("backup %d = %q, want %q", i+1, got, want)
+				}
+			}
+			if got := readLog(t, f.backupPath(len(tt.wantBackups)+1)); got != "" {
+				t.Errorf("backup past the limit kept: %q", got)
+			}
+		})
+	}
+}
+
+func TestRollingFileAppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("previous run\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenRollingFile(path, 16, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("restart\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The size of the existing file counts toward the limit
+	if got := readLog(t, path); got != "restart\n" {
+		t.Errorf("current = %q, want the new record only", got)
+	}
+	if got := readLog(t, f.backupPath(1)); got != "previous run\n" {
+		t.Errorf("backup = %q, want the previous run", got)
+	}
+}
+
+func TestRollingFileMaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRollingFile(path, 4, 3, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, w := range []string{"old\n", "new\n"} {
+		if _, err := f.Write([]byte(w)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(f.backupPath(1), stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next rotation shifts the stale backup to path.2 and deletes it
+	if _, err := f.Write([]byte("now\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := readLog(t, f.backupPath(1)); got != "new\n" {
+		t.Errorf("backup 1 = %q, want the recent one", got)
+	}
+	if got := readLog(t, f.backupPath(2)); got != "" {
+		t.Errorf("expired backup kept: %q", got)
+	}
+}
+
+func TestOpenRollingFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		maxSize int64
+	}{
+		{"directory is a file", filepath.Join(blocker, "app.log"), 1024},
+		{"path is a directory", dir, 1024},
+		{"no size limit", filepath.Join(dir, "app.log"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := OpenRollingFile(tt.path, tt.maxSize, 1, 0)
+			if err == nil {
+				f.Close()
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.path) {
+				t.Errorf("err = %v, want it to name %s", err, tt.path)
+			}
+		})
+	}
+}
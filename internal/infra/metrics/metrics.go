@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scrapeTimeout bounds the repository queries run on each scrape
+const scrapeTimeout = 5 * time.Second
+
+// Metrics holds the Prometheus registry and the business metrics of the application
+type Metrics struct {
+	registry         *prometheus.Registry
+	ordersCreated    *prometheus.CounterVec
+	ordersCancelled  *prometheus.CounterVec
+	ordersDelivered  *prometheus.CounterVec
+	openOrdersSource order.Repository
+	openOrdersDesc   *prometheus.Desc
+}
+
+// New creates the business metrics and registers them in a dedicated registry.
+// Open order gauges are computed on scrape by counting orders in the repository.
+func New(orderRepo order.Repository) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		ordersCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_created_total",
+			Help: "Number of orders created.",
+		}, []string{"sale_type"}),
+		ordersCancelled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_cancelled_total",
+			Help: "Number of orders cancelled.",
+		}, []string{"sale_type"}),
+		ordersDelivered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_delivered_total",
+			Help: "Number of orders delivered.",
+		}, []string{"sale_type"}),
+		openOrdersSource: orderRepo,
+		openOrdersDesc: prometheus.NewDesc(
+			"orders_open",
+			"Number of orders currently in a non-terminal status.",
+			[]string{"status"}, nil,
+		),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.ordersCreated,
+		m.ordersCancelled,
+		m.ordersDelivered,
+		m,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler that exposes the metrics for scraping
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Describe implements prometheus.Collector for the open orders gauge
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.openOrdersDesc
+}
+
+// Collect implements prometheus.Collector, counting open orders per status
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	for _, status := range order.OpenStatuses {
+		count, err := m.openOrdersSource.Count(ctx, order.OrderFilters{Status: &status})
+		if err != nil {
+			logger.Warn("failed to count open orders", "status", status, "error", err)
+			ch <- prometheus.NewInvalidMetric(m.openOrdersDesc, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(m.openOrdersDesc, prometheus.GaugeValue, float64(count), string(status))
+	}
+}
+
+// OrderCreated implements order.EventRecorder
+func (m *Metrics) OrderCreated(o *order.Order) {
+	m.ordersCreated.WithLabelValues(string(o.SaleType)).Inc()
+}
+
+// OrderStatusChanged implements order.EventRecorder
+func (m *Metrics) OrderStatusChanged(o *order.Order, _ order.OrderStatus) {
+	switch o.Status {
+	case order.StatusCancelled:
+		m.ordersCancelled.WithLabelValues(string(o.SaleType)).Inc()
+	case order.StatusDelivered:
+		m.ordersDelivered.WithLabelValues(string(o.SaleType)).Inc()
+	}
+}
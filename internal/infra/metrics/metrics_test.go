@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	os.Exit(m.Run())
+}
+
+// countingRepository counts seeded orders by status; the other methods panic through the nil interface
+type countingRepository struct {
+	order.Repository
+	orders []*order.Order
+	err    error
+}
+
+func (r *countingRepository) Count(ctx context.Context, filters order.OrderFilters) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	var count int64
+	for _, o := range r.orders {
+		if filters.Status == nil || o.Status == *filters.Status {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// seed returns orders of the given sale type, one per status listed
+func seed(saleType order.SaleType, statuses ...order.OrderStatus) []*order.Order {
+	orders := make([]*order.Order, len(statuses))
+	for i, status := range statuses {
+		orders[i] = &order.Order{SaleType: saleType, Status: status}
+	}
+	return orders
+}
+
+// scrape returns the exposition text of the metrics
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d, body %s", w.Code, w.Body.String())
+	}
+	return w.Body.String()
+}
+
+func TestOpenOrdersGauge(t *testing.T) {
+	tests := []struct {
+		name   string
+		orders []*order.Order
+		want   []string
+	}{
+		{
+			name: "no orders",
+			want: []string{
+				`orders_open{status="CREATED"} 0`,
+				`orders_open{status="IN_PROGRESS"} 0`,
+			},
+		},
+		{
+			name: "counts open statuses only",
+			orders: append(
+				seed(order.SaleTypeDelivery, order.StatusInProgress, order.StatusInProgress, order.StatusCreated, order.StatusDelivered),
+				seed(order.SaleTypeOnSite, order.StatusInProgress, order.StatusCancelled)...,
+			),
+			want: []string{
+				`orders_open{status="CREATED"} 1`,
+				`orders_open{status="IN_PROGRESS"} 3`,
+				`orders_open{status="VERIFIED"} 0`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := scrape(t, New(&countingRepository{orders: tt.orders}))
+			for _, want := range tt.want {
+				if !strings.Contains(body, want+"\n") {
+					t.Errorf("scrape misses %s", want)
+				}
+			}
+			for _, terminal := range []order.OrderStatus{order.StatusDelivered, order.StatusCancelled} {
+				if strings.Contains(body, `orders_open{status="`+string(terminal)+`"}`) {
+					t.Errorf("scrape has a gauge for terminal status %s", terminal)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenOrdersGaugeCountFailure(t *testing.T) {
+	m := New(&countingRepository{err: errors.New("database unavailable")})
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d when counting fails", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestLifecycleCounters(t *testing.T) {
+	m := New(&countingRepository{})
+
+	delivery := &order.Order{SaleType: order.SaleTypeDelivery}
+	onSite := &order.Order{SaleType: order.SaleTypeOnSite}
+	m.OrderCreated(delivery)
+	m.OrderCreated(delivery)
+	m.OrderCreated(onSite)
+
+	delivery.Status = order.StatusDelivered
+	m.OrderStatusChanged(delivery, order.StatusInProgress)
+	onSite.Status = order.StatusCancelled
+	m.OrderStatusChanged(onSite, order.StatusCreated)
+	onSite.Status = order.StatusInProgress
+	m.OrderStatusChanged(onSite, order.StatusCreated)
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`orders_created_total{sale_type="DELIVERY"} 2`,
+		`orders_created_total{sale_type="ON_SITE"} 1`,
+		`orders_delivered_total{sale_type="DELIVERY"} 1`,
+		`orders_cancelled_total{sale_type="ON_SITE"} 1`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("scrape misses %s", want)
+		}
+	}
+	if strings.Contains(body, `orders_delivered_total{sale_type="ON_SITE"}`) {
+		t.Error("a non-terminal status change counted as delivered")
+	}
+}
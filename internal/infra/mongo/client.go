@@ -9,6 +9,7 @@ import (
 	"github.com/emerarteaga/products-api/internal/infra/logger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // Client holds the MongoDB client and database
@@ -21,10 +22,20 @@ type Client struct {
 func NewClient(ctx context.Context, cfg *config.DatabaseConfig) (*Client, error) {
 	logger.Info("connecting to MongoDB", "uri", cfg.URI, "database", cfg.Name)
 
+	mode, err := readpref.ModeFromString(cfg.ReadPreference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read preference: %w", err)
+	}
+	readPref, err := readpref.New(mode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read preference: %w", err)
+	}
+
 	clientOpts := options.Client().
 		ApplyURI(cfg.URI).
 		SetMaxPoolSize(cfg.MaxPoolSize).
-		SetTimeout(time.Duration(cfg.Timeout) * time.Second)
+		SetTimeout(time.Duration(cfg.Timeout) * time.Second).
+		SetReadPreference(readPref)
 
 	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
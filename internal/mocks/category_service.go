@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/emerarteaga/products-api/internal/domain/category"
+)
+
+// CategoryService is a hand-written mock of category.ServiceAPI.
+// Set the Func field of each method a test needs; unset methods return ErrNotMocked.
+type CategoryService struct {
+	CreateFunc  func(ctx context.Context, input category.CreateInput) (*category.Category, error)
+	GetByIDFunc func(ctx context.Context, id string) (*category.Category, error)
+	ListFunc    func(ctx context.Context, salePointID string, filters category.ListFilters) (*category.Listing, error)
+	UpdateFunc  func(ctx context.Context, id string, input category.UpdateInput) (*category.Category, error)
+	DeleteFunc  func(ctx context.Context, id string) error
+}
+
+// Compile-time check that CategoryService implements category.ServiceAPI
+var _ category.ServiceAPI = (*CategoryService)(nil)
+
+func (m *CategoryService) Create(ctx context.Context, input category.CreateInput) (*category.Category, error) {
+	if m.CreateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, input)
+}
+
+func (m *CategoryService) GetByID(ctx context.Context, id string) (*category.Category, error) {
+	if m.GetByIDFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetByIDFunc(ctx, id)
+}
+
+func (m *CategoryService) List(ctx context.Context, salePointID string, filters category.ListFilters) (*category.Listing, error) {
+	if m.ListFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ListFunc(ctx, salePointID, filters)
+}
+
+func (m *CategoryService) Update(ctx context.Context, id string, input category.UpdateInput) (*category.Category, error) {
+	if m.UpdateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.UpdateFunc(ctx, id, input)
+}
+
+func (m *CategoryService) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, id)
+}
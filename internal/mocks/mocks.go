@@ -0,0 +1,8 @@
+// Package mocks provides hand-written mocks of the domain service interfaces
+// so handlers can be exercised without a database.
+package mocks
+
+import "errors"
+
+// ErrNotMocked is returned by mock methods whose Func field was not set
+var ErrNotMocked = errors.New("mocks: method not mocked")
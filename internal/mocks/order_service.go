@@ -0,0 +1,239 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/money"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// OrderService is a hand-written mock of order.ServiceAPI.
+// Set the Func field of each method a test needs; unset methods return ErrNotMocked.
+type OrderService struct {
+	CreateFunc             func(ctx context.Context, input order.CreateInput) (*order.Order, error)
+	ValidateCreateFunc     func(ctx context.Context, input order.CreateInput) (*order.Order, error)
+	GetByIDFunc            func(ctx context.Context, id string) (*order.Order, error)
+	GetByCodeFunc          func(ctx context.Context, code string) (*order.Order, error)
+	PartialUpdateFunc      func(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error)
+	ModifyFunc             func(ctx context.Context, code string, input order.ModifyInput) (*order.ModifyResult, error)
+	GetAllFunc             func(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error)
+	SearchFunc             func(ctx context.Context, query string, filters order.OrderFilters) ([]order.SearchResult, int64, error)
+	GetCustomerHistoryFunc func(ctx context.Context, q order.CustomerHistoryQuery) (*order.CustomerHistory, error)
+	GetMetricsFunc         func(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error)
+	GetProductSalesFunc    func(ctx context.Context, filters order.OrderFilters, sortBy order.ProductSalesSort) ([]order.ProductSales, int64, error)
+	RecalculateTotalsFunc  func(ctx context.Context, input order.RecalculateTotalsInput) (*order.RecalculateTotalsResult, error)
+	ArchiveFunc            func(ctx context.Context, input order.ArchiveInput) (*order.ArchiveResult, error)
+	PageLimitsFunc         func() util.PageLimits
+	LimitsFunc             func() order.OrderLimits
+	CurrencyFunc           func() money.Currency
+	FilterLocationFunc     func() *time.Location
+	TransitionsFunc        func(saleType order.SaleType) order.Transitions
+	TrackingURLFunc        func(code string) (string, error)
+	CustomerEditFunc       func(ctx context.Context, code string, input order.CustomerEditInput) (*order.Order, error)
+	DuplicateFunc          func(ctx context.Context, code string, input order.DuplicateInput) (*order.DuplicateResult, error)
+	ExportFunc             func(ctx context.Context, input order.ExportInput, emit order.ExportEmitFunc) error
+	GetZReportFunc         func(ctx context.Context, date string, loc *time.Location) (*order.ZReport, error)
+	SubscribeStatusFunc    func(code string) (*order.StatusSubscription, error)
+	ConfirmPaymentFunc     func(ctx context.Context, code string) (*order.Order, error)
+	AddNoteFunc            func(ctx context.Context, code string, input order.AddNoteInput) (*order.Order, error)
+	BatchUpdateStatusFunc  func(ctx context.Context, input order.BatchStatusInput) ([]order.BatchStatusResult, error)
+}
+
+// Compile-time check that OrderService implements order.ServiceAPI
+var _ order.ServiceAPI = (*OrderService)(nil)
+
+func (m *OrderService) Create(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+	if m.CreateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, input)
+}
+
+func (m *OrderService) ValidateCreate(ctx context.Context, input order.CreateInput) (*order.Order, error) {
+	if m.ValidateCreateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ValidateCreateFunc(ctx, input)
+}
+
+func (m *OrderService) GetByID(ctx context.Context, id string) (*order.Order, error) {
+	if m.GetByIDFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetByIDFunc(ctx, id)
+}
+
+func (m *OrderService) GetByCode(ctx context.Context, code string) (*order.Order, error) {
+	if m.GetByCodeFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetByCodeFunc(ctx, code)
+}
+
+func (m *OrderService) PartialUpdate(ctx context.Context, code string, input order.PartialUpdateInput) (*order.Order, error) {
+	if m.PartialUpdateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.PartialUpdateFunc(ctx, code, input)
+}
+
+func (m *OrderService) Modify(ctx context.Context, code string, input order.ModifyInput) (*order.ModifyResult, error) {
+	if m.ModifyFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ModifyFunc(ctx, code, input)
+}
+
+func (m *OrderService) GetAll(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+	if m.GetAllFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetAllFunc(ctx, filters)
+}
+
+func (m *OrderService) Search(ctx context.Context, query string, filters order.OrderFilters) ([]order.SearchResult, int64, error) {
+	if m.SearchFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.SearchFunc(ctx, query, filters)
+}
+
+func (m *OrderService) GetCustomerHistory(ctx context.Context, q order.CustomerHistoryQuery) (*order.CustomerHistory, error) {
+	if m.GetCustomerHistoryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCustomerHistoryFunc(ctx, q)
+}
+
+func (m *OrderService) GetMetrics(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+	if m.GetMetricsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetMetricsFunc(ctx, filters)
+}
+
+func (m *OrderService) GetProductSales(ctx context.Context, filters order.OrderFilters, sortBy order.ProductSalesSort) ([]order.ProductSales, int64, error) {
+	if m.GetProductSalesFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetProductSalesFunc(ctx, filters, sortBy)
+}
+
+func (m *OrderService) RecalculateTotals(ctx context.Context, input order.RecalculateTotalsInput) (*order.RecalculateTotalsResult, error) {
+	if m.RecalculateTotalsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.RecalculateTotalsFunc(ctx, input)
+}
+
+func (m *OrderService) Archive(ctx context.Context, input order.ArchiveInput) (*order.ArchiveResult, error) {
+	if m.ArchiveFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ArchiveFunc(ctx, input)
+}
+
+// PageLimits returns util.DefaultPageLimits unless PageLimitsFunc is set
+func (m *OrderService) PageLimits() util.PageLimits {
+	if m.PageLimitsFunc == nil {
+		return util.DefaultPageLimits
+	}
+	return m.PageLimitsFunc()
+}
+
+// Limits returns no limits unless LimitsFunc is set
+func (m *OrderService) Limits() order.OrderLimits {
+	if m.LimitsFunc == nil {
+		return order.OrderLimits{}
+	}
+	return m.LimitsFunc()
+}
+
+// Currency returns money.Default unless CurrencyFunc is set
+func (m *OrderService) Currency() money.Currency {
+	if m.CurrencyFunc == nil {
+		return money.Default
+	}
+	return m.CurrencyFunc()
+}
+
+// FilterLocation returns time.UTC unless FilterLocationFunc is set
+func (m *OrderService) FilterLocation() *time.Location {
+	if m.FilterLocationFunc == nil {
+		return time.UTC
+	}
+	return m.FilterLocationFunc()
+}
+
+// Transitions returns order.DefaultTransitionsFor(saleType) unless TransitionsFunc is set
+func (m *OrderService) Transitions(saleType order.SaleType) order.Transitions {
+	if m.TransitionsFunc == nil {
+		return order.DefaultTransitionsFor(saleType)
+	}
+	return m.TransitionsFunc(saleType)
+}
+
+func (m *OrderService) TrackingURL(code string) (string, error) {
+	if m.TrackingURLFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.TrackingURLFunc(code)
+}
+
+func (m *OrderService) CustomerEdit(ctx context.Context, code string, input order.CustomerEditInput) (*order.Order, error) {
+	if m.CustomerEditFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CustomerEditFunc(ctx, code, input)
+}
+
+func (m *OrderService) GetZReport(ctx context.Context, date string, loc *time.Location) (*order.ZReport, error) {
+	if m.GetZReportFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetZReportFunc(ctx, date, loc)
+}
+
+func (m *OrderService) Duplicate(ctx context.Context, code string, input order.DuplicateInput) (*order.DuplicateResult, error) {
+	if m.DuplicateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DuplicateFunc(ctx, code, input)
+}
+
+func (m *OrderService) Export(ctx context.Context, input order.ExportInput, emit order.ExportEmitFunc) error {
+	if m.ExportFunc == nil {
+		return ErrNotMocked
+	}
+	return m.ExportFunc(ctx, input, emit)
+}
+
+func (m *OrderService) SubscribeStatus(code string) (*order.StatusSubscription, error) {
+	if m.SubscribeStatusFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.SubscribeStatusFunc(code)
+}
+
+func (m *OrderService) ConfirmPayment(ctx context.Context, code string) (*order.Order, error) {
+	if m.ConfirmPaymentFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ConfirmPaymentFunc(ctx, code)
+}
+
+func (m *OrderService) AddNote(ctx context.Context, code string, input order.AddNoteInput) (*order.Order, error) {
+	if m.AddNoteFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AddNoteFunc(ctx, code, input)
+}
+
+func (m *OrderService) BatchUpdateStatus(ctx context.Context, input order.BatchStatusInput) ([]order.BatchStatusResult, error) {
+	if m.BatchUpdateStatusFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.BatchUpdateStatusFunc(ctx, input)
+}
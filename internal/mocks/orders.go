@@ -0,0 +1,101 @@
+package mocks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+)
+
+// sampleEpoch is the fixed creation time of the first sample order
+var sampleEpoch = time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+// SampleOrders returns n deterministic orders covering the optional fields of an order
+// (customer, discount, notes, addons, history, archive), for golden files and benchmarks
+func SampleOrders(n int) []*order.Order {
+	orders := make([]*order.Order, n)
+	for i := range orders {
+		orders[i] = sampleOrder(i)
+	}
+	return orders
+}
+
+func sampleOrder(i int) *order.Order {
+	created := sampleEpoch.Add(time.Duration(i) * 17 * time.Minute)
+	o := &order.Order{
+		ID:            fmt.Sprintf("00000000-0000-4000-8000-%012d", i),
+		Code:          fmt.Sprintf("ORD-%06X", 0x7F3A00+i),
+		CompanyID:     "11111111-1111-4111-8111-111111111111",
+		SalePointID:   "22222222-2222-4222-8222-222222222222",
+		Status:        order.StatusCreated,
+		SaleType:      order.SaleTypeOnSite,
+		Channel:       order.ChannelPOS,
+		PaymentStatus: order.PaymentPending,
+		Version:       int64(1 + i%3),
+		CreatedAt:     created,
+		UpdatedAt:     created.Add(5 * time.Minute),
+	}
+
+	// 1 to 8 lines; every third line has addons and observations
+	for l := 0; l < 1+i%8; l++ {
+		line := order.OrderProduct{
+			ID:       fmt.Sprintf("33333333-3333-4333-8333-%012d", l),
+			Name:     fmt.Sprintf("Hamburguesa <doble> & papas %d", l),
+			Price:    int64(1500000 + l*250000),
+			Quantity: 1 + l%3,
+		}
+		if l%3 == 0 {
+			observation := "sin cebolla, \"bien asada\""
+			line.Observation = &observation
+			line.SelectedObservations = []string{"Sin salsa", "Extra queso"}
+			line.Addons = []order.OrderProductAddon{{ID: "addon-bacon", Name: "Tocineta", Price: 300000, Quantity: 1}}
+		}
+		o.Products = append(o.Products, line)
+	}
+
+	if i%2 == 0 {
+		address := "Calle 10 # 43-12, apto 501"
+		o.SaleType = order.SaleTypeDelivery
+		o.Channel = order.ChannelWeb
+		o.ShippingAddress = &address
+		o.Customer = &order.Customer{
+			Identification:  fmt.Sprintf("10%08d", i),
+			IDType:          order.IDTypeCC,
+			Name:            "María José Ñúñez",
+			Phone:           "300 123 4567",
+			PhoneNormalized: "+573001234567",
+		}
+	} else {
+		table := 1 + i%12
+		o.TableNumber = &table
+	}
+
+	if i%4 == 1 {
+		o.Discount = &order.Discount{Type: order.DiscountPercent, Value: 10, Description: "Happy hour"}
+	}
+	o.CalculateTotal()
+
+	if i%3 == 2 {
+		o.Notes = []order.OrderNote{
+			{Text: "Cliente llamó", Author: "caja", Visibility: order.NoteInternal, CreatedAt: created.Add(time.Minute)},
+			{Text: "Su pedido sale en 10 min", Author: "cocina", Visibility: order.NotePublic, CreatedAt: created.Add(2 * time.Minute)},
+		}
+	}
+	if i%5 == 3 {
+		verified := created.Add(3 * time.Minute)
+		o.Status = order.StatusVerified
+		o.VerifiedAt = &verified
+		o.StatusHistory = []order.StatusChange{{From: order.StatusCreated, To: order.StatusVerified, Actor: order.ActorUser, ChangedAt: verified}}
+	}
+	if i%7 == 6 {
+		delivered := created.Add(40 * time.Minute)
+		archived := created.Add(90 * 24 * time.Hour)
+		receipt := "https://cdn.example.com/receipts/r.png?a=1&b=2"
+		o.Status = order.StatusDelivered
+		o.DeliveredAt = &delivered
+		o.ArchivedAt = &archived
+		o.PaymentReceiptURL = &receipt
+		o.PaymentStatus = order.PaymentConfirmed
+	}
+	return o
+}
@@ -0,0 +1,243 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/util"
+)
+
+// ProductService is a hand-written mock of product.ServiceAPI.
+// Set the Func field of each method a test needs; unset methods return ErrNotMocked.
+type ProductService struct {
+	CreateFunc                     func(ctx context.Context, input product.CreateInput) (*product.Product, error)
+	GetByIDFunc                    func(ctx context.Context, id string) (*product.Product, error)
+	GetBySKUFunc                   func(ctx context.Context, salePointID, sku string) (*product.Product, error)
+	GetByBarcodeFunc               func(ctx context.Context, companyID, barcode string) (*product.Product, error)
+	GetByIDsFunc                   func(ctx context.Context, input product.BatchInput) (*product.BatchResult, error)
+	GetByCompanyIDFunc             func(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error)
+	GetBySalePointIDFunc           func(ctx context.Context, salePointID string, filters product.ProductFilters) ([]*product.Product, int64, error)
+	UpdateFunc                     func(ctx context.Context, id string, input product.UpdateInput) (*product.Product, error)
+	AdjustStockFunc                func(ctx context.Context, id string, adjustment product.StockAdjustment) (*product.Product, error)
+	DeleteFunc                     func(ctx context.Context, id string, hard bool) error
+	RestoreFunc                    func(ctx context.Context, id string) (*product.Product, error)
+	GetPriceHistoryFunc            func(ctx context.Context, id string, limit, offset int) ([]product.PriceChange, int64, error)
+	BulkDeleteFunc                 func(ctx context.Context, input product.BulkDeleteInput) (*product.BulkDeleteResult, error)
+	BulkSetAvailabilityFunc        func(ctx context.Context, input product.BulkAvailabilityInput) (*product.BulkAvailabilityResult, error)
+	ReorderFunc                    func(ctx context.Context, input product.ReorderInput) (*product.ReorderResult, error)
+	CopyFunc                       func(ctx context.Context, input product.CopyInput) (*product.CopyResult, error)
+	GetLowStockFunc                func(ctx context.Context, threshold, limit int) ([]*product.Product, error)
+	GetCompanySummaryFunc          func(ctx context.Context, companyID string) (*product.CompanySummary, error)
+	GetMenuFunc                    func(ctx context.Context, salePointID string, filters product.MenuFilters) ([]product.MenuSection, int64, error)
+	GetCategoriesByCompanyIDFunc   func(ctx context.Context, companyID string, filters product.CategoryFilters) ([]product.CategorySummary, int64, error)
+	GetCategoriesBySalePointIDFunc func(ctx context.Context, salePointID string, filters product.CategoryFilters) ([]product.CategorySummary, int64, error)
+	RenameCategoryFunc             func(ctx context.Context, input product.CategoryRenameInput) (*product.CategoryRenameResult, error)
+	GetTagsByCompanyIDFunc         func(ctx context.Context, companyID string, filters product.CategoryFilters) ([]product.TagSummary, int64, error)
+	ImportFunc                     func(ctx context.Context, input product.ImportInput) (*product.ImportResult, error)
+	ExportFunc                     func(ctx context.Context, input product.ExportInput, emit product.ExportEmitFunc) error
+	CompanyPageLimitsFunc          func() util.PageLimits
+	SalePointPageLimitsFunc        func() util.PageLimits
+	ImportLimitsFunc               func() product.ImportLimits
+}
+
+// Compile-time check that ProductService implements product.ServiceAPI
+var _ product.ServiceAPI = (*ProductService)(nil)
+
+func (m *ProductService) Create(ctx context.Context, input product.CreateInput) (*product.Product, error) {
+	if m.CreateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, input)
+}
+
+func (m *ProductService) GetByID(ctx context.Context, id string) (*product.Product, error) {
+	if m.GetByIDFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetByIDFunc(ctx, id)
+}
+
+func (m *ProductService) GetBySKU(ctx context.Context, salePointID, sku string) (*product.Product, error) {
+	if m.GetBySKUFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetBySKUFunc(ctx, salePointID, sku)
+}
+
+func (m *ProductService) GetByBarcode(ctx context.Context, companyID, barcode string) (*product.Product, error) {
+	if m.GetByBarcodeFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetByBarcodeFunc(ctx, companyID, barcode)
+}
+
+func (m *ProductService) GetByIDs(ctx context.Context, input product.BatchInput) (*product.BatchResult, error) {
+	if m.GetByIDsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetByIDsFunc(ctx, input)
+}
+
+func (m *ProductService) GetByCompanyID(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+	if m.GetByCompanyIDFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetByCompanyIDFunc(ctx, companyID, filters)
+}
+
+func (m *ProductService) GetBySalePointID(ctx context.Context, salePointID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+	if m.GetBySalePointIDFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetBySalePointIDFunc(ctx, salePointID, filters)
+}
+
+func (m *ProductService) BulkDelete(ctx context.Context, input product.BulkDeleteInput) (*product.BulkDeleteResult, error) {
+	if m.BulkDeleteFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.BulkDeleteFunc(ctx, input)
+}
+
+func (m *ProductService) BulkSetAvailability(ctx context.Context, input product.BulkAvailabilityInput) (*product.BulkAvailabilityResult, error) {
+	if m.BulkSetAvailabilityFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.BulkSetAvailabilityFunc(ctx, input)
+}
+
+func (m *ProductService) Reorder(ctx context.Context, input product.ReorderInput) (*product.ReorderResult, error) {
+	if m.ReorderFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ReorderFunc(ctx, input)
+}
+
+func (m *ProductService) Copy(ctx context.Context, input product.CopyInput) (*product.CopyResult, error) {
+	if m.CopyFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CopyFunc(ctx, input)
+}
+
+func (m *ProductService) GetLowStock(ctx context.Context, threshold, limit int) ([]*product.Product, error) {
+	if m.GetLowStockFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetLowStockFunc(ctx, threshold, limit)
+}
+
+func (m *ProductService) GetCompanySummary(ctx context.Context, companyID string) (*product.CompanySummary, error) {
+	if m.GetCompanySummaryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCompanySummaryFunc(ctx, companyID)
+}
+
+func (m *ProductService) GetMenu(ctx context.Context, salePointID string, filters product.MenuFilters) ([]product.MenuSection, int64, error) {
+	if m.GetMenuFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetMenuFunc(ctx, salePointID, filters)
+}
+
+func (m *ProductService) Update(ctx context.Context, id string, input product.UpdateInput) (*product.Product, error) {
+	if m.UpdateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.UpdateFunc(ctx, id, input)
+}
+
+func (m *ProductService) AdjustStock(ctx context.Context, id string, adjustment product.StockAdjustment) (*product.Product, error) {
+	if m.AdjustStockFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AdjustStockFunc(ctx, id, adjustment)
+}
+
+func (m *ProductService) Delete(ctx context.Context, id string, hard bool) error {
+	if m.DeleteFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, id, hard)
+}
+
+func (m *ProductService) Restore(ctx context.Context, id string) (*product.Product, error) {
+	if m.RestoreFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.RestoreFunc(ctx, id)
+}
+
+func (m *ProductService) GetPriceHistory(ctx context.Context, id string, limit, offset int) ([]product.PriceChange, int64, error) {
+	if m.GetPriceHistoryFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetPriceHistoryFunc(ctx, id, limit, offset)
+}
+
+func (m *ProductService) GetCategoriesByCompanyID(ctx context.Context, companyID string, filters product.CategoryFilters) ([]product.CategorySummary, int64, error) {
+	if m.GetCategoriesByCompanyIDFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetCategoriesByCompanyIDFunc(ctx, companyID, filters)
+}
+
+func (m *ProductService) GetCategoriesBySalePointID(ctx context.Context, salePointID string, filters product.CategoryFilters) ([]product.CategorySummary, int64, error) {
+	if m.GetCategoriesBySalePointIDFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetCategoriesBySalePointIDFunc(ctx, salePointID, filters)
+}
+
+func (m *ProductService) RenameCategory(ctx context.Context, input product.CategoryRenameInput) (*product.CategoryRenameResult, error) {
+	if m.RenameCategoryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.RenameCategoryFunc(ctx, input)
+}
+
+func (m *ProductService) GetTagsByCompanyID(ctx context.Context, companyID string, filters product.CategoryFilters) ([]product.TagSummary, int64, error) {
+	if m.GetTagsByCompanyIDFunc == nil {
+		return nil, 0, ErrNotMocked
+	}
+	return m.GetTagsByCompanyIDFunc(ctx, companyID, filters)
+}
+
+func (m *ProductService) Import(ctx context.Context, input product.ImportInput) (*product.ImportResult, error) {
+	if m.ImportFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ImportFunc(ctx, input)
+}
+
+func (m *ProductService) Export(ctx context.Context, input product.ExportInput, emit product.ExportEmitFunc) error {
+	if m.ExportFunc == nil {
+		return ErrNotMocked
+	}
+	return m.ExportFunc(ctx, input, emit)
+}
+
+// CompanyPageLimits returns util.DefaultPageLimits unless CompanyPageLimitsFunc is set
+func (m *ProductService) CompanyPageLimits() util.PageLimits {
+	if m.CompanyPageLimitsFunc == nil {
+		return util.DefaultPageLimits
+	}
+	return m.CompanyPageLimitsFunc()
+}
+
+// SalePointPageLimits returns util.DefaultPageLimits unless SalePointPageLimitsFunc is set
+func (m *ProductService) SalePointPageLimits() util.PageLimits {
+	if m.SalePointPageLimitsFunc == nil {
+		return util.DefaultPageLimits
+	}
+	return m.SalePointPageLimitsFunc()
+}
+
+// ImportLimits returns product.DefaultImportLimits unless ImportLimitsFunc is set
+func (m *ProductService) ImportLimits() product.ImportLimits {
+	if m.ImportLimitsFunc == nil {
+		return product.DefaultImportLimits
+	}
+	return m.ImportLimitsFunc()
+}
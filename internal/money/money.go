@@ -0,0 +1,64 @@
+// Package money formats the integer amounts stored by the API. Amounts are int64
+// counts of the currency's minor unit (cents for USD, whole pesos for COP).
+package money
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency and the number of digits of its minor unit
+type Currency struct {
+	Code       string `json:"code"`
+	MinorUnits int    `json:"minor_units"`
+}
+
+// currencies are the supported codes with their customary minor units.
+// COP is listed with 0 digits: centavos are not used in practice.
+var currencies = map[string]int{
+	"COP": 0,
+	"USD": 2,
+	"EUR": 2,
+	"MXN": 2,
+	"PEN": 2,
+	"CLP": 0,
+	"ARS": 2,
+	"BRL": 2,
+}
+
+// Default is the currency used when none is configured
+var Default = Currency{Code: "COP", MinorUnits: 0}
+
+// Lookup returns the currency for an ISO 4217 code (case-insensitive)
+func Lookup(code string) (Currency, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	digits, ok := currencies[code]
+	if !ok {
+		return Currency{}, false
+	}
+	return Currency{Code: code, MinorUnits: digits}, true
+}
+
+// Format renders an amount as a plain decimal, e.g. 123456 → "1234.56" in USD and "123456" in COP
+func (c Currency) Format(amount int64) string {
+	if c.MinorUnits <= 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if len(digits) <= c.MinorUnits {
+		digits = strings.Repeat("0", c.MinorUnits-len(digits)+1) + digits
+	}
+	split := len(digits) - c.MinorUnits
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// FormatWithCode renders an amount followed by the currency code, e.g. "1234.56 USD"
+func (c Currency) FormatWithCode(amount int64) string {
+	return c.Format(amount) + " " + c.Code
+}
@@ -0,0 +1,67 @@
+package money
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	cop := Currency{Code: "COP", MinorUnits: 0}
+	usd := Currency{Code: "USD", MinorUnits: 2}
+
+	tests := []struct {
+		name     string
+		currency Currency
+		amount   int64
+		want     string
+	}{
+		{"pesos", cop, 123456, "123456"},
+		{"zero pesos", cop, 0, "0"},
+		{"negative pesos", cop, -2500, "-2500"},
+		{"dollars", usd, 123456, "1234.56"},
+		{"whole dollars", usd, 1200, "12.00"},
+		{"cents only", usd, 5, "0.05"},
+		{"zero dollars", usd, 0, "0.00"},
+		{"negative cents", usd, -5, "-0.05"},
+		{"negative dollars", usd, -123456, "-1234.56"},
+		{"three digits", Currency{Code: "XXX", MinorUnits: 3}, 1005, "1.005"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.currency.Format(tt.amount); got != tt.want {
+				t.Errorf("Format(%d) = %q, want %q", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWithCode(t *testing.T) {
+	if got := (Currency{Code: "USD", MinorUnits: 2}).FormatWithCode(995); got != "9.95 USD" {
+		t.Errorf("FormatWithCode = %q, want %q", got, "9.95 USD")
+	}
+	if got := Default.FormatWithCode(18000); got != "18000 COP" {
+		t.Errorf("FormatWithCode = %q, want %q", got, "18000 COP")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code   string
+		want   Currency
+		wantOK bool
+	}{
+		{"COP", Currency{Code: "COP", MinorUnits: 0}, true},
+		{"usd", Currency{Code: "USD", MinorUnits: 2}, true},
+		{" eur ", Currency{Code: "EUR", MinorUnits: 2}, true},
+		{"CLP", Currency{Code: "CLP", MinorUnits: 0}, true},
+		{"XYZ", Currency{}, false},
+		{"", Currency{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, ok := Lookup(tt.code)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Lookup(%q) = %v, %v, want %v, %v", tt.code, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
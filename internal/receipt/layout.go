@@ -0,0 +1,119 @@
+package receipt
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Width is the line width of 80mm thermal printers in the default font
+const Width = 80
+
+// Rule returns a full-width separator line made of ch
+func Rule(ch string) string {
+	return strings.Repeat(ch, Width) + "\n"
+}
+
+// Center centers a heading on one line, truncating it to the width
+func Center(text string) string {
+	text = truncate(text, Width)
+	padding := (Width - utf8.RuneCountInString(text)) / 2
+	return strings.Repeat(" ", padding) + text + "\n"
+}
+
+// Columns left-aligns the label and right-aligns the value on one line,
+// truncating the label so the line never exceeds the width
+func Columns(label, value string) string {
+	room := Width - utf8.RuneCountInString(value) - 1
+	label = truncate(label, room)
+	padding := Width - utf8.RuneCountInString(label) - utf8.RuneCountInString(value)
+	return label + strings.Repeat(" ", max(padding, 1)) + value + "\n"
+}
+
+// Wrap breaks text into lines that fit the width, prefixing every line with indent.
+// Line breaks in the text are kept and words longer than a line are split.
+func Wrap(text, indent string) string {
+	return wrap(text, indent, indent)
+}
+
+// Hanging wraps text like Wrap but leaves the first line unindented, e.g. "2 x Name" with the name continued below
+func Hanging(text, indent string) string {
+	return wrap(text, "", indent)
+}
+
+// wrap breaks text into lines, prefixing the first one with first and the others with rest
+func wrap(text, first, rest string) string {
+	room := max(Width-max(utf8.RuneCountInString(first), utf8.RuneCountInString(rest)), 1)
+	prefix := first
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		for _, line := range wrapParagraph(paragraph, room) {
+			b.WriteString(prefix)
+			b.WriteString(line)
+			b.WriteString("\n")
+			prefix = rest
+		}
+	}
+	return b.String()
+}
+
+// WrapColumns wraps the label into the space left by the value, which goes on the first line
+func WrapColumns(label, indent, value string) string {
+	room := Width - utf8.RuneCountInString(value) - 1
+	lines := wrapParagraph(label, room)
+	var b strings.Builder
+	for i, line := range lines {
+		if i == 0 {
+			b.WriteString(Columns(line, value))
+			continue
+		}
+		b.WriteString(Wrap(line, indent))
+	}
+	return b.String()
+}
+
+// wrapParagraph greedily fills lines of at most room runes; an empty paragraph is one empty line
+func wrapParagraph(paragraph string, room int) []string {
+	words := strings.Fields(paragraph)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	var line []rune
+	for _, word := range words {
+		w := []rune(word)
+		// Split words that can never fit
+		for len(w) > room {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(w[:room]))
+			w = w[room:]
+		}
+		switch {
+		case len(line) == 0:
+			line = w
+		case len(line)+1+len(w) <= room:
+			line = append(append(line, ' '), w...)
+		default:
+			lines = append(lines, string(line))
+			line = w
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// truncate shortens text to at most n runes, marking the cut with an ellipsis
+func truncate(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	if n <= 1 {
+		return string([]rune(text)[:max(n, 0)])
+	}
+	return string([]rune(text)[:n-1]) + "…"
+}
@@ -0,0 +1,111 @@
+package receipt
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCenter(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"even padding", "RECEIPT", strings.Repeat(" ", 36) + "RECEIPT\n"},
+		{"full width", strings.Repeat("x", Width), strings.Repeat("x", Width) + "\n"},
+		{"truncated", strings.Repeat("x", Width+5), strings.Repeat("x", Width-1) + "…\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Center(tt.text); got != tt.want {
+				t.Errorf("Center = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		name         string
+		label, value string
+		want         string
+	}{
+		{"padded", "TOTAL", "10.00", "TOTAL" + strings.Repeat(" ", Width-10) + "10.00\n"},
+		{"multibyte label", "Piña colada", "5", "Piña colada" + strings.Repeat(" ", Width-12) + "5\n"},
+		{"long label truncated", strings.Repeat("a", Width), "9.99", strings.Repeat("a", Width-6) + "… 9.99\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Columns(tt.label, tt.value)
+			if got != tt.want {
+				t.Errorf("Columns = %q, want %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(strings.TrimSuffix(got, "\n")); n != Width {
+				t.Errorf("line is %d columns wide, want %d", n, Width)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	long := strings.Repeat("word ", 20)
+	tests := []struct {
+		name   string
+		text   string
+		indent string
+		hang   bool
+		want   []string
+	}{
+		{"fits", "no onions", "  ", false, []string{"  no onions"}},
+		{"words move to the next line", long, "", false, []string{strings.TrimSpace(strings.Repeat("word ", 16)), strings.TrimSpace(strings.Repeat("word ", 4))}},
+		{"indent counts toward the width", long, "      ", false, []string{"      " + strings.TrimSpace(strings.Repeat("word ", 15)), "      " + strings.TrimSpace(strings.Repeat("word ", 5))}},
+		{"hanging indent", long, "    ", true, []string{strings.TrimSpace(strings.Repeat("word ", 15)), "    " + strings.TrimSpace(strings.Repeat("word ", 5))}},
+		{"line breaks kept", "ring twice\r\nleave at door", "- ", false, []string{"- ring twice", "- leave at door"}},
+		{"blank line kept", "a\n\nb", "", false, []string{"a", "", "b"}},
+		{"word longer than a line split", strings.Repeat("x", Width+10), "", false, []string{strings.Repeat("x", Width), strings.Repeat("x", 10)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Wrap(tt.text, tt.indent)
+			if tt.hang {
+				got = Hanging(tt.text, tt.indent)
+			}
+			want := strings.Join(tt.want, "\n") + "\n"
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+			assertWidth(t, got)
+		})
+	}
+}
+
+func TestWrapColumns(t *testing.T) {
+	label := "2 x " + strings.Repeat("Hamburguesa ", 8)
+	got := WrapColumns(label, "      ", "1,234.50")
+
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), got)
+	}
+	if !strings.HasSuffix(lines[0], " 1,234.50") || strings.Contains(lines[1], "1,234.50") {
+		t.Errorf("value not on the first line only:\n%s", got)
+	}
+	if !strings.HasPrefix(lines[1], "      Hamburguesa") {
+		t.Errorf("continuation not indented: %q", lines[1])
+	}
+	assertWidth(t, got)
+}
+
+// assertWidth fails when a line of text is wider than the printer
+func assertWidth(t *testing.T, text string) {
+	t.Helper()
+	for i, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if n := utf8.RuneCountInString(line); n > Width {
+			t.Errorf("line %d is %d columns wide: %q", i+1, n, line)
+		}
+	}
+}
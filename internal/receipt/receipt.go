@@ -0,0 +1,173 @@
+// Package receipt renders orders as fixed-width plain text for 80-column thermal printers
+package receipt
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/money"
+)
+
+// Variant selects the receipt layout
+type Variant string
+
+const (
+	// VariantCustomer has prices, the total and the tracking link
+	VariantCustomer Variant = "customer"
+	// VariantKitchen has quantities, observations, the table and the note, without prices
+	VariantKitchen Variant = "kitchen"
+)
+
+// IsValidVariant checks if the variant is supported
+func IsValidVariant(v Variant) bool {
+	return v == VariantCustomer || v == VariantKitchen
+}
+
+// Options holds what a receipt needs beyond the order itself
+type Options struct {
+	Currency    money.Currency
+	Location    *time.Location // Zone the order time is printed in; nil prints UTC
+	TrackingURL string         // Printed on customer receipts when set
+}
+
+// itemIndent indents continuation lines, observations and unit prices under an item
+const itemIndent = "      "
+
+// Render writes the order in the given variant
+func Render(w io.Writer, o *order.Order, variant Variant, opts Options) error {
+	var b strings.Builder
+	switch variant {
+	case VariantKitchen:
+		renderKitchen(&b, o, opts)
+	case VariantCustomer:
+		renderCustomer(&b, o, opts)
+	default:
+		return fmt.Errorf("unknown receipt variant %q", variant)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderKitchen writes the kitchen ticket: what to prepare and where it goes, without prices
+func renderKitchen(b *strings.Builder, o *order.Order, opts Options) {
+	b.WriteString(Rule("="))
+	b.WriteString(Center("KITCHEN - " + saleTypeLabel(o.SaleType)))
+	b.WriteString(Rule("="))
+	renderHeader(b, o, opts)
+	if o.TableNumber != nil {
+		b.WriteString(Columns("TABLE", strconv.Itoa(*o.TableNumber)))
+	}
+	b.WriteString(Rule("-"))
+
+	for _, p := range o.Products {
+		b.WriteString(Hanging(fmt.Sprintf("%d x %s", p.Quantity, lineName(p)), itemIndent))
+		renderAddons(b, p)
+		for _, observation := range p.SelectedObservations {
+			b.WriteString(Wrap("* "+observation, itemIndent))
+		}
+		if p.Observation != nil && strings.TrimSpace(*p.Observation) != "" {
+			b.WriteString(Wrap("> "+*p.Observation, itemIndent))
+		}
+	}
+
+	if notes := o.NoteList(); len(notes) > 0 {
+		b.WriteString(Rule("-"))
+		b.WriteString("NOTES\n")
+		for _, note := range notes {
+			b.WriteString(Wrap("- "+note.Text, "  "))
+		}
+	}
+	b.WriteString(Rule("="))
+}
+
+// renderCustomer writes the customer receipt: lines with prices, the total and the tracking link
+func renderCustomer(b *strings.Builder, o *order.Order, opts Options) {
+	b.WriteString(Rule("="))
+	b.WriteString(Center("RECEIPT"))
+	b.WriteString(Rule("="))
+	renderHeader(b, o, opts)
+	b.WriteString(Columns("Type", saleTypeLabel(o.SaleType)))
+	if o.TableNumber != nil {
+		b.WriteString(Columns("Table", strconv.Itoa(*o.TableNumber)))
+	}
+	if o.Customer != nil && o.Customer.Name != "" {
+		b.WriteString(Hanging("Customer: "+o.Customer.Name, "  "))
+	}
+	if o.ShippingAddress != nil && *o.ShippingAddress != "" {
+		b.WriteString(Hanging("Address: "+*o.ShippingAddress, "  "))
+	}
+	b.WriteString(Rule("-"))
+
+	for _, p := range o.Products {
+		b.WriteString(WrapColumns(fmt.Sprintf("%d x %s", p.Quantity, lineName(p)), itemIndent, opts.Currency.Format(p.LineTotal())))
+		renderAddons(b, p)
+		if p.Quantity > 1 {
+			b.WriteString(itemIndent + "@ " + opts.Currency.Format(p.UnitPrice()) + "\n")
+		}
+		for _, observation := range p.SelectedObservations {
+			b.WriteString(Wrap("* "+observation, itemIndent))
+		}
+	}
+
+	b.WriteString(Rule("-"))
+	if o.DiscountAmount > 0 {
+		label := "DISCOUNT"
+		if o.Discount != nil && o.Discount.Description != "" {
+			label += " (" + o.Discount.Description + ")"
+		}
+		b.WriteString(Columns("SUBTOTAL", opts.Currency.Format(o.Total+o.DiscountAmount)))
+		b.WriteString(WrapColumns(label, "", "-"+opts.Currency.Format(o.DiscountAmount)))
+	}
+	b.WriteString(Columns("TOTAL", opts.Currency.FormatWithCode(o.Total)))
+	b.WriteString(Rule("="))
+
+	if opts.TrackingURL != "" {
+		b.WriteString(Center("Track your order"))
+		b.WriteString(Wrap(opts.TrackingURL, ""))
+		b.WriteString(Rule("="))
+	}
+}
+
+// lineName returns the product name of a line followed by its price variation, e.g. "Limonada (Grande)"
+func lineName(p order.OrderProduct) string {
+	if p.VariationType == nil || *p.VariationType == "" {
+		return p.Name
+	}
+	return p.Name + " (" + *p.VariationType + ")"
+}
+
+// renderAddons writes the addons of a line, with their quantity per unit when more than one
+func renderAddons(b *strings.Builder, p order.OrderProduct) {
+	for _, a := range p.Addons {
+		label := a.Name
+		if a.Quantity > 1 {
+			label = fmt.Sprintf("%d x %s", a.Quantity, a.Name)
+		}
+		b.WriteString(Wrap("+ "+label, itemIndent))
+	}
+}
+
+// renderHeader writes the order code and creation time shared by both variants
+func renderHeader(b *strings.Builder, o *order.Order, opts Options) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	b.WriteString(Columns("Order "+o.Code, o.CreatedAt.In(loc).Format("2006-01-02 15:04")))
+}
+
+// saleTypeLabel returns the printed name of a sale type
+func saleTypeLabel(t order.SaleType) string {
+	switch t {
+	case order.SaleTypeDelivery:
+		return "DELIVERY"
+	case order.SaleTypeOnSite:
+		return "ON SITE"
+	default:
+		return string(t)
+	}
+}
@@ -0,0 +1,141 @@
+package receipt
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/money"
+)
+
+// update rewrites the golden files in testdata with the current output
+var update = flag.Bool("update", false, "update golden files")
+
+// assertGolden compares got with testdata/<name>, rewriting the file instead when -update is set
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := "testdata/" + name
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run go test -update to create it): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("output differs from %s (run go test -update after checking the change)\n got: %s\nwant: %s", path, got, want)
+	}
+}
+
+// busyOrder is an on-site order with the content that stresses the layout:
+// long names, many addons, observations and multi-line notes
+func busyOrder() *order.Order {
+	table := 12
+	observation := "Bien cocida, sin sal en las papas y con la salsa aparte porque el cliente es alérgico al ajo"
+	o := &order.Order{
+		Code:        "ORD-7KQ2M9",
+		Status:      order.StatusInProgress,
+		SaleType:    order.SaleTypeOnSite,
+		TableNumber: &table,
+		Products: []order.OrderProduct{
+			{
+				ID: "p1", Name: "Hamburguesa doble de la casa con tocineta ahumada, queso cheddar añejo y cebolla caramelizada",
+				Price: 2850000, Quantity: 2, Observation: &observation,
+				SelectedObservations: []string{"Sin pepinillos", "Pan sin gluten"},
+				Addons: []order.OrderProductAddon{
+					{ID: "a1", Name: "Queso extra", Price: 300000, Quantity: 2},
+					{ID: "a2", Name: "Tocineta", Price: 450000, Quantity: 1},
+					{ID: "a3", Name: "Huevo frito", Price: 250000, Quantity: 1},
+					{ID: "a4", Name: "Aguacate", Price: 350000, Quantity: 1},
+					{ID: "a5", Name: "Jalapeños encurtidos de la casa con un nombre larguísimo para probar el ajuste", Price: 150000, Quantity: 3},
+				},
+			},
+			{ID: "p2", Name: "Limonada de coco", Price: 990000, Quantity: 1},
+			{ID: "p3", Name: "Agua", Price: 400000, Quantity: 3},
+		},
+		Discount: &order.Discount{Type: order.DiscountPercent, Value: 10, Description: "Cliente frecuente"},
+		Notes: []order.OrderNote{
+			{Text: "Celebran cumpleaños:\ntraer la torta con el postre\ny apagar las luces", Author: "mesero", Visibility: order.NotePublic},
+			{Text: "Pagan por separado", Author: "caja", Visibility: order.NoteInternal},
+		},
+		CreatedAt: time.Date(2024, 6, 1, 1, 30, 0, 0, time.UTC),
+	}
+	o.CalculateTotal()
+	return o
+}
+
+// deliveryOrder is a plain delivery order with a customer and an address
+func deliveryOrder() *order.Order {
+	address := "Carrera 7 # 71-21, Torre B, Apartamento 1204, Edificio Los Almendros, Bogotá, Colombia"
+	o := &order.Order{
+		Code:            "ORD-1717205400-a1b2c3d4",
+		Status:          order.StatusCreated,
+		SaleType:        order.SaleTypeDelivery,
+		Customer:        &order.Customer{Name: "María Fernanda Rodríguez"},
+		ShippingAddress: &address,
+		Products:        []order.OrderProduct{{ID: "p1", Name: "Pizza margarita", Price: 3200000, Quantity: 1}},
+		CreatedAt:       time.Date(2024, 6, 1, 18, 5, 0, 0, time.UTC),
+	}
+	o.CalculateTotal()
+	return o
+}
+
+func TestRenderGolden(t *testing.T) {
+	bogota, err := time.LoadLocation("America/Bogota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cop := money.Currency{Code: "COP", MinorUnits: 2}
+
+	tests := []struct {
+		name    string
+		order   *order.Order
+		variant Variant
+		opts    Options
+		golden  string
+	}{
+		{"kitchen ticket", busyOrder(), VariantKitchen, Options{Currency: cop, Location: bogota}, "kitchen_busy.golden"},
+		{"customer receipt", busyOrder(), VariantCustomer, Options{Currency: cop, Location: bogota, TrackingURL: "https://track.example.com/ORD-7KQ2M9"}, "customer_busy.golden"},
+		{"delivery kitchen ticket", deliveryOrder(), VariantKitchen, Options{Currency: cop}, "kitchen_delivery.golden"},
+		{"delivery customer receipt", deliveryOrder(), VariantCustomer, Options{Currency: cop}, "customer_delivery.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := Render(&b, tt.order, tt.variant, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			got := b.String()
+			assertWidth(t, got)
+			assertGolden(t, tt.golden, []byte(got))
+		})
+	}
+}
+
+func TestKitchenTicketHasNoPrices(t *testing.T) {
+	var b strings.Builder
+	if err := Render(&b, busyOrder(), VariantKitchen, Options{Currency: money.Currency{Code: "COP", MinorUnits: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, forbidden := range []string{"TOTAL", "28,500", "28500", "COP", "DISCOUNT"} {
+		if strings.Contains(b.String(), forbidden) {
+			t.Errorf("kitchen ticket shows %q", forbidden)
+		}
+	}
+}
+
+func TestRenderUnknownVariant(t *testing.T) {
+	var b strings.Builder
+	if err := Render(&b, deliveryOrder(), Variant("bar"), Options{}); err == nil {
+		t.Error("unknown variant rendered")
+	}
+	if IsValidVariant("bar") || !IsValidVariant(VariantKitchen) || !IsValidVariant(VariantCustomer) {
+		t.Error("IsValidVariant disagrees with the supported variants")
+	}
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/category"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CategoriesCollection is the collection of category entities, which product menus look up
+const CategoriesCollection = "categories"
+
+// categoryNameIndex keeps category names unique within a sale point
+const categoryNameIndex = "sale_point_id_name_unique"
+
+type categoryMongoRepository struct {
+	collection *mongo.Collection
+	reads      readers
+}
+
+// NewCategoryMongoRepository creates a new category repository
+func NewCategoryMongoRepository(collection *mongo.Collection) category.Repository {
+	return &categoryMongoRepository{collection: collection, reads: newReaders(collection)}
+}
+
+// CreateIndexes creates the unique name index, which also serves sale point listings
+func (r *categoryMongoRepository) CreateIndexes(ctx context.Context) error {
+	if _, err := r.collection.Indexes().CreateOne(ctx, categoryNameIndexModel()); err != nil {
+		return fmt.Errorf("failed to create category indexes: %w", err)
+	}
+	return nil
+}
+
+// categoryNameIndexModel returns the unique name index. Its case-insensitive collation makes
+// "Drinks" and "drinks" the same name.
+func categoryNameIndexModel() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "sale_point_id", Value: 1},
+			{Key: "name", Value: 1},
+		},
+		Options: options.Index().
+			SetName(categoryNameIndex).
+			SetUnique(true).
+			SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+	}
+}
+
+// isDuplicateCategory reports whether a write failed on the unique name index
+func isDuplicateCategory(err error) bool {
+	return mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), categoryNameIndex)
+}
+
+// categorySort lists categories in display order; the name breaks ties
+func categorySort() bson.D {
+	return bson.D{
+		{Key: "position", Value: 1},
+		{Key: "name", Value: 1},
+	}
+}
+
+// Create creates a new category
+func (r *categoryMongoRepository) Create(ctx context.Context, c *category.Category) error {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.InsertOne(ctx, c); err != nil {
+		if isDuplicateCategory(err) {
+			return category.ErrDuplicateCategory
+		}
+		return wrapError(ctx, "failed to insert category", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a category by ID
+func (r *categoryMongoRepository) FindByID(ctx context.Context, id string) (*category.Category, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var c category.Category
+	if err := r.reads.forRead(ctx).FindOne(ctx, bson.M{"_id": id}).Decode(&c); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, category.ErrCategoryNotFound
+		}
+		return nil, wrapError(ctx, "failed to find category", err)
+	}
+
+	return &c, nil
+}
+
+// FindBySalePointID retrieves the categories of a sale point in display order
+func (r *categoryMongoRepository) FindBySalePointID(ctx context.Context, salePointID string) ([]*category.Category, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.reads.forRead(ctx).Find(ctx, bson.M{"sale_point_id": salePointID}, options.Find().SetSort(categorySort()))
+	if err != nil {
+		return nil, wrapError(ctx, "failed to find categories", err)
+	}
+	defer cursor.Close(ctx)
+
+	categories := []*category.Category{}
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, wrapError(ctx, "failed to decode categories", err)
+	}
+
+	return categories, nil
+}
+
+// FindActiveNames retrieves the names of the active categories of a sale point
+func (r *categoryMongoRepository) FindActiveNames(ctx context.Context, salePointID string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"sale_point_id": salePointID, "is_active": true}
+	cursor, err := r.reads.forRead(ctx).Find(ctx, filter, options.Find().SetProjection(bson.M{"name": 1}))
+	if err != nil {
+		return nil, wrapError(ctx, "failed to find category names", err)
+	}
+	defer cursor.Close(ctx)
+
+	var categories []category.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, wrapError(ctx, "failed to decode category names", err)
+	}
+
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = c.Name
+	}
+	return names, nil
+}
+
+// Update updates an existing category
+func (r *categoryMongoRepository) Update(ctx context.Context, c *category.Category) error {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	c.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": c.ID}, bson.M{"$set": c})
+	if err != nil {
+		if isDuplicateCategory(err) {
+			return category.ErrDuplicateCategory
+		}
+		return wrapError(ctx, "failed to update category", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return category.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a category by ID
+func (r *categoryMongoRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return wrapError(ctx, "failed to delete category", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return category.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// productCategoryNames exposes the categories of the products collection to the category domain
+type productCategoryNames struct {
+	collection *mongo.Collection
+	reads      readers
+}
+
+// NewProductCategoryNames creates the fallback listing used by sale points without categories
+func NewProductCategoryNames(collection *mongo.Collection) category.ProductCategories {
+	return &productCategoryNames{collection: collection, reads: newReaders(collection)}
+}
+
+// FindProductCategoryNames retrieves the distinct categories of a sale point's live products, sorted by name
+func (p *productCategoryNames) FindProductCategoryNames(ctx context.Context, salePointID string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	values, err := p.reads.forRead(ctx).Distinct(ctx, "category", bson.M{"sale_point_id": salePointID, "deleted_at": liveProduct})
+	if err != nil {
+		return nil, wrapError(ctx, "failed to find product categories", err)
+	}
+
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		if name, ok := v.(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestCategoryNameIndexModel(t *testing.T) {
+	model := categoryNameIndexModel()
+
+	wantKeys := bson.D{{Key: "sale_point_id", Value: 1}, {Key: "name", Value: 1}}
+	if !reflect.DeepEqual(model.Keys, wantKeys) {
+		t.Errorf("keys = %v, want %v", model.Keys, wantKeys)
+	}
+	if model.Options.Unique == nil || !*model.Options.Unique {
+		t.Error("index is not unique")
+	}
+
+	// Strength 2 compares letters ignoring case, so "Drinks" and "drinks" collide
+	if c := model.Options.Collation; c == nil || c.Strength != 2 {
+		t.Errorf("collation = %+v, want strength 2", c)
+	}
+}
+
+func TestIsDuplicateCategory(t *testing.T) {
+	duplicate := func(message string) error {
+		return mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: message}}}
+	}
+
+	if !isDuplicateCategory(duplicate("E11000 duplicate key error collection: db.categories index: sale_point_id_name_unique dup key")) {
+		t.Error("name index duplicate not detected")
+	}
+	if isDuplicateCategory(duplicate("E11000 duplicate key error collection: db.categories index: _id_ dup key")) {
+		t.Error("id index duplicate reported as a duplicate name")
+	}
+}
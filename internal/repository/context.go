@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/util"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// withTimeout derives the operation context from the caller's context.
+// The timeout can only shorten an existing deadline, never extend it, and the
+// caller's cancellation (e.g. a client disconnect) still propagates.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// readers pairs a collection with a copy that always reads from the primary
+type readers struct {
+	collection *mongo.Collection
+	primary    *mongo.Collection
+}
+
+// newReaders prepares the primary copy of a collection once, so per-call selection is free
+func newReaders(collection *mongo.Collection) readers {
+	primary, err := collection.Clone(options.Collection().SetReadPreference(readpref.Primary()))
+	if err != nil {
+		primary = collection
+	}
+	return readers{collection: collection, primary: primary}
+}
+
+// forRead returns the collection to read from: the primary when the context asks for read-your-writes
+// consistency, otherwise the collection with the client's configured read preference
+func (r readers) forRead(ctx context.Context) *mongo.Collection {
+	if util.IsReadPreferencePrimary(ctx) {
+		return r.primary
+	}
+	return r.collection
+}
+
+// checkBatch returns the context error once the cursor has consumed its current batch,
+// so long scans stop between batches instead of fetching the next one
+func checkBatch(ctx context.Context, cursor *mongo.Cursor) error {
+	if cursor.RemainingBatchLength() == 0 {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// wrapError wraps a driver error, preferring the context error when the context is done
+// so callers can match context.Canceled and context.DeadlineExceeded with errors.Is
+func wrapError(ctx context.Context, msg string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%s: %w", msg, ctxErr)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
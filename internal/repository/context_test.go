@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/util"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func TestWithTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		callerBudget time.Duration // 0 for a caller without deadline
+		timeout      time.Duration
+		wantBudget   time.Duration
+	}{
+		{"no caller deadline uses the timeout", 0, 5 * time.Second, 5 * time.Second},
+		{"shorter caller deadline is kept", time.Second, 5 * time.Second, time.Second},
+		{"longer caller deadline is shortened", time.Minute, 5 * time.Second, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := context.Background()
+			if tt.callerBudget > 0 {
+				var cancel context.CancelFunc
+				parent, cancel = context.WithTimeout(parent, tt.callerBudget)
+				defer cancel()
+			}
+
+			ctx, cancel := withTimeout(parent, tt.timeout)
+			defer cancel()
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatal("operation context has no deadline")
+			}
+			if budget := time.Until(deadline); budget > tt.wantBudget || budget < tt.wantBudget-time.Second {
+				t.Errorf("budget = %v, want about %v", budget, tt.wantBudget)
+			}
+		})
+	}
+}
+
+func TestWithTimeoutPropagatesCancellation(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, release := withTimeout(parent, time.Minute)
+	defer release()
+
+	cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("caller cancellation did not reach the operation context")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", ctx.Err())
+	}
+}
+
+// unreachableDatabase returns a database whose server never answers, so every operation
+// waits in server selection until its context ends
+func unreachableDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://127.0.0.1:1").
+		SetServerSelectionTimeout(30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+	return client.Database("products_api_test")
+}
+
+// TestRepositoriesReturnPromptlyOnCancel cancels the caller's context while each repository
+// call waits for the database and checks it gives up with context.Canceled
+func TestRepositoriesReturnPromptlyOnCancel(t *testing.T) {
+	db := unreachableDatabase(t)
+	orders := NewOrderMongoRepository(db.Collection("orders"), db.Collection("orders_archive"))
+	products := NewProductMongoRepository(db.Collection("products"))
+
+	calls := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"order FindByID", func(ctx context.Context) error { _, err := orders.FindByID(ctx, "id"); return err }},
+		{"order Count", func(ctx context.Context) error { _, err := orders.Count(ctx, order.OrderFilters{}); return err }},
+		{"order GetMetrics", func(ctx context.Context) error { _, err := orders.GetMetrics(ctx, order.OrderFilters{}); return err }},
+		{"product FindByID", func(ctx context.Context) error { _, err := products.FindByID(ctx, "id"); return err }},
+		{"product CountByCompanyID", func(ctx context.Context) error {
+			_, err := products.CountByCompanyID(ctx, "company", product.ProductFilters{})
+			return err
+		}},
+	}
+
+	for _, tc := range calls {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := tc.call(ctx)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("call took %v after the cancellation", elapsed)
+			}
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("err = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
+
+func TestForReadHonorsReadAfterWrite(t *testing.T) {
+	db := unreachableDatabase(t)
+	collection := db.Collection("orders", options.Collection().SetReadPreference(readpref.SecondaryPreferred()))
+	r := newReaders(collection)
+	if r.primary == collection {
+		t.Fatal("no primary copy of the collection was prepared")
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *mongo.Collection
+	}{
+		{"configured preference", context.Background(), collection},
+		{"read after write", util.WithReadPreferencePrimary(context.Background()), r.primary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.forRead(tt.ctx); got != tt.want {
+				t.Errorf("forRead picked the wrong collection copy")
+			}
+		})
+	}
+}
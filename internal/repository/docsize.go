@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DocumentSize returns the size in bytes of the document as stored by MongoDB
+func DocumentSize(doc interface{}) (int, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode document: %w", err)
+	}
+	return len(raw), nil
+}
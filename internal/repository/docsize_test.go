@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDocumentSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     interface{}
+		want    int
+		wantErr bool
+	}{
+		// int32 (4) + "a\x00" (2) + type byte (1) + length (4) + terminator (1)
+		{"single field", bson.M{"a": int32(1)}, 12, false},
+		{"empty document", bson.M{}, 5, false},
+		{"not a document", "text", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DocumentSize(tt.doc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("size = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentSizeGrowsWithOrderLines(t *testing.T) {
+	observation := strings.Repeat("x", 1000)
+	line := order.OrderProduct{ID: "p1", Name: "Burger", Price: 1000, Quantity: 1, Observation: &observation}
+	small := order.NewOrder(order.SaleTypeOnSite, []order.OrderProduct{line})
+	large := order.NewOrder(order.SaleTypeOnSite, []order.OrderProduct{line, line, line})
+
+	smallSize, err := DocumentSize(small)
+	if err != nil {
+		t.Fatal(err)
+	}
+	largeSize, err := DocumentSize(large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if largeSize-smallSize < 2000 {
+		t.Errorf("two more 1KB lines grew the document by %d bytes", largeSize-smallSize)
+	}
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// These tests pin the filter documents the repositories sent before the shared query
+// builder existed, so listings, counts and aggregations keep matching the same orders.
+func TestOrderFilter(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filters order.OrderFilters
+		want    bson.M
+	}{
+		{"no filters", order.OrderFilters{}, bson.M{}},
+		{
+			name:    "single status",
+			filters: order.OrderFilters{Status: ptr(order.StatusCreated)},
+			want:    bson.M{"status": order.StatusCreated},
+		},
+		{
+			name:    "status combined with statuses",
+			filters: order.OrderFilters{Status: ptr(order.StatusCreated), Statuses: []order.OrderStatus{order.StatusCancelled}},
+			want:    bson.M{"status": bson.M{"$in": []order.OrderStatus{order.StatusCreated, order.StatusCancelled}}},
+		},
+		{
+			name:    "sale type, product and total range",
+			filters: order.OrderFilters{SaleType: ptr(order.SaleTypeDelivery), ProductID: ptr("p1"), MinTotal: ptr(int64(1000)), MaxTotal: ptr(int64(5000))},
+			want: bson.M{
+				"sale_type":   order.SaleTypeDelivery,
+				"products.id": "p1",
+				"total":       bson.M{"$gte": int64(1000), "$lte": int64(5000)},
+			},
+		},
+		{
+			name:    "product name",
+			filters: order.OrderFilters{ProductName: ptr("taco")},
+			want:    bson.M{"products.name": bson.M{"$regex": "taco", "$options": "i"}},
+		},
+		{
+			name:    "date range",
+			filters: order.OrderFilters{DateFrom: ptr(from.Format(time.RFC3339)), DateTo: ptr(to.Format(time.RFC3339))},
+			want:    bson.M{"created_at": bson.M{"$gte": from, "$lte": to}},
+		},
+		{
+			name:    "unparseable dates are ignored",
+			filters: order.OrderFilters{DateFrom: ptr("yesterday")},
+			want:    bson.M{},
+		},
+		{
+			name:    "exclude cancelled leaves the status free",
+			filters: order.OrderFilters{Status: ptr(order.StatusCreated), ExcludeCancelled: true},
+			want: bson.M{
+				"status": order.StatusCreated,
+				"$nor":   []bson.M{{"status": order.StatusCancelled}},
+			},
+		},
+		{
+			name:    "channel other includes orders stored without one",
+			filters: order.OrderFilters{Channel: ptr(order.ChannelOther)},
+			want:    bson.M{"channel": bson.M{"$in": []interface{}{order.ChannelOther, nil}}},
+		},
+		{
+			name:    "short search falls back to $regex",
+			filters: order.OrderFilters{Search: ptr("12")},
+			want: bson.M{"$and": []bson.M{{"$or": []bson.M{
+				{"customer.name": bson.M{"$regex": "12", "$options": "i"}},
+				{"customer.phone": bson.M{"$regex": "12", "$options": "i"}},
+				{"customer.phone_normalized": bson.M{"$regex": "12", "$options": "i"}},
+				{"shipping_address": bson.M{"$regex": "12", "$options": "i"}},
+				{"note": bson.M{"$regex": "12", "$options": "i"}},
+				{"notes.text": bson.M{"$regex": "12", "$options": "i"}},
+				{"products.name": bson.M{"$regex": "12", "$options": "i"}},
+			}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := orderFilter(tt.filters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters order.OrderFilters
+		want    bson.D
+	}{
+		{"default newest first", order.OrderFilters{}, bson.D{{Key: "created_at", Value: -1}}},
+		{"by total ascending", order.OrderFilters{SortBy: order.SortFieldTotal, SortDir: order.SortAsc},
+			bson.D{{Key: "total", Value: 1}, {Key: "_id", Value: 1}}},
+		{"by total descending", order.OrderFilters{SortBy: order.SortFieldTotal, SortDir: order.SortDesc},
+			bson.D{{Key: "total", Value: -1}, {Key: "_id", Value: -1}}},
+		{"field only is descending", order.OrderFilters{SortBy: order.SortFieldStatus},
+			bson.D{{Key: "status", Value: -1}, {Key: "_id", Value: -1}}},
+		{"by last update ascending", order.OrderFilters{SortBy: order.SortFieldUpdatedAt, SortDir: order.SortAsc},
+			bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{"direction only", order.OrderFilters{SortDir: order.SortAsc},
+			bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := orderSort(tt.filters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sort = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductFilter(t *testing.T) {
+	scope := bson.M{"company_id": "c1"}
+
+	tests := []struct {
+		name    string
+		filters product.ProductFilters
+		want    bson.M
+	}{
+		{"scope only", product.ProductFilters{}, bson.M{"company_id": "c1", "deleted_at": liveProduct}},
+		{
+			name:    "categories and flags",
+			filters: product.ProductFilters{Category: ptr("Tacos"), Categories: []string{"Drinks"}, IsAvailable: ptr(true), IsAddon: ptr(false)},
+			want: bson.M{
+				"company_id":   "c1",
+				"category":     bson.M{"$in": []string{"Tacos", "Drinks"}},
+				"is_available": true,
+				"is_addon":     false,
+				"deleted_at":   liveProduct,
+			},
+		},
+		{
+			name:    "one sale point of the company",
+			filters: product.ProductFilters{SalePointID: ptr("sp1")},
+			want:    bson.M{"company_id": "c1", "sale_point_id": "sp1", "deleted_at": liveProduct},
+		},
+		{
+			name:    "price range within one variation",
+			filters: product.ProductFilters{MinPrice: ptr(int64(500)), MaxPrice: ptr(int64(900))},
+			want: bson.M{"company_id": "c1", "deleted_at": liveProduct, "price_variations": bson.M{"$elemMatch": bson.M{
+				"price": bson.M{"$gte": int64(500), "$lte": int64(900)},
+			}}},
+		},
+		{
+			name:    "min price only",
+			filters: product.ProductFilters{MinPrice: ptr(int64(500))},
+			want: bson.M{"company_id": "c1", "deleted_at": liveProduct, "price_variations": bson.M{"$elemMatch": bson.M{
+				"price": bson.M{"$gte": int64(500)},
+			}}},
+		},
+		{
+			name:    "price range with category and availability",
+			filters: product.ProductFilters{Category: ptr("Pizzas"), IsAvailable: ptr(true), MaxPrice: ptr(int64(900))},
+			want: bson.M{
+				"company_id":   "c1",
+				"category":     "Pizzas",
+				"is_available": true,
+				"deleted_at":   liveProduct,
+				"price_variations": bson.M{"$elemMatch": bson.M{
+					"price": bson.M{"$lte": int64(900)},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := productFilter(scope, tt.filters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %#v, want %#v", got, tt.want)
+			}
+			if len(scope) != 1 {
+				t.Fatalf("scope was modified: %v", scope)
+			}
+		})
+	}
+}
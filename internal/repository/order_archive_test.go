@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestArchiveWrites(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	orders := []*order.Order{
+		{ID: "o1", Status: order.StatusDelivered},
+		{ID: "o2", Status: order.StatusCancelled},
+	}
+	writes := archiveWrites(orders, now)
+
+	if len(writes) != 2 {
+		t.Fatalf("got %d writes, want 2", len(writes))
+	}
+	for i, o := range orders {
+		model, ok := writes[i].(*mongo.ReplaceOneModel)
+		if !ok {
+			t.Fatalf("write %d is %T, want a replace", i, writes[i])
+		}
+		if !reflect.DeepEqual(model.Filter, bson.M{"_id": o.ID}) {
+			t.Errorf("filter = %v, want the order %s", model.Filter, o.ID)
+		}
+		// A retry must overwrite the copy of an earlier failed run, so the write upserts
+		if model.Upsert == nil || !*model.Upsert {
+			t.Errorf("write %d does not upsert", i)
+		}
+		archived, ok := model.Replacement.(*order.Order)
+		if !ok || archived.ID != o.ID || archived.ArchivedAt == nil || !archived.ArchivedAt.Equal(now) {
+			t.Errorf("replacement = %+v, want %s archived at %v", model.Replacement, o.ID, now)
+		}
+		if o.ArchivedAt != nil {
+			t.Errorf("order %s was marked archived, want the copy marked only", o.ID)
+		}
+	}
+}
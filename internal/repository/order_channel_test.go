@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestOrderFilterChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel *order.Channel
+		want    interface{} // nil when no channel condition is expected
+	}{
+		{"no channel", nil, nil},
+		{"web", channelPtr(order.ChannelWeb), order.ChannelWeb},
+		{"other includes orders without a channel", channelPtr(order.ChannelOther), bson.M{"$in": []interface{}{order.ChannelOther, nil}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := orderFilter(order.OrderFilters{Channel: tt.channel})["channel"]
+			if tt.want == nil {
+				if ok {
+					t.Errorf("channel condition = %v, want none", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("channel condition = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeMetricsByChannel(t *testing.T) {
+	doc := rawDoc(t, bson.M{
+		"metrics": bson.A{bson.M{"count": int32(9), "total_sales": int32(900), "avg_ticket": 100.0}},
+		"by_channel": bson.A{
+			bson.M{"_id": "WEB", "count": int32(4)},
+			bson.M{"_id": "WHATSAPP", "count": int32(2)},
+			bson.M{"_id": "FAX", "count": int32(1)},
+			bson.M{"_id": "OTHER", "count": int32(2)}, // The pipeline groups orders without a channel here
+		},
+	})
+
+	got := decodeMetrics(doc).OrdersByChannel
+	want := map[order.Channel]int{order.ChannelWeb: 4, order.ChannelWhatsApp: 2, order.ChannelOther: 2, order.ChannelUnknown: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("orders by channel = %v, want %v", got, want)
+	}
+}
+
+func channelPtr(c order.Channel) *order.Channel { return &c }
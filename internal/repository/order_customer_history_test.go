@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/repository/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCustomerHistoryPipeline(t *testing.T) {
+	cc := order.IDType("CC")
+	company := "c1"
+
+	tests := []struct {
+		name      string
+		q         order.CustomerHistoryQuery
+		wantMatch bson.M
+		wantRows  []bson.M
+	}{
+		{
+			name:      "any id type",
+			q:         order.CustomerHistoryQuery{Identification: "1020304050", Limit: 20},
+			wantMatch: bson.M{"customer.identification": "1020304050"},
+			wantRows:  []bson.M{{"$sort": query.NewestFirst}, {"$limit": 20}},
+		},
+		{
+			name:      "one id type",
+			q:         order.CustomerHistoryQuery{Identification: "1020304050", IDType: &cc, Limit: 20, Offset: 40},
+			wantMatch: bson.M{"customer.identification": "1020304050", "customer.id_type": cc},
+			wantRows:  []bson.M{{"$sort": query.NewestFirst}, {"$skip": 40}, {"$limit": 20}},
+		},
+		{
+			name:      "one company",
+			q:         order.CustomerHistoryQuery{Identification: "1020304050", CompanyID: &company, Limit: 20},
+			wantMatch: bson.M{"customer.identification": "1020304050", "company_id": "c1"},
+			wantRows:  []bson.M{{"$sort": query.NewestFirst}, {"$limit": 20}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := customerHistoryPipeline(tt.q)
+			if len(pipeline) != 2 || pipeline[0][0].Key != "$match" || pipeline[1][0].Key != "$facet" {
+				t.Fatalf("pipeline = %v, want $match and $facet", pipeline)
+			}
+			if match := pipeline[0][0].Value; !reflect.DeepEqual(match, tt.wantMatch) {
+				t.Errorf("$match = %v, want %v", match, tt.wantMatch)
+			}
+			facet := pipeline[1][0].Value.(bson.M)
+			if !reflect.DeepEqual(facet["rows"], tt.wantRows) {
+				t.Errorf("rows = %v, want %v", facet["rows"], tt.wantRows)
+			}
+			if _, ok := facet["stats"]; !ok {
+				t.Error("stats facet missing")
+			}
+		})
+	}
+}
+
+func TestDecodeCustomerHistory(t *testing.T) {
+	last := time.Date(2024, 6, 1, 18, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		doc       bson.M // nil when the aggregation returned no document
+		wantCodes []string
+		want      order.CustomerStats
+	}{
+		{
+			name: "page of a longer history",
+			doc: bson.M{
+				"rows":  bson.A{bson.M{"_id": "order-3", "code": "ORD-000003"}, bson.M{"_id": "order-2", "code": "ORD-000002"}},
+				"stats": bson.A{bson.M{"count": 3, "spend": int64(45000), "last_order_at": last}},
+			},
+			wantCodes: []string{"ORD-000003", "ORD-000002"},
+			want:      order.CustomerStats{OrderCount: 3, LifetimeSpend: 45000, LastOrderAt: &last},
+		},
+		{
+			name:      "only cancelled orders",
+			doc:       bson.M{"rows": bson.A{bson.M{"_id": "order-1", "code": "ORD-000001"}}, "stats": bson.A{bson.M{"count": 1, "spend": 0, "last_order_at": last}}},
+			wantCodes: []string{"ORD-000001"},
+			want:      order.CustomerStats{OrderCount: 1, LastOrderAt: &last},
+		},
+		{
+			name: "unknown customer",
+			doc:  bson.M{"rows": bson.A{}, "stats": bson.A{}},
+		},
+		{
+			name: "no document",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results []customerHistoryResult
+			if tt.doc != nil {
+				var result customerHistoryResult
+				if err := bson.Unmarshal(rawDoc(t, tt.doc), &result); err != nil {
+					t.Fatal(err)
+				}
+				results = append(results, result)
+			}
+
+			orders, stats := decodeCustomerHistory(results)
+			var codes []string
+			for _, o := range orders {
+				codes = append(codes, o.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if stats.OrderCount != tt.want.OrderCount || stats.LifetimeSpend != tt.want.LifetimeSpend {
+				t.Errorf("stats = %+v, want %+v", stats, tt.want)
+			}
+			if (stats.LastOrderAt == nil) != (tt.want.LastOrderAt == nil) ||
+				stats.LastOrderAt != nil && !stats.LastOrderAt.Equal(*tt.want.LastOrderAt) {
+				t.Errorf("last order at = %v, want %v", stats.LastOrderAt, tt.want.LastOrderAt)
+			}
+		})
+	}
+}
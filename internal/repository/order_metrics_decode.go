@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// decodeMetrics builds the metrics from the raw $facet document.
+// Missing facets leave their defaults, numbers are accepted in any numeric BSON type and
+// status/channel/sale type/payment status values outside the enums are counted under the UNKNOWN bucket.
+func decodeMetrics(doc bson.Raw) *order.OrderMetrics {
+	metrics := &order.OrderMetrics{
+		OrdersByStatus:   make(map[order.OrderStatus]int),
+		OrdersByChannel:  make(map[order.Channel]int),
+		OrdersBySaleType: make(map[order.SaleType]order.SaleTypeMetrics),
+		OrdersByPayment:  make(map[order.PaymentStatus]int),
+		TopProducts:      []order.ProductSalesSummary{},
+		StageDurations:   make([]order.StageDuration, len(order.Stages)),
+	}
+	for i, stage := range order.Stages {
+		metrics.StageDurations[i].Stage = stage
+	}
+	if doc == nil {
+		return metrics
+	}
+
+	if summaries := facetDocs(doc, "metrics"); len(summaries) > 0 {
+		metrics.TotalSales = rawInt64(summaries[0].Lookup("total_sales"))
+		metrics.AvgTicket = rawInt64(summaries[0].Lookup("avg_ticket"))
+		metrics.TotalDiscounts = rawInt64(summaries[0].Lookup("total_discounts"))
+		metrics.OrderCount = rawInt64(summaries[0].Lookup("count"))
+	}
+
+	probe := order.Order{}
+	for _, group := range facetDocs(doc, "by_status") {
+		status := order.OrderStatus(rawString(group.Lookup("_id")))
+		if !probe.IsValidStatus(status) {
+			status = order.StatusUnknown
+		}
+		metrics.OrdersByStatus[status] += int(rawInt64(group.Lookup("count")))
+	}
+
+	for _, group := range facetDocs(doc, "by_channel") {
+		channel := order.Channel(rawString(group.Lookup("_id")))
+		if !order.IsValidChannel(channel) {
+			channel = order.ChannelUnknown
+		}
+		metrics.OrdersByChannel[channel] += int(rawInt64(group.Lookup("count")))
+	}
+
+	for _, group := range facetDocs(doc, "by_sale_type") {
+		saleType := order.SaleType(rawString(group.Lookup("_id")))
+		if !slices.Contains(order.AllSaleTypes, saleType) {
+			saleType = order.SaleTypeUnknown
+		}
+		// Unknown sale types may merge several groups, so the average is computed from the sums
+		m := metrics.OrdersBySaleType[saleType]
+		m.OrderCount += rawInt64(group.Lookup("count"))
+		m.TotalSales += rawInt64(group.Lookup("total_sales"))
+		if m.OrderCount > 0 {
+			m.AvgTicket = int64(math.Round(float64(m.TotalSales) / float64(m.OrderCount)))
+		}
+		metrics.OrdersBySaleType[saleType] = m
+	}
+
+	for _, group := range facetDocs(doc, "by_payment_status") {
+		status := order.PaymentStatus(rawString(group.Lookup("_id")))
+		if !order.IsValidPaymentStatus(status) {
+			status = order.PaymentUnknown
+		}
+		metrics.OrdersByPayment[status] += int(rawInt64(group.Lookup("count")))
+	}
+
+	for _, product := range facetDocs(doc, "top_products") {
+		metrics.TopProducts = append(metrics.TopProducts, order.ProductSalesSummary{
+			ProductID:     rawString(product.Lookup("product_id")),
+			Name:          rawString(product.Lookup("name")),
+			TotalQuantity: int(rawInt64(product.Lookup("total_quantity"))),
+			TotalRevenue:  rawInt64(product.Lookup("total_revenue")),
+			AddonRevenue:  rawInt64(product.Lookup("addon_revenue")),
+		})
+	}
+
+	if durations := facetDocs(doc, "stage_durations"); len(durations) > 0 {
+		for i := range metrics.StageDurations {
+			d := &metrics.StageDurations[i]
+			d.OrderCount = rawInt64(durations[0].Lookup(d.Key() + "_count"))
+			if d.OrderCount > 0 {
+				d.AvgSeconds = int64(math.Round(rawFloat64(durations[0].Lookup(d.Key()+"_ms")) / 1000))
+			}
+		}
+	}
+
+	return metrics
+}
+
+// facetDocs returns the documents of a facet array, skipping anything that is not a document
+func facetDocs(doc bson.Raw, facet string) []bson.Raw {
+	array, ok := doc.Lookup(facet).ArrayOK()
+	if !ok {
+		return nil
+	}
+	values, err := array.Values()
+	if err != nil {
+		return nil
+	}
+
+	docs := make([]bson.Raw, 0, len(values))
+	for _, value := range values {
+		if d, ok := value.DocumentOK(); ok {
+			docs = append(docs, d)
+		}
+	}
+	return docs
+}
+
+// rawInt64 converts any numeric BSON value to int64 (doubles are rounded), 0 otherwise
+func rawInt64(value bson.RawValue) int64 {
+	switch value.Type {
+	case bson.TypeInt32:
+		return int64(value.Int32())
+	case bson.TypeInt64:
+		return value.Int64()
+	case bson.TypeDouble:
+		f := value.Double()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0
+		}
+		return int64(math.Round(f))
+	case bson.TypeDecimal128:
+		f, err := strconv.ParseFloat(value.Decimal128().String(), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0
+		}
+		return int64(math.Round(f))
+	}
+	return 0
+}
+
+// rawFloat64 converts any numeric BSON value to float64, 0 otherwise
+func rawFloat64(value bson.RawValue) float64 {
+	var f float64
+	switch value.Type {
+	case bson.TypeInt32:
+		f = float64(value.Int32())
+	case bson.TypeInt64:
+		f = float64(value.Int64())
+	case bson.TypeDouble:
+		f = value.Double()
+	case bson.TypeDecimal128:
+		f, _ = strconv.ParseFloat(value.Decimal128().String(), 64)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	return f
+}
+
+// rawString returns the value if it is a string, "" otherwise (e.g. a null group _id)
+func rawString(value bson.RawValue) string {
+	s, _ := value.StringValueOK()
+	return s
+}
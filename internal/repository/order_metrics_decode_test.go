@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// rawDoc marshals a $facet result document
+func rawDoc(t *testing.T, doc bson.M) bson.Raw {
+	t.Helper()
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestDecodeMetricsOrderCount(t *testing.T) {
+	tests := []struct {
+		name          string
+		doc           bson.M // nil when the aggregation returned no document
+		wantCount     int64
+		wantSales     int64
+		wantAvgTicket int64
+		wantStatuses  map[order.OrderStatus]int
+	}{
+		{
+			name:         "no document",
+			wantStatuses: map[order.OrderStatus]int{},
+		},
+		{
+			name:         "zero matching orders",
+			doc:          bson.M{"metrics": bson.A{}, "by_status": bson.A{}, "top_products": bson.A{}},
+			wantStatuses: map[order.OrderStatus]int{},
+		},
+		{
+			name: "single free order",
+			doc: bson.M{
+				"metrics":   bson.A{bson.M{"count": int32(1), "total_sales": int32(0), "avg_ticket": 0.0}},
+				"by_status": bson.A{bson.M{"_id": "DELIVERED", "count": int32(1)}},
+			},
+			wantCount:    1,
+			wantStatuses: map[order.OrderStatus]int{order.StatusDelivered: 1},
+		},
+		{
+			name: "large window",
+			doc: bson.M{
+				"metrics": bson.A{bson.M{"count": int64(3_000_000_000), "total_sales": int64(9_000_000_000_000_000), "avg_ticket": 3_000_000.4}},
+				"by_status": bson.A{
+					bson.M{"_id": "DELIVERED", "count": int64(2_000_000_000)},
+					bson.M{"_id": "CANCELLED", "count": int64(100_000_000)},
+				},
+			},
+			wantCount:     3_000_000_000,
+			wantSales:     9_000_000_000_000_000,
+			wantAvgTicket: 3_000_000,
+			wantStatuses:  map[order.OrderStatus]int{order.StatusDelivered: 2_000_000_000, order.StatusCancelled: 100_000_000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw bson.Raw
+			if tt.doc != nil {
+				raw = rawDoc(t, tt.doc)
+			}
+			m := decodeMetrics(raw)
+
+			if m.OrderCount != tt.wantCount || m.TotalSales != tt.wantSales || m.AvgTicket != tt.wantAvgTicket {
+				t.Errorf("count/sales/avg = %d/%d/%d, want %d/%d/%d", m.OrderCount, m.TotalSales, m.AvgTicket, tt.wantCount, tt.wantSales, tt.wantAvgTicket)
+			}
+			if len(m.OrdersByStatus) != len(tt.wantStatuses) {
+				t.Errorf("orders by status = %v, want %v", m.OrdersByStatus, tt.wantStatuses)
+			}
+			for status, n := range tt.wantStatuses {
+				if m.OrdersByStatus[status] != n {
+					t.Errorf("orders by status = %v, want %v", m.OrdersByStatus, tt.wantStatuses)
+				}
+			}
+			if m.OrdersByStatus == nil || m.OrdersByChannel == nil || m.TopProducts == nil {
+				t.Error("empty metrics must have empty maps and lists, not nil")
+			}
+		})
+	}
+}
+
+// TestDecodeMetricsReadsSummaryCount checks order_count comes from the summary facet the pipeline
+// builds, not from the status groups, which can disagree when the facets are decoded loosely
+func TestDecodeMetricsReadsSummaryCount(t *testing.T) {
+	group, ok := summaryFacet()[0]["$group"].(bson.M)
+	if !ok {
+		t.Fatalf("facet = %v, want a $group", summaryFacet())
+	}
+	if !reflect.DeepEqual(group["count"], bson.M{"$sum": 1}) {
+		t.Fatalf("count = %v, want one per matched order", group["count"])
+	}
+
+	// Give every field the group outputs a distinct value, as the aggregation would
+	summary := bson.M{}
+	value := int64(100)
+	for field := range group {
+		summary[field] = value
+		value++
+	}
+	m := decodeMetrics(rawDoc(t, bson.M{
+		"metrics":   bson.A{summary},
+		"by_status": bson.A{bson.M{"_id": "DELIVERED", "count": int32(3)}},
+	}))
+
+	if m.OrderCount != summary["count"] {
+		t.Errorf("order count = %d, want the summary count %d", m.OrderCount, summary["count"])
+	}
+}
+
+func TestDecodeMetricsTopProductsAddonRevenue(t *testing.T) {
+	m := decodeMetrics(rawDoc(t, bson.M{"top_products": bson.A{
+		bson.M{"product_id": "p1", "name": "Burger", "total_quantity": int32(3), "total_revenue": int64(39000), "addon_revenue": int64(9000)},
+		bson.M{"product_id": "p2", "name": "Soda", "total_quantity": int32(2), "total_revenue": int64(6000)},
+	}}))
+
+	want := []order.ProductSalesSummary{
+		{ProductID: "p1", Name: "Burger", TotalQuantity: 3, TotalRevenue: 39000, AddonRevenue: 9000},
+		{ProductID: "p2", Name: "Soda", TotalQuantity: 2, TotalRevenue: 6000},
+	}
+	if len(m.TopProducts) != len(want) {
+		t.Fatalf("top products = %+v, want %+v", m.TopProducts, want)
+	}
+	for i := range want {
+		if m.TopProducts[i] != want[i] {
+			t.Errorf("top product %d = %+v, want %+v", i, m.TopProducts[i], want[i])
+		}
+	}
+}
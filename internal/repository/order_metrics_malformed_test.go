@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecodeMetricsMalformedFacets(t *testing.T) {
+	decimal := func(s string) primitive.Decimal128 {
+		d, err := primitive.ParseDecimal128(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		doc      bson.M
+		check    func(t *testing.T, m *order.OrderMetrics)
+		wantJSON bool
+	}{
+		{
+			name: "facets missing entirely",
+			doc:  bson.M{},
+			check: func(t *testing.T, m *order.OrderMetrics) {
+				if m.OrderCount != 0 || len(m.OrdersByStatus) != 0 || m.TopProducts == nil {
+					t.Errorf("metrics = %+v, want empty defaults", m)
+				}
+			},
+		},
+		{
+			name: "facet is not an array",
+			doc:  bson.M{"metrics": bson.M{"count": 3}, "by_status": "DELIVERED", "top_products": nil},
+			check: func(t *testing.T, m *order.OrderMetrics) {
+				if m.OrderCount != 0 || len(m.OrdersByStatus) != 0 || len(m.TopProducts) != 0 {
+					t.Errorf("metrics = %+v, want empty defaults", m)
+				}
+			},
+		},
+		{
+			name: "array items that are not documents",
+			doc:  bson.M{"by_status": bson.A{"DELIVERED", int32(4), nil, bson.M{"_id": "DELIVERED", "count": int32(2)}}},
+			check: func(t *testing.T, m *order.OrderMetrics) {
+				if want := map[order.OrderStatus]int{order.StatusDelivered: 2}; !reflect.DeepEqual(m.OrdersByStatus, want) {
+					t.Errorf("by status = %v, want %v", m.OrdersByStatus, want)
+				}
+			},
+		},
+		{
+			name: "unknown and null statuses share the UNKNOWN bucket",
+			doc: bson.M{"by_status": bson.A{
+				bson.M{"_id": "SHIPPED", "count": int32(2)},
+				bson.M{"_id": nil, "count": int32(1)},
+				bson.M{"_id": int32(7), "count": int32(1)},
+				bson.M{"_id": "CREATED", "count": int32(5)},
+			}},
+			check: func(t *testing.T, m *order.OrderMetrics) {
+				want := map[order.OrderStatus]int{order.StatusUnknown: 4, order.StatusCreated: 5}
+				if !reflect.DeepEqual(m.OrdersByStatus, want) {
+					t.Errorf("by status = %v, want %v", m.OrdersByStatus, want)
+				}
+			},
+		},
+		{
+			name: "numbers in any BSON type",
+			doc: bson.M{"metrics": bson.A{bson.M{
+				"count":       int64(4),
+				"total_sales": decimal("10000.4"),
+				"avg_ticket":  "2500", // Strings are not numbers
+			}}},
+			check: func(t *testing.T, m *order.OrderMetrics) {
+				if m.OrderCount != 4 || m.TotalSales != 10000 || m.AvgTicket != 0 {
+					t.Errorf("summary = %d/%d/%d, want 4/10000/0", m.OrderCount, m.TotalSales, m.AvgTicket)
+				}
+			},
+		},
+		{
+			name: "top products with missing fields",
+			doc:  bson.M{"top_products": bson.A{bson.M{"name": "Burger", "total_quantity": 3.0}, bson.M{}}},
+			check: func(t *testing.T, m *order.OrderMetrics) {
+				want := []order.ProductSalesSummary{{Name: "Burger", TotalQuantity: 3}, {}}
+				if !reflect.DeepEqual(m.TopProducts, want) {
+					t.Errorf("top products = %+v, want %+v", m.TopProducts, want)
+				}
+			},
+		},
+		{
+			name: "stage durations without a document",
+			doc:  bson.M{"stage_durations": bson.A{}},
+			check: func(t *testing.T, m *order.OrderMetrics) {
+				if len(m.StageDurations) != len(order.Stages) {
+					t.Fatalf("got %d stage durations, want one per stage", len(m.StageDurations))
+				}
+				for i, d := range m.StageDurations {
+					if d.Stage != order.Stages[i] || d.OrderCount != 0 || d.AvgSeconds != 0 {
+						t.Errorf("stage %d = %+v, want an empty %v", i, d, order.Stages[i])
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := decodeMetrics(rawDoc(t, tt.doc))
+			if m.OrdersByStatus == nil || m.OrdersByChannel == nil {
+				t.Fatalf("metrics maps must never be nil: %+v", m)
+			}
+			tt.check(t, m)
+		})
+	}
+}
+
+func TestRawNumbers(t *testing.T) {
+	value := func(v interface{}) bson.RawValue {
+		raw, err := bson.Marshal(bson.M{"v": v})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bson.Raw(raw).Lookup("v")
+	}
+
+	tests := []struct {
+		name      string
+		value     interface{}
+		wantInt   int64
+		wantFloat float64
+	}{
+		{"int32", int32(-7), -7, -7},
+		{"int64", int64(1) << 40, 1 << 40, 1 << 40},
+		{"double rounds", 2.5, 3, 2.5},
+		{"NaN", math.NaN(), 0, 0},
+		{"infinity", math.Inf(1), 0, 0},
+		{"string", "12", 0, 0},
+		{"null", nil, 0, 0},
+		{"bool", true, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := value(tt.value)
+			if got := rawInt64(v); got != tt.wantInt {
+				t.Errorf("rawInt64 = %d, want %d", got, tt.wantInt)
+			}
+			if got := rawFloat64(v); got != tt.wantFloat {
+				t.Errorf("rawFloat64 = %v, want %v", got, tt.wantFloat)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDecodeMetricsBySaleType(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  bson.M // nil when the aggregation returned no document
+		want map[order.SaleType]order.SaleTypeMetrics
+	}{
+		{"no document", nil, map[order.SaleType]order.SaleTypeMetrics{}},
+		{"empty facet", bson.M{"by_sale_type": bson.A{}}, map[order.SaleType]order.SaleTypeMetrics{}},
+		{"facet missing", bson.M{"metrics": bson.A{}}, map[order.SaleType]order.SaleTypeMetrics{}},
+		{
+			name: "both sale types",
+			doc: bson.M{"by_sale_type": bson.A{
+				bson.M{"_id": "DELIVERY", "count": int32(2), "total_sales": int32(30000), "avg_ticket": 15000.0},
+				bson.M{"_id": "ON_SITE", "count": int64(3), "total_sales": int64(10000), "avg_ticket": 3333.3},
+			}},
+			want: map[order.SaleType]order.SaleTypeMetrics{
+				order.SaleTypeDelivery: {OrderCount: 2, TotalSales: 30000, AvgTicket: 15000},
+				order.SaleTypeOnSite:   {OrderCount: 3, TotalSales: 10000, AvgTicket: 3333},
+			},
+		},
+		{
+			name: "average rounded from the sums",
+			doc:  bson.M{"by_sale_type": bson.A{bson.M{"_id": "ON_SITE", "count": int32(3), "total_sales": int32(5000)}}},
+			want: map[order.SaleType]order.SaleTypeMetrics{order.SaleTypeOnSite: {OrderCount: 3, TotalSales: 5000, AvgTicket: 1667}},
+		},
+		{
+			name: "group without orders",
+			doc:  bson.M{"by_sale_type": bson.A{bson.M{"_id": "DELIVERY", "count": int32(0), "total_sales": int32(0)}}},
+			want: map[order.SaleType]order.SaleTypeMetrics{order.SaleTypeDelivery: {}},
+		},
+		{
+			name: "missing sale type counted as unknown",
+			doc:  bson.M{"by_sale_type": bson.A{bson.M{"_id": nil, "count": int32(1), "total_sales": int32(800)}}},
+			want: map[order.SaleType]order.SaleTypeMetrics{order.SaleTypeUnknown: {OrderCount: 1, TotalSales: 800, AvgTicket: 800}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw bson.Raw
+			if tt.doc != nil {
+				raw = rawDoc(t, tt.doc)
+			}
+			m := decodeMetrics(raw)
+			if m.OrdersBySaleType == nil {
+				t.Fatal("orders by sale type is nil")
+			}
+			if !reflect.DeepEqual(m.OrdersBySaleType, tt.want) {
+				t.Errorf("by sale type = %+v, want %+v", m.OrdersBySaleType, tt.want)
+			}
+		})
+	}
+}
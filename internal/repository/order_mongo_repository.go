@@ -2,22 +2,58 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/repository/query"
+	"github.com/emerarteaga/products-api/internal/util"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type orderMongoRepository struct {
-	collection *mongo.Collection
+	collection   *mongo.Collection
+	archive      *mongo.Collection // Old terminal orders moved out of the active collection
+	reads        readers
+	archiveReads readers
 }
 
 // NewOrderMongoRepository creates a new order repository
-func NewOrderMongoRepository(collection *mongo.Collection) order.Repository {
-	return &orderMongoRepository{collection: collection}
+func NewOrderMongoRepository(collection, archive *mongo.Collection) order.Repository {
+	return &orderMongoRepository{
+		collection:   collection,
+		archive:      archive,
+		reads:        newReaders(collection),
+		archiveReads: newReaders(archive),
+	}
+}
+
+// Text search index names; the legacy one predates the notes list
+const (
+	textSearchIndex       = "orders_text_search_v2"
+	legacyTextSearchIndex = "orders_text_search"
+)
+
+// MongoDB server error codes
+const (
+	mongoNamespaceNotFound = 26
+	mongoIndexNotFound     = 27
+)
+
+// dropIndexIfExists drops an index by name, ignoring a missing index or collection
+func dropIndexIfExists(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == mongoIndexNotFound || cmdErr.Code == mongoNamespaceNotFound) {
+		return nil
+	}
+	return err
 }
 
 // CreateIndexes creates the necessary indexes for the orders collection
@@ -39,6 +75,16 @@ func (r *orderMongoRepository) CreateIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{{Key: "updated_at", Value: -1}},
 		},
+		{
+			Keys: bson.D{{Key: "total", Value: -1}},
+		},
+		{
+			// Listings filtered by status and sorted by total (GET /orders?status=...&sort=total)
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "total", Value: -1},
+			},
+		},
 		{
 			Keys: bson.D{{Key: "products.id", Value: 1}},
 		},
@@ -57,6 +103,81 @@ func (r *orderMongoRepository) CreateIndexes(ctx context.Context) error {
 				{Key: "created_at", Value: -1},
 			},
 		},
+		{
+			Keys: bson.D{
+				{Key: "channel", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			// Payment review queues (GET /orders?payment_status=RECEIPT_UPLOADED)
+			Keys: bson.D{
+				{Key: "payment_status", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			// Listings and metrics of one restaurant (GET /orders?company_id=...&status=...)
+			Keys: bson.D{
+				{Key: "company_id", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "sale_point_id", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "company_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "sale_point_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "customer.phone_normalized", Value: 1}},
+		},
+		{
+			// Customer order history (GET /orders/customer/:identification?id_type=...)
+			Keys: bson.D{
+				{Key: "customer.identification", Value: 1},
+				{Key: "customer.id_type", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			// Quick search of order listings (GET /orders?q=...)
+			Keys: bson.D{{Key: "customer.name", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "customer.phone", Value: 1}},
+		},
+		{
+			// Free text search (GET /orders/search)
+			Keys: bson.D{
+				{Key: "customer.name", Value: "text"},
+				{Key: "customer.phone", Value: "text"},
+				{Key: "shipping_address", Value: "text"},
+				{Key: "note", Value: "text"},
+				{Key: "notes.text", Value: "text"},
+				{Key: "products.name", Value: "text"},
+			},
+			Options: options.Index().SetName(textSearchIndex),
+		},
+	}
+
+	// A collection has a single text index, so the previous version must go first
+	if err := dropIndexIfExists(ctx, r.collection, legacyTextSearchIndex); err != nil {
+		return fmt.Errorf("failed to drop legacy text index: %w", err)
 	}
 
 	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
@@ -64,12 +185,21 @@ func (r *orderMongoRepository) CreateIndexes(ctx context.Context) error {
 		return fmt.Errorf("failed to create indexes: %w", err)
 	}
 
+	// Archived orders are only looked up by tracking code
+	_, err = r.archive.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create archive indexes: %w", err)
+	}
+
 	return nil
 }
 
 // Create creates a new order
 func (r *orderMongoRepository) Create(ctx context.Context, o *order.Order) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	_, err := r.collection.InsertOne(ctx, o)
@@ -85,11 +215,11 @@ func (r *orderMongoRepository) Create(ctx context.Context, o *order.Order) error
 
 // FindByID finds an order by ID
 func (r *orderMongoRepository) FindByID(ctx context.Context, id string) (*order.Order, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var o order.Order
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&o)
+	err := r.reads.forRead(ctx).FindOne(ctx, bson.M{"_id": id}).Decode(&o)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, order.ErrOrderNotFound
@@ -102,11 +232,11 @@ func (r *orderMongoRepository) FindByID(ctx context.Context, id string) (*order.
 
 // FindByCode finds an order by tracking code
 func (r *orderMongoRepository) FindByCode(ctx context.Context, code string) (*order.Order, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var o order.Order
-	err := r.collection.FindOne(ctx, bson.M{"code": code}).Decode(&o)
+	err := r.reads.forRead(ctx).FindOne(ctx, bson.M{"code": code}).Decode(&o)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, order.ErrOrderNotFound
@@ -117,23 +247,37 @@ func (r *orderMongoRepository) FindByCode(ctx context.Context, code string) (*or
 	return &o, nil
 }
 
-// Update updates an order
+// Update saves an order if nobody else saved it since it was loaded, incrementing its version.
+// It returns ErrOrderConflict when the stored version moved on and ErrOrderNotFound when the order is gone.
 func (r *orderMongoRepository) Update(ctx context.Context, o *order.Order) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	o.UpdatedAt = time.Now()
-
-	update := bson.M{
-		"$set": o,
+	loaded := o.Version
+	filter := bson.M{"_id": o.ID, "version": loaded}
+	if loaded == 0 {
+		// Orders stored before versioning have no version field
+		filter["version"] = bson.M{"$in": []interface{}{0, nil}}
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": o.ID}, update)
+	o.UpdatedAt = time.Now()
+	o.Version = loaded + 1
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": o})
 	if err != nil {
+		o.Version = loaded
 		return fmt.Errorf("failed to update order: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
+		o.Version = loaded
+		exists, err := r.collection.CountDocuments(ctx, bson.M{"_id": o.ID}, options.Count().SetLimit(1))
+		if err != nil {
+			return fmt.Errorf("failed to check order existence: %w", err)
+		}
+		if exists > 0 {
+			return order.ErrOrderConflict
+		}
 		return order.ErrOrderNotFound
 	}
 
@@ -142,27 +286,15 @@ func (r *orderMongoRepository) Update(ctx context.Context, o *order.Order) error
 
 // FindAll retrieves all orders with optional filters
 func (r *orderMongoRepository) FindAll(ctx context.Context, filters order.OrderFilters) ([]*order.Order, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	// Build filter
-	filter := bson.M{}
-	r.applyFilters(filter, filters)
-
-	// Set default pagination
-	if filters.Limit <= 0 {
-		filters.Limit = 50
-	}
-	if filters.Offset < 0 {
-		filters.Offset = 0
+	filter, sort, err := orderPage(filters)
+	if err != nil {
+		return nil, err
 	}
 
-	opts := options.Find().
-		SetLimit(int64(filters.Limit)).
-		SetSkip(int64(filters.Offset)).
-		SetSort(bson.D{{Key: "created_at", Value: -1}})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.reads.forRead(ctx).Find(ctx, filter, query.Page(filters.Limit, filters.Offset, sort))
 	if err != nil {
 		return nil, fmt.Errorf("failed to find orders: %w", err)
 	}
@@ -170,21 +302,74 @@ func (r *orderMongoRepository) FindAll(ctx context.Context, filters order.OrderF
 
 	var orders []*order.Order
 	if err := cursor.All(ctx, &orders); err != nil {
-		return nil, fmt.Errorf("failed to decode orders: %w", err)
+		return nil, wrapError(ctx, "failed to decode orders", err)
 	}
 
 	return orders, nil
 }
 
+// orderSort returns the listing order requested in filters, newest first by default.
+// Other fields are tie-broken by _id so pages stay stable across equal values.
+func orderSort(filters order.OrderFilters) bson.D {
+	if filters.SortBy == "" && filters.SortDir == "" {
+		return query.NewestFirst
+	}
+
+	field := filters.SortBy
+	if field == "" {
+		field = order.SortFieldCreatedAt
+	}
+	dir := -1
+	if filters.SortDir == order.SortAsc {
+		dir = 1
+	}
+	return bson.D{{Key: string(field), Value: dir}, {Key: "_id", Value: dir}}
+}
+
+// orderPage returns the filter and sort of one listing page. Cursor pages seek past the cursor
+// and break ties by _id, so they stay stable while orders are created.
+func orderPage(filters order.OrderFilters) (bson.M, bson.D, error) {
+	filter := orderFilter(filters)
+	if filters.Cursor == nil {
+		return filter, orderSort(filters), nil
+	}
+
+	listOrder := query.WithIDTieBreak(orderSort(filters))
+	if filters.Cursor.IsStart() {
+		return filter, listOrder, nil
+	}
+	field, dir := listOrder[0].Key, listOrder[0].Value.(int)
+	if filters.Cursor.Field != field {
+		return nil, nil, fmt.Errorf("%w: orders are sorted by %s, not %s", util.ErrInvalidCursor, field, filters.Cursor.Field)
+	}
+	value, err := orderCursorValue(order.OrderSortField(field), filters.Cursor.Value)
+	if err != nil {
+		return nil, nil, util.ErrInvalidCursor
+	}
+	appendAnd(filter, query.After(field, dir, value, filters.Cursor.ID))
+	return filter, listOrder, nil
+}
+
+// orderCursorValue parses the sort value of a cursor into the type stored in the field
+func orderCursorValue(field order.OrderSortField, value string) (interface{}, error) {
+	switch field {
+	case order.SortFieldCreatedAt, order.SortFieldUpdatedAt:
+		return time.Parse(time.RFC3339Nano, value)
+	case order.SortFieldTotal:
+		return strconv.ParseInt(value, 10, 64)
+	default:
+		return value, nil
+	}
+}
+
 // Count returns the total number of orders matching filters
 func (r *orderMongoRepository) Count(ctx context.Context, filters order.OrderFilters) (int64, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	filter := bson.M{}
-	r.applyFilters(filter, filters)
+	filter := orderFilter(filters)
 
-	count, err := r.collection.CountDocuments(ctx, filter)
+	count, err := r.reads.forRead(ctx).CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count orders: %w", err)
 	}
@@ -192,9 +377,120 @@ func (r *orderMongoRepository) Count(ctx context.Context, filters order.OrderFil
 	return count, nil
 }
 
+// FindAllWithCount retrieves one page of orders and the total matching filters with a single
+// $facet aggregation, so both come from the same snapshot of the collection
+func (r *orderMongoRepository) FindAllWithCount(ctx context.Context, filters order.OrderFilters) ([]*order.Order, int64, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter, sort, err := orderPage(filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pipeline := pagePipeline(filter, sort, filters.Offset, filters.Limit, filters.SkipCount)
+	cursor, err := r.reads.forRead(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, wrapError(ctx, "failed to find orders", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []pageResult[*order.Order]
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, wrapError(ctx, "failed to decode orders", err)
+	}
+
+	orders, total := decodePage(results, filters.SkipCount)
+	return orders, total, nil
+}
+
+// FindByCustomerIdentification retrieves one page of a customer's orders, newest first, and the
+// stats of all their orders with a single $facet aggregation
+func (r *orderMongoRepository) FindByCustomerIdentification(ctx context.Context, q order.CustomerHistoryQuery) ([]*order.Order, order.CustomerStats, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.reads.forRead(ctx).Aggregate(ctx, customerHistoryPipeline(q))
+	if err != nil {
+		return nil, order.CustomerStats{}, wrapError(ctx, "failed to find customer orders", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []customerHistoryResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, order.CustomerStats{}, wrapError(ctx, "failed to decode customer orders", err)
+	}
+
+	orders, stats := decodeCustomerHistory(results)
+	return orders, stats, nil
+}
+
+// customerHistoryPipeline lists one page of the orders of a customer, newest first, next to the
+// stats of all of them. Cancelled orders count as orders but add nothing to the lifetime spend.
+func customerHistoryPipeline(q order.CustomerHistoryQuery) mongo.Pipeline {
+	b := query.New(bson.M{"customer.identification": q.Identification})
+	query.Equal(b, "customer.id_type", q.IDType)
+	tenantFilter(b, "company_id", q.CompanyID)
+	tenantFilter(b, "sale_point_id", q.SalePointID)
+
+	rows := []bson.M{{"$sort": query.NewestFirst}}
+	if q.Offset > 0 {
+		rows = append(rows, bson.M{"$skip": q.Offset})
+	}
+	if q.Limit > 0 {
+		rows = append(rows, bson.M{"$limit": q.Limit})
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: b.Filter()}},
+		{{Key: "$facet", Value: bson.M{
+			"rows": rows,
+			"stats": []bson.M{{"$group": bson.M{
+				"_id":   nil,
+				"count": bson.M{"$sum": 1},
+				"spend": bson.M{"$sum": bson.M{"$cond": []interface{}{
+					bson.M{"$eq": []interface{}{"$status", order.StatusCancelled}}, 0, "$total",
+				}}},
+				"last_order_at": bson.M{"$max": "$created_at"},
+			}}},
+		}}},
+	}
+}
+
+// customerHistoryResult is the document returned by a customerHistoryPipeline
+type customerHistoryResult struct {
+	Rows  []*order.Order `bson:"rows"`
+	Stats []struct {
+		Count       int64     `bson:"count"`
+		Spend       int64     `bson:"spend"`
+		LastOrderAt time.Time `bson:"last_order_at"`
+	} `bson:"stats"`
+}
+
+// decodeCustomerHistory returns the page and the stats of a customerHistoryPipeline result.
+// $group emits nothing when the customer has no orders, which leaves the stats empty.
+func decodeCustomerHistory(results []customerHistoryResult) ([]*order.Order, order.CustomerStats) {
+	var (
+		orders []*order.Order
+		stats  order.CustomerStats
+	)
+	if len(results) > 0 {
+		orders = results[0].Rows
+		if len(results[0].Stats) > 0 {
+			s := results[0].Stats[0]
+			stats.OrderCount = s.Count
+			stats.LifetimeSpend = s.Spend
+			if !s.LastOrderAt.IsZero() {
+				stats.LastOrderAt = &s.LastOrderAt
+			}
+		}
+	}
+	return orders, stats
+}
+
 // ExistsByCode checks if an order exists with the given code
 func (r *orderMongoRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	count, err := r.collection.CountDocuments(ctx, bson.M{"code": code})
@@ -207,31 +503,56 @@ func (r *orderMongoRepository) ExistsByCode(ctx context.Context, code string) (b
 
 // GetMetrics returns aggregated order metrics
 func (r *orderMongoRepository) GetMetrics(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	ctx, cancel := withTimeout(ctx, 15*time.Second)
 	defer cancel()
 
 	// Build base filter
-	matchFilter := bson.M{}
-	r.applyFilters(matchFilter, filters)
+	matchFilter := orderFilter(filters)
 
 	// Aggregation pipeline
-	pipeline := mongo.Pipeline{
+	pipeline := mongo.Pipeline{}
+	if filters.IncludeArchived {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{"coll": r.archive.Name()}}})
+	}
+	pipeline = append(pipeline, mongo.Pipeline{
 		{{Key: "$match", Value: matchFilter}},
 		{{Key: "$facet", Value: bson.M{
-			"metrics": []bson.M{
+			"metrics": summaryFacet(),
+			"by_status": []bson.M{
+				{
+					"$group": bson.M{
+						"_id":   "$status",
+						"count": bson.M{"$sum": 1},
+					},
+				},
+			},
+			"by_channel": []bson.M{
 				{
 					"$group": bson.M{
-						"_id":         nil,
+						// Orders created before channels were tracked count as OTHER
+						"_id":   bson.M{"$ifNull": []interface{}{"$channel", order.ChannelOther}},
+						"count": bson.M{"$sum": 1},
+					},
+				},
+			},
+			"by_sale_type": []bson.M{
+				{
+					"$group": bson.M{
+						"_id":         "$sale_type",
 						"total_sales": bson.M{"$sum": "$total"},
-						"avg_ticket":  bson.M{"$avg": "$total"},
 						"count":       bson.M{"$sum": 1},
 					},
 				},
 			},
-			"by_status": []bson.M{
+			"by_payment_status": []bson.M{
 				{
 					"$group": bson.M{
-						"_id":   "$status",
+						// Orders stored before payment statuses existed count by their receipt
+						"_id": bson.M{"$ifNull": []interface{}{"$payment_status", bson.M{"$cond": []interface{}{
+							bson.M{"$eq": []interface{}{bson.M{"$ifNull": []interface{}{"$payment_receipt_url", ""}}, ""}},
+							order.PaymentPending,
+							order.PaymentReceiptUploaded,
+						}}}},
 						"count": bson.M{"$sum": 1},
 					},
 				},
@@ -245,14 +566,8 @@ func (r *orderMongoRepository) GetMetrics(ctx context.Context, filters order.Ord
 							"name": "$products.name",
 						},
 						"total_quantity": bson.M{"$sum": "$products.quantity"},
-						"total_revenue": bson.M{
-							"$sum": bson.M{
-								"$multiply": []interface{}{
-									"$products.price",
-									"$products.quantity",
-								},
-							},
-						},
+						"total_revenue":  bson.M{"$sum": lineRevenueExpr},
+						"addon_revenue":  bson.M{"$sum": lineAddonRevenueExpr},
 					},
 				},
 				{"$sort": bson.M{"total_quantity": -1}},
@@ -263,103 +578,530 @@ func (r *orderMongoRepository) GetMetrics(ctx context.Context, filters order.Ord
 						"name":           "$_id.name",
 						"total_quantity": 1,
 						"total_revenue":  1,
+						"addon_revenue":  1,
 						"_id":            0,
 					},
 				},
 			},
+			"stage_durations": stageDurationsFacet(),
 		}}},
-	}
+	}...)
 
 	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, fmt.Errorf("failed to aggregate metrics: %w", err)
+		return nil, wrapError(ctx, "failed to aggregate metrics", err)
 	}
 	defer cursor.Close(ctx)
 
-	var results []struct {
-		Metrics []struct {
-			TotalSales int64 `bson:"total_sales"`
-			AvgTicket  int64 `bson:"avg_ticket"`
-		} `bson:"metrics"`
-		ByStatus []struct {
-			Status order.OrderStatus `bson:"_id"`
-			Count  int               `bson:"count"`
-		} `bson:"by_status"`
-		TopProducts []order.ProductSalesSummary `bson:"top_products"`
+	// Decode loosely: a facet with an unexpected shape must not fail the whole response
+	var results []bson.Raw
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, wrapError(ctx, "failed to decode metrics", err)
+	}
+
+	if len(results) == 0 {
+		return decodeMetrics(nil), nil
+	}
+
+	return decodeMetrics(results[0]), nil
+}
+
+// statusTimeFields maps each status to the field holding when the order first reached it
+var statusTimeFields = map[order.OrderStatus]string{
+	order.StatusCreated:        "created_at",
+	order.StatusVerified:       "verified_at",
+	order.StatusInProgress:     "in_progress_at",
+	order.StatusOutForDelivery: "out_for_delivery_at",
+	order.StatusDelivered:      "delivered_at",
+	order.StatusCancelled:      "cancelled_at",
+}
+
+// summaryFacet totals the matched orders; decodeMetrics reads its count as the order count
+func summaryFacet() []bson.M {
+	return []bson.M{{"$group": bson.M{
+		"_id":             nil,
+		"total_sales":     bson.M{"$sum": "$total"},
+		"avg_ticket":      bson.M{"$avg": "$total"},
+		"total_discounts": bson.M{"$sum": "$discount_amount"},
+		"count":           bson.M{"$sum": 1},
+	}}}
+}
+
+// stageDurationsFacet averages the milliseconds spent in each of order.Stages.
+// Orders missing either timestamp of a stage (it was skipped, or the order predates
+// status timestamps) yield null, which $avg ignores, so they are left out instead of counted as zero.
+func stageDurationsFacet() []bson.M {
+	group := bson.M{"_id": nil}
+	for _, stage := range order.Stages {
+		from, to := "$"+statusTimeFields[stage.From], "$"+statusTimeFields[stage.To]
+		measured := bson.M{"$and": []interface{}{
+			bson.M{"$eq": []interface{}{bson.M{"$type": from}, "date"}},
+			bson.M{"$eq": []interface{}{bson.M{"$type": to}, "date"}},
+			bson.M{"$gte": []interface{}{to, from}},
+		}}
+		group[stage.Key()+"_ms"] = bson.M{"$avg": bson.M{"$cond": []interface{}{
+			measured, bson.M{"$subtract": []interface{}{to, from}}, nil,
+		}}}
+		group[stage.Key()+"_count"] = bson.M{"$sum": bson.M{"$cond": []interface{}{measured, 1, 0}}}
 	}
+	return []bson.M{{"$group": group}}
+}
+
+// lineAddonsExpr is the price of the addons of one unit of an unwound order line
+var lineAddonsExpr = bson.M{"$sum": bson.M{"$map": bson.M{
+	"input": bson.M{"$ifNull": []interface{}{"$products.addons", bson.A{}}},
+	"as":    "addon",
+	"in":    bson.M{"$multiply": []interface{}{"$$addon.price", "$$addon.quantity"}},
+}}}
+
+// lineRevenueExpr is the total of an unwound order line, addons included
+var lineRevenueExpr = bson.M{"$multiply": []interface{}{
+	bson.M{"$add": []interface{}{"$products.price", lineAddonsExpr}},
+	"$products.quantity",
+}}
 
+// lineAddonRevenueExpr is the part of an unwound order line's total that comes from addons
+var lineAddonRevenueExpr = bson.M{"$multiply": []interface{}{lineAddonsExpr, "$products.quantity"}}
+
+// productSalesSortFields maps the sort column to the aggregated field
+var productSalesSortFields = map[order.ProductSalesSort]string{
+	order.SortByRevenue:  "total_revenue",
+	order.SortByQuantity: "total_quantity",
+	order.SortByOrders:   "order_count",
+}
+
+// GetProductSales aggregates quantity, revenue and order count per product
+func (r *orderMongoRepository) GetProductSales(ctx context.Context, filters order.OrderFilters, sortBy order.ProductSalesSort) ([]order.ProductSales, int64, error) {
+	ctx, cancel := withTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Aggregate(ctx, productSalesPipeline(filters, sortBy, r.archive.Name()))
+	if err != nil {
+		return nil, 0, wrapError(ctx, "failed to aggregate product sales", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Rows  []order.ProductSales `bson:"rows"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
 	if err := cursor.All(ctx, &results); err != nil {
-		return nil, fmt.Errorf("failed to decode metrics: %w", err)
+		return nil, 0, wrapError(ctx, "failed to decode product sales", err)
 	}
 
-	if len(results) == 0 {
-		return &order.OrderMetrics{
-			OrdersByStatus: make(map[order.OrderStatus]int),
-			TopProducts:    []order.ProductSalesSummary{},
-		}, nil
+	sales := []order.ProductSales{}
+	var total int64
+	if len(results) > 0 {
+		if results[0].Rows != nil {
+			sales = results[0].Rows
+		}
+		if len(results[0].Total) > 0 {
+			total = results[0].Total[0].Count
+		}
 	}
 
-	result := results[0]
-	metrics := &order.OrderMetrics{
-		OrdersByStatus: make(map[order.OrderStatus]int),
-		TopProducts:    result.TopProducts,
+	return sales, total, nil
+}
+
+// productSalesPipeline builds the per-product sales aggregation: one page of rows and the
+// number of products. Archived orders are read from archiveColl when the filters include them.
+func productSalesPipeline(filters order.OrderFilters, sortBy order.ProductSalesSort, archiveColl string) mongo.Pipeline {
+	matchFilter := orderFilter(filters)
+	if filters.Status == nil && len(filters.Statuses) == 0 {
+		// Cancelled orders never produced revenue
+		matchFilter["status"] = bson.M{"$ne": order.StatusCancelled}
 	}
 
-	if len(result.Metrics) > 0 {
-		metrics.TotalSales = result.Metrics[0].TotalSales
-		metrics.AvgTicket = result.Metrics[0].AvgTicket
+	pipeline := mongo.Pipeline{}
+	if filters.IncludeArchived {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{"coll": archiveColl}}})
+	}
+	return append(pipeline, mongo.Pipeline{
+		{{Key: "$match", Value: matchFilter}},
+		{{Key: "$unwind", Value: "$products"}},
+		// The same product can appear on several lines of one order, so group per order first
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"order":   "$_id",
+				"product": "$products.id",
+			},
+			"name":          bson.M{"$last": "$products.name"},
+			"quantity":      bson.M{"$sum": "$products.quantity"},
+			"revenue":       bson.M{"$sum": lineRevenueExpr},
+			"addon_revenue": bson.M{"$sum": lineAddonRevenueExpr},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$_id.product",
+			"name":           bson.M{"$last": "$name"},
+			"total_quantity": bson.M{"$sum": "$quantity"},
+			"total_revenue":  bson.M{"$sum": "$revenue"},
+			"addon_revenue":  bson.M{"$sum": "$addon_revenue"},
+			"order_count":    bson.M{"$sum": 1},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"rows": []bson.M{
+				// _id breaks ties so pages are stable
+				{"$sort": bson.D{{Key: productSalesSortFields[sortBy], Value: -1}, {Key: "_id", Value: 1}}},
+				{"$skip": filters.Offset},
+				{"$limit": filters.Limit},
+				{
+					"$project": bson.M{
+						"product_id":     "$_id",
+						"name":           1,
+						"total_quantity": 1,
+						"total_revenue":  1,
+						"addon_revenue":  1,
+						"order_count":    1,
+						"avg_price": bson.M{
+							"$cond": []interface{}{
+								bson.M{"$gt": []interface{}{"$total_quantity", 0}},
+								bson.M{"$toLong": bson.M{"$round": []interface{}{
+									bson.M{"$divide": []interface{}{"$total_revenue", "$total_quantity"}}, 0,
+								}}},
+								0,
+							},
+						},
+						"_id": 0,
+					},
+				},
+			},
+			"total": []bson.M{
+				{"$count": "count"},
+			},
+		}}},
+	}...)
+}
+
+// orderFilter builds the filter document shared by listings, counts, batches and aggregations
+func orderFilter(filters order.OrderFilters) bson.M {
+	b := query.New(nil)
+	tenantFilter(b, "company_id", filters.CompanyID)
+	tenantFilter(b, "sale_point_id", filters.SalePointID)
+	query.OneOf(b, "status", filters.Status, filters.Statuses)
+	query.Equal(b, "sale_type", filters.SaleType)
+
+	if filters.Channel != nil && *filters.Channel == order.ChannelOther {
+		// Orders created before channels were tracked have no channel field
+		b.Set("channel", bson.M{"$in": []interface{}{order.ChannelOther, nil}})
+	} else {
+		query.Equal(b, "channel", filters.Channel)
 	}
 
-	for _, statusCount := range result.ByStatus {
-		metrics.OrdersByStatus[statusCount.Status] = statusCount.Count
+	query.Equal(b, "products.id", filters.ProductID)
+	b.Regex("products.name", filters.ProductName)
+	query.Range(b, "total", filters.MinTotal, filters.MaxTotal)
+
+	dateFrom, dateTo := filters.ParseDateRange()
+	b.TimeRange("created_at", dateFrom, dateTo)
+
+	filter := b.Filter()
+	if filters.ExcludeCancelled {
+		// $nor leaves the status key free for the status filter
+		filter["$nor"] = []bson.M{{"status": order.StatusCancelled}}
+	}
+	if filters.PaymentStatus != nil {
+		appendAnd(filter, paymentStatusFilter(*filters.PaymentStatus))
+	}
+	if filters.Query != nil {
+		appendAnd(filter, quickSearchFilter(*filters.Query))
 	}
+	if filters.Search != nil {
+		applySearch(filter, *filters.Search)
+	}
+	return filter
+}
 
-	return metrics, nil
+// tenantFilter scopes the query to a company or sale point
+func tenantFilter(b *query.Builder, field string, value *string) {
+	if value != nil && *value == order.DefaultTenant {
+		// Orders created before tenants were tracked have no tenant fields
+		b.Set(field, bson.M{"$in": []interface{}{order.DefaultTenant, nil}})
+		return
+	}
+	query.Equal(b, field, value)
 }
 
-// applyFilters applies filters to the query
-func (r *orderMongoRepository) applyFilters(filter bson.M, filters order.OrderFilters) {
-	if filters.Status != nil {
-		filter["status"] = *filters.Status
+// minTextSearchLength is the shortest query served by the text index; shorter
+// queries (e.g. a table number or a few phone digits) fall back to $regex
+const minTextSearchLength = 4
+
+// applySearch adds the free text condition to the query.
+// Phone-like queries match customer phones whatever their formatting, instead of the text index.
+func applySearch(filter bson.M, query string) {
+	if digits, ok := order.PhoneSearchDigits(query); ok {
+		appendAnd(filter, bson.M{"$or": []bson.M{
+			{"customer.phone_normalized": bson.M{"$regex": regexp.QuoteMeta(digits)}},
+			{"customer.phone": bson.M{"$regex": regexp.QuoteMeta(query)}},
+		}})
+		return
+	}
+	if len([]rune(query)) >= minTextSearchLength {
+		filter["$text"] = bson.M{"$search": query}
+		return
 	}
 
-	if filters.SaleType != nil {
-		filter["sale_type"] = *filters.SaleType
+	pattern := bson.M{"$regex": regexp.QuoteMeta(query), "$options": "i"}
+	conditions := []bson.M{
+		{"customer.name": pattern},
+		{"customer.phone": pattern},
+		{"customer.phone_normalized": pattern},
+		{"shipping_address": pattern},
+		{"note": pattern},
+		{"notes.text": pattern},
+		{"products.name": pattern},
 	}
+	// Wrapped in $and so it never clashes with other $or conditions (e.g. batch cursors)
+	appendAnd(filter, bson.M{"$or": conditions})
+}
 
-	if filters.ProductID != nil {
-		filter["products.id"] = *filters.ProductID
+// quickSearchFilter matches the quick search of order listings. Queries starting with "ORD-" only
+// match codes by exact prefix, which the unique code index serves; other queries match customer
+// name and phone and product names anywhere, case-insensitively. Phone-like queries also match
+// the digits of normalized phones, so "300 123 4567" finds "+573001234567".
+func quickSearchFilter(query string) bson.M {
+	if prefix, ok := order.CodePrefixQuery(query); ok {
+		return bson.M{"code": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}}
 	}
 
-	if filters.ProductName != nil {
-		filter["products.name"] = bson.M{"$regex": *filters.ProductName, "$options": "i"}
+	pattern := bson.M{"$regex": regexp.QuoteMeta(query), "$options": "i"}
+	// Every branch has an index, so the $or never falls back to a collection scan
+	conditions := []bson.M{
+		{"customer.name": pattern},
+		{"customer.phone": pattern},
+		{"customer.phone_normalized": pattern},
+		{"products.name": pattern},
+	}
+	if digits, ok := order.PhoneSearchDigits(query); ok {
+		conditions = append(conditions, bson.M{"customer.phone_normalized": bson.M{"$regex": regexp.QuoteMeta(digits)}})
 	}
+	return bson.M{"$or": conditions}
+}
 
-	if filters.MinTotal != nil || filters.MaxTotal != nil {
-		totalFilter := bson.M{}
-		if filters.MinTotal != nil {
-			totalFilter["$gte"] = *filters.MinTotal
-		}
-		if filters.MaxTotal != nil {
-			totalFilter["$lte"] = *filters.MaxTotal
+// paymentStatusFilter matches a payment status. Orders stored before payment statuses existed
+// have none and match PENDING or RECEIPT_UPLOADED depending on their receipt, like Order.CurrentPaymentStatus.
+func paymentStatusFilter(status order.PaymentStatus) bson.M {
+	noReceipt := bson.M{"$in": []interface{}{nil, ""}}
+	switch status {
+	case order.PaymentPending:
+		return bson.M{"$or": []bson.M{
+			{"payment_status": status},
+			{"payment_status": bson.M{"$exists": false}, "payment_receipt_url": noReceipt},
+		}}
+	case order.PaymentReceiptUploaded:
+		return bson.M{"$or": []bson.M{
+			{"payment_status": status},
+			{"payment_status": bson.M{"$exists": false}, "payment_receipt_url": bson.M{"$nin": []interface{}{nil, ""}}},
+		}}
+	default:
+		return bson.M{"payment_status": status}
+	}
+}
+
+// appendAnd adds a condition to the filter's $and list
+func appendAnd(filter bson.M, condition bson.M) {
+	conditions, _ := filter["$and"].([]bson.M)
+	filter["$and"] = append(conditions, condition)
+}
+
+// FindBatch retrieves up to limit orders matching filters, oldest first, after the cursor (if any)
+func (r *orderMongoRepository) FindBatch(ctx context.Context, filters order.OrderFilters, after *order.BatchCursor, limit int) ([]*order.Order, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	filter := orderFilter(filters)
+
+	return r.findBatch(ctx, filter, after, limit)
+}
+
+// FindArchivable retrieves up to limit terminal orders created before olderThan, oldest first, after the cursor (if any)
+func (r *orderMongoRepository) FindArchivable(ctx context.Context, olderThan time.Time, after *order.BatchCursor, limit int) ([]*order.Order, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"status":     bson.M{"$in": order.TerminalStatuses},
+		"created_at": bson.M{"$lt": olderThan},
+	}
+
+	return r.findBatch(ctx, filter, after, limit)
+}
+
+// findBatch walks the orders matching filter in ascending (created_at, _id) order
+func (r *orderMongoRepository) findBatch(ctx context.Context, filter bson.M, after *order.BatchCursor, limit int) ([]*order.Order, error) {
+	if after != nil {
+		filter["$or"] = []bson.M{
+			{"created_at": bson.M{"$gt": after.CreatedAt}},
+			{"created_at": after.CreatedAt, "_id": bson.M{"$gt": after.ID}},
 		}
-		filter["total"] = totalFilter
 	}
 
-	if filters.DateFrom != nil || filters.DateTo != nil {
-		dateFilter := bson.M{}
-		if filters.DateFrom != nil {
-			if t, err := time.Parse(time.RFC3339, *filters.DateFrom); err == nil {
-				dateFilter["$gte"] = t
-			}
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	orders := make([]*order.Order, 0, limit)
+	for cursor.Next(ctx) {
+		var o order.Order
+		if err := cursor.Decode(&o); err != nil {
+			return nil, fmt.Errorf("failed to decode order: %w", err)
 		}
-		if filters.DateTo != nil {
-			if t, err := time.Parse(time.RFC3339, *filters.DateTo); err == nil {
-				dateFilter["$lte"] = t
-			}
+		orders = append(orders, &o)
+
+		// Stop between batches when the request was cancelled
+		if err := checkBatch(ctx, cursor); err != nil {
+			return nil, fmt.Errorf("batch scan interrupted: %w", err)
 		}
-		if len(dateFilter) > 0 {
-			filter["created_at"] = dateFilter
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, wrapError(ctx, "cursor error", err)
+	}
+
+	return orders, nil
+}
+
+// ApplyTotalFixes updates stored totals, subtotals and discount amounts in bulk and appends the adjustment to each order's audit trail
+func (r *orderMongoRepository) ApplyTotalFixes(ctx context.Context, fixes []order.TotalFix) (int64, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	models := make([]mongo.WriteModel, 0, len(fixes))
+	for _, fix := range fixes {
+		models = append(models, mongo.NewUpdateOneModel().
+			// Only update if the total was not changed since it was read
+			SetFilter(bson.M{"_id": fix.OrderID, "total": fix.Adjustment.PreviousTotal}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"total":           fix.Adjustment.NewTotal,
+					"subtotal":        fix.Subtotal,
+					"discount_amount": fix.DiscountAmount,
+					"updated_at":      fix.Adjustment.AdjustedAt,
+				},
+				"$push": bson.M{"total_adjustments": fix.Adjustment},
+				"$inc":  bson.M{"version": 1},
+			}))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply total fixes: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// ArchiveOrders copies the orders to the archive collection and then removes them from the active one.
+// A retry after a partial failure replaces the copies already in the archive with the current orders.
+func (r *orderMongoRepository) ArchiveOrders(ctx context.Context, orders []*order.Order) (int64, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	ids := make([]string, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+
+	_, err := r.archive.BulkWrite(ctx, archiveWrites(orders, time.Now()), options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, wrapError(ctx, "failed to copy orders to archive", err)
+	}
+
+	// Only remove orders that are still terminal
+	result, err := r.collection.DeleteMany(ctx, bson.M{
+		"_id":    bson.M{"$in": ids},
+		"status": bson.M{"$in": order.TerminalStatuses},
+	})
+	if err != nil {
+		return 0, wrapError(ctx, "failed to remove archived orders", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// FindArchivedByCode retrieves an archived order by its tracking code
+func (r *orderMongoRepository) FindArchivedByCode(ctx context.Context, code string) (*order.Order, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var o order.Order
+	err := r.archiveReads.forRead(ctx).FindOne(ctx, bson.M{"code": code}).Decode(&o)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, order.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to find archived order: %w", err)
+	}
+
+	return &o, nil
+}
+
+// FindReferencedProductIDs returns which of the product IDs appear in orders created since the given time
+func (r *orderMongoRepository) FindReferencedProductIDs(ctx context.Context, productIDs []string, since time.Time) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	values, err := r.collection.Distinct(ctx, "products.id", bson.M{
+		"products.id": bson.M{"$in": productIDs},
+		"created_at":  bson.M{"$gte": since},
+	})
+	if err != nil {
+		return nil, wrapError(ctx, "failed to find referenced products", err)
+	}
+
+	// Distinct returns every product ID of the matching orders, keep only the requested ones
+	requested := make(map[string]bool, len(productIDs))
+	for _, id := range productIDs {
+		requested[id] = true
+	}
+	referenced := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok && requested[id] {
+			referenced = append(referenced, id)
 		}
 	}
+	sort.Strings(referenced)
+	return referenced, nil
+}
+
+// CountOpenOrdersWithProduct counts the open orders containing the product through the products.id index
+func (r *orderMongoRepository) CountOpenOrdersWithProduct(ctx context.Context, productID string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.collection.CountDocuments(ctx, openOrdersWithProduct(productID))
+	if err != nil {
+		return 0, wrapError(ctx, "failed to count open orders", err)
+	}
+	return count, nil
+}
+
+// openOrdersWithProduct filters the orders in a non-terminal status that contain the product
+func openOrdersWithProduct(productID string) bson.M {
+	return bson.M{
+		"products.id": productID,
+		"status":      bson.M{"$in": order.OpenStatuses},
+	}
+}
+
+// archiveWrites builds one upsert per order, so a copy left by an earlier failed run is replaced
+// with the order as it is now instead of keeping the stale one
+func archiveWrites(orders []*order.Order, now time.Time) []mongo.WriteModel {
+	writes := make([]mongo.WriteModel, len(orders))
+	for i, o := range orders {
+		archived := *o
+		archived.ArchivedAt = &now
+		writes[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": o.ID}).
+			SetReplacement(&archived).
+			SetUpsert(true)
+	}
+	return writes
 }
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestOpenOrdersWithProduct(t *testing.T) {
+	want := bson.M{
+		"products.id": "p1",
+		"status": bson.M{"$in": []order.OrderStatus{
+			order.StatusCreated, order.StatusVerified, order.StatusInProgress, order.StatusOutForDelivery,
+		}},
+	}
+	if got := openOrdersWithProduct("p1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("filter = %v, want %v", got, want)
+	}
+}
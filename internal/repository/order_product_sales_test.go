@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// stageValue returns the value of the first pipeline stage with the given operator
+func stageValue(t *testing.T, pipeline mongo.Pipeline, operator string) interface{} {
+	t.Helper()
+	for _, stage := range pipeline {
+		if stage[0].Key == operator {
+			return stage[0].Value
+		}
+	}
+	t.Fatalf("pipeline has no %s stage", operator)
+	return nil
+}
+
+func TestProductSalesPipelineMatch(t *testing.T) {
+	delivered := order.StatusDelivered
+	cancelled := order.StatusCancelled
+	onSite := order.SaleTypeOnSite
+
+	tests := []struct {
+		name    string
+		filters order.OrderFilters
+		want    bson.M
+	}{
+		{"cancelled orders excluded by default", order.OrderFilters{}, bson.M{"status": bson.M{"$ne": order.StatusCancelled}}},
+		{"status filter wins", order.OrderFilters{Status: &delivered}, bson.M{"status": order.StatusDelivered}},
+		{"cancelled on request", order.OrderFilters{Status: &cancelled}, bson.M{"status": order.StatusCancelled}},
+		{
+			"standard filters",
+			order.OrderFilters{SaleType: &onSite},
+			bson.M{"sale_type": order.SaleTypeOnSite, "status": bson.M{"$ne": order.StatusCancelled}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := productSalesPipeline(tt.filters, order.SortByRevenue, "orders_archive")
+			if got := stageValue(t, pipeline, "$match"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("$match = %v, want %v", got, tt.want)
+			}
+			if pipeline[0][0].Key != "$match" {
+				t.Errorf("first stage = %s, want $match", pipeline[0][0].Key)
+			}
+		})
+	}
+}
+
+func TestProductSalesPipelineIncludesArchive(t *testing.T) {
+	pipeline := productSalesPipeline(order.OrderFilters{IncludeArchived: true}, order.SortByRevenue, "orders_archive")
+	if pipeline[0][0].Key != "$unionWith" {
+		t.Fatalf("first stage = %s, want $unionWith", pipeline[0][0].Key)
+	}
+	if got := pipeline[0][0].Value; !reflect.DeepEqual(got, bson.M{"coll": "orders_archive"}) {
+		t.Errorf("$unionWith = %v", got)
+	}
+	// The filters apply to archived orders too
+	if pipeline[1][0].Key != "$match" {
+		t.Errorf("second stage = %s, want $match", pipeline[1][0].Key)
+	}
+}
+
+func TestProductSalesPipelinePage(t *testing.T) {
+	tests := []struct {
+		sortBy    order.ProductSalesSort
+		wantField string
+	}{
+		{order.SortByRevenue, "total_revenue"},
+		{order.SortByQuantity, "total_quantity"},
+		{order.SortByOrders, "order_count"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sortBy), func(t *testing.T) {
+			pipeline := productSalesPipeline(order.OrderFilters{Limit: 50, Offset: 100}, tt.sortBy, "orders_archive")
+			facet := stageValue(t, pipeline, "$facet").(bson.M)
+			rows := facet["rows"].([]bson.M)
+
+			wantSort := bson.D{{Key: tt.wantField, Value: -1}, {Key: "_id", Value: 1}}
+			if got := rows[0]["$sort"]; !reflect.DeepEqual(got, wantSort) {
+				t.Errorf("$sort = %v, want %v", got, wantSort)
+			}
+			if rows[1]["$skip"] != 100 || rows[2]["$limit"] != 50 {
+				t.Errorf("page = %v %v, want $skip 100 and $limit 50", rows[1], rows[2])
+			}
+			if _, ok := facet["total"]; !ok {
+				t.Error("the total count facet is missing")
+			}
+		})
+	}
+}
+
+func TestProductSalesPipelineGroupsPerOrderFirst(t *testing.T) {
+	pipeline := productSalesPipeline(order.OrderFilters{}, order.SortByRevenue, "orders_archive")
+
+	var groups []bson.M
+	for _, stage := range pipeline {
+		if stage[0].Key == "$group" {
+			groups = append(groups, stage[0].Value.(bson.M))
+		}
+	}
+	if len(groups) != 2 {
+		t.Fatalf("got %d $group stages, want 2", len(groups))
+	}
+	// Order count counts orders, not lines: a product on two lines of one order is one order
+	if got := groups[0]["_id"]; !reflect.DeepEqual(got, bson.M{"order": "$_id", "product": "$products.id"}) {
+		t.Errorf("first $group _id = %v", got)
+	}
+	if got := groups[1]["order_count"]; !reflect.DeepEqual(got, bson.M{"$sum": 1}) {
+		t.Errorf("order_count = %v", got)
+	}
+}
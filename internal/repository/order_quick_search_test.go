@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestQuickSearchFilter(t *testing.T) {
+	anywhere := func(pattern string) bson.M {
+		p := bson.M{"$regex": pattern, "$options": "i"}
+		return bson.M{"$or": []bson.M{
+			{"customer.name": p},
+			{"customer.phone": p},
+			{"customer.phone_normalized": p},
+			{"products.name": p},
+		}}
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bson.M
+	}{
+		{"code prefix", "ORD-7F", bson.M{"code": bson.M{"$regex": "^ORD-7F"}}},
+		{"code prefix typed in lower case", "ord-7f", bson.M{"code": bson.M{"$regex": "^ORD-7F"}}},
+		{"bare code prefix", "ORD-", bson.M{"code": bson.M{"$regex": "^ORD-"}}},
+		{"legacy code prefix", "ORD-1716412345123456789-A1", bson.M{"code": bson.M{"$regex": "^ORD-1716412345123456789-a1"}}},
+		{"customer or product", "Ana", anywhere("Ana")},
+		{"phone digits", "300 123", anywhere("300 123")},
+		{"regex characters quoted", "taco (xl)+", anywhere(`taco \(xl\)\+`)},
+		{"code prefix characters quoted", "ORD-7F.*", bson.M{"code": bson.M{"$regex": `^ORD-7F\.\*`}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quickSearchFilter(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestQuickSearchCodePrefixUsesTheIndex stands in for an explain plan: MongoDB turns a regex into
+// index bounds on the code index only when it is anchored, case-sensitive and has a literal prefix.
+func TestQuickSearchCodePrefixUsesTheIndex(t *testing.T) {
+	literalPrefix := regexp.MustCompile(`^\^ORD-[0-9A-Za-z-]*`)
+
+	for _, query := range []string{"ORD-7F", "ord-7", "ORD-", "ORD-1716412345123456789-a"} {
+		condition, ok := quickSearchFilter(query)["code"].(bson.M)
+		if !ok {
+			t.Errorf("%q does not filter on code alone: %v", query, quickSearchFilter(query))
+			continue
+		}
+		pattern, _ := condition["$regex"].(string)
+		if !literalPrefix.MatchString(pattern) {
+			t.Errorf("%q pattern %q is not an anchored literal prefix", query, pattern)
+		}
+		if _, ok := condition["$options"]; ok {
+			t.Errorf("%q pattern has options %v, which prevent tight index bounds", query, condition["$options"])
+		}
+	}
+}
+
+func TestQuickSearchCombinesWithFilters(t *testing.T) {
+	status := order.StatusCreated
+	query := "Ana"
+	got := orderFilter(order.OrderFilters{Status: &status, Query: &query})
+
+	if got["status"] != order.StatusCreated {
+		t.Errorf("status filter lost: %v", got)
+	}
+	and, ok := got["$and"].([]bson.M)
+	if !ok || len(and) != 1 || !reflect.DeepEqual(and[0], quickSearchFilter(query)) {
+		t.Errorf("$and = %v, want the quick search", got["$and"])
+	}
+}
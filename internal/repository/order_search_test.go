@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestApplySearch(t *testing.T) {
+	regexOn := func(pattern string) bson.M {
+		p := bson.M{"$regex": pattern, "$options": "i"}
+		return bson.M{"$and": []bson.M{{"$or": []bson.M{
+			{"customer.name": p},
+			{"customer.phone": p},
+			{"customer.phone_normalized": p},
+			{"shipping_address": p},
+			{"note": p},
+			{"notes.text": p},
+			{"products.name": p},
+		}}}}
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bson.M
+	}{
+		{"long query uses the text index", "maria calle", bson.M{"$text": bson.M{"$search": "maria calle"}}},
+		{"short query falls back to a regex", "ana", regexOn("ana")},
+		{"regex metacharacters are escaped", "a.*", regexOn(`a\.\*`)},
+		{"phone in any format", "+57 300-123-4567", bson.M{"$and": []bson.M{{"$or": []bson.M{
+			{"customer.phone_normalized": bson.M{"$regex": "573001234567"}},
+			{"customer.phone": bson.M{"$regex": `\+57 300-123-4567`}},
+		}}}}},
+		{"partial phone with separators", "(300) 123.4", bson.M{"$and": []bson.M{{"$or": []bson.M{
+			{"customer.phone_normalized": bson.M{"$regex": "3001234"}},
+			{"customer.phone": bson.M{"$regex": `\(300\) 123\.4`}},
+		}}}}},
+		{"00 prefix dropped", "0057 300 123 4567", bson.M{"$and": []bson.M{{"$or": []bson.M{
+			{"customer.phone_normalized": bson.M{"$regex": "573001234567"}},
+			{"customer.phone": bson.M{"$regex": "0057 300 123 4567"}},
+		}}}}},
+		{"too few digits for a phone", "123 45", bson.M{"$text": bson.M{"$search": "123 45"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := bson.M{}
+			applySearch(filter, tt.query)
+			if !reflect.DeepEqual(filter, tt.want) {
+				t.Errorf("filter = %#v, want %#v", filter, tt.want)
+			}
+		})
+	}
+}
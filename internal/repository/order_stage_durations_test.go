@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDecodeMetricsStageDurations(t *testing.T) {
+	type want struct {
+		count      int64
+		avgSeconds int64
+	}
+	tests := []struct {
+		name      string
+		durations bson.M
+		want      map[string]want // By stage key; stages missing here are empty
+	}{
+		{
+			name: "every stage measured",
+			durations: bson.M{
+				"created_to_verified_ms": 90000.0, "created_to_verified_count": int32(4),
+				"verified_to_in_progress_ms": int64(300000), "verified_to_in_progress_count": int64(4),
+				"in_progress_to_out_for_delivery_ms": 900000.0, "in_progress_to_out_for_delivery_count": 2,
+				"out_for_delivery_to_delivered_ms": 1200000.0, "out_for_delivery_to_delivered_count": 2,
+			},
+			want: map[string]want{
+				"created_to_verified":             {4, 90},
+				"verified_to_in_progress":         {4, 300},
+				"in_progress_to_out_for_delivery": {2, 900},
+				"out_for_delivery_to_delivered":   {2, 1200},
+			},
+		},
+		{
+			name:      "rounded to the nearest second",
+			durations: bson.M{"created_to_verified_ms": 1499.9, "created_to_verified_count": 3, "verified_to_in_progress_ms": 1500.0, "verified_to_in_progress_count": 3},
+			want:      map[string]want{"created_to_verified": {3, 1}, "verified_to_in_progress": {3, 2}},
+		},
+		{
+			name: "stages skipped by every order",
+			durations: bson.M{
+				"created_to_verified_ms": 60000.0, "created_to_verified_count": 5,
+				"in_progress_to_out_for_delivery_ms": nil, "in_progress_to_out_for_delivery_count": 0,
+			},
+			want: map[string]want{"created_to_verified": {5, 60}},
+		},
+		{
+			name:      "average without orders ignored",
+			durations: bson.M{"created_to_verified_ms": 60000.0, "created_to_verified_count": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := decodeMetrics(rawDoc(t, bson.M{"stage_durations": bson.A{tt.durations}}))
+			if len(m.StageDurations) != len(order.Stages) {
+				t.Fatalf("got %d stage durations, want one per stage", len(m.StageDurations))
+			}
+			for i, d := range m.StageDurations {
+				if d.Stage != order.Stages[i] {
+					t.Errorf("stage %d = %v, want %v", i, d.Stage, order.Stages[i])
+				}
+				w := tt.want[d.Key()]
+				if d.OrderCount != w.count || d.AvgSeconds != w.avgSeconds {
+					t.Errorf("%s = %d orders, %ds; want %d orders, %ds", d.Key(), d.OrderCount, d.AvgSeconds, w.count, w.avgSeconds)
+				}
+			}
+		})
+	}
+}
+
+func TestStageDurationsFacet(t *testing.T) {
+	facet := stageDurationsFacet()
+	if len(facet) != 1 {
+		t.Fatalf("facet has %d stages, want one $group", len(facet))
+	}
+	group, ok := facet[0]["$group"].(bson.M)
+	if !ok {
+		t.Fatalf("facet = %v, want a $group", facet)
+	}
+
+	for _, stage := range order.Stages {
+		from, to := "$"+statusTimeFields[stage.From], "$"+statusTimeFields[stage.To]
+		measured := bson.M{"$and": []interface{}{
+			bson.M{"$eq": []interface{}{bson.M{"$type": from}, "date"}},
+			bson.M{"$eq": []interface{}{bson.M{"$type": to}, "date"}},
+			bson.M{"$gte": []interface{}{to, from}},
+		}}
+
+		// Orders without both times average as null, which $avg skips, instead of as zero
+		wantAvg := bson.M{"$avg": bson.M{"$cond": []interface{}{measured, bson.M{"$subtract": []interface{}{to, from}}, nil}}}
+		if got := group[stage.Key()+"_ms"]; !reflect.DeepEqual(got, wantAvg) {
+			t.Errorf("%s_ms = %v, want %v", stage.Key(), got, wantAvg)
+		}
+		wantCount := bson.M{"$sum": bson.M{"$cond": []interface{}{measured, 1, 0}}}
+		if got := group[stage.Key()+"_count"]; !reflect.DeepEqual(got, wantCount) {
+			t.Errorf("%s_count = %v, want %v", stage.Key(), got, wantCount)
+		}
+	}
+}
+
+// TestStatusTimeFieldsMatchTheEntity checks the aggregation reads the fields the orders are stored with
+func TestStatusTimeFieldsMatchTheEntity(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	o := &order.Order{ID: "order-1", CreatedAt: at, VerifiedAt: &at, InProgressAt: &at, OutForDeliveryAt: &at, DeliveredAt: &at, CancelledAt: &at}
+	raw, err := bson.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for status, field := range statusTimeFields {
+		value, err := bson.Raw(raw).LookupErr(field)
+		if err != nil || value.Type != bson.TypeDateTime {
+			t.Errorf("%s is stored as %q: %v %v", status, field, value.Type, err)
+		}
+	}
+}
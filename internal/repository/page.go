@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// pagePipeline lists one page of the documents matching filter and counts all of them with a single
+// $facet aggregation, so the page and the total come from the same snapshot of the collection.
+// The count is left out when skipCount is set.
+func pagePipeline(filter bson.M, sort bson.D, offset, limit int, skipCount bool) mongo.Pipeline {
+	rows := []bson.M{{"$sort": sort}}
+	if offset > 0 {
+		rows = append(rows, bson.M{"$skip": offset})
+	}
+	if limit > 0 {
+		rows = append(rows, bson.M{"$limit": limit})
+	}
+	facets := bson.M{"rows": rows}
+	if !skipCount {
+		facets["total"] = []bson.M{{"$count": "count"}}
+	}
+
+	// $match comes first so a $text search can use the text index
+	return mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: facets}},
+	}
+}
+
+// pageResult is the document returned by a pagePipeline
+type pageResult[T any] struct {
+	Rows  []T `bson:"rows"`
+	Total []struct {
+		Count int64 `bson:"count"`
+	} `bson:"total"`
+}
+
+// decodePage returns the rows and the total of a pagePipeline result.
+// $count emits nothing when no document matches, so a missing count is 0; the total is -1 when skipCount is set.
+func decodePage[T any](results []pageResult[T], skipCount bool) ([]T, int64) {
+	var (
+		rows  []T
+		total int64 = -1
+	)
+	if !skipCount {
+		total = 0
+	}
+	if len(results) > 0 {
+		rows = results[0].Rows
+		if len(results[0].Total) > 0 {
+			total = results[0].Total[0].Count
+		}
+	}
+	return rows, total
+}
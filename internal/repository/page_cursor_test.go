@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/repository/query"
+	"github.com/emerarteaga/products-api/internal/util"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestProductPage(t *testing.T) {
+	createdAt := time.Date(2024, 6, 1, 12, 0, 0, 123000000, time.UTC)
+	last := (&product.Product{ID: "p9", CreatedAt: createdAt}).PageCursor()
+	tieBroken := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+
+	tests := []struct {
+		name       string
+		filters    product.ProductFilters
+		wantFilter bson.M
+		wantSort   bson.D
+		wantErr    error
+	}{
+		{
+			name:       "offset pagination",
+			filters:    product.ProductFilters{},
+			wantFilter: bson.M{"company_id": "c1", "deleted_at": liveProduct},
+			wantSort:   bson.D{{Key: "created_at", Value: -1}},
+		},
+		{
+			name:       "first cursor page",
+			filters:    product.ProductFilters{Cursor: &util.PageCursor{}},
+			wantFilter: bson.M{"company_id": "c1", "deleted_at": liveProduct},
+			wantSort:   tieBroken,
+		},
+		{
+			name:    "next cursor page",
+			filters: product.ProductFilters{Cursor: &last, IsAvailable: ptr(true)},
+			wantFilter: bson.M{"company_id": "c1", "deleted_at": liveProduct, "is_available": true, "$and": []bson.M{{"$or": []bson.M{
+				{"created_at": bson.M{"$lt": createdAt}},
+				{"created_at": createdAt, "_id": bson.M{"$lt": "p9"}},
+			}}}},
+			wantSort: tieBroken,
+		},
+		{
+			name:    "cursor of another listing",
+			filters: product.ProductFilters{Cursor: &util.PageCursor{Field: "total", Value: "100", ID: "o1"}},
+			wantErr: util.ErrInvalidCursor,
+		},
+		{
+			name:    "ranked search",
+			filters: product.ProductFilters{Cursor: &util.PageCursor{}, Search: ptr("arepa")},
+			wantErr: util.ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, sort, err := productPage(bson.M{"company_id": "c1"}, tt.filters, query.NewestFirst)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(filter, tt.wantFilter) {
+				t.Errorf("filter = %v, want %v", filter, tt.wantFilter)
+			}
+			if !reflect.DeepEqual(sort, tt.wantSort) {
+				t.Errorf("sort = %v, want %v", sort, tt.wantSort)
+			}
+		})
+	}
+}
+
+func TestOrderPage(t *testing.T) {
+	o := &order.Order{ID: "o9", Total: 45000, Status: order.StatusVerified, CreatedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name     string
+		filters  order.OrderFilters
+		wantSeek bson.M
+		wantErr  error
+	}{
+		{
+			name:     "newest first",
+			filters:  order.OrderFilters{Cursor: ptr(o.PageCursor(""))},
+			wantSeek: bson.M{"$or": []bson.M{{"created_at": bson.M{"$lt": o.CreatedAt}}, {"created_at": o.CreatedAt, "_id": bson.M{"$lt": "o9"}}}},
+		},
+		{
+			name:     "by total ascending",
+			filters:  order.OrderFilters{SortBy: order.SortFieldTotal, SortDir: order.SortAsc, Cursor: ptr(o.PageCursor(order.SortFieldTotal))},
+			wantSeek: bson.M{"$or": []bson.M{{"total": bson.M{"$gt": int64(45000)}}, {"total": int64(45000), "_id": bson.M{"$gt": "o9"}}}},
+		},
+		{
+			name:     "by status",
+			filters:  order.OrderFilters{SortBy: order.SortFieldStatus, Cursor: ptr(o.PageCursor(order.SortFieldStatus))},
+			wantSeek: bson.M{"$or": []bson.M{{"status": bson.M{"$lt": "VERIFIED"}}, {"status": "VERIFIED", "_id": bson.M{"$lt": "o9"}}}},
+		},
+		{
+			name:    "sort changed since the cursor",
+			filters: order.OrderFilters{SortBy: order.SortFieldTotal, Cursor: ptr(o.PageCursor(""))},
+			wantErr: util.ErrInvalidCursor,
+		},
+		{
+			name:    "garbled value",
+			filters: order.OrderFilters{SortBy: order.SortFieldTotal, Cursor: &util.PageCursor{Field: "total", Value: "lots", ID: "o9"}},
+			wantErr: util.ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, _, err := orderPage(tt.filters)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := filter["$and"]; !reflect.DeepEqual(got, []bson.M{tt.wantSeek}) {
+				t.Errorf("seek = %v, want %v", got, tt.wantSeek)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/repository/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPagePipeline(t *testing.T) {
+	filter := bson.M{"status": "CREATED"}
+	count := []bson.M{{"$count": "count"}}
+
+	tests := []struct {
+		name      string
+		offset    int
+		limit     int
+		skipCount bool
+		want      bson.M // The $facet stage
+	}{
+		{"first page", 0, 20, false, bson.M{
+			"rows":  []bson.M{{"$sort": query.NewestFirst}, {"$limit": 20}},
+			"total": count,
+		}},
+		{"later page", 40, 20, false, bson.M{
+			"rows":  []bson.M{{"$sort": query.NewestFirst}, {"$skip": 40}, {"$limit": 20}},
+			"total": count,
+		}},
+		{"count skipped", 40, 20, true, bson.M{
+			"rows": []bson.M{{"$sort": query.NewestFirst}, {"$skip": 40}, {"$limit": 20}},
+		}},
+		{"no limit", 0, 0, false, bson.M{
+			"rows":  []bson.M{{"$sort": query.NewestFirst}},
+			"total": count,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := pagePipeline(filter, query.NewestFirst, tt.offset, tt.limit, tt.skipCount)
+			if len(pipeline) != 2 {
+				t.Fatalf("pipeline has %d stages, want $match and $facet", len(pipeline))
+			}
+			if stage := pipeline[0][0]; stage.Key != "$match" || !reflect.DeepEqual(stage.Value, filter) {
+				t.Errorf("first stage = %v, want $match %v", stage, filter)
+			}
+			if stage := pipeline[1][0]; stage.Key != "$facet" || !reflect.DeepEqual(stage.Value, tt.want) {
+				t.Errorf("second stage = %v, want $facet %v", stage, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePage(t *testing.T) {
+	rows := bson.A{bson.M{"_id": "order-1", "code": "ORD-000001"}, bson.M{"_id": "order-2", "code": "ORD-000002"}}
+
+	tests := []struct {
+		name      string
+		doc       bson.M // nil when the aggregation returned no document
+		skipCount bool
+		wantCodes []string
+		wantTotal int64
+	}{
+		{"page of a larger total", bson.M{"rows": rows, "total": bson.A{bson.M{"count": 42}}}, false, []string{"ORD-000001", "ORD-000002"}, 42},
+		{"offset past the end", bson.M{"rows": bson.A{}, "total": bson.A{bson.M{"count": 42}}}, false, nil, 42},
+		{"nothing matched", bson.M{"rows": bson.A{}, "total": bson.A{}}, false, nil, 0},
+		{"count skipped", bson.M{"rows": rows}, true, []string{"ORD-000001", "ORD-000002"}, -1},
+		{"no document", nil, false, nil, 0},
+		{"no document with the count skipped", nil, true, nil, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results []pageResult[*order.Order]
+			if tt.doc != nil {
+				var result pageResult[*order.Order]
+				if err := bson.Unmarshal(rawDoc(t, tt.doc), &result); err != nil {
+					t.Fatal(err)
+				}
+				results = append(results, result)
+			}
+
+			orders, total := decodePage(results, tt.skipCount)
+			var codes []string
+			for _, o := range orders {
+				codes = append(codes, o.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// productBarcodeIndex keeps barcodes unique within a company
+const productBarcodeIndex = "company_id_barcode_unique"
+
+// productBarcodeIndexModel returns the unique barcode index, which also serves barcode lookups. Like the
+// SKU index, it only covers live products with a barcode.
+func productBarcodeIndexModel() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "company_id", Value: 1},
+			{Key: "barcode", Value: 1},
+		},
+		Options: options.Index().
+			SetName(productBarcodeIndex).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{
+				"barcode":    bson.M{"$type": "string"},
+				"deleted_at": liveProduct,
+			}),
+	}
+}
+
+// isDuplicateBarcode reports whether a write failed on the unique barcode index
+func isDuplicateBarcode(err error) bool {
+	return mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), productBarcodeIndex)
+}
+
+// duplicateProductError returns the domain error of a write that failed on a unique product index, nil otherwise
+func duplicateProductError(err error) error {
+	switch {
+	case isDuplicateSKU(err):
+		return product.ErrDuplicateSKU
+	case isDuplicateBarcode(err):
+		return product.ErrDuplicateBarcode
+	}
+	return nil
+}
+
+// FindByBarcode finds the live product of a company with the given normalized barcode
+func (r *productMongoRepository) FindByBarcode(ctx context.Context, companyID, barcode string) (*product.Product, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"company_id": companyID, "barcode": barcode, "deleted_at": liveProduct}
+	var p product.Product
+	if err := r.reads.forRead(ctx).FindOne(ctx, filter).Decode(&p); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, product.ErrProductNotFound
+		}
+		return nil, wrapError(ctx, "failed to find product by barcode", err)
+	}
+
+	return &p, nil
+}
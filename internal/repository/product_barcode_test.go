@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestProductBarcodeIndexModel(t *testing.T) {
+	model := productBarcodeIndexModel()
+
+	wantKeys := bson.D{{Key: "company_id", Value: 1}, {Key: "barcode", Value: 1}}
+	if !reflect.DeepEqual(model.Keys, wantKeys) {
+		t.Errorf("keys = %v, want %v", model.Keys, wantKeys)
+	}
+	if model.Options.Unique == nil || !*model.Options.Unique {
+		t.Error("index is not unique")
+	}
+
+	wantFilter := bson.M{"barcode": bson.M{"$type": "string"}, "deleted_at": liveProduct}
+	if !reflect.DeepEqual(model.Options.PartialFilterExpression, wantFilter) {
+		t.Errorf("partial filter = %v, want %v", model.Options.PartialFilterExpression, wantFilter)
+	}
+}
+
+func TestDuplicateProductError(t *testing.T) {
+	duplicate := func(message string) error {
+		return mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: message}}}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"sku index", duplicate("E11000 duplicate key error collection: db.products index: sale_point_id_sku_unique dup key"), product.ErrDuplicateSKU},
+		{"barcode index", duplicate("E11000 duplicate key error collection: db.products index: company_id_barcode_unique dup key"), product.ErrDuplicateBarcode},
+		{"id index", duplicate("E11000 duplicate key error collection: db.products index: _id_ dup key"), nil},
+		{"other error", errors.New("company_id_barcode_unique"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := duplicateProductError(tt.err); got != tt.want {
+				t.Errorf("duplicateProductError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
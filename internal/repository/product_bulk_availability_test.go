@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAvailabilityFilter(t *testing.T) {
+	fritos := "Fritos"
+
+	tests := []struct {
+		name     string
+		selector product.AvailabilitySelector
+		want     bson.M
+	}{
+		{
+			name:     "category of a sale point",
+			selector: product.AvailabilitySelector{SalePointID: "sp1", Category: &fritos},
+			want:     bson.M{"sale_point_id": "sp1", "category": "Fritos", "deleted_at": liveProduct},
+		},
+		{
+			name:     "ids",
+			selector: product.AvailabilitySelector{IDs: []string{"p1", "p2"}},
+			want:     bson.M{"_id": bson.M{"$in": []string{"p1", "p2"}}, "deleted_at": liveProduct},
+		},
+		{
+			name:     "ids within a sale point",
+			selector: product.AvailabilitySelector{IDs: []string{"p1"}, SalePointID: "sp1"},
+			want:     bson.M{"_id": bson.M{"$in": []string{"p1"}}, "sale_point_id": "sp1", "deleted_at": liveProduct},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := availabilityFilter(tt.selector); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
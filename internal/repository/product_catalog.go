@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// productCatalog exposes the products collection to the order domain
+type productCatalog struct {
+	collection *mongo.Collection
+}
+
+// NewProductCatalog creates the product lookup used by the order service
+func NewProductCatalog(collection *mongo.Collection) order.Catalog {
+	return &productCatalog{collection: collection}
+}
+
+// FindCatalogProducts returns the current state of the products that still exist, keyed by ID.
+// Soft deleted products are left out, so they cannot be ordered.
+// It reads from the primary: the result decides prices of a new order.
+func (c *productCatalog) FindCatalogProducts(ctx context.Context, ids []string) (map[string]order.CatalogProduct, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	projection := bson.M{"name": 1, "is_available": 1, "is_unlimited_stock": 1, "stock": 1, "availability_schedule": 1, "price_variations.type": 1, "price_variations.price": 1}
+	cursor, err := c.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": liveProduct}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find catalog products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var products []product.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("failed to decode catalog products: %w", err)
+	}
+
+	catalog := make(map[string]order.CatalogProduct, len(products))
+	for _, p := range products {
+		prices := make([]int64, len(p.PriceVariations))
+		variations := make(map[string]int64, len(p.PriceVariations))
+		for i, v := range p.PriceVariations {
+			prices[i] = v.Price
+			variations[v.Type] = v.Price
+		}
+		catalog[p.ID] = order.CatalogProduct{
+			ID:         p.ID,
+			Name:       p.Name,
+			Available:  p.Availability().Available,
+			Prices:     prices,
+			Variations: variations,
+		}
+	}
+	return catalog, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestSummaryPipeline(t *testing.T) {
+	match := mongo.Pipeline{{{Key: "$match", Value: bson.M{"company_id": "c1"}}}}
+
+	tests := []struct {
+		name          string
+		filters       product.CategoryFilters
+		wantAvailable bool     // A $match drops groups without available products
+		wantPage      []bson.M // The $skip and $limit of the rows facet
+	}{
+		{"page", product.CategoryFilters{Limit: 20, Offset: 40}, false, []bson.M{{"$skip": 40}, {"$limit": 20}}},
+		{"only with available", product.CategoryFilters{OnlyWithAvailable: true, Limit: 20}, true, []bson.M{{"$skip": 0}, {"$limit": 20}}},
+		{"all categories", product.CategoryFilters{All: true}, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := summaryPipeline(match, "category", tt.filters)
+
+			if !reflect.DeepEqual(pipeline[0], match[0]) {
+				t.Errorf("first stage = %v, want the caller's $match", pipeline[0])
+			}
+			group := stageValue(t, pipeline, "$group").(bson.M)
+			if group["_id"] != "$category" {
+				t.Errorf("grouped by %v, want $category", group["_id"])
+			}
+
+			availableMatch := bson.D{{Key: "$match", Value: bson.M{"available_count": bson.M{"$gt": 0}}}}
+			if got := reflect.DeepEqual(pipeline[2], availableMatch); got != tt.wantAvailable {
+				t.Errorf("available filter present = %v, want %v", got, tt.wantAvailable)
+			}
+
+			facet := stageValue(t, pipeline, "$facet").(bson.M)
+			rows := facet["rows"].([]bson.M)
+			if !reflect.DeepEqual(rows[0], bson.M{"$sort": bson.M{"_id": 1}}) {
+				t.Errorf("rows start with %v, want the name sort", rows[0])
+			}
+			if got := rows[1 : len(rows)-1]; !reflect.DeepEqual(got, tt.wantPage) && !(len(got) == 0 && tt.wantPage == nil) {
+				t.Errorf("page = %v, want %v", got, tt.wantPage)
+			}
+			if _, ok := facet["total"]; !ok {
+				t.Error("the total count facet is missing")
+			}
+		})
+	}
+}
+
+func TestSummaryPipelineKeepsCallerStages(t *testing.T) {
+	stages := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"tags": bson.M{"$type": "string"}}}},
+		{{Key: "$unwind", Value: "$tags"}},
+	}
+	pipeline := summaryPipeline(stages, "tags", product.CategoryFilters{All: true})
+
+	if pipeline[1][0].Key != "$unwind" || pipeline[2][0].Key != "$group" {
+		t.Errorf("stages = %v, want $match, $unwind, then $group", pipeline)
+	}
+	if group := pipeline[2][0].Value.(bson.M); group["_id"] != "$tags" {
+		t.Errorf("grouped by %v, want $tags", group["_id"])
+	}
+}
+
+func TestCategoryRenameFilter(t *testing.T) {
+	from := []string{"Bebidas", "drinks"}
+
+	tests := []struct {
+		name  string
+		scope product.CategoryScope
+		want  bson.M
+	}{
+		{
+			name:  "company",
+			scope: product.CategoryScope{CompanyID: "c1"},
+			want:  bson.M{"company_id": "c1", "category": bson.M{"$in": from, "$ne": "Drinks"}},
+		},
+		{
+			name:  "sale point",
+			scope: product.CategoryScope{CompanyID: "c1", SalePointID: "sp1"},
+			want:  bson.M{"company_id": "c1", "sale_point_id": "sp1", "category": bson.M{"$in": from, "$ne": "Drinks"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Soft deleted products are renamed too, so the filter has no deleted_at condition
+			if got := categoryRenameFilter(tt.scope, from, "Drinks"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// menuRow is a document of a menuPipeline: one category and the number of categories
+type menuRow struct {
+	product.MenuSection `bson:",inline"`
+	Total               int64 `bson:"total"`
+}
+
+// FindMenu groups the sale point's products by category with a single aggregation
+func (r *productMongoRepository) FindMenu(ctx context.Context, salePointID string, filters product.MenuFilters) ([]product.MenuSection, int64, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.reads.forRead(ctx).Aggregate(ctx, menuPipeline(salePointID, filters))
+	if err != nil {
+		return nil, 0, wrapError(ctx, "failed to find menu", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []menuRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, 0, wrapError(ctx, "failed to decode menu", err)
+	}
+	sections, total := decodeMenu(rows)
+	return sections, total, nil
+}
+
+// decodeMenu returns the sections of a menuPipeline result, never nil, and the number of categories.
+// A page past the last category has no row to carry the count, so its total is 0.
+func decodeMenu(rows []menuRow) ([]product.MenuSection, int64) {
+	sections := make([]product.MenuSection, len(rows))
+	var total int64
+	for i, row := range rows {
+		sections[i] = row.MenuSection
+		total = row.Total
+	}
+	return sections, total
+}
+
+// menuPipeline groups the sale point's live products by category in menu order, keeping the first
+// filters.ProductLimit of each, then sorts the categories by the position of their category entity,
+// matched ignoring case. Categories without an entity sort last, by name. The storefront menu only has
+// products customers can order, like product.Availability ignoring schedules, and drops inactive categories.
+func menuPipeline(salePointID string, filters product.MenuFilters) mongo.Pipeline {
+	match := bson.M{
+		"sale_point_id": salePointID,
+		"category":      bson.M{"$nin": bson.A{"", nil}},
+		"deleted_at":    liveProduct,
+	}
+	if !filters.IncludeUnavailable {
+		match["is_available"] = true
+		match["$or"] = bson.A{bson.M{"is_unlimited_stock": true}, bson.M{"stock": bson.M{"$gt": 0}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: menuOrder}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           "$category",
+			"product_count": bson.M{"$sum": 1},
+			// $firstN keeps the order of the $sort above
+			"products": bson.M{"$firstN": bson.M{"input": "$$ROOT", "n": filters.ProductLimit}},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": CategoriesCollection,
+			"let":  bson.M{"category": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"sale_point_id": salePointID,
+					"$expr":         bson.M{"$eq": bson.A{bson.M{"$toLower": "$name"}, bson.M{"$toLower": "$$category"}}},
+				}},
+				bson.M{"$project": bson.M{"position": 1, "is_active": 1}},
+			},
+			"as": "entity",
+		}}},
+		{{Key: "$set", Value: bson.M{"entity": bson.M{"$first": "$entity"}}}},
+	}
+	if !filters.IncludeUnavailable {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"entity.is_active": bson.M{"$ne": false}}}})
+	}
+
+	// Each category is its own document, so a large menu does not hit the document size limit; the
+	// window counts the categories before the page is cut
+	return append(pipeline,
+		bson.D{{Key: "$set", Value: bson.M{"entity_position": bson.M{"$ifNull": bson.A{"$entity.position", math.MaxInt32}}}}},
+		bson.D{{Key: "$setWindowFields", Value: bson.M{
+			"sortBy": bson.D{{Key: "entity_position", Value: 1}, {Key: "_id", Value: 1}},
+			"output": bson.M{"total": bson.M{"$count": bson.M{}}},
+		}}},
+		bson.D{{Key: "$skip", Value: filters.Offset}},
+		bson.D{{Key: "$limit", Value: filters.Limit}},
+		bson.D{{Key: "$project", Value: bson.M{"category": "$_id", "product_count": 1, "products": 1, "total": 1, "_id": 0}}},
+	)
+}
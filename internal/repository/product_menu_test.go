@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// stageKeys lists the operators of a pipeline's stages
+func stageKeys(pipeline mongo.Pipeline) []string {
+	keys := make([]string, len(pipeline))
+	for i, stage := range pipeline {
+		keys[i] = stage[0].Key
+	}
+	return keys
+}
+
+func TestMenuPipeline(t *testing.T) {
+	t.Run("storefront", func(t *testing.T) {
+		pipeline := menuPipeline("sp1", product.MenuFilters{ProductLimit: 5, Limit: 10, Offset: 20})
+
+		want := []string{"$match", "$sort", "$group", "$lookup", "$set", "$match", "$set", "$setWindowFields", "$skip", "$limit", "$project"}
+		if got := stageKeys(pipeline); !reflect.DeepEqual(got, want) {
+			t.Fatalf("stages = %v, want %v", got, want)
+		}
+
+		match := pipeline[0][0].Value.(bson.M)
+		if match["sale_point_id"] != "sp1" || match["is_available"] != true || match["$or"] == nil {
+			t.Errorf("match = %v, want the sale point's orderable products", match)
+		}
+		if !reflect.DeepEqual(pipeline[1][0].Value, menuOrder) {
+			t.Errorf("sort = %v, want menuOrder", pipeline[1][0].Value)
+		}
+		group := pipeline[2][0].Value.(bson.M)
+		if group["_id"] != "$category" || !reflect.DeepEqual(group["products"], bson.M{"$firstN": bson.M{"input": "$$ROOT", "n": 5}}) {
+			t.Errorf("group = %v, want the first 5 products of each category", group)
+		}
+		if lookup := pipeline[3][0].Value.(bson.M); lookup["from"] != CategoriesCollection {
+			t.Errorf("lookup from = %v, want %s", lookup["from"], CategoriesCollection)
+		}
+		if active := pipeline[5][0].Value.(bson.M); !reflect.DeepEqual(active, bson.M{"entity.is_active": bson.M{"$ne": false}}) {
+			t.Errorf("category match = %v, want inactive categories dropped", active)
+		}
+		if pipeline[8][0].Value != 20 || pipeline[9][0].Value != 10 {
+			t.Errorf("page = skip %v limit %v, want skip 20 limit 10", pipeline[8][0].Value, pipeline[9][0].Value)
+		}
+	})
+
+	t.Run("admin preview", func(t *testing.T) {
+		pipeline := menuPipeline("sp1", product.MenuFilters{IncludeUnavailable: true, ProductLimit: 5, Limit: 10})
+
+		want := []string{"$match", "$sort", "$group", "$lookup", "$set", "$set", "$setWindowFields", "$skip", "$limit", "$project"}
+		if got := stageKeys(pipeline); !reflect.DeepEqual(got, want) {
+			t.Fatalf("stages = %v, want %v", got, want)
+		}
+		wantMatch := bson.M{"sale_point_id": "sp1", "category": bson.M{"$nin": bson.A{"", nil}}, "deleted_at": liveProduct}
+		if !reflect.DeepEqual(pipeline[0][0].Value, wantMatch) {
+			t.Errorf("match = %v, want %v", pipeline[0][0].Value, wantMatch)
+		}
+	})
+}
+
+func TestDecodeMenu(t *testing.T) {
+	t.Run("page past the last category", func(t *testing.T) {
+		sections, total := decodeMenu(nil)
+		if sections == nil || len(sections) != 0 || total != 0 {
+			t.Errorf("decodeMenu(nil) = %v, %d; want an empty page", sections, total)
+		}
+	})
+
+	t.Run("sections", func(t *testing.T) {
+		var rows []menuRow
+		for _, doc := range []bson.M{
+			{"category": "Pizzas", "product_count": int32(3), "total": int32(2), "products": bson.A{
+				bson.M{"_id": "p1", "name": "Margarita", "category": "Pizzas", "position": int32(0)},
+				bson.M{"_id": "p2", "name": "Hawaiana", "category": "Pizzas", "position": int32(1)},
+			}},
+			{"category": "Drinks", "product_count": int32(1), "total": int32(2), "products": bson.A{
+				bson.M{"_id": "p3", "name": "Limonada", "category": "Drinks", "position": int32(0)},
+			}},
+		} {
+			var row menuRow
+			if err := bson.Unmarshal(rawDoc(t, doc), &row); err != nil {
+				t.Fatal(err)
+			}
+			rows = append(rows, row)
+		}
+
+		sections, total := decodeMenu(rows)
+		if total != 2 || len(sections) != 2 {
+			t.Fatalf("decodeMenu() = %d sections of %d, want 2 of 2", len(sections), total)
+		}
+		pizzas := sections[0]
+		if pizzas.Category != "Pizzas" || pizzas.ProductCount != 3 || len(pizzas.Products) != 2 || pizzas.Products[1].ID != "p2" {
+			t.Errorf("first section = %+v, want Pizzas with 2 of its 3 products", pizzas)
+		}
+		if sections[1].Category != "Drinks" || sections[1].Products[0].Name != "Limonada" {
+			t.Errorf("second section = %+v, want Drinks", sections[1])
+		}
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/repository/query"
+	"github.com/emerarteaga/products-api/internal/util"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -13,21 +15,51 @@ import (
 
 type productMongoRepository struct {
 	collection *mongo.Collection
+	reads      readers
 }
 
 // NewProductMongoRepository creates a new product repository
 func NewProductMongoRepository(collection *mongo.Collection) product.Repository {
-	return &productMongoRepository{collection: collection}
+	return &productMongoRepository{collection: collection, reads: newReaders(collection)}
 }
 
+// liveProduct matches the deleted_at of products that were not soft deleted. Live products store
+// null instead of omitting the field, because partial indexes cannot filter on a missing field.
+var liveProduct = bson.M{"$type": "null"}
+
+// loadedVersion matches the version a product had when it was loaded, for optimistic locking.
+// Products stored before versioning have no version field and load as version 0.
+func loadedVersion(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// bumpVersion is the $inc of writes that change products without loading them, so full updates
+// based on an earlier copy conflict instead of undoing them
+var bumpVersion = bson.M{"version": 1}
+
 // CreateIndexes creates the necessary indexes for the products collection
 func (r *productMongoRepository) CreateIndexes(ctx context.Context) error {
+	// Products stored before soft deletes have no deleted_at; mark them live so listings keep finding them
+	if _, err := r.collection.UpdateMany(ctx,
+		bson.M{"deleted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"deleted_at": nil}},
+	); err != nil {
+		return fmt.Errorf("failed to backfill deleted_at: %w", err)
+	}
+
+	// Scoped listings only index live products, so soft deleted ones don't slow them down.
+	// The names differ from the full indexes created before soft deletes, which can be dropped.
 	indexes := []mongo.IndexModel{
 		{
-			Keys: bson.D{{Key: "company_id", Value: 1}},
+			Keys:    bson.D{{Key: "company_id", Value: 1}},
+			Options: liveIndex("company_id_live"),
 		},
 		{
-			Keys: bson.D{{Key: "sale_point_id", Value: 1}},
+			Keys:    bson.D{{Key: "sale_point_id", Value: 1}},
+			Options: liveIndex("sale_point_id_live"),
 		},
 		{
 			Keys: bson.D{{Key: "category", Value: 1}},
@@ -37,19 +69,41 @@ func (r *productMongoRepository) CreateIndexes(ctx context.Context) error {
 				{Key: "company_id", Value: 1},
 				{Key: "category", Value: 1},
 			},
+			Options: liveIndex("company_id_category_live"),
 		},
 		{
 			Keys: bson.D{
 				{Key: "sale_point_id", Value: 1},
 				{Key: "is_available", Value: 1},
 			},
+			Options: liveIndex("sale_point_id_is_available_live"),
 		},
 		{
 			Keys: bson.D{
 				{Key: "company_id", Value: 1},
 				{Key: "is_available", Value: 1},
 			},
+			Options: liveIndex("company_id_is_available_live"),
 		},
+		{
+			// Multikey: one entry per tag
+			Keys: bson.D{
+				{Key: "company_id", Value: 1},
+				{Key: "tags", Value: 1},
+			},
+			Options: liveIndex("company_id_tags_live"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "sale_point_id", Value: 1},
+				{Key: "tags", Value: 1},
+			},
+			Options: liveIndex("sale_point_id_tags_live"),
+		},
+		productTextIndex(),
+		productSKUIndexModel(),
+		productBarcodeIndexModel(),
+		productPositionIndexModel(),
 	}
 
 	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
@@ -60,26 +114,54 @@ func (r *productMongoRepository) CreateIndexes(ctx context.Context) error {
 	return nil
 }
 
+// liveIndex returns the options of a named index that only covers live products
+func liveIndex(name string) *options.IndexOptions {
+	return options.Index().SetName(name).SetPartialFilterExpression(bson.M{"deleted_at": liveProduct})
+}
+
 // Create creates a new product
 func (r *productMongoRepository) Create(ctx context.Context, p *product.Product) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	_, err := r.collection.InsertOne(ctx, p)
 	if err != nil {
+		if dupErr := duplicateProductError(err); dupErr != nil {
+			return dupErr
+		}
 		return fmt.Errorf("failed to insert product: %w", err)
 	}
 
 	return nil
 }
 
+// CreateMany inserts the products in one ordered insert, so a failure leaves the products before it created
+func (r *productMongoRepository) CreateMany(ctx context.Context, products []*product.Product) error {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	documents := make([]any, len(products))
+	for i, p := range products {
+		documents[i] = p
+	}
+
+	if _, err := r.collection.InsertMany(ctx, documents); err != nil {
+		if dupErr := duplicateProductError(err); dupErr != nil {
+			return dupErr
+		}
+		return wrapError(ctx, "failed to insert products", err)
+	}
+
+	return nil
+}
+
 // FindByID finds a product by ID
 func (r *productMongoRepository) FindByID(ctx context.Context, id string) (*product.Product, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var p product.Product
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&p)
+	err := r.reads.forRead(ctx).FindOne(ctx, bson.M{"_id": id, "deleted_at": liveProduct}).Decode(&p)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, product.ErrProductNotFound
@@ -90,29 +172,36 @@ func (r *productMongoRepository) FindByID(ctx context.Context, id string) (*prod
 	return &p, nil
 }
 
-// FindByCompanyID retrieves all products for a company with optional filters
-func (r *productMongoRepository) FindByCompanyID(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+// FindByIDs finds the products with the given IDs in a single $in query
+func (r *productMongoRepository) FindByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*product.Product, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Build filter
-	filter := bson.M{"company_id": companyID}
-	r.applyFilters(filter, filters)
-
-	// Set default pagination
-	if filters.Limit <= 0 {
-		filters.Limit = 50
+	filter := bson.M{"_id": bson.M{"$in": ids}}
+	if !includeDeleted {
+		filter["deleted_at"] = liveProduct
 	}
-	if filters.Offset < 0 {
-		filters.Offset = 0
+
+	cursor, err := r.reads.forRead(ctx).Find(ctx, filter)
+	if err != nil {
+		return nil, wrapError(ctx, "failed to find products", err)
 	}
+	defer cursor.Close(ctx)
 
-	opts := options.Find().
-		SetLimit(int64(filters.Limit)).
-		SetSkip(int64(filters.Offset)).
-		SetSort(bson.D{{Key: "created_at", Value: -1}})
+	return r.decodeProducts(ctx, cursor)
+}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+// FindByCompanyID retrieves all products for a company with optional filters
+func (r *productMongoRepository) FindByCompanyID(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter, sort, err := productPage(bson.M{"company_id": companyID}, filters, query.NewestFirst)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.reads.forRead(ctx).Find(ctx, filter, query.Page(filters.Limit, filters.Offset, sort))
 	if err != nil {
 		return nil, fmt.Errorf("failed to find products: %w", err)
 	}
@@ -123,53 +212,118 @@ func (r *productMongoRepository) FindByCompanyID(ctx context.Context, companyID
 
 // FindBySalePointID retrieves all products for a sale point with optional filters
 func (r *productMongoRepository) FindBySalePointID(ctx context.Context, salePointID string, filters product.ProductFilters) ([]*product.Product, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	// Build filter
-	filter := bson.M{"sale_point_id": salePointID}
-	r.applyFilters(filter, filters)
-
-	// Set default pagination
-	if filters.Limit <= 0 {
-		filters.Limit = 50
+	filter, sort, err := productPage(bson.M{"sale_point_id": salePointID}, filters, menuOrder)
+	if err != nil {
+		return nil, err
 	}
-	if filters.Offset < 0 {
-		filters.Offset = 0
+
+	cursor, err := r.reads.forRead(ctx).Find(ctx, filter, query.Page(filters.Limit, filters.Offset, sort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find products: %w", err)
 	}
+	defer cursor.Close(ctx)
+
+	return r.decodeProducts(ctx, cursor)
+}
+
+// EachByCompanyID streams a company's products oldest first. The cursor fetches them in batches, so
+// memory stays flat however many products match, and the scan stops between batches on cancellation.
+func (r *productMongoRepository) EachByCompanyID(ctx context.Context, companyID string, filters product.ProductFilters, fn func(*product.Product) error) error {
+	oldestFirst := bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	return r.each(ctx, bson.M{"company_id": companyID}, oldestFirst, filters, fn)
+}
+
+// EachBySalePointID streams a sale point's products in menu order, like EachByCompanyID
+func (r *productMongoRepository) EachBySalePointID(ctx context.Context, salePointID string, filters product.ProductFilters, fn func(*product.Product) error) error {
+	return r.each(ctx, bson.M{"sale_point_id": salePointID}, menuOrder, filters, fn)
+}
+
+// each walks the scope's products matching filters in the given order through a single cursor
+func (r *productMongoRepository) each(ctx context.Context, scope bson.M, order bson.D, filters product.ProductFilters, fn func(*product.Product) error) error {
+	// Exports and copies of large catalogs take a while; the client disconnecting still cancels them
+	ctx, cancel := withTimeout(ctx, 10*time.Minute)
+	defer cancel()
 
 	opts := options.Find().
-		SetLimit(int64(filters.Limit)).
-		SetSkip(int64(filters.Offset)).
-		SetSort(bson.D{{Key: "created_at", Value: -1}})
+		SetSort(order).
+		SetBatchSize(200)
+	cursor, err := r.reads.forRead(ctx).Find(ctx, productFilter(scope, filters), opts)
+	if err != nil {
+		return wrapError(ctx, "failed to find products", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var p product.Product
+		if err := cursor.Decode(&p); err != nil {
+			return fmt.Errorf("failed to decode product: %w", err)
+		}
+		if err := fn(&p); err != nil {
+			return err
+		}
+
+		if err := checkBatch(ctx, cursor); err != nil {
+			return fmt.Errorf("product scan interrupted: %w", err)
+		}
+	}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err := cursor.Err(); err != nil {
+		return wrapError(ctx, "cursor error", err)
+	}
+
+	return nil
+}
+
+// FindByCompanyIDWithCount retrieves one page of a company's products and the total matching filters
+func (r *productMongoRepository) FindByCompanyIDWithCount(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+	return r.findWithCount(ctx, bson.M{"company_id": companyID}, query.NewestFirst, filters)
+}
+
+// FindBySalePointIDWithCount retrieves one page of a sale point's products and the total matching filters
+func (r *productMongoRepository) FindBySalePointIDWithCount(ctx context.Context, salePointID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+	return r.findWithCount(ctx, bson.M{"sale_point_id": salePointID}, menuOrder, filters)
+}
+
+// findWithCount lists the matching products within a scope in the given order and counts them with
+// a single $facet aggregation, so the page and the total come from the same snapshot of the collection
+func (r *productMongoRepository) findWithCount(ctx context.Context, scope bson.M, order bson.D, filters product.ProductFilters) ([]*product.Product, int64, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter, sort, err := productPage(scope, filters, order)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find products: %w", err)
+		return nil, 0, err
+	}
+
+	pipeline := pagePipeline(filter, sort, filters.Offset, filters.Limit, filters.SkipCount)
+	cursor, err := r.reads.forRead(ctx).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, wrapError(ctx, "failed to find products", err)
 	}
 	defer cursor.Close(ctx)
 
-	return r.decodeProducts(ctx, cursor)
+	var results []pageResult[*product.Product]
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, wrapError(ctx, "failed to decode products", err)
+	}
+
+	products, total := decodePage(results, filters.SkipCount)
+	return products, total, nil
 }
 
 // FindAll finds all products with pagination (deprecated)
 func (r *productMongoRepository) FindAll(ctx context.Context, limit, offset int) ([]*product.Product, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	if limit <= 0 {
 		limit = 50
 	}
-	if offset < 0 {
-		offset = 0
-	}
-
-	opts := options.Find().
-		SetLimit(int64(limit)).
-		SetSkip(int64(offset)).
-		SetSort(bson.D{{Key: "created_at", Value: -1}})
 
-	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	cursor, err := r.reads.forRead(ctx).Find(ctx, bson.M{"deleted_at": liveProduct}, query.Page(limit, offset, query.NewestFirst))
 	if err != nil {
 		return nil, fmt.Errorf("failed to find products: %w", err)
 	}
@@ -178,32 +332,126 @@ func (r *productMongoRepository) FindAll(ctx context.Context, limit, offset int)
 	return r.decodeProducts(ctx, cursor)
 }
 
-// Update updates a product
+// Update saves a product if nobody else changed it since it was loaded, incrementing its version.
+// It returns ErrProductConflict when the stored version moved on and ErrProductNotFound when the product is gone.
 func (r *productMongoRepository) Update(ctx context.Context, p *product.Product) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	p.UpdatedAt = time.Now()
+	loaded := p.Version
+	filter := bson.M{"_id": p.ID, "deleted_at": liveProduct, "version": loadedVersion(loaded)}
 
-	update := bson.M{
-		"$set": p,
-	}
+	p.UpdatedAt = time.Now()
+	p.Version = loaded + 1
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": p.ID}, update)
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": p})
 	if err != nil {
+		p.Version = loaded
+		if dupErr := duplicateProductError(err); dupErr != nil {
+			return dupErr
+		}
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
+		p.Version = loaded
+		exists, err := r.Exists(ctx, p.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return product.ErrProductConflict
+		}
 		return product.ErrProductNotFound
 	}
 
 	return nil
 }
 
-// Delete deletes a product (hard delete)
+// UpdateMany sets the products with one unordered BulkWrite, incrementing their versions. Soft deleted
+// products and those changed since they were loaded are skipped.
+func (r *productMongoRepository) UpdateMany(ctx context.Context, products []*product.Product) (int64, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, len(products))
+	for i, p := range products {
+		filter := bson.M{"_id": p.ID, "deleted_at": liveProduct, "version": loadedVersion(p.Version)}
+		next := *p
+		next.UpdatedAt = now
+		next.Version++
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": &next})
+	}
+
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		if dupErr := duplicateProductError(err); dupErr != nil {
+			return 0, dupErr
+		}
+		return 0, wrapError(ctx, "failed to update products", err)
+	}
+	// A bulk write only reports counts, so the given products are bumped when every one matched
+	if result.MatchedCount == int64(len(products)) {
+		for _, p := range products {
+			p.UpdatedAt = now
+			p.Version++
+		}
+	}
+	return result.MatchedCount, nil
+}
+
+// AdjustStock applies the adjustment in a single FindOneAndUpdate. The filter only matches live
+// products with limited stock and, for decrements, enough stock, so concurrent calls cannot oversell.
+func (r *productMongoRepository) AdjustStock(ctx context.Context, id string, adjustment product.StockAdjustment) (*product.Product, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter, update := stockUpdate(id, adjustment, time.Now())
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var p product.Product
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&p)
+	if err == nil {
+		return &p, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to adjust stock: %w", err)
+	}
+
+	// The guard failed: find out whether the product is missing, unlimited or short of stock
+	current, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsUnlimitedStock {
+		return nil, product.ErrCannotUpdateStockForUnlimited
+	}
+	return nil, product.ErrInsufficientStock
+}
+
+// stockUpdate builds the guarded filter and the update document of a stock adjustment
+func stockUpdate(id string, adjustment product.StockAdjustment, now time.Time) (bson.M, bson.M) {
+	filter := bson.M{"_id": id, "deleted_at": liveProduct, "is_unlimited_stock": false}
+	if adjustment.Set != nil {
+		return filter, bson.M{"$set": bson.M{"stock": *adjustment.Set, "updated_at": now}, "$inc": bumpVersion}
+	}
+
+	delta := *adjustment.Delta
+	if delta < 0 {
+		filter["stock"] = bson.M{"$gte": -delta}
+	}
+	return filter, bson.M{
+		"$inc": bson.M{"stock": delta, "version": 1},
+		"$set": bson.M{"updated_at": now},
+	}
+}
+
+// Delete deletes a product (hard delete), including soft deleted ones
 func (r *productMongoRepository) Delete(ctx context.Context, id string) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
@@ -218,56 +466,254 @@ func (r *productMongoRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// FindCategoriesByCompanyID retrieves all unique categories for a company
-func (r *productMongoRepository) FindCategoriesByCompanyID(ctx context.Context, companyID string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+// SoftDelete marks a live product as deleted
+func (r *productMongoRepository) SoftDelete(ctx context.Context, id string, at time.Time) error {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	filter := bson.M{"company_id": companyID}
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": liveProduct},
+		bson.M{"$set": bson.M{"deleted_at": at, "updated_at": at}, "$inc": bumpVersion},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return product.ErrProductNotFound
+	}
+
+	return nil
+}
 
-	categories, err := r.collection.Distinct(ctx, "category", filter)
+// Restore clears the deleted mark of a product
+func (r *productMongoRepository) Restore(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Live products match too, so restoring twice is not an error
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"deleted_at": nil, "updated_at": time.Now()}, "$inc": bumpVersion},
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find categories: %w", err)
+		if dupErr := duplicateProductError(err); dupErr != nil {
+			return dupErr
+		}
+		return fmt.Errorf("failed to restore product: %w", err)
 	}
 
-	result := make([]string, 0, len(categories))
-	for _, cat := range categories {
-		if str, ok := cat.(string); ok && str != "" {
-			result = append(result, str)
+	if result.MatchedCount == 0 {
+		return product.ErrProductNotFound
+	}
+
+	return nil
+}
+
+// FindIDsBySalePointID retrieves the IDs of the sale point's products matching filters
+func (r *productMongoRepository) FindIDsBySalePointID(ctx context.Context, salePointID string, filters product.ProductFilters) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	values, err := r.collection.Distinct(ctx, "_id", productFilter(bson.M{"sale_point_id": salePointID}, filters))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find product IDs: %w", err)
+	}
+
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			ids = append(ids, id)
 		}
 	}
+	return ids, nil
+}
+
+// FindLowStock retrieves products with limited stock at or below the threshold, lowest stock first
+func (r *productMongoRepository) FindLowStock(ctx context.Context, threshold, limit int) ([]*product.Product, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"is_unlimited_stock": false,
+		"stock":              bson.M{"$lte": threshold},
+		"deleted_at":         liveProduct,
+	}
+	sort := bson.D{{Key: "stock", Value: 1}, {Key: "name", Value: 1}}
+
+	cursor, err := r.reads.forRead(ctx).Find(ctx, filter, query.Page(limit, 0, sort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find low stock products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	return r.decodeProducts(ctx, cursor)
+}
+
+// DeleteMany deletes the products with the given IDs (hard delete)
+func (r *productMongoRepository) DeleteMany(ctx context.Context, ids []string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete products: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// SoftDeleteMany marks the live products with the given IDs as deleted with a single UpdateMany
+func (r *productMongoRepository) SoftDeleteMany(ctx context.Context, ids []string, at time.Time) (int64, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "deleted_at": liveProduct},
+		bson.M{"$set": bson.M{"deleted_at": at, "updated_at": at}, "$inc": bumpVersion},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete products: %w", err)
+	}
+
+	return result.MatchedCount, nil
+}
+
+// SetAvailability sets is_available on the selected live products with a single UpdateMany
+func (r *productMongoRepository) SetAvailability(ctx context.Context, selector product.AvailabilitySelector, available bool) (*product.BulkAvailabilityResult, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"is_available": available, "updated_at": time.Now()}, "$inc": bumpVersion}
+	result, err := r.collection.UpdateMany(ctx, availabilityFilter(selector), update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product availability: %w", err)
+	}
 
-	return result, nil
+	return &product.BulkAvailabilityResult{Matched: result.MatchedCount, Modified: result.ModifiedCount}, nil
+}
+
+// availabilityFilter selects the live products of a bulk availability update
+func availabilityFilter(selector product.AvailabilitySelector) bson.M {
+	filter := bson.M{"deleted_at": liveProduct}
+	if len(selector.IDs) > 0 {
+		filter["_id"] = bson.M{"$in": selector.IDs}
+	}
+	if selector.SalePointID != "" {
+		filter["sale_point_id"] = selector.SalePointID
+	}
+	if selector.Category != nil {
+		filter["category"] = *selector.Category
+	}
+	return filter
+}
+
+// FindCategoriesByCompanyID retrieves all unique categories for a company
+func (r *productMongoRepository) FindCategoriesByCompanyID(ctx context.Context, companyID string, filters product.CategoryFilters) ([]product.CategorySummary, int64, error) {
+	return r.findCategories(ctx, bson.M{"company_id": companyID}, filters)
 }
 
 // FindCategoriesBySalePointID retrieves all unique categories for a sale point
-func (r *productMongoRepository) FindCategoriesBySalePointID(ctx context.Context, salePointID string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+func (r *productMongoRepository) FindCategoriesBySalePointID(ctx context.Context, salePointID string, filters product.CategoryFilters) ([]product.CategorySummary, int64, error) {
+	return r.findCategories(ctx, bson.M{"sale_point_id": salePointID}, filters)
+}
+
+// findCategories groups the matching products by category, counting all and available products
+func (r *productMongoRepository) findCategories(ctx context.Context, filter bson.M, filters product.CategoryFilters) ([]product.CategorySummary, int64, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	filter := bson.M{"sale_point_id": salePointID}
+	filter["category"] = bson.M{"$nin": []interface{}{"", nil}}
+	filter["deleted_at"] = liveProduct
+
+	pipeline := summaryPipeline(mongo.Pipeline{{{Key: "$match", Value: filter}}}, "category", filters)
+	return findSummaries[product.CategorySummary](ctx, r.collection, pipeline, "categories")
+}
+
+// RenameCategory sets the category of the scope's products in any of the from categories with a single UpdateMany.
+// Soft deleted products are renamed too, so restoring them does not bring the old name back.
+func (r *productMongoRepository) RenameCategory(ctx context.Context, scope product.CategoryScope, from []string, to string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
 
-	categories, err := r.collection.Distinct(ctx, "category", filter)
+	update := bson.M{"$set": bson.M{"category": to, "updated_at": time.Now()}, "$inc": bumpVersion}
+	result, err := r.collection.UpdateMany(ctx, categoryRenameFilter(scope, from, to), update)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find categories: %w", err)
+		return 0, fmt.Errorf("failed to rename category: %w", err)
 	}
 
-	result := make([]string, 0, len(categories))
-	for _, cat := range categories {
-		if str, ok := cat.(string); ok && str != "" {
-			result = append(result, str)
-		}
+	return result.ModifiedCount, nil
+}
+
+// categoryRenameFilter selects the products of a category rename that do not already have the new name
+func categoryRenameFilter(scope product.CategoryScope, from []string, to string) bson.M {
+	filter := bson.M{
+		"company_id": scope.CompanyID,
+		"category":   bson.M{"$in": from, "$ne": to},
+	}
+	if scope.SalePointID != "" {
+		filter["sale_point_id"] = scope.SalePointID
+	}
+	return filter
+}
+
+// findSummaries runs a summaryPipeline and returns its rows, never nil, and the total number of groups
+func findSummaries[T any](ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, what string) ([]T, int64, error) {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, wrapError(ctx, "failed to find "+what, err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []pageResult[T]
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, wrapError(ctx, "failed to decode "+what, err)
+	}
+
+	rows, total := decodePage(results, false)
+	if rows == nil {
+		rows = []T{}
+	}
+	return rows, total, nil
+}
+
+// summaryPipeline appends to stages the grouping by field, the availability filter and the page
+func summaryPipeline(stages mongo.Pipeline, field string, filters product.CategoryFilters) mongo.Pipeline {
+	pipeline := append(stages,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":           "$" + field,
+			"product_count": bson.M{"$sum": 1},
+			"available_count": bson.M{
+				"$sum": bson.M{"$cond": []interface{}{"$is_available", 1, 0}},
+			},
+		}}},
+	)
+	if filters.OnlyWithAvailable {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"available_count": bson.M{"$gt": 0}}}})
 	}
 
-	return result, nil
+	rows := []bson.M{{"$sort": bson.M{"_id": 1}}}
+	if !filters.All {
+		rows = append(rows, bson.M{"$skip": filters.Offset}, bson.M{"$limit": filters.Limit})
+	}
+	rows = append(rows, bson.M{"$project": bson.M{
+		"name":            "$_id",
+		"product_count":   1,
+		"available_count": 1,
+		"_id":             0,
+	}})
+	return append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"rows":  rows,
+		"total": []bson.M{{"$count": "count"}},
+	}}})
 }
 
-// Count returns the total number of products
+// Count returns the total number of live products
 func (r *productMongoRepository) Count(ctx context.Context) (int64, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	count, err := r.collection.CountDocuments(ctx, bson.M{})
+	count, err := r.collection.CountDocuments(ctx, bson.M{"deleted_at": liveProduct})
 	if err != nil {
 		return 0, fmt.Errorf("failed to count products: %w", err)
 	}
@@ -277,14 +723,12 @@ func (r *productMongoRepository) Count(ctx context.Context) (int64, error) {
 
 // CountByCompanyID returns the total number of products for a company with filters
 func (r *productMongoRepository) CountByCompanyID(ctx context.Context, companyID string, filters product.ProductFilters) (int64, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Build filter (same as FindByCompanyID but without pagination)
-	filter := bson.M{"company_id": companyID}
-	r.applyFilters(filter, filters)
+	filter := productFilter(bson.M{"company_id": companyID}, filters)
 
-	count, err := r.collection.CountDocuments(ctx, filter)
+	count, err := r.reads.forRead(ctx).CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count products: %w", err)
 	}
@@ -294,14 +738,12 @@ func (r *productMongoRepository) CountByCompanyID(ctx context.Context, companyID
 
 // CountBySalePointID returns the total number of products for a sale point with filters
 func (r *productMongoRepository) CountBySalePointID(ctx context.Context, salePointID string, filters product.ProductFilters) (int64, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Build filter (same as FindBySalePointID but without pagination)
-	filter := bson.M{"sale_point_id": salePointID}
-	r.applyFilters(filter, filters)
+	filter := productFilter(bson.M{"sale_point_id": salePointID}, filters)
 
-	count, err := r.collection.CountDocuments(ctx, filter)
+	count, err := r.reads.forRead(ctx).CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count products: %w", err)
 	}
@@ -311,10 +753,10 @@ func (r *productMongoRepository) CountBySalePointID(ctx context.Context, salePoi
 
 // Exists checks if a product exists
 func (r *productMongoRepository) Exists(ctx context.Context, id string) (bool, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id})
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id, "deleted_at": liveProduct})
 	if err != nil {
 		return false, fmt.Errorf("failed to check product existence: %w", err)
 	}
@@ -322,17 +764,51 @@ func (r *productMongoRepository) Exists(ctx context.Context, id string) (bool, e
 	return count > 0, nil
 }
 
-// applyFilters applies filters to the MongoDB filter document
-func (r *productMongoRepository) applyFilters(filter bson.M, filters product.ProductFilters) {
-	if filters.Category != nil {
-		filter["category"] = *filters.Category
+// productFilter builds the filter document shared by listings and counts within a scope
+func productFilter(scope bson.M, filters product.ProductFilters) bson.M {
+	b := query.New(scope)
+	query.Equal(b, "sale_point_id", filters.SalePointID)
+	query.OneOf(b, "category", filters.Category, filters.Categories)
+	query.Equal(b, "is_available", filters.IsAvailable)
+	query.Equal(b, "is_addon", filters.IsAddon)
+	if len(filters.Tags) > 0 {
+		b.Set("tags", tagsCondition(filters))
+	}
+	// A single variation must fall within both bounds
+	query.ElemRange(b, "price_variations", "price", filters.MinPrice, filters.MaxPrice)
+	if !filters.IncludeDeleted {
+		b.Set("deleted_at", liveProduct)
+	}
+	filter := b.Filter()
+	applyProductSearch(filter, filters)
+	return filter
+}
+
+// productPage returns the filter and sort of one listing page, in order unless searching. Cursor pages
+// are always newest first: they seek past the cursor and break ties by _id, so they stay stable while
+// products are inserted.
+func productPage(scope bson.M, filters product.ProductFilters, order bson.D) (bson.M, bson.D, error) {
+	filter := productFilter(scope, filters)
+	if filters.Cursor == nil {
+		return filter, productSort(filters, order), nil
+	}
+	if usesTextSearch(filters) {
+		return nil, nil, fmt.Errorf("%w: search results are ranked, use offset pagination", util.ErrInvalidCursor)
 	}
-	if filters.IsAvailable != nil {
-		filter["is_available"] = *filters.IsAvailable
+
+	sort := query.WithIDTieBreak(query.NewestFirst)
+	if filters.Cursor.IsStart() {
+		return filter, sort, nil
 	}
-	if filters.IsAddon != nil {
-		filter["is_addon"] = *filters.IsAddon
+	if filters.Cursor.Field != "created_at" {
+		return nil, nil, fmt.Errorf("%w: products are not sorted by %s", util.ErrInvalidCursor, filters.Cursor.Field)
 	}
+	createdAt, err := time.Parse(time.RFC3339Nano, filters.Cursor.Value)
+	if err != nil {
+		return nil, nil, util.ErrInvalidCursor
+	}
+	appendAnd(filter, query.After("created_at", -1, createdAt, filters.Cursor.ID))
+	return filter, sort, nil
 }
 
 // decodeProducts decodes products from cursor
@@ -344,10 +820,15 @@ func (r *productMongoRepository) decodeProducts(ctx context.Context, cursor *mon
 			return nil, fmt.Errorf("failed to decode product: %w", err)
 		}
 		products = append(products, &p)
+
+		// Stop between batches when the request was cancelled
+		if err := checkBatch(ctx, cursor); err != nil {
+			return nil, fmt.Errorf("product scan interrupted: %w", err)
+		}
 	}
 
 	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("cursor error: %w", err)
+		return nil, wrapError(ctx, "cursor error", err)
 	}
 
 	return products, nil
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// menuOrder is the default order of sale point listings: categories by name, then each category's
+// products by display position. Products sharing a position, e.g. created at the same time or stored
+// before positions existed, keep their creation order.
+var menuOrder = bson.D{
+	{Key: "category", Value: 1},
+	{Key: "position", Value: 1},
+	{Key: "created_at", Value: 1},
+	{Key: "_id", Value: 1},
+}
+
+// productPositionIndexModel serves menuOrder listings and the lookups of a category's last position
+func productPositionIndexModel() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "sale_point_id", Value: 1},
+			{Key: "category", Value: 1},
+			{Key: "position", Value: 1},
+		},
+		Options: liveIndex("sale_point_id_category_position_live"),
+	}
+}
+
+// NextPosition reads the highest position of the category's live products
+func (r *productMongoRepository) NextPosition(ctx context.Context, salePointID, category string) (int, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"sale_point_id": salePointID, "category": category, "deleted_at": liveProduct}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "position", Value: -1}}).
+		SetProjection(bson.M{"position": 1})
+
+	var last struct {
+		Position int `bson:"position"`
+	}
+	// Read from the primary, so products created just before are counted
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&last)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, wrapError(ctx, "failed to find category position", err)
+	}
+	return last.Position + 1, nil
+}
+
+// SetPositions writes the positions with one unordered BulkWrite. Products already in place are not
+// matched, so their updated_at is kept and the modified count only reports moved products.
+func (r *productMongoRepository) SetPositions(ctx context.Context, salePointID, category string, orderedIDs []string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := r.collection.BulkWrite(ctx, positionWrites(salePointID, category, orderedIDs, time.Now()),
+		options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("failed to set product positions: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// positionWrites builds one guarded update per product, setting its index in orderedIDs as position
+func positionWrites(salePointID, category string, orderedIDs []string, now time.Time) []mongo.WriteModel {
+	writes := make([]mongo.WriteModel, len(orderedIDs))
+	for i, id := range orderedIDs {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"_id":           id,
+				"sale_point_id": salePointID,
+				"category":      category,
+				"deleted_at":    liveProduct,
+				"position":      bson.M{"$ne": i},
+			}).
+			SetUpdate(bson.M{"$set": bson.M{"position": i, "updated_at": now}, "$inc": bumpVersion})
+	}
+	return writes
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestSalePointListingOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters product.ProductFilters
+		want    bson.D
+	}{
+		{"menu order", product.ProductFilters{}, menuOrder},
+		{"short search keeps the menu order", product.ProductFilters{Search: ptr("pan")}, menuOrder},
+		{"text search ranks by score", product.ProductFilters{Search: ptr("arepa")},
+			bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_at", Value: -1}}},
+		{"cursor pages are newest first", product.ProductFilters{Cursor: &util.PageCursor{}},
+			bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sort, err := productPage(bson.M{"sale_point_id": "sp1"}, tt.filters, menuOrder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sort, tt.want) {
+				t.Errorf("sort = %v, want %v", sort, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductPositionIndexModel(t *testing.T) {
+	model := productPositionIndexModel()
+
+	// The index prefix must follow menuOrder within a sale point
+	wantKeys := bson.D{{Key: "sale_point_id", Value: 1}, {Key: "category", Value: 1}, {Key: "position", Value: 1}}
+	if !reflect.DeepEqual(model.Keys, wantKeys) {
+		t.Errorf("keys = %v, want %v", model.Keys, wantKeys)
+	}
+	if !reflect.DeepEqual(model.Options.PartialFilterExpression, bson.M{"deleted_at": liveProduct}) {
+		t.Errorf("partial filter = %v, want live products only", model.Options.PartialFilterExpression)
+	}
+}
+
+func TestPositionWrites(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	writes := positionWrites("sp1", "Pizzas", []string{"p2", "p1"}, now)
+
+	if len(writes) != 2 {
+		t.Fatalf("got %d writes, want 2", len(writes))
+	}
+	for i, id := range []string{"p2", "p1"} {
+		model, ok := writes[i].(*mongo.UpdateOneModel)
+		if !ok {
+			t.Fatalf("write %d is %T, want an update", i, writes[i])
+		}
+		wantFilter := bson.M{
+			"_id":           id,
+			"sale_point_id": "sp1",
+			"category":      "Pizzas",
+			"deleted_at":    liveProduct,
+			"position":      bson.M{"$ne": i},
+		}
+		if !reflect.DeepEqual(model.Filter, wantFilter) {
+			t.Errorf("filter = %v, want %v", model.Filter, wantFilter)
+		}
+		wantUpdate := bson.M{"$set": bson.M{"position": i, "updated_at": now}, "$inc": bson.M{"version": 1}}
+		if !reflect.DeepEqual(model.Update, wantUpdate) {
+			t.Errorf("update = %v, want %v", model.Update, wantUpdate)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// priceHistoryMongoRepository keeps price changes in their own collection, so a product
+// whose prices change often does not grow toward the document size limit
+type priceHistoryMongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPriceHistoryMongoRepository creates the price history repository
+func NewPriceHistoryMongoRepository(collection *mongo.Collection) product.PriceHistoryRepository {
+	return &priceHistoryMongoRepository{collection: collection}
+}
+
+// CreateIndexes creates the index that lists a product's changes newest first
+func (r *priceHistoryMongoRepository) CreateIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: priceHistorySort()})
+	if err != nil {
+		return fmt.Errorf("failed to create price history index: %w", err)
+	}
+	return nil
+}
+
+// Append records a price change
+func (r *priceHistoryMongoRepository) Append(ctx context.Context, change *product.PriceChange) error {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.InsertOne(ctx, change); err != nil {
+		return wrapError(ctx, "failed to insert price change", err)
+	}
+	return nil
+}
+
+// FindByProductID retrieves one page of a product's price changes, newest first, and their total
+func (r *priceHistoryMongoRepository) FindByProductID(ctx context.Context, productID string, limit, offset int) ([]product.PriceChange, int64, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pipeline := pagePipeline(bson.M{"product_id": productID}, priceHistorySort(), offset, limit, false)
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, wrapError(ctx, "failed to find price history", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []pageResult[product.PriceChange]
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, wrapError(ctx, "failed to decode price history", err)
+	}
+
+	changes, total := decodePage(results, false)
+	if changes == nil {
+		changes = []product.PriceChange{}
+	}
+	return changes, total, nil
+}
+
+// priceHistorySort orders a product's changes newest first; the ID breaks ties within the same instant
+func priceHistorySort() bson.D {
+	return bson.D{
+		{Key: "product_id", Value: 1},
+		{Key: "changed_at", Value: -1},
+		{Key: "_id", Value: -1},
+	}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// productTextSearchIndex is the name of the products text index; a collection has a single one
+const productTextSearchIndex = "products_text_search"
+
+// productTextIndex indexes name and description for product search. Name matches weigh more, so
+// they rank above description matches. Text indexes ignore case and diacritics, so "jalapeno"
+// finds "jalapeño", and the Spanish stemmer makes "arepas" find "arepa".
+func productTextIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "description", Value: "text"},
+		},
+		Options: options.Index().
+			SetName(productTextSearchIndex).
+			SetWeights(bson.M{"name": 10, "description": 1}).
+			SetDefaultLanguage("spanish"),
+	}
+}
+
+// usesTextSearch reports whether the filters search with the text index, whose score sorts the results
+func usesTextSearch(filters product.ProductFilters) bool {
+	return filters.Search != nil && len([]rune(*filters.Search)) >= minTextSearchLength
+}
+
+// applyProductSearch adds the search condition to the filter. Shorter queries, which the text
+// index matches poorly as it works on whole words, fall back to an accent-insensitive $regex.
+func applyProductSearch(filter bson.M, filters product.ProductFilters) {
+	if filters.Search == nil {
+		return
+	}
+	if usesTextSearch(filters) {
+		filter["$text"] = bson.M{"$search": *filters.Search}
+		return
+	}
+
+	pattern := bson.M{"$regex": accentInsensitivePattern(*filters.Search), "$options": "i"}
+	appendAnd(filter, bson.M{"$or": []bson.M{
+		{"name": pattern},
+		{"description": pattern},
+	}})
+}
+
+// productSort returns the listing order: best text matches first when searching, the given order otherwise
+func productSort(filters product.ProductFilters, order bson.D) bson.D {
+	if usesTextSearch(filters) {
+		return bson.D{
+			{Key: "score", Value: bson.M{"$meta": "textScore"}},
+			{Key: "created_at", Value: -1},
+		}
+	}
+	return order
+}
+
+// accentVariants lists the letters a search letter stands for, so searches match with or without accents
+var accentVariants = map[rune]string{
+	'a': "aáàâäAÁÀÂÄ",
+	'e': "eéèêëEÉÈÊË",
+	'i': "iíìîïIÍÌÎÏ",
+	'o': "oóòôöOÓÒÔÖ",
+	'u': "uúùûüUÚÙÛÜ",
+	'n': "nñNÑ",
+	'c': "cçCÇ",
+}
+
+// accentInsensitivePattern quotes the query as a regular expression whose letters also match their
+// accented forms: "jalapeno" and "jalapeño" both become "jalape[nñNÑ][oóòôöOÓÒÔÖ]"
+func accentInsensitivePattern(query string) string {
+	var b strings.Builder
+	for _, r := range query {
+		if variants, ok := accentVariants[baseLetter(r)]; ok {
+			b.WriteString("[" + variants + "]")
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(string(r)))
+	}
+	return b.String()
+}
+
+// baseLetter returns the lowercase unaccented letter of r, or r itself when it has no variants
+func baseLetter(r rune) rune {
+	lower := []rune(strings.ToLower(string(r)))[0]
+	for base, variants := range accentVariants {
+		if strings.ContainsRune(variants, lower) {
+			return base
+		}
+	}
+	return r
+}
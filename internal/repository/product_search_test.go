@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/emerarteaga/products-api/internal/repository/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestProductFilterSearch(t *testing.T) {
+	scope := bson.M{"company_id": "c1"}
+
+	tests := []struct {
+		name    string
+		filters product.ProductFilters
+		want    bson.M
+	}{
+		{
+			name:    "text search",
+			filters: product.ProductFilters{Search: ptr("arepa")},
+			want:    bson.M{"company_id": "c1", "deleted_at": liveProduct, "$text": bson.M{"$search": "arepa"}},
+		},
+		{
+			name:    "combined with category and availability",
+			filters: product.ProductFilters{Search: ptr("jalapeño"), Category: ptr("Tacos"), IsAvailable: ptr(true)},
+			want: bson.M{
+				"company_id":   "c1",
+				"deleted_at":   liveProduct,
+				"category":     "Tacos",
+				"is_available": true,
+				"$text":        bson.M{"$search": "jalapeño"},
+			},
+		},
+		{
+			name:    "short query falls back to regex",
+			filters: product.ProductFilters{Search: ptr("té"), IsAvailable: ptr(false)},
+			want: bson.M{
+				"company_id":   "c1",
+				"deleted_at":   liveProduct,
+				"is_available": false,
+				"$and": []bson.M{{"$or": []bson.M{
+					{"name": bson.M{"$regex": "t[eéèêëEÉÈÊË]", "$options": "i"}},
+					{"description": bson.M{"$regex": "t[eéèêëEÉÈÊË]", "$options": "i"}},
+				}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := productFilter(scope, tt.filters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductSort(t *testing.T) {
+	ranked := bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_at", Value: -1}}
+
+	tests := []struct {
+		name   string
+		search *string
+		want   bson.D
+	}{
+		{"no search", nil, query.NewestFirst},
+		{"text search ranks by score", ptr("arepa"), ranked},
+		{"regex search keeps newest first", ptr("pan"), query.NewestFirst},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := productSort(product.ProductFilters{Search: tt.search}, query.NewestFirst); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sort = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProductTextIndexRanksNames checks name matches weigh more than description matches
+func TestProductTextIndexRanksNames(t *testing.T) {
+	index := productTextIndex()
+	if index.Options.Name == nil || *index.Options.Name != productTextSearchIndex {
+		t.Errorf("name = %v, want %s", index.Options.Name, productTextSearchIndex)
+	}
+	weights, ok := index.Options.Weights.(bson.M)
+	if !ok || weights["name"].(int) <= weights["description"].(int) {
+		t.Errorf("weights = %v, want name above description", index.Options.Weights)
+	}
+}
+
+func TestAccentInsensitivePattern(t *testing.T) {
+	tests := []struct {
+		query   string
+		matches []string
+		misses  []string
+	}{
+		{"jalapeño", []string{"Salsa jalapeño", "JALAPENO", "jalapeno"}, []string{"jalapa"}},
+		{"jalapeno", []string{"Jalapeño", "jalapeño picante"}, []string{"jalapenio"}},
+		{"cafe", []string{"Café con leche", "CAFÉ"}, []string{"caffe"}},
+		{"piña", []string{"Jugo de pina", "PIÑA colada"}, []string{"pia"}},
+		{"1+1", []string{"Combo 1+1"}, []string{"11"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			re := regexp.MustCompile("(?i)" + accentInsensitivePattern(tt.query))
+			for _, s := range tt.matches {
+				if !re.MatchString(s) {
+					t.Errorf("%q does not match %q", tt.query, s)
+				}
+			}
+			for _, s := range tt.misses {
+				if re.MatchString(s) {
+					t.Errorf("%q matches %q", tt.query, s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// productSKUIndex keeps SKUs unique within a sale point
+const productSKUIndex = "sale_point_id_sku_unique"
+
+// productSKUIndexModel returns the unique SKU index. It only covers live products with a SKU, so products
+// without one are unaffected and a soft deleted product does not hold its SKU.
+func productSKUIndexModel() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "sale_point_id", Value: 1},
+			{Key: "sku", Value: 1},
+		},
+		Options: options.Index().
+			SetName(productSKUIndex).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{
+				"sku":        bson.M{"$type": "string"},
+				"deleted_at": liveProduct,
+			}),
+	}
+}
+
+// isDuplicateSKU reports whether a write failed on the unique SKU index
+func isDuplicateSKU(err error) bool {
+	return mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), productSKUIndex)
+}
+
+// FindBySKU finds the live product of a sale point with the given normalized SKU
+func (r *productMongoRepository) FindBySKU(ctx context.Context, salePointID, sku string) (*product.Product, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"sale_point_id": salePointID, "sku": sku, "deleted_at": liveProduct}
+	var p product.Product
+	if err := r.reads.forRead(ctx).FindOne(ctx, filter).Decode(&p); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, product.ErrProductNotFound
+		}
+		return nil, wrapError(ctx, "failed to find product by sku", err)
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestProductSKUIndexModel(t *testing.T) {
+	model := productSKUIndexModel()
+
+	wantKeys := bson.D{{Key: "sale_point_id", Value: 1}, {Key: "sku", Value: 1}}
+	if !reflect.DeepEqual(model.Keys, wantKeys) {
+		t.Errorf("keys = %v, want %v", model.Keys, wantKeys)
+	}
+	if model.Options.Unique == nil || !*model.Options.Unique {
+		t.Error("index is not unique")
+	}
+
+	// Products without a SKU and soft deleted products stay out of the index
+	wantFilter := bson.M{"sku": bson.M{"$type": "string"}, "deleted_at": liveProduct}
+	if !reflect.DeepEqual(model.Options.PartialFilterExpression, wantFilter) {
+		t.Errorf("partial filter = %v, want %v", model.Options.PartialFilterExpression, wantFilter)
+	}
+}
+
+func TestIsDuplicateSKU(t *testing.T) {
+	duplicate := func(message string) error {
+		return mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: message}}}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"sku index", duplicate("E11000 duplicate key error collection: db.products index: sale_point_id_sku_unique dup key"), true},
+		{"id index", duplicate("E11000 duplicate key error collection: db.products index: _id_ dup key"), false},
+		{"other error", errors.New("sale_point_id_sku_unique"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDuplicateSKU(tt.err); got != tt.want {
+				t.Errorf("isDuplicateSKU() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
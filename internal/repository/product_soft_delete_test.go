@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestProductFilterExcludesDeleted(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters product.ProductFilters
+		want    bson.M
+	}{
+		{"live products by default", product.ProductFilters{}, bson.M{"company_id": "c1", "deleted_at": liveProduct}},
+		{"admin listing", product.ProductFilters{IncludeDeleted: true}, bson.M{"company_id": "c1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := productFilter(bson.M{"company_id": "c1"}, tt.filters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLiveIndexSkipsDeleted(t *testing.T) {
+	opts := liveIndex("company_id_live")
+	if opts.Name == nil || *opts.Name != "company_id_live" {
+		t.Errorf("name = %v, want company_id_live", opts.Name)
+	}
+	if want := (bson.M{"deleted_at": bson.M{"$type": "null"}}); !reflect.DeepEqual(opts.PartialFilterExpression, want) {
+		t.Errorf("partial filter = %v, want %v", opts.PartialFilterExpression, want)
+	}
+}
+
+// TestLiveProductsStoreNull checks live products are stored with a null deleted_at, which the
+// partial indexes and the live filter match, instead of omitting the field
+func TestLiveProductsStoreNull(t *testing.T) {
+	raw, err := bson.Marshal(&product.Product{ID: "p1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := bson.Raw(raw).LookupErr("deleted_at")
+	if err != nil || value.Type != bson.TypeNull {
+		t.Errorf("deleted_at = %v, %v, want null", value.Type, err)
+	}
+}
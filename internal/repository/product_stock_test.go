@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestStockUpdate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ptr := func(n int) *int { return &n }
+	guard := func(extra bson.M) bson.M {
+		filter := bson.M{"_id": "p1", "deleted_at": liveProduct, "is_unlimited_stock": false}
+		for k, v := range extra {
+			filter[k] = v
+		}
+		return filter
+	}
+
+	tests := []struct {
+		name       string
+		adjustment product.StockAdjustment
+		wantFilter bson.M
+		wantUpdate bson.M
+	}{
+		{
+			name:       "decrement guards against overselling",
+			adjustment: product.StockAdjustment{Delta: ptr(-3)},
+			wantFilter: guard(bson.M{"stock": bson.M{"$gte": 3}}),
+			wantUpdate: bson.M{"$inc": bson.M{"stock": -3, "version": 1}, "$set": bson.M{"updated_at": now}},
+		},
+		{
+			name:       "restock needs no guard",
+			adjustment: product.StockAdjustment{Delta: ptr(4)},
+			wantFilter: guard(nil),
+			wantUpdate: bson.M{"$inc": bson.M{"stock": 4, "version": 1}, "$set": bson.M{"updated_at": now}},
+		},
+		{
+			name:       "set replaces the stock",
+			adjustment: product.StockAdjustment{Set: ptr(25)},
+			wantFilter: guard(nil),
+			wantUpdate: bson.M{"$set": bson.M{"stock": 25, "updated_at": now}, "$inc": bson.M{"version": 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, update := stockUpdate("p1", tt.adjustment, now)
+			if !reflect.DeepEqual(filter, tt.wantFilter) {
+				t.Errorf("filter = %v, want %v", filter, tt.wantFilter)
+			}
+			if !reflect.DeepEqual(update, tt.wantUpdate) {
+				t.Errorf("update = %v, want %v", update, tt.wantUpdate)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SummarizeByCompanyID counts the company's live products with a single aggregation
+func (r *productMongoRepository) SummarizeByCompanyID(ctx context.Context, companyID string) (*product.CompanySummary, error) {
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.reads.forRead(ctx).Aggregate(ctx, summaryByCategoryPipeline(bson.M{"company_id": companyID}))
+	if err != nil {
+		return nil, wrapError(ctx, "failed to summarize products", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, wrapError(ctx, "failed to decode product summary", err)
+	}
+	return decodeCompanySummary(docs)
+}
+
+// summaryByCategoryPipeline groups the scope's live products by category, counting them by availability
+// like product.Availability does, then folds the categories into the overall counts
+func summaryByCategoryPipeline(scope bson.M) mongo.Pipeline {
+	match := bson.M{"deleted_at": liveProduct}
+	for k, v := range scope {
+		match[k] = v
+	}
+
+	limited := bson.M{"$not": bson.A{"$is_unlimited_stock"}}
+	inStock := bson.M{"$gt": bson.A{"$stock", 0}}
+	countIf := func(condition any) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{condition, 1, 0}}}
+	}
+	// Products without variations have no price and add nothing
+	value := bson.M{"$cond": bson.A{
+		bson.M{"$and": bson.A{limited, inStock}},
+		bson.M{"$multiply": bson.A{bson.M{"$ifNull": bson.A{bson.M{"$min": "$price_variations.price"}, 0}}, "$stock"}},
+		0,
+	}}
+
+	counts := []string{"total", "available", "disabled", "out_of_stock", "catalog_value"}
+	overall := bson.M{"_id": nil}
+	category := bson.M{"name": "$_id", "_id": 0}
+	for _, field := range counts {
+		overall[field] = bson.M{"$sum": "$" + field}
+		category[field] = "$" + field
+	}
+	overall["categories"] = bson.M{"$push": "$$ROOT"}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$category",
+			"total": bson.M{"$sum": 1},
+			// Manually disabled wins over stock, as in product.Availability
+			"available":     countIf(bson.M{"$and": bson.A{"$is_available", bson.M{"$or": bson.A{"$is_unlimited_stock", inStock}}}}),
+			"disabled":      countIf(bson.M{"$not": bson.A{"$is_available"}}),
+			"out_of_stock":  countIf(bson.M{"$and": bson.A{"$is_available", limited, bson.M{"$not": bson.A{inStock}}}}),
+			"catalog_value": bson.M{"$sum": value},
+		}}},
+		{{Key: "$project", Value: category}},
+		{{Key: "$sort", Value: bson.M{"name": 1}}},
+		{{Key: "$group", Value: overall}},
+		{{Key: "$project", Value: bson.M{"_id": 0}}},
+	}
+}
+
+// decodeCompanySummary decodes the single document of a summaryByCategoryPipeline.
+// The $group emits nothing when no product matches, which is an empty summary.
+func decodeCompanySummary(docs []bson.Raw) (*product.CompanySummary, error) {
+	summary := &product.CompanySummary{}
+	if len(docs) > 0 {
+		if err := bson.Unmarshal(docs[0], summary); err != nil {
+			return nil, fmt.Errorf("failed to decode product summary: %w", err)
+		}
+	}
+	if summary.Categories == nil {
+		summary.Categories = []product.CategoryCounts{}
+	}
+	return summary, nil
+}
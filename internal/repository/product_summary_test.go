@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSummaryByCategoryPipeline(t *testing.T) {
+	pipeline := summaryByCategoryPipeline(bson.M{"company_id": "c1"})
+
+	stages := make([]string, len(pipeline))
+	for i, stage := range pipeline {
+		stages[i] = stage[0].Key
+	}
+	if want := []string{"$match", "$group", "$project", "$sort", "$group", "$project"}; !reflect.DeepEqual(stages, want) {
+		t.Fatalf("stages = %v, want %v", stages, want)
+	}
+
+	wantMatch := bson.M{"company_id": "c1", "deleted_at": liveProduct}
+	if !reflect.DeepEqual(pipeline[0][0].Value, wantMatch) {
+		t.Errorf("match = %v, want %v", pipeline[0][0].Value, wantMatch)
+	}
+	if group := pipeline[1][0].Value.(bson.M); group["_id"] != "$category" {
+		t.Errorf("first group _id = %v, want $category", group["_id"])
+	}
+	if overall := pipeline[4][0].Value.(bson.M); overall["_id"] != nil {
+		t.Errorf("overall group _id = %v, want nil", overall["_id"])
+	}
+}
+
+func TestDecodeCompanySummary(t *testing.T) {
+	t.Run("no document", func(t *testing.T) {
+		summary, err := decodeCompanySummary(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &product.CompanySummary{Categories: []product.CategoryCounts{}}
+		if !reflect.DeepEqual(summary, want) {
+			t.Errorf("summary = %+v, want %+v", summary, want)
+		}
+	})
+
+	t.Run("counts per category", func(t *testing.T) {
+		doc := rawDoc(t, bson.M{
+			"total": int32(3), "available": int32(1), "disabled": int32(1), "out_of_stock": int32(1), "catalog_value": int64(25_000),
+			"categories": bson.A{
+				bson.M{"name": "Drinks", "total": int32(1), "available": int32(0), "disabled": int32(1), "out_of_stock": int32(0), "catalog_value": int32(0)},
+				bson.M{"name": "Pizzas", "total": int32(2), "available": int32(1), "disabled": int32(0), "out_of_stock": int32(1), "catalog_value": int64(25_000)},
+			},
+		})
+
+		summary, err := decodeCompanySummary([]bson.Raw{doc})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &product.CompanySummary{
+			ProductCounts: product.ProductCounts{Total: 3, Available: 1, Disabled: 1, OutOfStock: 1, CatalogValue: 25_000},
+			Categories: []product.CategoryCounts{
+				{Name: "Drinks", ProductCounts: product.ProductCounts{Total: 1, Disabled: 1}},
+				{Name: "Pizzas", ProductCounts: product.ProductCounts{Total: 2, Available: 1, OutOfStock: 1, CatalogValue: 25_000}},
+			},
+		}
+		if !reflect.DeepEqual(summary, want) {
+			t.Errorf("summary = %+v, want %+v", summary, want)
+		}
+	})
+}
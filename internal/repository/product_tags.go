@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// tagsCondition matches products with any of the filter tags, or all of them in TagsAll mode
+func tagsCondition(filters product.ProductFilters) bson.M {
+	if filters.TagsMode == product.TagsAll {
+		return bson.M{"$all": filters.Tags}
+	}
+	return bson.M{"$in": filters.Tags}
+}
+
+// FindTagsByCompanyID retrieves the distinct tags of a company's live products with their product counts
+func (r *productMongoRepository) FindTagsByCompanyID(ctx context.Context, companyID string, filters product.CategoryFilters) ([]product.TagSummary, int64, error) {
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return findSummaries[product.TagSummary](ctx, r.collection, tagsPipeline(bson.M{"company_id": companyID}, filters), "tags")
+}
+
+// tagsPipeline counts the products of each tag within a scope; $unwind skips products without tags
+func tagsPipeline(scope bson.M, filters product.CategoryFilters) mongo.Pipeline {
+	match := bson.M{"deleted_at": liveProduct}
+	for k, v := range scope {
+		match[k] = v
+	}
+	return summaryPipeline(mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$tags"}},
+	}, "tags", filters)
+}
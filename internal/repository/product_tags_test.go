@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestProductTagsFilter(t *testing.T) {
+	scope := bson.M{"sale_point_id": "sp1"}
+	tags := []string{"vegan", "promo"}
+
+	tests := []struct {
+		name    string
+		filters product.ProductFilters
+		want    bson.M
+	}{
+		{
+			name:    "any tag",
+			filters: product.ProductFilters{Tags: tags, TagsMode: product.TagsAny},
+			want:    bson.M{"sale_point_id": "sp1", "tags": bson.M{"$in": tags}, "deleted_at": liveProduct},
+		},
+		{
+			name:    "mode defaults to any",
+			filters: product.ProductFilters{Tags: tags},
+			want:    bson.M{"sale_point_id": "sp1", "tags": bson.M{"$in": tags}, "deleted_at": liveProduct},
+		},
+		{
+			name:    "all tags",
+			filters: product.ProductFilters{Tags: tags, TagsMode: product.TagsAll},
+			want:    bson.M{"sale_point_id": "sp1", "tags": bson.M{"$all": tags}, "deleted_at": liveProduct},
+		},
+		{
+			name:    "mode without tags",
+			filters: product.ProductFilters{TagsMode: product.TagsAll},
+			want:    bson.M{"sale_point_id": "sp1", "deleted_at": liveProduct},
+		},
+		{
+			name:    "tags with category and availability",
+			filters: product.ProductFilters{Tags: []string{"spicy"}, TagsMode: product.TagsAll, Category: ptr("Tacos"), IsAvailable: ptr(true)},
+			want: bson.M{
+				"sale_point_id": "sp1",
+				"category":      "Tacos",
+				"is_available":  true,
+				"tags":          bson.M{"$all": []string{"spicy"}},
+				"deleted_at":    liveProduct,
+			},
+		},
+		{
+			name:    "tags with price range and deleted products",
+			filters: product.ProductFilters{Tags: tags, MaxPrice: ptr(int64(900)), IncludeDeleted: true},
+			want: bson.M{
+				"sale_point_id":    "sp1",
+				"tags":             bson.M{"$in": tags},
+				"price_variations": bson.M{"$elemMatch": bson.M{"price": bson.M{"$lte": int64(900)}}},
+			},
+		},
+		{
+			name:    "tags with short search",
+			filters: product.ProductFilters{Tags: tags, TagsMode: product.TagsAll, Search: ptr("tac")},
+			want: bson.M{
+				"sale_point_id": "sp1",
+				"tags":          bson.M{"$all": tags},
+				"deleted_at":    liveProduct,
+				"$and": []bson.M{{"$or": []bson.M{
+					{"name": bson.M{"$regex": accentInsensitivePattern("tac"), "$options": "i"}},
+					{"description": bson.M{"$regex": accentInsensitivePattern("tac"), "$options": "i"}},
+				}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := productFilter(scope, tt.filters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagsPipeline(t *testing.T) {
+	scope := bson.M{"company_id": "c1"}
+	pipeline := tagsPipeline(scope, product.CategoryFilters{Limit: 20})
+
+	wantMatch := bson.M{"company_id": "c1", "deleted_at": liveProduct}
+	if got := stageValue(t, pipeline, "$match"); !reflect.DeepEqual(got, wantMatch) {
+		t.Errorf("$match = %v, want %v", got, wantMatch)
+	}
+	if got := stageValue(t, pipeline, "$unwind"); got != "$tags" {
+		t.Errorf("$unwind = %v, want $tags", got)
+	}
+	if group := stageValue(t, pipeline, "$group").(bson.M); group["_id"] != "$tags" {
+		t.Errorf("grouped by %v, want $tags", group["_id"])
+	}
+	if len(scope) != 1 {
+		t.Errorf("scope was modified: %v", scope)
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLoadedVersion(t *testing.T) {
+	// Products stored before versioning have no version field
+	if got, want := loadedVersion(0), (bson.M{"$in": bson.A{0, nil}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("loadedVersion(0) = %v, want %v", got, want)
+	}
+	if got := loadedVersion(3); got != int64(3) {
+		t.Errorf("loadedVersion(3) = %v, want 3", got)
+	}
+}
@@ -0,0 +1,142 @@
+// Package query builds MongoDB filter documents and find options from typed filters,
+// so listings, counts and aggregations of a repository always match the same documents.
+package query
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewestFirst is the default listing order
+var NewestFirst = bson.D{{Key: "created_at", Value: -1}}
+
+// Builder accumulates the conditions of a filter document. Conditions whose value is
+// nil or empty are skipped, so optional filters can be passed through unchecked.
+type Builder struct {
+	filter bson.M
+}
+
+// New creates a builder starting from the base conditions (e.g. the tenant scope)
+func New(base bson.M) *Builder {
+	filter := make(bson.M, len(base))
+	for k, v := range base {
+		filter[k] = v
+	}
+	return &Builder{filter: filter}
+}
+
+// Filter returns the filter document
+func (b *Builder) Filter() bson.M {
+	return b.filter
+}
+
+// Set adds a raw condition
+func (b *Builder) Set(field string, condition interface{}) *Builder {
+	b.filter[field] = condition
+	return b
+}
+
+// Equal matches field == *value
+func Equal[T any](b *Builder, field string, value *T) *Builder {
+	if value != nil {
+		b.filter[field] = *value
+	}
+	return b
+}
+
+// OneOf matches field == *single, or field in many when several values are given
+func OneOf[T any](b *Builder, field string, single *T, many []T) *Builder {
+	values := many
+	if single != nil {
+		values = append([]T{*single}, many...)
+	}
+	switch len(values) {
+	case 0:
+	case 1:
+		b.filter[field] = values[0]
+	default:
+		b.filter[field] = bson.M{"$in": values}
+	}
+	return b
+}
+
+// Regex matches field against a case-insensitive regular expression
+func (b *Builder) Regex(field string, pattern *string) *Builder {
+	if pattern != nil {
+		b.filter[field] = bson.M{"$regex": *pattern, "$options": "i"}
+	}
+	return b
+}
+
+// Range matches min <= field <= max; either bound may be nil
+func Range[T any](b *Builder, field string, min, max *T) *Builder {
+	if r := rangeCondition(min, max); r != nil {
+		b.filter[field] = r
+	}
+	return b
+}
+
+// ElemRange matches arrays with at least one element whose sub-field is within the bounds
+func ElemRange[T any](b *Builder, field, sub string, min, max *T) *Builder {
+	if r := rangeCondition(min, max); r != nil {
+		b.filter[field] = bson.M{"$elemMatch": bson.M{sub: r}}
+	}
+	return b
+}
+
+// TimeRange matches from <= field <= to; either bound may be nil
+func (b *Builder) TimeRange(field string, from, to *time.Time) *Builder {
+	return Range(b, field, from, to)
+}
+
+// rangeCondition returns the $gte/$lte condition, or nil without bounds
+func rangeCondition[T any](min, max *T) bson.M {
+	if min == nil && max == nil {
+		return nil
+	}
+	r := bson.M{}
+	if min != nil {
+		r["$gte"] = *min
+	}
+	if max != nil {
+		r["$lte"] = *max
+	}
+	return r
+}
+
+// Page returns find options for one page in the given order; a non-positive limit means no limit
+func Page(limit, offset int, sort bson.D) *options.FindOptions {
+	if offset < 0 {
+		offset = 0
+	}
+	opts := options.Find().SetSkip(int64(offset)).SetSort(sort)
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	return opts
+}
+
+// After matches the documents listed after the one with the given sort value and _id, in a
+// listing sorted by field then _id in direction dir (1 ascending, -1 descending).
+// Seeking this way replaces SetSkip for cursor pagination, so deep pages stay cheap.
+func After(field string, dir int, value interface{}, id string) bson.M {
+	op := "$gt"
+	if dir < 0 {
+		op = "$lt"
+	}
+	return bson.M{"$or": []bson.M{
+		{field: bson.M{op: value}},
+		{field: value, "_id": bson.M{op: id}},
+	}}
+}
+
+// WithIDTieBreak appends _id to a single-key sort in the same direction, so documents with equal
+// sort values keep a stable order across pages
+func WithIDTieBreak(sort bson.D) bson.D {
+	if len(sort) != 1 {
+		return sort
+	}
+	return bson.D{sort[0], {Key: "_id", Value: sort[0].Value}}
+}
@@ -0,0 +1,144 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestBuilder(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		build func(b *Builder)
+		want  bson.M
+	}{
+		{"nothing set", func(b *Builder) {}, bson.M{}},
+		{"nil equal skipped", func(b *Builder) { Equal[string](b, "status", nil) }, bson.M{}},
+		{"equal", func(b *Builder) { Equal(b, "status", ptr("CREATED")) }, bson.M{"status": "CREATED"}},
+		{"one of without values skipped", func(b *Builder) { OneOf[string](b, "status", nil, nil) }, bson.M{}},
+		{"one of a single value", func(b *Builder) { OneOf(b, "status", nil, []string{"CREATED"}) }, bson.M{"status": "CREATED"}},
+		{"one of single and many", func(b *Builder) { OneOf(b, "status", ptr("CREATED"), []string{"READY"}) },
+			bson.M{"status": bson.M{"$in": []string{"CREATED", "READY"}}}},
+		{"regex", func(b *Builder) { b.Regex("name", ptr("taco")) },
+			bson.M{"name": bson.M{"$regex": "taco", "$options": "i"}}},
+		{"range without bounds skipped", func(b *Builder) { Range[int64](b, "total", nil, nil) }, bson.M{}},
+		{"range lower bound", func(b *Builder) { Range(b, "total", ptr(int64(100)), nil) },
+			bson.M{"total": bson.M{"$gte": int64(100)}}},
+		{"range both bounds", func(b *Builder) { Range(b, "total", ptr(int64(100)), ptr(int64(500))) },
+			bson.M{"total": bson.M{"$gte": int64(100), "$lte": int64(500)}}},
+		{"element range", func(b *Builder) { ElemRange(b, "price_variations", "price", nil, ptr(int64(900))) },
+			bson.M{"price_variations": bson.M{"$elemMatch": bson.M{"price": bson.M{"$lte": int64(900)}}}}},
+		{"time range", func(b *Builder) { b.TimeRange("created_at", &from, &to) },
+			bson.M{"created_at": bson.M{"$gte": from, "$lte": to}}},
+		{"set replaces", func(b *Builder) { Equal(b, "status", ptr("CREATED")).Set("status", "READY") },
+			bson.M{"status": "READY"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(nil)
+			tt.build(b)
+			if got := b.Filter(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCopiesTheBase(t *testing.T) {
+	base := bson.M{"company_id": "c1"}
+	b := New(base)
+	b.Set("status", "CREATED")
+
+	if len(base) != 1 {
+		t.Errorf("base was modified: %v", base)
+	}
+	want := bson.M{"company_id": "c1", "status": "CREATED"}
+	if got := b.Filter(); !reflect.DeepEqual(got, want) {
+		t.Errorf("filter = %v, want %v", got, want)
+	}
+}
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit, offset int
+		wantLimit     *int64
+		wantSkip      int64
+	}{
+		{"limit and offset", 20, 40, ptr(int64(20)), 40},
+		{"no limit", 0, 10, nil, 10},
+		{"negative offset", 5, -3, ptr(int64(5)), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Page(tt.limit, tt.offset, NewestFirst)
+			if !reflect.DeepEqual(opts.Limit, tt.wantLimit) {
+				t.Errorf("limit = %v, want %v", opts.Limit, tt.wantLimit)
+			}
+			if opts.Skip == nil || *opts.Skip != tt.wantSkip {
+				t.Errorf("skip = %v, want %d", opts.Skip, tt.wantSkip)
+			}
+			if !reflect.DeepEqual(opts.Sort, NewestFirst) {
+				t.Errorf("sort = %v, want %v", opts.Sort, NewestFirst)
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		dir  int
+		want bson.M
+	}{
+		{"descending", -1, bson.M{"$or": []bson.M{
+			{"created_at": bson.M{"$lt": at}},
+			{"created_at": at, "_id": bson.M{"$lt": "o1"}},
+		}}},
+		{"ascending", 1, bson.M{"$or": []bson.M{
+			{"created_at": bson.M{"$gt": at}},
+			{"created_at": at, "_id": bson.M{"$gt": "o1"}},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := After("created_at", tt.dir, at, "o1"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("After = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithIDTieBreak(t *testing.T) {
+	tests := []struct {
+		name string
+		sort bson.D
+		want bson.D
+	}{
+		{"single key", NewestFirst, bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{"already tie-broken", bson.D{{Key: "total", Value: 1}, {Key: "_id", Value: 1}}, bson.D{{Key: "total", Value: 1}, {Key: "_id", Value: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithIDTieBreak(tt.sort); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sort = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if len(NewestFirst) != 1 {
+		t.Errorf("NewestFirst was modified: %v", NewestFirst)
+	}
+}
@@ -18,8 +18,10 @@ type ErrorResponse struct {
 
 // ValidationErrorDetail represents a field-level validation error
 type ValidationErrorDetail struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
+	Field   string      `json:"field"`
+	Message string      `json:"message"`
+	Index   *int        `json:"index,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
 }
 
 // ValidationErrorResponse represents an error response with validation details
@@ -34,7 +36,8 @@ type ValidationErrorResponse struct {
 type PaginatedResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data"`
-	Meta    MetaData    `json:"meta"`
+	Summary interface{} `json:"summary,omitempty"` // Figures about all the items, not only the page
+	Meta    interface{} `json:"meta"`              // MetaData, or CursorMetaData for cursor pages
 }
 
 // MetaData contains pagination metadata
@@ -45,6 +48,12 @@ type MetaData struct {
 	PageSize    int   `json:"page_size"`
 }
 
+// CursorMetaData contains the metadata of a cursor page
+type CursorMetaData struct {
+	PageSize   int     `json:"page_size"`
+	NextCursor *string `json:"next_cursor"` // Null on the last page
+}
+
 // Success sends a success response
 func Success(c *gin.Context, statusCode int, data interface{}, message string) {
 	c.JSON(statusCode, SuccessResponse{
@@ -73,8 +82,41 @@ func ValidationError(c *gin.Context, statusCode int, errorMsg string, message st
 	})
 }
 
-// Paginated sends a paginated response
+// Paginated sends a paginated response.
+// A negative total means the count was skipped; total_items and total_pages are then -1.
 func Paginated(c *gin.Context, statusCode int, data interface{}, total int64, limit, offset int) {
+	c.JSON(statusCode, PaginatedResponse{
+		Success: true,
+		Data:    data,
+		Meta:    newMetaData(total, limit, offset),
+	})
+}
+
+// PaginatedWithSummary sends a paginated response with a summary of all the items next to the page
+func PaginatedWithSummary(c *gin.Context, statusCode int, data, summary interface{}, total int64, limit, offset int) {
+	c.JSON(statusCode, PaginatedResponse{
+		Success: true,
+		Data:    data,
+		Summary: summary,
+		Meta:    newMetaData(total, limit, offset),
+	})
+}
+
+// CursorPaginated sends a cursor page; an empty nextCursor marks the last page
+func CursorPaginated(c *gin.Context, statusCode int, data interface{}, limit int, nextCursor string) {
+	meta := CursorMetaData{PageSize: limit}
+	if nextCursor != "" {
+		meta.NextCursor = &nextCursor
+	}
+	c.JSON(statusCode, PaginatedResponse{
+		Success: true,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
+// newMetaData computes the pagination metadata of a page
+func newMetaData(total int64, limit, offset int) MetaData {
 	// Calculate current page (1-indexed)
 	currentPage := (offset / limit) + 1
 	if limit == 0 {
@@ -89,15 +131,14 @@ func Paginated(c *gin.Context, statusCode int, data interface{}, total int64, li
 	if totalPages == 0 {
 		totalPages = 1
 	}
+	if total < 0 {
+		total, totalPages = -1, -1
+	}
 
-	c.JSON(statusCode, PaginatedResponse{
-		Success: true,
-		Data:    data,
-		Meta: MetaData{
-			CurrentPage: currentPage,
-			TotalPages:  totalPages,
-			TotalItems:  total,
-			PageSize:    limit,
-		},
-	})
+	return MetaData{
+		CurrentPage: currentPage,
+		TotalPages:  totalPages,
+		TotalItems:  total,
+		PageSize:    limit,
+	}
 }
@@ -0,0 +1,17 @@
+package util
+
+import "context"
+
+// adminKey marks contexts of requests authenticated with the admin token
+type adminKey struct{}
+
+// WithAdmin returns a context marking the caller as an authenticated administrator
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether ctx belongs to a request authenticated with the admin token
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
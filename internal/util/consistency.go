@@ -0,0 +1,18 @@
+package util
+
+import "context"
+
+// readPreferencePrimaryKey marks contexts whose reads must see the caller's latest writes
+type readPreferencePrimaryKey struct{}
+
+// WithReadPreferencePrimary returns a context whose database reads go to the primary,
+// so they observe writes that may not have replicated to secondaries yet
+func WithReadPreferencePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPreferencePrimaryKey{}, true)
+}
+
+// IsReadPreferencePrimary reports whether reads made with ctx must go to the primary
+func IsReadPreferencePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(readPreferencePrimaryKey{}).(bool)
+	return primary
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned for cursors that cannot be decoded or do not fit the listing
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageCursor marks where a cursor-paginated listing stopped: the sort field and value of the last
+// item, and its ID to break ties. The next page lists the items after it in the same order, so it
+// neither skips nor repeats items when others are inserted meanwhile.
+// The zero value starts from the first item.
+type PageCursor struct {
+	Field string `json:"f"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// IsStart reports whether the cursor starts from the first item
+func (c PageCursor) IsStart() bool {
+	return c.ID == ""
+}
+
+// Encode returns the opaque form of the cursor handed to clients
+func (c PageCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageCursor parses a cursor returned by Encode; an empty string starts from the first item
+func DecodePageCursor(s string) (PageCursor, error) {
+	var c PageCursor
+	if s == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.Field == "" || c.ID == "" {
+		return PageCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
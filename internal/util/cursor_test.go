@@ -0,0 +1,47 @@
+package util
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPageCursorRoundTrip(t *testing.T) {
+	cursor := PageCursor{Field: "created_at", Value: "2024-06-01T12:00:00.123Z", ID: "p1"}
+
+	got, err := DecodePageCursor(cursor.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != cursor {
+		t.Errorf("cursor = %+v, want %+v", got, cursor)
+	}
+	if got.IsStart() {
+		t.Error("decoded cursor starts from the first item")
+	}
+}
+
+func TestDecodePageCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr error
+	}{
+		{"empty starts from the first item", "", nil},
+		{"not base64", "%%%", ErrInvalidCursor},
+		{"not JSON", "bm90IGpzb24", ErrInvalidCursor},
+		{"missing ID", PageCursor{Field: "created_at", Value: "x"}.Encode(), ErrInvalidCursor},
+		{"missing field", PageCursor{Value: "x", ID: "p1"}.Encode(), ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodePageCursor(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !cursor.IsStart() {
+				t.Errorf("cursor = %+v, want the start", cursor)
+			}
+		})
+	}
+}
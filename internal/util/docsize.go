@@ -0,0 +1,31 @@
+package util
+
+// DocumentSizeLimit is a soft limit on the encoded size of stored documents,
+// kept well below the database's hard limit so oversized writes fail with a clear error.
+// The zero value disables the check.
+type DocumentSizeLimit struct {
+	MaxBytes int
+	// Size returns the encoded size of a document in bytes
+	Size func(doc interface{}) (int, error)
+	// OnNearLimit is called when a document within the limit exceeds half of it
+	OnNearLimit func(id string, size, maxBytes int)
+}
+
+// Check measures the document and reports whether it exceeds the limit, returning its size
+func (l DocumentSizeLimit) Check(id string, doc interface{}) (size int, exceeded bool, err error) {
+	if l.MaxBytes <= 0 || l.Size == nil {
+		return 0, false, nil
+	}
+
+	size, err = l.Size(doc)
+	if err != nil {
+		return 0, false, err
+	}
+	if size > l.MaxBytes {
+		return size, true, nil
+	}
+	if size > l.MaxBytes/2 && l.OnNearLimit != nil {
+		l.OnNearLimit(id, size, l.MaxBytes)
+	}
+	return size, false, nil
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDocumentSizeLimitCheck(t *testing.T) {
+	errEncode := errors.New("cannot encode")
+	sizeOf := func(n int) func(interface{}) (int, error) {
+		return func(interface{}) (int, error) { return n, nil }
+	}
+
+	tests := []struct {
+		name         string
+		limit        DocumentSizeLimit
+		wantSize     int
+		wantExceeded bool
+		wantWarning  bool
+		wantErr      error
+	}{
+		{"disabled without a maximum", DocumentSizeLimit{Size: sizeOf(5000)}, 0, false, false, nil},
+		{"disabled without a size function", DocumentSizeLimit{MaxBytes: 1000}, 0, false, false, nil},
+		{"small document", DocumentSizeLimit{MaxBytes: 1000, Size: sizeOf(100)}, 100, false, false, nil},
+		{"exactly half", DocumentSizeLimit{MaxBytes: 1000, Size: sizeOf(500)}, 500, false, false, nil},
+		{"past half warns", DocumentSizeLimit{MaxBytes: 1000, Size: sizeOf(501)}, 501, false, true, nil},
+		{"at the limit", DocumentSizeLimit{MaxBytes: 1000, Size: sizeOf(1000)}, 1000, false, true, nil},
+		{"over the limit", DocumentSizeLimit{MaxBytes: 1000, Size: sizeOf(1001)}, 1001, true, false, nil},
+		{"size error", DocumentSizeLimit{MaxBytes: 1000, Size: func(interface{}) (int, error) { return 0, errEncode }}, 0, false, false, errEncode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warned := false
+			tt.limit.OnNearLimit = func(id string, size, maxBytes int) {
+				warned = true
+				if id != "doc-1" || size != tt.wantSize || maxBytes != tt.limit.MaxBytes {
+					t.Errorf("OnNearLimit(%q, %d, %d)", id, size, maxBytes)
+				}
+			}
+
+			size, exceeded, err := tt.limit.Check("doc-1", struct{}{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if size != tt.wantSize || exceeded != tt.wantExceeded || warned != tt.wantWarning {
+				t.Errorf("size, exceeded, warned = %d, %v, %v, want %d, %v, %v",
+					size, exceeded, warned, tt.wantSize, tt.wantExceeded, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"net/url"
+	"strings"
+)
+
+// HostAllowlist restricts the hosts URLs may point at. Entries are host names
+// ("cdn.example.com"), wildcard subdomains ("*.cloudfront.net") and may pin a
+// port ("cdn.example.com:8443"); entries without a port match any port.
+// An empty allowlist allows every host.
+type HostAllowlist []string
+
+// NewHostAllowlist normalizes the configured entries
+func NewHostAllowlist(entries []string) HostAllowlist {
+	allowlist := make(HostAllowlist, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry != "" {
+			allowlist = append(allowlist, entry)
+		}
+	}
+	return allowlist
+}
+
+// Check reports whether the URL's host is allowed. It also returns the parsed
+// host (without userinfo or port) so callers can name it in errors.
+func (a HostAllowlist) Check(rawURL string) (host string, allowed bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", len(a) == 0
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if len(a) == 0 {
+		return host, true
+	}
+
+	port := u.Port()
+	for _, entry := range a {
+		if matchHost(entry, host, port) {
+			return host, true
+		}
+	}
+	return host, false
+}
+
+// matchHost matches a single allowlist entry against a host and port
+func matchHost(entry, host, port string) bool {
+	entryHost, entryPort := entry, ""
+	// Split off a port, leaving bracketed IPv6 literals intact
+	if i := strings.LastIndex(entry, ":"); i > strings.LastIndex(entry, "]") {
+		entryHost, entryPort = entry[:i], entry[i+1:]
+	}
+	entryHost = strings.TrimSuffix(strings.Trim(entryHost, "[]"), ".")
+
+	if entryPort != "" && entryPort != port {
+		return false
+	}
+
+	if suffix, ok := strings.CutPrefix(entryHost, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return host == entryHost
+}
@@ -0,0 +1,52 @@
+package util
+
+import "testing"
+
+func TestHostAllowlistCheck(t *testing.T) {
+	allowlist := NewHostAllowlist([]string{" CDN.Example.com ", "*.cloudfront.net", "media.example.org:8443", "[::1]", "10.0.0.5", ""})
+
+	tests := []struct {
+		name     string
+		url      string
+		wantHost string
+		want     bool
+	}{
+		{"exact host", "https://cdn.example.com/a.jpg", "cdn.example.com", true},
+		{"uppercase host", "https://CDN.EXAMPLE.COM/a.jpg", "cdn.example.com", true},
+		{"trailing dot", "https://cdn.example.com./a.jpg", "cdn.example.com", true},
+		{"any port without a pinned one", "https://cdn.example.com:9000/a.jpg", "cdn.example.com", true},
+		{"wildcard subdomain", "https://d111.cloudfront.net/a.jpg", "d111.cloudfront.net", true},
+		{"wildcard nested subdomain", "https://a.b.cloudfront.net/a.jpg", "a.b.cloudfront.net", true},
+		{"wildcard does not match the apex", "https://cloudfront.net/a.jpg", "cloudfront.net", false},
+		{"suffix without a dot boundary", "https://evilcloudfront.net/a.jpg", "evilcloudfront.net", false},
+		{"pinned port", "https://media.example.org:8443/a.jpg", "media.example.org", true},
+		{"wrong pinned port", "https://media.example.org/a.jpg", "media.example.org", false},
+		{"userinfo is not the host", "https://cdn.example.com@evil.com/a.jpg", "evil.com", false},
+		{"userinfo before an allowed host", "https://evil.com:x@cdn.example.com/a.jpg", "cdn.example.com", true},
+		{"allowed host as a subdomain of another", "https://cdn.example.com.evil.com/a.jpg", "cdn.example.com.evil.com", false},
+		{"ipv4 literal", "http://10.0.0.5/a.jpg", "10.0.0.5", true},
+		{"other ipv4 literal", "http://10.0.0.6/a.jpg", "10.0.0.6", false},
+		{"ipv6 literal", "http://[::1]:8080/a.jpg", "::1", true},
+		{"relative URL", "/uploads/a.jpg", "", false},
+		{"unparseable URL", "https://%zz", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, ok := allowlist.Check(tt.url)
+			if host != tt.wantHost || ok != tt.want {
+				t.Errorf("Check(%q) = %q, %v, want %q, %v", tt.url, host, ok, tt.wantHost, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptyHostAllowlistAllowsAll(t *testing.T) {
+	for _, allowlist := range []HostAllowlist{nil, NewHostAllowlist([]string{" ", ""})} {
+		for _, url := range []string{"https://anything.example/a.jpg", "/relative.jpg", "https://%zz"} {
+			if _, ok := allowlist.Check(url); !ok {
+				t.Errorf("empty allowlist rejected %q", url)
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package util
+
+// PageLimits holds the default and maximum page size for a listing
+type PageLimits struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// DefaultPageLimits are used when no limits are configured
+var DefaultPageLimits = PageLimits{DefaultLimit: 50, MaxLimit: 100}
+
+// Resolve returns the effective page size for a requested limit.
+// Non-positive values fall back to the default and larger values are clamped to the maximum.
+func (l PageLimits) Resolve(limit int) int {
+	if limit <= 0 {
+		return l.DefaultLimit
+	}
+	if limit > l.MaxLimit {
+		return l.MaxLimit
+	}
+	return limit
+}
@@ -0,0 +1,27 @@
+package util
+
+import "testing"
+
+func TestPageLimitsResolve(t *testing.T) {
+	limits := PageLimits{DefaultLimit: 20, MaxLimit: 500}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"missing uses the default", 0, 20},
+		{"negative uses the default", -5, 20},
+		{"within the maximum", 300, 300},
+		{"at the maximum", 500, 500},
+		{"above the maximum is clamped", 501, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limits.Resolve(tt.limit); got != tt.want {
+				t.Errorf("Resolve(%d) = %d, want %d", tt.limit, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SanitizeText cleans free text that ends up on receipts, kitchen displays and logs.
+// It normalizes line endings, turns tabs and lone carriage returns into spaces, strips other control
+// characters except newlines, collapses runs of blank lines into one and trims surrounding whitespace.
+// A result of "" means the input contained no printable content.
+func SanitizeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+
+	cleaned := make([]string, 0, len(lines))
+	previousBlank := false
+	for _, line := range lines {
+		line = strings.TrimRightFunc(stripControl(line), unicode.IsSpace)
+		blank := strings.TrimSpace(line) == ""
+		if blank && previousBlank {
+			continue
+		}
+		if blank {
+			line = ""
+		}
+		cleaned = append(cleaned, line)
+		previousBlank = blank
+	}
+
+	return strings.TrimSpace(strings.Join(cleaned, "\n"))
+}
+
+// stripControl removes control characters and invalid UTF-8 from a single line.
+// Tabs and lone carriage returns separate words, so they become spaces instead.
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\r' {
+			return ' '
+		}
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"plain text", "Sin cebolla", "Sin cebolla"},
+		{"surrounding whitespace", "  \t Sin cebolla \n ", "Sin cebolla"},
+		{"keeps newlines", "Sin cebolla\nExtra queso", "Sin cebolla\nExtra queso"},
+		{"normalizes CRLF", "Sin cebolla\r\nExtra queso", "Sin cebolla\nExtra queso"},
+		{"lone carriage returns become spaces", "Sin cebolla\rExtra queso", "Sin cebolla Extra queso"},
+		{"strips escape sequences", "\x1b[2J\x1b[31mRojo\x1b[0m", "[2J[31mRojo[0m"},
+		{"strips NUL and bell", "Mesa\x00 4\a", "Mesa 4"},
+		{"strips DEL and C1 controls", "Mesa\x7f 4\u0085", "Mesa 4"},
+		{"tabs become spaces", "Sin\tcebolla", "Sin cebolla"},
+		{"strips invalid UTF-8", "Caf\xe9 con leche", "Caf con leche"},
+		{"keeps accents and emoji", "Jalapeño 🌶️", "Jalapeño 🌶️"},
+		{"collapses blank lines", "Uno\n\n\n\nDos", "Uno\n\nDos"},
+		{"collapses whitespace-only lines", "Uno\n \n\t\n  \nDos", "Uno\n\nDos"},
+		{"trims trailing spaces per line", "Uno   \nDos  ", "Uno\nDos"},
+		{"keeps leading indentation inside", "Uno\n  Dos", "Uno\n  Dos"},
+		{"only whitespace", " \n\t\r\n ", ""},
+		{"only control characters", "\x00\x01\x1b", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeText(tt.in); got != tt.want {
+				t.Errorf("SanitizeText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeTextIsIdempotent(t *testing.T) {
+	inputs := []string{
+		"Uno\r\n\r\n\r\nDos\x00",
+		strings.Repeat("paste bomb\n\n\n", 1000),
+		"  \x1b[1mNegrita\x1b[0m  ",
+	}
+
+	for _, in := range inputs {
+		once := SanitizeText(in)
+		if twice := SanitizeText(once); twice != once {
+			t.Errorf("SanitizeText is not idempotent for %q: %q then %q", in, once, twice)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package client is a typed Go client for the products and orders API.
+//
+// Request and response types are aliases of the server DTOs, so they always match what the
+// handlers bind and render:
+//
+//	c, err := client.New("http://localhost:8080", client.WithAPIKey(key))
+//	created, err := c.CreateOrder(ctx, &client.CreateOrderRequest{...})
+//	tracking, err := c.TrackOrder(ctx, created.Code)
+//
+// Failed calls return an *APIError carrying the status code and the error envelope.
+// Requests rejected with 429, or with 503 by the read-only mode, are retried with backoff
+// (honouring Retry-After). Other 5xx responses and network errors are only retried for
+// requests that are safe to repeat: reads, and writes sent with an Idempotency-Key.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/response"
+)
+
+// HeaderIdempotencyKey carries the idempotency key of a write
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// Retry defaults
+const (
+	DefaultMaxRetries = 3
+	DefaultBackoff    = 200 * time.Millisecond
+	maxBackoff        = 10 * time.Second
+)
+
+// Client calls the API over HTTP. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	apiKey     string
+	maxRetries int
+	backoff    time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests (default: a client with a 30s timeout)
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithAPIKey authenticates every request with the key as "Authorization: Bearer <key>",
+// the admin token the server checks for staff-only options and admin routes
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithRetries sets how many times a retryable request is repeated and the first backoff,
+// which doubles on every attempt. maxRetries 0 disables retries.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// New creates a client for the API served at baseURL, e.g. "http://localhost:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		maxRetries: DefaultMaxRetries,
+		backoff:    DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// APIError is returned when the API answers with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Err        string // "error" field of the envelope
+	Message    string // "message" field of the envelope
+	Details    []ValidationErrorDetail
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("api error %d", e.StatusCode)
+	if e.Err != "" {
+		msg += ": " + e.Err
+	}
+	if e.Message != "" {
+		msg += " (" + e.Message + ")"
+	}
+	return msg
+}
+
+// IsNotFound reports whether err is an API 404
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// envelope is the shape shared by the success, error and paginated responses
+type envelope struct {
+	Success bool                    `json:"success"`
+	Data    json.RawMessage         `json:"data"`
+	Meta    *response.MetaData      `json:"meta"`
+	Message string                  `json:"message"`
+	Error   string                  `json:"error"`
+	Details []ValidationErrorDetail `json:"details"`
+}
+
+// call describes one API request
+type call struct {
+	method         string
+	path           string
+	query          url.Values
+	body           any
+	idempotencyKey string
+}
+
+// do sends the call, retrying when allowed, and decodes the data into out (when not nil).
+// It returns the pagination metadata of paginated responses.
+func (c *Client) do(ctx context.Context, cl call, out any) (*response.MetaData, error) {
+	var payload []byte
+	if cl.body != nil {
+		var err error
+		if payload, err = json.Marshal(cl.body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	// Reads and idempotent writes may be repeated after the server failed mid-request
+	repeatable := cl.method == http.MethodGet || cl.idempotencyKey != ""
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, cl, payload)
+		if err != nil {
+			if ctx.Err() != nil || !repeatable || attempt >= c.maxRetries {
+				return nil, err
+			}
+			if err := c.wait(ctx, attempt, ""); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if attempt < c.maxRetries && shouldRetry(resp.StatusCode, repeatable) {
+			retryAfter := resp.Header.Get("Retry-After")
+			drain(resp)
+			if err := c.wait(ctx, attempt, retryAfter); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return decode(resp, out)
+	}
+}
+
+// send performs a single HTTP request
+func (c *Client) send(ctx context.Context, cl call, payload []byte) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += cl.path
+	u.RawQuery = cl.query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, cl.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if cl.idempotencyKey != "" {
+		req.Header.Set(HeaderIdempotencyKey, cl.idempotencyKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", cl.method, cl.path, err)
+	}
+	return resp, nil
+}
+
+// shouldRetry reports whether a response status is worth another attempt.
+// 429 and 503 are rejections before the request was handled; other 5xx may have been applied.
+func shouldRetry(status int, repeatable bool) bool {
+	switch {
+	case status == http.StatusTooManyRequests, status == http.StatusServiceUnavailable:
+		return true
+	case status >= 500:
+		return repeatable
+	default:
+		return false
+	}
+}
+
+// wait sleeps before the next attempt: Retry-After when the server sent it, exponential backoff otherwise
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter string) error {
+	delay := min(c.backoff<<attempt, maxBackoff)
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		delay = min(time.Duration(seconds)*time.Second, maxBackoff)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// decode reads the envelope, returning an *APIError for non-2xx statuses
+func decode(resp *http.Response, out any) (*response.MetaData, error) {
+	defer resp.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil && !errors.Is(err, io.EOF) {
+		if resp.StatusCode >= 300 {
+			return nil, &APIError{StatusCode: resp.StatusCode, Err: http.StatusText(resp.StatusCode)}
+		}
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 || !env.Success {
+		return nil, &APIError{StatusCode: resp.StatusCode, Err: env.Error, Message: env.Message, Details: env.Details}
+	}
+
+	if out != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return nil, fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	return env.Meta, nil
+}
+
+// drain discards the rest of a response so the connection can be reused
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
@@ -0,0 +1,398 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/internal/app"
+	"github.com/emerarteaga/products-api/internal/config"
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/domain/product"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testAdminToken = "client-test-admin-token-0123456789"
+	testCompanyID  = "11111111-1111-4111-8111-111111111111"
+	testSalePoint  = "22222222-2222-4222-8222-222222222222"
+)
+
+// memoryOrders keeps orders in memory with the optimistic locking of the MongoDB repository
+type memoryOrders struct {
+	order.Repository
+
+	mu     sync.Mutex
+	orders map[string]order.Order // By code
+}
+
+func (r *memoryOrders) Create(ctx context.Context, o *order.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[o.Code] = *o
+	return nil
+}
+
+func (r *memoryOrders) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.orders[code]
+	return ok, nil
+}
+
+func (r *memoryOrders) FindByCode(ctx context.Context, code string) (*order.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if o, ok := r.orders[code]; ok {
+		return &o, nil
+	}
+	return nil, order.ErrOrderNotFound
+}
+
+func (r *memoryOrders) FindArchivedByCode(ctx context.Context, code string) (*order.Order, error) {
+	return nil, order.ErrOrderNotFound
+}
+
+func (r *memoryOrders) Update(ctx context.Context, o *order.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[o.Code]
+	if !ok {
+		return order.ErrOrderNotFound
+	}
+	if stored.Version != o.Version {
+		return order.ErrOrderConflict
+	}
+	o.Version++
+	r.orders[o.Code] = *o
+	return nil
+}
+
+// GetMetrics counts the stored orders by status, ignoring the filters
+func (r *memoryOrders) GetMetrics(ctx context.Context, filters order.OrderFilters) (*order.OrderMetrics, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := &order.OrderMetrics{OrdersByStatus: make(map[order.OrderStatus]int)}
+	for _, o := range r.orders {
+		m.OrderCount++
+		m.TotalSales += o.Total
+		m.OrdersByStatus[o.Status]++
+	}
+	return m, nil
+}
+
+// memoryProducts pages through a fixed list of products, for every company and sale point
+type memoryProducts struct {
+	product.Repository
+	products []*product.Product
+}
+
+func (r *memoryProducts) page(filters product.ProductFilters) ([]*product.Product, int64, error) {
+	start := min(filters.Offset, len(r.products))
+	end := len(r.products)
+	if filters.Limit > 0 {
+		end = min(start+filters.Limit, end)
+	}
+	total := int64(len(r.products))
+	if filters.SkipCount {
+		total = -1
+	}
+	return r.products[start:end], total, nil
+}
+
+func (r *memoryProducts) FindByCompanyIDWithCount(ctx context.Context, companyID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+	return r.page(filters)
+}
+
+func (r *memoryProducts) FindBySalePointIDWithCount(ctx context.Context, salePointID string, filters product.ProductFilters) ([]*product.Product, int64, error) {
+	return r.page(filters)
+}
+
+// newTestServer serves the real router over in-memory repositories holding n products
+func newTestServer(t *testing.T, n int) *httptest.Server {
+	t.Helper()
+	t.Setenv("ADMIN_TOKEN", testAdminToken)
+	t.Setenv("SERVER_MODE", gin.TestMode)
+	t.Setenv("READ_ONLY_RETRY_AFTER_SECONDS", "1")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	products := &memoryProducts{}
+	for i := range n {
+		products.products = append(products.products, &product.Product{
+			ID: fmt.Sprintf("product-%02d", i), CompanyID: testCompanyID, SalePointID: testSalePoint,
+			Name: fmt.Sprintf("Product %d", i), IsAvailable: true, IsUnlimitedStock: true,
+		})
+	}
+	deps, err := app.NewDependencies(cfg, app.Repositories{
+		Products: products,
+		Orders:   &memoryOrders{orders: make(map[string]order.Order)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := deps.Router()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestClient creates a client for the server that retries quickly
+func newTestClient(t *testing.T, baseURL string, opts ...Option) *Client {
+	t.Helper()
+	c, err := New(baseURL, append([]Option{WithRetries(2, time.Millisecond)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestOrderLifecycleAgainstServer(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, newTestServer(t, 0).URL, WithAPIKey(testAdminToken))
+
+	table := 4
+	created, err := c.CreateOrder(ctx, &CreateOrderRequest{
+		CompanyID: testCompanyID, SalePointID: testSalePoint, SaleType: order.SaleTypeOnSite, TableNumber: &table,
+		Products: []OrderProductRequest{{ID: "p1", Name: "Burger", Price: 12000, Quantity: 2}},
+	}, WithIdempotencyKey("create-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Code == "" || created.Status != order.StatusCreated || created.Total != 24000 {
+		t.Fatalf("created = %+v", created)
+	}
+
+	tracked, err := c.TrackOrder(ctx, created.Code, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracked.Code != created.Code || tracked.Status != order.StatusCreated {
+		t.Errorf("tracked = %+v", tracked)
+	}
+
+	verified := order.StatusVerified
+	updated, err := c.PartialUpdateOrder(ctx, &PartialUpdateOrderRequest{Code: created.Code, Status: &verified})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status != order.StatusVerified || updated.Version != 2 {
+		t.Errorf("updated = %s at version %d, want VERIFIED at version 2", updated.Status, updated.Version)
+	}
+
+	metrics, err := c.Metrics(ctx, MetricsQuery{CompanyID: testCompanyID, Statuses: []OrderStatus{order.StatusVerified}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics.Metrics.OrderCount != 1 || metrics.Metrics.TotalSales != 24000 {
+		t.Errorf("metrics = %+v", metrics.Metrics)
+	}
+	if f := metrics.Filters; f.CompanyID == nil || *f.CompanyID != testCompanyID {
+		t.Errorf("applied filters = %+v, want the query's company", f)
+	}
+}
+
+func TestErrorsAgainstServer(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, newTestServer(t, 0).URL)
+
+	_, err := c.TrackOrder(ctx, "ORD-1700000000-a1b2c3d4", false)
+	if !IsNotFound(err) {
+		t.Errorf("unknown order err = %v, want a 404 APIError", err)
+	}
+
+	_, err = c.CreateOrder(ctx, &CreateOrderRequest{CompanyID: testCompanyID, SalePointID: testSalePoint, SaleType: order.SaleTypeOnSite})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || len(apiErr.Details) == 0 {
+		t.Errorf("invalid order err = %#v, want a 400 APIError with validation details", err)
+	}
+}
+
+// TestAPIKeyAgainstServer checks the key authenticates admin routes and that read-only 503s end in an APIError
+func TestAPIKeyAgainstServer(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, 0)
+	readOnly := call{method: http.MethodPost, path: "/api/v1/admin/read-only", body: map[string]bool{"enabled": true}}
+
+	_, err := newTestClient(t, srv.URL).do(ctx, readOnly, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("without a key err = %v, want 401", err)
+	}
+
+	admin := newTestClient(t, srv.URL, WithAPIKey(testAdminToken), WithRetries(0, 0))
+	if _, err := admin.do(ctx, readOnly, nil); err != nil {
+		t.Fatalf("with the key: %v", err)
+	}
+
+	table := 1
+	_, err = admin.CreateOrder(ctx, &CreateOrderRequest{
+		CompanyID: testCompanyID, SalePointID: testSalePoint, SaleType: order.SaleTypeOnSite, TableNumber: &table,
+		Products: []OrderProductRequest{{ID: "p1", Name: "Burger", Price: 12000, Quantity: 1}},
+	})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("write in read-only mode err = %v, want 503", err)
+	}
+}
+
+func TestEachProductAgainstServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		products int
+		query    ProductQuery
+		want     int
+	}{
+		{"no products", 0, ProductQuery{Limit: 3}, 0},
+		{"last page short", 7, ProductQuery{Limit: 3}, 7},
+		{"last page full", 6, ProductQuery{Limit: 3}, 6},
+		{"count skipped", 7, ProductQuery{Limit: 3, SkipCount: true}, 7},
+		{"from an offset", 7, ProductQuery{Limit: 3, Offset: 2}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, newTestServer(t, tt.products).URL)
+			var ids []string
+			err := c.EachProduct(context.Background(), testCompanyID, tt.query, func(p ProductListResponse) error {
+				ids = append(ids, p.ID)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ids) != tt.want {
+				t.Fatalf("visited %d products, want %d: %v", len(ids), tt.want, ids)
+			}
+			for i, id := range ids {
+				if want := fmt.Sprintf("product-%02d", i+tt.query.Offset); id != want {
+					t.Errorf("product %d = %s, want %s", i, id, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMenuAgainstServer(t *testing.T) {
+	c := newTestClient(t, newTestServer(t, 5).URL)
+	page, err := c.Menu(context.Background(), testSalePoint, ProductQuery{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != "product-02" || page.Meta.TotalItems != 5 || page.Meta.PageSize != 2 {
+		t.Errorf("page = %+v", page)
+	}
+	if !page.Items[0].Availability.Available {
+		t.Errorf("availability = %+v", page.Items[0].Availability)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		idempotencyKey string
+		statuses       []int // Answered in turn; the last one repeats
+		retryAfter     string
+		wantAttempts   int
+		wantStatus     int // 0 when the call succeeds
+	}{
+		{"read retried after 500", http.MethodGet, "", []int{500, 502, 200}, "", 3, 0},
+		{"write not retried after 500", http.MethodPost, "", []int{500, 200}, "", 1, 500},
+		{"idempotent write retried after 500", http.MethodPost, "key-1", []int{500, 200}, "", 2, 0},
+		{"write retried after 429", http.MethodPost, "", []int{429, 200}, "", 2, 0},
+		{"write retried after 503", http.MethodPost, "", []int{503, 503, 200}, "", 3, 0},
+		{"retry after honoured", http.MethodGet, "", []int{429, 200}, "0", 2, 0},
+		{"retries exhausted", http.MethodGet, "", []int{503}, "", 3, 503},
+		{"client errors not retried", http.MethodGet, "", []int{404, 200}, "", 1, 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			attempts := 0
+			var keys []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				status := tt.statuses[min(attempts, len(tt.statuses)-1)]
+				attempts++
+				keys = append(keys, r.Header.Get(HeaderIdempotencyKey))
+				mu.Unlock()
+
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					fmt.Fprint(w, `{"success": true, "data": {"code": "ORD-7KQ2M9"}}`)
+					return
+				}
+				fmt.Fprintf(w, `{"success": false, "error": "%s"}`, http.StatusText(status))
+			}))
+			defer srv.Close()
+
+			c := newTestClient(t, srv.URL)
+			var out struct{ Code string }
+			_, err := c.do(context.Background(), call{method: tt.method, path: "/api/v1/orders", idempotencyKey: tt.idempotencyKey}, &out)
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil || out.Code != "ORD-7KQ2M9" {
+					t.Errorf("err = %v, data = %+v", err, out)
+				}
+			} else {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+					t.Errorf("err = %v, want an APIError %d", err, tt.wantStatus)
+				}
+			}
+			if slices.ContainsFunc(keys, func(k string) bool { return k != tt.idempotencyKey }) {
+				t.Errorf("idempotency keys sent = %q, want %q on every attempt", keys, tt.idempotencyKey)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := newTestClient(t, srv.URL).TrackOrder(ctx, "ORD-7KQ2M9", false)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the context deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waited %v for Retry-After despite the cancelled context", elapsed)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "/api", "http://"} {
+		if _, err := New(baseURL); err == nil {
+			t.Errorf("New(%q) accepted", baseURL)
+		}
+	}
+	c, err := New("http://localhost:8080/")
+	if err != nil || c.baseURL.String() != "http://localhost:8080" || c.maxRetries != DefaultMaxRetries {
+		t.Errorf("New = %+v, %v", c, err)
+	}
+}
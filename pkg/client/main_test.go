@@ -0,0 +1,19 @@
+package client
+
+import (
+	"os"
+	"testing"
+
+	"github.com/emerarteaga/products-api/internal/config"
+	"github.com/emerarteaga/products-api/internal/infra/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	// The test server logs like production; only errors would reach stderr
+	if err := logger.InitLogger(config.LoggerConfig{Level: "error", Format: "text", Outputs: []string{logger.OutputStderr}}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
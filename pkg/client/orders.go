@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CallOption configures a single call
+type CallOption func(*call)
+
+// WithIdempotencyKey sets the Idempotency-Key of a write. The same key is sent on every attempt,
+// which also lets the write be retried after a 5xx or a network error.
+func WithIdempotencyKey(key string) CallOption {
+	return func(cl *call) {
+		cl.idempotencyKey = key
+	}
+}
+
+// CreateOrder places a new order (POST /api/v1/orders)
+func (c *Client) CreateOrder(ctx context.Context, req *CreateOrderRequest, opts ...CallOption) (*OrderCreatedResponse, error) {
+	cl := call{method: http.MethodPost, path: "/api/v1/orders", body: req}
+	for _, opt := range opts {
+		opt(&cl)
+	}
+
+	var out OrderCreatedResponse
+	if _, err := c.do(ctx, cl, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TrackOrder returns the public tracking view of an order (GET /api/v1/orders/track/:code).
+// With includeNotes the notes with PUBLIC visibility are added.
+func (c *Client) TrackOrder(ctx context.Context, code string, includeNotes bool) (*OrderTrackResponse, error) {
+	query := url.Values{}
+	if includeNotes {
+		query.Set("include", "notes")
+	}
+
+	var out OrderTrackResponse
+	cl := call{method: http.MethodGet, path: "/api/v1/orders/track/" + url.PathEscape(code), query: query}
+	if _, err := c.do(ctx, cl, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PartialUpdateOrder changes the status, note or payment fields of an order (PATCH /api/v1/orders)
+func (c *Client) PartialUpdateOrder(ctx context.Context, req *PartialUpdateOrderRequest, opts ...CallOption) (*OrderResponse, error) {
+	cl := call{method: http.MethodPatch, path: "/api/v1/orders", body: req}
+	for _, opt := range opts {
+		opt(&cl)
+	}
+
+	var out OrderResponse
+	if _, err := c.do(ctx, cl, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BatchUpdateStatus moves several orders to a status (POST /api/v1/orders/batch/status).
+// Failed orders are reported in the results, not as an error.
+func (c *Client) BatchUpdateStatus(ctx context.Context, req *BatchStatusRequest, opts ...CallOption) (*BatchStatusResponse, error) {
+	cl := call{method: http.MethodPost, path: "/api/v1/orders/batch/status", body: req}
+	for _, opt := range opts {
+		opt(&cl)
+	}
+
+	var out BatchStatusResponse
+	if _, err := c.do(ctx, cl, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddOrderNote appends a note to an order (POST /api/v1/orders/:code/notes)
+func (c *Client) AddOrderNote(ctx context.Context, code string, req *AddOrderNoteRequest, opts ...CallOption) (*OrderResponse, error) {
+	cl := call{method: http.MethodPost, path: "/api/v1/orders/" + url.PathEscape(code) + "/notes", body: req}
+	for _, opt := range opts {
+		opt(&cl)
+	}
+
+	var out OrderResponse
+	if _, err := c.do(ctx, cl, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConfirmPayment marks the payment of an order as CONFIRMED (POST /api/v1/orders/:code/payment/confirm).
+// It is an admin route, so the client needs WithAPIKey.
+func (c *Client) ConfirmPayment(ctx context.Context, code string, opts ...CallOption) (*OrderResponse, error) {
+	cl := call{method: http.MethodPost, path: "/api/v1/orders/" + url.PathEscape(code) + "/payment/confirm"}
+	for _, opt := range opts {
+		opt(&cl)
+	}
+
+	var out OrderResponse
+	if _, err := c.do(ctx, cl, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MetricsQuery filters the orders aggregated by Metrics; zero values are not sent
+type MetricsQuery struct {
+	CompanyID        string
+	SalePointID      string
+	DateFrom         string // YYYY-MM-DD or RFC3339
+	DateTo           string
+	Statuses         []OrderStatus
+	SaleType         SaleType
+	Channel          Channel
+	PaymentStatus    PaymentStatus
+	ProductID        string
+	IncludeArchived  bool
+	ExcludeCancelled bool
+}
+
+func (q MetricsQuery) values() url.Values {
+	v := url.Values{}
+	setIf(v, "company_id", q.CompanyID)
+	setIf(v, "sale_point_id", q.SalePointID)
+	setIf(v, "date_from", q.DateFrom)
+	setIf(v, "date_to", q.DateTo)
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, len(q.Statuses))
+		for i, s := range q.Statuses {
+			statuses[i] = string(s)
+		}
+		v.Set("status", strings.Join(statuses, ","))
+	}
+	setIf(v, "sale_type", string(q.SaleType))
+	setIf(v, "channel", string(q.Channel))
+	setIf(v, "payment_status", string(q.PaymentStatus))
+	setIf(v, "product_id", q.ProductID)
+	if q.IncludeArchived {
+		v.Set("include_archived", "true")
+	}
+	if q.ExcludeCancelled {
+		v.Set("exclude_cancelled", "true")
+	}
+	return v
+}
+
+// Metrics returns aggregated order metrics (GET /api/v1/orders/metrics)
+func (c *Client) Metrics(ctx context.Context, q MetricsQuery) (*OrderMetricsResponse, error) {
+	var out OrderMetricsResponse
+	if _, err := c.do(ctx, call{method: http.MethodGet, path: "/api/v1/orders/metrics", query: q.values()}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// setIf sets a query value when it is not empty
+func setIf(v url.Values, key, value string) {
+	if value != "" {
+		v.Set(key, value)
+	}
+}
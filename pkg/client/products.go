@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ProductQuery filters and paginates product listings; zero values are not sent
+type ProductQuery struct {
+	Category    string
+	IsAvailable *bool
+	IsAddon     *bool
+	MinPrice    *int64 // In cents
+	MaxPrice    *int64 // In cents
+	Limit       int    // 0 uses the server default
+	Offset      int
+	SkipCount   bool // Skip the total count; Meta.TotalItems is then -1
+}
+
+func (q ProductQuery) values() url.Values {
+	v := url.Values{}
+	setIf(v, "category", q.Category)
+	if q.IsAvailable != nil {
+		v.Set("is_available", strconv.FormatBool(*q.IsAvailable))
+	}
+	if q.IsAddon != nil {
+		v.Set("is_addon", strconv.FormatBool(*q.IsAddon))
+	}
+	if q.MinPrice != nil {
+		v.Set("min_price", strconv.FormatInt(*q.MinPrice, 10))
+	}
+	if q.MaxPrice != nil {
+		v.Set("max_price", strconv.FormatInt(*q.MaxPrice, 10))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.SkipCount {
+		v.Set("skip_count", "true")
+	}
+	return v
+}
+
+// ListProducts returns one page of a company's products (GET /api/v1/products/company/:company_id)
+func (c *Client) ListProducts(ctx context.Context, companyID string, q ProductQuery) (*Page[ProductListResponse], error) {
+	return c.productPage(ctx, "/api/v1/products/company/"+url.PathEscape(companyID), q)
+}
+
+// Menu returns one page of a sale point's products, the public menu (GET /api/v1/products/sale-point/:sale_point_id)
+func (c *Client) Menu(ctx context.Context, salePointID string, q ProductQuery) (*Page[ProductListResponse], error) {
+	return c.productPage(ctx, "/api/v1/products/sale-point/"+url.PathEscape(salePointID), q)
+}
+
+// EachProduct walks every page of a company's products from q.Offset, calling fn for each product.
+// It stops at the first error returned by fn.
+func (c *Client) EachProduct(ctx context.Context, companyID string, q ProductQuery, fn func(ProductListResponse) error) error {
+	for {
+		page, err := c.ListProducts(ctx, companyID, q)
+		if err != nil {
+			return err
+		}
+		for _, p := range page.Items {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+
+		// A short page is the last one; the count may have been skipped
+		if len(page.Items) == 0 || len(page.Items) < page.Meta.PageSize {
+			return nil
+		}
+		q.Offset += len(page.Items)
+		if page.Meta.TotalItems >= 0 && int64(q.Offset) >= page.Meta.TotalItems {
+			return nil
+		}
+	}
+}
+
+// productPage fetches one page of a product listing
+func (c *Client) productPage(ctx context.Context, path string, q ProductQuery) (*Page[ProductListResponse], error) {
+	var items []ProductListResponse
+	meta, err := c.do(ctx, call{method: http.MethodGet, path: path, query: q.values()}, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page[ProductListResponse]{Items: items}
+	if meta != nil {
+		page.Meta = *meta
+	}
+	return page, nil
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"github.com/emerarteaga/products-api/internal/domain/order"
+	"github.com/emerarteaga/products-api/internal/dto"
+	"github.com/emerarteaga/products-api/internal/response"
+)
+
+// Request and response types, shared with the server handlers
+type (
+	CreateOrderRequest        = dto.CreateOrderRequest
+	OrderProductRequest       = dto.OrderProductRequest
+	OrderProductAddonRequest  = dto.OrderProductAddonRequest
+	DiscountRequest           = dto.DiscountRequest
+	CustomerRequest           = dto.CustomerRequest
+	OrderCreatedResponse      = dto.OrderCreatedResponse
+	OrderTrackResponse        = dto.OrderTrackResponse
+	PartialUpdateOrderRequest = dto.PartialUpdateOrderRequest
+	AddOrderNoteRequest       = dto.AddOrderNoteRequest
+	BatchStatusRequest        = dto.BatchStatusRequest
+	BatchStatusResponse       = dto.BatchStatusResponse
+	OrderResponse             = dto.OrderResponse
+	ProductListResponse       = dto.ProductListResponse
+	OrderMetricsResponse      = dto.OrderMetricsResponse
+	ValidationErrorDetail     = response.ValidationErrorDetail
+	PageMeta                  = response.MetaData
+)
+
+// Enumerations used by the request types
+type (
+	OrderStatus    = order.OrderStatus
+	SaleType       = order.SaleType
+	Channel        = order.Channel
+	IDType         = order.IDType
+	NoteVisibility = order.NoteVisibility
+	DiscountType   = order.DiscountType
+	PaymentStatus  = order.PaymentStatus
+)
+
+// Page is one page of a paginated listing
+type Page[T any] struct {
+	Items []T
+	Meta  PageMeta // TotalItems and TotalPages are -1 when the count was skipped
+}
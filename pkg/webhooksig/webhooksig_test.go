@@ -0,0 +1,170 @@
+package webhooksig_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emerarteaga/products-api/pkg/webhooksig"
+)
+
+var (
+	secret  = []byte("whsec_test")
+	payload = []byte(`{"code":"ORD-1"}`)
+	signed  = time.Unix(1700000000, 0)
+)
+
+func TestSign(t *testing.T) {
+	// Reference values computed independently with HMAC-SHA256 over "<timestamp>.<delivery id>.<body>"
+	tests := []struct {
+		name       string
+		secret     []byte
+		timestamp  time.Time
+		deliveryID string
+		payload    []byte
+		want       string
+	}{
+		{"order payload", secret, signed, "d1", payload, "sha256=c07ecc085dedb183551b32936ab498b0e3d5faf281c4f428f1dd595c70eac0b7"},
+		{"empty secret and body", nil, time.Unix(0, 0), "x", nil, "sha256=f0c0c92854e473168b77854978033e7e5091cecc5fdee0616bfc1c00301205d8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webhooksig.Sign(tt.secret, tt.timestamp, tt.deliveryID, tt.payload); got != tt.want {
+				t.Errorf("Sign = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	headers := func(edit func(h http.Header)) http.Header {
+		h := http.Header{}
+		webhooksig.SetHeaders(h, secret, "d1", payload, signed)
+		if edit != nil {
+			edit(h)
+		}
+		return h
+	}
+
+	tests := []struct {
+		name    string
+		secret  []byte
+		header  http.Header
+		payload []byte
+		now     time.Time
+		wantErr error
+	}{
+		{"valid", secret, headers(nil), payload, signed, nil},
+		{"within the tolerance", secret, headers(nil), payload, signed.Add(webhooksig.DefaultTolerance), nil},
+		{"clock skew within the tolerance", secret, headers(nil), payload, signed.Add(-webhooksig.DefaultTolerance), nil},
+		{"tampered payload", secret, headers(nil), []byte(`{"code":"ORD-2"}`), signed, webhooksig.ErrInvalidSignature},
+		{"whitespace added to the payload", secret, headers(nil), []byte(`{"code": "ORD-1"}`), signed, webhooksig.ErrInvalidSignature},
+		{"wrong secret", []byte("other"), headers(nil), payload, signed, webhooksig.ErrInvalidSignature},
+		{"tampered delivery ID", secret, headers(func(h http.Header) { h.Set(webhooksig.HeaderDeliveryID, "d2") }), payload, signed, webhooksig.ErrInvalidSignature},
+		{"timestamp moved forward", secret, headers(func(h http.Header) { h.Set(webhooksig.HeaderTimestamp, "1700000060") }), payload, signed, webhooksig.ErrInvalidSignature},
+		{"stale timestamp", secret, headers(nil), payload, signed.Add(webhooksig.DefaultTolerance + time.Second), webhooksig.ErrStaleTimestamp},
+		{"timestamp from the future", secret, headers(nil), payload, signed.Add(-webhooksig.DefaultTolerance - time.Second), webhooksig.ErrStaleTimestamp},
+		{"malformed timestamp", secret, headers(func(h http.Header) { h.Set(webhooksig.HeaderTimestamp, "yesterday") }), payload, signed, webhooksig.ErrInvalidTimestamp},
+		{"missing signature", secret, headers(func(h http.Header) { h.Del(webhooksig.HeaderSignature) }), payload, signed, webhooksig.ErrMissingHeader},
+		{"missing timestamp", secret, headers(func(h http.Header) { h.Del(webhooksig.HeaderTimestamp) }), payload, signed, webhooksig.ErrMissingHeader},
+		{"missing delivery ID", secret, headers(func(h http.Header) { h.Del(webhooksig.HeaderDeliveryID) }), payload, signed, webhooksig.ErrMissingHeader},
+		{"signature without prefix", secret, headers(func(h http.Header) {
+			h.Set(webhooksig.HeaderSignature, strings.TrimPrefix(h.Get(webhooksig.HeaderSignature), "sha256="))
+		}), payload, signed, webhooksig.ErrInvalidSignature},
+		{"signature not hex", secret, headers(func(h http.Header) { h.Set(webhooksig.HeaderSignature, "sha256=zz") }), payload, signed, webhooksig.ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webhooksig.Verify(tt.secret, tt.header, tt.payload, 0, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyCustomTolerance(t *testing.T) {
+	h := http.Header{}
+	webhooksig.SetHeaders(h, secret, "d1", payload, signed)
+
+	if err := webhooksig.Verify(secret, h, payload, 30*time.Second, signed.Add(30*time.Second)); err != nil {
+		t.Errorf("at the tolerance: %v", err)
+	}
+	if err := webhooksig.Verify(secret, h, payload, 30*time.Second, signed.Add(31*time.Second)); !errors.Is(err, webhooksig.ErrStaleTimestamp) {
+		t.Errorf("past the tolerance: err = %v, want %v", err, webhooksig.ErrStaleTimestamp)
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	now := time.Now()
+	request := func(body string, signedAt time.Time) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/webhooks/orders", strings.NewReader(body))
+		webhooksig.SetHeaders(r.Header, secret, "d1", payload, signedAt)
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		wantErr error
+	}{
+		{"valid", request(string(payload), now), nil},
+		{"tampered body", request(`{"code":"ORD-9"}`, now), webhooksig.ErrInvalidSignature},
+		{"replayed after the tolerance", request(string(payload), now.Add(-time.Hour)), webhooksig.ErrStaleTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := webhooksig.VerifyRequest(tt.request, secret, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if string(got) != string(payload) {
+				t.Errorf("payload = %s, want %s", got, payload)
+			}
+			// The body stays readable for the receiver's own decoding
+			if rest, _ := io.ReadAll(tt.request.Body); string(rest) != string(payload) {
+				t.Errorf("body after verification = %q, want %s", rest, payload)
+			}
+		})
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	h := http.Header{}
+	webhooksig.SetHeaders(h, secret, "d1", payload, signed)
+
+	if got := h.Get(webhooksig.HeaderTimestamp); got != strconv.FormatInt(signed.Unix(), 10) {
+		t.Errorf("timestamp = %s", got)
+	}
+	if got := h.Get(webhooksig.HeaderDeliveryID); got != "d1" {
+		t.Errorf("delivery ID = %s", got)
+	}
+	if got, want := h.Get(webhooksig.HeaderSignature), webhooksig.Sign(secret, signed, "d1", payload); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+}
+
+func TestNewDeliveryIDIsUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 1000 {
+		id, err := webhooksig.NewDeliveryID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(id) != 32 || seen[id] {
+			t.Fatalf("delivery ID %q is malformed or repeated", id)
+		}
+		seen[id] = true
+	}
+}